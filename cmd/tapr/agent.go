@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/agent"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// agentTokenEnv is the environment variable holding the shared token when
+// --token isn't given.
+const agentTokenEnv = "TAPR_AGENT_TOKEN"
+
+var (
+	agentJoin        string        // Controller URL the agent reports to
+	agentName        string        // Name the agent reports under (default: hostname)
+	agentInterval    time.Duration // Time between agent runs
+	agentOnce        bool          // Run once and exit
+	agentToken       string        // Shared token between agents and controller
+	controllerListen string        // Address the controller API listens on
+)
+
+// agentCmd represents the agent command for distributed probing
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run checks for a controller from this machine",
+	Long: `Agent mode joins a tapr controller, fetches the batch config it serves and
+runs it on an interval, reporting the results back. Run agents in every region
+or network you care about and the controller shows how each endpoint looks
+from all of them.
+
+Agents only make outgoing connections, so they work behind NAT and firewalls.
+If a round fails (controller down, network error), the agent retries on the
+next interval.`,
+	Example: `  tapr agent --join http://controller.example.com:9900 --name eu-west
+  tapr agent --join controller:9900 --interval 30s --token s3cret
+  tapr agent --join controller:9900 --once   # one round, e.g. from cron`,
+	Args: cobra.NoArgs,
+	Run:  runAgent,
+}
+
+// controllerCmd represents the controller command for distributed probing
+var controllerCmd = &cobra.Command{
+	Use:   "controller [config]",
+	Short: "Collect and combine results from tapr agents",
+	Long: `Controller mode serves a batch config to tapr agents and collects the results
+they report, combining them per agent and per endpoint.
+
+API (default ` + agent.DefaultListen + `):
+  GET /healthz           Controller liveness
+  GET /api/agents        Latest results of every agent (503 if any is failing or stale)
+  GET /api/endpoints     Every endpoint's latest result from each agent
+
+Agents that miss three runs are marked stale. Set a shared token with --token
+(or ` + agentTokenEnv + `) whenever the controller is reachable from other hosts.`,
+	Example: `  tapr controller endpoints.yml
+  tapr controller endpoints.yml --listen :9900 --token s3cret
+  curl -H "Authorization: Bearer s3cret" localhost:9900/api/endpoints`,
+	Args: cobra.ExactArgs(1),
+	Run:  runController,
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(controllerCmd)
+
+	agentCmd.Flags().StringVar(
+		&agentJoin,
+		"join",
+		"",
+		"URL of the controller to report to (required)",
+	)
+
+	agentCmd.Flags().StringVar(
+		&agentName,
+		"name",
+		"",
+		"Name to report results under, e.g. a region (default: hostname)",
+	)
+
+	agentCmd.Flags().DurationVar(
+		&agentInterval,
+		"interval",
+		time.Minute,
+		"Time between runs",
+	)
+
+	agentCmd.Flags().BoolVar(
+		&agentOnce,
+		"once",
+		false,
+		"Run once, report and exit with the batch's exit code",
+	)
+
+	for _, cmd := range []*cobra.Command{agentCmd, controllerCmd} {
+		cmd.Flags().StringVar(
+			&agentToken,
+			"token",
+			"",
+			"Shared token between agents and controller (default $"+agentTokenEnv+")",
+		)
+	}
+
+	controllerCmd.Flags().StringVar(
+		&controllerListen,
+		"listen",
+		agent.DefaultListen,
+		"Address for the controller API",
+	)
+}
+
+// resolveAgentToken returns the token from --token or the environment.
+func resolveAgentToken() string {
+	if agentToken != "" {
+		return agentToken
+	}
+	return os.Getenv(agentTokenEnv)
+}
+
+// runAgent executes the agent command.
+func runAgent(cmd *cobra.Command, args []string) {
+	if agentJoin == "" {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --join is required (the controller's URL)"))
+		os.Exit(ExitError)
+	}
+	if agentInterval <= 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --interval must be positive"))
+		os.Exit(ExitError)
+	}
+	if agentName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red("Error: can't determine the hostname, set --name"))
+			os.Exit(ExitError)
+		}
+		agentName = hostname
+	}
+
+	a := agent.New(agentName, agentJoin, resolveAgentToken(), agentInterval, runAgentBatch, logAgentRound)
+
+	if agentOnce {
+		report, err := a.Once(context.Background())
+		logAgentRound(report, err)
+		switch {
+		case err != nil:
+			os.Exit(ExitError)
+		case report.Result.Failed > 0:
+			os.Exit(ExitFailure)
+		}
+		os.Exit(ExitSuccess)
+	}
+
+	// Stop cleanly on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !quiet && !silent {
+		output.Printf("🛰️  Agent %s reporting to %s every %s\n\n", output.Blue(agentName), agentJoin, agentInterval)
+	}
+	a.Run(ctx)
+	if !quiet && !silent {
+		output.Println("\n👋 Agent stopped")
+	}
+}
+
+// runAgentBatch runs a batch config received from the controller.
+func runAgentBatch(batch []byte) (output.JSONBatchResult, error) {
+	batchConfig, err := config.ParseBatchConfig(batch, "controller config")
+	if err != nil {
+		return output.JSONBatchResult{}, err
+	}
+	// Discover entries are resolved on every round, from this agent
+	if err := expandDiscovery(batchConfig); err != nil {
+		return output.JSONBatchResult{}, err
+	}
+
+	start := time.Now()
+	summary := runBatchTests(batchConfig, nil)
+	summary.TotalTime = time.Since(start)
+	return output.NewJSONBatchResult(summary), nil
+}
+
+// logAgentRound prints one line per agent round. Quiet mode only shows
+// failures.
+func logAgentRound(report agent.Report, err error) {
+	if silent {
+		return
+	}
+	timestamp := time.Now().Format("15:04:05")
+	if err != nil {
+		output.Printf("[%s] %s %v\n", timestamp, output.Red("✗"), err)
+		return
+	}
+	if quiet && report.Result.Failed == 0 {
+		return
+	}
+	output.Printf("[%s] %s\n", timestamp, describeAgentResult(report.Result))
+}
+
+// describeAgentResult summarizes the results of one agent round.
+func describeAgentResult(result output.JSONBatchResult) string {
+	if result.Failed == 0 {
+		return fmt.Sprintf("%s %d/%d passed  avg %s", output.Green("✓"), result.Successful, result.Total, formatMillis(result.AvgLatency))
+	}
+
+	var failing []string
+	for _, endpoint := range result.Results {
+		if !endpoint.Success {
+			failing = append(failing, endpoint.Name)
+		}
+	}
+	return fmt.Sprintf("%s %d of %d failing: %s", output.Red("✗"), result.Failed, result.Total, strings.Join(failing, ", "))
+}
+
+// runController executes the controller command.
+func runController(cmd *cobra.Command, args []string) {
+	batchConfig, err := config.LoadBatchConfig(args[0])
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading batch config: %v", err)))
+		os.Exit(ExitError)
+	}
+	batch, err := batchConfig.Standalone()
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	token := resolveAgentToken()
+	controller := agent.NewController(batch, token, logAgentReport)
+
+	// Stop cleanly on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !quiet && !silent {
+		served := fmt.Sprintf("%d endpoint(s)", len(batchConfig.Endpoints))
+		if n := len(batchConfig.Discover); n > 0 {
+			served += fmt.Sprintf(" and %d discover entry(s), resolved by each agent,", n)
+		}
+		output.Printf("🛰️  Controller serving %s to agents\n", served)
+		output.Printf("   API:   %s\n", output.Blue("http://"+controllerListen+"/api/agents"))
+		if token == "" {
+			output.Println(output.Yellow("   ⚠️  No --token set: anyone who can reach the controller can read the config and send results"))
+		}
+		output.Println()
+	}
+
+	if err := controller.Run(ctx, controllerListen); err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	if !quiet && !silent {
+		output.Println("\n👋 Controller stopped")
+	}
+}
+
+// logAgentReport prints one line per report the controller receives. Quiet
+// mode only shows failures.
+func logAgentReport(report agent.Report) {
+	if silent || (quiet && report.Result.Failed == 0) {
+		return
+	}
+	output.Printf("[%s] %-16s %s\n", time.Now().Format("15:04:05"), report.Agent, describeAgentResult(report.Result))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// allIPs checks every address the host resolves to (--all-ips)
+var allIPs bool
+
+func init() {
+	rootCmd.Flags().BoolVar(
+		&allIPs,
+		"all-ips",
+		false,
+		"Resolve the host and check each of its addresses separately (round-robin DNS)",
+	)
+}
+
+// runAllIPs checks url once against every address its host resolves to,
+// connecting to each address directly while keeping the host name for the
+// Host header, SNI and certificate checks. It prints one line per address
+// and exits, failing if any address fails.
+func runAllIPs(url string, opts request.PingOptions) {
+	if compareStack {
+		fmt.Fprintln(output.Stderr, output.Red("Error: use either --all-ips or --compare-stack, not both"))
+		os.Exit(ExitError)
+	}
+
+	host, port, err := urlHostPort(url)
+	if err == nil && net.ParseIP(host) != nil {
+		err = fmt.Errorf("%s is an IP address, --all-ips needs a host name", host)
+	}
+	var addresses []string
+	if err == nil {
+		addresses, err = hostAddresses(url, opts)
+	}
+	if err != nil {
+		printError(url, err)
+		exitPing(url, request.Result{URL: url, Error: err}, ExitFailure)
+	}
+	addresses = filterIPVersion(addresses, opts.IPVersion)
+	if len(addresses) == 0 {
+		err := fmt.Errorf("%s has no IPv%s addresses", host, opts.IPVersion)
+		printError(url, err)
+		exitPing(url, request.Result{URL: url, Error: err}, ExitFailure)
+	}
+
+	output.Printf("🔀 Checking %d address(es) of %s\n\n", len(addresses), output.Blue(host))
+
+	results := probeAddresses(url, host, port, addresses, opts)
+	saveCookieJar(opts.Jar)
+	output.Println()
+
+	// Summarize, reporting the first failing address (or else the slowest)
+	var (
+		failing    int
+		summarized request.Result
+		fastest    = -1
+		slowest    = -1
+	)
+	for i, result := range results {
+		if !answered(result) {
+			if failing == 0 {
+				summarized = result
+			}
+			failing++
+			continue
+		}
+		if fastest == -1 || result.Latency < results[fastest].Latency {
+			fastest = i
+		}
+		if slowest == -1 || result.Latency > results[slowest].Latency {
+			slowest = i
+		}
+	}
+
+	if failing > 0 {
+		output.Printf("%s %d of %d address(es) failing\n", output.Red("✗"), failing, len(addresses))
+		exitPing(url, summarized, ExitFailure)
+	}
+
+	output.Printf("%s All %d address(es) healthy", output.Green("✓"), len(addresses))
+	if len(addresses) > 1 {
+		output.Printf(" (fastest %s %s, slowest %s %s)", addresses[fastest], results[fastest].Latency.Round(time.Millisecond),
+			addresses[slowest], results[slowest].Latency.Round(time.Millisecond))
+	}
+	output.Println()
+	exitPing(url, results[slowest], ExitSuccess)
+}
+
+// probeAddresses pings url once per address, connecting to the address
+// directly, and prints one line per address. host and port are those of
+// url.
+func probeAddresses(url, host, port string, addresses []string, opts request.PingOptions) []request.Result {
+	results := make([]request.Result, len(addresses))
+	width := 0
+	for _, address := range addresses {
+		width = max(width, len(address))
+	}
+	for i, address := range addresses {
+		pinned := opts
+		pinned.Resolve = map[string]string{net.JoinHostPort(strings.ToLower(host), port): address}
+		results[i] = request.Ping(url, pinned)
+		printAddressResult(address, width, results[i])
+	}
+	return results
+}
+
+// filterIPVersion keeps the addresses of the given IP version (all for
+// request.IPVersionAny).
+func filterIPVersion(addresses []string, version string) []string {
+	if version == request.IPVersionAny {
+		return addresses
+	}
+
+	var kept []string
+	for _, address := range addresses {
+		isV4 := net.ParseIP(address).To4() != nil
+		if isV4 == (version == request.IPVersion4) {
+			kept = append(kept, address)
+		}
+	}
+	return kept
+}
+
+// printAddressResult prints the result for one address on a single line.
+func printAddressResult(address string, width int, result request.Result) {
+	label := fmt.Sprintf("   %-*s", width, address)
+	switch {
+	case result.Error != nil:
+		output.Printf("%s  %s %v\n", label, output.Red("✗"), result.Error)
+	case !answered(result):
+		output.Printf("%s  %s %-16s %s\n", label, output.Red("✗"), result.Status, formatLatency(result.Latency))
+	default:
+		output.Printf("%s  %s %-16s %s\n", label, output.Green("✓"), result.Status, formatLatency(result.Latency))
+	}
+}
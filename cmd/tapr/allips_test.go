@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+func TestProbeAddresses(t *testing.T) {
+	// Listen on every interface, so each loopback address reaches the server
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen on all interfaces: %v", err)
+	}
+	var (
+		mu     sync.Mutex
+		probed []string
+	)
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			local := r.Context().Value(http.LocalAddrContextKey).(net.Addr).String()
+			mu.Lock()
+			probed = append(probed, strings.Split(local, ":")[0]+" "+r.Host)
+			mu.Unlock()
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	url := "http://api.example.com:" + port + "/health"
+	addresses := []string{"127.0.0.1", "127.0.0.2"}
+
+	results := probeAddresses(url, "api.example.com", port, addresses, request.PingOptions{Method: "GET", Timeout: 5 * time.Second})
+	if len(results) != len(addresses) {
+		t.Fatalf("got %d results, want one per address", len(results))
+	}
+	for i, result := range results {
+		if result.Error != nil || result.StatusCode != 200 || !strings.HasPrefix(result.RemoteAddr, addresses[i]+":") {
+			t.Errorf("%s: status %d, remote %q, error %v", addresses[i], result.StatusCode, result.RemoteAddr, result.Error)
+		}
+	}
+
+	// Each address got a request, addressed to the host name
+	want := []string{"127.0.0.1 api.example.com:" + port, "127.0.0.2 api.example.com:" + port}
+	if strings.Join(probed, ",") != strings.Join(want, ",") {
+		t.Errorf("server saw %v, want %v", probed, want)
+	}
+}
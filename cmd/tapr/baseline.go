@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/baseline"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+var (
+	baselineFile string   // Compare the run with this baseline (--baseline)
+	saveBaseline bool     // Write the run to --baseline instead (--save-baseline)
+	tolerances   []string // Allowed regression per metric (--tolerance)
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{batchCmd, watchCmd} {
+		cmd.Flags().StringVar(
+			&baselineFile,
+			"baseline",
+			"",
+			"Fail if latency or success rate regressed against this baseline file",
+		)
+
+		cmd.Flags().BoolVar(
+			&saveBaseline,
+			"save-baseline",
+			false,
+			"Write the results of this run to the --baseline file",
+		)
+
+		cmd.Flags().StringSliceVar(
+			&tolerances,
+			"tolerance",
+			nil,
+			"Allowed regression per metric (repeatable): \"p95=10%\", \"p99=25%\", \"success_rate=1\" or \"p95=off\"",
+		)
+	}
+}
+
+// checkBaselineFlags validates the baseline flags before the run starts.
+func checkBaselineFlags() {
+	if saveBaseline && baselineFile == "" {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --save-baseline needs --baseline <file>"))
+		os.Exit(ExitError)
+	}
+	if _, err := baseline.ParseTolerances(tolerances); err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+}
+
+// gateBaseline saves the run as the --baseline or compares it with the
+// baseline, and returns the exit code: a passing run fails if any metric
+// regressed beyond its tolerance.
+func gateBaseline(command, target string, summary output.RunSummary, code int) int {
+	if baselineFile == "" {
+		return code
+	}
+
+	summary.Command = command
+	summary.Target = target
+	summary.StartedAt = runStarted
+	current := baseline.FromSummary(summary)
+
+	// Keep stdout parseable for machine-readable formats
+	var w io.Writer = os.Stdout
+	if outputFormat != "pretty" {
+		w = os.Stderr
+	}
+
+	if saveBaseline {
+		if err := baseline.Save(baselineFile, current); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			return ExitError
+		}
+		if !quiet && !silent {
+			fmt.Fprintf(w, "\n📌 Saved baseline to %s\n", baselineFile)
+		}
+		return code
+	}
+
+	base, err := baseline.Load(baselineFile)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v (create it with --save-baseline)", err)))
+		return ExitError
+	}
+	limits, _ := baseline.ParseTolerances(tolerances) // Checked by checkBaselineFlags
+	checks := baseline.Compare(base, current, limits)
+	regressions := baseline.Regressions(checks)
+
+	if !silent && (!quiet || regressions > 0) {
+		fmt.Fprintf(w, "\n📌 Baseline (%s)\n", baselineFile)
+		for _, check := range checks {
+			fmt.Fprintf(w, "   %s\n", formatBaselineCheck(check))
+		}
+		if regressions > 0 {
+			fmt.Fprintf(w, "%s\n", output.Red(fmt.Sprintf("✗ %d metric(s) regressed against the baseline", regressions)))
+		} else {
+			fmt.Fprintf(w, "%s\n", output.Green("✓ Within the baseline"))
+		}
+	}
+
+	if regressions > 0 && code == ExitSuccess {
+		return ExitFailure
+	}
+	return code
+}
+
+// formatBaselineCheck formats one metric of the baseline comparison.
+func formatBaselineCheck(check baseline.Check) string {
+	var values, change, limit string
+	if check.Metric == baseline.SuccessRate {
+		values = fmt.Sprintf("%.1f%% → %.1f%%", check.Baseline, check.Current)
+		change = fmt.Sprintf("%+.1f pts", check.Change)
+		limit = fmt.Sprintf("(max -%g pts)", check.Allowed)
+	} else {
+		values = fmt.Sprintf("%.1fms → %.1fms", check.Baseline, check.Current)
+		change = fmt.Sprintf("%+.1f%%", check.Change)
+		limit = fmt.Sprintf("(max +%g%%)", check.Allowed)
+	}
+
+	line := fmt.Sprintf("%-13s %-22s %10s", check.Metric, values, change)
+	switch {
+	case check.Regressed:
+		return output.Red("✗ "+line) + " " + limit
+	case check.Gated:
+		return output.Green("✓ "+line) + " " + limit
+	}
+	return "  " + line
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func TestSaveBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := 64
+		if r.URL.Path == "/large" {
+			size = request.MaxBodySize + 1
+		}
+		w.Write(bytes.Repeat([]byte("x"), size))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	endpoint := config.Endpoint{Name: "small", URL: server.URL + "/small", Method: "GET", ExpectedStatus: 200,
+		SaveBody: filepath.Join(dir, "small.txt")}
+	if result := testEndpointOnce(context.Background(), endpoint, 5*time.Second, nil); !result.Success {
+		t.Fatalf("small body: %s", result.Message)
+	}
+	if data, err := os.ReadFile(endpoint.SaveBody); err != nil || len(data) != 64 {
+		t.Errorf("saved %d bytes (error %v), want 64", len(data), err)
+	}
+
+	// A body past the read limit fails the endpoint rather than save part of it
+	endpoint = config.Endpoint{Name: "large", URL: server.URL + "/large", Method: "GET", ExpectedStatus: 200,
+		SaveBody: filepath.Join(dir, "large.txt")}
+	result := testEndpointOnce(context.Background(), endpoint, 5*time.Second, nil)
+	if result.Success || !strings.Contains(result.Message, "larger than 10.00 MB") {
+		t.Errorf("large body: success %t, message %q; want a failure naming the limit", result.Success, result.Message)
+	}
+	if _, err := os.Stat(endpoint.SaveBody); !os.IsNotExist(err) {
+		t.Errorf("a truncated body was saved (stat error %v)", err)
+	}
+}
+
+func TestExceedsMaxLatency(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		limit   time.Duration
+		want    bool
+	}{
+		{name: "below", latency: 99 * time.Millisecond, limit: 100 * time.Millisecond, want: false},
+		{name: "equal", latency: 100 * time.Millisecond, limit: 100 * time.Millisecond, want: false},
+		{name: "above", latency: 101 * time.Millisecond, limit: 100 * time.Millisecond, want: true},
+		{name: "no limit", latency: time.Hour, limit: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exceedsMaxLatency(tt.latency, tt.limit); got != tt.want {
+				t.Errorf("exceedsMaxLatency(%v, %v) = %v, want %v", tt.latency, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchMaxLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	batchConfig, err := config.ParseBatchConfig([]byte(fmt.Sprintf(`
+endpoints:
+  - name: slow
+    url: %[1]s/slow
+    max_latency: 20ms
+  - name: fast
+    url: %[1]s/fast
+    max_latency: 5s
+`, server.URL)), "max-latency.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := runBatchTests(batchConfig, nil)
+	if summary.Failed != 1 || summary.Successful != 1 {
+		t.Fatalf("failed %d, successful %d; want 1 and 1", summary.Failed, summary.Successful)
+	}
+	for _, result := range summary.Results {
+		switch result.Name {
+		case "slow":
+			if result.Success || result.Category != request.CategorySlow || !strings.Contains(result.Message, "exceeded max 20ms") {
+				t.Errorf("slow: success %t, category %q, message %q; want a slow failure", result.Success, result.Category, result.Message)
+			}
+		case "fast":
+			if !result.Success {
+				t.Errorf("fast: %s", result.Message)
+			}
+		}
+	}
+}
+
+func TestBatchTemplateErrorIsAFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	batchConfig, err := config.ParseBatchConfig([]byte(fmt.Sprintf(`
+endpoints:
+  - name: unrendered
+    url: %s/{{env.TAPR_TEST_UNSET_VARIABLE}}
+`, server.URL)), "template-error.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := runBatchTests(batchConfig, nil)
+	if summary.Failed != 1 || summary.Statuses[stats.StatusSkipped] != 0 {
+		t.Fatalf("failed %d, statuses %v; want one failure and nothing skipped", summary.Failed, summary.Statuses)
+	}
+	result := summary.Results[0]
+	if result.Skipped || result.Result.Error == nil || !strings.Contains(result.Message, "undefined variable") {
+		t.Errorf("skipped %t, error %v, message %q; want an unskipped template error", result.Skipped, result.Result.Error, result.Message)
+	}
+	if wasSent(result) {
+		t.Error("wasSent() = true for a request that could not be built")
+	}
+
+	// It fails the run, under --fail-on too
+	for _, conditions := range [][]string{nil, {"5xx"}} {
+		failOn = conditions
+		if code := batchExitCode(summary); code != ExitFailure {
+			t.Errorf("exit code with --fail-on %v = %d, want %d", conditions, code, ExitFailure)
+		}
+	}
+	failOn = nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/compare"
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+var (
+	batchHistory     string  // Record batch results as a run in this history file
+	compareHistory   string  // History file holding the runs to compare
+	compareThreshold float64 // Latency change in percent that counts as a regression
+)
+
+// compareCmd represents the compare command for diffing two runs
+var compareCmd = &cobra.Command{
+	Use:   "compare [run1] [run2]",
+	Short: "Compare two runs endpoint by endpoint and highlight regressions",
+	Long: `Compare diffs the latency and success rate of every endpoint between two
+runs, e.g. before and after a deployment. Each run is either:
+
+  • a JSON summary file written with --summary-file
+  • the ID of a batch run recorded with tapr batch --history
+  • latest or previous, the last two runs in the history file
+
+An endpoint regressed if it got slower by more than --threshold (and at
+least 5ms), or if fewer of its checks passed. Exits with code 1 if any
+endpoint regressed.`,
+	Example: `  tapr batch endpoints.yml --summary-file before.json
+  tapr batch endpoints.yml --summary-file after.json
+  tapr compare before.json after.json
+
+  tapr batch endpoints.yml --history runs.ndjson   # before and after a deploy
+  tapr compare previous latest --history runs.ndjson --threshold 10`,
+	Args: cobra.ExactArgs(2),
+	Run:  runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	batchCmd.Flags().StringVar(
+		&batchHistory,
+		"history",
+		"",
+		"Also record the results as a run in this history file (see tapr compare)",
+	)
+
+	compareCmd.Flags().StringVar(
+		&compareHistory,
+		"history",
+		history.DefaultPath(),
+		"History file holding the runs",
+	)
+
+	compareCmd.Flags().Float64Var(
+		&compareThreshold,
+		"threshold",
+		compare.DefaultThreshold*100,
+		"Latency change in percent that counts as a regression or improvement",
+	)
+}
+
+// recordBatchRun appends the results of a batch to --history, tagged with
+// an ID for tapr compare. Write failures are reported but never change the
+// exit code.
+func recordBatchRun(summary *stats.BatchSummary) {
+	if batchHistory == "" {
+		return
+	}
+
+	store, err := history.Open(batchHistory)
+	if err == nil {
+		run := runStarted.Format("20060102-150405")
+		for _, result := range summary.Results {
+			if result.Skipped {
+				continue
+			}
+			err = store.Append(history.Record{
+				Time:      runStarted,
+				Name:      result.Name,
+				URL:       result.URL,
+				Status:    result.Result.StatusCode,
+				LatencyMs: float64(result.Result.Latency.Microseconds()) / 1000,
+				Success:   result.Success,
+				Message:   result.Message,
+				Silenced:  result.Silenced != "",
+				Upstream:  result.Upstream,
+				Run:       run,
+			})
+			if err != nil {
+				break
+			}
+		}
+		store.Close()
+	}
+	if err != nil {
+		slog.Warn("failed to record the run", "error", err)
+	}
+}
+
+// runCompare executes the compare command.
+func runCompare(cmd *cobra.Command, args []string) {
+	before, err := loadCompareRun(args[0])
+	if err != nil {
+		exitCompare(err)
+	}
+	after, err := loadCompareRun(args[1])
+	if err != nil {
+		exitCompare(err)
+	}
+
+	result := compare.Compare(before, after, compareThreshold/100)
+	if outputFormat == "json" {
+		displayCompareJSON(before, after, result)
+	} else if !silent {
+		displayCompare(before, after, result)
+	}
+	if result.Regressions > 0 {
+		os.Exit(ExitFailure)
+	}
+}
+
+// loadCompareRun loads a run from a summary file, or from the history by ID
+// (or latest and previous).
+func loadCompareRun(arg string) (compare.Run, error) {
+	if _, err := os.Stat(arg); err == nil {
+		summary, err := output.ReadSummaryFile(arg)
+		if err != nil {
+			return compare.Run{}, err
+		}
+		return compare.FromSummary(arg, summary), nil
+	}
+
+	id := arg
+	if arg == "latest" || arg == "previous" {
+		records, err := history.Load(compareHistory, history.Query{})
+		if err != nil {
+			return compare.Run{}, err
+		}
+		runs := history.Runs(records)
+		back := 1
+		if arg == "previous" {
+			back = 2
+		}
+		if len(runs) < back {
+			return compare.Run{}, fmt.Errorf("no %s run in %s (record runs with tapr batch --history)", arg, compareHistory)
+		}
+		id = runs[len(runs)-back]
+	}
+
+	records, err := history.Load(compareHistory, history.Query{Run: id})
+	if err != nil {
+		return compare.Run{}, err
+	}
+	if len(records) == 0 {
+		return compare.Run{}, fmt.Errorf("%s is neither a summary file nor a run in %s", arg, compareHistory)
+	}
+	return compare.FromRecords(id, records), nil
+}
+
+// displayCompare prints the per-endpoint diff of two runs.
+func displayCompare(before, after compare.Run, result compare.Result) {
+	output.Printf("🔀 Comparing runs\n")
+	output.Printf("   A: %s\n", output.Blue(before.Label))
+	output.Printf("   B: %s\n\n", output.Blue(after.Label))
+
+	width := len("Endpoint")
+	for _, change := range result.Changes {
+		width = max(width, len(change.Name))
+	}
+	width = min(width, multiWatchURLWidth)
+
+	output.Printf("   %-*s  %10s  %10s  %8s  %s\n", width, "Endpoint", "Latency A", "Latency B", "Change", "Success")
+	for _, change := range result.Changes {
+		name := shortenURL(change.Name, width)
+		switch change.Verdict {
+		case compare.Added:
+			output.Printf("   %-*s  %10s  %10s  %8s  %s\n", width, name, "–", roundLatency(change.After.Latency), "", output.Blue("new"))
+			continue
+		case compare.Removed:
+			output.Printf("   %-*s  %10s  %10s  %8s  %s\n", width, name, roundLatency(change.Before.Latency), "–", "", output.Yellow("removed"))
+			continue
+		}
+
+		success := fmt.Sprintf("%.0f%% → %.0f%%", change.Before.SuccessRate(), change.After.SuccessRate())
+		delta := fmt.Sprintf("%+.1f%%", change.LatencyChange*100)
+		marker := " "
+		switch change.Verdict {
+		case compare.Regressed:
+			marker = output.Red("▲")
+			delta = output.Red(fmt.Sprintf("%8s", delta))
+		case compare.Improved:
+			marker = output.Green("▼")
+			delta = output.Green(fmt.Sprintf("%8s", delta))
+		default:
+			delta = fmt.Sprintf("%8s", delta)
+		}
+		output.Printf("   %-*s  %10s  %10s  %s  %s %s\n", width, name,
+			roundLatency(change.Before.Latency), roundLatency(change.After.Latency), delta, success, marker)
+	}
+	output.Println()
+
+	switch {
+	case result.Regressions > 0:
+		output.Printf("%s\n", output.Red(fmt.Sprintf("✗ %d endpoint(s) regressed", result.Regressions)))
+	case result.Improvements > 0:
+		output.Printf("%s\n", output.Green(fmt.Sprintf("✓ No regressions, %d endpoint(s) improved", result.Improvements)))
+	default:
+		output.Printf("%s\n", output.Green("✓ No regressions"))
+	}
+}
+
+// jsonCompare is the JSON output of compare.
+type jsonCompare struct {
+	Before       string          `json:"before"`
+	After        string          `json:"after"`
+	Regressions  int             `json:"regressions"`
+	Improvements int             `json:"improvements"`
+	Endpoints    []jsonCompareEP `json:"endpoints"`
+}
+
+// jsonCompareEP is one endpoint in the JSON output of compare.
+type jsonCompareEP struct {
+	Name          string   `json:"name"`
+	Verdict       string   `json:"verdict"`
+	BeforeMs      *float64 `json:"before_latency_ms,omitempty"`
+	AfterMs       *float64 `json:"after_latency_ms,omitempty"`
+	LatencyChange float64  `json:"latency_change_percent"`
+	BeforeSuccess *float64 `json:"before_success_rate,omitempty"`
+	AfterSuccess  *float64 `json:"after_success_rate,omitempty"`
+}
+
+// displayCompareJSON prints the diff of two runs as JSON.
+func displayCompareJSON(before, after compare.Run, result compare.Result) {
+	doc := jsonCompare{
+		Before:       before.Label,
+		After:        after.Label,
+		Regressions:  result.Regressions,
+		Improvements: result.Improvements,
+		Endpoints:    make([]jsonCompareEP, len(result.Changes)),
+	}
+	for i, change := range result.Changes {
+		endpoint := jsonCompareEP{Name: change.Name, Verdict: change.Verdict, LatencyChange: change.LatencyChange * 100}
+		if change.Before != nil {
+			latency, rate := float64(change.Before.Latency)/float64(time.Millisecond), change.Before.SuccessRate()
+			endpoint.BeforeMs, endpoint.BeforeSuccess = &latency, &rate
+		}
+		if change.After != nil {
+			latency, rate := float64(change.After.Latency)/float64(time.Millisecond), change.After.SuccessRate()
+			endpoint.AfterMs, endpoint.AfterSuccess = &latency, &rate
+		}
+		doc.Endpoints[i] = endpoint
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		exitCompare(err)
+	}
+	fmt.Println(string(data))
+}
+
+// exitCompare prints an error and exits.
+func exitCompare(err error) {
+	fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+	os.Exit(ExitError)
+}
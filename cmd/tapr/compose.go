@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/compose"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/netcheck"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// composeRetryInterval is the time between rounds while --wait is waiting
+// for services to come up.
+const composeRetryInterval = 2 * time.Second
+
+var (
+	composeFile        string // Compose file (default: found in the current directory)
+	composeOptions     compose.Options
+	composeWait        time.Duration // How long to wait for every service to pass
+	composeConcurrency int           // Number of concurrent checks
+)
+
+// composeCmd represents the compose command for checking a compose stack
+var composeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Health-check the services of a Docker Compose stack",
+	Long: `Compose mode reads a compose file, finds the ports each service publishes and
+checks them all, so a local stack can be verified after ` + "`docker compose up`" + `
+without writing a batch config.
+
+Ports are checked over HTTP, at the path the service's healthcheck requests
+(curl or wget) if it has one. Well-known database and broker ports (PostgreSQL,
+MySQL, Redis, ...) get a TCP connect check instead. Labels override this:
+
+  tapr.check             http, https, tcp or skip
+  tapr.path              Path to request (default: / or the healthcheck's)
+  tapr.expected_status   Status code to expect (default 200)
+
+Use --wait right after starting the stack to keep retrying until every
+service answers.`,
+	Example: `  tapr compose
+  docker compose up -d && tapr compose --wait 60s
+  tapr compose -f deploy/docker-compose.yml --host 192.168.1.20`,
+	Args: cobra.NoArgs,
+	Run:  runCompose,
+}
+
+func init() {
+	rootCmd.AddCommand(composeCmd)
+
+	composeCmd.Flags().StringVarP(
+		&composeFile,
+		"file",
+		"f",
+		"",
+		"Compose file (default: compose.yaml or docker-compose.yml in the current directory)",
+	)
+
+	composeCmd.Flags().StringVar(
+		&composeOptions.Host,
+		"host",
+		"localhost",
+		"Host the stack's ports are published on",
+	)
+
+	composeCmd.Flags().DurationVar(
+		&composeWait,
+		"wait",
+		0,
+		"Keep retrying failing services for up to this long (e.g. 60s)",
+	)
+
+	composeCmd.Flags().IntVarP(
+		&composeConcurrency,
+		"concurrency",
+		"c",
+		5,
+		"Number of concurrent checks",
+	)
+
+	composeCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for each response",
+	)
+}
+
+// composeResult is the result of checking one compose target.
+type composeResult struct {
+	Service string `json:"service"`
+	Target  string `json:"target,omitempty"` // URL or host:port
+	Check   string `json:"check"`
+	Skipped string `json:"skipped,omitempty"`
+	Status  int    `json:"status,omitempty"`
+	Latency int64  `json:"latency_ms,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+
+	latency time.Duration
+}
+
+// runCompose executes the compose command.
+func runCompose(cmd *cobra.Command, args []string) {
+	if outputFormat != "pretty" && outputFormat != "json" {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: compose supports pretty and json output, not %s", outputFormat)))
+		os.Exit(ExitError)
+	}
+	if composeWait < 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --wait can't be negative"))
+		os.Exit(ExitError)
+	}
+	resolveHTTPVersion()
+
+	path := composeFile
+	var err error
+	if path == "" {
+		path, err = compose.Find(".")
+	}
+	var file *compose.File
+	if err == nil {
+		file, err = compose.Load(path)
+	}
+	var targets []compose.Target
+	if err == nil {
+		targets, err = compose.Targets(file, composeOptions)
+	}
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	checked := 0
+	for _, target := range targets {
+		if target.Check != compose.CheckSkip {
+			checked++
+		}
+	}
+	if !quiet && !silent && outputFormat == "pretty" {
+		output.Printf("🐳 Checking %d port(s) of %d service(s) in %s\n\n", checked, len(file.Services), path)
+	}
+
+	// With --wait, retry until everything passes or time runs out
+	deadline := time.Now().Add(composeWait)
+	results := checkComposeTargets(targets)
+	for composeWait > 0 && countComposeFailures(results) > 0 && time.Now().Add(composeRetryInterval).Before(deadline) {
+		if !quiet && !silent && outputFormat == "pretty" {
+			output.Printf("⏳ Waiting for %s...\n", strings.Join(failingComposeTargets(results), ", "))
+		}
+		time.Sleep(composeRetryInterval)
+		results = checkComposeTargets(targets)
+	}
+
+	failed := countComposeFailures(results)
+	switch {
+	case outputFormat == "json":
+		printComposeJSON(results)
+	case silent:
+	case quiet:
+		for _, result := range results {
+			if !result.Success && result.Skipped == "" {
+				fmt.Fprintf(output.Stderr, "%s %s: %s\n", output.Red("✗"), result.Service, result.Error)
+			}
+		}
+	default:
+		if composeWait > 0 {
+			output.Println()
+		}
+		printComposeResults(results, checked, failed)
+	}
+
+	if failed > 0 {
+		os.Exit(ExitFailure)
+	}
+	os.Exit(ExitSuccess)
+}
+
+// checkComposeTargets checks every target that isn't skipped: HTTP targets
+// like batch endpoints, TCP targets with a connect check.
+func checkComposeTargets(targets []compose.Target) []composeResult {
+	batchConfig := &config.BatchConfig{Concurrency: max(composeConcurrency, 1), Timeout: timeout}
+	for _, target := range targets {
+		if target.URL == "" {
+			continue
+		}
+		expected := target.ExpectedStatus
+		if expected == 0 {
+			expected = 200
+		}
+		batchConfig.Endpoints = append(batchConfig.Endpoints, config.Endpoint{
+			Name:           target.Label(),
+			URL:            target.URL,
+			Method:         "GET",
+			ExpectedStatus: expected,
+		})
+	}
+
+	httpResults := make(map[string]stats.BatchResult)
+	if len(batchConfig.Endpoints) > 0 {
+		// Failures are reported below, not by the batch run
+		wasQuiet := quiet
+		quiet = false
+		summary := runBatchTests(batchConfig, nil)
+		quiet = wasQuiet
+		for _, result := range summary.Results {
+			httpResults[result.Name] = result
+		}
+	}
+
+	// TCP checks are quick, so they run one after another
+	results := make([]composeResult, 0, len(targets))
+	for _, target := range targets {
+		result := composeResult{Service: target.Service, Check: target.Check, Skipped: target.Skip}
+		switch {
+		case target.Check == compose.CheckSkip:
+			result.Target = target.Address
+		case target.URL != "":
+			result.Target = target.URL
+			batch := httpResults[target.Label()]
+			result.Success = batch.Success
+			if batch.Result.Error == nil {
+				result.Status = batch.Result.StatusCode
+				result.latency = batch.Result.Latency
+			}
+			if !batch.Success {
+				result.Error = batch.Message
+			}
+		default:
+			result.Target = target.Address
+			tcp := netcheck.CheckTCP(target.Address, netcheck.TCPOptions{Timeout: timeout})
+			result.Success = tcp.Error == nil
+			result.latency = tcp.ConnectTime
+			if tcp.Error != nil {
+				result.Error = tcp.Error.Error()
+			}
+		}
+		result.Latency = result.latency.Milliseconds()
+		results = append(results, result)
+	}
+	return results
+}
+
+// countComposeFailures returns the number of checked targets that failed.
+func countComposeFailures(results []composeResult) int {
+	failed := 0
+	for _, result := range results {
+		if !result.Success && result.Skipped == "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// failingComposeTargets names the failing targets, for --wait progress.
+func failingComposeTargets(results []composeResult) []string {
+	var failing []string
+	for _, result := range results {
+		if !result.Success && result.Skipped == "" {
+			failing = append(failing, result.Service)
+		}
+	}
+	return failing
+}
+
+// printComposeResults prints one line per target with its check result.
+func printComposeResults(results []composeResult, checked, failed int) {
+	table := output.NewTable(
+		output.Column{Header: "SERVICE", Flex: true, Min: 12},
+		output.Column{Header: "TARGET", Flex: true, Middle: true, Min: 16},
+		output.Column{Header: "STATUS"},
+		output.Column{Header: "LATENCY"},
+	)
+	table.Gap = 1
+	table.Width = tableWidth()
+
+	skipped := 0
+	for _, result := range results {
+		if result.Skipped != "" {
+			skipped++
+			table.AddSpan(output.Yellow("– "+result.Skipped), result.Service)
+			continue
+		}
+
+		status, latency := "-", "-"
+		switch {
+		case result.Check == compose.CheckTCP && result.Success:
+			status = "open"
+		case result.Status != 0:
+			status = fmt.Sprintf("%d", result.Status)
+		}
+		if result.Success || result.Status != 0 {
+			latency = result.latency.Round(time.Millisecond).String()
+		}
+		if result.Success {
+			status = output.Green(status)
+		} else {
+			status = output.Red(status)
+		}
+		table.AddRow(result.Service, result.Target, status, latency)
+		if !result.Success && result.Error != "" {
+			table.AddSpan(output.Red("✗ "+result.Error), "")
+		}
+	}
+	output.Print(table.Render())
+	output.Println()
+
+	suffix := ""
+	if skipped > 0 {
+		suffix = fmt.Sprintf(" (%d skipped)", skipped)
+	}
+	if failed > 0 {
+		output.Printf("%s %d of %d port(s) failing%s\n", output.Red("✗"), failed, checked, suffix)
+	} else {
+		output.Printf("%s All %d port(s) healthy%s\n", output.Green("✓"), checked, suffix)
+	}
+}
+
+// printComposeJSON prints every target with its result as JSON.
+func printComposeJSON(results []composeResult) {
+	data, err := json.MarshalIndent(map[string]interface{}{"targets": results}, "", "  ")
+	if err != nil {
+		fmt.Fprintf(output.Stderr, "Error formatting JSON: %v\n", err)
+		os.Exit(ExitError)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/symtalha14/tapr/internal/cors"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// corsRequest describes the browser request to check, from the cors flags
+var corsRequest cors.Request
+
+// corsCmd represents the cors command for checking CORS configuration
+var corsCmd = &cobra.Command{
+	Use:   "cors [url]",
+	Short: "Check whether a browser would allow a cross-origin request",
+	Long: `CORS mode sends the preflight OPTIONS request a browser would send before
+calling url from a page on --origin, and checks the response the way the
+browser does: status, Access-Control-Allow-Origin, -Credentials, -Methods
+and -Headers. Exits with code 1 if the browser would block the request.
+
+Content-Type counts as a custom header: browsers only let pages set it
+without a preflight for form and plain text bodies.`,
+	Example: `  tapr cors https://api.example.com/users --origin https://app.example.com
+  tapr cors https://api.example.com/users --origin https://app.example.com --method POST --headers Content-Type,X-Custom
+  tapr cors https://api.example.com/me --origin https://app.example.com --credentials`,
+	Args: cobra.ExactArgs(1),
+	Run:  runCORS,
+}
+
+func init() {
+	rootCmd.AddCommand(corsCmd)
+
+	corsCmd.Flags().StringVar(
+		&corsRequest.Origin,
+		"origin",
+		"",
+		"Origin of the calling page (e.g., https://app.example.com)",
+	)
+
+	corsCmd.Flags().StringVarP(
+		&corsRequest.Method,
+		"method",
+		"X",
+		"GET",
+		"Method of the request the page makes",
+	)
+
+	corsCmd.Flags().StringSliceVar(
+		&corsRequest.Headers,
+		"headers",
+		[]string{},
+		"Headers the page sets on the request (e.g., Content-Type,X-Custom)",
+	)
+
+	corsCmd.Flags().BoolVar(
+		&corsRequest.Credentials,
+		"credentials",
+		false,
+		"The page sends cookies or HTTP auth (fetch credentials: 'include')",
+	)
+
+	corsCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for the response",
+	)
+}
+
+// runCORS executes the cors command.
+func runCORS(cmd *cobra.Command, args []string) {
+	url := args[0]
+	if !isValidURL(url) {
+		fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
+		os.Exit(ExitError)
+	}
+	if corsRequest.Origin == "" {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --origin is required (the origin of the calling page)"))
+		os.Exit(ExitError)
+	}
+	corsRequest.Origin = strings.TrimSuffix(corsRequest.Origin, "/")
+	corsRequest.Method = strings.ToUpper(corsRequest.Method)
+	url = withQueryParams(url)
+
+	opts := requestOptions(corsRequest.PreflightHeaders())
+	opts.Method = http.MethodOptions
+	opts.Jar = nil // Browsers never send cookies with a preflight
+
+	output.Printf("🌐 CORS preflight: %s\n", output.Blue(url))
+	output.Printf("   Origin:  %s\n", corsRequest.Origin)
+	output.Printf("   Request: %s\n\n", describeCORSRequest(corsRequest))
+
+	result := request.Ping(url, opts)
+	if result.Error != nil {
+		printError(url, result.Error)
+		os.Exit(ExitFailure)
+	}
+
+	checks := cors.Evaluate(corsRequest, result.StatusCode, result.Headers)
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+	for _, check := range checks {
+		symbol := output.Green("✓")
+		switch check.Result {
+		case cors.Fail:
+			symbol = output.Red("✗")
+		case cors.Warn:
+			symbol = output.Yellow("⚠")
+		}
+		output.Printf("  %s %s  %s\n", symbol, output.Pad(check.Name, width), check.Detail)
+	}
+	output.Println()
+
+	if !corsRequest.NeedsPreflight() {
+		output.Printf("💡 Browsers send this request without a preflight; only Access-Control-Allow-Origin\n")
+		output.Printf("   (and -Credentials) of the actual response decide whether the page can read it.\n\n")
+	}
+
+	if !cors.Allowed(checks) {
+		output.Printf("%s\n", output.Red(fmt.Sprintf("✗ A page on %s can't make this request", corsRequest.Origin)))
+		os.Exit(ExitFailure)
+	}
+	output.Printf("%s\n", output.Green(fmt.Sprintf("✓ A page on %s can make this request", corsRequest.Origin)))
+}
+
+// describeCORSRequest formats the request being checked, e.g.
+// "POST with Content-Type, X-Custom and credentials".
+func describeCORSRequest(r cors.Request) string {
+	var extras []string
+	if len(r.Headers) > 0 {
+		extras = append(extras, strings.Join(r.Headers, ", "))
+	}
+	if r.Credentials {
+		extras = append(extras, "credentials")
+	}
+	if len(extras) == 0 {
+		return r.Method
+	}
+	return r.Method + " with " + strings.Join(extras, " and ")
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/crawl"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// Crawl flags
+var (
+	crawlDepth        int  // Levels of links to follow from the start page
+	crawlMaxPages     int  // Most URLs to check
+	crawlConcurrency  int  // Requests at once while discovering and checking
+	crawlSitemap      bool // Also check the pages listed in the site's sitemaps
+	crawlExternal     bool // Also check links to other hosts
+	crawlIgnoreRobots bool // Crawl paths disallowed by robots.txt
+)
+
+// maxSitemaps limits how many sitemaps a sitemap index may pull in.
+const maxSitemaps = 20
+
+// crawlCmd represents the crawl command for checking the links of a site
+var crawlCmd = &cobra.Command{
+	Use:   "crawl [base-url]",
+	Short: "Find the pages of a site by following its links and check each one",
+	Long: `Crawl mode follows the links of the HTML pages of a site, starting from
+base-url, and checks every page and asset it finds with the batch engine:
+broken links, errors and slow pages are reported like failed endpoints,
+along with the page that links to them.
+
+Only pages on the same host are followed. Paths disallowed by robots.txt
+are skipped unless --ignore-robots is given. With --sitemap, the pages
+listed in the site's sitemaps (from robots.txt, or /sitemap.xml) are
+checked and crawled too.`,
+	Example: `  tapr crawl https://example.com
+  tapr crawl https://example.com --depth 3 --max-pages 500
+  tapr crawl https://example.com --sitemap --depth 0
+  tapr crawl https://docs.example.com --external --slow-threshold 1s`,
+	Args: cobra.ExactArgs(1),
+	Run:  runCrawl,
+}
+
+func init() {
+	rootCmd.AddCommand(crawlCmd)
+
+	crawlCmd.Flags().IntVar(
+		&crawlDepth,
+		"depth",
+		2,
+		"Levels of links to follow from the start page (0 = only the start page and sitemaps)",
+	)
+
+	crawlCmd.Flags().IntVar(
+		&crawlMaxPages,
+		"max-pages",
+		crawl.DefaultMaxPages,
+		"Most URLs to check",
+	)
+
+	crawlCmd.Flags().IntVarP(
+		&crawlConcurrency,
+		"concurrency",
+		"c",
+		10,
+		"Number of concurrent requests",
+	)
+
+	crawlCmd.Flags().BoolVar(
+		&crawlSitemap,
+		"sitemap",
+		false,
+		"Also check the pages listed in the site's sitemaps",
+	)
+
+	crawlCmd.Flags().BoolVar(
+		&crawlExternal,
+		"external",
+		false,
+		"Also check links to other hosts (they are never crawled)",
+	)
+
+	crawlCmd.Flags().BoolVar(
+		&crawlIgnoreRobots,
+		"ignore-robots",
+		false,
+		"Crawl paths that robots.txt disallows",
+	)
+
+	crawlCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for each response",
+	)
+
+	crawlCmd.Flags().DurationVar(
+		&maxTime,
+		"max-time",
+		0,
+		"Maximum time for checking the discovered URLs (e.g., 5m)",
+	)
+}
+
+// runCrawl executes the crawl command.
+func runCrawl(cmd *cobra.Command, args []string) {
+	base := args[0]
+	if !isValidURL(base) {
+		fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
+		os.Exit(ExitError)
+	}
+	if crawlDepth < 0 || crawlMaxPages < 1 || crawlConcurrency < 1 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --depth cannot be negative, --max-pages and --concurrency must be at least 1"))
+		os.Exit(ExitError)
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	summaryTarget = base
+
+	// Validate protocol, connection and exit policy flags before starting any requests
+	resolveHTTPVersion()
+	batchPool()
+	resolveExitPolicy()
+
+	opts := requestOptions(nil)
+	opts.Method = "GET"
+	opts.ReadBody = true
+	fetch := func(ctx context.Context, target string) (crawl.Page, error) {
+		result := request.PingContext(ctx, target, opts)
+		if result.Error != nil {
+			return crawl.Page{}, result.Error
+		}
+		if result.StatusCode >= 400 {
+			return crawl.Page{}, errors.New(result.Status)
+		}
+		return crawl.Page{URL: result.FinalURL, ContentType: result.Headers.Get("Content-Type"), Body: result.Body}, nil
+	}
+
+	pretty := !quiet && !silent && outputFormat == "pretty"
+	if pretty {
+		output.Printf("\n%s", output.Box(tableWidth(), fmt.Sprintf("Crawling %s (depth: %d, max pages: %d)", base, crawlDepth, crawlMaxPages)))
+	}
+
+	// robots.txt rules and sitemaps
+	robotsURL := baseURL.ResolveReference(&url.URL{Path: "/robots.txt"}).String()
+	var robots *crawl.Robots
+	if page, err := fetch(context.Background(), robotsURL); err == nil {
+		robots = crawl.ParseRobots(page.Body, request.DefaultUserAgent)
+	}
+	var seeds []string
+	if crawlSitemap {
+		seeds = sitemapURLs(baseURL, robots, fetch, pretty)
+	}
+	if crawlIgnoreRobots && robots != nil {
+		robots = &crawl.Robots{Sitemaps: robots.Sitemaps}
+	}
+
+	if pretty {
+		output.Println("Discovering links... 🔎")
+	}
+	crawler := &crawl.Crawler{
+		Fetch:       fetch,
+		Depth:       crawlDepth,
+		MaxPages:    crawlMaxPages,
+		Concurrency: crawlConcurrency,
+		External:    crawlExternal,
+		Robots:      robots,
+	}
+	links, err := crawler.Crawl(context.Background(), baseURL.String(), seeds)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	if pretty {
+		output.Printf("Checking %d URL(s) (concurrency: %d)... ⚡\n", len(links), crawlConcurrency)
+	}
+
+	// Check every URL with the batch engine
+	batchConfig := &config.BatchConfig{Concurrency: crawlConcurrency, Timeout: timeout}
+	referrers := make(map[string]string, len(links))
+	names := make(map[string]bool, len(links))
+	for _, link := range links {
+		name := crawlName(baseURL, link.URL)
+		if _, taken := names[name]; taken {
+			name = link.URL // Same path over another scheme or port
+		}
+		names[name] = true
+		batchConfig.Endpoints = append(batchConfig.Endpoints, config.Endpoint{
+			Name:           name,
+			URL:            link.URL,
+			Method:         "GET",
+			ExpectedStatus: 200,
+		})
+		if link.Referrer != "" {
+			referrers[name] = crawlName(baseURL, link.Referrer)
+		}
+	}
+
+	startTime := time.Now()
+	sink := openMetrics()
+	batchProgress = showBatchProgress()
+	openResultStream()
+	summary := runBatchTests(batchConfig, sink)
+	summary.TotalTime = time.Since(startTime)
+	closeMetrics(sink)
+
+	// Say where broken links were found
+	for i, result := range summary.Results {
+		if referrer, ok := referrers[result.Name]; ok && !result.Success {
+			summary.Results[i].Message = strings.TrimSpace(result.Message + " (linked from " + referrer + ")")
+		}
+	}
+
+	displayBatchResults(summary, base)
+}
+
+// sitemapURLs returns the pages listed in the sitemaps that robots.txt
+// names, or in /sitemap.xml, following sitemap indexes.
+func sitemapURLs(baseURL *url.URL, robots *crawl.Robots, fetch crawl.Fetcher, pretty bool) []string {
+	var queue []string
+	if robots != nil {
+		queue = append(queue, robots.Sitemaps...)
+	}
+	if len(queue) == 0 {
+		queue = append(queue, baseURL.ResolveReference(&url.URL{Path: "/sitemap.xml"}).String())
+	}
+
+	var urls []string
+	for fetched := 0; len(queue) > 0 && fetched < maxSitemaps; fetched++ {
+		sitemap := queue[0]
+		queue = queue[1:]
+		page, err := fetch(context.Background(), sitemap)
+		if err == nil {
+			var nested []string
+			var listed []string
+			listed, nested, err = crawl.ParseSitemap(page.Body)
+			urls = append(urls, listed...)
+			queue = append(queue, nested...)
+		}
+		if err != nil && pretty {
+			output.Printf("%s Sitemap %s: %v\n", output.Yellow("⚠️"), sitemap, err)
+		}
+	}
+	return urls
+}
+
+// crawlName names a crawled URL in the results: its path and query on the
+// crawled site, or the full URL elsewhere.
+func crawlName(baseURL *url.URL, link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || !strings.EqualFold(parsed.Host, baseURL.Host) {
+		return link
+	}
+	return parsed.RequestURI()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/alert"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/daemon"
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/maintenance"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+var (
+	daemonListen  string // Overrides the status API address from the config
+	daemonHistory string // Overrides the history file from the config
+)
+
+// daemonCmd represents the daemon command for scheduled monitoring
+var daemonCmd = &cobra.Command{
+	Use:   "daemon [config]",
+	Short: "Monitor endpoints continuously in the background",
+	Long: `Daemon mode loads a YAML file of monitors, checks each one on its own
+interval, appends every result to the history store and serves the current
+state over a local HTTP API and status page.
+
+Status API (default 127.0.0.1:9876):
+  GET /                              HTML status page with uptime and latency charts
+  GET /healthz                       Daemon liveness
+  GET /status                        Latest state of every monitor (503 if any is down)
+  GET /history?name=api&since=1h     Stored results
+
+Control API (local requests only, or with the api_token from the config):
+  GET    /monitors                   Monitors and their stats
+  POST   /monitors                   Add a monitor (JSON or YAML, like a config entry)
+  DELETE /monitors/{name}            Remove a monitor
+  POST   /monitors/{name}/pause      Pause scheduled checks (and /resume)
+  POST   /monitors/{name}/check      Check right away
+
+Perfect for:
+  • Lightweight uptime monitoring
+  • Collecting latency history on a build box or server
+  • Feeding dashboards and health checks from one place
+  • An instant internal status page for the team`,
+	Example: `  tapr daemon monitors.yml
+  tapr daemon monitors.yml --listen :8080   # Status page for the whole network
+  tapr daemon monitors.yml --listen :9876 --history /var/lib/tapr/history.ndjson
+  curl localhost:9876/status
+  curl -X POST localhost:9876/monitors/api/check`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDaemon,
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringVar(
+		&daemonListen,
+		"listen",
+		"",
+		"Address for the status API (default from config, or "+config.DefaultDaemonListen+")",
+	)
+
+	daemonCmd.Flags().StringVar(
+		&daemonHistory,
+		"history",
+		"",
+		"Path of the history file (default from config, or "+history.DefaultPath()+")",
+	)
+}
+
+// runDaemon executes the daemon command.
+func runDaemon(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadDaemonConfig(args[0])
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	// Command-line flags override the config file
+	if daemonListen != "" {
+		cfg.Listen = daemonListen
+	}
+	if daemonHistory != "" {
+		cfg.History = daemonHistory
+	}
+	if cfg.History == "" {
+		cfg.History = history.DefaultPath()
+	}
+	if cfg.Silences == "" {
+		cfg.Silences = maintenance.DefaultSilencePath()
+	}
+
+	store, err := history.Open(cfg.History)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	defer store.Close()
+
+	// Stop cleanly on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !quiet && !silent {
+		output.Printf("🛰️  Monitoring %d endpoint(s)\n", len(cfg.Monitors))
+		output.Printf("   Status page: %s\n", output.Blue("http://"+cfg.Listen+"/"))
+		output.Printf("   Status API:  %s\n", output.Blue("http://"+cfg.Listen+"/status"))
+		output.Printf("   History:     %s\n\n", store.Path())
+	}
+
+	onResult := logMonitorResult
+	var alerts *alert.Manager
+	if cfg.Alerts != nil {
+		alerts = alert.NewManager(cfg.Alerts, logAlertError)
+		onResult = func(monitor config.Monitor, result stats.BatchResult) {
+			logMonitorResult(monitor, result)
+			if result.Silenced != "" || result.Upstream != "" {
+				return // Failures during maintenance or a dependency's outage don't alert
+			}
+			for _, event := range alerts.Observe(monitorCheck(monitor, result)) {
+				if !silent {
+					output.Printf("[%s] 🔔 %s\n", event.Time.Format("15:04:05"), event.Summary())
+				}
+			}
+		}
+	}
+
+	d := daemon.New(cfg, store, checkMonitor, onResult)
+	err = d.Run(ctx)
+	if alerts != nil {
+		alerts.Wait()
+	}
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	if !quiet && !silent {
+		output.Println("\n👋 Daemon stopped")
+	}
+}
+
+// checkMonitor runs one daemon check using the same logic as batch mode.
+func checkMonitor(endpoint config.Endpoint, timeout time.Duration) stats.BatchResult {
+	rendered, err := endpoint.Render(nil)
+	if err != nil {
+		return errorResult(endpoint, err)
+	}
+	return testEndpoint(context.Background(), rendered, timeout, nil, nil)
+}
+
+// monitorCheck converts a daemon check result for the alert manager.
+func monitorCheck(monitor config.Monitor, result stats.BatchResult) alert.Check {
+	return alert.Check{
+		Name:    monitor.Name,
+		URL:     monitor.URL,
+		Success: result.Success,
+		Status:  result.Result.StatusCode,
+		Latency: result.Result.Latency,
+		Message: result.Message,
+	}
+}
+
+// logMonitorResult prints one line per check. Quiet mode only shows failures.
+func logMonitorResult(monitor config.Monitor, result stats.BatchResult) {
+	if silent || (quiet && result.Success) {
+		return
+	}
+
+	timestamp := time.Now().Format("15:04:05")
+	if result.Success {
+		output.Printf("[%s] %s %-20s %d  %s\n", timestamp, output.Green("✓"), monitor.Name,
+			result.Result.StatusCode, formatLatency(result.Result.Latency))
+		return
+	}
+
+	if result.Silenced != "" {
+		output.Printf("[%s] %s %-20s %s (%s)\n", timestamp, output.Yellow("🔇"), monitor.Name, result.Message, result.Silenced)
+		return
+	}
+	if result.Upstream != "" {
+		output.Printf("[%s] %s %-20s %s (degraded, upstream %s is down)\n", timestamp, output.Yellow("◌"), monitor.Name, result.Message, result.Upstream)
+		return
+	}
+	output.Printf("[%s] %s %-20s %s\n", timestamp, output.Red("✗"), monitor.Name, result.Message)
+}
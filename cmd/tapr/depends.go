@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// displayDependencyTree prints the endpoints that depend on others as a
+// tree under the endpoints they depend on, with their results, so a failure
+// and the endpoints it degraded are seen together. It prints nothing when
+// no endpoint has depends_on.
+func displayDependencyTree(results []stats.BatchResult) {
+	byName := make(map[string]stats.BatchResult, len(results))
+	dependents := make(map[string][]string)
+	for _, result := range results {
+		if _, exists := byName[result.Name]; exists {
+			continue // Only the first endpoint of a name takes part in dependencies
+		}
+		byName[result.Name] = result
+		for _, dep := range result.DependsOn {
+			dependents[dep] = append(dependents[dep], result.Name)
+		}
+	}
+	if len(dependents) == 0 {
+		return
+	}
+
+	output.Printf("\n🌳 Dependencies\n")
+	printed := make(map[string]bool)
+	for _, result := range results {
+		if len(result.DependsOn) > 0 || len(dependents[result.Name]) == 0 || printed[result.Name] {
+			continue
+		}
+		printed[result.Name] = true
+		output.Printf("   %s %s\n", result.Name, dependencyState(result))
+		printDependents(result.Name, "   ", dependents, byName)
+	}
+}
+
+// printDependents prints the endpoints that depend on name, and theirs in
+// turn. An endpoint with several dependencies appears under each of them.
+func printDependents(name, indent string, dependents map[string][]string, byName map[string]stats.BatchResult) {
+	children := dependents[name]
+	for i, child := range children {
+		branch, next := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, next = "└── ", "    "
+		}
+		output.Printf("%s%s%s %s\n", indent, branch, child, dependencyState(byName[child]))
+		printDependents(child, indent+next, dependents, byName)
+	}
+}
+
+// dependencyState describes a result in the dependency tree.
+func dependencyState(result stats.BatchResult) string {
+	switch {
+	case result.Success:
+		return output.Green("✓")
+	case result.Silenced != "":
+		return output.Yellow("🔇 " + result.Silenced)
+	case result.Upstream != "":
+		return output.Yellow(fmt.Sprintf("◌ degraded (upstream: %s)", result.Upstream))
+	}
+	return output.Red("✗ " + result.Message)
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/jsondiff"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+var (
+	diffBody          bool     // Compare response bodies as well
+	diffIgnoreHeaders []string // Extra headers to leave out of the comparison
+)
+
+// volatileHeaders change on every response and are never compared.
+var volatileHeaders = []string{"Date", "Age", "Expires", "Set-Cookie", "X-Request-Id", "Cf-Ray", "X-Amzn-Trace-Id"}
+
+// diffCmd represents the diff command for comparing two deployments
+var diffCmd = &cobra.Command{
+	Use:   "diff [url1] [url2]",
+	Short: "Compare the responses of two URLs",
+	Long: `Diff mode sends the same request to two URLs at the same time and reports
+differences in status, headers and latency. With --body, JSON bodies are
+compared structurally and other bodies byte for byte.
+
+Headers that change on every response (Date, Set-Cookie, request IDs, ...)
+are ignored. Exits with code 1 if any difference is found.
+
+Perfect for:
+  • Canary validation (old vs new deployment)
+  • Comparing regions or replicas behind a load balancer
+  • Verifying migrations and proxy configuration`,
+	Example: `  tapr diff https://api.example.com/health https://canary.example.com/health
+  tapr diff https://v1.example.com/users https://v2.example.com/users --body
+  tapr diff URL1 URL2 --body --ignore-header Server -H "Authorization: Bearer token"`,
+	Args: cobra.ExactArgs(2),
+	Run:  runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVar(
+		&diffBody,
+		"body",
+		false,
+		"Compare response bodies (structural diff for JSON)",
+	)
+
+	diffCmd.Flags().StringSliceVar(
+		&diffIgnoreHeaders,
+		"ignore-header",
+		[]string{},
+		"Response header to leave out of the comparison, repeatable",
+	)
+
+	diffCmd.Flags().StringVarP(
+		&method,
+		"method",
+		"X",
+		"GET",
+		"HTTP method (GET, POST, PUT, PATCH, DELETE)",
+	)
+
+	diffCmd.Flags().StringSliceVarP(
+		&inlineHeaders,
+		"header",
+		"H",
+		[]string{},
+		"Add a header (format: 'Key: Value'), repeatable",
+	)
+
+	diffCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for each response",
+	)
+}
+
+// headerDiff is a response header whose values differ between two URLs.
+type headerDiff struct {
+	Name  string
+	Left  string // "" if missing on the left
+	Right string // "" if missing on the right
+}
+
+// runDiff executes the diff command.
+func runDiff(cmd *cobra.Command, args []string) {
+	for _, arg := range args {
+		if !isValidURL(arg) {
+			fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
+			os.Exit(ExitError)
+		}
+	}
+	left, right := withQueryParams(args[0]), withQueryParams(args[1])
+
+	var headers map[string]string
+	if len(inlineHeaders) > 0 {
+		parsed, err := config.ParseInlineHeaders(inlineHeaders)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
+			os.Exit(ExitError)
+		}
+		headers = parsed
+	}
+
+	opts := requestOptions(headers)
+	opts.ReadBody = diffBody
+
+	// Send both requests at the same time so they see the same conditions
+	var results [2]request.Result
+	var wg sync.WaitGroup
+	for i, target := range []string{left, right} {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			results[i] = request.Ping(target, opts)
+		}(i, target)
+	}
+	wg.Wait()
+	saveCookieJar(opts.Jar)
+
+	output.Printf("🔀 Comparing responses\n")
+	output.Printf("   A: %s\n", output.Blue(left))
+	output.Printf("   B: %s\n\n", output.Blue(right))
+
+	a, b := results[0], results[1]
+	if a.Error != nil || b.Error != nil {
+		for i, result := range results {
+			if result.Error != nil {
+				output.Printf("%s %s: %v\n", output.Red("✗"), string(rune('A'+i)), result.Error)
+			}
+		}
+		os.Exit(ExitFailure)
+	}
+
+	differences := 0
+
+	// Status
+	if a.StatusCode == b.StatusCode {
+		output.Printf("  Status:   %s %s\n", a.Status, output.Green("✓"))
+	} else {
+		output.Printf("  Status:   %s → %s %s\n", a.Status, b.Status, output.Red("✗"))
+		differences++
+	}
+
+	// Latency is reported but never counted as a difference
+	output.Printf("  Latency:  %s → %s (%s)\n", formatLatency(a.Latency), formatLatency(b.Latency), latencyChange(a.Latency, b.Latency))
+
+	// Headers
+	differences += displayHeaderDiff(a.Headers, b.Headers)
+
+	// Body
+	if diffBody {
+		differences += displayBodyDiff(a.Body, b.Body)
+	}
+
+	output.Println()
+	if differences == 0 {
+		output.Printf("%s\n", output.Green("✓ Responses match"))
+		return
+	}
+	output.Printf("%s\n", output.Red(fmt.Sprintf("✗ Found %d difference(s)", differences)))
+	os.Exit(ExitFailure)
+}
+
+// displayBodyDiff prints the differences between two bodies and returns
+// how many were found. JSON bodies are compared structurally.
+func displayBodyDiff(a, b []byte) int {
+	diffs, err := jsondiff.Compare(a, b)
+	if err != nil {
+		// Not JSON: fall back to an exact comparison
+		if bytes.Equal(a, b) {
+			output.Printf("  Body:     identical %s\n", output.Green("✓"))
+			return 0
+		}
+		output.Printf("  Body:     different (%s → %s) %s\n", formatBytes(int64(len(a))), formatBytes(int64(len(b))), output.Red("✗"))
+		return 1
+	}
+
+	if len(diffs) == 0 {
+		output.Printf("  Body:     identical JSON %s\n", output.Green("✓"))
+		return 0
+	}
+
+	output.Printf("  Body:     %d JSON difference(s) %s\n", len(diffs), output.Red("✗"))
+	for _, d := range diffs {
+		switch d.Kind {
+		case jsondiff.Added:
+			output.Printf("    %s %s: %s\n", output.Green("+"), d.Path, d.Right)
+		case jsondiff.Removed:
+			output.Printf("    %s %s: %s\n", output.Red("-"), d.Path, d.Left)
+		default:
+			output.Printf("    %s %s: %s → %s\n", output.Yellow("~"), d.Path, d.Left, d.Right)
+		}
+	}
+	return len(diffs)
+}
+
+// displayHeaderDiff prints the differences between two sets of response
+// headers, leaving out volatile and --ignore-header headers, and returns how
+// many were found.
+func displayHeaderDiff(a, b http.Header) int {
+	headerDiffs := diffHeaders(a, b, append(volatileHeaders, diffIgnoreHeaders...))
+	if len(headerDiffs) == 0 {
+		output.Printf("  Headers:  identical %s\n", output.Green("✓"))
+		return 0
+	}
+
+	output.Printf("  Headers:  %d different %s\n", len(headerDiffs), output.Red("✗"))
+	for _, d := range headerDiffs {
+		switch {
+		case d.Left == "":
+			output.Printf("    %s %s: %s\n", output.Green("+"), d.Name, d.Right)
+		case d.Right == "":
+			output.Printf("    %s %s: %s\n", output.Red("-"), d.Name, d.Left)
+		default:
+			output.Printf("    %s %s: %s → %s\n", output.Yellow("~"), d.Name, d.Left, d.Right)
+		}
+	}
+	return len(headerDiffs)
+}
+
+// diffHeaders returns the headers whose values differ between a and b,
+// sorted by name, skipping the ignored ones.
+func diffHeaders(a, b http.Header, ignore []string) []headerDiff {
+	ignored := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		ignored[http.CanonicalHeaderKey(name)] = true
+	}
+
+	names := make(map[string]bool)
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+
+	var diffs []headerDiff
+	for name := range names {
+		if ignored[name] {
+			continue
+		}
+		left := strings.Join(a.Values(name), ", ")
+		right := strings.Join(b.Values(name), ", ")
+		if left != right {
+			diffs = append(diffs, headerDiff{Name: name, Left: left, Right: right})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// latencyChange formats the relative latency change from a to b.
+func latencyChange(a, b time.Duration) string {
+	if a == 0 {
+		return "n/a"
+	}
+
+	change := float64(b-a) / float64(a) * 100
+	formatted := fmt.Sprintf("%+.1f%%", change)
+	if change > 0 {
+		return output.Red(formatted)
+	}
+	return output.Green(formatted)
+}
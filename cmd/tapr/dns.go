@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/netcheck"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// dnsResolver is the DNS server to compare against the system resolver
+var dnsResolver string
+
+// dnsCmd represents the dns command for checking name resolution only
+var dnsCmd = &cobra.Command{
+	Use:   "dns [host]",
+	Short: "Resolve a hostname and show its DNS records",
+	Long: `DNS mode resolves a hostname (or the host of a URL) and reports its
+A, AAAA and CNAME records along with the resolution time.
+
+With --resolver, the same lookup is also sent to the given DNS server and
+any differences from the system resolver are highlighted.
+
+Perfect for:
+  • Debugging the DNS phase seen in trace
+  • Verifying DNS cutovers and propagation
+  • Spotting stale or split-horizon DNS`,
+	Example: `  tapr dns api.example.com
+  tapr dns https://api.example.com/health
+  tapr dns api.example.com --resolver 1.1.1.1`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDNS,
+}
+
+func init() {
+	rootCmd.AddCommand(dnsCmd)
+
+	dnsCmd.Flags().StringVar(
+		&dnsResolver,
+		"resolver",
+		"",
+		"DNS server to compare against the system resolver (e.g., 1.1.1.1)",
+	)
+
+	dnsCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for resolution",
+	)
+}
+
+// runDNS executes the dns command.
+func runDNS(cmd *cobra.Command, args []string) {
+	host := hostFromArg(args[0])
+	if host == "" {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: invalid host: %s", args[0])))
+		os.Exit(ExitError)
+	}
+
+	system := netcheck.LookupDNS(host, "", timeout)
+	displayDNSResult("System resolver", system)
+
+	if dnsResolver == "" {
+		if system.Error != nil {
+			os.Exit(ExitFailure)
+		}
+		return
+	}
+
+	custom := netcheck.LookupDNS(host, dnsResolver, timeout)
+	displayDNSResult(fmt.Sprintf("Resolver %s", dnsResolver), custom)
+
+	if system.Error != nil || custom.Error != nil {
+		os.Exit(ExitFailure)
+	}
+
+	if netcheck.SameAddresses(system, custom) {
+		output.Printf("%s\n", output.Green("✓ Both resolvers returned the same addresses"))
+	} else {
+		output.Printf("%s\n", output.Yellow("⚠️  Resolvers returned different addresses (CDN, split-horizon or stale DNS?)"))
+	}
+}
+
+// displayDNSResult prints the records returned by one resolver.
+func displayDNSResult(label string, result netcheck.DNSResult) {
+	output.Printf("🔎 %s\n", label)
+
+	if result.Error != nil {
+		output.Printf("   %s %v\n\n", output.Red("✗"), result.Error)
+		return
+	}
+
+	output.Printf("   Host:     %s\n", result.Host)
+	output.Printf("   Time:     %s\n", formatLatency(result.Duration))
+	if result.CNAME != "" {
+		output.Printf("   CNAME:    %s\n", result.CNAME)
+	}
+	for _, a := range result.A {
+		output.Printf("   A:        %s\n", a)
+	}
+	for _, aaaa := range result.AAAA {
+		output.Printf("   AAAA:     %s\n", aaaa)
+	}
+	output.Println()
+}
+
+// hostFromArg extracts a hostname from either a bare host, host:port or a URL.
+func hostFromArg(arg string) string {
+	if isValidURL(arg) {
+		parsed, err := url.Parse(arg)
+		if err != nil {
+			return ""
+		}
+		return parsed.Hostname()
+	}
+
+	// Strip an optional port
+	if host, _, err := net.SplitHostPort(arg); err == nil {
+		return host
+	}
+	return arg
+}
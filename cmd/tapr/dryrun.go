@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+var dryRun bool // Print the requests instead of sending them (--dry-run)
+
+func init() {
+	rootCmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Print the request that would be sent (secrets masked) without sending it",
+	)
+
+	batchCmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Print the requests that would be sent (secrets masked) without sending them",
+	)
+}
+
+// dryRunPing prints the request a ping would send and exits.
+func dryRunPing(url string, opts request.PingOptions) {
+	if err := printPreview(url, opts); err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	output.Println()
+	output.Println(output.Yellow("Dry run: no request sent"))
+	os.Exit(ExitSuccess)
+}
+
+// dryRunBatch prints the request of every batch endpoint, with templates
+// expanded, and exits. Values captured from responses are not known ahead
+// of time and are shown as their {{name}} placeholder.
+func dryRunBatch(batchConfig *config.BatchConfig) {
+	vars := make(map[string]string, len(batchConfig.Variables))
+	for name, value := range batchConfig.Variables {
+		vars[name] = value
+	}
+	for _, endpoint := range batchConfig.Endpoints {
+		for name := range endpoint.Capture {
+			if _, defined := vars[name]; !defined {
+				vars[name] = "{{" + name + "}}"
+			}
+		}
+	}
+
+	jar := resolveCookieJar()
+	failed := 0
+	for _, endpoint := range batchConfig.Endpoints {
+		label := endpoint.Name
+		if label == "" {
+			label = endpoint.URL
+		}
+		if endpoint.Stage != "" {
+			label += fmt.Sprintf(" (stage: %s)", endpoint.Stage)
+		}
+		output.Println(output.Cyan("# " + label))
+
+		if len(batchConfig.Headers) > 0 {
+			endpoint.Headers = config.MergeHeaders(batchConfig.Headers, endpoint.Headers)
+		}
+		err := func() error {
+			rendered, err := endpoint.Render(vars)
+			if err != nil {
+				return err
+			}
+			targetURL, err := rendered.RequestURL()
+			if err != nil {
+				return err
+			}
+			return printPreview(targetURL, endpointOptions(rendered, batchConfig.Timeout, jar))
+		}()
+		if err != nil {
+			output.Println(output.Red(fmt.Sprintf("✗ %s", capitalize(err.Error()))))
+			failed++
+		}
+		output.Println()
+	}
+
+	output.Println(output.Yellow(fmt.Sprintf("Dry run: %d requests not sent", len(batchConfig.Endpoints)-failed)))
+	if failed > 0 {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %d endpoint(s) could not be prepared", failed)))
+		os.Exit(ExitError)
+	}
+	os.Exit(ExitSuccess)
+}
+
+// printPreview prints the request that opts would send to url.
+func printPreview(url string, opts request.PingOptions) error {
+	req, err := request.Preview(url, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(output.FormatRequest(req, opts.Body)) // Request as sent, never themed
+	if opts.Auth != nil && opts.Auth.Digest {
+		output.Println(output.Yellow(fmt.Sprintf("(Digest auth as '%s' is answered after the server's challenge)", opts.Auth.Username)))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+// TestRepeatableFlagsKeepCommas checks that repeatable flags take each value
+// whole: a comma is part of the value, not a separator.
+func TestRepeatableFlagsKeepCommas(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		got   *[]string
+	}{
+		{name: "param", value: "ids=1,2,3", got: &queryParams},
+		{name: "expect-header", value: "Cache-Control: regex:^max-age=\\d+, public$", got: &expectHeaders},
+		{name: "cookie", value: "prefs=a,b,c", got: &cookies},
+		{name: "resolve", value: "api.example.com:443:203.0.113.7,203.0.113.8", got: &resolveHosts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := rootCmd.ParseFlags([]string{"--" + tt.name, tt.value}); err != nil {
+				t.Fatal(err)
+			}
+			flag := rootCmd.Flags().Lookup(tt.name)
+			t.Cleanup(func() {
+				flag.Value.(interface{ Replace([]string) error }).Replace(nil)
+				flag.Changed = false
+			})
+
+			if len(*tt.got) != 1 || (*tt.got)[0] != tt.value {
+				t.Errorf("--%s %q parsed as %q, want one value", tt.name, tt.value, *tt.got)
+			}
+		})
+	}
+}
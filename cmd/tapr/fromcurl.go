@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/importer"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+var fromCurlYAML bool // Print the batch endpoint instead of running it
+
+// fromCurlCmd represents the from-curl command for converting curl snippets
+var fromCurlCmd = &cobra.Command{
+	Use:   "from-curl [curl-command]",
+	Short: "Run a curl command through tapr, or convert it to batch YAML",
+	Long: `From-curl parses a curl command line and runs the same request through tapr,
+showing latency, status and size. With --yaml it prints the equivalent batch
+endpoint instead, ready to paste into a config file.
+
+Pass the command as a single quoted argument, or after '--' so that its
+options are not read as tapr flags. Method, headers, bodies (-d, --data-*,
+--json), basic auth (-u), cookies (-b), -G and --max-time are translated;
+options with no tapr equivalent (-k, -L, -o, ...) are reported and ignored.
+
+Perfect for:
+  • Migrating curl snippets from docs and runbooks
+  • Re-running a "Copy as cURL" request from browser dev tools`,
+	Example: `  tapr from-curl 'curl -X POST https://api.example.com/users -H "Content-Type: application/json" -d "{\"name\":\"ada\"}"'
+  tapr from-curl --yaml 'curl -sS https://api.example.com/health' >> endpoints.yml
+  tapr from-curl -- curl -I https://api.example.com/health`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runFromCurl,
+}
+
+func init() {
+	rootCmd.AddCommand(fromCurlCmd)
+
+	fromCurlCmd.Flags().BoolVar(
+		&fromCurlYAML,
+		"yaml",
+		false,
+		"Print the equivalent batch endpoint YAML instead of running the request",
+	)
+}
+
+// runFromCurl executes the from-curl command.
+func runFromCurl(cmd *cobra.Command, args []string) {
+	words := args
+	if len(args) == 1 {
+		split, err := importer.SplitCommand(args[0])
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		words = split
+	}
+
+	endpoint, ignored, err := importer.Curl(words)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	if len(ignored) > 0 && !silent {
+		fmt.Fprintln(output.Stderr, output.Yellow(fmt.Sprintf("⚠️  Ignored curl options: %s", strings.Join(ignored, " "))))
+	}
+
+	if fromCurlYAML {
+		data, err := importer.Marshal(&config.BatchConfig{Endpoints: []config.Endpoint{endpoint}})
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		os.Stdout.Write(data)
+		return
+	}
+
+	if !isValidURL(endpoint.URL) {
+		fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
+		os.Exit(ExitError)
+	}
+
+	opts := request.PingOptions{
+		Method:    endpoint.Method,
+		Timeout:   timeout,
+		Headers:   config.MergeHeaders(userConfig.Headers, endpoint.Headers),
+		Body:      []byte(endpoint.Body),
+		UserAgent: userAgent,
+	}
+	if endpoint.Timeout > 0 {
+		opts.Timeout = endpoint.Timeout
+	}
+	applyTraceHTTP(&opts)
+
+	result := request.Ping(endpoint.URL, opts)
+	if result.Error != nil {
+		printError(endpoint.URL, result.Error)
+		os.Exit(ExitFailure)
+	}
+	printSuccess(result)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// Method modes of the ping command
+var (
+	headMode    bool // Send HEAD and print the response headers (--head)
+	optionsMode bool // Send OPTIONS and report allowed methods and CORS headers (--options)
+)
+
+func init() {
+	rootCmd.Flags().BoolVarP(
+		&headMode,
+		"head",
+		"I",
+		false,
+		"Send a HEAD request and print the response headers",
+	)
+
+	rootCmd.Flags().BoolVar(
+		&optionsMode,
+		"options",
+		false,
+		"Send an OPTIONS request and report the allowed methods and CORS headers",
+	)
+}
+
+// applyMethodMode sets the method for --head or --options. It exits if
+// both are given, or if -X asks for another method.
+func applyMethodMode(cmd *cobra.Command) {
+	if !headMode && !optionsMode {
+		return
+	}
+	if headMode && optionsMode {
+		fmt.Fprintln(output.Stderr, output.Red("Error: use either --head or --options, not both"))
+		os.Exit(ExitError)
+	}
+
+	mode, modeMethod := "--head", http.MethodHead
+	if optionsMode {
+		mode, modeMethod = "--options", http.MethodOptions
+	}
+	if cmd.Flags().Changed("method") && !strings.EqualFold(method, modeMethod) {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %s sends %s, it can't be combined with -X %s", mode, modeMethod, method)))
+		os.Exit(ExitError)
+	}
+	if len(formFields) > 0 {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %s can't send a --form body", mode)))
+		os.Exit(ExitError)
+	}
+	method = modeMethod
+}
+
+// printResponseHeaders prints response headers sorted by name, with
+// secrets masked.
+func printResponseHeaders(header http.Header) {
+	if len(header) == 0 {
+		output.Printf("  Headers:  %s\n", output.Yellow("(none)"))
+		return
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output.Println("  Headers:")
+	for _, name := range names {
+		for _, value := range header[name] {
+			output.Printf("    %s: %s\n", output.Cyan(name), request.MaskHeader(name, value))
+		}
+	}
+}
+
+// runOptions sends an OPTIONS request to url and reports the methods the
+// server allows and the CORS headers it sends, then exits.
+func runOptions(url string, opts request.PingOptions) {
+	result := request.Ping(url, opts)
+	saveCookieJar(opts.Jar)
+	if result.Error != nil {
+		printError(url, result.Error)
+		exitPing(url, result, ExitFailure)
+	}
+	printSuccess(result)
+
+	if methods := request.AllowedMethods(result.Headers); len(methods) > 0 {
+		output.Printf("  Allow:    %s\n", output.Green(strings.Join(methods, ", ")))
+	} else {
+		output.Printf("  Allow:    %s\n", output.Yellow("(not sent)"))
+	}
+
+	names := request.CORSHeaders(result.Headers)
+	if len(names) == 0 {
+		output.Printf("  CORS:     %s\n", output.Yellow("no Access-Control-* headers"))
+		if !request.HasHeader(opts.Headers, "Origin") {
+			output.Printf("            Servers often answer CORS only to a cross-origin caller: add -H 'Origin: https://app.example.com'\n")
+		}
+	} else {
+		width := 0
+		for _, name := range names {
+			width = max(width, len(name))
+		}
+		output.Println("  CORS:")
+		for _, name := range names {
+			output.Printf("    %s  %s\n", output.Pad(name+":", width+1), strings.Join(result.Headers.Values(name), ", "))
+		}
+	}
+
+	if resolveExitPolicy().failed(result, request.Classify(result), true) {
+		output.Printf("%s Status %d matches --fail-on\n", output.Red("✗"), result.StatusCode)
+		exitPing(url, result, ExitFailure)
+	}
+	exitPing(url, result, ExitSuccess)
+}
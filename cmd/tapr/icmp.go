@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/netcheck"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// ICMP-specific flags
+var (
+	icmpCount    int           // Number of echo requests to send
+	icmpInterval time.Duration // Time between echo requests
+)
+
+// icmpCmd represents the icmp command for network-level reachability
+var icmpCmd = &cobra.Command{
+	Use:   "icmp [host]",
+	Short: "Check network reachability with ICMP echo (ping)",
+	Long: `ICMP mode sends echo requests to a host and reports packet loss and
+round-trip time statistics, independent of any HTTP service.
+
+Comparing ICMP round-trip times with HTTP latency shows whether slowness
+comes from the network or from the application.
+
+Raw ICMP sockets require root or the CAP_NET_RAW capability.`,
+	Example: `  tapr icmp api.example.com
+  tapr icmp https://api.example.com/health --count 10
+  tapr icmp 10.0.0.5 -n 20 -i 200ms`,
+	Args: cobra.ExactArgs(1),
+	Run:  runICMP,
+}
+
+func init() {
+	rootCmd.AddCommand(icmpCmd)
+
+	icmpCmd.Flags().IntVarP(
+		&icmpCount,
+		"count",
+		"n",
+		5,
+		"Number of echo requests (0 = until Ctrl+C)",
+	)
+
+	icmpCmd.Flags().DurationVarP(
+		&icmpInterval,
+		"interval",
+		"i",
+		time.Second,
+		"Time between echo requests",
+	)
+
+	icmpCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for each reply",
+	)
+}
+
+// runICMP executes the icmp command.
+func runICMP(cmd *cobra.Command, args []string) {
+	host := hostFromArg(args[0])
+
+	pinger, err := netcheck.NewPinger(host, timeout)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	defer pinger.Close()
+
+	if !quiet && !silent {
+		output.Printf("ICMP echo to %s (%s)\n", output.Blue(host), pinger.Addr())
+	}
+
+	tracker := stats.NewTracker()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+loop:
+	for icmpCount == 0 || tracker.Total < icmpCount {
+		result := pinger.Ping()
+
+		if result.Success {
+			tracker.Record(result.RTT, true)
+		} else {
+			tracker.Record(0, false)
+		}
+
+		if !quiet && !silent {
+			if result.Success {
+				output.Printf("   seq=%-4d from %s  time=%s\n", result.Seq, result.Addr, formatLatency(result.RTT))
+			} else {
+				output.Printf("   seq=%-4d %s\n", result.Seq, output.Red(fmt.Sprintf("✗ %v", result.Error)))
+			}
+		}
+
+		if icmpCount > 0 && tracker.Total >= icmpCount {
+			break
+		}
+
+		select {
+		case <-time.After(icmpInterval):
+		case <-sigChan:
+			break loop
+		}
+	}
+
+	if !silent {
+		displayICMPSummary(host, tracker)
+	}
+
+	if tracker.Successful == 0 {
+		os.Exit(ExitFailure)
+	}
+}
+
+// displayICMPSummary prints packet loss and RTT statistics.
+func displayICMPSummary(host string, tracker *stats.Tracker) {
+	loss := 100 - tracker.SuccessRate()
+
+	lossColor := output.Green
+	if loss >= 100 {
+		lossColor = output.Red
+	} else if loss > 0 {
+		lossColor = output.Yellow
+	}
+
+	output.Printf("\n📊 %s ICMP statistics\n", host)
+	output.Printf("   Sent:         %d\n", tracker.Total)
+	output.Printf("   Received:     %d\n", tracker.Successful)
+	output.Printf("   Packet Loss:  %s\n", lossColor(fmt.Sprintf("%.1f%%", loss)))
+
+	if tracker.Successful == 0 {
+		return
+	}
+
+	// Failed probes are recorded with zero RTT, so only use replies for RTT stats
+	replies := stats.NewTracker()
+	for _, rtt := range tracker.Latencies {
+		if rtt > 0 {
+			replies.Record(rtt, true)
+		}
+	}
+
+	output.Printf("   Min RTT:      %s\n", replies.MinLatency)
+	output.Printf("   Avg RTT:      %s\n", formatLatency(replies.AvgLatency()))
+	output.Printf("   Max RTT:      %s\n", replies.MaxLatency)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/importer"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+var (
+	importWrite   string   // File to write the generated batch config to
+	importMethods []string // OpenAPI operations to import
+	importServer  string   // Base URL overriding the spec's servers
+	importEnv     string   // Postman environment file
+	importHosts   []string // HAR hosts to import
+	importStatic  bool     // Import static assets from a HAR
+	importSeq     bool     // Chain HAR requests to replay them in order
+)
+
+// importCmd groups the converters that generate batch configs
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Generate a batch config from another tool's format",
+	Long: `Import converts API descriptions from other tools into a tapr batch config.
+The YAML is printed to stdout, or written to a file with --write, ready for
+'tapr batch'.
+
+Perfect for:
+  • Bootstrapping smoke tests from an existing API spec
+  • Reusing request collections from other tools`,
+}
+
+// importOpenAPICmd converts an OpenAPI/Swagger document
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi [spec-file]",
+	Short: "Generate a batch config from an OpenAPI/Swagger spec",
+	Long: `Generate a batch config from an OpenAPI 3 or Swagger 2 document (YAML or JSON).
+
+Each GET operation becomes an endpoint (use --methods for others). URLs are
+relative to the {{base}} variable, taken from the spec's first server.
+Parameters are filled from their example or default values, and the lowest
+documented 2xx response becomes the expected status. Path parameters without
+an example are left as {{name}} placeholders to define under variables.`,
+	Example: `  tapr import openapi openapi.yaml
+  tapr import openapi swagger.json --server https://staging.example.com --write smoke.yml
+  tapr import openapi openapi.yaml --methods GET,HEAD`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImportOpenAPI,
+}
+
+// importPostmanCmd converts a Postman collection
+var importPostmanCmd = &cobra.Command{
+	Use:   "postman [collection-file]",
+	Short: "Generate a batch config from a Postman collection",
+	Long: `Generate a batch config from a Postman collection (v2.0 or v2.1 export).
+
+Every request becomes an endpoint, named "Folder / Request" when it lives in
+a folder. URLs, methods, headers, raw and urlencoded bodies are kept, and
+bearer or basic auth becomes an Authorization header. Postman {{variables}}
+use the same syntax as tapr templates, so collection variables (and the
+values of an exported environment, with --environment) become batch
+variables.`,
+	Example: `  tapr import postman shop.postman_collection.json
+  tapr import postman shop.postman_collection.json -e prod.postman_environment.json -w smoke.yml`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImportPostman,
+}
+
+// importHARCmd converts a browser-recorded HAR file
+var importHARCmd = &cobra.Command{
+	Use:   "har [har-file]",
+	Short: "Generate a batch config from a HAR recording",
+	Long: `Generate a batch config from an HTTP Archive (HAR) recorded in a browser's
+developer tools.
+
+Every request keeps its method, URL, headers (including cookies) and body,
+and the recorded response status becomes the expected status. Images,
+scripts, stylesheets, fonts and media are skipped unless --static is given.
+With --sequence each endpoint depends on the previous one, so 'tapr batch'
+replays the user flow in the recorded order.`,
+	Example: `  tapr import har session.har --host api.example.com
+  tapr import har checkout.har --sequence -w checkout.yml`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImportHAR,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importOpenAPICmd)
+	importCmd.AddCommand(importPostmanCmd)
+	importCmd.AddCommand(importHARCmd)
+
+	importCmd.PersistentFlags().StringVarP(
+		&importWrite,
+		"write",
+		"w",
+		"",
+		"Write the batch config to a file instead of stdout",
+	)
+
+	importOpenAPICmd.Flags().StringSliceVar(
+		&importMethods,
+		"methods",
+		[]string{"GET"},
+		"HTTP methods of the operations to import",
+	)
+
+	importOpenAPICmd.Flags().StringVar(
+		&importServer,
+		"server",
+		"",
+		"Base URL to use instead of the spec's servers",
+	)
+
+	importPostmanCmd.Flags().StringVarP(
+		&importEnv,
+		"environment",
+		"e",
+		"",
+		"Postman environment file whose values override collection variables",
+	)
+
+	importHARCmd.Flags().StringSliceVar(
+		&importHosts,
+		"host",
+		[]string{},
+		"Only import requests to this host, repeatable",
+	)
+
+	importHARCmd.Flags().BoolVar(
+		&importStatic,
+		"static",
+		false,
+		"Also import images, scripts, stylesheets, fonts and media",
+	)
+
+	importHARCmd.Flags().BoolVar(
+		&importSeq,
+		"sequence",
+		false,
+		"Chain the requests with depends_on to replay them in order",
+	)
+}
+
+// runImportOpenAPI executes the import openapi command.
+func runImportOpenAPI(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	cfg, err := importer.OpenAPI(data, importer.OpenAPIOptions{
+		Methods: importMethods,
+		Server:  importServer,
+	})
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	writeImportedConfig(cfg)
+}
+
+// runImportPostman executes the import postman command.
+func runImportPostman(cmd *cobra.Command, args []string) {
+	collection, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	var environment []byte
+	if importEnv != "" {
+		if environment, err = os.ReadFile(importEnv); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+	}
+
+	cfg, err := importer.Postman(collection, environment)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	writeImportedConfig(cfg)
+}
+
+// runImportHAR executes the import har command.
+func runImportHAR(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	cfg, err := importer.HAR(data, importer.HAROptions{
+		Hosts:    importHosts,
+		Static:   importStatic,
+		Sequence: importSeq,
+	})
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	writeImportedConfig(cfg)
+}
+
+// writeImportedConfig prints the generated batch config (or writes it to
+// --write) and warns about templates that still need a variable.
+func writeImportedConfig(cfg *config.BatchConfig) {
+	data, err := importer.Marshal(cfg)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	if importWrite == "" {
+		os.Stdout.Write(data)
+	} else {
+		if err := os.WriteFile(importWrite, data, 0o644); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		fmt.Fprintf(output.Stderr, "%s Wrote %d endpoint(s) to %s\n", output.Green("✓"), len(cfg.Endpoints), importWrite)
+	}
+
+	for _, endpoint := range cfg.Endpoints {
+		if _, err := endpoint.Render(cfg.Variables); err != nil {
+			fmt.Fprintln(output.Stderr, output.Yellow(fmt.Sprintf("⚠️  %s: %v", endpoint.Name, err)))
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/k8s"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+var (
+	k8sQuery          k8s.Query
+	k8sOptions        k8s.Options
+	k8sExpectedStatus int // Status every target must return
+	k8sConcurrency    int // Number of concurrent checks
+)
+
+// k8sCmd represents the k8s command for checking cluster services
+var k8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Health-check the Services and Ingresses of a Kubernetes cluster",
+	Long: `K8s mode lists the Services and Ingresses of a cluster with kubectl (using your
+kubeconfig and its authentication), checks each one over HTTP and reports the
+result next to the pods behind it, so a cluster can be smoke-tested without
+writing down any URLs.
+
+Ingresses are checked at each host and path. Services are checked through
+their load balancer, or with --cluster-dns by their cluster DNS name when
+tapr itself runs inside the cluster (e.g. as a Job).
+
+Perfect for:
+  • Smoke testing a cluster after a deploy or upgrade
+  • Finding services whose pods are not ready
+  • Checking every ingress host and path at once`,
+	Example: `  tapr k8s
+  tapr k8s --context prod -n shop -l app=checkout
+  tapr k8s -A --selector tier=frontend
+  tapr k8s --cluster-dns --path /healthz   # from a pod in the cluster`,
+	Args: cobra.NoArgs,
+	Run:  runK8s,
+}
+
+func init() {
+	rootCmd.AddCommand(k8sCmd)
+
+	k8sCmd.Flags().StringVar(
+		&k8sQuery.Context,
+		"context",
+		"",
+		"kubeconfig context to use (default: current context)",
+	)
+
+	k8sCmd.Flags().StringVarP(
+		&k8sQuery.Namespace,
+		"namespace",
+		"n",
+		"",
+		"Namespace to check (default: the context's namespace)",
+	)
+
+	k8sCmd.Flags().BoolVarP(
+		&k8sQuery.AllNamespaces,
+		"all-namespaces",
+		"A",
+		false,
+		"Check every namespace",
+	)
+
+	k8sCmd.Flags().StringVarP(
+		&k8sQuery.Selector,
+		"selector",
+		"l",
+		"",
+		"Only check resources matching this label selector (e.g. app=api,tier!=db)",
+	)
+
+	k8sCmd.Flags().StringVar(
+		&k8sOptions.Path,
+		"path",
+		"/",
+		"Path to request on services (ingress paths are used as they are)",
+	)
+
+	k8sCmd.Flags().BoolVar(
+		&k8sOptions.ClusterDNS,
+		"cluster-dns",
+		false,
+		"Reach services by cluster DNS name (when tapr runs inside the cluster)",
+	)
+
+	k8sCmd.Flags().StringVar(
+		&k8sOptions.Domain,
+		"cluster-domain",
+		"cluster.local",
+		"Cluster domain for --cluster-dns",
+	)
+
+	k8sCmd.Flags().IntVar(
+		&k8sExpectedStatus,
+		"expected-status",
+		200,
+		"Status code every target must return",
+	)
+
+	k8sCmd.Flags().IntVarP(
+		&k8sConcurrency,
+		"concurrency",
+		"c",
+		5,
+		"Number of concurrent requests",
+	)
+
+	k8sCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for each response",
+	)
+}
+
+// k8sTargetJSON is one target in the JSON output of the k8s command.
+type k8sTargetJSON struct {
+	Resource  string       `json:"resource"`
+	Namespace string       `json:"namespace"`
+	Service   string       `json:"service,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	Skipped   string       `json:"skipped,omitempty"`
+	Status    int          `json:"status,omitempty"`
+	Latency   int64        `json:"latency_ms,omitempty"`
+	Success   bool         `json:"success"`
+	Error     string       `json:"error,omitempty"`
+	Pods      []k8sPodJSON `json:"pods"`
+}
+
+// k8sPodJSON is a pod behind a target in JSON output.
+type k8sPodJSON struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+// runK8s executes the k8s command.
+func runK8s(cmd *cobra.Command, args []string) {
+	if k8sQuery.AllNamespaces && k8sQuery.Namespace != "" {
+		fmt.Fprintln(output.Stderr, output.Red("Error: use either --namespace or --all-namespaces, not both"))
+		os.Exit(ExitError)
+	}
+	if outputFormat != "pretty" && outputFormat != "json" {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: k8s supports pretty and json output, not %s", outputFormat)))
+		os.Exit(ExitError)
+	}
+	resolveHTTPVersion()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	list, err := k8s.Get(ctx, k8sQuery)
+	cancel()
+	if err == nil && len(list) == 0 {
+		err = fmt.Errorf("kubectl returned nothing")
+	}
+	var targets []k8s.Target
+	if err == nil {
+		targets, err = k8s.Targets(list, k8sOptions)
+	}
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: no services or ingresses found"))
+		os.Exit(ExitError)
+	}
+
+	// Check the reachable targets like batch endpoints
+	batchConfig := &config.BatchConfig{Concurrency: max(k8sConcurrency, 1), Timeout: timeout}
+	for _, target := range targets {
+		if target.URL == "" {
+			continue
+		}
+		batchConfig.Endpoints = append(batchConfig.Endpoints, config.Endpoint{
+			Name:           target.Label(k8sQuery.AllNamespaces),
+			URL:            target.URL,
+			Method:         "GET",
+			ExpectedStatus: k8sExpectedStatus,
+		})
+	}
+
+	if !quiet && !silent && outputFormat == "pretty" {
+		output.Printf("☸️  Checking %d target(s) in %s\n\n", len(batchConfig.Endpoints), describeK8sQuery(k8sQuery))
+	}
+	summary := stats.NewBatchSummary()
+	summary.SlowThreshold = slowThreshold
+	if len(batchConfig.Endpoints) > 0 {
+		summary = runBatchTests(batchConfig, nil)
+	}
+	results := make(map[string]stats.BatchResult, len(summary.Results))
+	for _, result := range summary.Results {
+		results[result.Name+" "+result.URL] = result
+	}
+
+	code := ExitSuccess
+	if summary.Failed > 0 {
+		code = ExitFailure
+	}
+	switch {
+	case outputFormat == "json":
+		printK8sJSON(targets, results)
+	case silent, quiet:
+	default:
+		printK8sTargets(targets, results, summary)
+	}
+	os.Exit(code)
+}
+
+// describeK8sQuery describes where resources were looked up.
+func describeK8sQuery(q k8s.Query) string {
+	where := "the current context"
+	if q.Context != "" {
+		where = "context " + output.Blue(q.Context)
+	}
+	switch {
+	case q.AllNamespaces:
+		where += ", all namespaces"
+	case q.Namespace != "":
+		where += ", namespace " + q.Namespace
+	}
+	if q.Selector != "" {
+		where += ", selector " + q.Selector
+	}
+	return where
+}
+
+// printK8sTargets prints one line per target with its check result and the
+// readiness of the pods behind it.
+func printK8sTargets(targets []k8s.Target, results map[string]stats.BatchResult, summary *stats.BatchSummary) {
+	table := output.NewTable(
+		output.Column{Header: "RESOURCE", Flex: true, Min: 16},
+		output.Column{Header: "URL", Flex: true, Middle: true, Min: 16},
+		output.Column{Header: "STATUS"},
+		output.Column{Header: "LATENCY"},
+		output.Column{Header: "PODS"},
+	)
+	table.Gap = 1
+	table.Width = tableWidth()
+
+	skipped := 0
+	for _, target := range targets {
+		label := target.Label(k8sQuery.AllNamespaces)
+		if target.URL == "" {
+			skipped++
+			table.AddSpan(output.Yellow("– "+target.Skip), label)
+			continue
+		}
+
+		result := results[label+" "+target.URL]
+		status, latency := "-", "-"
+		if result.Result.Error == nil {
+			status = fmt.Sprintf("%d", result.Result.StatusCode)
+			latency = result.Result.Latency.Round(time.Millisecond).String()
+		}
+		if result.Success {
+			status = output.Green(status)
+		} else {
+			status = output.Red(status)
+		}
+		table.AddRow(label, target.URL, status, latency, describePods(target))
+		if !result.Success && result.Message != "" {
+			table.AddSpan(output.Red("✗ "+result.Message), "")
+		}
+	}
+	output.Print(table.Render())
+	output.Println()
+
+	checked := summary.Total
+	suffix := ""
+	if skipped > 0 {
+		suffix = fmt.Sprintf(" (%d skipped)", skipped)
+	}
+	if summary.Failed > 0 {
+		output.Printf("%s %d of %d checked target(s) failing%s\n", output.Red("✗"), summary.Failed, checked, suffix)
+	} else {
+		output.Printf("%s %d of %d checked target(s) healthy%s\n", output.Green("✓"), checked, checked, suffix)
+	}
+}
+
+// describePods summarizes the readiness of the pods behind a target,
+// naming the pods that aren't ready.
+func describePods(target k8s.Target) string {
+	if target.Pods == nil {
+		return "-"
+	}
+	ready := target.ReadyPods()
+	summary := fmt.Sprintf("%d/%d ready", ready, len(target.Pods))
+	if ready == len(target.Pods) && ready > 0 {
+		return summary
+	}
+
+	var notReady []string
+	for _, pod := range target.Pods {
+		if !pod.Ready {
+			notReady = append(notReady, pod.Name)
+		}
+	}
+	if len(notReady) > 0 {
+		summary += " (" + strings.Join(notReady, ", ") + " not ready)"
+	}
+	return output.Yellow("⚠️  " + summary)
+}
+
+// printK8sJSON prints every target with its result as JSON.
+func printK8sJSON(targets []k8s.Target, results map[string]stats.BatchResult) {
+	entries := make([]k8sTargetJSON, 0, len(targets))
+	for _, target := range targets {
+		entry := k8sTargetJSON{
+			Resource:  target.Label(false),
+			Namespace: target.Namespace,
+			Service:   target.Service,
+			URL:       target.URL,
+			Skipped:   target.Skip,
+			Pods:      []k8sPodJSON{},
+		}
+		for _, pod := range target.Pods {
+			entry.Pods = append(entry.Pods, k8sPodJSON{Name: pod.Name, Ready: pod.Ready})
+		}
+		if result, ok := results[target.Label(k8sQuery.AllNamespaces)+" "+target.URL]; ok && target.URL != "" {
+			entry.Status = result.Result.StatusCode
+			entry.Latency = result.Result.Latency.Milliseconds()
+			entry.Success = result.Success
+			if !result.Success {
+				entry.Error = result.Message
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"targets": entries}, "", "  ")
+	if err != nil {
+		fmt.Fprintf(output.Stderr, "Error formatting JSON: %v\n", err)
+		os.Exit(ExitError)
+	}
+	fmt.Println(string(data))
+}
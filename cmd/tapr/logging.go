@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/logging"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+var (
+	logLevel  string // Lowest level of diagnostic messages shown (--log-level)
+	logFormat string // text or json (--log-format)
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&logLevel,
+		"log-level",
+		"warn",
+		"Diagnostic messages to show on stderr: debug, info, warn or error (-v implies debug)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&logFormat,
+		"log-format",
+		logging.FormatText,
+		"Format of diagnostic messages: text or json",
+	)
+}
+
+// setupLogging installs the logger for --log-level and --log-format as the
+// default slog logger. --silent drops every message.
+func setupLogging(cmd *cobra.Command) {
+	if silent {
+		slog.SetDefault(logging.Discard())
+		return
+	}
+
+	name := logLevel
+	if verbose && !flagChanged(cmd, "log-level") {
+		name = "debug"
+	}
+	level, err := logging.ParseLevel(name)
+	if err == nil {
+		var logger *slog.Logger
+		w := output.Stderr
+		if logFormat == logging.FormatJSON {
+			w = os.Stderr // Machine-readable, never themed
+		}
+		logger, err = logging.New(w, level, logFormat)
+		if err == nil {
+			slog.SetDefault(logger)
+			return
+		}
+	}
+	fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+	os.Exit(ExitError)
+}
@@ -4,17 +4,33 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal" // Add this
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"syscall" // Add this
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/alert"
+	"github.com/symtalha14/tapr/internal/assert"
+	"github.com/symtalha14/tapr/internal/breaker"
 	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/discovery"
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/jsonpath"
+	"github.com/symtalha14/tapr/internal/metrics"
 	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/ratelimit"
+	"github.com/symtalha14/tapr/internal/report"
 	"github.com/symtalha14/tapr/internal/request"
 	"github.com/symtalha14/tapr/internal/stats"
 )
@@ -41,25 +57,86 @@ const logo = `
 // Command-line flags
 var (
 	timeout          time.Duration // Request timeout duration
+	firstByteTimeout time.Duration // Maximum wait for the first response byte
+	idleTimeout      time.Duration // Maximum pause while reading the response body
+	connectTimeout   time.Duration // Maximum time to connect, including the TLS handshake
+	responseTimeout  time.Duration // Maximum wait for response headers once the request is sent
 	method           string        // HTTP method (GET, POST, etc.)
 	headersFile      string        // Path to YAML file containing headers
 	inlineHeaders    []string      // Individual headers from command line
 	verbose          bool          // Enable verbose output
 	retries          int           // Number of retry attempts on failure
+	retryOnStatus    []int         // Also retry responses with these status codes
+	retryMaxDelay    time.Duration // Longest wait between retries
 	watchInterval    time.Duration // Time between requests in watch mode
 	watchCount       int           // Number of requests (0 = infinite)
+	watchKeepAlive   bool          // Reuse one connection across watch requests
+	watchAlerts      string        // YAML file with an alerts section
+	watchLogHist     bool          // Use log-scale buckets for the latency histogram
+	watchWindow      time.Duration // Sliding window for recent stats (0 = off)
+	watchLogFile     string        // Append one NDJSON line per watch check
+	watchResume      string        // Session file to resume from and save to
+	watchBreaker     int           // Consecutive failures that open the circuit (0 = off)
+	watchBreakerMax  time.Duration // Longest interval while the circuit is open
+	watchSLO         float64       // Availability target in percent (0 = none)
+	watchAnomaly     float64       // Deviations above the baseline latency that count as an anomaly (0 = off)
+	watchFromFile    string        // File listing URLs to watch, one per line
+	traceRuns        int           // Number of traced requests to aggregate
+	traceWarmup      int           // Traced requests to discard before measuring
+	traceReuse       bool          // Compare a fresh connection with a reused one
 	batchConcurrency int           // Number of concurrent requests in batch mode
+	batchTags        []string      // Only run endpoints with one of these tags
+	batchSkipTags    []string      // Skip endpoints with any of these tags
+	batchHeadersFile string        // YAML file with headers for every batch endpoint
+	batchRPS         float64       // Maximum batch requests per second
+	batchNoProgress  bool          // Don't draw a progress bar while the batch runs
+	wideOutput       bool          // Don't truncate tables and boxes to the terminal width
+	batchProgress    bool          // Draw a progress bar in runBatchTests (set by tapr batch only)
 	quiet            bool          // Only show errors
 	silent           bool          // No output at all
 	failFast         bool          // Stop on first failure
 	maxTime          time.Duration // Maximum time for batch
-	outputFormat     string        // Output format: pretty, json, csv
+	reportFile       string        // Write a Markdown or HTML report of the batch
+	emitCurl         bool          // Print curl commands reproducing failed batch requests
+	downloadBody     bool          // Read full bodies to measure exact size and throughput
+	compressedBody   bool          // Request compression and report the compression ratio
+	outputFormat     string        // Output format: pretty, json, ndjson, csv, junit, influx, summary, template
+	maxLatency       time.Duration // Latency SLA; slower responses count as failures
+	forceHTTP1       bool          // Force HTTP/1.1
+	forceHTTP2       bool          // Force HTTP/2
+	forceHTTP3       bool          // Force HTTP/3 (QUIC)
+	forceIPv4        bool          // Connect over IPv4 only
+	forceIPv6        bool          // Connect over IPv6 only
+	resolveHosts     []string      // Addresses to connect to instead of resolving ("host:port:address")
+	dnsServer        string        // DNS server to resolve hosts with
+	sourceInterface  string        // Network interface to send requests from
+	localAddr        string        // Source IP address to send requests from
+	expectHeaders    []string      // Expected response headers ("Key: Value")
+	userCredentials  string        // Credentials for HTTP auth ("user:password")
+	digestAuth       bool          // Use Digest instead of Basic auth
+	cookies          []string      // Cookies to send ("name=value")
+	cookieJarFile    string        // Netscape-format cookie file to load and save
+	queryParams      []string      // Query parameters to append ("key=value")
+	formFields       []string      // Multipart form fields ("name=value" or "name=@file")
+	userAgent        string        // User-Agent to send instead of tapr/<version>
+	statsdAddr       string        // StatsD server to send metrics to (host:port)
+	statsdPrefix     string        // Prefix for StatsD metric names
+	statsdTags       []string      // Global DogStatsD tags ("key:value")
+	dogStatsD        bool          // Send DogStatsD tags
+	influxURL        string        // InfluxDB/Telegraf line protocol write URL
+	influxToken      string        // InfluxDB API token
+	includeBody      int           // Print the first N bytes of the response body
+	outputBodyFile   string        // Save the response body to this file
+	jqExpr           string        // jq-style path to extract from the JSON body
+	jsonPathExpr     string        // JSONPath expression to extract from the JSON body
+	expectValue      string        // Expected value of the extracted JSON field
 )
 
-// Latency thresholds for color-coding responses
-const (
-	fastThreshold = 200 * time.Millisecond // Green: fast response
-	slowThreshold = 500 * time.Millisecond // Red: slow response
+// Latency thresholds for color-coding responses (--fast-threshold,
+// --slow-threshold or the user config)
+var (
+	fastThreshold = 200 * time.Millisecond     // Green: fast response
+	slowThreshold = stats.DefaultSlowThreshold // Red: slow response, counted as slow in batch summaries
 )
 
 // Exit codes for CI/CD integration
@@ -87,7 +164,8 @@ Perfect for:
 	Example: `  tapr https://api.example.com/health
   tapr https://api.example.com/users -t 5s -v
   tapr https://api.example.com/orders -X POST -r 3
-  tapr https://api.example.com -H "Authorization: Bearer token123"`,
+  tapr https://api.example.com -H "Authorization: Bearer token123"
+  tapr @prod-health`,
 	Args:    cobra.ExactArgs(1), // Require exactly one URL argument
 	Run:     runPing,            // Execute the ping command
 	Version: Version,
@@ -95,10 +173,11 @@ Perfect for:
 
 // watchCmd represents the watch command for continuous monitoring
 var watchCmd = &cobra.Command{
-	Use:   "watch [url]",
-	Short: "Continuously monitor an endpoint",
+	Use:   "watch [url...]",
+	Short: "Continuously monitor one or more endpoints",
 	Long: `Watch mode continuously pings an endpoint at regular intervals,
-displaying live statistics and recent request history.
+displaying live statistics and recent request history. Several URLs are
+watched concurrently in one combined table.
 
 Perfect for:
   • Monitoring API health over time
@@ -107,9 +186,11 @@ Perfect for:
   • Real-time latency tracking`,
 	Example: `  tapr watch https://api.example.com/health
   tapr watch https://api.example.com/health --interval 5s
-  tapr watch https://api.example.com/health --count 20 -v`,
-	Args: cobra.ExactArgs(1),
-	Run:  runWatch,
+  tapr watch https://api.example.com/health --count 20 -v
+  tapr watch @prod-health
+  tapr watch https://api.example.com/health https://auth.example.com/health
+  tapr watch --from-file urls.txt`,
+	Run: runWatch,
 }
 
 // batchCmd represents the batch command for testing multiple endpoints
@@ -126,7 +207,8 @@ Perfect for:
   • Pre-deployment validation`,
 	Example: `  tapr batch endpoints.yml
   tapr batch endpoints.yml --concurrency 10
-  tapr batch endpoints.yml -v`,
+  tapr batch endpoints.yml -v
+  tapr batch endpoints.yml --remotes`,
 	Args: cobra.ExactArgs(1),
 	Run:  runBatch,
 }
@@ -146,7 +228,10 @@ Perfect for:
   • Optimizing API performance`,
 	Example: `  tapr trace https://api.example.com/health
   tapr trace https://api.example.com/users -v
-  tapr trace https://api.example.com/data -H "Authorization: Bearer token"`,
+  tapr trace https://api.example.com/data -H "Authorization: Bearer token"
+  tapr trace @prod-health
+  tapr trace https://api.example.com/health --runs 10 --warmup 2
+  tapr trace https://api.example.com/health --compare-reuse`,
 	Args: cobra.ExactArgs(1),
 	Run:  runTrace,
 }
@@ -157,7 +242,7 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version number of Tapr",
 	Long:  "Print the version number of Tapr",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Tapr version %s\n", Version)
+		output.Printf("Tapr version %s\n", Version)
 	},
 }
 
@@ -174,6 +259,28 @@ func init() {
 	// add trace command to root
 	rootCmd.AddCommand(traceCmd)
 
+	// Trace-specific flags
+	traceCmd.Flags().IntVar(
+		&traceRuns,
+		"runs",
+		1,
+		"Number of traced requests; reports per-phase min/avg/p95/max when > 1",
+	)
+
+	traceCmd.Flags().IntVar(
+		&traceWarmup,
+		"warmup",
+		0,
+		"Requests to send and discard before measuring",
+	)
+
+	traceCmd.Flags().BoolVar(
+		&traceReuse,
+		"compare-reuse",
+		false,
+		"Trace a request on a new connection and one on a reused keep-alive connection",
+	)
+
 	// Watch-specific flags
 	watchCmd.Flags().DurationVarP(
 		&watchInterval,
@@ -191,6 +298,83 @@ func init() {
 		"Number of requests (0 = infinite)",
 	)
 
+	watchCmd.Flags().BoolVar(
+		&watchKeepAlive,
+		"keep-alive",
+		false,
+		"Reuse the connection between requests and compare warm vs cold latency",
+	)
+
+	watchCmd.Flags().StringVar(
+		&watchAlerts,
+		"alerts",
+		"",
+		"YAML file with an alerts section (Slack, webhooks, email) to notify on failures",
+	)
+
+	watchCmd.Flags().BoolVar(
+		&watchLogHist,
+		"log-histogram",
+		false,
+		"Use log-scale buckets for the latency histogram in the summary",
+	)
+
+	watchCmd.Flags().DurationVar(
+		&watchWindow,
+		"window",
+		0,
+		"Also report success rate and latency over this trailing window (e.g. 1m)",
+	)
+
+	watchCmd.Flags().StringVar(
+		&watchLogFile,
+		"log-file",
+		"",
+		"Append each check to this file as a JSON line (time, status, latency, error)",
+	)
+
+	watchCmd.Flags().StringVar(
+		&watchResume,
+		"resume",
+		"",
+		"Save statistics to this session file and continue from it on the next run",
+	)
+
+	watchCmd.Flags().IntVar(
+		&watchBreaker,
+		"breaker",
+		0,
+		"Back off the interval after this many consecutive failures, probing until the endpoint recovers (0 = off)",
+	)
+
+	watchCmd.Flags().DurationVar(
+		&watchBreakerMax,
+		"breaker-max-interval",
+		breaker.DefaultMaxInterval,
+		"Longest interval between probes while the circuit is open",
+	)
+
+	watchCmd.Flags().StringVar(
+		&watchFromFile,
+		"from-file",
+		"",
+		"Also watch the URLs listed in this file, one per line",
+	)
+
+	watchCmd.Flags().Float64Var(
+		&watchSLO,
+		"slo",
+		0,
+		"Availability target in percent (e.g. 99.9); the summary reports the error budget left",
+	)
+
+	watchCmd.Flags().Float64Var(
+		&watchAnomaly,
+		"anomaly-factor",
+		stats.DefaultAnomalyFactor,
+		"Flag checks this many deviations slower than the learned baseline latency as anomalies (0 = off)",
+	)
+
 	// Timeout flag: -t or --timeout
 	rootCmd.Flags().DurationVarP(
 		&timeout,
@@ -200,6 +384,36 @@ func init() {
 		"Maximum time to wait for response",
 	)
 
+	// Streaming timeouts: --first-byte-timeout / --idle-timeout
+	rootCmd.Flags().DurationVar(
+		&firstByteTimeout,
+		"first-byte-timeout",
+		0,
+		"Maximum time to wait for the first response byte (drops the default --timeout)",
+	)
+
+	rootCmd.Flags().DurationVar(
+		&idleTimeout,
+		"idle-timeout",
+		0,
+		"Maximum pause while reading the response body (drops the default --timeout)",
+	)
+
+	// Phase timeouts: --connect-timeout / --response-timeout
+	rootCmd.Flags().DurationVar(
+		&connectTimeout,
+		"connect-timeout",
+		0,
+		"Maximum time to connect: DNS, TCP and the TLS handshake together (default 30s, within --timeout)",
+	)
+
+	rootCmd.Flags().DurationVar(
+		&responseTimeout,
+		"response-timeout",
+		0,
+		"Maximum time to wait for response headers once the request is sent",
+	)
+
 	// Method flag: -X or --method
 	rootCmd.Flags().StringVarP(
 		&method,
@@ -244,10 +458,98 @@ func init() {
 		"Number of retry attempts on failure",
 	)
 
+	rootCmd.Flags().IntSliceVar(
+		&retryOnStatus,
+		"retry-on-status",
+		[]int{},
+		"Also retry responses with these status codes (e.g., 429,502,503), honoring Retry-After",
+	)
+
+	rootCmd.Flags().DurationVar(
+		&retryMaxDelay,
+		"retry-max-delay",
+		request.DefaultRetryMaxDelay,
+		"Longest wait between retries, even if Retry-After asks for more",
+	)
+
+	// Expect header flag: --expect-header (repeatable)
+	rootCmd.Flags().StringArrayVar(
+		&expectHeaders,
+		"expect-header",
+		[]string{},
+		"Fail unless response header matches ('Key: value', 'Key: prefix:v', 'Key: regex:^v'), repeatable",
+	)
+
+	// JSON extraction flags: --jq / --jsonpath
+	rootCmd.Flags().StringVar(
+		&jqExpr,
+		"jq",
+		"",
+		"Extract and print a value from the JSON response (e.g., '.status')",
+	)
+
+	rootCmd.Flags().StringVar(
+		&jsonPathExpr,
+		"jsonpath",
+		"",
+		"Extract and print a value from the JSON response (e.g., '$.data.version')",
+	)
+
+	// Response body flags: --include-body / --output-body
+	rootCmd.Flags().IntVar(
+		&includeBody,
+		"include-body",
+		0,
+		"Print the first N bytes of the response body (default 512 when given without a value)",
+	)
+	rootCmd.Flags().Lookup("include-body").NoOptDefVal = "512"
+
+	rootCmd.Flags().StringVar(
+		&outputBodyFile,
+		"output-body",
+		"",
+		"Save the response body to this file",
+	)
+
+	rootCmd.Flags().StringVar(
+		&expectValue,
+		"expect-value",
+		"",
+		"Fail unless the value extracted with --jq/--jsonpath equals this",
+	)
+
 	// Add batch command
 	rootCmd.AddCommand(batchCmd)
 
 	// Batch-specific flags
+	batchCmd.Flags().DurationVar(
+		&firstByteTimeout,
+		"first-byte-timeout",
+		0,
+		"Default first_byte_timeout for endpoints that don't set one",
+	)
+
+	batchCmd.Flags().DurationVar(
+		&idleTimeout,
+		"idle-timeout",
+		0,
+		"Default idle_timeout for endpoints that don't set one",
+	)
+
+	batchCmd.Flags().DurationVar(
+		&connectTimeout,
+		"connect-timeout",
+		0,
+		"Default connect_timeout for endpoints that don't set one",
+	)
+
+	batchCmd.Flags().DurationVar(
+		&responseTimeout,
+		"response-timeout",
+		0,
+		"Default response_timeout for endpoints that don't set one",
+	)
+
 	batchCmd.Flags().IntVarP(
 		&batchConcurrency,
 		"concurrency",
@@ -256,6 +558,56 @@ func init() {
 		"Number of concurrent requests (0 = use config default)",
 	)
 
+	batchCmd.Flags().StringSliceVar(
+		&batchTags,
+		"tags",
+		[]string{},
+		"Only run endpoints with one of these tags (and the endpoints they depend on)",
+	)
+
+	batchCmd.Flags().StringSliceVar(
+		&batchSkipTags,
+		"skip-tags",
+		[]string{},
+		"Skip endpoints with any of these tags",
+	)
+
+	batchCmd.Flags().StringVar(
+		&batchHeadersFile,
+		"headers",
+		"",
+		"Path to YAML file with headers for every endpoint (endpoint headers win)",
+	)
+
+	batchCmd.Flags().IntVarP(
+		&retries,
+		"retries",
+		"r",
+		0,
+		"Times to retry an endpoint whose check fails (endpoint retries win)",
+	)
+
+	batchCmd.Flags().DurationVar(
+		&retryMaxDelay,
+		"retry-max-delay",
+		request.DefaultRetryMaxDelay,
+		"Longest wait between retries, even if Retry-After asks for more",
+	)
+
+	batchCmd.Flags().Float64Var(
+		&batchRPS,
+		"rps",
+		0,
+		"Maximum requests per second, including retries (0 = use config rate_limit)",
+	)
+
+	batchCmd.Flags().BoolVar(
+		&batchNoProgress,
+		"no-progress",
+		false,
+		"Don't show a progress bar while endpoints are tested",
+	)
+
 	// Batch-specific CI/CD flags
 	batchCmd.Flags().BoolVar(
 		&failFast,
@@ -271,6 +623,20 @@ func init() {
 		"Maximum time for entire batch (e.g., 5m, 30s)",
 	)
 
+	batchCmd.Flags().StringVar(
+		&reportFile,
+		"report",
+		"",
+		"Write a self-contained report of the results (.html or .md)",
+	)
+
+	batchCmd.Flags().BoolVar(
+		&emitCurl,
+		"emit-curl",
+		false,
+		"Print a curl command reproducing each failed request (secrets masked)",
+	)
+
 	// CI/CD flags (persistent - available on all commands)
 	rootCmd.PersistentFlags().BoolVarP(
 		&quiet,
@@ -292,34 +658,250 @@ func init() {
 		"output",
 		"o",
 		"pretty",
-		"Output format: pretty, json, csv",
+		"Output format: pretty, json, ndjson, csv, junit, influx, summary",
+	)
+
+	// Latency SLA flag (persistent - applies to ping, watch and batch defaults)
+	rootCmd.PersistentFlags().DurationVar(
+		&maxLatency,
+		"max-latency",
+		0,
+		"Fail requests slower than this (e.g., 300ms, 0 = no limit)",
+	)
+
+	// Wide flag (persistent - tables and boxes of every command)
+	rootCmd.PersistentFlags().BoolVar(
+		&wideOutput,
+		"wide",
+		false,
+		"Don't truncate long names and URLs to fit the terminal width",
+	)
+
+	// Latency coloring thresholds (persistent - available on all commands)
+	rootCmd.PersistentFlags().DurationVar(
+		&fastThreshold,
+		"fast-threshold",
+		fastThreshold,
+		"Latencies below this are shown as fast (green)",
+	)
+
+	rootCmd.PersistentFlags().DurationVar(
+		&slowThreshold,
+		"slow-threshold",
+		slowThreshold,
+		"Latencies above this are shown and counted as slow (red)",
+	)
+
+	// Protocol forcing flags (persistent - available on all commands)
+	rootCmd.PersistentFlags().BoolVar(
+		&forceHTTP1,
+		"http1",
+		false,
+		"Force HTTP/1.1",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&forceHTTP2,
+		"http2",
+		false,
+		"Force HTTP/2 (fails if the server does not negotiate it)",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&forceHTTP3,
+		"http3",
+		false,
+		"Force HTTP/3 over QUIC (https:// URLs only)",
+	)
+
+	// IP version flags (persistent - available on all commands)
+	rootCmd.PersistentFlags().BoolVarP(
+		&forceIPv4,
+		"ipv4",
+		"4",
+		false,
+		"Connect over IPv4 only",
+	)
+
+	rootCmd.PersistentFlags().BoolVarP(
+		&forceIPv6,
+		"ipv6",
+		"6",
+		false,
+		"Connect over IPv6 only",
+	)
+
+	// Name resolution flags (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringArrayVar(
+		&resolveHosts,
+		"resolve",
+		[]string{},
+		"Connect to this address instead of resolving the host (format: 'host:port:address'), repeatable",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&dnsServer,
+		"dns-server",
+		"",
+		"Resolve hosts with this DNS server instead of the system resolver (e.g., 1.1.1.1)",
+	)
+
+	// Source address flags (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringVar(
+		&sourceInterface,
+		"interface",
+		"",
+		"Send requests from this network interface's address (e.g., eth1)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&localAddr,
+		"local-addr",
+		"",
+		"Send requests from this source IP address (e.g., 10.0.0.5)",
+	)
+
+	// Authentication flags (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringVarP(
+		&userCredentials,
+		"user",
+		"u",
+		"",
+		"Credentials for HTTP authentication (format: 'user:password')",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&digestAuth,
+		"digest",
+		false,
+		"Use HTTP Digest authentication with --user (default: Basic)",
+	)
+
+	// Cookie flags (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringArrayVar(
+		&cookies,
+		"cookie",
+		[]string{},
+		"Send a cookie (format: 'name=value'), repeatable",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&cookieJarFile,
+		"cookie-jar",
+		"",
+		"Load cookies from and save cookies to this file (Netscape format)",
+	)
+
+	// Query parameter flag (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringArrayVar(
+		&queryParams,
+		"param",
+		[]string{},
+		"Append a URL-encoded query parameter (format: 'key=value'), repeatable",
+	)
+
+	// Metrics flags (persistent - used by watch and batch)
+	rootCmd.PersistentFlags().StringVar(
+		&statsdAddr,
+		"statsd",
+		"",
+		"Send latency and success metrics to this StatsD server (host:port)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&statsdPrefix,
+		"statsd-prefix",
+		"tapr",
+		"Prefix for StatsD metric names",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&dogStatsD,
+		"dogstatsd",
+		false,
+		"Tag StatsD metrics with endpoint, method and status (DogStatsD format)",
+	)
+
+	rootCmd.PersistentFlags().StringSliceVar(
+		&statsdTags,
+		"statsd-tag",
+		[]string{},
+		"Add a DogStatsD tag to every metric (format: 'key:value'), repeatable",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&influxURL,
+		"influx-url",
+		"",
+		"Write metrics to InfluxDB/Telegraf (e.g., http://localhost:8086/api/v2/write?org=acme&bucket=api)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&influxToken,
+		"influx-token",
+		"",
+		"InfluxDB API token (default: $INFLUX_TOKEN)",
+	)
+
+	// Download flag (persistent - available on all commands)
+	rootCmd.PersistentFlags().BoolVar(
+		&downloadBody,
+		"download",
+		false,
+		"Download the full response body to report its exact size and transfer rate",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&compressedBody,
+		"compressed",
+		false,
+		"Send Accept-Encoding (gzip, deflate, br) and report compressed vs decoded size",
+	)
+
+	// Multipart form flag (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringArrayVarP(
+		&formFields,
+		"form",
+		"F",
+		[]string{},
+		"Send a multipart form field ('name=value' or 'name=@file'), repeatable; implies POST",
+	)
+
+	// User-Agent flag (persistent - available on all commands)
+	rootCmd.PersistentFlags().StringVar(
+		&userAgent,
+		"user-agent",
+		"",
+		"Send this User-Agent instead of tapr/<version> (overrides a User-Agent header)",
 	)
 }
 
 // main is the entry point of the application.
 func main() {
+	request.DefaultUserAgent = "tapr/" + Version
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(output.Stderr, err)
 		os.Exit(1)
 	}
 }
 
 // runPing executes the ping command with the provided URL and flags.
 func runPing(cmd *cobra.Command, args []string) {
-	url := args[0]
+	url, aliasHeaders := resolveTarget(cmd, args[0])
 
 	// Validate that URL has proper HTTP/HTTPS scheme
 	if !isValidURL(url) {
-		fmt.Fprintln(os.Stderr, output.Red("Error: URL must start with http:// or https://"))
+		fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
 		os.Exit(1)
 	}
+	url = withQueryParams(url)
 
 	// Load headers from file if specified
 	var fileHeaders map[string]string
 	if headersFile != "" {
 		loadedHeaders, err := config.LoadHeaders(headersFile)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
 			os.Exit(1)
 		}
 		fileHeaders = loadedHeaders
@@ -330,113 +912,308 @@ func runPing(cmd *cobra.Command, args []string) {
 	if len(inlineHeaders) > 0 {
 		parsed, err := config.ParseInlineHeaders(inlineHeaders)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
 			os.Exit(1)
 		}
 		parsedInlineHeaders = parsed
 	}
 
-	// Merge file headers and inline headers (inline headers take precedence)
-	headers := config.MergeHeaders(fileHeaders, parsedInlineHeaders)
+	// Merge alias, file and inline headers (later sources take precedence)
+	headers := config.MergeHeaders(aliasHeaders, fileHeaders, parsedInlineHeaders)
+
+	// Parse expected response headers
+	var headerExpectations []assert.HeaderExpectation
+	if len(expectHeaders) > 0 {
+		expected, err := config.ParseInlineHeaders(expectHeaders)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing expected headers: %v", err)))
+			os.Exit(ExitError)
+		}
+		headerExpectations, err = assert.ParseHeaderExpectations(expected)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing expected headers: %v", err)))
+			os.Exit(ExitError)
+		}
+	}
+
+	// JSON extraction needs the response body
+	extractExpr := jqExpr
+	if jsonPathExpr != "" {
+		if jqExpr != "" {
+			fmt.Fprintln(output.Stderr, output.Red("Error: use either --jq or --jsonpath, not both"))
+			os.Exit(ExitError)
+		}
+		extractExpr = jsonPathExpr
+	}
+	if expectValue != "" && extractExpr == "" {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --expect-value requires --jq or --jsonpath"))
+		os.Exit(ExitError)
+	}
+	if extractExpr != "" {
+		if _, err := jsonpath.Parse(extractExpr); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+	}
+
+	if headMode && extractExpr != "" {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --head gets no body to extract a value from"))
+		os.Exit(ExitError)
+	}
+
+	// Configure the ping
+	applyMethodMode(cmd)
+	opts := requestOptions(headers)
+	policy := resolveExitPolicy()
+
+	// With streaming timeouts, only an explicit --timeout limits the
+	// whole request, so long-lived responses aren't cut off (--sse always
+	// waits at most --timeout for its events)
+	if (firstByteTimeout > 0 || idleTimeout > 0) && !sseMode && !cmd.Flags().Changed("timeout") {
+		opts.Timeout = 0
+	}
+	if dryRun {
+		dryRunPing(url, opts)
+	}
 
 	// Show request details in verbose mode
 	if verbose {
-		printRequestDetails(url, headers)
+		printRequestDetails(url, opts)
+	}
+	if sseMode {
+		runSSE(url, opts)
+	}
+	if allIPs {
+		runAllIPs(url, opts)
+	}
+	if compareStack {
+		runCompareStack(url, opts)
 	}
 
-	// Configure and execute the ping
-	opts := request.PingOptions{
-		Method:  strings.ToUpper(method),
-		Timeout: timeout,
-		Retries: retries,
-		Headers: headers,
+	// A streamed result line replaces the pretty output
+	openResultStream()
+	if resultStream != nil {
+		output.Stdout = io.Discard
 	}
+	if optionsMode {
+		runOptions(url, opts)
+	}
+
+	// Execute the ping
+	opts.ReadBody = !headMode && (extractExpr != "" || includeBody > 0 || outputBodyFile != "")
 
 	result := request.Ping(url, opts)
+	saveCookieJar(opts.Jar)
 
 	// Handle request failure
 	if result.Error != nil {
 		printError(url, result.Error)
-		os.Exit(1)
+		exitPing(url, result, ExitFailure)
 	}
 
 	// Print successful result
 	printSuccess(result)
-}
+	if headMode {
+		printResponseHeaders(result.Headers)
+	}
 
-// runWatch executes the watch command for continuous monitoring.
-// runWatch executes the watch command for continuous monitoring.
-func runWatch(cmd *cobra.Command, args []string) {
-	url := args[0]
+	// Save and show the response body
+	if outputBodyFile != "" {
+		if err := saveBody(outputBodyFile, result); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error saving body: %v", err)))
+			os.Exit(ExitError)
+		}
+		output.Printf("  Saved:    %s to %s\n", formatBytes(int64(len(result.Body))), outputBodyFile)
+	}
+	if includeBody > 0 && !headMode {
+		printBodySnippet(result.Body, includeBody)
+	}
 
-	// Validate URL
-	if !isValidURL(url) {
-		fmt.Fprintln(os.Stderr, output.Red("Error: URL must start with http:// or https://"))
-		os.Exit(1)
+	// Failing status codes under --fail-on
+	if policy.failed(result, request.Classify(result), true) {
+		output.Printf("%s Status %d matches --fail-on\n", output.Red("✗"), result.StatusCode)
+		exitPing(url, result, ExitFailure)
 	}
 
-	// Load headers (same as ping command)
-	var fileHeaders map[string]string
-	if headersFile != "" {
-		loadedHeaders, err := config.LoadHeaders(headersFile)
+	// Enforce latency SLA
+	if exceedsMaxLatency(result.Latency, maxLatency) {
+		output.Printf("%s Latency %s exceeded max %s\n", output.Red("✗"), result.Latency, maxLatency)
+		if policy.stops(request.CategorySlow) {
+			exitPing(url, result, ExitFailure)
+		}
+	}
+
+	// Enforce header assertions
+	if err := assert.CheckHeaders(result.Headers, headerExpectations); err != nil {
+		output.Printf("%s %s\n", output.Red("✗"), capitalize(err.Error()))
+		if policy.stops(request.CategoryAssertion) {
+			exitPing(url, result, ExitFailure)
+		}
+	}
+
+	// Extract (and optionally assert) a JSON value
+	if extractExpr != "" {
+		value, err := jsonpath.Extract(result.Body, extractExpr)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
-			os.Exit(1)
+			output.Printf("%s Extract %s: %v\n", output.Red("✗"), extractExpr, err)
+			if policy.stops(request.CategoryAssertion) {
+				exitPing(url, result, ExitFailure)
+			}
+			exitPing(url, result, ExitSuccess)
+		}
+
+		formatted := jsonpath.Format(value)
+		output.Printf("  Value:    %s = %s\n", extractExpr, output.Cyan(formatted))
+
+		if expectValue != "" && formatted != expectValue {
+			output.Printf("%s Expected %s = %q, got %q\n", output.Red("✗"), extractExpr, expectValue, formatted)
+			if policy.stops(request.CategoryAssertion) {
+				exitPing(url, result, ExitFailure)
+			}
 		}
-		fileHeaders = loadedHeaders
 	}
 
-	var parsedInlineHeaders map[string]string
-	if len(inlineHeaders) > 0 {
-		parsed, err := config.ParseInlineHeaders(inlineHeaders)
+	exitPing(url, result, ExitSuccess)
+}
+
+// watchSession holds the state of a running watch on a single endpoint.
+type watchSession struct {
+	url           string
+	opts          request.PingOptions
+	client        *http.Client     // Shared across iterations (keeps connections with --keep-alive)
+	tracker       *stats.Tracker   // All-time statistics
+	history       *stats.History   // Recent requests for the live view
+	window        *stats.Window    // Requests within --window, nil when off
+	ttfb          *stats.Tracker   // Time to first byte of answered requests
+	downloadBytes int64            // Body bytes received with --download
+	downloadTime  time.Duration    // Time spent receiving those bytes
+	warm          *stats.Tracker   // Latencies of requests on reused connections
+	cold          *stats.Tracker   // Latencies of requests on new connections
+	alerts        *alert.Manager   // Optional alerting (--alerts)
+	alertsSent    []alert.Event    // Alerts raised during the session
+	metrics       metrics.Sink     // Optional metrics export (--statsd)
+	log           *history.Store   // Optional per-check log (--log-file)
+	breaker       *breaker.Breaker // Optional circuit breaker (--breaker)
+	uptime        stats.Uptime     // Up and down periods of this run
+	schedule      watchSchedule    // Interval, jitter and adaptive checking
+	failing       bool             // Whether the last request failed
+	health        stats.Hysteresis // Debounced up/down state and flapping, with the --alerts thresholds
+	policy        exitPolicy       // Exit policy (--fail-on, --success-threshold)
+	failures      int              // Requests failed under the exit policy
+	requestCount  int
+	started       time.Time     // When the session first started (earlier with --resume)
+	elapsed       time.Duration // Time watched in earlier runs (--resume)
+	savedAt       time.Time     // Last time the session state was saved
+
+	// Latency anomalies (--anomaly-factor)
+	anomalies *stats.AnomalyDetector // nil when off
+	anomaly   *stats.Anomaly         // Of the last check, if it was one
+}
+
+// runWatch executes the watch command for continuous monitoring.
+func runWatch(cmd *cobra.Command, args []string) {
+	// Targets from the command line and --from-file
+	targets := args
+	if watchFromFile != "" {
+		loaded, err := config.LoadURLList(watchFromFile)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
-			os.Exit(1)
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
 		}
-		parsedInlineHeaders = parsed
+		targets = append(targets, loaded...)
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: watch needs a URL or --from-file"))
+		os.Exit(ExitError)
+	}
+
+	if watchSLO < 0 || watchSLO >= 100 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --slo must be between 0 and 100 (e.g. 99.9)"))
+		os.Exit(ExitError)
 	}
+	checkBaselineFlags()
 
-	headers := config.MergeHeaders(fileHeaders, parsedInlineHeaders)
+	// Load headers (same as ping command)
+	fileHeaders, parsedInlineHeaders := loadHeaderFlags()
+	openResultStream()
 
-	// Print header
-	fmt.Printf("\n┌─────────────────────────────────────────────────────────────────────┐\n")
-	fmt.Printf("│ Watching: %s%s│\n", output.Blue(url), strings.Repeat(" ", 70-len(url)-11))
-	fmt.Printf("│ Interval: %v, ", watchInterval)
-	if watchCount > 0 {
-		fmt.Printf("Count: %d%s│\n", watchCount, strings.Repeat(" ", 48-len(fmt.Sprintf("%d", watchCount))))
-	} else {
-		fmt.Printf("Count: infinite%s│\n", strings.Repeat(" ", 43))
+	if len(targets) > 1 {
+		runMultiWatch(cmd, targets, fileHeaders, parsedInlineHeaders)
+		return
 	}
-	fmt.Printf("└─────────────────────────────────────────────────────────────────────┘\n")
 
-	// Initialize trackers
-	tracker := stats.NewTracker()
-	history := stats.NewHistory(10) // Keep last 10 requests
-	startTime := time.Now()
+	url, aliasHeaders := resolveTarget(cmd, targets[0])
+
+	// Validate URL
+	if !isValidURL(url) {
+		fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
+		os.Exit(1)
+	}
+	url = withQueryParams(url)
+
+	headers := config.MergeHeaders(aliasHeaders, fileHeaders, parsedInlineHeaders)
+
+	// Print header (not while streaming NDJSON)
+	if resultStream == nil {
+		count := "infinite"
+		if watchCount > 0 {
+			count = fmt.Sprintf("%d", watchCount)
+		}
+		output.Printf("\n%s", output.Box(tableWidth(),
+			"Watching: "+output.Blue(url),
+			fmt.Sprintf("Interval: %v, Count: %s", watchInterval, count)))
+	}
 
 	// Configure request options
-	opts := request.PingOptions{
-		Method:  strings.ToUpper(method),
-		Timeout: timeout,
-		Retries: retries,
-		Headers: headers,
+	opts := requestOptions(headers)
+	session := newWatchSession(url, opts)
+
+	// Continue a saved session
+	if watchResume != "" {
+		state, err := loadWatchState(watchResume, url)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		if state != nil {
+			session.restore(state)
+			if resultStream == nil {
+				output.Printf("Resuming session from %s: %d requests over %s\n",
+					state.Started.Format("2006-01-02 15:04"), state.Requests, state.Elapsed.Round(time.Second))
+			}
+		}
 	}
+	startCount := session.requestCount
 
-	// Setup signal handling for Ctrl+C
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	session.alerts = openWatchAlerts()
+	if session.alerts != nil {
+		session.health = session.alerts.Hysteresis()
+	}
+	session.metrics = openMetrics()
+	session.log = openWatchLog()
+	startTime := time.Now()
+	session.savedAt = startTime
 
-	// Request counter
-	requestCount := 0
+	// Ctrl+C cancels the request in flight, including a wait between retries
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Create ticker for periodic requests
 	ticker := time.NewTicker(watchInterval)
 	defer ticker.Stop()
 
+	// Live stats, unless results are streamed as NDJSON
+	refresh := func() {
+		if resultStream == nil {
+			displayWatchStats(session)
+		}
+	}
+
 	// Make first request immediately
-	makeWatchRequest(url, opts, tracker, history)
-	requestCount++
-	displayWatchStats(tracker, history)
+	makeWatchRequest(ctx, session)
+	refresh()
+	session.checkpoint(startTime)
+	ticker.Reset(session.schedule.next(session))
 
 	// Channel to signal when to stop
 	done := make(chan bool)
@@ -446,16 +1223,20 @@ func runWatch(cmd *cobra.Command, args []string) {
 		for {
 			select {
 			case <-ticker.C:
-				makeWatchRequest(url, opts, tracker, history)
-				requestCount++
-				displayWatchStats(tracker, history)
+				if !makeWatchRequest(ctx, session) {
+					done <- true
+					return
+				}
+				refresh()
+				session.checkpoint(startTime)
+				ticker.Reset(session.schedule.next(session))
 
-				// Stop if we've reached the count limit
-				if watchCount > 0 && requestCount >= watchCount {
+				// Stop if we've reached this run's count limit
+				if watchCount > 0 && session.requestCount-startCount >= watchCount {
 					done <- true
 					return
 				}
-			case <-sigChan:
+			case <-ctx.Done():
 				// Ctrl+C pressed
 				done <- true
 				return
@@ -466,41 +1247,296 @@ func runWatch(cmd *cobra.Command, args []string) {
 	// Wait for completion
 	<-done
 
-	// Calculate total duration
-	totalDuration := time.Since(startTime)
+	// Calculate total duration, including earlier runs of a resumed session
+	totalDuration := session.elapsed + time.Since(startTime)
+
+	saveCookieJar(opts.Jar)
+	if watchResume != "" {
+		if err := session.saveState(watchResume, time.Since(startTime)); err != nil {
+			slog.Warn(err.Error())
+		}
+	}
+
+	// Let alerts in flight finish sending
+	if session.alerts != nil {
+		session.alerts.Wait()
+	}
+	closeMetrics(session.metrics)
+	if session.log != nil {
+		session.log.Close()
+	}
 
 	// Display final summary
-	displayWatchSummary(url, tracker, history, totalDuration, requestCount)
+	if resultStream == nil {
+		displayWatchSummary(session, totalDuration)
+	}
+
+	// Watch only sets a failure exit code under an explicit policy
+	code := ExitSuccess
+	if session.policy.active() {
+		code = session.policy.exitCode(session.requestCount, session.failures)
+		if code != ExitSuccess && resultStream == nil {
+			output.Printf("%s\n", output.Red(fmt.Sprintf("✗ Exit policy not met: %d of %d requests failed", session.failures, session.requestCount)))
+		}
+	}
+	summary := output.TrackerSummary(session.tracker)
+	summary.Availability = output.UptimeSummary(&session.uptime, watchSLO, time.Now())
+	summary.Flaps = session.health.Flaps()
+	summary.Flapping = session.health.Flapping()
+	if session.anomalies != nil {
+		summary.Anomalies = session.anomalies.Anomalies()
+	}
+	code = gateBaseline("watch", url, summary, code)
+	writeSummary("watch", url, summary, code)
+	os.Exit(code)
 }
 
-// makeWatchRequest makes a single request and updates trackers.
-func makeWatchRequest(url string, opts request.PingOptions, tracker *stats.Tracker, history *stats.History) {
-	result := request.Ping(url, opts)
+// loadHeaderFlags loads the headers given with --headers and -H.
+func loadHeaderFlags() (fileHeaders, parsedInlineHeaders map[string]string) {
+	if headersFile != "" {
+		loadedHeaders, err := config.LoadHeaders(headersFile)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
+			os.Exit(1)
+		}
+		fileHeaders = loadedHeaders
+	}
+
+	if len(inlineHeaders) > 0 {
+		parsed, err := config.ParseInlineHeaders(inlineHeaders)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
+			os.Exit(1)
+		}
+		parsedInlineHeaders = parsed
+	}
+
+	return fileHeaders, parsedInlineHeaders
+}
+
+// newWatchSession sets up the client, trackers and per-endpoint options of
+// a watch on url.
+func newWatchSession(url string, opts request.PingOptions) *watchSession {
+	opts.DisableKeepAlive = !watchKeepAlive
+
+	// Always keep cookies between iterations, like a browser session
+	if opts.Jar == nil {
+		opts.Jar = request.NewCookieJar()
+	}
+
+	// One client for the whole session so --keep-alive can reuse connections
+	client, err := request.NewClient(opts)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	session := &watchSession{
+		url:     url,
+		opts:    opts,
+		client:  client,
+		tracker: stats.NewTracker(),
+		history: stats.NewHistory(10), // Keep last 10 requests
+		ttfb:    stats.NewTracker(),
+		warm:    stats.NewTracker(),
+		cold:    stats.NewTracker(),
+	}
+	if watchWindow > 0 {
+		session.window = stats.NewWindow(watchWindow)
+	}
+	session.policy = resolveExitPolicy()
+	session.schedule = resolveWatchSchedule()
+	session.started = time.Now()
+	if watchBreaker > 0 {
+		session.breaker = breaker.New(watchBreaker, watchBreakerMax)
+	}
+	if watchAnomaly > 0 {
+		session.anomalies = &stats.AnomalyDetector{Factor: watchAnomaly}
+	}
+	return session
+}
+
+// openWatchAlerts loads the --alerts config, or returns nil without one.
+func openWatchAlerts() *alert.Manager {
+	if watchAlerts == "" {
+		return nil
+	}
+	alertConfig, err := config.LoadAlertConfig(watchAlerts)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading alerts: %v", err)))
+		os.Exit(ExitError)
+	}
+	return alert.NewManager(alertConfig, logAlertError)
+}
+
+// openWatchLog opens the --log-file store, or returns nil without one.
+func openWatchLog() *history.Store {
+	if watchLogFile == "" {
+		return nil
+	}
+	store, err := history.Open(watchLogFile)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	return store
+}
 
-	success := result.Error == nil
-	tracker.Record(result.Latency, success)
-	history.Add(result)
+// makeWatchRequest makes a single request and updates trackers. It reports
+// false, recording nothing, if ctx was canceled while the request ran.
+func makeWatchRequest(ctx context.Context, session *watchSession) bool {
+	result := request.PingWithClientContext(ctx, session.client, session.url, session.opts)
+	if ctx.Err() != nil {
+		return false
+	}
+	recordWatchResult(session, result)
+	return true
+}
+
+// recordWatchResult updates the session's trackers, log, metrics and alerts
+// with the result of one request.
+func recordWatchResult(session *watchSession, result request.Result) {
+	session.requestCount++
+
+	success := result.Error == nil && !exceedsMaxLatency(result.Latency, maxLatency)
+	session.tracker.Record(result.Latency, success)
+	session.tracker.RecordStatus(stats.StatusLabel(result))
+	var category string
+	if result.Error != nil {
+		category = request.ClassifyError(result.Error)
+	} else if !success {
+		category = request.CategorySlow
+	}
+	if category != "" {
+		session.tracker.RecordError(category)
+	}
+	if session.policy.failed(result, category, success) {
+		session.failures++
+	}
+	session.history.Add(result)
+	session.failing = !success
+	session.uptime.Observe(success, time.Now())
+	session.health.Observe(success)
+	session.anomaly = nil
+	if session.anomalies != nil && success {
+		if anomaly, anomalous := session.anomalies.Observe(result.Latency); anomalous {
+			session.anomaly = &anomaly
+		}
+	}
+	if session.breaker != nil {
+		session.breaker.Observe(success, time.Now())
+	}
+	if session.window != nil {
+		session.window.Record(time.Now(), result.Latency, success)
+	}
+
+	if result.TTFB > 0 {
+		session.ttfb.Record(result.TTFB, true)
+	}
+	if bytes := downloadedBytes(result); bytes > 0 {
+		session.downloadBytes += bytes
+		session.downloadTime += result.Download
+	}
+
+	// Split latencies by connection state for warm vs cold comparison
+	if result.Error == nil {
+		if result.ConnReused {
+			session.warm.Record(result.Latency, success)
+		} else {
+			session.cold.Record(result.Latency, success)
+		}
+	}
+
+	// Export metrics
+	if session.metrics != nil {
+		session.metrics.Record(metrics.Sample{
+			Mode:    "watch",
+			Name:    session.url,
+			URL:     session.url,
+			Method:  session.opts.Method,
+			Status:  result.StatusCode,
+			Latency: result.Latency,
+			Success: success,
+			Bytes:   downloadedBytes(result),
+			Rate:    result.Throughput(),
+			Time:    time.Now(),
+		})
+	}
+
+	var message string
+	if result.Error != nil {
+		message = result.Error.Error()
+	} else if !success {
+		message = fmt.Sprintf("latency %s exceeded max %s", result.Latency.Round(time.Millisecond), maxLatency)
+	}
+
+	streamResult("watch", stats.BatchResult{
+		Name:     session.url,
+		URL:      session.url,
+		Method:   session.opts.Method,
+		Result:   result,
+		Success:  success,
+		Message:  message,
+		Category: category,
+	})
+
+	// Log the check; a failing log must not stop the watch
+	if session.log != nil {
+		err := session.log.Append(history.Record{
+			Time:      time.Now(),
+			Name:      session.url,
+			URL:       session.url,
+			Status:    result.StatusCode,
+			LatencyMs: float64(result.Latency.Microseconds()) / 1000,
+			Success:   success,
+			Message:   message,
+		})
+		if err != nil {
+			slog.Warn(err.Error()+", logging stopped", "file", session.log.Path())
+			session.log.Close()
+			session.log = nil
+		}
+	}
+
+	// Notify on state changes
+	if session.alerts != nil {
+		check := alert.Check{
+			Name:    session.url,
+			URL:     session.url,
+			Success: success,
+			Status:  result.StatusCode,
+			Latency: result.Latency,
+			Message: message,
+		}
+		session.alertsSent = append(session.alertsSent, session.alerts.Observe(check)...)
+	}
+}
+
+// logAlertError reports a notification that could not be delivered.
+func logAlertError(notifier string, err error) {
+	slog.Warn("alert failed", "notifier", notifier, "error", err)
 }
 
 // displayWatchSummary shows a comprehensive summary when watch mode ends.
-func displayWatchSummary(url string, tracker *stats.Tracker, history *stats.History, duration time.Duration, requestCount int) {
+func displayWatchSummary(session *watchSession, duration time.Duration) {
+	url := session.url
+	tracker := session.tracker
+	requestCount := session.requestCount
+
 	// Clear screen one last time
-	fmt.Print("\033[H\033[2J")
+	output.ClearScreen()
 
-	fmt.Printf("\n")
-	fmt.Printf("┌─────────────────────────────────────────────────────────────────────┐\n")
-	fmt.Printf("│ %s Watch Summary%s │\n", output.Blue("📋"), strings.Repeat(" ", 52))
-	fmt.Printf("└─────────────────────────────────────────────────────────────────────┘\n")
+	output.Printf("\n%s", output.Box(tableWidth(), output.Blue("📋")+" Watch Summary"))
 
 	// Endpoint info
-	fmt.Printf("🎯 Endpoint\n")
-	fmt.Printf("   URL:      %s\n", url)
-	fmt.Printf("   Method:   %s\n", method)
-	fmt.Printf("   Duration: %s\n", duration.Round(time.Second))
-	fmt.Printf("   Requests: %d\n", requestCount)
+	output.Printf("🎯 Endpoint\n")
+	output.Printf("   URL:      %s\n", url)
+	output.Printf("   Method:   %s\n", method)
+	output.Printf("   Duration: %s\n", duration.Round(time.Second))
+	output.Printf("   Requests: %d\n", requestCount)
 
 	// Success/Failure stats
-	fmt.Printf("📊 Results\n")
+	output.Printf("📊 Results\n")
 	successRate := tracker.SuccessRate()
 
 	var rateColor func(string) string
@@ -516,68 +1552,384 @@ func displayWatchSummary(url string, tracker *stats.Tracker, history *stats.Hist
 		rateEmoji = "✗"
 	}
 
-	fmt.Printf("   Success Rate:  %s %s (%d/%d)\n",
+	output.Printf("   Success Rate:  %s %s (%d/%d)\n",
 		rateEmoji,
 		rateColor(fmt.Sprintf("%.1f%%", successRate)),
 		tracker.Successful,
 		tracker.Total)
-	fmt.Printf("   Successful:    %s\n", output.Green(fmt.Sprintf("%d", tracker.Successful)))
-	fmt.Printf("   Failed:        %s\n", output.Red(fmt.Sprintf("%d", tracker.Failed)))
-	fmt.Println()
+	output.Printf("   Successful:    %s\n", output.Green(fmt.Sprintf("%d", tracker.Successful)))
+	output.Printf("   Failed:        %s%s\n", output.Red(fmt.Sprintf("%d", tracker.Failed)), formatFailures(tracker.Errors))
+	if session.breaker != nil && session.breaker.Opens() > 0 {
+		output.Printf("   Circuit:       opened %d time(s), %s down\n",
+			session.breaker.Opens(), session.breaker.Downtime(time.Now()).Round(time.Second))
+	}
+	if flaps := session.health.Flaps(); flaps > 0 {
+		flapping := fmt.Sprintf("flapped %d time(s)", flaps)
+		if session.health.Flapping() {
+			flapping += ", still flapping"
+		}
+		output.Printf("   Flapping:      %s\n", output.Yellow(flapping))
+	}
+	output.Println()
+
+	// Status code breakdown
+	if tracker.Total > 0 {
+		displayStatusBreakdown(tracker.Statuses, tracker.Total)
+		output.Println()
+	}
+
+	// Availability over time
+	if tracker.Total > 0 {
+		displayAvailability(&session.uptime, time.Now())
+		output.Println()
+	}
 
 	// Latency statistics
 	if tracker.Total > 0 {
-		fmt.Printf("⚡ Performance\n")
-		fmt.Printf("   Min Latency:   %s\n", output.Cyan(tracker.MinLatency.String()))
-		fmt.Printf("   Max Latency:   %s\n", output.Red(tracker.MaxLatency.String()))
-		fmt.Printf("   Avg Latency:   %s\n", formatLatency(tracker.AvgLatency()))
+		output.Printf("⚡ Performance\n")
+		output.Printf("   Min Latency:   %s\n", output.Cyan(tracker.MinLatency.String()))
+		output.Printf("   Max Latency:   %s\n", output.Red(tracker.MaxLatency.String()))
+		output.Printf("   Avg Latency:   %s\n", formatLatency(tracker.AvgLatency()))
+		if session.ttfb.Total > 0 {
+			output.Printf("   Avg TTFB:      %s\n", session.ttfb.AvgLatency().Round(time.Microsecond))
+		}
+		if session.downloadTime > 0 {
+			output.Printf("   Transfer Rate: %s (%s downloaded)\n",
+				formatThroughput(float64(session.downloadBytes)/session.downloadTime.Seconds()),
+				formatBytes(session.downloadBytes))
+		}
+
+		if tracker.Total >= 2 {
+			output.Printf("   P50 Latency:   %s\n", tracker.Percentile(0.50).String())
+			output.Printf("   P95 Latency:   %s\n", tracker.Percentile(0.95).String())
+			output.Printf("   P99 Latency:   %s\n", tracker.Percentile(0.99).String())
+		}
+
+		// Calculate standard deviation for consistency
+		stdDev := tracker.StdDev()
+		output.Printf("   Std Dev:       %s", stdDev.String())
+
+		if stdDev < 50*time.Millisecond {
+			output.Printf(" %s\n", output.Green("(very consistent)"))
+		} else if stdDev < 200*time.Millisecond {
+			output.Printf(" %s\n", output.Yellow("(moderate variance)"))
+		} else {
+			output.Printf(" %s\n", output.Red("(high variance)"))
+		}
+		output.Println()
+	}
+
+	// Latency distribution, to show bimodality and tails
+	if tracker.Total >= 2 {
+		displayLatencyHistogram(tracker.Latencies, watchLogHist)
+	}
+
+	// Recent stats vs all-time (--window)
+	if session.window != nil {
+		recent := session.window.Stats(time.Now())
+		displayWindowStats(recent, session.window.Span)
+		if warning := windowRegression(recent, tracker); warning != "" {
+			output.Printf("   %s\n", output.Yellow(warning))
+		}
+		output.Println()
+	}
+
+	// Connection reuse comparison (keep-alive mode only)
+	if watchKeepAlive {
+		displayConnectionReuse(session.warm, session.cold)
+	}
+
+	// Alerts raised during the session
+	if len(session.alertsSent) > 0 {
+		output.Printf("🔔 Alerts\n")
+		for _, event := range session.alertsSent {
+			output.Printf("   %s  %s\n", event.Time.Format("15:04:05"), event.Summary())
+		}
+	}
+
+	// Insights section
+	output.Printf("💡 Insights\n")
+	insights := generateInsights(tracker, duration, requestCount)
+	if insight := anomalyInsight(session.anomalies); insight != "" {
+		insights = append(insights, insight)
+	}
+	for _, insight := range insights {
+		output.Printf("   %s\n", insight)
+	}
+	output.Println()
+
+	// Final message
+	if successRate == 100 {
+		output.Printf("%s\n", output.Green("✓ All requests successful! API is healthy."))
+	} else if session.health.Flapping() {
+		output.Printf("%s\n", output.Yellow("↯ API keeps flapping between up and down. It is unstable."))
+	} else if successRate >= 80 {
+		output.Printf("%s\n", output.Yellow("⚠️  Some failures detected. API may be unstable."))
+	} else {
+		output.Printf("%s\n", output.Red("✗ High failure rate. API needs attention!"))
+	}
+}
+
+// histogramBuckets is the number of bars in the latency histogram.
+const histogramBuckets = 10
+
+// histogramWidth is the length of the longest histogram bar.
+const histogramWidth = 30
+
+// displayLatencyHistogram draws a bar per latency bucket, scaled to the
+// fullest bucket.
+func displayLatencyHistogram(latencies []time.Duration, logScale bool) {
+	buckets := stats.Histogram(latencies, histogramBuckets, logScale)
+
+	peak := 0
+	for _, bucket := range buckets {
+		if bucket.Count > peak {
+			peak = bucket.Count
+		}
+	}
+
+	unit := histogramUnit(buckets)
+
+	title := "📶 Latency Distribution"
+	if logScale {
+		title += " (log scale)"
+	}
+	output.Println(title)
+	for _, bucket := range buckets {
+		width := bucket.Count * histogramWidth / peak
+		if width == 0 && bucket.Count > 0 {
+			width = 1 // Keep rare outliers visible
+		}
+		percent := float64(bucket.Count) / float64(len(latencies)) * 100
+		output.Printf("   %9s - %-9s %s %d (%.0f%%)\n",
+			bucket.Lower.Round(unit),
+			bucket.Upper.Round(unit),
+			output.Cyan(strings.Repeat("█", width)+strings.Repeat("░", histogramWidth-width)),
+			bucket.Count,
+			percent)
+	}
+	output.Println()
+}
+
+// histogramUnit picks the rounding unit for bucket edges: the largest
+// power of ten that still keeps the narrowest bucket's edges distinct.
+func histogramUnit(buckets []stats.Bucket) time.Duration {
+	narrowest := time.Duration(0)
+	for _, bucket := range buckets {
+		if width := bucket.Upper - bucket.Lower; width > 0 && (narrowest == 0 || width < narrowest) {
+			narrowest = width
+		}
+	}
+
+	unit := time.Microsecond
+	for unit*100 <= narrowest {
+		unit *= 10
+	}
+	return unit
+}
+
+// failureNames describe failure categories in summaries, as singular and
+// plural forms.
+var failureNames = map[string][2]string{
+	request.CategoryDNS:       {"DNS failure", "DNS failures"},
+	request.CategoryConnect:   {"connection error", "connection errors"},
+	request.CategoryTLS:       {"TLS error", "TLS errors"},
+	request.CategoryTimeout:   {"timeout", "timeouts"},
+	request.CategoryServer:    {"5xx response", "5xx responses"},
+	request.CategoryClient:    {"4xx response", "4xx responses"},
+	request.CategorySlow:      {"slow response", "slow responses"},
+	request.CategoryAssertion: {"failed check", "failed checks"},
+	request.CategoryOther:     {"other error", "other errors"},
+	stats.StatusSkipped:       {"skipped", "skipped"},
+}
+
+// formatFailures describes failure categories, most frequent first, as
+// " (3 timeouts, 2 DNS failures)", or "" when there were no failures.
+func formatFailures(categories map[string]int) string {
+	if len(categories) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(categories))
+	for category := range categories {
+		names = append(names, category)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if categories[names[i]] != categories[names[j]] {
+			return categories[names[i]] > categories[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, len(names))
+	for i, category := range names {
+		count := categories[category]
+		name, ok := failureNames[category]
+		if !ok {
+			name = [2]string{category, category}
+		}
+		if count == 1 {
+			parts[i] = fmt.Sprintf("%d %s", count, name[0])
+		} else {
+			parts[i] = fmt.Sprintf("%d %s", count, name[1])
+		}
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// displayStatusBreakdown prints how many requests ended with each status
+// code, timeout or error.
+func displayStatusBreakdown(statuses map[string]int, total int) {
+	output.Printf("🔢 Status Codes\n")
+	for _, status := range stats.SortStatuses(statuses) {
+		color := output.Red
+		switch {
+		case strings.HasPrefix(status.Label, "2"):
+			color = output.Green
+		case strings.HasPrefix(status.Label, "3"):
+			color = output.Cyan
+		case strings.HasPrefix(status.Label, "4"):
+			color = output.Yellow
+		case status.Label == stats.StatusSkipped:
+			color = func(text string) string { return text }
+		}
+		output.Printf("   %s %5d  (%.1f%%)\n",
+			color(fmt.Sprintf("%-8s", status.Label)),
+			status.Count,
+			float64(status.Count)/float64(total)*100)
+	}
+}
+
+// displayAvailability prints uptime, outages and, with --slo, the error
+// budget.
+func displayAvailability(uptime *stats.Uptime, now time.Time) {
+	percent := uptime.Percent(now)
+	outages := uptime.Outages()
+
+	output.Printf("🟢 Availability\n")
+	uptimeText := fmt.Sprintf("%.3f%%", percent)
+	if len(outages) == 0 {
+		output.Printf("   Uptime:        %s\n", output.Green(uptimeText))
+	} else {
+		output.Printf("   Uptime:        %s (%s down)\n", output.Yellow(uptimeText), roundSpan(uptime.Downtime(now)))
+		output.Printf("   Incidents:     %d\n", len(outages))
+		if mttr := uptime.MTTR(); mttr > 0 {
+			output.Printf("   MTTR:          %s\n", roundSpan(mttr))
+		}
+		output.Printf("   Longest:       %s\n", roundSpan(uptime.LongestOutage(now)))
+	}
+
+	if watchSLO > 0 {
+		label := fmt.Sprintf("%-15s", fmt.Sprintf("SLO %g%%:", watchSLO))
+		allowed, remaining := uptime.ErrorBudget(watchSLO, now)
+		if remaining >= 0 {
+			share := 100.0
+			if allowed > 0 {
+				share = float64(remaining) / float64(allowed) * 100
+			}
+			output.Printf("   %s%s\n", label, output.Green(fmt.Sprintf("met, %s of %s error budget left (%.0f%%)",
+				roundSpan(remaining), roundSpan(allowed), share)))
+		} else {
+			output.Printf("   %s%s\n", label, output.Red(fmt.Sprintf("breached, error budget of %s exceeded by %s",
+				roundSpan(allowed), roundSpan(-remaining))))
+		}
+	}
+}
 
-		if tracker.Total >= 2 {
-			fmt.Printf("   P50 Latency:   %s\n", tracker.Percentile(0.50).String())
-			fmt.Printf("   P95 Latency:   %s\n", tracker.Percentile(0.95).String())
-			fmt.Printf("   P99 Latency:   %s\n", tracker.Percentile(0.99).String())
-		}
+// roundSpan rounds a duration for display: to the second from a minute
+// up, and to the millisecond below.
+func roundSpan(d time.Duration) time.Duration {
+	if d >= time.Minute {
+		return d.Round(time.Second)
+	}
+	return d.Round(time.Millisecond)
+}
 
-		// Calculate standard deviation for consistency
-		stdDev := calculateStdDev(tracker.Latencies, tracker.AvgLatency())
-		fmt.Printf("   Std Dev:       %s", stdDev.String())
+// displayConnectionReuse compares latency on reused vs new connections.
+func displayConnectionReuse(warm, cold *stats.Tracker) {
+	output.Printf("🔁 Connections\n")
+	output.Printf("   Reused:        %d\n", warm.Total)
+	output.Printf("   New:           %d\n", cold.Total)
 
-		if stdDev < 50*time.Millisecond {
-			fmt.Printf(" %s\n", output.Green("(very consistent)"))
-		} else if stdDev < 200*time.Millisecond {
-			fmt.Printf(" %s\n", output.Yellow("(moderate variance)"))
-		} else {
-			fmt.Printf(" %s\n", output.Red("(high variance)"))
-		}
-		fmt.Println()
+	if warm.Total > 0 {
+		output.Printf("   Warm Avg:      %s\n", formatLatency(warm.AvgLatency()))
+	}
+	if cold.Total > 0 {
+		output.Printf("   Cold Avg:      %s\n", formatLatency(cold.AvgLatency()))
 	}
+	if warm.Total > 0 && cold.Total > 0 && cold.AvgLatency() > warm.AvgLatency() {
+		saved := cold.AvgLatency() - warm.AvgLatency()
+		output.Printf("   Setup Cost:    %s per new connection\n", output.Yellow(saved.String()))
+	}
+	output.Println()
+}
 
-	// Insights section
-	fmt.Printf("💡 Insights\n")
-	insights := generateInsights(tracker, duration, requestCount)
-	for _, insight := range insights {
-		fmt.Printf("   %s\n", insight)
+// displayWindowStats shows success rate and latency for the requests of
+// the trailing --window.
+func displayWindowStats(recent *stats.Tracker, span time.Duration) {
+	output.Printf("🕒 Last %s (%d requests)\n", formatSpan(span), recent.Total)
+	if recent.Total == 0 {
+		output.Printf("   No requests in window\n")
+		return
 	}
-	fmt.Println()
 
-	// Final message
+	successRate := recent.SuccessRate()
+	rateColor := output.Red
 	if successRate == 100 {
-		fmt.Printf("%s\n", output.Green("✓ All requests successful! API is healthy."))
+		rateColor = output.Green
 	} else if successRate >= 80 {
-		fmt.Printf("%s\n", output.Yellow("⚠️  Some failures detected. API may be unstable."))
-	} else {
-		fmt.Printf("%s\n", output.Red("✗ High failure rate. API needs attention!"))
+		rateColor = output.Yellow
+	}
+	output.Printf("   Success Rate:  %s (%d/%d)\n",
+		rateColor(fmt.Sprintf("%.1f%%", successRate)),
+		recent.Successful,
+		recent.Total)
+	output.Printf("   Avg Latency:   %s\n", formatLatency(recent.AvgLatency()))
+	if recent.Total >= 2 {
+		output.Printf("   P95 Latency:   %s\n", recent.Percentile(0.95).String())
+	}
+}
+
+// windowRegression compares recent stats with all-time stats and describes
+// a drop in success rate or a rise in p95 latency, or returns "".
+func windowRegression(recent, overall *stats.Tracker) string {
+	if recent.Total < 2 || recent.Total == overall.Total {
+		return ""
+	}
+	if recent.SuccessRate() < overall.SuccessRate()-5 {
+		return fmt.Sprintf("⚠️  Recent success rate %.1f%% is below the session's %.1f%%",
+			recent.SuccessRate(), overall.SuccessRate())
+	}
+	if recentP95, overallP95 := recent.Percentile(0.95), overall.Percentile(0.95); recentP95 > overallP95*3/2 {
+		return fmt.Sprintf("⚠️  Recent P95 %s is well above the session's %s",
+			recentP95.Round(time.Microsecond), overallP95.Round(time.Microsecond))
+	}
+	return ""
+}
+
+// formatSpan prints a duration without zero units ("1m" instead of "1m0s").
+func formatSpan(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
 	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
 }
 
 // displayWatchStats displays current statistics and recent history.
-func displayWatchStats(tracker *stats.Tracker, history *stats.History) {
+func displayWatchStats(session *watchSession) {
+	tracker := session.tracker
+	history := session.history
+
 	// Clear previous output (move cursor up)
 	// We'll implement this simply for now
-	fmt.Print("\033[H\033[2J") // Clear screen
+	output.ClearScreen()
 
 	// Display stats header
-	fmt.Printf("\n📈 Live Stats (%d requests)\n", tracker.Total)
+	output.Printf("\n📈 Live Stats (%d requests)\n", tracker.Total)
 
 	// Success rate with color
 	successRate := tracker.SuccessRate()
@@ -590,26 +1942,57 @@ func displayWatchStats(tracker *stats.Tracker, history *stats.History) {
 		rateColor = output.Red
 	}
 
-	fmt.Printf("   Success Rate:  %s (%d/%d)\n",
+	output.Printf("   Success Rate:  %s (%d/%d)\n",
 		rateColor(fmt.Sprintf("%.1f%%", successRate)),
 		tracker.Successful,
 		tracker.Total)
 
 	// Latency stats
 	if tracker.Total > 0 {
-		fmt.Printf("   Avg Latency:   %s\n", formatLatency(tracker.AvgLatency()))
-		fmt.Printf("   Min Latency:   %s\n", output.Green(tracker.MinLatency.String()))
-		fmt.Printf("   Max Latency:   %s\n", output.Red(tracker.MaxLatency.String()))
+		output.Printf("   Avg Latency:   %s\n", formatLatency(tracker.AvgLatency()))
+		output.Printf("   Min Latency:   %s\n", output.Green(tracker.MinLatency.String()))
+		output.Printf("   Max Latency:   %s\n", output.Red(tracker.MaxLatency.String()))
 
 		if tracker.Total >= 2 {
-			fmt.Printf("   P95 Latency:   %s\n", tracker.Percentile(0.95).String())
+			output.Printf("   P95 Latency:   %s\n", tracker.Percentile(0.95).String())
 		}
+		if session.ttfb.Total > 0 {
+			output.Printf("   Avg TTFB:      %s\n", session.ttfb.AvgLatency().Round(time.Microsecond))
+		}
+	}
+
+	// Circuit breaker backing off (--breaker)
+	if session.breaker != nil && session.breaker.Open() {
+		output.Printf("   %s\n", output.Red(fmt.Sprintf("Circuit open: down for %s, probing every %s",
+			time.Since(session.breaker.DownSince()).Round(time.Second),
+			session.breaker.Interval(watchInterval))))
+	}
+
+	if session.health.Flapping() {
+		output.Printf("   %s\n", output.Yellow(fmt.Sprintf("Flapping: %d state changes in the last %d checks",
+			session.health.Changes(), session.health.Checks())))
+	}
+
+	if session.anomaly != nil {
+		output.Printf("   %s\n", output.Yellow(fmt.Sprintf("Latency anomaly: %s against a %s baseline",
+			roundLatency(session.anomaly.Latency), roundLatency(session.anomaly.Baseline))))
+	}
+
+	if session.schedule.adaptive && session.failing {
+		output.Printf("   %s\n", output.Yellow(fmt.Sprintf("Failing: checking every %s until recovery",
+			session.schedule.interval(session))))
+	}
+
+	// Recent stats (--window)
+	if session.window != nil {
+		output.Println()
+		displayWindowStats(session.window.Stats(time.Now()), session.window.Span)
 	}
 
 	// Recent history with better formatting
-	fmt.Printf("\n📊 Recent Checks\n")
-	fmt.Printf("   %-8s  %-3s  %-10s  %-10s  %-25s\n", "TIME", "✓/✗", "STATUS", "LATENCY", "PERFORMANCE")
-	fmt.Printf("   %s\n", strings.Repeat("─", 65))
+	output.Printf("\n📊 Recent Checks\n")
+	output.Printf("   %-8s  %-3s  %-10s  %-10s  %-25s\n", "TIME", "✓/✗", "STATUS", "LATENCY", "PERFORMANCE")
+	output.Printf("   %s\n", strings.Repeat("─", 65))
 
 	recent := history.GetRecent(5)
 
@@ -617,7 +2000,7 @@ func displayWatchStats(tracker *stats.Tracker, history *stats.History) {
 		timestamp := entry.Timestamp.Format("15:04:05")
 
 		if entry.Result.Error != nil {
-			fmt.Printf("   %-8s  %s  %-10s  %-10s  %s\n",
+			output.Printf("   %-8s  %s  %-10s  %-10s  %s\n",
 				timestamp,
 				output.Red("✗"),
 				"Error",
@@ -627,7 +2010,12 @@ func displayWatchStats(tracker *stats.Tracker, history *stats.History) {
 			statusStr := fmt.Sprintf("%d", entry.Result.StatusCode)
 			latencyStr := entry.Result.Latency.String()
 
-			fmt.Printf("   %-8s  %s  %-10s  %-10s  %s\n",
+			// Mark requests that reused a keep-alive connection
+			if watchKeepAlive && entry.Result.ConnReused {
+				statusStr += " ♻"
+			}
+
+			output.Printf("   %-8s  %s  %-10s  %-10s  %s\n",
 				timestamp,
 				output.Green("✓"),
 				statusStr,
@@ -636,35 +2024,7 @@ func displayWatchStats(tracker *stats.Tracker, history *stats.History) {
 		}
 	}
 
-	fmt.Printf("\n%s\n", output.Blue("Press Ctrl+C to stop..."))
-}
-
-// calculateStdDev calculates the standard deviation of latencies.
-func calculateStdDev(latencies []time.Duration, avg time.Duration) time.Duration {
-	if len(latencies) == 0 {
-		return 0
-	}
-
-	var sumSquares float64
-	for _, latency := range latencies {
-		diff := float64(latency - avg)
-		sumSquares += diff * diff
-	}
-
-	variance := sumSquares / float64(len(latencies))
-	stdDev := time.Duration(int64(variance))
-
-	// Take square root approximation
-	if stdDev > 0 {
-		// Simple Newton's method for square root
-		x := float64(stdDev)
-		for i := 0; i < 10; i++ {
-			x = (x + variance/x) / 2
-		}
-		stdDev = time.Duration(int64(x))
-	}
-
-	return stdDev
+	output.Printf("\n%s\n", output.Blue("Press Ctrl+C to stop..."))
 }
 
 // generateInsights creates helpful observations about the API behavior.
@@ -686,18 +2046,18 @@ func generateInsights(tracker *stats.Tracker, duration time.Duration, requestCou
 
 		if avgLatency < 50*time.Millisecond {
 			insights = append(insights, output.Cyan("⚡ Exceptional response times (< 50ms average)"))
-		} else if avgLatency < 200*time.Millisecond {
-			insights = append(insights, output.Green("✓ Fast response times (< 200ms average)"))
-		} else if avgLatency < 500*time.Millisecond {
-			insights = append(insights, output.Yellow("⚠️  Moderate response times (200-500ms average)"))
-		} else if avgLatency < 1*time.Second {
-			insights = append(insights, output.Yellow("⚠️  Slow response times (500ms-1s average)"))
+		} else if avgLatency < fastThreshold {
+			insights = append(insights, output.Green(fmt.Sprintf("✓ Fast response times (< %s average)", fastThreshold)))
+		} else if avgLatency < slowThreshold {
+			insights = append(insights, output.Yellow(fmt.Sprintf("⚠️  Moderate response times (%s-%s average)", fastThreshold, slowThreshold)))
+		} else if avgLatency < max(time.Second, 2*slowThreshold) {
+			insights = append(insights, output.Yellow(fmt.Sprintf("⚠️  Slow response times (> %s average)", slowThreshold)))
 		} else {
-			insights = append(insights, output.Red("⚠️  Very slow response times (> 1s average)"))
+			insights = append(insights, output.Red(fmt.Sprintf("⚠️  Very slow response times (> %s average)", max(time.Second, 2*slowThreshold))))
 		}
 
 		// Variance insights
-		stdDev := calculateStdDev(tracker.Latencies, avgLatency)
+		stdDev := tracker.StdDev()
 		varianceRatio := float64(stdDev) / float64(avgLatency)
 
 		if varianceRatio < 0.2 {
@@ -726,6 +2086,21 @@ func generateInsights(tracker *stats.Tracker, duration time.Duration, requestCou
 	return insights
 }
 
+// anomalyInsight describes the latency anomalies of a watch session, or
+// returns "" if there were none (or detection is off).
+func anomalyInsight(detector *stats.AnomalyDetector) string {
+	if detector == nil || detector.Anomalies() == 0 {
+		return ""
+	}
+	worst := detector.Worst()
+	noun := "anomalies"
+	if detector.Anomalies() == 1 {
+		noun = "anomaly"
+	}
+	return output.Yellow(fmt.Sprintf("⚠️  %d latency %s - up to %s against a %s baseline",
+		detector.Anomalies(), noun, roundLatency(worst.Latency), roundLatency(worst.Baseline)))
+}
+
 // makeColoredLatencyBar creates a color-coded, well-formatted progress bar.
 func makeColoredLatencyBar(latency, maxLatency time.Duration) string {
 	if maxLatency == 0 {
@@ -759,84 +2134,184 @@ func makeColoredLatencyBar(latency, maxLatency time.Duration) string {
 	var coloredBar string
 	var badge string
 
-	if latency < blazingFastThreshold {
+	if latency < blazingFastThreshold && latency < fastThreshold {
 		// Blazing fast - use stars instead of blocks
 		filledBar := strings.Repeat("★", filled)
 		emptyBar := strings.Repeat("·", barWidth-filled)
-		coloredBar = output.Green(filledBar) + emptyBar
+		coloredBar = output.Latency(filledBar, output.LatencyFast) + emptyBar
 		badge = " ⚡"
-	} else if latency < fastThreshold {
-		// Fast - green blocks
-		filledBar := strings.Repeat("█", filled)
-		emptyBar := strings.Repeat("·", barWidth-filled)
-		coloredBar = output.Green(filledBar) + emptyBar
-		badge = ""
-	} else if latency < slowThreshold {
-		// Medium - yellow blocks
-		filledBar := strings.Repeat("█", filled)
-		emptyBar := strings.Repeat("·", barWidth-filled)
-		coloredBar = output.Yellow(filledBar) + emptyBar
-		badge = ""
 	} else {
-		// Slow - red blocks
+		// Fast, medium or slow - blocks in the color of the latency
 		filledBar := strings.Repeat("█", filled)
 		emptyBar := strings.Repeat("·", barWidth-filled)
-		coloredBar = output.Red(filledBar) + emptyBar
+		coloredBar = output.Latency(filledBar, latencyLevel(latency)) + emptyBar
 		badge = ""
 	}
 
 	return fmt.Sprintf("[%s] %3d%%%s", coloredBar, percentage, badge)
 }
 
+// expandDiscovery replaces the discover entries of a batch config with an
+// endpoint for every instance currently registered in their catalog.
+func expandDiscovery(batchConfig *config.BatchConfig) error {
+	if len(batchConfig.Discover) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return batchConfig.ExpandDiscovery(func(d config.Discovery) ([]string, error) {
+		return discovery.Lookup(ctx, d)
+	})
+}
+
 // runBatch executes the batch command to test multiple endpoints.
 func runBatch(cmd *cobra.Command, args []string) {
 	configFile := args[0]
+	summaryTarget = configFile
+	checkBaselineFlags()
 
 	// Load batch configuration
 	batchConfig, err := config.LoadBatchConfig(configFile)
 	if err != nil {
 		if !silent {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error loading batch config: %v", err)))
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading batch config: %v", err)))
+		}
+		os.Exit(ExitError)
+	}
+
+	// Add the instances found by discover entries
+	if err := expandDiscovery(batchConfig); err != nil {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		}
+		os.Exit(ExitError)
+	}
+
+	// Run only the endpoints selected by --tags and --skip-tags
+	if err := batchConfig.SelectTags(batchTags, batchSkipTags); err != nil {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
 		}
 		os.Exit(ExitError)
 	}
 
-	// Override concurrency if specified via flag
+	// Headers from --headers override the config's global headers
+	if batchHeadersFile != "" {
+		fileHeaders, err := config.LoadHeaders(batchHeadersFile)
+		if err != nil {
+			if !silent {
+				fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
+			}
+			os.Exit(ExitError)
+		}
+		batchConfig.Headers = config.MergeHeaders(batchConfig.Headers, fileHeaders)
+	}
+
+	// Validate protocol, connection and exit policy flags before starting any requests
+	resolveHTTPVersion()
+	batchPool()
+	resolveExitPolicy()
+
+	// Override concurrency and rate limit if specified via flags
 	if batchConcurrency > 0 {
 		batchConfig.Concurrency = batchConcurrency
 	}
+	if batchRPS < 0 {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red("Error: --rps must not be negative"))
+		}
+		os.Exit(ExitError)
+	}
+	if batchRPS > 0 {
+		batchConfig.RateLimit = batchRPS
+	}
+
+	if dryRun {
+		dryRunBatch(batchConfig)
+	}
+	if batchRemotes {
+		runRemoteBatch(cmd, batchConfig)
+	}
 
 	// Print header (only in normal mode)
 	if !quiet && !silent && outputFormat == "pretty" {
-		fmt.Printf("\n┌─────────────────────────────────────────────────────────────────────┐\n")
-		fmt.Printf("│ Running batch: %d endpoints (concurrency: %d)%s│\n",
-			len(batchConfig.Endpoints),
-			batchConfig.Concurrency,
-			strings.Repeat(" ", 44-len(fmt.Sprintf("%d", len(batchConfig.Endpoints)))-len(fmt.Sprintf("%d", batchConfig.Concurrency))))
-		fmt.Printf("└─────────────────────────────────────────────────────────────────────┘\n")
+		output.Printf("\n%s", output.Box(tableWidth(),
+			fmt.Sprintf("Running batch: %d endpoints (concurrency: %d)", len(batchConfig.Endpoints), batchConfig.Concurrency)))
 
-		fmt.Println("Testing endpoints... ⚡")
+		output.Println("Testing endpoints... ⚡")
 	}
 
 	// Run batch tests
 	startTime := time.Now()
-	summary := runBatchTests(batchConfig)
+	sink := openMetrics()
+	batchProgress = showBatchProgress()
+	openResultStream()
+	summary := runBatchTests(batchConfig, sink)
 	summary.TotalTime = time.Since(startTime)
+	closeMetrics(sink)
+	reportSnapshots()
+	applyMaintenance(batchConfig.Maintenance, summary)
+
+	// Write the report before displaying, since display exits
+	if reportFile != "" {
+		meta := report.Meta{
+			Title:     "Tapr Batch Report",
+			Source:    configFile,
+			Generated: time.Now(),
+		}
+		if err := report.Write(reportFile, summary, meta); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+	}
 
 	// Display results
-	displayBatchResults(summary)
+	displayBatchResults(summary, configFile)
+}
+
+// batchVariables holds template variables shared between batch endpoints.
+// Values captured from one endpoint become visible to its dependents.
+type batchVariables struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// snapshot returns a copy of the current variables.
+func (v *batchVariables) snapshot() map[string]string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	copied := make(map[string]string, len(v.values))
+	for key, value := range v.values {
+		copied[key] = value
+	}
+	return copied
+}
+
+// set stores captured variables.
+func (v *batchVariables) set(captured map[string]string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for key, value := range captured {
+		v.values[key] = value
+	}
 }
 
 // runBatchTests executes all endpoint tests concurrently with CI/CD features.
-func runBatchTests(batchConfig *config.BatchConfig) *stats.BatchSummary {
+// Endpoints with depends_on wait until their dependencies have finished and
+// are skipped if any dependency failed. Stages run one after another, and
+// once an endpoint fails, the endpoints of later stages are skipped.
+func runBatchTests(batchConfig *config.BatchConfig, sink metrics.Sink) *stats.BatchSummary {
 	summary := stats.NewBatchSummary()
+	summary.SlowThreshold = slowThreshold
 
 	// Channel to collect results
 	resultsChan := make(chan stats.BatchResult, len(batchConfig.Endpoints))
 
 	// Channel to signal stopping (for fail-fast)
 	stopChan := make(chan struct{})
-	stopped := false
+	var stopOnce sync.Once
 
 	// Semaphore to limit concurrency
 	semaphore := make(chan struct{}, batchConfig.Concurrency)
@@ -853,13 +2328,141 @@ func runBatchTests(batchConfig *config.BatchConfig) *stats.BatchSummary {
 		defer cancel()
 	}
 
-	// Launch goroutine for each endpoint
+	// Dependency tracking: each endpoint closes its channel when finished
+	finished := make(map[string]chan struct{}, len(batchConfig.Endpoints))
+	passed := make(map[string]bool, len(batchConfig.Endpoints))
+	upstream := make(map[string]string) // Root failed dependency of skipped endpoints
+	var passedMu sync.Mutex
+	for _, endpoint := range batchConfig.Endpoints {
+		if _, exists := finished[endpoint.Name]; !exists {
+			finished[endpoint.Name] = make(chan struct{})
+		}
+	}
+
+	// Stage tracking: each stage's channel closes once all of its endpoints
+	// have finished
+	stageCount := 1
+	if n := len(batchConfig.Endpoints); n > 0 {
+		stageCount = batchConfig.Endpoints[n-1].StageIndex + 1
+	}
+	stageDone := make([]chan struct{}, stageCount)
+	stageWG := make([]sync.WaitGroup, stageCount)
+	stageFailed := make([]bool, stageCount)
+	for i := range stageDone {
+		stageDone[i] = make(chan struct{})
+	}
 	for _, endpoint := range batchConfig.Endpoints {
+		stageWG[endpoint.StageIndex].Add(1)
+	}
+	for i := range stageDone {
+		go func(i int) {
+			stageWG[i].Wait()
+			close(stageDone[i])
+		}(i)
+	}
+
+	// Template variables (config variables plus captured values)
+	variables := &batchVariables{values: make(map[string]string)}
+	variables.set(batchConfig.Variables)
+
+	// Requests from all endpoints share one rate limit
+	limiter := ratelimit.New(batchConfig.RateLimit, 1)
+
+	// Cookies are shared by all endpoints so chained requests keep the session
+	jar := resolveCookieJar()
+	defer saveCookieJar(jar)
+
+	// Launch goroutine for each endpoint
+	for i, endpoint := range batchConfig.Endpoints {
 		wg.Add(1)
 
-		go func(ep config.Endpoint) {
+		// Only the first endpoint with a given name signals dependents
+		done := finished[endpoint.Name]
+		if firstIndex(batchConfig.Endpoints, endpoint.Name) != i {
+			done = nil
+		}
+
+		go func(ep config.Endpoint, done chan struct{}) {
 			defer wg.Done()
 
+			success := false
+			stageSkipped := false
+			defer func() {
+				passedMu.Lock()
+				if done != nil {
+					passed[ep.Name] = success
+				}
+				if !success && !stageSkipped {
+					stageFailed[ep.StageIndex] = true
+				}
+				passedMu.Unlock()
+				if done != nil {
+					close(done)
+				}
+				stageWG[ep.StageIndex].Done()
+			}()
+
+			// Wait for earlier stages, skipping this one if any of them failed
+			if ep.StageIndex > 0 {
+				select {
+				case <-stageDone[ep.StageIndex-1]:
+				case <-stopChan:
+					return
+				case <-ctx.Done():
+					return
+				}
+
+				passedMu.Lock()
+				failedStage := -1
+				for i := 0; i < ep.StageIndex; i++ {
+					if stageFailed[i] {
+						failedStage = i
+						break
+					}
+				}
+				passedMu.Unlock()
+
+				if failedStage >= 0 {
+					stageSkipped = true
+					resultsChan <- skippedResult(ep, fmt.Sprintf("Skipped: stage '%s' failed", stageName(batchConfig.Endpoints, failedStage)))
+					return
+				}
+			}
+
+			// Wait for dependencies
+			for _, dep := range ep.DependsOn {
+				select {
+				case <-finished[dep]:
+				case <-stopChan:
+					return
+				case <-ctx.Done():
+					return
+				}
+
+				passedMu.Lock()
+				depPassed := passed[dep]
+				passedMu.Unlock()
+
+				if !depPassed {
+					// Degraded rather than failed: report the dependency
+					// that failed first, not the one that was skipped
+					passedMu.Lock()
+					root := upstream[dep]
+					if root == "" {
+						root = dep
+					}
+					if done != nil {
+						upstream[ep.Name] = root
+					}
+					passedMu.Unlock()
+
+					result := skippedResult(ep, fmt.Sprintf("Skipped: dependency '%s' failed", dep))
+					result.Upstream = root
+					resultsChan <- result
+					return
+				}
+			}
+
 			// Check if we should stop (fail-fast triggered)
 			select {
 			case <-stopChan:
@@ -879,23 +2482,43 @@ func runBatchTests(batchConfig *config.BatchConfig) *stats.BatchSummary {
 				return
 			}
 
-			// Test the endpoint
-			result := testEndpoint(ep, batchConfig.Timeout)
+			// Add global headers, expand {{variables}} and test the endpoint
+			if len(batchConfig.Headers) > 0 {
+				ep.Headers = config.MergeHeaders(batchConfig.Headers, ep.Headers)
+			}
+			var result stats.BatchResult
+			rendered, err := ep.Render(variables.snapshot())
+			if err != nil {
+				result = errorResult(ep, err)
+			} else {
+				result = testEndpoint(ctx, rendered, batchConfig.Timeout, jar, limiter)
+			}
+
+			// Capture variables for dependent endpoints
+			if result.Success && len(ep.Capture) > 0 {
+				captured, err := captureVariables(ep.Capture, result.Result.Body)
+				if err != nil {
+					result.Success = false
+					result.Message = capitalize(err.Error())
+				} else {
+					variables.set(captured)
+				}
+			}
+			success = result.Success
 
 			// Send result
 			select {
 			case resultsChan <- result:
 				// If fail-fast is enabled and this test failed, signal stop
-				if failFast && !result.Success && !stopped {
-					stopped = true
-					close(stopChan)
+				if failFast && !result.Success {
+					stopOnce.Do(func() { close(stopChan) })
 				}
 			case <-stopChan:
 				return
 			case <-ctx.Done():
 				return
 			}
-		}(endpoint)
+		}(endpoint, done)
 	}
 
 	// Close results channel when all goroutines finish
@@ -904,66 +2527,326 @@ func runBatchTests(batchConfig *config.BatchConfig) *stats.BatchSummary {
 		close(resultsChan)
 	}()
 
+	// Draw a progress bar until all results are in
+	var progress *output.Progress
+	if batchProgress {
+		progress = output.NewProgress(output.Stderr, len(batchConfig.Endpoints))
+	}
+
 	// Collect results
 	for result := range resultsChan {
 		summary.AddResult(result)
+		if progress != nil {
+			progress.Add(result.Success)
+		}
+
+		if sink != nil {
+			sink.Record(batchSample(result, time.Now()))
+		}
+		streamResult("batch", result)
 
 		// In quiet mode, print failures immediately
 		if quiet && !silent && !result.Success {
 			if result.Result.Error != nil {
-				fmt.Fprintf(os.Stderr, "%s %s: %v\n",
+				fmt.Fprintf(output.Stderr, "%s %s: %v\n",
 					output.Red("✗"),
 					result.Name,
 					result.Result.Error)
 			} else {
-				fmt.Fprintf(os.Stderr, "%s %s: Expected %d, got %d\n",
+				fmt.Fprintf(output.Stderr, "%s %s: %s\n",
 					output.Red("✗"),
 					result.Name,
-					result.ExpectedStatus,
-					result.Result.StatusCode)
+					result.Message)
+			}
+			if emitCurl && wasSent(result) {
+				fmt.Fprintf(output.Stderr, "  %s\n", strings.ReplaceAll(output.FormatCurl(result), "\n", "\n  "))
 			}
 		}
 	}
 
+	if progress != nil {
+		progress.Stop()
+	}
+
 	// Check if we hit timeout
 	if ctx.Err() == context.DeadlineExceeded {
 		if !silent {
-			fmt.Fprintf(os.Stderr, "%s Batch exceeded max-time limit (%v)\n",
+			fmt.Fprintf(output.Stderr, "%s Batch exceeded max-time limit (%v)\n",
 				output.Yellow("⏱️"), maxTime)
 		}
 	}
 
+	batchPool().CloseIdleConnections()
 	return summary
 }
 
-// testEndpoint tests a single endpoint and returns the result.
-func testEndpoint(endpoint config.Endpoint, defaultTimeout time.Duration) stats.BatchResult {
-	// Use endpoint-specific timeout or default
+// tableWidth returns the width tables and boxes are fitted into: the
+// terminal width, or 0 (unlimited) with --wide or when stdout is not a
+// terminal.
+func tableWidth() int {
+	if wideOutput {
+		return 0
+	}
+	return output.TerminalWidth()
+}
+
+// showBatchProgress reports whether to draw a progress bar while a batch
+// runs: only in pretty output on a terminal, and not with --quiet, --silent
+// or --trace-http, which print to stderr as results come in.
+func showBatchProgress() bool {
+	return !batchNoProgress && !quiet && !silent && !traceHTTP &&
+		outputFormat == "pretty" && output.IsTerminal(os.Stderr) && !output.LegacyConsole()
+}
+
+// stageName returns the name of the stage at the given index.
+func stageName(endpoints []config.Endpoint, index int) string {
+	for _, endpoint := range endpoints {
+		if endpoint.StageIndex == index {
+			return endpoint.Stage
+		}
+	}
+	return ""
+}
+
+// firstIndex returns the index of the first endpoint with the given name.
+func firstIndex(endpoints []config.Endpoint, name string) int {
+	for i, endpoint := range endpoints {
+		if endpoint.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// skippedResult builds a failed result for an endpoint that was not requested.
+func skippedResult(endpoint config.Endpoint, message string) stats.BatchResult {
+	return stats.BatchResult{
+		Name:           endpoint.Name,
+		URL:            endpoint.URL,
+		Method:         endpoint.Method,
+		ExpectedStatus: endpoint.ExpectedStatus,
+		Success:        false,
+		Skipped:        true,
+		Message:        message,
+		Tags:           endpoint.Tags,
+		Stage:          endpoint.Stage,
+		DependsOn:      endpoint.DependsOn,
+	}
+}
+
+// errorResult builds a failed result for an endpoint whose request could
+// not be built, such as one with a template that doesn't render. Unlike a
+// skipped endpoint, it counts as an error, under --fail-on too.
+func errorResult(endpoint config.Endpoint, err error) stats.BatchResult {
+	return stats.BatchResult{
+		Name:           endpoint.Name,
+		URL:            endpoint.URL,
+		Method:         endpoint.Method,
+		Result:         request.Result{URL: endpoint.URL, Error: unbuiltError{err}},
+		ExpectedStatus: endpoint.ExpectedStatus,
+		Success:        false,
+		Message:        capitalize(err.Error()),
+		Tags:           endpoint.Tags,
+		Stage:          endpoint.Stage,
+		DependsOn:      endpoint.DependsOn,
+	}
+}
+
+// unbuiltError is the error of a request that could not be built, and so
+// was never sent.
+type unbuiltError struct {
+	err error
+}
+
+func (e unbuiltError) Error() string { return e.err.Error() }
+func (e unbuiltError) Unwrap() error { return e.err }
+
+// captureVariables extracts named values from a JSON response body.
+func captureVariables(capture map[string]string, body []byte) (map[string]string, error) {
+	captured := make(map[string]string, len(capture))
+	for name, path := range capture {
+		value, err := jsonpath.Extract(body, path)
+		if err != nil {
+			return nil, fmt.Errorf("capture '%s': %w", name, err)
+		}
+		captured[name] = jsonpath.Format(value)
+	}
+	return captured, nil
+}
+
+// testEndpoint tests a single endpoint and returns the result. Failed
+// checks are retried (endpoint retries, or --retries) with exponential
+// backoff; the result records how many attempts were made. Every attempt
+// waits for the limiter, which may be nil. Once ctx is done (--max-time),
+// no more attempts are made and the last result is returned.
+func testEndpoint(ctx context.Context, endpoint config.Endpoint, defaultTimeout time.Duration, jar http.CookieJar, limiter *ratelimit.Limiter) stats.BatchResult {
+	maxRetries := endpoint.Retries
+	if maxRetries == 0 {
+		maxRetries = retries
+	}
+
+	var result stats.BatchResult
+	for attempt := 1; ; attempt++ {
+		limiter.Wait()
+		result = testEndpointOnce(ctx, endpoint, defaultTimeout, jar)
+		if !wasSent(result) {
+			return result
+		}
+		result.Attempts = attempt
+		if result.Success || attempt > maxRetries {
+			return result
+		}
+		backoff := request.RetryDelay(attempt, result.Result, retryMaxDelay)
+		slog.Info("retrying endpoint", "name", endpoint.Name, "attempt", attempt+1, "of", maxRetries+1,
+			"backoff", backoff, "reason", result.Message)
+		if err := request.Sleep(ctx, backoff); err != nil {
+			return result
+		}
+	}
+}
+
+// endpointOptions builds the request options for a batch endpoint.
+func endpointOptions(endpoint config.Endpoint, defaultTimeout time.Duration, jar http.CookieJar) request.PingOptions {
+	// Use endpoint-specific timeouts or the defaults
+	firstByte, idle := endpoint.FirstByteTimeout, endpoint.IdleTimeout
+	if firstByte == 0 {
+		firstByte = firstByteTimeout
+	}
+	if idle == 0 {
+		idle = idleTimeout
+	}
+	connect, response := endpoint.ConnectTimeout, endpoint.ResponseTimeout
+	if connect == 0 {
+		connect = connectTimeout
+	}
+	if response == 0 {
+		response = responseTimeout
+	}
+
+	// The endpoint's user_agent wins, then a User-Agent header of its own
+	agent := endpoint.UserAgent
+	if agent == "" && !request.HasHeader(endpoint.Headers, "User-Agent") {
+		agent = userAgent
+	}
+
+	// Streaming endpoints (with a first-byte or idle timeout) are only
+	// bound by a timeout of their own, not the global one
 	timeout := endpoint.Timeout
-	if timeout == 0 {
+	if timeout == 0 && firstByte == 0 && idle == 0 {
 		timeout = defaultTimeout
 	}
 
 	// Configure request
 	opts := request.PingOptions{
-		Method:  strings.ToUpper(endpoint.Method),
-		Timeout: timeout,
-		Retries: 0, // No retries in batch mode for speed
-		Headers: endpoint.Headers,
+		Method:           strings.ToUpper(endpoint.Method),
+		Timeout:          timeout,
+		Retries:          0, // Retried by testEndpoint, which also retries failed checks
+		Headers:          config.MergeHeaders(userConfig.Headers, endpoint.Headers),
+		UserAgent:        agent,
+		FirstByteTimeout: firstByte,
+		IdleTimeout:      idle,
+		ConnectTimeout:   connect,
+		ResponseTimeout:  response,
+		Resolve:          resolveHostOverrides(),
+		DNSServer:        dnsServer,
+		LocalAddr:        resolveLocalAddr(),
+		IPVersion:        resolveIPVersion(),
+		HTTPVersion:      resolveHTTPVersion(),
+		ReadBody:         readsBody(endpoint),
+		Download:         downloadBody || endpoint.MinSize > 0 || endpoint.MaxSize > 0, // Exact size for size checks
+		Compressed:       compressedBody,
+		Jar:              jar,
+		Pool:             batchPool(),
+	}
+
+	// Send the body, labeled with a detected content type unless the
+	// endpoint sets one
+	if endpoint.Body != "" {
+		opts.Body = []byte(endpoint.Body)
+		if !request.HasHeader(opts.Headers, "Content-Type") {
+			opts.Headers["Content-Type"] = request.DetectContentType(opts.Body, endpoint.BodyFile)
+		}
+	}
+	if endpoint.SOAPAction != "" {
+		request.SetSOAPAction(opts.Headers, opts.Body, endpoint.SOAPAction)
+	}
+	applyTraceHTTP(&opts)
+
+	return opts
+}
+
+// readsBody reports whether the response body of an endpoint must be read,
+// for its checks or for the run's recordings and snapshots.
+func readsBody(endpoint config.Endpoint) bool {
+	return recordBodies || snapshotDir != "" ||
+		endpoint.ExpectSchema != "" || len(endpoint.ExpectXPath) > 0 ||
+		len(endpoint.Capture) > 0 || endpoint.SaveBody != ""
+}
+
+// saveBody writes the response body of result to path. A body cut off at
+// request.MaxBodySize is not saved: a partial file would pass for the
+// whole response.
+func saveBody(path string, result request.Result) error {
+	if result.Truncated {
+		return fmt.Errorf("response body is larger than %s, the most that can be saved", formatBytes(request.MaxBodySize))
+	}
+	return os.WriteFile(path, result.Body, 0o644)
+}
+
+// testEndpointOnce makes a single request to an endpoint and checks it.
+func testEndpointOnce(ctx context.Context, endpoint config.Endpoint, defaultTimeout time.Duration, jar http.CookieJar) stats.BatchResult {
+	opts := endpointOptions(endpoint, defaultTimeout, jar)
+
+	// Append endpoint query parameters
+	targetURL, err := endpoint.RequestURL()
+	if err != nil {
+		return errorResult(endpoint, err)
 	}
 
 	// Make request
-	result := request.Ping(endpoint.URL, opts)
+	result := request.PingContext(ctx, targetURL, opts)
 
-	// Check if test passed
-	success := result.Error == nil && result.StatusCode == endpoint.ExpectedStatus
+	// Save the body whether or not the checks pass, to help debug failures
+	var saveErr error
+	if endpoint.SaveBody != "" && result.Error == nil {
+		saveErr = saveBody(endpoint.SaveBody, result)
+	}
 
-	var message string
+	// Use endpoint-specific latency SLA or the --max-latency default
+	latencyLimit := endpoint.MaxLatency
+	if latencyLimit == 0 {
+		latencyLimit = maxLatency
+	}
+
+	// Header and XPath assertions were validated when the config was loaded
+	headerExpectations, _ := assert.ParseHeaderExpectations(endpoint.ExpectHeaders)
+	xpathExpectations, _ := assert.ParseXPathExpectations(endpoint.ExpectXPath)
+
+	// Check if test passed
+	var message, category string
 	if result.Error != nil {
 		message = fmt.Sprintf("Error: %v", result.Error)
 	} else if result.StatusCode != endpoint.ExpectedStatus {
 		message = fmt.Sprintf("Expected %d, got %d", endpoint.ExpectedStatus, result.StatusCode)
+	} else if exceedsMaxLatency(result.Latency, latencyLimit) {
+		message = fmt.Sprintf("Latency %s exceeded max %s", result.Latency.Round(time.Millisecond), latencyLimit)
+		category = request.CategorySlow
+	} else if err := assert.CheckHeaders(result.Headers, headerExpectations); err != nil {
+		message = capitalize(err.Error())
+	} else if err := assert.CheckSize(result.Size, endpoint.MinSize, endpoint.MaxSize); err != nil {
+		message = capitalize(err.Error())
+	} else if err := assert.CheckRedirect(result.URL, result.FinalURL, endpoint.ExpectRedirect); err != nil {
+		message = capitalize(err.Error())
+	} else if err := assert.CheckXPath(result.Body, xpathExpectations); err != nil {
+		message = capitalize(err.Error())
+	} else if err := checkSchema(endpoint, result.Body); err != nil {
+		message = capitalize(err.Error())
+	} else if err := checkSnapshot(endpoint, result.Body); err != nil {
+		message = capitalize(err.Error())
+	} else if saveErr != nil {
+		message = fmt.Sprintf("Failed to save body: %v", saveErr)
 	}
+	success := message == ""
 
 	return stats.BatchResult{
 		Name:           endpoint.Name,
@@ -973,66 +2856,117 @@ func testEndpoint(endpoint config.Endpoint, defaultTimeout time.Duration) stats.
 		ExpectedStatus: endpoint.ExpectedStatus,
 		Success:        success,
 		Message:        message,
+		Category:       category,
+		Tags:           endpoint.Tags,
+		Stage:          endpoint.Stage,
+		DependsOn:      endpoint.DependsOn,
+		RequestHeaders: opts.Headers,
+		RequestBody:    endpoint.Body,
 	}
 }
 
+// wasSent reports whether a batch result comes from a request that was
+// actually made (skipped endpoints and requests that could not be built
+// have nothing to reproduce).
+func wasSent(result stats.BatchResult) bool {
+	var unbuilt unbuiltError
+	return !result.Skipped && !errors.As(result.Result.Error, &unbuilt)
+}
+
 // displayBatchResults shows the batch test results based on output format.
-func displayBatchResults(summary *stats.BatchSummary) {
+func displayBatchResults(summary *stats.BatchSummary, configFile string) {
 	// Handle different output formats
 	switch outputFormat {
 	case "json":
 		displayBatchResultsJSON(summary)
 		return
+	case "junit":
+		displayBatchResultsJUnit(summary, filepath.Base(configFile))
+		return
+	case "influx":
+		displayBatchResultsInflux(summary)
+		return
 	case "csv":
 		displayBatchResultsCSV(summary)
 		return
+	case "summary":
+		displayBatchResultsSummary(summary)
+		return
+	case "ndjson", "template":
+		// Results were streamed as they completed
+		exitBatch(summary, batchExitCode(summary))
 	case "pretty":
 		// Continue with normal display
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown output format: %s\n", outputFormat)
+		fmt.Fprintf(output.Stderr, "Unknown output format: %s\n", outputFormat)
 		os.Exit(ExitError)
 	}
 
 	// Silent mode: no output at all
 	if silent {
-		if summary.Failed > 0 {
-			os.Exit(ExitFailure)
-		}
-		os.Exit(ExitSuccess)
+		exitBatch(summary, batchExitCode(summary))
 	}
 
 	// Quiet mode: errors already printed during execution
 	if quiet {
-		if summary.Failed > 0 {
-			os.Exit(ExitFailure)
-		}
-		os.Exit(ExitSuccess)
+		exitBatch(summary, batchExitCode(summary))
+	}
+
+	// Normal mode: pretty output
+	displayBatchResultsPretty(summary)
+}
+
+// displayBatchResultsJSON outputs results in JSON format.
+func displayBatchResultsJSON(summary *stats.BatchSummary) {
+	jsonOutput, err := output.FormatBatchResultJSON(summary)
+	if err != nil {
+		fmt.Fprintf(output.Stderr, "Error formatting JSON: %v\n", err)
+		os.Exit(ExitError)
+	}
+
+	fmt.Println(jsonOutput)
+
+	exitBatch(summary, batchExitCode(summary))
+}
+
+// displayBatchResultsSummary prints only the counts and the names of the
+// failed endpoints, one per line, for cron mails and chat notifications.
+func displayBatchResultsSummary(summary *stats.BatchSummary) {
+	if !silent {
+		output.Print(output.FormatBatchResultSummary(summary))
 	}
 
-	// Normal mode: pretty output
-	displayBatchResultsPretty(summary)
+	exitBatch(summary, batchExitCode(summary))
 }
 
-// displayBatchResultsJSON outputs results in JSON format.
-func displayBatchResultsJSON(summary *stats.BatchSummary) {
-	jsonOutput, err := output.FormatBatchResultJSON(summary)
+// displayBatchResultsJUnit outputs results as JUnit XML for CI test reports.
+func displayBatchResultsJUnit(summary *stats.BatchSummary, suiteName string) {
+	xmlOutput, err := output.FormatBatchResultJUnit(summary, suiteName)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+		fmt.Fprintf(output.Stderr, "Error formatting JUnit XML: %v\n", err)
 		os.Exit(ExitError)
 	}
 
-	fmt.Println(jsonOutput)
+	fmt.Println(xmlOutput)
 
-	if summary.Failed > 0 {
-		os.Exit(ExitFailure)
+	exitBatch(summary, batchExitCode(summary))
+}
+
+// displayBatchResultsInflux outputs results as InfluxDB line protocol,
+// e.g. for Telegraf's exec input.
+func displayBatchResultsInflux(summary *stats.BatchSummary) {
+	now := time.Now()
+	for _, result := range summary.Results {
+		fmt.Println(metrics.LineProtocol(batchSample(result, now)))
 	}
-	os.Exit(ExitSuccess)
+
+	exitBatch(summary, batchExitCode(summary))
 }
 
 // displayBatchResultsCSV outputs results in CSV format.
 func displayBatchResultsCSV(summary *stats.BatchSummary) {
 	// CSV header
-	fmt.Println("name,url,method,status,expected_status,latency_ms,size_bytes,success,error")
+	fmt.Println("name,url,method,status,expected_status,latency_ms,ttfb_ms,size_bytes,download_ms,throughput_bytes_per_sec,success,tags,error")
 
 	// CSV rows
 	for _, result := range summary.Results {
@@ -1043,49 +2977,67 @@ func displayBatchResultsCSV(summary *stats.BatchSummary) {
 			errMsg = result.Message
 		}
 
-		fmt.Printf("%s,%s,%s,%d,%d,%d,%d,%t,%s\n",
+		fmt.Printf("%s,%s,%s,%d,%d,%d,%d,%d,%d,%.0f,%t,%s,%s\n",
 			result.Name,
 			result.URL,
 			result.Method,
 			result.Result.StatusCode,
 			result.ExpectedStatus,
 			result.Result.Latency.Milliseconds(),
+			result.Result.TTFB.Milliseconds(),
 			result.Result.Size,
+			result.Result.Download.Milliseconds(),
+			result.Result.Throughput(),
 			result.Success,
+			strings.Join(result.Tags, ";"),
 			errMsg,
 		)
 	}
 
-	if summary.Failed > 0 {
-		os.Exit(ExitFailure)
+	exitBatch(summary, batchExitCode(summary))
+}
+
+// printReproCommands prints a curl command for every failed request.
+func printReproCommands(summary *stats.BatchSummary) {
+	header := false
+	for _, result := range summary.Results {
+		if result.Success || !wasSent(result) {
+			continue
+		}
+		if !header {
+			output.Printf("\n🔁 Reproduce failures\n")
+			header = true
+		}
+		output.Printf("\n   # %s\n", result.Name)
+		output.Printf("   %s\n", strings.ReplaceAll(output.FormatCurl(result), "\n", "\n   "))
 	}
-	os.Exit(ExitSuccess)
 }
 
 // displayBatchResultsPretty shows the normal pretty output.
 func displayBatchResultsPretty(summary *stats.BatchSummary) {
-	// Table header
-	fmt.Printf("%-20s %-7s %-7s %-10s %-8s %s\n",
-		"ENDPOINT", "METHOD", "STATUS", "LATENCY", "SIZE", "RESULT")
-	fmt.Printf("%s\n", strings.Repeat("─", 75))
+	table := output.NewTable(
+		output.Column{Header: "ENDPOINT", Flex: true, Min: 12},
+		output.Column{Header: "METHOD"},
+		output.Column{Header: "STATUS"},
+		output.Column{Header: "LATENCY"},
+		output.Column{Header: "SIZE"},
+		output.Column{Header: "RESULT"},
+	)
+	table.Gap = 1
+	table.Width = tableWidth()
 
-	// Results rows
+	// Results rows, with a heading whenever a new stage starts
+	stage := ""
 	for _, result := range summary.Results {
-		// Format endpoint name (truncate if too long)
-		name := result.Name
-		if len(name) > 20 {
-			name = name[:17] + "..."
+		if result.Stage != stage {
+			stage = result.Stage
+			table.AddTitle(output.Cyan(fmt.Sprintf("▸ %s", stage)))
 		}
 
-		// Format status
-		statusStr := "-"
-		if result.Result.Error == nil {
+		// Format status and latency; skipped endpoints have neither
+		statusStr, latencyStr := "-", "-"
+		if result.Result.Error == nil && !result.Skipped {
 			statusStr = fmt.Sprintf("%d", result.Result.StatusCode)
-		}
-
-		// Format latency
-		latencyStr := "-"
-		if result.Result.Error == nil {
 			latencyStr = result.Result.Latency.String()
 		}
 
@@ -1097,29 +3049,38 @@ func displayBatchResultsPretty(summary *stats.BatchSummary) {
 
 		// Format result indicator
 		var resultStr string
-		if result.Success {
-			if result.Result.Latency > 500*time.Millisecond {
+		if result.Flaky() {
+			resultStr = output.Yellow(fmt.Sprintf("⚠️  FLAKY (passed on retry %d)", result.Attempts-1))
+		} else if result.Success {
+			if result.Result.Latency > slowThreshold {
 				resultStr = output.Yellow("⚠️  SLOW")
 			} else {
 				resultStr = output.Green("✓")
 			}
+		} else if result.Silenced != "" {
+			resultStr = output.Yellow(fmt.Sprintf("🔇 %s (%s)", result.Message, result.Silenced))
+		} else if result.Upstream != "" {
+			resultStr = output.Yellow(fmt.Sprintf("◌ degraded (upstream: %s)", result.Upstream))
+		} else if result.Attempts > 1 {
+			resultStr = output.Red(fmt.Sprintf("✗ %s (%d attempts)", result.Message, result.Attempts))
 		} else {
 			resultStr = output.Red(fmt.Sprintf("✗ %s", result.Message))
 		}
 
-		fmt.Printf("%-20s %-7s %-7s %-10s %-8s %s\n",
-			name,
-			result.Method,
-			statusStr,
-			latencyStr,
-			sizeStr,
-			resultStr)
+		table.AddRow(result.Name, result.Method, statusStr, latencyStr, sizeStr, resultStr)
+	}
+	output.Print(table.Render())
+
+	displayDependencyTree(summary.Results)
+
+	if emitCurl {
+		printReproCommands(summary)
 	}
 
 	// Summary section
-	fmt.Printf("\n%s\n", strings.Repeat("─", 75))
-	fmt.Printf("📊 Summary\n")
-	fmt.Printf("   Total:        %d endpoints\n", summary.Total)
+	output.Printf("\n%s\n", strings.Repeat("─", 75))
+	output.Printf("📊 Summary\n")
+	output.Printf("   Total:        %d endpoints\n", summary.Total)
 
 	successRate := summary.SuccessRate()
 	var rateColor func(string) string
@@ -1131,29 +3092,394 @@ func displayBatchResultsPretty(summary *stats.BatchSummary) {
 		rateColor = output.Red
 	}
 
-	fmt.Printf("   Successful:   %s (%.1f%%)\n",
+	output.Printf("   Successful:   %s (%.1f%%)\n",
 		rateColor(fmt.Sprintf("%d", summary.Successful)),
 		successRate)
-	fmt.Printf("   Failed:       %s\n", output.Red(fmt.Sprintf("%d", summary.Failed)))
+	output.Printf("   Failed:       %s%s\n", output.Red(fmt.Sprintf("%d", summary.Failed)), formatFailures(summary.Errors))
 
 	if summary.Slow > 0 {
-		fmt.Printf("   Slow:         %s (> 500ms)\n", output.Yellow(fmt.Sprintf("%d", summary.Slow)))
+		output.Printf("   Slow:         %s (> %s)\n", output.Yellow(fmt.Sprintf("%d", summary.Slow)), slowThreshold)
+	}
+	if summary.Flaky > 0 {
+		output.Printf("   Flaky:        %s (passed on retry)\n", output.Yellow(fmt.Sprintf("%d", summary.Flaky)))
+	}
+	if summary.Silenced > 0 {
+		output.Printf("   Silenced:     %s (failed during maintenance)\n", output.Yellow(fmt.Sprintf("%d", summary.Silenced)))
+	}
+	if summary.Degraded > 0 {
+		output.Printf("   Degraded:     %s (upstream dependency failed)\n", output.Yellow(fmt.Sprintf("%d", summary.Degraded)))
 	}
 
 	if summary.Total > 0 && summary.AvgLatency > 0 {
-		fmt.Printf("   Avg Latency:  %s\n", formatLatency(summary.AvgLatency))
+		output.Printf("   Avg Latency:  %s\n", formatLatency(summary.AvgLatency))
+	}
+	output.Printf("   Total Time:   %s\n", summary.TotalTime.Round(10*time.Millisecond))
+	if summary.Requests > 0 && summary.TotalTime > 0 {
+		output.Printf("   Rate:         %.1f req/s\n", summary.RequestRate())
+	}
+	if compressedBody {
+		compressed := 0
+		for _, result := range summary.Results {
+			if result.Result.Encoding != "" {
+				compressed++
+			}
+		}
+		output.Printf("   Compressed:   %d/%d responses\n", compressed, summary.Total)
+	}
+	if downloadBody {
+		var bytes int64
+		var elapsed time.Duration
+		for _, result := range summary.Results {
+			bytes += downloadedBytes(result.Result)
+			elapsed += result.Result.Download
+		}
+		if elapsed > 0 {
+			output.Printf("   Downloaded:   %s (%s)\n", formatBytes(bytes), formatThroughput(float64(bytes)/elapsed.Seconds()))
+		}
+	}
+
+	if summary.Total > 0 {
+		output.Println()
+		displayStatusBreakdown(summary.Statuses, summary.Total)
 	}
-	fmt.Printf("   Total Time:   %s\n", summary.TotalTime.Round(10*time.Millisecond))
 
 	// Final message
-	fmt.Println()
+	output.Println()
+	code := batchExitCode(summary)
 	if summary.Failed == 0 {
-		fmt.Printf("%s\n", output.Green("✓ All endpoints healthy!"))
-		os.Exit(ExitSuccess)
+		output.Printf("%s\n", output.Green("✓ All endpoints healthy!"))
+	} else if summary.Silenced > 0 && summary.Silenced+summary.Degraded == summary.Failed {
+		output.Printf("%s\n", output.Yellow(fmt.Sprintf("🔇 %d endpoint(s) failed during maintenance", summary.Failed)))
+	} else if code == ExitSuccess {
+		output.Printf("%s\n", output.Yellow(fmt.Sprintf("⚠️  %d endpoint(s) failed, within the exit policy", summary.Failed)))
+	} else if summary.Degraded > 0 {
+		output.Printf("%s\n", output.Red(fmt.Sprintf("✗ %d endpoint(s) failed, %d more degraded upstream!",
+			summary.Failed-summary.Degraded, summary.Degraded)))
 	} else {
-		fmt.Printf("%s\n", output.Red(fmt.Sprintf("✗ %d endpoint(s) failed!", summary.Failed)))
-		os.Exit(ExitFailure)
+		output.Printf("%s\n", output.Red(fmt.Sprintf("✗ %d endpoint(s) failed!", summary.Failed)))
+	}
+	exitBatch(summary, code)
+}
+
+// exceedsMaxLatency reports whether latency breaks the given SLA.
+// A zero limit means no SLA is enforced.
+func exceedsMaxLatency(latency, limit time.Duration) bool {
+	return limit > 0 && latency > limit
+}
+
+// requestOptions builds the request options shared by ping, watch and trace
+// from the command-line flags. It exits on invalid flag combinations.
+func requestOptions(headers map[string]string) request.PingOptions {
+	opts := request.PingOptions{
+		Method:           strings.ToUpper(method),
+		Timeout:          timeout,
+		Retries:          retries,
+		RetryOnStatus:    retryOnStatus,
+		RetryMaxDelay:    retryMaxDelay,
+		Headers:          config.MergeHeaders(userConfig.Headers, headers),
+		UserAgent:        userAgent,
+		FirstByteTimeout: firstByteTimeout,
+		IdleTimeout:      idleTimeout,
+		ConnectTimeout:   connectTimeout,
+		ResponseTimeout:  responseTimeout,
+		Resolve:          resolveHostOverrides(),
+		DNSServer:        dnsServer,
+		LocalAddr:        resolveLocalAddr(),
+		IPVersion:        resolveIPVersion(),
+		HTTPVersion:      resolveHTTPVersion(),
+		Auth:             resolveCredentials(),
+		Download:         downloadBody,
+		Compressed:       compressedBody,
+		DisableKeepAlive: true, // Every request measures a cold connection
+	}
+
+	for _, status := range retryOnStatus {
+		if status < 100 || status > 599 {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: --retry-on-status: %d is not an HTTP status code", status)))
+			os.Exit(ExitError)
+		}
+	}
+
+	if len(cookies) > 0 || cookieJarFile != "" {
+		opts.Jar = resolveCookieJar()
+	}
+
+	if len(formFields) > 0 {
+		applyForm(&opts)
+	}
+	applyTraceHTTP(&opts)
+
+	return opts
+}
+
+// applyForm encodes the --form fields as the request body. Like curl, a
+// form turns the default GET into a POST. It exits if a field is malformed
+// or a file cannot be read.
+func applyForm(opts *request.PingOptions) {
+	fields, err := request.ParseFormFields(formFields)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing form: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	body, contentType, err := request.EncodeMultipart(fields)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error building form: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	headers := make(map[string]string, len(opts.Headers)+1)
+	for key, value := range opts.Headers {
+		headers[key] = value
+	}
+	headers["Content-Type"] = contentType
+
+	opts.Headers = headers
+	opts.Body = body
+	if opts.Method == http.MethodGet {
+		opts.Method = http.MethodPost
+	}
+}
+
+// resolveCookieJar loads the --cookie-jar file (if any) and adds the
+// --cookie values. It exits if either is malformed.
+func resolveCookieJar() *request.CookieJar {
+	jar := request.NewCookieJar()
+
+	if cookieJarFile != "" {
+		loaded, err := request.LoadCookieJar(cookieJarFile)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		jar = loaded
+	}
+
+	parsed, err := request.ParseCookies(cookies)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	jar.AddStatic(parsed...)
+
+	return jar
+}
+
+// withQueryParams appends the --param values to rawURL, exiting on invalid input.
+func withQueryParams(rawURL string) string {
+	if len(queryParams) == 0 {
+		return rawURL
+	}
+
+	params, err := config.ParseQueryParams(queryParams)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing params: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	withParams, err := config.AppendQueryParams(rawURL, params)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	return withParams
+}
+
+// batchSample converts a batch result into a metrics sample.
+func batchSample(result stats.BatchResult, at time.Time) metrics.Sample {
+	return metrics.Sample{
+		Mode:    "batch",
+		Name:    result.Name,
+		URL:     result.URL,
+		Method:  result.Method,
+		Status:  result.Result.StatusCode,
+		Latency: result.Result.Latency,
+		Success: result.Success,
+		Bytes:   downloadedBytes(result.Result),
+		Rate:    result.Result.Throughput(),
+		Time:    at,
+	}
+}
+
+// openMetrics connects the metrics sinks selected by flags. It returns nil
+// if none were requested and exits if one cannot be set up.
+func openMetrics() metrics.Sink {
+	var sinks metrics.Multi
+
+	if statsdAddr != "" {
+		statsd, err := metrics.NewStatsD(statsdAddr, statsdPrefix, dogStatsD, statsdTags)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		sinks = append(sinks, statsd)
+	}
+
+	if influxURL != "" {
+		token := influxToken
+		if token == "" {
+			token = os.Getenv("INFLUX_TOKEN")
+		}
+		influx, err := metrics.NewInflux(influxURL, token)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		sinks = append(sinks, influx)
+	}
+
+	if len(sinks) == 0 {
+		return nil
+	}
+	return sinks
+}
+
+// closeMetrics flushes and closes sink, warning if delivery failed.
+func closeMetrics(sink metrics.Sink) {
+	if sink == nil {
+		return
+	}
+	if err := sink.Close(); err != nil {
+		slog.Warn("failed to send metrics", "error", err)
+	}
+}
+
+// saveCookieJar writes the session cookies back to --cookie-jar, if set.
+func saveCookieJar(jar http.CookieJar) {
+	cookieJar, ok := jar.(*request.CookieJar)
+	if !ok || cookieJarFile == "" {
+		return
+	}
+
+	if err := cookieJar.Save(cookieJarFile); err != nil {
+		slog.Warn(err.Error())
+	}
+}
+
+// resolveCredentials parses the -u/--user flag. It exits if the value is malformed.
+func resolveCredentials() *request.Credentials {
+	if userCredentials == "" {
+		return nil
+	}
+
+	creds, err := request.ParseCredentials(userCredentials, digestAuth)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	return creds
+}
+
+// resolveHostOverrides parses the --resolve flags. It exits if a value is
+// malformed.
+func resolveHostOverrides() map[string]string {
+	if len(resolveHosts) == 0 {
+		return nil
+	}
+
+	overrides, err := request.ParseResolve(resolveHosts)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	return overrides
+}
+
+// resolveLocalAddr returns the source address selected by --local-addr or
+// --interface. It exits if the address or interface is invalid.
+func resolveLocalAddr() string {
+	switch {
+	case sourceInterface != "" && localAddr != "":
+		fmt.Fprintln(output.Stderr, output.Red("Error: use either --interface or --local-addr, not both"))
+		os.Exit(ExitError)
+	case sourceInterface != "":
+		addr, err := request.InterfaceAddress(sourceInterface)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		return addr
+	case localAddr != "" && net.ParseIP(localAddr) == nil:
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: --local-addr must be an IP address, got %q", localAddr)))
+		os.Exit(ExitError)
+	}
+	return localAddr
+}
+
+// resolveIPVersion returns the IP version selected by -4 or -6. It exits
+// with an error if both are set.
+func resolveIPVersion() string {
+	switch {
+	case forceIPv4 && forceIPv6:
+		fmt.Fprintln(output.Stderr, output.Red("Error: use either -4 or -6, not both"))
+		os.Exit(ExitError)
+	case forceIPv4:
+		return request.IPVersion4
+	case forceIPv6:
+		return request.IPVersion6
+	}
+	return request.IPVersionAny
+}
+
+// resolveHTTPVersion returns the protocol version selected by --http1,
+// --http2 or --http3. It exits with an error if more than one is set.
+func resolveHTTPVersion() string {
+	selected := make([]string, 0, 1)
+	if forceHTTP1 {
+		selected = append(selected, request.HTTPVersion1)
+	}
+	if forceHTTP2 {
+		selected = append(selected, request.HTTPVersion2)
+	}
+	if forceHTTP3 {
+		selected = append(selected, request.HTTPVersion3)
+	}
+
+	if len(selected) > 1 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: only one of --http1, --http2 and --http3 can be used"))
+		os.Exit(ExitError)
+	}
+	if len(selected) == 0 {
+		return request.HTTPVersionAuto
+	}
+	return selected[0]
+}
+
+// capitalize upper-cases the first letter of an error message for display.
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
+
+// describeTimeouts formats the overall timeout of a request and those of
+// its phases, e.g. "10s (connect 2s, response 5s)" or "none (first byte 5s,
+// idle 30s)".
+func describeTimeouts(opts request.PingOptions) string {
+	description := "none"
+	if opts.Timeout > 0 {
+		description = opts.Timeout.String()
+	}
+
+	var phases []string
+	if opts.ConnectTimeout > 0 {
+		phases = append(phases, "connect "+opts.ConnectTimeout.String())
 	}
+	if opts.ResponseTimeout > 0 {
+		phases = append(phases, "response "+opts.ResponseTimeout.String())
+	}
+	if opts.FirstByteTimeout > 0 {
+		phases = append(phases, "first byte "+opts.FirstByteTimeout.String())
+	}
+	if opts.IdleTimeout > 0 {
+		phases = append(phases, "idle "+opts.IdleTimeout.String())
+	}
+	if len(phases) > 0 {
+		description += " (" + strings.Join(phases, ", ") + ")"
+	}
+	return description
 }
 
 // isValidURL checks if the URL starts with http:// or https://
@@ -1162,26 +3488,55 @@ func isValidURL(url string) bool {
 }
 
 // printRequestDetails displays verbose information about the request being made.
-func printRequestDetails(url string, headers map[string]string) {
-	fmt.Printf("   Request\n")
-	fmt.Printf("   URL:     %s\n", output.Blue(url))
-	fmt.Printf("   Method:  %s\n", method)
-	fmt.Printf("   Timeout: %v\n", timeout)
+func printRequestDetails(url string, opts request.PingOptions) {
+	headers := opts.Headers
+
+	output.Printf("   Request\n")
+	output.Printf("   URL:     %s\n", output.Blue(url))
+	output.Printf("   Method:  %s\n", opts.Method)
+	output.Printf("   Timeout: %s\n", describeTimeouts(opts))
+	overridden := make([]string, 0, len(opts.Resolve))
+	for hostPort := range opts.Resolve {
+		overridden = append(overridden, hostPort)
+	}
+	sort.Strings(overridden)
+	for _, hostPort := range overridden {
+		output.Printf("   Resolve: %s → %s\n", hostPort, opts.Resolve[hostPort])
+	}
+	if opts.DNSServer != "" {
+		output.Printf("   DNS:     %s\n", opts.DNSServer)
+	}
+	if opts.LocalAddr != "" {
+		output.Printf("   Source:  %s\n", opts.LocalAddr)
+	}
+	if opts.IPVersion != request.IPVersionAny {
+		output.Printf("   IP:      IPv%s only\n", opts.IPVersion)
+	}
 	if retries > 0 {
-		fmt.Printf("   Retries: %d\n", retries)
+		output.Printf("   Retries: %d\n", retries)
+	}
+	if creds := resolveCredentials(); creds != nil {
+		scheme := "Basic"
+		if creds.Digest {
+			scheme = "Digest"
+		}
+		output.Printf("   Auth:    %s (%s)\n", scheme, creds.Username)
+	}
+	if len(opts.Body) > 0 {
+		output.Printf("   Body:    %d bytes\n", len(opts.Body))
 	}
 	if len(headers) > 0 {
-		fmt.Printf("   Headers: %d total\n", len(headers))
+		output.Printf("   Headers: %d total\n", len(headers))
 		for key, value := range headers {
 			// Mask sensitive headers for security
 			displayValue := value
 			if isSensitiveHeader(key) {
 				displayValue = maskSensitiveValue(value)
 			}
-			fmt.Printf("     %s: %s\n", key, displayValue)
+			output.Printf("     %s: %s\n", key, displayValue)
 		}
 	}
-	fmt.Println()
+	output.Println()
 }
 
 // isSensitiveHeader checks if a header contains sensitive information
@@ -1206,8 +3561,8 @@ func maskSensitiveValue(value string) string {
 
 // printError displays a formatted error message for failed requests.
 func printError(url string, err error) {
-	fmt.Printf("%s Failed to ping %s\n", output.Red("✗"), url)
-	fmt.Printf("  Error: %v\n", err)
+	output.Printf("%s Failed to ping %s\n", output.Red("✗"), url)
+	output.Printf("  Error: %v\n", err)
 }
 
 // printSuccess displays a formatted success message with response details.
@@ -1216,32 +3571,95 @@ func printSuccess(result request.Result) {
 	latencyDisplay := formatLatency(result.Latency)
 
 	// Print main success message
-	fmt.Printf("%s Success\n", output.Green("✓"))
-	fmt.Printf("  Status:   %s\n", result.Status)
-	fmt.Printf("  Latency:  %s\n", latencyDisplay)
+	output.Printf("%s Success\n", output.Green("✓"))
+	output.Printf("  Status:   %s\n", result.Status)
+	output.Printf("  Latency:  %s\n", latencyDisplay)
+	if result.TTFB > 0 {
+		output.Printf("  TTFB:     %s\n", result.TTFB.Round(time.Microsecond))
+	}
 
 	// Show protocol if available
 	if result.Protocol != "" {
-		fmt.Printf("  Protocol: %s\n", result.Protocol)
+		output.Printf("  Protocol: %s\n", result.Protocol)
 	}
 
 	// Show size if known (ContentLength returns -1 if unknown)
 	if result.Size > 0 {
-		fmt.Printf("  Size:     %s\n", formatBytes(result.Size))
+		output.Printf("  Size:     %s\n", formatBytes(result.Size))
+	}
+	if result.Download > 0 {
+		output.Printf("  Download: %s (%s)\n", result.Download.Round(time.Microsecond), formatThroughput(result.Throughput()))
+	}
+	if compressedBody {
+		output.Printf("  Encoding: %s\n", describeCompression(result))
+	}
+}
+
+// printBodySnippet prints up to limit bytes of a response body.
+func printBodySnippet(body []byte, limit int) {
+	if len(body) == 0 {
+		output.Printf("  Body:     %s\n", output.Yellow("(empty)"))
+		return
+	}
+
+	snippet := body
+	if len(snippet) > limit {
+		snippet = snippet[:limit]
+	}
+
+	output.Println("  Body:")
+	for _, line := range strings.Split(strings.TrimRight(string(snippet), "\n"), "\n") {
+		fmt.Printf("    %s\n", line) // Body as received, never themed
+	}
+	if len(body) > limit {
+		output.Printf("    %s\n", output.Yellow(fmt.Sprintf("... (%d more bytes)", len(body)-limit)))
 	}
 }
 
 // formatLatency returns a color-coded latency string based on performance thresholds.
-// Fast responses (<200ms) are green, medium (200-500ms) are yellow, slow (>500ms) are red.
+// Fast responses (<200ms by default) are green, medium (200-500ms) are yellow, slow (>500ms) are red.
 func formatLatency(latency time.Duration) string {
-	latencyStr := latency.String()
+	return output.Latency(latency.String(), latencyLevel(latency))
+}
 
+// latencyLevel rates a latency against --fast-threshold and --slow-threshold.
+func latencyLevel(latency time.Duration) int {
 	if latency < fastThreshold {
-		return output.Green(latencyStr)
+		return output.LatencyFast
 	} else if latency < slowThreshold {
-		return output.Yellow(latencyStr)
+		return output.LatencyMedium
+	}
+	return output.LatencySlow
+}
+
+// describeCompression summarizes the Content-Encoding and size savings of
+// a response fetched with --compressed.
+func describeCompression(result request.Result) string {
+	switch {
+	case result.Encoding == "":
+		return output.Yellow("none (response was not compressed)")
+	case result.Size < 0:
+		return fmt.Sprintf("%s, %s transferred (ratio unavailable: tapr cannot decode %s)",
+			result.Encoding, formatBytes(result.CompressedSize), result.Encoding)
+	}
+	return fmt.Sprintf("%s, %s → %s (%s)",
+		result.Encoding,
+		formatBytes(result.CompressedSize),
+		formatBytes(result.Size),
+		output.Green(fmt.Sprintf("%.1f%% smaller", result.CompressionRatio()*100)))
+}
+
+// formatThroughput formats a transfer rate given in bytes per second.
+func formatThroughput(bytesPerSecond float64) string {
+	return formatBytes(int64(bytesPerSecond)) + "/s"
+}
+
+// downloadedBytes returns the body size measured in --download mode, or 0.
+func downloadedBytes(result request.Result) int64 {
+	if result.Download <= 0 || result.Size < 0 {
+		return 0
 	}
-	return output.Red(latencyStr)
+	return result.Size
 }
 
 // formatBytes converts a byte count to a human-readable string (e.g., "1.2 KB").
@@ -1263,20 +3681,21 @@ func formatBytes(bytes int64) string {
 
 // runTrace executes the trace command to show detailed timing breakdown.
 func runTrace(cmd *cobra.Command, args []string) {
-	url := args[0]
+	url, aliasHeaders := resolveTarget(cmd, args[0])
 
 	// Validate URL
 	if !isValidURL(url) {
-		fmt.Fprintln(os.Stderr, output.Red("Error: URL must start with http:// or https://"))
+		fmt.Fprintln(output.Stderr, output.Red("Error: URL must start with http:// or https://"))
 		os.Exit(1)
 	}
+	url = withQueryParams(url)
 
 	// Load headers
 	var fileHeaders map[string]string
 	if headersFile != "" {
 		loadedHeaders, err := config.LoadHeaders(headersFile)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading headers: %v", err)))
 			os.Exit(1)
 		}
 		fileHeaders = loadedHeaders
@@ -1286,47 +3705,56 @@ func runTrace(cmd *cobra.Command, args []string) {
 	if len(inlineHeaders) > 0 {
 		parsed, err := config.ParseInlineHeaders(inlineHeaders)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error parsing headers: %v", err)))
 			os.Exit(1)
 		}
 		parsedInlineHeaders = parsed
 	}
 
-	headers := config.MergeHeaders(fileHeaders, parsedInlineHeaders)
+	headers := config.MergeHeaders(aliasHeaders, fileHeaders, parsedInlineHeaders)
 
 	// Print header
-	fmt.Printf("\n┌─────────────────────────────────────────────────────────────────────┐\n")
-	fmt.Printf("│ %s Trace: %s%s│\n",
-		output.Blue("🔍"),
-		url,
-		strings.Repeat(" ", 57-len(url)))
-	fmt.Printf("└─────────────────────────────────────────────────────────────────────┘\n")
+	output.Printf("\n%s", output.Box(tableWidth(), output.Blue("🔍")+" Trace: "+url))
 
 	if verbose {
-		fmt.Printf("⚡ Request\n")
-		fmt.Printf("   Method:  %s\n", method)
-		fmt.Printf("   Timeout: %v\n", timeout)
+		output.Printf("⚡ Request\n")
+		output.Printf("   Method:  %s\n", method)
+		output.Printf("   Timeout: %v\n", timeout)
 		if len(headers) > 0 {
-			fmt.Printf("   Headers: %d total\n", len(headers))
+			output.Printf("   Headers: %d total\n", len(headers))
 		}
-		fmt.Println()
+		output.Println()
 	}
 
 	// Configure request
-	opts := request.PingOptions{
-		Method:  strings.ToUpper(method),
-		Timeout: timeout,
-		Headers: headers,
+	opts := requestOptions(headers)
+
+	if traceRuns < 1 || traceWarmup < 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --runs must be at least 1 and --warmup cannot be negative"))
+		os.Exit(ExitError)
+	}
+	if traceReuse {
+		if traceRuns > 1 || traceWarmup > 0 {
+			fmt.Fprintln(output.Stderr, output.Red("Error: use either --compare-reuse or --runs/--warmup, not both"))
+			os.Exit(ExitError)
+		}
+		runTraceReuse(url, opts)
+		return
+	}
+	if traceRuns > 1 || traceWarmup > 0 {
+		runTraceSeries(url, opts)
+		return
 	}
 
 	// Execute trace
-	fmt.Println("Tracing request...")
+	output.Println("Tracing request...")
 	result := request.TraceRequest(url, opts.Method, opts)
+	saveCookieJar(opts.Jar)
 
 	// Display results
 	if result.Error != nil {
-		fmt.Printf("%s Failed to trace request\n", output.Red("✗"))
-		fmt.Printf("  Error: %v\n", result.Error)
+		output.Printf("%s Failed to trace request\n", output.Red("✗"))
+		output.Printf("  Error: %v\n", result.Error)
 		os.Exit(1)
 	}
 
@@ -1335,7 +3763,7 @@ func runTrace(cmd *cobra.Command, args []string) {
 
 // displayTraceResults shows the detailed timing breakdown.
 func displayTraceResults(result request.TraceResult) {
-	fmt.Printf("📊 Request Timeline\n")
+	output.Printf("📊 Request Timeline\n")
 
 	// Calculate percentages
 	total := float64(result.TotalTime)
@@ -1374,7 +3802,7 @@ func displayTraceResults(result request.TraceResult) {
 
 		bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
 
-		fmt.Printf("   %-18s %s  %-8s (%5.1f%%)\n",
+		output.Printf("   %-18s %s  %-8s (%5.1f%%)\n",
 			phase.name,
 			phase.color(bar),
 			phase.duration,
@@ -1382,31 +3810,225 @@ func displayTraceResults(result request.TraceResult) {
 	}
 
 	// Total
-	fmt.Printf("   %s\n", strings.Repeat("─", 50))
-	fmt.Printf("   %-18s %s  %s\n",
+	output.Printf("   %s\n", strings.Repeat("─", 50))
+	output.Printf("   %-18s %s  %s\n",
 		"Total Time",
 		strings.Repeat(" ", 20),
 		output.Cyan(result.TotalTime.String()))
 
 	// Response information
-	fmt.Printf("📬 Response\n")
-	fmt.Printf("   Status:   %s\n", formatStatusCode(result.StatusCode, result.Status))
-	fmt.Printf("   Protocol: %s\n", result.Protocol)
+	output.Printf("📬 Response\n")
+	output.Printf("   Status:   %s\n", formatStatusCode(result.StatusCode, result.Status))
+	output.Printf("   Protocol: %s\n", result.Protocol)
+	if result.ALPN != "" {
+		output.Printf("   ALPN:     %s\n", result.ALPN)
+	}
 	if result.Size > 0 {
-		fmt.Printf("   Size:     %s\n", formatBytes(result.Size))
+		output.Printf("   Size:     %s\n", formatBytes(result.Size))
 	}
 	if result.RemoteAddr != "" {
-		fmt.Printf("   Server:   %s\n", result.RemoteAddr)
+		output.Printf("   Server:   %s\n", result.RemoteAddr)
 	}
-	fmt.Println()
+	output.Println()
 
 	// Insights
-	fmt.Printf("💡 Insights\n")
+	output.Printf("💡 Insights\n")
 	insights := generateTraceInsights(result)
 	for _, insight := range insights {
-		fmt.Printf("   %s\n", insight)
+		output.Printf("   %s\n", insight)
+	}
+	output.Println()
+}
+
+// tracePhases lists the timed phases of a trace in request order.
+var tracePhases = []struct {
+	name string
+	get  func(request.TraceResult) time.Duration
+}{
+	{"DNS Lookup", func(r request.TraceResult) time.Duration { return r.DNSLookup }},
+	{"TCP Connection", func(r request.TraceResult) time.Duration { return r.TCPConnection }},
+	{"TLS Handshake", func(r request.TraceResult) time.Duration { return r.TLSHandshake }},
+	{"Server Processing", func(r request.TraceResult) time.Duration { return r.ServerProcessing }},
+	{"Content Transfer", func(r request.TraceResult) time.Duration { return r.ContentTransfer }},
+	{"Total Time", func(r request.TraceResult) time.Duration { return r.TotalTime }},
+}
+
+// runTraceSeries traces --warmup discarded requests followed by --runs
+// measured ones and reports per-phase statistics.
+func runTraceSeries(url string, opts request.PingOptions) {
+	if traceWarmup > 0 {
+		output.Printf("Warming up (%d request(s))...\n", traceWarmup)
+		for i := 0; i < traceWarmup; i++ {
+			request.TraceRequest(url, opts.Method, opts)
+		}
+	}
+
+	output.Printf("Tracing %d request(s)...\n", traceRuns)
+	results := make([]request.TraceResult, 0, traceRuns)
+	var lastErr error
+	for i := 0; i < traceRuns; i++ {
+		result := request.TraceRequest(url, opts.Method, opts)
+		if result.Error != nil {
+			lastErr = result.Error
+			continue
+		}
+		results = append(results, result)
+	}
+	saveCookieJar(opts.Jar)
+	output.Println()
+
+	if len(results) == 0 {
+		output.Printf("%s All %d traced requests failed\n", output.Red("✗"), traceRuns)
+		output.Printf("  Error: %v\n", lastErr)
+		os.Exit(ExitFailure)
+	}
+
+	displayTraceStats(results)
+
+	if failed := traceRuns - len(results); failed > 0 {
+		output.Printf("%s %d of %d traced requests failed (last error: %v)\n\n",
+			output.Red("✗"), failed, traceRuns, lastErr)
+		os.Exit(ExitFailure)
+	}
+}
+
+// displayTraceStats shows min/avg/p95/max for each phase over several
+// traced requests, followed by insights based on the averages.
+func displayTraceStats(results []request.TraceResult) {
+	output.Printf("📊 Phase Statistics (%d runs", len(results))
+	if traceWarmup > 0 {
+		output.Printf(", %d warm-up discarded", traceWarmup)
+	}
+	output.Printf(")\n")
+	output.Printf("   %-18s %12s %12s %12s %12s\n", "Phase", "Min", "Avg", "P95", "Max")
+
+	for _, phase := range tracePhases {
+		tracker := stats.NewTracker()
+		for _, result := range results {
+			tracker.Record(phase.get(result), true)
+		}
+		if tracker.MaxLatency == 0 {
+			continue // Phase never happened (e.g., TLS for HTTP)
+		}
+
+		if phase.name == "Total Time" {
+			output.Printf("   %s\n", strings.Repeat("─", 70))
+		}
+		output.Printf("   %-18s %12s %12s %12s %12s\n",
+			phase.name,
+			tracker.MinLatency.Round(time.Microsecond),
+			tracker.AvgLatency().Round(time.Microsecond),
+			tracker.Percentile(0.95).Round(time.Microsecond),
+			tracker.MaxLatency.Round(time.Microsecond))
+	}
+	output.Println()
+
+	last := results[len(results)-1]
+	output.Printf("📬 Response\n")
+	output.Printf("   Status:   %s\n", formatStatusCode(last.StatusCode, last.Status))
+	output.Printf("   Protocol: %s\n", last.Protocol)
+	if last.RemoteAddr != "" {
+		output.Printf("   Server:   %s\n", last.RemoteAddr)
+	}
+	output.Println()
+
+	output.Printf("💡 Insights (averages)\n")
+	for _, insight := range generateTraceInsights(averageTrace(results)) {
+		output.Printf("   %s\n", insight)
+	}
+	output.Println()
+}
+
+// runTraceReuse traces a request on a fresh connection and another on the
+// same connection kept alive, and shows their phases side by side.
+func runTraceReuse(url string, opts request.PingOptions) {
+	output.Println("Tracing a cold and a warm request...")
+	cold, warm := request.TraceReuse(url, opts.Method, opts)
+	saveCookieJar(opts.Jar)
+	output.Println()
+
+	if cold.Error != nil || warm.Error != nil {
+		err := cold.Error
+		if err == nil {
+			err = warm.Error
+		}
+		output.Printf("%s Failed to trace request\n", output.Red("✗"))
+		output.Printf("  Error: %v\n", err)
+		os.Exit(ExitFailure)
+	}
+
+	output.Printf("📊 Cold vs Warm Connection\n")
+	output.Printf("   %-18s %14s %14s %14s\n", "Phase", "Cold (new)", "Warm (reused)", "Saved")
+	for _, phase := range tracePhases {
+		coldTime, warmTime := phase.get(cold), phase.get(warm)
+		if coldTime == 0 && warmTime == 0 {
+			continue
+		}
+		if phase.name == "Total Time" {
+			output.Printf("   %s\n", strings.Repeat("─", 63))
+		}
+		output.Printf("   %-18s %14s %14s %14s\n",
+			phase.name,
+			formatPhase(coldTime),
+			formatPhase(warmTime),
+			(coldTime - warmTime).Round(time.Microsecond))
+	}
+	output.Println()
+
+	output.Printf("📬 Response\n")
+	output.Printf("   Status:   %s\n", formatStatusCode(warm.StatusCode, warm.Status))
+	output.Printf("   Protocol: %s\n", warm.Protocol)
+	output.Println()
+
+	output.Printf("💡 Insights\n")
+	if !warm.Reused {
+		output.Printf("   %s\n", output.Yellow("⚠️  The connection was not reused - the server may not support keep-alive"))
+	} else {
+		setup := cold.DNSLookup + cold.TCPConnection + cold.TLSHandshake
+		output.Printf("   %s\n", output.Cyan(fmt.Sprintf("⚡ Connection setup costs %s (%.1f%% of a cold request)",
+			setup.Round(time.Microsecond), float64(setup)/float64(cold.TotalTime)*100)))
+		if cold.TLSHandshake > 0 {
+			output.Printf("   %s\n", output.Green(fmt.Sprintf("✓ Reuse skips the TLS handshake (%s)", cold.TLSHandshake.Round(time.Microsecond))))
+		}
 	}
-	fmt.Println()
+	output.Println()
+}
+
+// formatPhase formats a phase duration, showing "-" for phases that did
+// not happen.
+func formatPhase(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+// averageTrace returns a trace whose phase timings are the averages of results.
+// A phase missing from a run (such as TLS for HTTP, or the connection phases
+// on a reused connection) counts as zero, so the phases still add up to the
+// average total.
+func averageTrace(results []request.TraceResult) request.TraceResult {
+	var average request.TraceResult
+	if len(results) == 0 {
+		return average
+	}
+	for _, result := range results {
+		average.DNSLookup += result.DNSLookup
+		average.TCPConnection += result.TCPConnection
+		average.TLSHandshake += result.TLSHandshake
+		average.ServerProcessing += result.ServerProcessing
+		average.ContentTransfer += result.ContentTransfer
+		average.TotalTime += result.TotalTime
+	}
+
+	n := time.Duration(len(results))
+	average.DNSLookup /= n
+	average.TCPConnection /= n
+	average.TLSHandshake /= n
+	average.ServerProcessing /= n
+	average.ContentTransfer /= n
+	average.TotalTime /= n
+	return average
 }
 
 // formatStatusCode formats the status code with color.
@@ -1484,8 +4106,8 @@ func generateTraceInsights(result request.TraceResult) []string {
 	}
 
 	// Overall assessment
-	if total < 200*time.Millisecond {
-		insights = append(insights, output.Cyan("⚡ Excellent overall performance (< 200ms)"))
+	if total < fastThreshold {
+		insights = append(insights, output.Cyan(fmt.Sprintf("⚡ Excellent overall performance (< %s)", fastThreshold)))
 	} else if total > 1*time.Second {
 		insights = append(insights, output.Red("⚠️  Poor overall performance (> 1s) - multiple issues need attention"))
 	}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/symtalha14/tapr/internal/alert"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// multiWatchURLWidth is how many characters of a URL are shown in lists
+// such as the flapping endpoints of a multi-URL watch.
+const multiWatchURLWidth = 36
+
+// runMultiWatch watches several endpoints concurrently, each on its own
+// schedule, and shows them in one combined table.
+func runMultiWatch(cmd *cobra.Command, targets []string, fileHeaders, inlineHeaders map[string]string) {
+	if watchResume != "" || watchWindow > 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --resume and --window need a single URL"))
+		os.Exit(ExitError)
+	}
+
+	// Aliases may set their own method, so each target starts from the flag
+	defaultMethod := method
+	var jar http.CookieJar
+	sessions := make([]*watchSession, 0, len(targets))
+	for _, target := range targets {
+		method = defaultMethod
+		url, aliasHeaders := resolveTarget(cmd, target)
+		if !isValidURL(url) {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: URL must start with http:// or https:// (%s)", target)))
+			os.Exit(ExitError)
+		}
+		url = withQueryParams(url)
+
+		// All targets share one cookie jar, like one browser session
+		opts := requestOptions(config.MergeHeaders(aliasHeaders, fileHeaders, inlineHeaders))
+		if jar == nil {
+			jar = opts.Jar
+			if jar == nil {
+				jar = request.NewCookieJar()
+			}
+		}
+		opts.Jar = jar
+
+		sessions = append(sessions, newWatchSession(url, opts))
+	}
+
+	// Alerts, metrics and the log are shared; all are safe for concurrent use
+	alerts := openWatchAlerts()
+	sink := openMetrics()
+	log := openWatchLog()
+	for _, session := range sessions {
+		session.alerts = alerts
+		if alerts != nil {
+			session.health = alerts.Hysteresis()
+		}
+		session.metrics = sink
+		session.log = log
+	}
+
+	startTime := time.Now()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// One goroutine per target; mu guards every session and the screen
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		wg.Add(1)
+		go func(session *watchSession) {
+			defer wg.Done()
+			watchTarget(ctx, session, &mu, func() {
+				if resultStream == nil {
+					displayMultiWatchStats(sessions)
+				}
+			})
+		}(session)
+	}
+
+	// Run until every target reached --count, or Ctrl+C
+	wg.Wait()
+	duration := time.Since(startTime)
+
+	saveCookieJar(jar)
+	if alerts != nil {
+		alerts.Wait()
+	}
+	closeMetrics(sink)
+	if log != nil {
+		log.Close()
+	}
+
+	if resultStream == nil {
+		displayMultiWatchSummary(sessions, duration)
+	}
+
+	// Watch only sets a failure exit code under an explicit policy
+	requests, failures := 0, 0
+	for _, session := range sessions {
+		requests += session.requestCount
+		failures += session.failures
+	}
+	code := ExitSuccess
+	if policy := sessions[0].policy; policy.active() {
+		code = policy.exitCode(requests, failures)
+		if code != ExitSuccess && resultStream == nil {
+			output.Printf("%s\n", output.Red(fmt.Sprintf("✗ Exit policy not met: %d of %d requests failed", failures, requests)))
+		}
+	}
+
+	now := time.Now()
+	urls := make([]string, len(sessions))
+	targetSummaries := make([]output.TargetSummary, len(sessions))
+	for i, session := range sessions {
+		urls[i] = session.url
+		targetSummary := output.TrackerSummary(session.tracker).SummaryStats
+		targetSummary.Availability = output.UptimeSummary(&session.uptime, watchSLO, now)
+		targetSummary.Flaps = session.health.Flaps()
+		targetSummary.Flapping = session.health.Flapping()
+		if session.anomalies != nil {
+			targetSummary.Anomalies = session.anomalies.Anomalies()
+		}
+		targetSummaries[i] = output.TargetSummary{Target: session.url, SummaryStats: targetSummary}
+	}
+	summary := output.CombineTargets(targetSummaries)
+	code = gateBaseline("watch", strings.Join(urls, ", "), summary, code)
+	writeSummary("watch", strings.Join(urls, ", "), summary, code)
+	os.Exit(code)
+}
+
+// watchTarget checks one target of a multi-URL watch until it reaches
+// --count or ctx is canceled, which also cuts short a request in flight.
+// Requests run without holding mu, so a slow endpoint doesn't hold up the
+// others; refresh is called with mu held.
+func watchTarget(ctx context.Context, session *watchSession, mu *sync.Mutex, refresh func()) {
+	timer := time.NewTimer(0) // First request immediately
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		result := request.PingWithClientContext(ctx, session.client, session.url, session.opts)
+		if ctx.Err() != nil {
+			return
+		}
+
+		mu.Lock()
+		recordWatchResult(session, result)
+		refresh()
+		next := session.schedule.next(session)
+		mu.Unlock()
+
+		if watchCount > 0 && session.requestCount >= watchCount {
+			return
+		}
+		timer.Reset(next)
+	}
+}
+
+// displayMultiWatchStats shows the live table of a multi-URL watch.
+func displayMultiWatchStats(sessions []*watchSession) {
+	output.ClearScreen()
+
+	requests := 0
+	for _, session := range sessions {
+		requests += session.tracker.Total
+	}
+	output.Printf("\n📈 Live Stats (%d endpoints, %d requests)\n\n", len(sessions), requests)
+
+	table := output.NewTable(
+		output.Column{Header: "ENDPOINT", Flex: true, Middle: true, Min: 16},
+		output.Column{Header: "REQS", Right: true},
+		output.Column{Header: "SUCCESS", Right: true},
+		output.Column{Header: "LAST", Right: true},
+		output.Column{Header: "AVG", Right: true},
+		output.Column{Header: "P95", Right: true},
+		output.Column{Header: "STATUS"},
+	)
+	table.Indent = "   "
+	table.Width = tableWidth()
+
+	for _, session := range sessions {
+		tracker := session.tracker
+		last := session.history.GetRecent(1)
+
+		lastLatency, status := "-", "-"
+		if len(last) > 0 {
+			result := last[0].Result
+			lastLatency = roundLatency(result.Latency)
+			status = multiWatchStatus(result, session.failing, session.health.Flapping())
+		}
+		p95 := "-"
+		if tracker.Total >= 2 {
+			p95 = roundLatency(tracker.Percentile(0.95))
+		}
+
+		table.AddRow(
+			displayURL(session.url),
+			fmt.Sprintf("%d", tracker.Total),
+			successRateColor(tracker.SuccessRate())(fmt.Sprintf("%.1f%%", tracker.SuccessRate())),
+			lastLatency,
+			roundLatency(tracker.AvgLatency()),
+			p95,
+			status)
+
+		if session.breaker != nil && session.breaker.Open() {
+			table.AddTitle(output.Red(fmt.Sprintf("  ↳ circuit open, probing every %s", session.breaker.Interval(watchInterval))))
+		}
+	}
+	output.Print(table.Render())
+
+	output.Printf("\n%s\n", output.Blue("Press Ctrl+C to stop..."))
+}
+
+// displayMultiWatchSummary shows the final table of a multi-URL watch.
+func displayMultiWatchSummary(sessions []*watchSession, duration time.Duration) {
+	output.ClearScreen()
+
+	output.Printf("\n%s", output.Box(tableWidth(), output.Blue("📋")+" Watch Summary"))
+
+	requests, successful := 0, 0
+	failures := make(map[string]int)
+	for _, session := range sessions {
+		requests += session.tracker.Total
+		successful += session.tracker.Successful
+		for category, count := range session.tracker.Errors {
+			failures[category] += count
+		}
+	}
+
+	output.Printf("🎯 Endpoints\n")
+	output.Printf("   Endpoints: %d\n", len(sessions))
+	output.Printf("   Method:    %s\n", method)
+	output.Printf("   Duration:  %s\n", duration.Round(time.Second))
+	output.Printf("   Requests:  %d\n", requests)
+	output.Println()
+
+	now := time.Now()
+	output.Printf("📊 Results\n")
+	table := output.NewTable(
+		output.Column{Header: "ENDPOINT", Flex: true, Middle: true, Min: 16},
+		output.Column{Header: "REQS", Right: true},
+		output.Column{Header: "SUCCESS", Right: true},
+		output.Column{Header: "AVG", Right: true},
+		output.Column{Header: "P95", Right: true},
+		output.Column{Header: "MAX", Right: true},
+		output.Column{Header: "UPTIME", Right: true},
+	)
+	table.Indent = "   "
+	table.Width = tableWidth()
+	for _, session := range sessions {
+		tracker := session.tracker
+		p95 := "-"
+		if tracker.Total >= 2 {
+			p95 = roundLatency(tracker.Percentile(0.95))
+		}
+		table.AddRow(
+			displayURL(session.url),
+			fmt.Sprintf("%d", tracker.Total),
+			successRateColor(tracker.SuccessRate())(fmt.Sprintf("%.1f%%", tracker.SuccessRate())),
+			roundLatency(tracker.AvgLatency()),
+			p95,
+			roundLatency(tracker.MaxLatency),
+			fmt.Sprintf("%.2f%%", session.uptime.Percent(now)))
+	}
+	output.Print(table.Render())
+	output.Println()
+
+	successRate := 0.0
+	if requests > 0 {
+		successRate = float64(successful) / float64(requests) * 100
+	}
+	output.Printf("   Success Rate:  %s (%d/%d)\n",
+		successRateColor(successRate)(fmt.Sprintf("%.1f%%", successRate)), successful, requests)
+	output.Printf("   Failed:        %s%s\n", output.Red(fmt.Sprintf("%d", requests-successful)), formatFailures(failures))
+	var flapped []string
+	for _, session := range sessions {
+		if flaps := session.health.Flaps(); flaps > 0 {
+			flapped = append(flapped, fmt.Sprintf("%s (%dx)", shortenURL(session.url, multiWatchURLWidth), flaps))
+		}
+	}
+	if len(flapped) > 0 {
+		output.Printf("   Flapping:      %s\n", output.Yellow(strings.Join(flapped, ", ")))
+	}
+	var anomalous []string
+	for _, session := range sessions {
+		if session.anomalies != nil && session.anomalies.Anomalies() > 0 {
+			anomalous = append(anomalous, fmt.Sprintf("%s (%dx)", shortenURL(session.url, multiWatchURLWidth), session.anomalies.Anomalies()))
+		}
+	}
+	if len(anomalous) > 0 {
+		output.Printf("   Anomalies:     %s\n", output.Yellow(strings.Join(anomalous, ", ")))
+	}
+	output.Println()
+
+	// Alerts raised during the session (the manager is shared)
+	if alertsSent := collectAlerts(sessions); len(alertsSent) > 0 {
+		output.Printf("🔔 Alerts\n")
+		for _, event := range alertsSent {
+			output.Printf("   %s  %s\n", event.Time.Format("15:04:05"), event.Summary())
+		}
+		output.Println()
+	}
+
+	// Final message
+	down, flapping := 0, 0
+	for _, session := range sessions {
+		if session.tracker.SuccessRate() < 80 {
+			down++
+		}
+		if session.health.Flapping() {
+			flapping++
+		}
+	}
+	if successful == requests {
+		output.Printf("%s\n", output.Green("✓ All requests successful! All endpoints are healthy."))
+	} else if down == 0 && flapping > 0 {
+		output.Printf("%s\n", output.Yellow(fmt.Sprintf("↯ %d endpoint(s) keep flapping between up and down.", flapping)))
+	} else if down == 0 {
+		output.Printf("%s\n", output.Yellow("⚠️  Some failures detected. Some endpoints may be unstable."))
+	} else {
+		output.Printf("%s\n", output.Red(fmt.Sprintf("✗ %d endpoint(s) with a high failure rate need attention!", down)))
+	}
+}
+
+// multiWatchStatus describes the last result of a target for the table.
+// Flapping targets are marked whatever their last result.
+func multiWatchStatus(result request.Result, failing, flapping bool) string {
+	label := stats.StatusLabel(result)
+	if flapping {
+		return output.Yellow("↯ " + label + " (flapping)")
+	}
+	if failing {
+		return output.Red("✗ " + label)
+	}
+	return output.Green("✓ " + label)
+}
+
+// collectAlerts returns the alerts raised for every target, oldest first.
+func collectAlerts(sessions []*watchSession) []alert.Event {
+	var events []alert.Event
+	for _, session := range sessions {
+		events = append(events, session.alertsSent...)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+// successRateColor picks the color for a success rate, as in the single
+// endpoint view.
+func successRateColor(rate float64) func(string) string {
+	switch {
+	case rate == 100:
+		return output.Green
+	case rate >= 80:
+		return output.Yellow
+	}
+	return output.Red
+}
+
+// roundLatency formats a latency to a tenth of a millisecond.
+func roundLatency(latency time.Duration) string {
+	return latency.Round(100 * time.Microsecond).String()
+}
+
+// displayURL drops the scheme of a URL for display.
+func displayURL(url string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+}
+
+// shortenURL fits a URL into width characters, dropping the scheme first
+// and then the middle.
+func shortenURL(url string, width int) string {
+	url = displayURL(url)
+	if len(url) <= width {
+		return url
+	}
+	half := (width - 3) / 2
+	return url[:half] + "..." + url[len(url)-(width-3-half):]
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// Exit policy flags
+var (
+	failOn           []string // Conditions that fail the run (--fail-on)
+	successThreshold string   // Minimum success rate for watch and batch (--success-threshold)
+)
+
+// Conditions accepted by --fail-on.
+const (
+	failOn5xx   = "5xx"   // Server error responses
+	failOn4xx   = "4xx"   // Client error responses
+	failOnSlow  = "slow"  // Responses over the latency limit
+	failOnCheck = "check" // Failed expectations (status, headers, values)
+	failOnAny   = "any"   // All of the above
+)
+
+// exitPolicy decides which requests count as failed for the exit code and
+// how many failures a run tolerates. The zero value keeps each command's
+// built-in behavior.
+type exitPolicy struct {
+	conditions map[string]bool // Empty: the command's own notion of failure
+	threshold  float64         // Minimum success rate in percent (0 = no failures allowed)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringSliceVar(
+		&failOn,
+		"fail-on",
+		[]string{},
+		"Fail the run on these conditions: 5xx, 4xx, slow, check, any (requests without a response always fail)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&successThreshold,
+		"success-threshold",
+		"",
+		"Exit successfully if at least this share of watch/batch requests pass (e.g., 95%)",
+	)
+}
+
+// parseExitPolicy builds the exit policy from --fail-on and
+// --success-threshold.
+func parseExitPolicy(conditions []string, threshold string) (exitPolicy, error) {
+	policy := exitPolicy{conditions: make(map[string]bool, len(conditions))}
+
+	for _, condition := range conditions {
+		condition = strings.ToLower(strings.TrimSpace(condition))
+		switch condition {
+		case failOn5xx, failOn4xx, failOnSlow, failOnCheck:
+			policy.conditions[condition] = true
+		case failOnAny:
+			for _, c := range []string{failOn5xx, failOn4xx, failOnSlow, failOnCheck} {
+				policy.conditions[c] = true
+			}
+		default:
+			return policy, fmt.Errorf("invalid --fail-on condition '%s' (expected 5xx, 4xx, slow, check or any)", condition)
+		}
+	}
+
+	if threshold != "" {
+		value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(threshold), "%"), 64)
+		if err != nil || value <= 0 || value > 100 {
+			return policy, fmt.Errorf("invalid --success-threshold '%s' (expected a percentage like 95%%)", threshold)
+		}
+		policy.threshold = value
+	}
+
+	return policy, nil
+}
+
+// resolveExitPolicy parses the policy flags, exiting on invalid values.
+func resolveExitPolicy() exitPolicy {
+	policy, err := parseExitPolicy(failOn, successThreshold)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	return policy
+}
+
+// active reports whether any policy flag was given.
+func (p exitPolicy) active() bool {
+	return len(p.conditions) > 0 || p.threshold > 0
+}
+
+// failed reports whether a request counts as failed. success is the
+// command's own verdict and category the failure category of the request
+// (see request.Classify), used when --fail-on is given.
+func (p exitPolicy) failed(result request.Result, category string, success bool) bool {
+	if result.Error != nil {
+		return true
+	}
+	if len(p.conditions) == 0 {
+		return !success
+	}
+
+	switch {
+	case p.conditions[failOn5xx] && result.StatusCode >= 500:
+		return true
+	case p.conditions[failOn4xx] && result.StatusCode >= 400 && result.StatusCode < 500:
+		return true
+	case p.conditions[failOnSlow] && category == request.CategorySlow:
+		return true
+	case p.conditions[failOnCheck] && !success && category != request.CategorySlow:
+		return true
+	}
+	return false
+}
+
+// exitCode returns the exit code for a run of total requests of which
+// failed counted as failures.
+func (p exitPolicy) exitCode(total, failed int) int {
+	if p.threshold > 0 && total > 0 {
+		if float64(total-failed)/float64(total)*100 >= p.threshold {
+			return ExitSuccess
+		}
+		return ExitFailure
+	}
+	if failed > 0 {
+		return ExitFailure
+	}
+	return ExitSuccess
+}
+
+// batchExitCode applies the exit policy to a batch run. Failures during
+// maintenance (see applyMaintenance) don't count, nor do endpoints degraded
+// by a failed dependency, which counts itself.
+func batchExitCode(summary *stats.BatchSummary) int {
+	policy := resolveExitPolicy()
+
+	failed := 0
+	for _, result := range summary.Results {
+		if result.Silenced != "" || result.Upstream != "" {
+			continue
+		}
+		if policy.failed(result.Result, result.Category, result.Success) {
+			failed++
+		}
+	}
+	return policy.exitCode(summary.Total, failed)
+}
+
+// stops reports whether a failed ping check of the given category should
+// end the run with a failure exit code.
+func (p exitPolicy) stops(category string) bool {
+	if len(p.conditions) == 0 {
+		return true
+	}
+	if category == request.CategorySlow {
+		return p.conditions[failOnSlow]
+	}
+	return p.conditions[failOnCheck]
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+func TestParseExitPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []string
+		threshold  string
+		want       []string
+		wantRate   float64
+		wantErr    bool
+	}{
+		{name: "none", want: nil},
+		{name: "classes", conditions: []string{"5xx", "4xx"}, want: []string{failOn5xx, failOn4xx}},
+		{name: "case and spaces", conditions: []string{" 5XX ", "Slow"}, want: []string{failOn5xx, failOnSlow}},
+		{name: "any", conditions: []string{"any"}, want: []string{failOn5xx, failOn4xx, failOnSlow, failOnCheck}},
+		{name: "threshold", threshold: "95%", wantRate: 95},
+		{name: "threshold without percent", threshold: "99.5", wantRate: 99.5},
+		{name: "unknown condition", conditions: []string{"3xx"}, wantErr: true},
+		{name: "status code", conditions: []string{"503"}, wantErr: true},
+		{name: "empty condition", conditions: []string{""}, wantErr: true},
+		{name: "zero threshold", threshold: "0%", wantErr: true},
+		{name: "threshold over 100", threshold: "101%", wantErr: true},
+		{name: "invalid threshold", threshold: "most", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := parseExitPolicy(tt.conditions, tt.threshold)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExitPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(policy.conditions) != len(tt.want) {
+				t.Errorf("conditions = %v, want %v", policy.conditions, tt.want)
+			}
+			for _, condition := range tt.want {
+				if !policy.conditions[condition] {
+					t.Errorf("conditions = %v, missing %s", policy.conditions, condition)
+				}
+			}
+			if policy.threshold != tt.wantRate {
+				t.Errorf("threshold = %v, want %v", policy.threshold, tt.wantRate)
+			}
+			if policy.active() != (len(tt.want) > 0 || tt.wantRate > 0) {
+				t.Errorf("active() = %v", policy.active())
+			}
+		})
+	}
+}
+
+func TestExitPolicyFailed(t *testing.T) {
+	status := func(code int) request.Result { return request.Result{StatusCode: code} }
+
+	tests := []struct {
+		name       string
+		conditions []string
+		result     request.Result
+		category   string
+		success    bool
+		want       bool
+	}{
+		// Without --fail-on, the command's own verdict decides
+		{name: "default pass", result: status(200), success: true, want: false},
+		{name: "default fail", result: status(404), category: request.CategoryClient, want: true},
+		{name: "no response always fails", conditions: []string{"5xx"}, result: request.Result{Error: errors.New("refused")}, want: true},
+
+		// Status classes, at the edges of their ranges
+		{name: "5xx at 500", conditions: []string{"5xx"}, result: status(500), want: true},
+		{name: "5xx at 599", conditions: []string{"5xx"}, result: status(599), want: true},
+		{name: "5xx ignores 499", conditions: []string{"5xx"}, result: status(499), category: request.CategoryClient, want: false},
+		{name: "4xx at 400", conditions: []string{"4xx"}, result: status(400), want: true},
+		{name: "4xx at 499", conditions: []string{"4xx"}, result: status(499), want: true},
+		{name: "4xx ignores 399", conditions: []string{"4xx"}, result: status(399), want: false},
+		{name: "4xx ignores 500", conditions: []string{"4xx"}, result: status(500), category: request.CategoryServer, want: false},
+
+		// Slow responses and failed checks
+		{name: "slow", conditions: []string{"slow"}, result: status(200), category: request.CategorySlow, want: true},
+		{name: "slow only", conditions: []string{"slow"}, result: status(200), category: request.CategoryAssertion, want: false},
+		{name: "check", conditions: []string{"check"}, result: status(200), category: request.CategoryAssertion, want: true},
+		{name: "check ignores slow", conditions: []string{"check"}, result: status(200), category: request.CategorySlow, want: false},
+		{name: "any", conditions: []string{"any"}, result: status(302), category: request.CategoryAssertion, want: true},
+		{name: "any passes success", conditions: []string{"any"}, result: status(200), success: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := parseExitPolicy(tt.conditions, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := policy.failed(tt.result, tt.category, tt.success); got != tt.want {
+				t.Errorf("failed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitPolicyExitCode(t *testing.T) {
+	tests := []struct {
+		threshold     string
+		total, failed int
+		want          int
+	}{
+		{threshold: "", total: 10, failed: 0, want: ExitSuccess},
+		{threshold: "", total: 10, failed: 1, want: ExitFailure},
+		{threshold: "90%", total: 10, failed: 1, want: ExitSuccess},
+		{threshold: "90%", total: 10, failed: 2, want: ExitFailure},
+		{threshold: "90%", total: 0, failed: 0, want: ExitSuccess},
+	}
+
+	for _, tt := range tests {
+		policy, err := parseExitPolicy(nil, tt.threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := policy.exitCode(tt.total, tt.failed); got != tt.want {
+			t.Errorf("exitCode(%d, %d) with threshold %q = %d, want %d", tt.total, tt.failed, tt.threshold, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// Connection pool flags
+var (
+	noKeepAlive     bool // Open a new connection for every batch request
+	maxIdleConns    int  // Idle connections kept for reuse
+	maxConnsPerHost int  // Connections per host, active or idle (0 = unlimited)
+	tlsSessionCache int  // TLS sessions cached for resumption (0 = off)
+)
+
+// connPool is shared by every batch request of the run; see batchPool.
+var (
+	connPool     *request.Pool
+	connPoolOnce sync.Once
+)
+
+func init() {
+	batchCmd.Flags().BoolVar(
+		&noKeepAlive,
+		"no-keepalive",
+		false,
+		"Open a new connection for every request instead of reusing pooled ones",
+	)
+
+	batchCmd.Flags().IntVar(
+		&maxIdleConns,
+		"max-idle-conns",
+		request.DefaultMaxIdleConns,
+		"Idle connections kept open for reuse",
+	)
+
+	batchCmd.Flags().IntVar(
+		&maxConnsPerHost,
+		"max-conns-per-host",
+		0,
+		"Limit connections per host, active or idle (0 = unlimited)",
+	)
+
+	batchCmd.Flags().IntVar(
+		&tlsSessionCache,
+		"tls-session-cache",
+		request.DefaultTLSSessions,
+		"TLS sessions cached for resumption (0 = full handshake every time)",
+	)
+}
+
+// batchPool returns the connection pool shared by batch requests, created
+// from the flags on first use. It exits on invalid flag values.
+func batchPool() *request.Pool {
+	connPoolOnce.Do(func() {
+		if maxIdleConns < 1 || maxConnsPerHost < 0 || tlsSessionCache < 0 {
+			fmt.Fprintln(output.Stderr, output.Red("Error: --max-idle-conns must be positive, --max-conns-per-host and --tls-session-cache not negative"))
+			os.Exit(ExitError)
+		}
+
+		sessions := tlsSessionCache
+		if sessions == 0 {
+			sessions = -1 // The pool's "no cache"
+		}
+		connPool = request.NewPool(request.PoolOptions{
+			MaxIdleConns:    maxIdleConns,
+			MaxConnsPerHost: maxConnsPerHost,
+			NoKeepAlive:     noKeepAlive,
+			TLSSessions:     sessions,
+		})
+	})
+	return connPool
+}
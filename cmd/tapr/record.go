@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/recording"
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+var (
+	recordArchive string // Archive file written by record
+	recordBodies  bool   // Read every response body so it can be recorded
+	replayBaseURL string // Base URL that replayed requests are sent to
+)
+
+// recordCmd represents the record command for capturing request/response pairs
+var recordCmd = &cobra.Command{
+	Use:   "record [config]",
+	Short: "Run a batch config and record every request and response",
+	Long: `Record runs a batch config exactly like 'tapr batch' and saves every request
+that was sent, together with the full response (status, headers and body),
+to a local archive. The archive can be replayed later with 'tapr replay',
+against the same deployment or a different one.
+
+Requests are recorded in the order they completed, so endpoints that depend
+on others are replayed after them. The archive contains the headers that
+were sent, including credentials: keep it private.
+
+Perfect for:
+  • Regression checks between deployments (record on prod, replay on canary)
+  • Capturing a known-good baseline before a migration`,
+	Example: `  tapr record endpoints.yml --archive prod.ndjson
+  tapr replay prod.ndjson --base-url https://canary.example.com`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRecord,
+}
+
+// replayCmd represents the replay command for re-running recorded requests
+var replayCmd = &cobra.Command{
+	Use:   "replay [archive]",
+	Short: "Re-send recorded requests and compare the responses",
+	Long: `Replay sends every request of an archive written by 'tapr record' again, one
+at a time and in recorded order, and compares each response with the
+recorded one: status, headers and body (structurally for JSON).
+
+With --base-url, requests are sent to another deployment: the scheme and host
+of each recorded URL are replaced, and the path of the base URL is prepended.
+Headers that change on every response (Date, Set-Cookie, request IDs, ...)
+are ignored. Exits with code 1 if any response differs.`,
+	Example: `  tapr replay prod.ndjson
+  tapr replay prod.ndjson --base-url https://canary.example.com
+  tapr replay prod.ndjson --base-url http://localhost:8080/v2 --ignore-header Server`,
+	Args: cobra.ExactArgs(1),
+	Run:  runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(replayCmd)
+
+	recordCmd.Flags().StringVarP(
+		&recordArchive,
+		"archive",
+		"a",
+		"tapr-recording.ndjson",
+		"Archive file to write the recorded exchanges to",
+	)
+
+	recordCmd.Flags().IntVarP(
+		&batchConcurrency,
+		"concurrency",
+		"c",
+		0,
+		"Number of concurrent requests (overrides config)",
+	)
+
+	replayCmd.Flags().StringVar(
+		&replayBaseURL,
+		"base-url",
+		"",
+		"Send the requests to this base URL instead of the recorded one",
+	)
+
+	replayCmd.Flags().StringSliceVar(
+		&diffIgnoreHeaders,
+		"ignore-header",
+		[]string{},
+		"Response header to leave out of the comparison, repeatable",
+	)
+}
+
+// runRecord executes the record command.
+func runRecord(cmd *cobra.Command, args []string) {
+	configFile := args[0]
+	summaryTarget = configFile
+
+	batchConfig, err := config.LoadBatchConfig(configFile)
+	if err != nil {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading batch config: %v", err)))
+		}
+		os.Exit(ExitError)
+	}
+	if err := expandDiscovery(batchConfig); err != nil {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		}
+		os.Exit(ExitError)
+	}
+
+	resolveHTTPVersion()
+	resolveExitPolicy()
+	if batchConcurrency > 0 {
+		batchConfig.Concurrency = batchConcurrency
+	}
+
+	// Response bodies are only read when a check needs them; a recording
+	// needs all of them
+	recordBodies = true
+
+	startTime := time.Now()
+	sink := openMetrics()
+	summary := runBatchTests(batchConfig, sink)
+	summary.TotalTime = time.Since(startTime)
+	closeMetrics(sink)
+
+	exchanges := make([]recording.Exchange, 0, len(summary.Results))
+	for _, result := range summary.Results {
+		if wasSent(result) {
+			exchanges = append(exchanges, recordExchange(result, startTime))
+		}
+	}
+	if err := recording.Write(recordArchive, exchanges); err != nil {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		}
+		os.Exit(ExitError)
+	}
+
+	if !quiet && !silent && outputFormat == "pretty" {
+		output.Printf("\n📼 Recorded %d exchanges to %s\n", len(exchanges), recordArchive)
+	}
+
+	displayBatchResults(summary, configFile)
+}
+
+// recordExchange converts a batch result into an archive entry.
+func recordExchange(result stats.BatchResult, recorded time.Time) recording.Exchange {
+	exchange := recording.Exchange{
+		Time: recorded,
+		Name: result.Name,
+		Request: recording.Request{
+			Method:  result.Method,
+			URL:     result.Result.URL,
+			Headers: result.RequestHeaders,
+			Body:    result.RequestBody,
+		},
+		Response: recording.Response{
+			Status:    result.Result.StatusCode,
+			Headers:   result.Result.Headers,
+			LatencyMs: float64(result.Result.Latency.Microseconds()) / 1000,
+		},
+	}
+	exchange.Response.SetBody(result.Result.Body)
+	if result.Result.Error != nil {
+		exchange.Response.Error = result.Result.Error.Error()
+	}
+	return exchange
+}
+
+// runReplay executes the replay command.
+func runReplay(cmd *cobra.Command, args []string) {
+	archive := args[0]
+
+	exchanges, err := recording.Load(archive)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	// Rebase every URL up front so a bad --base-url fails before any request
+	targets := make([]string, len(exchanges))
+	for i, exchange := range exchanges {
+		targets[i] = exchange.Request.URL
+		if replayBaseURL != "" {
+			if targets[i], err = recording.Rebase(exchange.Request.URL, replayBaseURL); err != nil {
+				fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+				os.Exit(ExitError)
+			}
+		}
+	}
+
+	output.Printf("🔁 Replaying %d exchanges from %s\n", len(exchanges), archive)
+	if replayBaseURL != "" {
+		output.Printf("   against %s\n", output.Blue(replayBaseURL))
+	}
+
+	changed := 0
+	for i, exchange := range exchanges {
+		output.Println()
+		if replayExchange(exchange, targets[i]) > 0 {
+			changed++
+		}
+	}
+
+	output.Println()
+	if changed == 0 {
+		output.Printf("%s\n", output.Green(fmt.Sprintf("✓ All %d responses match the recording", len(exchanges))))
+		return
+	}
+	output.Printf("%s\n", output.Red(fmt.Sprintf("✗ %d of %d responses differ from the recording", changed, len(exchanges))))
+	os.Exit(ExitFailure)
+}
+
+// replayExchange re-sends a recorded request to target, prints how the
+// response compares with the recorded one and returns the number of
+// differences.
+func replayExchange(exchange recording.Exchange, target string) int {
+	label := exchange.Request.Method + " " + target
+	if exchange.Name != "" {
+		label = exchange.Name + ": " + label
+	}
+	output.Println(output.Cyan("▸ " + label))
+
+	opts := request.PingOptions{
+		Method:      exchange.Request.Method,
+		Timeout:     timeout,
+		Headers:     exchange.Request.Headers,
+		UserAgent:   userAgent,
+		Resolve:     resolveHostOverrides(),
+		DNSServer:   dnsServer,
+		LocalAddr:   resolveLocalAddr(),
+		IPVersion:   resolveIPVersion(),
+		HTTPVersion: resolveHTTPVersion(),
+		ReadBody:    true,
+	}
+	if exchange.Request.Body != "" {
+		opts.Body = []byte(exchange.Request.Body)
+	}
+	applyTraceHTTP(&opts)
+	result := request.Ping(target, opts)
+
+	recorded := exchange.Response
+	if result.Error != nil || recorded.Error != "" {
+		if recorded.Error != "" && result.Error != nil {
+			output.Printf("  Error:    failed as recorded %s\n", output.Green("✓"))
+			return 0
+		}
+		was, now := recorded.Error, result.Status
+		if was == "" {
+			was = fmt.Sprintf("%d", recorded.Status)
+		}
+		if result.Error != nil {
+			now = result.Error.Error()
+		}
+		output.Printf("  Result:   %s → %s %s\n", was, now, output.Red("✗"))
+		return 1
+	}
+
+	differences := 0
+	if result.StatusCode == recorded.Status {
+		output.Printf("  Status:   %s %s\n", result.Status, output.Green("✓"))
+	} else {
+		output.Printf("  Status:   %d → %s %s\n", recorded.Status, result.Status, output.Red("✗"))
+		differences++
+	}
+
+	// Latency is reported but never counted as a difference
+	output.Printf("  Latency:  %s → %s (%s)\n", formatLatency(recorded.Latency()), formatLatency(result.Latency), latencyChange(recorded.Latency(), result.Latency))
+
+	differences += displayHeaderDiff(recorded.Headers, result.Headers)
+
+	body, err := recorded.BodyBytes()
+	if err != nil {
+		output.Printf("  Body:     unreadable in archive (%v) %s\n", err, output.Red("✗"))
+		return differences + 1
+	}
+	return differences + displayBodyDiff(body, result.Body)
+}
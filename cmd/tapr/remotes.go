@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/remote"
+)
+
+// batchRemotes runs the batch on the config's remotes too (--remotes)
+var batchRemotes bool
+
+// remoteFlags are the batch flags passed on to remote runs when set.
+var remoteFlags = []string{"retries", "fail-fast", "max-time", "max-latency", "fail-on"}
+
+func init() {
+	batchCmd.Flags().BoolVar(
+		&batchRemotes,
+		"remotes",
+		false,
+		"Also run the batch on every host under remotes: (over SSH) and compare regions",
+	)
+}
+
+// jsonRegion is one region in the JSON output of --remotes.
+type jsonRegion struct {
+	Name       string                  `json:"name"`
+	DurationMs int64                   `json:"duration_ms"`
+	Error      string                  `json:"error,omitempty"`
+	Result     *output.JSONBatchResult `json:"result,omitempty"`
+}
+
+// runRemoteBatch runs the batch here and on every remote at the same time,
+// prints the results per region and exits. It fails if any endpoint fails
+// in any region or the batch couldn't run on a remote.
+func runRemoteBatch(cmd *cobra.Command, batchConfig *config.BatchConfig) {
+	if len(batchConfig.Remotes) == 0 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --remotes needs a remotes: list in the batch config"))
+		os.Exit(ExitError)
+	}
+	if outputFormat != "pretty" && outputFormat != "json" {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: --remotes supports pretty and json output, not %s", outputFormat)))
+		os.Exit(ExitError)
+	}
+	standalone, err := batchConfig.Standalone()
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	var extra []string
+	for _, name := range remoteFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			extra = append(extra, "--"+name+"="+flag.Value.String())
+		}
+	}
+
+	if !quiet && !silent && outputFormat == "pretty" {
+		names := []string{config.LocalRemote}
+		for _, r := range batchConfig.Remotes {
+			names = append(names, r.Name)
+		}
+		output.Printf("\n🌍 Running batch: %d endpoints from %d regions (%s)\n\n",
+			len(batchConfig.Endpoints), len(names), strings.Join(names, ", "))
+	}
+
+	// Run everywhere at once; the local run is the first region
+	regions := make([]remote.Region, len(batchConfig.Remotes)+1)
+	var wg sync.WaitGroup
+	for i, r := range batchConfig.Remotes {
+		wg.Add(1)
+		go func(i int, r config.Remote) {
+			defer wg.Done()
+			regions[i+1] = remote.Run(context.Background(), r, standalone, extra...)
+		}(i, r)
+	}
+	start := time.Now()
+	sink := openMetrics()
+	local := runBatchTests(batchConfig, sink)
+	local.TotalTime = time.Since(start)
+	closeMetrics(sink)
+	localResult := output.NewJSONBatchResult(local)
+	regions[0] = remote.Region{Name: config.LocalRemote, Result: &localResult, Duration: local.TotalTime}
+	wg.Wait()
+
+	code := ExitSuccess
+	for _, region := range regions {
+		if region.Error != nil || region.Result.Failed > 0 {
+			code = ExitFailure
+		}
+	}
+
+	switch {
+	case outputFormat == "json":
+		printRegionsJSON(regions)
+	case silent:
+	case quiet:
+		for _, region := range regions {
+			if region.Error != nil {
+				fmt.Fprintf(output.Stderr, "%s %s: %v\n", output.Red("✗"), region.Name, region.Error)
+			}
+		}
+	default:
+		printRegions(regions, batchConfig.Endpoints)
+	}
+	exitBatch(local, code)
+}
+
+// printRegionsJSON prints the results of every region as one JSON document.
+func printRegionsJSON(regions []remote.Region) {
+	out := struct {
+		Regions []jsonRegion `json:"regions"`
+	}{}
+	for _, region := range regions {
+		entry := jsonRegion{Name: region.Name, DurationMs: region.Duration.Milliseconds(), Result: region.Result}
+		if region.Error != nil {
+			entry.Error = region.Error.Error()
+		}
+		out.Regions = append(out.Regions, entry)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		fmt.Fprintf(output.Stderr, "Error formatting JSON: %v\n", err)
+		os.Exit(ExitError)
+	}
+	fmt.Println(string(data))
+}
+
+// printRegions prints a summary per region, then the latency of every
+// endpoint from each region that ran the batch.
+func printRegions(regions []remote.Region, endpoints []config.Endpoint) {
+	output.Printf("%-16s %-8s %-13s %-12s %s\n", "REGION", "PASSED", "AVAILABILITY", "AVG LATENCY", "TIME")
+	output.Printf("%s\n", strings.Repeat("─", 65))
+	for _, region := range regions {
+		name := truncate(region.Name, 16)
+		if region.Error != nil {
+			output.Printf("%-16s %s %v\n", name, output.Red("✗"), region.Error)
+			continue
+		}
+		result := region.Result
+		passed := fmt.Sprintf("%d/%d", result.Successful, result.Total)
+		availability := fmt.Sprintf("%.1f%%", result.SuccessRate)
+		if result.Failed > 0 {
+			availability = output.Red(fmt.Sprintf("%-13s", availability))
+		} else {
+			availability = output.Green(fmt.Sprintf("%-13s", availability))
+		}
+		output.Printf("%-16s %-8s %s %-12s %s\n", name, passed, availability,
+			formatMillis(result.AvgLatency), region.Duration.Round(time.Millisecond))
+	}
+	output.Println()
+
+	// One column per region that has results, one row per endpoint in
+	// config order. Results arrive in completion order, so they're matched
+	// up by name and URL.
+	var ran []remote.Region
+	for _, region := range regions {
+		if region.Result != nil {
+			ran = append(ran, region)
+		}
+	}
+	order := make(map[string]int, len(endpoints))
+	for i, endpoint := range endpoints {
+		if _, seen := order[endpoint.Name]; !seen {
+			order[endpoint.Name] = i
+		}
+	}
+	rows := append([]output.JSONEndpoint{}, ran[0].Result.Results...)
+	sort.SliceStable(rows, func(i, j int) bool {
+		return order[rows[i].Name] < order[rows[j].Name]
+	})
+
+	output.Printf("%-20s", "ENDPOINT")
+	for _, region := range ran {
+		output.Printf(" %-12s", strings.ToUpper(truncate(region.Name, 12)))
+	}
+	output.Printf("\n%s\n", strings.Repeat("─", 20+13*len(ran)))
+	for _, row := range rows {
+		output.Printf("%-20s", truncate(row.Name, 20))
+		for _, region := range ran {
+			cell := fmt.Sprintf("%-12s", "-")
+			for _, endpoint := range region.Result.Results {
+				if endpoint.Name == row.Name && endpoint.URL == row.URL {
+					cell = regionCell(endpoint)
+					break
+				}
+			}
+			output.Printf(" %s", cell)
+		}
+		output.Println()
+	}
+	output.Println()
+
+	failures, unreachable := 0, 0
+	for _, region := range regions {
+		if region.Error != nil {
+			unreachable++
+		} else {
+			failures += region.Result.Failed
+		}
+	}
+	switch {
+	case failures == 0 && unreachable == 0:
+		output.Printf("%s All endpoints pass from all %d regions\n", output.Green("✓"), len(regions))
+	case unreachable > 0:
+		output.Printf("%s %d failure(s); batch couldn't run in %d of %d regions\n", output.Red("✗"), failures, unreachable, len(regions))
+	default:
+		output.Printf("%s %d failure(s) across %d regions\n", output.Red("✗"), failures, len(regions))
+	}
+}
+
+// regionCell formats one endpoint's result from one region, padded to the
+// column width.
+func regionCell(endpoint output.JSONEndpoint) string {
+	switch {
+	case endpoint.Success:
+		return fmt.Sprintf("%-12s", formatMillis(endpoint.Latency))
+	case endpoint.Status == 0 && strings.HasPrefix(endpoint.Error, "Skipped"):
+		return output.Yellow(fmt.Sprintf("%-12s", "– skipped"))
+	case endpoint.Status == 0:
+		return output.Red(fmt.Sprintf("%-12s", "✗ error"))
+	default:
+		return output.Red(fmt.Sprintf("%-12s", fmt.Sprintf("✗ %d", endpoint.Status)))
+	}
+}
+
+// formatMillis formats a latency reported in whole milliseconds.
+func formatMillis(ms int64) string {
+	if ms == 0 {
+		return "<1ms"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// truncate shortens s to at most n characters, ending in "..." if cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// stateSaveInterval is how often a resumable watch saves its state, besides
+// once when it stops.
+const stateSaveInterval = 10 * time.Second
+
+// watchState is the part of a watch session saved with --resume.
+type watchState struct {
+	URL           string             `json:"url"`
+	Started       time.Time          `json:"started"`
+	Saved         time.Time          `json:"saved"`
+	Elapsed       time.Duration      `json:"elapsed_ns"` // Time spent watching across all runs
+	Requests      int                `json:"requests"`
+	Failures      int                `json:"failures"`
+	DownloadBytes int64              `json:"download_bytes"`
+	DownloadTime  time.Duration      `json:"download_time_ns"`
+	Tracker       stats.TrackerState `json:"tracker"`
+	TTFB          stats.TrackerState `json:"ttfb"`
+	Warm          stats.TrackerState `json:"warm"`
+	Cold          stats.TrackerState `json:"cold"`
+}
+
+// loadWatchState reads a saved session. A missing file starts a new
+// session and returns nil.
+func loadWatchState(path, url string) (*watchState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var state watchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	if state.URL != url {
+		return nil, fmt.Errorf("session file %s belongs to %s", path, state.URL)
+	}
+	return &state, nil
+}
+
+// restore continues the session from a saved state.
+func (s *watchSession) restore(state *watchState) {
+	s.started = state.Started
+	s.elapsed = state.Elapsed
+	s.requestCount = state.Requests
+	s.failures = state.Failures
+	s.downloadBytes = state.DownloadBytes
+	s.downloadTime = state.DownloadTime
+	s.tracker = stats.RestoreTracker(state.Tracker)
+	s.ttfb = stats.RestoreTracker(state.TTFB)
+	s.warm = stats.RestoreTracker(state.Warm)
+	s.cold = stats.RestoreTracker(state.Cold)
+}
+
+// checkpoint saves the session if --resume is set and the last save is
+// older than stateSaveInterval. runStart is when this run began.
+func (s *watchSession) checkpoint(runStart time.Time) {
+	if watchResume == "" || time.Since(s.savedAt) < stateSaveInterval {
+		return
+	}
+	if err := s.saveState(watchResume, time.Since(runStart)); err != nil {
+		slog.Warn(err.Error())
+	}
+	s.savedAt = time.Now()
+}
+
+// saveState writes the session to path, replacing the previous state
+// atomically so a crash mid-write can't corrupt it. runTime is how long
+// this run has been watching.
+func (s *watchSession) saveState(path string, runTime time.Duration) error {
+	state := watchState{
+		URL:           s.url,
+		Started:       s.started,
+		Saved:         time.Now(),
+		Elapsed:       s.elapsed + runTime,
+		Requests:      s.requestCount,
+		Failures:      s.failures,
+		DownloadBytes: s.downloadBytes,
+		DownloadTime:  s.downloadTime,
+		Tracker:       s.tracker.State(),
+		TTFB:          s.ttfb.State(),
+		Warm:          s.warm.State(),
+		Cold:          s.cold.State(),
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
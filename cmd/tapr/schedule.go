@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// Watch scheduling flags
+var (
+	watchJitter   string // Random variation of the interval (--jitter)
+	watchAdaptive bool   // Check more often while failing (--adaptive)
+)
+
+// adaptiveMinInterval is the shortest interval --adaptive shortens to.
+const adaptiveMinInterval = 500 * time.Millisecond
+
+// watchSchedule decides how long to wait between watch requests.
+type watchSchedule struct {
+	base     time.Duration // --interval
+	jitter   float64       // Maximum variation as a fraction of the interval
+	adaptive bool
+	random   *rand.Rand
+}
+
+func init() {
+	watchCmd.Flags().StringVar(
+		&watchJitter,
+		"jitter",
+		"",
+		"Randomize each interval by up to this share (e.g. 10%) or duration (e.g. 2s) so several watchers don't fire together",
+	)
+
+	watchCmd.Flags().BoolVar(
+		&watchAdaptive,
+		"adaptive",
+		false,
+		"Check four times as often while the endpoint is failing, to time its recovery precisely",
+	)
+}
+
+// parseJitter parses a jitter percentage such as "10%", or a duration such
+// as "2s", into a fraction of interval. Either must stay below the interval.
+func parseJitter(value string, interval time.Duration) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	invalid := fmt.Errorf("invalid --jitter '%s' (expected a percentage below 100%%, like 10%%, or a duration below the interval, like 2s)", value)
+
+	var fraction float64
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		parsed, err := strconv.ParseFloat(percent, 64)
+		if err != nil {
+			return 0, invalid
+		}
+		fraction = parsed / 100
+	} else {
+		duration, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return 0, invalid
+		}
+		fraction = float64(duration) / float64(interval)
+	}
+
+	// Also rejects NaN
+	if !(fraction >= 0 && fraction < 1) {
+		return 0, invalid
+	}
+	return fraction, nil
+}
+
+// resolveWatchSchedule builds the schedule from the watch flags, exiting on
+// invalid values.
+func resolveWatchSchedule() watchSchedule {
+	jitter, err := parseJitter(watchJitter, watchInterval)
+	if err == nil && watchAdaptive && watchBreaker > 0 {
+		err = fmt.Errorf("--adaptive and --breaker cannot be combined")
+	}
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	return watchSchedule{
+		base:     watchInterval,
+		jitter:   jitter,
+		adaptive: watchAdaptive,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// interval returns the wait before the next request without jitter: backed
+// off while the circuit breaker is open, shortened with --adaptive while
+// failing, and the base interval otherwise.
+func (s watchSchedule) interval(session *watchSession) time.Duration {
+	switch {
+	case session.breaker != nil:
+		return session.breaker.Interval(s.base)
+	case s.adaptive && session.failing:
+		return adaptiveInterval(s.base)
+	}
+	return s.base
+}
+
+// next returns the wait before the next request, including jitter.
+func (s watchSchedule) next(session *watchSession) time.Duration {
+	interval := s.interval(session)
+	if s.jitter == 0 {
+		return interval
+	}
+
+	// Uniform in [interval*(1-jitter), interval*(1+jitter)]
+	factor := 1 + s.jitter*(2*s.random.Float64()-1)
+	if jittered := time.Duration(float64(interval) * factor); jittered > 0 {
+		return jittered
+	}
+	return interval
+}
+
+// adaptiveInterval returns the shortened interval used while failing.
+func adaptiveInterval(base time.Duration) time.Duration {
+	interval := base / 4
+	if interval < adaptiveMinInterval {
+		interval = adaptiveMinInterval
+	}
+	if interval > base {
+		interval = base
+	}
+	return interval
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+func TestParseJitter(t *testing.T) {
+	tests := []struct {
+		value    string
+		interval time.Duration
+		want     float64
+		wantErr  bool
+	}{
+		{value: "", interval: 10 * time.Second, want: 0},
+		{value: "10%", interval: 10 * time.Second, want: 0.1},
+		{value: " 2.5% ", interval: 10 * time.Second, want: 0.025},
+		{value: "0%", interval: 10 * time.Second, want: 0},
+		{value: "2s", interval: 10 * time.Second, want: 0.2},
+		{value: "500ms", interval: time.Second, want: 0.5},
+		{value: "-10%", interval: 10 * time.Second, wantErr: true},
+		{value: "-2s", interval: 10 * time.Second, wantErr: true},
+		{value: "100%", interval: 10 * time.Second, wantErr: true},
+		{value: "10s", interval: 10 * time.Second, wantErr: true},
+		{value: "1e400%", interval: 10 * time.Second, wantErr: true},
+		{value: "Inf%", interval: 10 * time.Second, wantErr: true},
+		{value: "NaN%", interval: 10 * time.Second, wantErr: true},
+		{value: "99999999h", interval: 10 * time.Second, wantErr: true},
+		{value: "ten%", interval: 10 * time.Second, wantErr: true},
+		{value: "10", interval: 10 * time.Second, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseJitter(tt.value, tt.interval)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJitter(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseJitter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveInterval(t *testing.T) {
+	tests := []struct {
+		base time.Duration
+		want time.Duration
+	}{
+		{base: time.Minute, want: 15 * time.Second},
+		{base: 10 * time.Second, want: 2500 * time.Millisecond},
+		{base: time.Second, want: adaptiveMinInterval},               // Never below the minimum
+		{base: 300 * time.Millisecond, want: 300 * time.Millisecond}, // Never above the base
+	}
+
+	for _, tt := range tests {
+		if got := adaptiveInterval(tt.base); got != tt.want {
+			t.Errorf("adaptiveInterval(%v) = %v, want %v", tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestWatchScheduleAdaptive(t *testing.T) {
+	schedule := watchSchedule{base: 10 * time.Second, adaptive: true}
+	session := &watchSession{}
+
+	steps := []struct {
+		failing bool
+		want    time.Duration
+	}{
+		{failing: false, want: 10 * time.Second},
+		{failing: true, want: 2500 * time.Millisecond}, // Shortened while failing
+		{failing: true, want: 2500 * time.Millisecond}, // Stays short, doesn't compound
+		{failing: false, want: 10 * time.Second},       // Back to the base once recovered
+	}
+	for i, step := range steps {
+		session.failing = step.failing
+		if got := schedule.interval(session); got != step.want {
+			t.Errorf("step %d (failing %v): interval = %v, want %v", i, step.failing, got, step.want)
+		}
+	}
+
+	schedule.adaptive = false
+	session.failing = true
+	if got := schedule.interval(session); got != schedule.base {
+		t.Errorf("interval without --adaptive = %v, want %v", got, schedule.base)
+	}
+}
+
+func TestWatchScheduleJitter(t *testing.T) {
+	schedule := watchSchedule{base: 10 * time.Second, jitter: 0.1, random: rand.New(rand.NewSource(1))}
+	session := &watchSession{}
+
+	for i := 0; i < 100; i++ {
+		got := schedule.next(session)
+		if got < 9*time.Second || got > 11*time.Second {
+			t.Fatalf("next() = %v, want within 10s ± 10%%", got)
+		}
+	}
+
+	schedule.jitter = 0
+	if got := schedule.next(session); got != schedule.base {
+		t.Errorf("next() without jitter = %v, want %v", got, schedule.base)
+	}
+}
+
+func TestWatchLogFile(t *testing.T) {
+	watchLogFile = filepath.Join(t.TempDir(), "checks.ndjson")
+	defer func() { watchLogFile = "" }()
+
+	const url = "https://api.example.com/health"
+	session := newWatchSession(url, request.PingOptions{Method: "GET"})
+	session.log = openWatchLog()
+	recordWatchResult(session, request.Result{URL: url, StatusCode: 200, Latency: 42 * time.Millisecond})
+	recordWatchResult(session, request.Result{URL: url, Error: errors.New("connection refused")})
+	session.log.Close()
+
+	records, err := history.Load(watchLogFile, history.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want 2", len(records))
+	}
+	if ok := records[0]; ok.Name != url || ok.Status != 200 || ok.LatencyMs != 42 || !ok.Success {
+		t.Errorf("first record = %+v, want a 42ms success for %s", ok, url)
+	}
+	if failed := records[1]; failed.Success || failed.Message != "connection refused" {
+		t.Errorf("second record = %+v, want the failure and its error", failed)
+	}
+}
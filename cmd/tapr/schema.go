@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/jsonschema"
+)
+
+// maxSchemaViolations is how many violations a failed schema check lists.
+const maxSchemaViolations = 3
+
+// schemaCache compiles each expect_schema file once per process, which
+// matters in daemon mode where the same endpoints run repeatedly.
+var schemaCache = jsonschema.NewCache()
+
+// checkSchema validates a response body against the endpoint's
+// expect_schema, if it has one.
+func checkSchema(endpoint config.Endpoint, body []byte) error {
+	if endpoint.ExpectSchema == "" {
+		return nil
+	}
+
+	schema, err := schemaCache.Load(endpoint.ExpectSchema)
+	if err != nil {
+		return err
+	}
+	violations, err := schema.ValidateJSON(body)
+	if err != nil {
+		return fmt.Errorf("response is not JSON, expected it to match %s", filepath.Base(endpoint.ExpectSchema))
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	shown := make([]string, 0, maxSchemaViolations)
+	for _, violation := range violations[:min(len(violations), maxSchemaViolations)] {
+		shown = append(shown, violation.String())
+	}
+	message := fmt.Sprintf("response does not match %s: %s", filepath.Base(endpoint.ExpectSchema), strings.Join(shown, "; "))
+	if hidden := len(violations) - len(shown); hidden > 0 {
+		message += fmt.Sprintf(" (and %d more)", hidden)
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/service"
+)
+
+var (
+	serviceName   string // Name of the installed service
+	serviceSystem bool   // Install system-wide instead of for the current user
+	serviceForce  bool   // Replace an existing installation
+	servicePrint  bool   // Print the service definition instead of installing it
+)
+
+// daemonInstallCmd represents the daemon install command
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install [config]",
+	Short: "Install the daemon as a systemd or launchd service",
+	Long: `Install generates a service definition that runs ` + "`tapr daemon`" + ` with the
+given config and hands it to the service manager: a systemd unit on Linux or a
+launchd job on macOS. The service starts right away, comes back after reboots
+and is restarted if it crashes.
+
+By default the service is installed for the current user. Use --system (as
+root) to install it system-wide, so it runs without anyone logged in.`,
+	Example: `  tapr daemon install monitors.yml
+  sudo tapr daemon install /etc/tapr/monitors.yml --system
+  tapr daemon install staging.yml --name tapr-staging --listen 127.0.0.1:9877
+  tapr daemon install monitors.yml --print   # show the unit without installing`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDaemonInstall,
+}
+
+// daemonUninstallCmd represents the daemon uninstall command
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop the daemon service and remove it",
+	Example: `  tapr daemon uninstall
+  sudo tapr daemon uninstall --system`,
+	Args: cobra.NoArgs,
+	Run:  runDaemonUninstall,
+}
+
+// daemonStatusCmd represents the daemon status command
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the daemon service is running",
+	Long: `Status shows the state of the installed daemon service. It exits with 0 when
+the service is running and 1 otherwise, so it can be used in scripts.`,
+	Example: `  tapr daemon status
+  tapr daemon status --name tapr-staging`,
+	Args: cobra.NoArgs,
+	Run:  runDaemonStatus,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
+	daemonCmd.AddCommand(daemonStatusCmd)
+
+	for _, cmd := range []*cobra.Command{daemonInstallCmd, daemonUninstallCmd, daemonStatusCmd} {
+		cmd.Flags().StringVar(
+			&serviceName,
+			"name",
+			service.DefaultName,
+			"Service name, to run several daemons side by side",
+		)
+
+		cmd.Flags().BoolVar(
+			&serviceSystem,
+			"system",
+			false,
+			"System-wide service instead of one for the current user (needs root)",
+		)
+	}
+
+	daemonInstallCmd.Flags().BoolVar(
+		&serviceForce,
+		"force",
+		false,
+		"Replace an existing service of the same name",
+	)
+
+	daemonInstallCmd.Flags().BoolVar(
+		&servicePrint,
+		"print",
+		false,
+		"Print the service definition instead of installing it",
+	)
+
+	daemonInstallCmd.Flags().StringVar(
+		&daemonListen,
+		"listen",
+		"",
+		"Address for the status API (default from config, or "+config.DefaultDaemonListen+")",
+	)
+
+	daemonInstallCmd.Flags().StringVar(
+		&daemonHistory,
+		"history",
+		"",
+		"Path of the history file (default from config)",
+	)
+}
+
+// serviceManager returns the service manager of this system, checking the
+// service name and that --system is only used as root.
+func serviceManager() service.Manager {
+	manager, err := service.ForOS(runtime.GOOS)
+	if err == nil {
+		err = service.ValidateName(serviceName)
+	}
+	if err == nil && serviceSystem && !servicePrint && os.Geteuid() != 0 {
+		err = fmt.Errorf("--system needs root (try again with sudo)")
+	}
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	return manager
+}
+
+// describeService names a service for messages, like "tapr (systemd user
+// service)".
+func describeService(manager service.Manager) string {
+	scope := "user"
+	if serviceSystem {
+		scope = "system"
+	}
+	return fmt.Sprintf("%s (%s %s service)", serviceName, manager.Name(), scope)
+}
+
+// runDaemonInstall executes the daemon install command.
+func runDaemonInstall(cmd *cobra.Command, args []string) {
+	manager := serviceManager()
+
+	// Catch config mistakes now rather than in a restart loop
+	if _, err := config.LoadDaemonConfig(args[0]); err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	configPath, err := filepath.Abs(args[0])
+	var program string
+	if err == nil {
+		program, err = os.Executable()
+	}
+	if err == nil {
+		program, err = filepath.EvalSymlinks(program)
+	}
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	daemonArgs := []string{"daemon", configPath}
+	if daemonListen != "" {
+		daemonArgs = append(daemonArgs, "--listen", daemonListen)
+	}
+	if daemonHistory != "" {
+		historyPath, err := filepath.Abs(daemonHistory)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		daemonArgs = append(daemonArgs, "--history", historyPath)
+	}
+
+	spec := service.Spec{
+		Name:        serviceName,
+		Description: fmt.Sprintf("tapr monitoring daemon (%s)", filepath.Base(configPath)),
+		Program:     program,
+		Args:        daemonArgs,
+		WorkingDir:  filepath.Dir(configPath),
+		System:      serviceSystem,
+	}
+
+	if servicePrint {
+		data, err := manager.Render(spec)
+		if err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+			os.Exit(ExitError)
+		}
+		fmt.Print(string(data))
+		return
+	}
+
+	path, err := manager.Install(spec, serviceForce)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	if silent {
+		return
+	}
+	output.Printf("%s Installed and started %s\n", output.Green("✓"), describeService(manager))
+	output.Printf("  Definition: %s\n", path)
+	output.Printf("  Config:     %s\n", configPath)
+	if status, err := manager.Status(serviceName, serviceSystem); err == nil {
+		output.Printf("  Logs:       %s\n", status.Logs)
+	}
+	if manager.Name() == "systemd" && !serviceSystem {
+		output.Println(output.Yellow("  User services stop when you log out; run `loginctl enable-linger` to keep it running."))
+	}
+}
+
+// runDaemonUninstall executes the daemon uninstall command.
+func runDaemonUninstall(cmd *cobra.Command, args []string) {
+	manager := serviceManager()
+
+	path, err := manager.Uninstall(serviceName, serviceSystem)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %s: %v", describeService(manager), err)))
+		os.Exit(ExitError)
+	}
+	if !silent {
+		output.Printf("%s Stopped and removed %s\n", output.Green("✓"), describeService(manager))
+		output.Printf("  Removed: %s\n", path)
+	}
+}
+
+// runDaemonStatus executes the daemon status command.
+func runDaemonStatus(cmd *cobra.Command, args []string) {
+	manager := serviceManager()
+
+	status, err := manager.Status(serviceName, serviceSystem)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	code := ExitSuccess
+	if !status.Running {
+		code = ExitFailure
+	}
+	if silent || quiet {
+		os.Exit(code)
+	}
+
+	if !status.Exists {
+		output.Printf("%s %s is not installed\n", output.Yellow("–"), describeService(manager))
+		output.Printf("  Install it with: tapr daemon install <config>\n")
+		os.Exit(code)
+	}
+
+	state := output.Red(status.State)
+	if status.Running {
+		state = output.Green(status.State)
+	}
+	output.Printf("🛰️  %s\n", describeService(manager))
+	output.Printf("  State:      %s\n", state)
+	if status.PID > 0 {
+		output.Printf("  PID:        %d\n", status.PID)
+	}
+	if status.Since != "" {
+		output.Printf("  Since:      %s\n", status.Since)
+	}
+	output.Printf("  Definition: %s\n", status.Path)
+	output.Printf("  Logs:       %s\n", status.Logs)
+	os.Exit(code)
+}
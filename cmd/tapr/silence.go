@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/maintenance"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+var (
+	silenceReason string // Why the endpoint is silenced
+	silenceClear  bool   // Remove the silence instead of adding one
+	silenceFile   string // Path of the silences file
+)
+
+// silenceCmd represents the silence command
+var silenceCmd = &cobra.Command{
+	Use:   "silence [name] [duration]",
+	Short: "Mute failures of an endpoint for a while, e.g. during a deploy",
+	Long: `Silence mutes an endpoint or daemon monitor for a while. Its checks keep
+running and failures are still recorded, but they don't alert and don't fail
+the exit code of tapr batch.
+
+Silences are stored in a file shared by every tapr process on this machine, so
+a running daemon picks them up right away. Names may be globs ("auth-*", or "*"
+for everything). Without arguments, silence lists the active silences.
+
+For planned, recurring work, add maintenance windows to the config instead.`,
+	Example: `  tapr silence api 2h --reason "deploy v2.3"
+  tapr silence 'auth-*' 30m
+  tapr silence               # list active silences
+  tapr silence api --clear   # unmute before it expires`,
+	Args: cobra.RangeArgs(0, 2),
+	Run:  runSilence,
+}
+
+func init() {
+	rootCmd.AddCommand(silenceCmd)
+
+	silenceCmd.Flags().StringVar(
+		&silenceReason,
+		"reason",
+		"",
+		"Why the endpoint is silenced, shown with its results",
+	)
+
+	silenceCmd.Flags().BoolVar(
+		&silenceClear,
+		"clear",
+		false,
+		"Remove the silence of the given name",
+	)
+
+	silenceCmd.Flags().StringVar(
+		&silenceFile,
+		"file",
+		maintenance.DefaultSilencePath(),
+		"Path of the silences file",
+	)
+}
+
+// runSilence executes the silence command.
+func runSilence(cmd *cobra.Command, args []string) {
+	switch {
+	case len(args) == 0:
+		listSilences()
+	case silenceClear:
+		if len(args) != 1 {
+			exitSilence(fmt.Errorf("--clear takes only a name"))
+		}
+		clearSilence(args[0])
+	case len(args) == 1:
+		exitSilence(fmt.Errorf("missing duration (e.g. tapr silence %s 2h)", args[0]))
+	default:
+		addSilence(args[0], args[1])
+	}
+}
+
+// addSilence silences name for the given duration.
+func addSilence(name, duration string) {
+	if _, err := path.Match(name, ""); err != nil {
+		exitSilence(fmt.Errorf("invalid name pattern '%s'", name))
+	}
+	length, err := time.ParseDuration(duration)
+	if err != nil || length <= 0 {
+		exitSilence(fmt.Errorf("invalid duration '%s' (expected e.g. 30m or 2h)", duration))
+	}
+
+	now := time.Now()
+	silence := maintenance.Silence{
+		Name:    name,
+		Until:   now.Add(length),
+		Reason:  silenceReason,
+		Created: now,
+	}
+	if err := maintenance.AddSilence(silenceFile, silence); err != nil {
+		exitSilence(err)
+	}
+
+	if !silent {
+		output.Printf("🔇 Silenced %s until %s", output.Cyan(name), silence.Until.Format("15:04 (Mon Jan 2)"))
+		if silenceReason != "" {
+			output.Printf(" — %s", silenceReason)
+		}
+		output.Println()
+	}
+}
+
+// clearSilence removes the silence of name.
+func clearSilence(name string) {
+	removed, err := maintenance.RemoveSilence(silenceFile, name)
+	if err != nil {
+		exitSilence(err)
+	}
+	if !removed {
+		exitSilence(fmt.Errorf("no active silence for '%s'", name))
+	}
+	if !silent {
+		output.Printf("🔔 Unmuted %s\n", output.Cyan(name))
+	}
+}
+
+// listSilences prints the active silences.
+func listSilences() {
+	now := time.Now()
+	silences, err := maintenance.LoadSilences(silenceFile, now)
+	if err != nil {
+		exitSilence(err)
+	}
+	if silent {
+		return
+	}
+	if len(silences) == 0 {
+		output.Println("No active silences")
+		return
+	}
+
+	output.Printf("%-24s %-20s %-10s %s\n", "NAME", "UNTIL", "LEFT", "REASON")
+	for _, s := range silences {
+		output.Printf("%-24s %-20s %-10s %s\n",
+			truncate(s.Name, 24),
+			s.Until.Format("Jan 2 15:04"),
+			s.Until.Sub(now).Round(time.Minute),
+			s.Reason)
+	}
+}
+
+// exitSilence prints a silence error and exits.
+func exitSilence(err error) {
+	fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+	os.Exit(ExitError)
+}
+
+// applyMaintenance marks the failures of a batch run that fall in one of
+// the config's maintenance windows or an active silence. They stay failures
+// but no longer fail the exit code.
+func applyMaintenance(windows []config.MaintenanceWindow, summary *stats.BatchSummary) {
+	checker := maintenance.NewChecker(windows, maintenance.DefaultSilencePath())
+	now := time.Now()
+	for i := range summary.Results {
+		result := &summary.Results[i]
+		if result.Success {
+			continue
+		}
+		if result.Silenced = checker.Reason(result.Name, now); result.Silenced != "" {
+			summary.Silenced++
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/snapshot"
+)
+
+var (
+	snapshotDir      string       // Directory of response snapshots (--snapshot-dir)
+	updateSnapshots  bool         // Rewrite snapshots instead of comparing (--update-snapshots)
+	snapshotsWritten atomic.Int32 // Snapshots created or rewritten during the run
+)
+
+func init() {
+	batchCmd.Flags().StringVar(
+		&snapshotDir,
+		"snapshot-dir",
+		"",
+		"Compare response bodies with snapshots in this directory (missing ones are created)",
+	)
+
+	batchCmd.Flags().BoolVar(
+		&updateSnapshots,
+		"update-snapshots",
+		false,
+		"Rewrite the snapshots in --snapshot-dir with the current responses",
+	)
+}
+
+// checkSnapshot compares a response body with the endpoint's snapshot, if
+// --snapshot-dir is set.
+func checkSnapshot(endpoint config.Endpoint, body []byte) error {
+	if snapshotDir == "" {
+		return nil
+	}
+
+	name := endpoint.Name
+	if name == "" {
+		name = endpoint.Method + " " + endpoint.URL
+	}
+
+	store := snapshot.Store{Dir: snapshotDir, Update: updateSnapshots}
+	written, err := store.Check(name, body, endpoint.SnapshotIgnore)
+	if written {
+		snapshotsWritten.Add(1)
+	}
+	return err
+}
+
+// reportSnapshots tells the user about snapshots written during the run.
+func reportSnapshots() {
+	written := snapshotsWritten.Load()
+	if written == 0 || quiet || silent || outputFormat != "pretty" {
+		return
+	}
+	output.Println(output.Cyan(fmt.Sprintf("📸 Wrote %d snapshot(s) to %s", written, snapshotDir)))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// SSE flags
+var (
+	sseMode   bool // Check a Server-Sent Events stream (--sse)
+	sseEvents int  // Events to wait for (--sse-events)
+)
+
+// maxEventPreview is how much of an event's data is shown.
+const maxEventPreview = 80
+
+func init() {
+	rootCmd.Flags().BoolVar(
+		&sseMode,
+		"sse",
+		false,
+		"Check a Server-Sent Events stream: fail unless an event arrives within --timeout",
+	)
+
+	rootCmd.Flags().IntVar(
+		&sseEvents,
+		"sse-events",
+		1,
+		"Number of events to wait for with --sse",
+	)
+}
+
+// runSSE checks an event stream, prints the events received and exits.
+func runSSE(url string, opts request.PingOptions) {
+	if sseEvents < 1 {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --sse-events must be at least 1"))
+		os.Exit(ExitError)
+	}
+
+	result := request.CheckSSE(url, opts, sseEvents)
+	saveCookieJar(opts.Jar)
+
+	if result.Error != nil {
+		if !silent {
+			printError(url, result.Error)
+			printEvents(result)
+		}
+		exitPing(url, result.Result, ExitFailure)
+	}
+
+	if !silent && !quiet {
+		output.Printf("%s Received %d event(s)\n", output.Green("✓"), len(result.Events))
+		output.Printf("  Status:      %s\n", result.Status)
+		output.Printf("  Connect:     %s\n", formatLatency(result.Latency))
+		output.Printf("  First event: %s\n", formatLatency(result.FirstEvent))
+		if len(result.Events) > 1 {
+			last := result.Events[len(result.Events)-1]
+			output.Printf("  Last event:  %s\n", last.After.Round(time.Millisecond))
+		}
+		printEvents(result)
+	}
+
+	// --max-latency and the summary use the time to the first event
+	first := result.Result
+	first.Latency = result.FirstEvent
+	if exceedsMaxLatency(result.FirstEvent, maxLatency) {
+		if !silent {
+			output.Printf("%s First event after %s exceeded max %s\n", output.Red("✗"), result.FirstEvent.Round(time.Millisecond), maxLatency)
+		}
+		exitPing(url, first, ExitFailure)
+	}
+	exitPing(url, first, ExitSuccess)
+}
+
+// printEvents lists the events received, one line each.
+func printEvents(result request.SSEResult) {
+	if len(result.Events) == 0 {
+		return
+	}
+
+	output.Println("  Events:")
+	for _, event := range result.Events {
+		data := []rune(strings.ReplaceAll(event.Data, "\n", " "))
+		preview := string(data)
+		if len(data) > maxEventPreview {
+			preview = string(data[:maxEventPreview]) + "..."
+		}
+		label := event.Type
+		if event.ID != "" {
+			label += " #" + event.ID
+		}
+		output.Printf("    +%-9s %s %s\n", event.After.Round(time.Millisecond), output.Cyan(label), preview)
+	}
+}
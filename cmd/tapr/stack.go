@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/netcheck"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// compareStack checks the endpoint over both IPv4 and IPv6 (--compare-stack)
+var compareStack bool
+
+func init() {
+	rootCmd.Flags().BoolVar(
+		&compareStack,
+		"compare-stack",
+		false,
+		"Check the endpoint over both IPv4 and IPv6 and compare reachability and latency",
+	)
+}
+
+// stackCheck is the outcome of checking an endpoint over one IP version.
+type stackCheck struct {
+	label     string   // "IPv4" or "IPv6"
+	record    string   // DNS record type of the version ("A" or "AAAA")
+	addresses []string // Addresses the host has in this version
+	result    request.Result
+}
+
+// ok reports whether the endpoint answered without a server error.
+func (c stackCheck) ok() bool {
+	return answered(c.result)
+}
+
+// answered reports whether a request got a response other than a server
+// error.
+func answered(result request.Result) bool {
+	return result.Error == nil && result.StatusCode < 500
+}
+
+// runCompareStack checks url over IPv4 and IPv6, prints both results and
+// exits. It fails when one version fails or answers differently while the
+// other works; a host without addresses in one version is reported but
+// doesn't fail the check.
+func runCompareStack(url string, opts request.PingOptions) {
+	if opts.IPVersion != request.IPVersionAny {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --compare-stack checks both IP versions and can't be combined with -4 or -6"))
+		os.Exit(ExitError)
+	}
+
+	checks := []stackCheck{
+		{label: "IPv4", record: "A"},
+		{label: "IPv6", record: "AAAA"},
+	}
+	if err := stackAddresses(url, opts, checks); err != nil {
+		printError(url, err)
+		exitPing(url, request.Result{URL: url, Error: err}, ExitFailure)
+	}
+
+	for i := range checks {
+		if len(checks[i].addresses) == 0 {
+			continue
+		}
+		versioned := opts
+		versioned.IPVersion = strings.TrimPrefix(checks[i].label, "IPv")
+		checks[i].result = request.Ping(url, versioned)
+	}
+	saveCookieJar(opts.Jar)
+
+	output.Printf("🌐 Dual-stack check: %s\n\n", output.Blue(url))
+	for _, check := range checks {
+		printStackCheck(check)
+	}
+	output.Println()
+
+	v4, v6 := checks[0], checks[1]
+	code := ExitSuccess
+	switch {
+	case len(v4.addresses) == 0 || len(v6.addresses) == 0:
+		missing := v4
+		if len(v6.addresses) == 0 {
+			missing = v6
+		}
+		output.Println(output.Yellow(fmt.Sprintf("⚠️  Host has no %s address (no %s records), so it is single-stack", missing.label, missing.record)))
+		for _, check := range checks {
+			if len(check.addresses) > 0 && !check.ok() {
+				code = ExitFailure
+			}
+		}
+	case v4.ok() && v6.ok():
+		output.Println(describeStackLatency(v4.result.Latency, v6.result.Latency))
+		if v4.result.StatusCode != v6.result.StatusCode {
+			output.Printf("%s Responses differ: IPv4 answered %d, IPv6 answered %d\n", output.Red("✗"), v4.result.StatusCode, v6.result.StatusCode)
+			code = ExitFailure
+		}
+	case v4.ok():
+		output.Printf("%s Reachable over IPv4 only: IPv6 fails\n", output.Red("✗"))
+		code = ExitFailure
+	case v6.ok():
+		output.Printf("%s Reachable over IPv6 only: IPv4 fails\n", output.Red("✗"))
+		code = ExitFailure
+	default:
+		output.Printf("%s Unreachable over both IPv4 and IPv6\n", output.Red("✗"))
+		code = ExitFailure
+	}
+
+	// The summary reports a failing version, or else the slower one
+	var summarized request.Result
+	for _, check := range checks {
+		if len(check.addresses) == 0 {
+			continue
+		}
+		if !check.ok() {
+			summarized = check.result
+			break
+		}
+		if check.result.Latency > summarized.Latency {
+			summarized = check.result
+		}
+	}
+	exitPing(url, summarized, code)
+}
+
+// stackAddresses fills in the IPv4 and IPv6 addresses of the URL's host.
+func stackAddresses(rawURL string, opts request.PingOptions, checks []stackCheck) error {
+	addresses, err := hostAddresses(rawURL, opts)
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if net.ParseIP(address).To4() != nil {
+			checks[0].addresses = append(checks[0].addresses, address)
+		} else {
+			checks[1].addresses = append(checks[1].addresses, address)
+		}
+	}
+	return nil
+}
+
+// hostAddresses returns the addresses the URL's host resolves to, IPv4
+// first, honoring --resolve overrides and --dns-server.
+func hostAddresses(rawURL string, opts request.PingOptions) ([]string, error) {
+	host, port, err := urlHostPort(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if override, ok := opts.Resolve[net.JoinHostPort(strings.ToLower(host), port)]; ok {
+		return []string{override}, nil
+	}
+
+	lookup := netcheck.LookupDNS(host, opts.DNSServer, opts.Timeout)
+	if lookup.Error != nil {
+		return nil, lookup.Error
+	}
+	return append(lookup.A, lookup.AAAA...), nil
+}
+
+// urlHostPort returns the host and port a URL connects to.
+func urlHostPort(rawURL string) (host, port string, err error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	port = parsed.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[parsed.Scheme]
+	}
+	return parsed.Hostname(), port, nil
+}
+
+// printStackCheck prints the result of one IP version on a single line.
+func printStackCheck(check stackCheck) {
+	label := fmt.Sprintf("   %-5s", check.label)
+	result := check.result
+	switch {
+	case len(check.addresses) == 0:
+		output.Printf("%s %s no %s records\n", label, output.Yellow("–"), check.record)
+	case result.Error != nil:
+		output.Printf("%s %s %v\n", label, output.Red("✗"), result.Error)
+	default:
+		symbol := output.Green("✓")
+		if !check.ok() {
+			symbol = output.Red("✗")
+		}
+		output.Printf("%s %s %-16s %-22s %s\n", label, symbol, result.Status, formatLatency(result.Latency), result.RemoteAddr)
+	}
+}
+
+// describeStackLatency compares the IPv6 latency with the IPv4 latency.
+func describeStackLatency(v4, v6 time.Duration) string {
+	diff := v6 - v4
+	if diff < 0 {
+		diff = -diff
+	}
+	if v4 <= 0 || diff < time.Millisecond {
+		return fmt.Sprintf("%s Both stacks work with about the same latency", output.Green("✓"))
+	}
+
+	slower := "IPv6"
+	if v4 > v6 {
+		slower = "IPv4"
+	}
+	percent := float64(diff) / float64(min(v4, v6)) * 100
+	return fmt.Sprintf("%s Both stacks work; %s is %s (%.0f%%) slower", output.Green("✓"), slower, diff.Round(time.Millisecond), percent)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// resultStream receives every result as it completes with --output ndjson
+// or --output-template; nil otherwise.
+var resultStream output.ResultStream
+
+// openResultStream starts streaming results to stdout for the ndjson and
+// template output formats. The live views and summaries are then left out
+// so stdout holds nothing but the streamed lines.
+func openResultStream() {
+	switch outputFormat {
+	case "ndjson":
+		resultStream = output.NewNDJSONStream(os.Stdout)
+	case "template":
+		resultStream = output.NewTemplateStream(os.Stdout, resultTemplate)
+	}
+}
+
+// streamResult writes a completed check to the result stream, if any.
+func streamResult(mode string, result stats.BatchResult) {
+	if resultStream == nil {
+		return
+	}
+	if err := resultStream.Result(mode, result, time.Now()); err != nil {
+		slog.Warn("Writing result: " + err.Error())
+	}
+}
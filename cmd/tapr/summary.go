@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// Summary file flag
+var (
+	summaryFile   string    // Write a JSON run summary here (--summary-file)
+	summaryTarget string    // Batch config file named in the summary
+	runStarted    time.Time // When the current command started
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&summaryFile,
+		"summary-file",
+		"",
+		"Always write a JSON summary of the run to this file, even with --silent",
+	)
+
+	runStarted = time.Now()
+}
+
+// writeSummary fills in the run details and writes summary to
+// --summary-file and, with --output ndjson, as the last line of the result
+// stream. Write failures are reported but never change the exit
+// code, since the run itself already finished.
+func writeSummary(command, target string, summary output.RunSummary, code int) {
+	summary.Command = command
+	summary.Target = target
+	summary.StartedAt = runStarted
+	summary.Duration = time.Since(runStarted).Milliseconds()
+	summary.ExitCode = code
+
+	// The summary also closes an NDJSON result stream
+	if resultStream != nil {
+		if err := resultStream.Summary(summary); err != nil {
+			slog.Warn("Writing summary: " + err.Error())
+		}
+	}
+
+	if summaryFile == "" {
+		return
+	}
+	if err := output.WriteSummaryFile(summaryFile, summary); err != nil {
+		slog.Warn(err.Error())
+	}
+}
+
+// exitPing writes the summary of a single ping and exits with code.
+func exitPing(url string, result request.Result, code int) {
+	tracker := stats.NewTracker()
+	tracker.Record(result.Latency, code == ExitSuccess)
+	tracker.RecordStatus(stats.StatusLabel(result))
+	category := ""
+	if code != ExitSuccess {
+		category = request.Classify(result)
+		if category == "" {
+			category = request.CategoryAssertion
+		}
+		tracker.RecordError(category)
+	}
+	streamResult("ping", stats.BatchResult{
+		Name:     url,
+		URL:      url,
+		Method:   method,
+		Result:   result,
+		Success:  code == ExitSuccess,
+		Message:  category,
+		Category: category,
+	})
+
+	writeSummary("ping", url, output.TrackerSummary(tracker), code)
+	os.Exit(code)
+}
+
+// exitBatch records the run in --history, checks it against --baseline,
+// writes the batch summary and exits with code.
+func exitBatch(summary *stats.BatchSummary, code int) {
+	recordBatchRun(summary)
+	run := output.BatchSummary(summary)
+	code = gateBaseline("batch", summaryTarget, run, code)
+	writeSummary("batch", summaryTarget, run, code)
+	os.Exit(code)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/netcheck"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// TCP-specific flags
+var (
+	tcpSend         string // Payload to send after connecting
+	tcpExpectBanner string // Substring the server banner must contain
+)
+
+// tcpCmd represents the tcp command for raw port connectivity checks
+var tcpCmd = &cobra.Command{
+	Use:   "tcp [host:port]",
+	Short: "Check raw TCP connectivity to a host and port",
+	Long: `TCP mode opens a plain TCP connection (no HTTP) and measures the
+connect time. Optionally it sends a payload and verifies the server's
+banner, so databases, caches, mail servers and other non-HTTP services
+can be monitored too.
+
+Perfect for:
+  • Checking that a port is open and reachable
+  • Monitoring Redis, PostgreSQL, SMTP or SSH
+  • Telling network problems apart from HTTP problems`,
+	Example: `  tapr tcp db.example.com:5432
+  tapr tcp smtp.example.com:25 --expect-banner 220
+  tapr tcp localhost:6379 --send 'PING\r\n' --expect-banner PONG`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTCP,
+}
+
+func init() {
+	rootCmd.AddCommand(tcpCmd)
+
+	tcpCmd.Flags().StringVar(
+		&tcpSend,
+		"send",
+		"",
+		"Payload to send after connecting (supports \\r and \\n escapes)",
+	)
+
+	tcpCmd.Flags().StringVar(
+		&tcpExpectBanner,
+		"expect-banner",
+		"",
+		"Fail unless the server response contains this text",
+	)
+
+	tcpCmd.Flags().DurationVarP(
+		&timeout,
+		"timeout",
+		"t",
+		10*time.Second,
+		"Maximum time to wait for the connection",
+	)
+}
+
+// runTCP executes the tcp command.
+func runTCP(cmd *cobra.Command, args []string) {
+	address := args[0]
+
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		fmt.Fprintln(output.Stderr, output.Red("Error: address must be in host:port format"))
+		os.Exit(ExitError)
+	}
+
+	opts := netcheck.TCPOptions{
+		Timeout:      timeout,
+		Send:         unescapePayload(tcpSend),
+		ExpectBanner: tcpExpectBanner,
+	}
+
+	result := netcheck.CheckTCP(address, opts)
+
+	if result.Error != nil {
+		if !silent {
+			output.Printf("%s Failed to connect to %s\n", output.Red("✗"), address)
+			output.Printf("  Error:   %v\n", result.Error)
+		}
+		os.Exit(ExitFailure)
+	}
+
+	if silent || quiet {
+		return
+	}
+
+	output.Printf("%s Connected\n", output.Green("✓"))
+	output.Printf("  Address: %s\n", result.RemoteAddr)
+	output.Printf("  Connect: %s\n", formatLatency(result.ConnectTime))
+	if result.Banner != "" {
+		// Only show the first line - banners can be long
+		banner := strings.SplitN(result.Banner, "\n", 2)[0]
+		output.Printf("  Banner:  %s\n", strings.TrimSpace(banner))
+	}
+}
+
+// unescapePayload converts common escape sequences typed on the shell
+// (\r, \n, \t) into their control characters.
+func unescapePayload(payload string) string {
+	replacer := strings.NewReplacer(`\r`, "\r", `\n`, "\n", `\t`, "\t")
+	return replacer.Replace(payload)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+var (
+	outputTemplate string             // Render each result with this Go template (--output-template)
+	resultTemplate *template.Template // Parsed --output-template
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&outputTemplate,
+		"output-template",
+		"",
+		"Print each result with this Go template (e.g., '{{.Status}} {{.LatencyMs}}ms'), implies --output template",
+	)
+}
+
+// resolveOutputTemplate parses --output-template and switches the output
+// format to template. It exits if the template is invalid or another
+// output format was asked for.
+func resolveOutputTemplate(cmd *cobra.Command) {
+	if outputTemplate == "" {
+		if outputFormat == "template" {
+			fmt.Fprintln(output.Stderr, output.Red("Error: --output template needs --output-template"))
+			os.Exit(ExitError)
+		}
+		return
+	}
+	if flagChanged(cmd, "output") && outputFormat != "template" {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: use either --output %s or --output-template, not both", outputFormat)))
+		os.Exit(ExitError)
+	}
+
+	tmpl, err := output.ParseTemplate(outputTemplate)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	resultTemplate = tmpl
+	outputFormat = "template"
+}
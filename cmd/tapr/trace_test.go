@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+func TestAverageTrace(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		results []request.TraceResult
+		want    request.TraceResult
+	}{
+		{
+			name: "no runs",
+			want: request.TraceResult{},
+		},
+		{
+			name: "https",
+			results: []request.TraceResult{
+				{DNSLookup: 2 * ms, TCPConnection: 4 * ms, TLSHandshake: 10 * ms, ServerProcessing: 20 * ms, ContentTransfer: 1 * ms, TotalTime: 37 * ms},
+				{DNSLookup: 4 * ms, TCPConnection: 6 * ms, TLSHandshake: 14 * ms, ServerProcessing: 30 * ms, ContentTransfer: 3 * ms, TotalTime: 57 * ms},
+			},
+			want: request.TraceResult{DNSLookup: 3 * ms, TCPConnection: 5 * ms, TLSHandshake: 12 * ms, ServerProcessing: 25 * ms, ContentTransfer: 2 * ms, TotalTime: 47 * ms},
+		},
+		{
+			name: "http has no TLS",
+			results: []request.TraceResult{
+				{DNSLookup: 2 * ms, TCPConnection: 4 * ms, ServerProcessing: 20 * ms, TotalTime: 26 * ms},
+				{DNSLookup: 2 * ms, TCPConnection: 2 * ms, ServerProcessing: 10 * ms, TotalTime: 14 * ms},
+			},
+			want: request.TraceResult{DNSLookup: 2 * ms, TCPConnection: 3 * ms, ServerProcessing: 15 * ms, TotalTime: 20 * ms},
+		},
+		{
+			// The second run reused the connection, so it skipped DNS, TCP and
+			// TLS; the missing phases count as zero and the phases add up
+			name: "some runs miss phases",
+			results: []request.TraceResult{
+				{DNSLookup: 4 * ms, TCPConnection: 6 * ms, TLSHandshake: 10 * ms, ServerProcessing: 20 * ms, TotalTime: 40 * ms},
+				{ServerProcessing: 20 * ms, TotalTime: 20 * ms, Reused: true},
+			},
+			want: request.TraceResult{DNSLookup: 2 * ms, TCPConnection: 3 * ms, TLSHandshake: 5 * ms, ServerProcessing: 20 * ms, TotalTime: 30 * ms},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := averageTrace(tt.results)
+			if got != tt.want {
+				t.Errorf("averageTrace() = %+v, want %+v", got, tt.want)
+			}
+			phases := got.DNSLookup + got.TCPConnection + got.TLSHandshake + got.ServerProcessing + got.ContentTransfer
+			if phases != got.TotalTime {
+				t.Errorf("phases add up to %v, want the average total %v", phases, got.TotalTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+var (
+	traceHTTP     bool // Dump every request and response to stderr (--trace-http)
+	traceHTTPBody int  // Bytes of each response body to include in the dump
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(
+		&traceHTTP,
+		"trace-http",
+		false,
+		"Dump every request and response (headers, secrets masked) to stderr, like curl -v",
+	)
+
+	rootCmd.PersistentFlags().IntVar(
+		&traceHTTPBody,
+		"trace-http-body",
+		0,
+		"Include the first N bytes of each response body in the --trace-http dump",
+	)
+}
+
+// applyTraceHTTP turns on the --trace-http dump for opts. Nothing is dumped
+// with --silent.
+func applyTraceHTTP(opts *request.PingOptions) {
+	if !traceHTTP || silent {
+		return
+	}
+	opts.Dump = os.Stderr
+	opts.DumpBody = traceHTTPBody
+}
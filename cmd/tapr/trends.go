@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/output"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+var (
+	trendsSince     string // How far back to analyze, e.g. 7d
+	trendsHistory   string // Path of the history file
+	trendsSparkline bool   // Draw daily p95 and availability as sparklines
+	trendsCSV       bool   // Print the breakdown as CSV
+)
+
+// trendsCmd represents the trends command for analyzing stored history
+var trendsCmd = &cobra.Command{
+	Use:   "trends [url or name]",
+	Short: "Analyze the latency and availability trend of an endpoint over time",
+	Long: `Trends reads the checks of an endpoint from the history store (written by
+tapr daemon and tapr watch --log-file) and reports whether its latency is
+improving or degrading, its p95 latency by hour of the day, and its
+availability per day.
+
+The endpoint is matched by URL or by daemon monitor name. Days and hours are
+in local time.`,
+	Example: `  tapr trends https://api.example.com/health
+  tapr trends api --since 30d --sparkline
+  tapr trends api --since 24h --csv > api.csv
+  tapr trends https://api.example.com --history watch.ndjson`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTrends,
+}
+
+func init() {
+	rootCmd.AddCommand(trendsCmd)
+
+	trendsCmd.Flags().StringVar(
+		&trendsSince,
+		"since",
+		"7d",
+		"How far back to analyze (e.g. 24h, 7d)",
+	)
+
+	trendsCmd.Flags().StringVar(
+		&trendsHistory,
+		"history",
+		history.DefaultPath(),
+		"Path of the history file",
+	)
+
+	trendsCmd.Flags().BoolVar(
+		&trendsSparkline,
+		"sparkline",
+		false,
+		"Draw daily p95 latency and availability as sparklines",
+	)
+
+	trendsCmd.Flags().BoolVar(
+		&trendsCSV,
+		"csv",
+		false,
+		"Print the daily and hourly breakdown as CSV",
+	)
+}
+
+// runTrends executes the trends command.
+func runTrends(cmd *cobra.Command, args []string) {
+	since, err := parseSince(trendsSince)
+	if err != nil {
+		exitTrends(err)
+	}
+
+	records, err := history.Load(trendsHistory, history.Query{Since: time.Now().Add(-since)})
+	if err != nil {
+		exitTrends(err)
+	}
+	var samples []stats.TrendSample
+	for _, record := range records {
+		if record.URL == args[0] || record.Name == args[0] {
+			samples = append(samples, stats.TrendSample{Time: record.Time, Latency: record.Latency(), Success: record.Success})
+		}
+	}
+	if len(samples) == 0 {
+		exitTrends(fmt.Errorf("no checks of %s in %s in the last %s", args[0], trendsHistory, trendsSince))
+	}
+	// Several processes may append to one history file
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+
+	trend := stats.AnalyzeTrend(samples, time.Local)
+	if trendsCSV {
+		if err := writeTrendsCSV(trend); err != nil {
+			exitTrends(err)
+		}
+		return
+	}
+	displayTrends(args[0], trend)
+}
+
+// parseSince parses a look-back period: a duration, or a number of days
+// such as 7d.
+func parseSince(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid --since %q (e.g. 24h, 7d)", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	since, err := time.ParseDuration(value)
+	if err != nil || since <= 0 {
+		return 0, fmt.Errorf("invalid --since %q (e.g. 24h, 7d)", value)
+	}
+	return since, nil
+}
+
+// displayTrends prints the trend, the daily breakdown and the hourly p95.
+func displayTrends(target string, trend stats.Trend) {
+	first, last := trend.Days[0].Date, trend.Days[len(trend.Days)-1].Date
+	output.Printf("📈 Trends for %s\n", target)
+	output.Printf("   %d checks from %s to %s, %d failed\n\n",
+		trend.Checks, first.Format("Jan 2"), last.Format("Jan 2"), trend.Failures)
+
+	output.Printf("⏱️  Latency Trend\n")
+	change := fmt.Sprintf("%s → %s (%+.0f%%)", roundLatency(trend.Start), roundLatency(trend.End), trend.Change*100)
+	switch trend.Direction {
+	case stats.TrendDegrading:
+		output.Printf("   %s %s\n", output.Red("▲ Degrading"), change)
+	case stats.TrendImproving:
+		output.Printf("   %s %s\n", output.Green("▼ Improving"), change)
+	default:
+		if trend.Start > 0 {
+			output.Printf("   %s %s\n", output.Blue("● Stable"), change)
+		} else {
+			output.Printf("   %s\n", output.Blue("● Not enough successful checks for a trend"))
+		}
+	}
+	output.Println()
+
+	output.Printf("📅 Availability per Day\n")
+	for _, day := range trend.Days {
+		output.Printf("   %-10s  %5d checks  %s  p95 %s\n",
+			day.Date.Format("Mon Jan 2"),
+			day.Checks,
+			successRateColor(day.Availability)(fmt.Sprintf("%7.2f%%", day.Availability)),
+			roundLatency(day.P95))
+	}
+	if trendsSparkline && len(trend.Days) > 1 {
+		p95s := make([]float64, len(trend.Days))
+		availability := make([]float64, len(trend.Days))
+		for i, day := range trend.Days {
+			p95s[i] = float64(day.P95)
+			availability[i] = day.Availability
+		}
+		output.Printf("\n   p95           %s\n", output.Sparkline(p95s))
+		output.Printf("   Availability  %s\n", output.Sparkline(availability))
+	}
+	output.Println()
+
+	output.Printf("🕐 p95 by Hour of Day\n")
+	var p95s []float64
+	for _, hour := range trend.Hours {
+		if hour.Checks == 0 {
+			p95s = append(p95s, -1)
+			continue
+		}
+		p95s = append(p95s, float64(hour.P95))
+		if !trendsSparkline {
+			output.Printf("   %02d:00  %5d checks  %s\n", hour.Hour, hour.Checks, roundLatency(hour.P95))
+		}
+	}
+	if trendsSparkline {
+		output.Printf("   %s\n", output.Sparkline(p95s))
+		output.Printf("   00    06    12    18   23\n")
+	}
+}
+
+// writeTrendsCSV prints one row per day and per hour of the day with checks.
+func writeTrendsCSV(trend stats.Trend) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"period", "start", "checks", "failures", "availability_percent", "p95_ms"})
+	for _, day := range trend.Days {
+		_ = w.Write([]string{
+			"day",
+			day.Date.Format("2006-01-02"),
+			strconv.Itoa(day.Checks),
+			strconv.Itoa(day.Failures),
+			strconv.FormatFloat(day.Availability, 'f', 2, 64),
+			strconv.FormatFloat(float64(day.P95)/float64(time.Millisecond), 'f', 1, 64),
+		})
+	}
+	for _, hour := range trend.Hours {
+		if hour.Checks == 0 {
+			continue
+		}
+		_ = w.Write([]string{
+			"hour",
+			fmt.Sprintf("%02d:00", hour.Hour),
+			strconv.Itoa(hour.Checks),
+			strconv.Itoa(hour.Failures),
+			strconv.FormatFloat(float64(hour.Checks-hour.Failures)/float64(hour.Checks)*100, 'f', 2, 64),
+			strconv.FormatFloat(float64(hour.P95)/float64(time.Millisecond), 'f', 1, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// exitTrends prints an error and exits.
+func exitTrends(err error) {
+	fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+	os.Exit(ExitError)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// userConfig holds the defaults read from the user config file.
+var userConfig = &config.UserConfig{}
+
+var (
+	colorMode   string // auto, always or never (--color)
+	noColor     bool   // Same as --color=never
+	asciiOutput bool   // Draw output with ASCII characters only (--ascii)
+)
+
+func init() {
+	rootCmd.PersistentPreRun = applyUserConfig
+
+	rootCmd.PersistentFlags().StringVar(
+		&colorMode,
+		"color",
+		output.ColorAuto,
+		"Color output: auto (only on a terminal, unless NO_COLOR is set), always or never",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&noColor,
+		"no-color",
+		false,
+		"Disable colored output (same as --color=never)",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&asciiOutput,
+		"ascii",
+		false,
+		"Draw output with ASCII characters only, without box drawing or emoji",
+	)
+}
+
+// applyUserConfig loads the user config file and applies its defaults to
+// every setting that was not given on the command line.
+func applyUserConfig(cmd *cobra.Command, args []string) {
+	path := config.UserConfigPath()
+	loaded, err := config.LoadUserConfig(path)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+	userConfig = loaded
+
+	// Watch and trace have no timeout flag of their own, so they always
+	// pick up the configured value
+	if userConfig.Timeout > 0 && !flagChanged(cmd, "timeout") {
+		timeout = userConfig.Timeout
+	}
+
+	if userConfig.Output != "" && !flagChanged(cmd, "output") {
+		outputFormat = userConfig.Output
+	}
+
+	if userConfig.FastThreshold > 0 && !flagChanged(cmd, "fast-threshold") {
+		fastThreshold = userConfig.FastThreshold
+	}
+	if userConfig.SlowThreshold > 0 && !flagChanged(cmd, "slow-threshold") {
+		slowThreshold = userConfig.SlowThreshold
+	}
+	if fastThreshold <= 0 || slowThreshold < fastThreshold {
+		fmt.Fprintln(output.Stderr, output.Red("Error: --fast-threshold must be positive and not above --slow-threshold"))
+		os.Exit(ExitError)
+	}
+	levels := map[string]int{"fast": output.LatencyFast, "medium": output.LatencyMedium, "slow": output.LatencySlow}
+	for level, name := range userConfig.LatencyColors {
+		if err := output.SetLatencyColor(levels[level], name); err != nil {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: latency_colors.%s: %v", level, err)))
+			os.Exit(ExitError)
+		}
+	}
+
+	// Consoles without ANSI escape support (legacy Windows consoles) get
+	// the ASCII theme and no colors
+	output.SetupTerminal()
+	if asciiOutput || output.LegacyConsole() {
+		output.SetTheme(output.ASCIITheme)
+	}
+
+	// --no-color wins over --color, which wins over the config file
+	mode := userConfig.Color
+	if flagChanged(cmd, "color") {
+		mode = colorMode
+	}
+	if noColor {
+		mode = output.ColorNever
+	}
+	if err := output.ConfigureColor(mode); err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	resolveOutputTemplate(cmd)
+	setupLogging(cmd)
+}
+
+// flagChanged reports whether the named flag exists on cmd and was set.
+func flagChanged(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flags().Lookup(name)
+	return flag != nil && flag.Changed
+}
+
+// resolveTarget turns a URL argument into the URL to request. An @name
+// argument is looked up in the user config aliases: its method applies
+// unless -X was given, and its headers are returned so that --headers and
+// -H can override them. It exits if the alias is unknown.
+func resolveTarget(cmd *cobra.Command, arg string) (string, map[string]string) {
+	if !config.IsAlias(arg) {
+		return arg, nil
+	}
+
+	alias, err := userConfig.ResolveAlias(arg)
+	if err != nil {
+		fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("Error: %v", err)))
+		os.Exit(ExitError)
+	}
+
+	if alias.Method != "" && !flagChanged(cmd, "method") {
+		method = alias.Method
+	}
+	return alias.URL, alias.Headers
+}
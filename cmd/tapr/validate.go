@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// validateCmd represents the validate command for checking batch configs
+var validateCmd = &cobra.Command{
+	Use:   "validate [config]",
+	Short: "Check a batch config file without making requests",
+	Long: `Validate loads a batch config the same way 'tapr batch' does, including its
+includes and body files, and reports any problems without sending a request:
+unknown or misspelled keys, values of the wrong type, malformed URLs,
+duplicate endpoint names and broken dependencies. Errors point at the line
+of the file they come from.
+
+Exits with 0 if the config is valid and 1 if it is not.
+
+Perfect for:
+  • Pre-commit hooks and CI checks on endpoint inventories
+  • Catching typos before a long batch run`,
+	Example: `  tapr validate endpoints.yml
+  tapr validate endpoints.yml --silent && tapr batch endpoints.yml`,
+	Args: cobra.ExactArgs(1),
+	Run:  runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+// runValidate executes the validate command.
+func runValidate(cmd *cobra.Command, args []string) {
+	configFile := args[0]
+
+	batchConfig, err := config.LoadBatchConfig(configFile)
+	if err != nil {
+		if !silent {
+			fmt.Fprintln(output.Stderr, output.Red(fmt.Sprintf("✗ %s is invalid: %v", configFile, err)))
+		}
+		os.Exit(ExitFailure)
+	}
+
+	if silent {
+		os.Exit(ExitSuccess)
+	}
+
+	stages := 0
+	if n := len(batchConfig.Endpoints); n > 0 && batchConfig.Endpoints[n-1].Stage != "" {
+		stages = batchConfig.Endpoints[n-1].StageIndex + 1
+	}
+	for _, d := range batchConfig.Discover {
+		if d.Stage != "" {
+			stages = max(stages, d.StageIndex+1)
+		}
+	}
+
+	detail := fmt.Sprintf("%d endpoints", len(batchConfig.Endpoints))
+	if stages > 0 {
+		detail += fmt.Sprintf(" in %d stages", stages)
+	}
+	if n := len(batchConfig.Discover); n > 0 {
+		detail += fmt.Sprintf(", %d discover entries", n)
+	}
+	if n := len(batchConfig.Maintenance); n > 0 {
+		detail += fmt.Sprintf(", %d maintenance windows", n)
+	}
+	output.Println(output.Green(fmt.Sprintf("✓ %s is valid (%s)", configFile, detail)))
+}
@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// CheckFunc runs a batch config and returns its results.
+type CheckFunc func(batch []byte) (output.JSONBatchResult, error)
+
+// RoundFunc is called after every run with the report sent to the
+// controller, or the error that prevented the run or the report.
+type RoundFunc func(report Report, err error)
+
+// Agent runs the controller's batch config on an interval and reports the
+// results back.
+type Agent struct {
+	name       string
+	controller string // Base URL of the controller
+	token      string
+	interval   time.Duration
+	check      CheckFunc
+	onRound    RoundFunc
+	client     *http.Client
+}
+
+// New creates an agent named name that joins the controller at
+// controllerURL, runs check every interval and reports with token.
+// onRound may be nil.
+func New(name, controllerURL, token string, interval time.Duration, check CheckFunc, onRound RoundFunc) *Agent {
+	if !strings.Contains(controllerURL, "://") {
+		controllerURL = "http://" + controllerURL
+	}
+	return &Agent{
+		name:       name,
+		controller: strings.TrimSuffix(controllerURL, "/"),
+		token:      token,
+		interval:   interval,
+		check:      check,
+		onRound:    onRound,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Run runs a round immediately and then on every interval until ctx is
+// cancelled. A failed round is reported to onRound and retried on the next
+// interval, so agents survive controller restarts.
+func (a *Agent) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := a.Once(ctx)
+		if a.onRound != nil && ctx.Err() == nil {
+			a.onRound(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Once fetches the batch config from the controller, runs it and reports
+// the results.
+func (a *Agent) Once(ctx context.Context) (Report, error) {
+	batch, err := a.fetchConfig(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	result, err := a.check(batch)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{
+		Agent:    a.name,
+		Interval: a.interval.Seconds(),
+		Time:     time.Now(),
+		Result:   result,
+	}
+	return report, a.sendReport(ctx, report)
+}
+
+// fetchConfig downloads the batch config to run.
+func (a *Agent) fetchConfig(ctx context.Context) ([]byte, error) {
+	resp, err := a.do(ctx, http.MethodGet, "/api/config", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	batch, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config from controller: %w", err)
+	}
+	return batch, nil
+}
+
+// sendReport posts the results of a run.
+func (a *Agent) sendReport(ctx context.Context, report Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	resp, err := a.do(ctx, http.MethodPost, "/api/reports", data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a request to the controller, turning error responses into
+// errors.
+func (a *Agent) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, a.controller+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		if apiErr.Error != "" {
+			return nil, fmt.Errorf("controller returned %d: %s", resp.StatusCode, apiErr.Error)
+		}
+		return nil, fmt.Errorf("controller returned %s", resp.Status)
+	}
+	return resp, nil
+}
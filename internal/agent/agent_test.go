@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// result returns a batch result with one endpoint per name, failing the
+// names in failing.
+func result(names []string, failing ...string) output.JSONBatchResult {
+	r := output.JSONBatchResult{Total: len(names)}
+	for _, name := range names {
+		endpoint := output.JSONEndpoint{Name: name, URL: "https://" + name + ".example.com", Status: 200, Success: true}
+		for _, f := range failing {
+			if f == name {
+				endpoint.Status, endpoint.Success = 503, false
+			}
+		}
+		if endpoint.Success {
+			r.Successful++
+		} else {
+			r.Failed++
+		}
+		r.Results = append(r.Results, endpoint)
+	}
+	return r
+}
+
+func TestAgentOnce(t *testing.T) {
+	var reports []Report
+	controller := NewController([]byte("endpoints: []\n"), "s3cret", func(r Report) { reports = append(reports, r) })
+	server := httptest.NewServer(controller.Handler())
+	defer server.Close()
+
+	var received string
+	check := func(batch []byte) (output.JSONBatchResult, error) {
+		received = string(batch)
+		return result([]string{"api", "web"}, "web"), nil
+	}
+
+	a := New("eu-west", server.URL+"/", "s3cret", time.Minute, check, nil)
+	report, err := a.Once(context.Background())
+	if err != nil {
+		t.Fatalf("Once() error = %v", err)
+	}
+	if received != "endpoints: []\n" {
+		t.Errorf("agent ran %q, want the controller's config", received)
+	}
+	if len(reports) != 1 || reports[0].Agent != "eu-west" || reports[0].Interval != 60 || report.Result.Failed != 1 {
+		t.Errorf("controller received %+v", reports)
+	}
+
+	agents := controller.Agents()
+	if len(agents) != 1 || agents[0].Name != "eu-west" || agents[0].Reports != 1 || agents[0].Address != "127.0.0.1" || agents[0].Stale {
+		t.Errorf("Agents() = %+v", agents)
+	}
+
+	// A wrong token is rejected before the batch runs
+	received = ""
+	bad := New("eu-west", strings.TrimPrefix(server.URL, "http://"), "wrong", time.Minute, check, nil)
+	if _, err := bad.Once(context.Background()); err == nil || !strings.Contains(err.Error(), "401: invalid or missing token") {
+		t.Errorf("Once() with a wrong token error = %v", err)
+	}
+	if received != "" {
+		t.Error("agent ran the batch without a valid token")
+	}
+}
+
+func TestControllerEndpoints(t *testing.T) {
+	controller := NewController(nil, "", nil)
+	controller.record(Report{Agent: "us-east", Interval: 60, Result: result([]string{"api", "web"})}, "10.0.0.2:4000")
+	controller.record(Report{Agent: "eu-west", Interval: 60, Result: result([]string{"api", "web"}, "web")}, "10.0.0.1:4000")
+	controller.record(Report{Agent: "ap-south", Interval: 60, Result: result([]string{"api"}, "api")}, "10.0.0.3:4000")
+
+	// ap-south hasn't reported for more than three intervals
+	controller.agents["ap-south"].LastReport = time.Now().Add(-4 * time.Minute)
+
+	endpoints := controller.Endpoints()
+	if len(endpoints) != 2 {
+		t.Fatalf("Endpoints() = %+v, want api and web", endpoints)
+	}
+	api, web := endpoints[0], endpoints[1]
+	if api.Name != "api" || api.Passing != 2 || api.Failing != 0 || len(api.Agents) != 2 {
+		t.Errorf("api = %+v, want passing from the two current agents", api)
+	}
+	if web.Name != "web" || web.Passing != 1 || web.Failing != 1 || web.Agents["eu-west"].Status != 503 {
+		t.Errorf("web = %+v, want failing from eu-west", web)
+	}
+
+	agents := controller.Agents()
+	if agents[0].Name != "ap-south" || !agents[0].Stale || agents[1].Stale {
+		t.Errorf("Agents() = %+v, want only ap-south stale", agents)
+	}
+}
+
+func TestControllerHandler(t *testing.T) {
+	controller := NewController([]byte("endpoints: []\n"), "s3cret", nil)
+	handler := controller.Handler()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{"health needs no token", "GET", "/healthz", "", "", http.StatusOK},
+		{"config needs the token", "GET", "/api/config", "", "", http.StatusUnauthorized},
+		{"config", "GET", "/api/config", "s3cret", "", http.StatusOK},
+		{"report needs POST", "GET", "/api/reports", "s3cret", "", http.StatusMethodNotAllowed},
+		{"invalid report", "POST", "/api/reports", "s3cret", "{", http.StatusBadRequest},
+		{"report without agent", "POST", "/api/reports", "s3cret", `{"result": {}}`, http.StatusBadRequest},
+		{"report", "POST", "/api/reports", "s3cret", `{"agent": "eu-west", "result": {"total": 1, "failed": 1}}`, http.StatusNoContent},
+		{"agents failing", "GET", "/api/agents", "s3cret", "", http.StatusServiceUnavailable},
+		{"endpoints", "GET", "/api/endpoints", "s3cret", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/api/agents", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var body struct {
+		Agents []AgentStatus `json:"agents"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || len(body.Agents) != 1 || body.Agents[0].Result.Failed != 1 {
+		t.Errorf("GET /api/agents = %s (error %v)", rec.Body, err)
+	}
+}
@@ -0,0 +1,304 @@
+// Package agent implements distributed probing: agents on other machines
+// fetch a batch config from a controller, run it on an interval and report
+// their results back, and the controller serves the combined results of
+// every agent over HTTP.
+package agent
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// DefaultListen is the address the controller listens on by default.
+const DefaultListen = ":9900"
+
+// maxReportSize limits the size of a report an agent may send.
+const maxReportSize = 16 << 20
+
+// Report is what an agent sends to the controller after each run.
+type Report struct {
+	Agent    string                 `json:"agent"`
+	Interval float64                `json:"interval_seconds"` // Time between the agent's runs
+	Time     time.Time              `json:"time"`             // When the run finished
+	Result   output.JSONBatchResult `json:"result"`
+}
+
+// AgentStatus is the latest state of one agent, as served by the API.
+type AgentStatus struct {
+	Name       string                  `json:"name"`
+	Address    string                  `json:"address"`
+	LastReport time.Time               `json:"last_report"` // When the controller received the latest report
+	Interval   float64                 `json:"interval_seconds"`
+	Reports    int                     `json:"reports"`
+	Stale      bool                    `json:"stale"` // No report for three intervals
+	Result     *output.JSONBatchResult `json:"result,omitempty"`
+}
+
+// EndpointStatus is the latest result of one endpoint from every agent.
+type EndpointStatus struct {
+	Name    string                 `json:"name"`
+	URL     string                 `json:"url"`
+	Passing int                    `json:"passing"` // Agents where the endpoint passed
+	Failing int                    `json:"failing"` // Agents where it failed
+	Agents  map[string]AgentResult `json:"agents"`
+}
+
+// AgentResult is one endpoint's result from one agent.
+type AgentResult struct {
+	Success bool   `json:"success"`
+	Status  int    `json:"status"`
+	Latency int64  `json:"latency_ms"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Controller hands out a batch config to agents and collects their reports.
+type Controller struct {
+	batch    []byte // Config served to agents (see config.BatchConfig.Standalone)
+	token    string // Shared secret agents must send ("" = no authentication)
+	onReport func(Report)
+
+	mu     sync.RWMutex
+	agents map[string]*AgentStatus
+}
+
+// NewController creates a controller that serves batch to agents presenting
+// token. onReport, which may be nil, is called for every report received.
+func NewController(batch []byte, token string, onReport func(Report)) *Controller {
+	return &Controller{
+		batch:    batch,
+		token:    token,
+		onReport: onReport,
+		agents:   make(map[string]*AgentStatus),
+	}
+}
+
+// Run serves the controller API on listen until ctx is cancelled or the
+// server fails.
+func (c *Controller) Run(ctx context.Context, listen string) error {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: c.Handler(), ReadHeaderTimeout: 5 * time.Second}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = nil
+	case err = <-serveErr:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Handler returns the controller API:
+//
+//	GET  /healthz          controller liveness
+//	GET  /api/config       batch config for agents (YAML)
+//	POST /api/reports      results of an agent's run
+//	GET  /api/agents       latest state of every agent (503 if any is stale or failing)
+//	GET  /api/endpoints    latest result of every endpoint from every agent
+//
+// Every /api/ route requires the token, if one is set.
+func (c *Controller) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", c.handleHealth)
+	mux.HandleFunc("/api/config", c.authorized(c.handleConfig))
+	mux.HandleFunc("/api/reports", c.authorized(c.handleReport))
+	mux.HandleFunc("/api/agents", c.authorized(c.handleAgents))
+	mux.HandleFunc("/api/endpoints", c.authorized(c.handleEndpoints))
+	return mux
+}
+
+// authorized rejects requests without the controller's bearer token.
+func (c *Controller) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if c.token != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing token"})
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// handleHealth reports that the controller is running.
+func (c *Controller) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleConfig serves the batch config agents run.
+func (c *Controller) handleConfig(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(c.batch)
+}
+
+// handleReport records the results of an agent's run.
+func (c *Controller) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+
+	var report Report
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportSize))
+	if err := decoder.Decode(&report); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid report: " + err.Error()})
+		return
+	}
+	if report.Agent == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid report: missing agent name"})
+		return
+	}
+	if report.Time.IsZero() {
+		report.Time = time.Now()
+	}
+
+	c.record(report, r.RemoteAddr)
+	if c.onReport != nil {
+		c.onReport(report)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// record stores a report as the agent's latest state.
+func (c *Controller) record(report Report, address string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status, ok := c.agents[report.Agent]
+	if !ok {
+		status = &AgentStatus{Name: report.Agent}
+		c.agents[report.Agent] = status
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	result := report.Result
+	status.Address = address
+	status.LastReport = time.Now() // Not the agent's clock, which may be off
+	status.Interval = report.Interval
+	status.Reports++
+	status.Result = &result
+}
+
+// Agents returns the latest state of every agent, sorted by name.
+func (c *Controller) Agents() []AgentStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	agents := make([]AgentStatus, 0, len(c.agents))
+	for _, status := range c.agents {
+		snapshot := *status
+		snapshot.Stale = stale(snapshot, now)
+		agents = append(agents, snapshot)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Name < agents[j].Name })
+	return agents
+}
+
+// stale reports whether an agent missed its last three runs.
+func stale(status AgentStatus, now time.Time) bool {
+	if status.Interval <= 0 {
+		return false
+	}
+	interval := time.Duration(status.Interval * float64(time.Second))
+	return now.Sub(status.LastReport) > 3*interval
+}
+
+// Endpoints returns the latest result of every endpoint from every agent
+// that isn't stale, sorted by name and URL.
+func (c *Controller) Endpoints() []EndpointStatus {
+	byKey := make(map[string]*EndpointStatus)
+	for _, agent := range c.Agents() {
+		if agent.Stale || agent.Result == nil {
+			continue
+		}
+		for _, endpoint := range agent.Result.Results {
+			key := endpoint.Name + "\x00" + endpoint.URL
+			status, ok := byKey[key]
+			if !ok {
+				status = &EndpointStatus{Name: endpoint.Name, URL: endpoint.URL, Agents: make(map[string]AgentResult)}
+				byKey[key] = status
+			}
+			status.Agents[agent.Name] = AgentResult{
+				Success: endpoint.Success,
+				Status:  endpoint.Status,
+				Latency: endpoint.Latency,
+				Error:   endpoint.Error,
+			}
+			if endpoint.Success {
+				status.Passing++
+			} else {
+				status.Failing++
+			}
+		}
+	}
+
+	endpoints := make([]EndpointStatus, 0, len(byKey))
+	for _, status := range byKey {
+		endpoints = append(endpoints, *status)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Name != endpoints[j].Name {
+			return endpoints[i].Name < endpoints[j].Name
+		}
+		return endpoints[i].URL < endpoints[j].URL
+	})
+	return endpoints
+}
+
+// handleAgents serves the state of every agent. It responds with 503 if any
+// agent is stale or reported failures, so it can be used as a health check.
+func (c *Controller) handleAgents(w http.ResponseWriter, r *http.Request) {
+	agents := c.Agents()
+
+	code := http.StatusOK
+	for _, agent := range agents {
+		if agent.Stale || (agent.Result != nil && agent.Result.Failed > 0) {
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	writeJSON(w, code, map[string]interface{}{"agents": agents})
+}
+
+// handleEndpoints serves the results of every endpoint across agents.
+func (c *Controller) handleEndpoints(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"endpoints": c.Endpoints()})
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
@@ -0,0 +1,280 @@
+// Package alert decides when an endpoint's state is worth notifying about
+// and delivers the resulting events to Slack, webhooks and email.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// Kinds of alert events.
+const (
+	KindDown      = "down"       // Endpoint started failing
+	KindRecovered = "recovered"  // Failing endpoint is healthy again
+	KindSlow      = "slow"       // Latency crossed the threshold
+	KindLatencyOK = "latency_ok" // Latency is back under the threshold
+	KindFlapping  = "flapping"   // Endpoint keeps alternating between up and down
+	KindStable    = "stable"     // Flapping endpoint has settled
+)
+
+// Event is a state change of a monitored endpoint.
+type Event struct {
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	URL       string        `json:"url"`
+	Status    int           `json:"status"`
+	Latency   time.Duration `json:"-"`
+	LatencyMs float64       `json:"latency_ms"`
+	Message   string        `json:"message,omitempty"`
+	Failures  int           `json:"consecutive_failures"`
+	Changes   int           `json:"state_changes,omitempty"` // Of a flapping endpoint, in the flap window
+	Time      time.Time     `json:"time"`
+}
+
+// Summary returns a one-line human-readable description of the event.
+func (e Event) Summary() string {
+	switch e.Kind {
+	case KindDown:
+		return fmt.Sprintf("🔴 %s is DOWN after %d failed checks: %s", e.Name, e.Failures, e.Message)
+	case KindRecovered:
+		return fmt.Sprintf("🟢 %s has RECOVERED (status %d, %s)", e.Name, e.Status, e.Latency.Round(time.Millisecond))
+	case KindSlow:
+		return fmt.Sprintf("🟡 %s is SLOW: %s", e.Name, e.Latency.Round(time.Millisecond))
+	case KindLatencyOK:
+		return fmt.Sprintf("🟢 %s latency is back to normal: %s", e.Name, e.Latency.Round(time.Millisecond))
+	case KindFlapping:
+		return fmt.Sprintf("🟠 %s is FLAPPING: %s", e.Name, e.Message)
+	case KindStable:
+		return fmt.Sprintf("🟢 %s has stopped flapping", e.Name)
+	}
+	return fmt.Sprintf("%s: %s", e.Name, e.Kind)
+}
+
+// Resolves reports whether the event ends an earlier alert.
+func (e Event) Resolves() bool {
+	return e.Kind == KindRecovered || e.Kind == KindLatencyOK || e.Kind == KindStable
+}
+
+// Category returns the condition the event belongs to ("down", "slow" or
+// "flapping"), so a resolving event can be matched with the alert it ends.
+func (e Event) Category() string {
+	switch e.Kind {
+	case KindSlow, KindLatencyOK:
+		return KindSlow
+	case KindFlapping, KindStable:
+		return KindFlapping
+	}
+	return KindDown
+}
+
+// Check is the outcome of one request, as fed to Manager.Observe.
+type Check struct {
+	Name    string
+	URL     string
+	Success bool
+	Status  int
+	Latency time.Duration
+	Message string
+}
+
+// Notifier delivers events to one channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, event Event) error
+}
+
+// endpointState tracks alerting state for one endpoint.
+type endpointState struct {
+	health    stats.Hysteresis // Debounced up/down state and flapping
+	slowCount int
+	down      bool
+	slow      bool
+	lastDown  time.Time
+	lastSlow  time.Time
+}
+
+// Manager turns checks into debounced events and sends them to notifiers.
+// It is safe for concurrent use.
+type Manager struct {
+	cfg       config.AlertConfig
+	notifiers []Notifier
+	onError   func(notifier string, err error)
+	now       func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*endpointState
+	wg     sync.WaitGroup
+}
+
+// NewManager creates a manager sending to the notifiers configured in cfg.
+// onError, if not nil, is called when a notification fails.
+func NewManager(cfg *config.AlertConfig, onError func(notifier string, err error)) *Manager {
+	return &Manager{
+		cfg:       *cfg,
+		notifiers: notifiersFor(cfg),
+		onError:   onError,
+		now:       time.Now,
+		states:    make(map[string]*endpointState),
+	}
+}
+
+// Observe records a check and sends any resulting events in the background.
+// It returns the events so callers can also display them.
+func (m *Manager) Observe(check Check) []Event {
+	events := m.evaluate(check)
+	for _, event := range events {
+		m.send(event)
+	}
+	return events
+}
+
+// Wait blocks until all notifications in flight have been sent.
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// Hysteresis returns a new state tracker with the manager's failure,
+// recovery and flap thresholds, so callers can follow an endpoint's state as
+// the manager sees it.
+func (m *Manager) Hysteresis() stats.Hysteresis {
+	return stats.Hysteresis{
+		FailureThreshold:  m.cfg.FailureThreshold,
+		RecoveryThreshold: m.cfg.RecoveryThreshold,
+		FlapWindow:        m.cfg.FlapWindow,
+		FlapThreshold:     m.cfg.FlapThreshold,
+	}
+}
+
+// evaluate updates the endpoint state and returns the events it triggers.
+// An endpoint is down after FailureThreshold consecutive failures, recovers
+// after RecoveryThreshold consecutive successes, and is slow after
+// FailureThreshold consecutive slow responses; while the condition lasts the
+// alert is repeated at most once per Cooldown. A flapping endpoint raises a
+// single flapping alert instead of going down and recovering over and over.
+func (m *Manager) evaluate(check Check) []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[check.Name]
+	if !ok {
+		state = &endpointState{health: m.Hysteresis()}
+		m.states[check.Name] = state
+	}
+
+	now := m.now()
+	event := Event{
+		Name:      check.Name,
+		URL:       check.URL,
+		Status:    check.Status,
+		Latency:   check.Latency,
+		LatencyMs: float64(check.Latency) / float64(time.Millisecond),
+		Message:   check.Message,
+		Time:      now,
+	}
+
+	var events []Event
+
+	wasFlapping := state.health.Flapping()
+	state.health.Observe(check.Success)
+	switch flapping := state.health.Flapping(); {
+	case flapping && !wasFlapping:
+		flap := event
+		flap.Kind = KindFlapping
+		flap.Changes = state.health.Changes()
+		flap.Message = fmt.Sprintf("%d state changes in the last %d checks", flap.Changes, state.health.Checks())
+		events = append(events, flap)
+	case !flapping && wasFlapping:
+		stable := event
+		stable.Kind = KindStable
+		events = append(events, stable)
+	}
+
+	// Down and recovered alerts wait until the endpoint stops flapping, when
+	// they catch up with its state
+	if !state.health.Flapping() {
+		if state.health.Down() {
+			if !state.down || (!check.Success && now.Sub(state.lastDown) >= m.cfg.Cooldown) {
+				state.down = true
+				state.lastDown = now
+				event.Kind = KindDown
+				event.Failures = state.health.Failures()
+				events = append(events, event)
+			}
+		} else if state.down {
+			state.down = false
+			event.Kind = KindRecovered
+			events = append(events, event)
+		}
+	}
+
+	if !check.Success {
+		state.slowCount = 0
+		return events
+	}
+
+	if m.cfg.LatencyThreshold <= 0 {
+		return events
+	}
+
+	if check.Latency > m.cfg.LatencyThreshold {
+		state.slowCount++
+		if state.slowCount >= m.cfg.FailureThreshold && (!state.slow || now.Sub(state.lastSlow) >= m.cfg.Cooldown) {
+			state.slow = true
+			state.lastSlow = now
+			event.Kind = KindSlow
+			events = append(events, event)
+		}
+	} else {
+		state.slowCount = 0
+		if state.slow {
+			state.slow = false
+			event.Kind = KindLatencyOK
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// send delivers event to every notifier concurrently.
+func (m *Manager) send(event Event) {
+	for _, notifier := range m.notifiers {
+		m.wg.Add(1)
+		go func(notifier Notifier) {
+			defer m.wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if err := notifier.Notify(ctx, event); err != nil && m.onError != nil {
+				m.onError(notifier.Name(), err)
+			}
+		}(notifier)
+	}
+}
+
+// notifiersFor builds the notifiers configured in cfg.
+func notifiersFor(cfg *config.AlertConfig) []Notifier {
+	var notifiers []Notifier
+	if cfg.Slack != nil {
+		notifiers = append(notifiers, &SlackNotifier{Config: *cfg.Slack})
+	}
+	for _, webhook := range cfg.Webhooks {
+		notifiers = append(notifiers, &WebhookNotifier{Config: webhook})
+	}
+	if cfg.Email != nil {
+		notifiers = append(notifiers, &EmailNotifier{Config: *cfg.Email})
+	}
+	if cfg.PagerDuty != nil {
+		notifiers = append(notifiers, &PagerDutyNotifier{Config: *cfg.PagerDuty})
+	}
+	if cfg.Opsgenie != nil {
+		notifiers = append(notifiers, &OpsgenieNotifier{Config: *cfg.Opsgenie})
+	}
+	return notifiers
+}
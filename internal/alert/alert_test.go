@@ -0,0 +1,178 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+func TestManagerEvaluate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewManager(&config.AlertConfig{
+		FailureThreshold: 2,
+		LatencyThreshold: time.Second,
+		Cooldown:         10 * time.Minute,
+	}, nil)
+	m.now = func() time.Time { return now }
+
+	ok := Check{Name: "api", Success: true, Status: 200, Latency: 100 * time.Millisecond}
+	fail := Check{Name: "api", Status: 500, Message: "Expected 200, got 500"}
+	slow := Check{Name: "api", Success: true, Status: 200, Latency: 2 * time.Second}
+
+	steps := []struct {
+		name    string
+		check   Check
+		advance time.Duration
+		want    string // Expected event kind ("" = none)
+	}{
+		{"healthy", ok, 0, ""},
+		{"first failure is debounced", fail, 0, ""},
+		{"second failure alerts", fail, 0, KindDown},
+		{"still down within cooldown", fail, time.Minute, ""},
+		{"reminder after cooldown", fail, 10 * time.Minute, KindDown},
+		{"recovery", ok, 0, KindRecovered},
+		{"first slow response is debounced", slow, 0, ""},
+		{"second slow response alerts", slow, 0, KindSlow},
+		{"still slow", slow, 0, ""},
+		{"latency back to normal", ok, 0, KindLatencyOK},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		events := m.evaluate(step.check)
+
+		got := ""
+		if len(events) > 0 {
+			got = events[0].Kind
+		}
+		if got != step.want || len(events) > 1 {
+			t.Errorf("%s: events = %v, want kind %q", step.name, events, step.want)
+		}
+	}
+}
+
+func TestManagerHysteresisAndFlapping(t *testing.T) {
+	m := NewManager(&config.AlertConfig{
+		FailureThreshold:  2,
+		RecoveryThreshold: 2,
+		FlapWindow:        10,
+		FlapThreshold:     4,
+		Cooldown:          time.Hour,
+	}, nil)
+
+	ok := Check{Name: "api", Success: true, Status: 200}
+	fail := Check{Name: "api", Status: 500}
+
+	steps := []struct {
+		name  string
+		check Check
+		want  []string // Expected event kinds
+	}{
+		{"down after two failures", fail, nil},
+		{"down", fail, []string{KindDown}},
+		{"one success doesn't recover", ok, nil},
+		{"failing again", fail, nil},
+		{"still not recovered", ok, nil},
+		{"fourth change starts flapping", fail, []string{KindFlapping}},
+		{"no down alerts while flapping", ok, nil},
+		{"no recovery while flapping", ok, nil},
+		{"settling", ok, nil},
+		{"settling more", ok, nil},
+		{"settling still", ok, nil},
+		{"changes still in the window", ok, nil},
+		{"three changes left", ok, nil},
+		{"changes drop to two", ok, []string{KindStable, KindRecovered}},
+		{"healthy", ok, nil},
+	}
+
+	for _, step := range steps {
+		var got []string
+		for _, event := range m.evaluate(step.check) {
+			got = append(got, event.Kind)
+		}
+		if strings.Join(got, ",") != strings.Join(step.want, ",") {
+			t.Errorf("%s: events = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestEventCategory(t *testing.T) {
+	tests := []struct {
+		kind     string
+		category string
+		resolves bool
+	}{
+		{KindDown, KindDown, false},
+		{KindRecovered, KindDown, true},
+		{KindSlow, KindSlow, false},
+		{KindLatencyOK, KindSlow, true},
+		{KindFlapping, KindFlapping, false},
+		{KindStable, KindFlapping, true},
+	}
+	for _, tt := range tests {
+		event := Event{Kind: tt.kind}
+		if event.Category() != tt.category || event.Resolves() != tt.resolves {
+			t.Errorf("%s: Category() = %q, Resolves() = %v; want %q, %v",
+				tt.kind, event.Category(), event.Resolves(), tt.category, tt.resolves)
+		}
+	}
+}
+
+func TestManagerTracksEndpointsSeparately(t *testing.T) {
+	m := NewManager(&config.AlertConfig{FailureThreshold: 1, Cooldown: time.Hour}, nil)
+
+	if events := m.evaluate(Check{Name: "a"}); len(events) != 1 {
+		t.Errorf("first failure of a: %d events, want 1", len(events))
+	}
+	if events := m.evaluate(Check{Name: "b"}); len(events) != 1 {
+		t.Errorf("first failure of b: %d events, want 1", len(events))
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received map[string]interface{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := &WebhookNotifier{Config: config.WebhookAlert{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}}
+
+	event := Event{Kind: KindDown, Name: "api", Status: 503, Failures: 3, Message: "Expected 200, got 503"}
+	if err := notifier.Notify(context.Background(), event); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer secret")
+	}
+	if received["kind"] != KindDown || received["name"] != "api" {
+		t.Errorf("payload = %v, want kind down for api", received)
+	}
+	if received["summary"] != event.Summary() {
+		t.Errorf("summary = %v, want %q", received["summary"], event.Summary())
+	}
+}
+
+func TestPostJSONFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	notifier := &SlackNotifier{Config: config.SlackAlert{WebhookURL: server.URL}}
+	if err := notifier.Notify(context.Background(), Event{Kind: KindDown, Name: "api"}); err == nil {
+		t.Error("Notify() error = nil, want error for 403 response")
+	}
+}
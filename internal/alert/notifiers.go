@@ -0,0 +1,130 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// SlackNotifier posts events to a Slack incoming webhook.
+type SlackNotifier struct {
+	Config config.SlackAlert
+}
+
+// Name implements Notifier.
+func (n *SlackNotifier) Name() string { return "slack" }
+
+// Notify implements Notifier.
+func (n *SlackNotifier) Notify(ctx context.Context, event Event) error {
+	payload := map[string]string{
+		"text": fmt.Sprintf("%s\n%s", event.Summary(), event.URL),
+	}
+	if n.Config.Channel != "" {
+		payload["channel"] = n.Config.Channel
+	}
+	return postJSON(ctx, n.Config.WebhookURL, nil, payload)
+}
+
+// WebhookNotifier posts events as JSON to an arbitrary URL.
+type WebhookNotifier struct {
+	Config config.WebhookAlert
+}
+
+// Name implements Notifier.
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+// Notify implements Notifier. The body is the Event plus a "summary" field.
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	payload := struct {
+		Event
+		Summary string `json:"summary"`
+	}{event, event.Summary()}
+
+	return postJSON(ctx, n.Config.URL, n.Config.Headers, payload)
+}
+
+// EmailNotifier sends events by email through SMTP.
+type EmailNotifier struct {
+	Config config.EmailAlert
+}
+
+// Name implements Notifier.
+func (n *EmailNotifier) Name() string { return "email" }
+
+// Notify implements Notifier.
+func (n *EmailNotifier) Notify(ctx context.Context, event Event) error {
+	addr := net.JoinHostPort(n.Config.Host, strconv.Itoa(n.Config.Port))
+
+	var auth smtp.Auth
+	if n.Config.Username != "" {
+		auth = smtp.PlainAuth("", n.Config.Username, n.Config.Password, n.Config.Host)
+	}
+
+	// net/smtp has no context support; run it so ctx can still bound the wait
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, n.Config.From, n.Config.To, emailMessage(n.Config, event))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// emailMessage builds an RFC 5322 message for event.
+func emailMessage(cfg config.EmailAlert, event Event) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [tapr] %s %s\r\n", event.Name, strings.ToUpper(event.Kind))
+	fmt.Fprintf(&msg, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\n", event.Summary())
+	fmt.Fprintf(&msg, "URL:     %s\r\n", event.URL)
+	fmt.Fprintf(&msg, "Status:  %d\r\n", event.Status)
+	fmt.Fprintf(&msg, "Latency: %s\r\n", event.Latency.Round(time.Millisecond))
+	fmt.Fprintf(&msg, "Time:    %s\r\n", event.Time.Format(time.RFC3339))
+	return []byte(msg.String())
+}
+
+// postJSON sends payload as a JSON POST and fails on non-2xx responses.
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return nil
+}
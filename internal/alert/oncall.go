@@ -0,0 +1,138 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// Default on-call API endpoints.
+const (
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieUSURL      = "https://api.opsgenie.com/v2/alerts"
+	opsgenieEUURL      = "https://api.eu.opsgenie.com/v2/alerts"
+)
+
+// incidentKey identifies the incident an event opens or resolves, so the
+// recovery of an endpoint closes the same incident its failure opened.
+func incidentKey(event Event) string {
+	return "tapr/" + event.Name + "/" + event.Category()
+}
+
+// details returns the event fields attached to on-call incidents.
+func details(event Event) map[string]interface{} {
+	return map[string]interface{}{
+		"url":                  event.URL,
+		"status":               event.Status,
+		"latency_ms":           event.LatencyMs,
+		"message":              event.Message,
+		"consecutive_failures": event.Failures,
+	}
+}
+
+// PagerDutyNotifier sends events to the PagerDuty Events API v2.
+type PagerDutyNotifier struct {
+	Config config.PagerDutyAlert
+	URL    string // Overrides the Events API URL (for testing)
+}
+
+// Name implements Notifier.
+func (n *PagerDutyNotifier) Name() string { return "pagerduty" }
+
+// Notify implements Notifier. Down and slow events trigger an incident;
+// recoveries resolve it unless auto_resolve is disabled.
+func (n *PagerDutyNotifier) Notify(ctx context.Context, event Event) error {
+	endpoint := n.URL
+	if endpoint == "" {
+		endpoint = pagerDutyEventsURL
+	}
+
+	payload := map[string]interface{}{
+		"routing_key": n.Config.RoutingKey,
+		"dedup_key":   incidentKey(event),
+	}
+
+	if event.Resolves() {
+		if n.Config.AutoResolve != nil && !*n.Config.AutoResolve {
+			return nil
+		}
+		payload["event_action"] = "resolve"
+		return postJSON(ctx, endpoint, nil, payload)
+	}
+
+	payload["event_action"] = "trigger"
+	payload["payload"] = map[string]interface{}{
+		"summary":        event.Summary(),
+		"source":         event.URL,
+		"severity":       n.Config.Severity[event.Category()],
+		"timestamp":      event.Time.Format(time.RFC3339),
+		"component":      event.Name,
+		"custom_details": details(event),
+	}
+	return postJSON(ctx, endpoint, nil, payload)
+}
+
+// OpsgenieNotifier sends events to the Opsgenie Alert API.
+type OpsgenieNotifier struct {
+	Config config.OpsgenieAlert
+	URL    string // Overrides the Alert API URL (for testing)
+}
+
+// Name implements Notifier.
+func (n *OpsgenieNotifier) Name() string { return "opsgenie" }
+
+// Notify implements Notifier. Down and slow events create an alert;
+// recoveries close it unless auto_close is disabled.
+func (n *OpsgenieNotifier) Notify(ctx context.Context, event Event) error {
+	endpoint := n.URL
+	if endpoint == "" {
+		endpoint = opsgenieUSURL
+		if n.Config.Region == "eu" {
+			endpoint = opsgenieEUURL
+		}
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + n.Config.APIKey}
+	alias := incidentKey(event)
+
+	if event.Resolves() {
+		if n.Config.AutoClose != nil && !*n.Config.AutoClose {
+			return nil
+		}
+		closeURL := endpoint + "/" + url.PathEscape(alias) + "/close?identifierType=alias"
+		return postJSON(ctx, closeURL, headers, map[string]string{
+			"source": "tapr",
+			"note":   event.Summary(),
+		})
+	}
+
+	return postJSON(ctx, endpoint, headers, map[string]interface{}{
+		"message":     truncate(event.Summary(), 130),
+		"alias":       alias,
+		"description": event.Message,
+		"priority":    n.Config.Priority[event.Category()],
+		"source":      "tapr",
+		"entity":      event.Name,
+		"details":     stringDetails(event),
+	})
+}
+
+// stringDetails returns details as strings, as Opsgenie requires.
+func stringDetails(event Event) map[string]string {
+	result := make(map[string]string)
+	for key, value := range details(event) {
+		result[key] = fmt.Sprint(value)
+	}
+	return result
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// captureServer records the path and JSON body of each request it receives.
+func captureServer(t *testing.T) (*httptest.Server, *[]string, *[]map[string]interface{}) {
+	t.Helper()
+
+	var paths []string
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.RequestURI())
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &paths, &bodies
+}
+
+func TestPagerDutyNotifier(t *testing.T) {
+	server, _, bodies := captureServer(t)
+
+	notifier := &PagerDutyNotifier{
+		Config: config.PagerDutyAlert{RoutingKey: "key123", Severity: config.DefaultPagerDutySeverity},
+		URL:    server.URL,
+	}
+
+	for _, kind := range []string{KindDown, KindRecovered} {
+		if err := notifier.Notify(context.Background(), Event{Kind: kind, Name: "api"}); err != nil {
+			t.Fatalf("Notify(%s) error = %v", kind, err)
+		}
+	}
+
+	trigger, resolve := (*bodies)[0], (*bodies)[1]
+	if trigger["event_action"] != "trigger" || resolve["event_action"] != "resolve" {
+		t.Errorf("event_action = %v, %v; want trigger, resolve", trigger["event_action"], resolve["event_action"])
+	}
+	if trigger["dedup_key"] != resolve["dedup_key"] {
+		t.Errorf("dedup_key = %v, %v; want the same key", trigger["dedup_key"], resolve["dedup_key"])
+	}
+	payload := trigger["payload"].(map[string]interface{})
+	if payload["severity"] != "critical" {
+		t.Errorf("severity = %v, want critical", payload["severity"])
+	}
+}
+
+func TestPagerDutyNotifierAutoResolveDisabled(t *testing.T) {
+	server, paths, _ := captureServer(t)
+
+	disabled := false
+	notifier := &PagerDutyNotifier{
+		Config: config.PagerDutyAlert{RoutingKey: "key123", AutoResolve: &disabled},
+		URL:    server.URL,
+	}
+
+	if err := notifier.Notify(context.Background(), Event{Kind: KindRecovered, Name: "api"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(*paths) != 0 {
+		t.Errorf("sent %d requests, want none with auto_resolve disabled", len(*paths))
+	}
+}
+
+func TestOpsgenieNotifier(t *testing.T) {
+	server, paths, bodies := captureServer(t)
+
+	notifier := &OpsgenieNotifier{
+		Config: config.OpsgenieAlert{APIKey: "genie", Priority: config.DefaultOpsgeniePriority},
+		URL:    server.URL + "/v2/alerts",
+	}
+
+	for _, kind := range []string{KindSlow, KindLatencyOK} {
+		if err := notifier.Notify(context.Background(), Event{Kind: kind, Name: "api"}); err != nil {
+			t.Fatalf("Notify(%s) error = %v", kind, err)
+		}
+	}
+
+	if (*bodies)[0]["priority"] != "P3" {
+		t.Errorf("priority = %v, want P3 for slow", (*bodies)[0]["priority"])
+	}
+	if (*bodies)[0]["alias"] != "tapr/api/slow" {
+		t.Errorf("alias = %v, want tapr/api/slow", (*bodies)[0]["alias"])
+	}
+	wantClose := "/v2/alerts/tapr%2Fapi%2Fslow/close?identifierType=alias"
+	if (*paths)[1] != wantClose {
+		t.Errorf("close path = %s, want %s", (*paths)[1], wantClose)
+	}
+}
@@ -0,0 +1,111 @@
+// Package assert provides response assertions (headers, body values, sizes)
+// that turn a successful HTTP response into a pass or fail result.
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Header matching modes
+const (
+	MatchExact   = "exact"   // Value must equal the expected value
+	MatchPrefix  = "prefix"  // Value must start with the expected value
+	MatchRegex   = "regex"   // Value must match the regular expression
+	MatchPresent = "present" // Header must exist, any value
+)
+
+// HeaderExpectation describes a single expected response header.
+type HeaderExpectation struct {
+	Name  string // Header name (case-insensitive)
+	Mode  string // One of the Match* modes
+	Value string // Expected value, prefix or pattern
+
+	pattern *regexp.Regexp
+}
+
+// ParseHeaderExpectations converts a map of header name to expected value
+// into expectations. Values use the following syntax:
+//
+//	application/json        exact match
+//	prefix:application/     prefix match
+//	regex:^application/.*$  regular expression match
+//	(empty)                 header must be present
+//
+// Expectations are returned sorted by header name so failures are reported
+// deterministically.
+func ParseHeaderExpectations(expected map[string]string) ([]HeaderExpectation, error) {
+	expectations := make([]HeaderExpectation, 0, len(expected))
+
+	for name, value := range expected {
+		mode, operand, pattern, err := parseMatch(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for header '%s': %w", name, err)
+		}
+		expectations = append(expectations, HeaderExpectation{Name: name, Mode: mode, Value: operand, pattern: pattern})
+	}
+
+	sort.Slice(expectations, func(i, j int) bool {
+		return expectations[i].Name < expectations[j].Name
+	})
+
+	return expectations, nil
+}
+
+// parseMatch splits an expected value into its matching mode and the value,
+// prefix or pattern to match (see ParseHeaderExpectations for the syntax).
+func parseMatch(value string) (mode, operand string, pattern *regexp.Regexp, err error) {
+	switch {
+	case value == "":
+		return MatchPresent, "", nil, nil
+	case strings.HasPrefix(value, "prefix:"):
+		return MatchPrefix, strings.TrimPrefix(value, "prefix:"), nil, nil
+	case strings.HasPrefix(value, "regex:"):
+		operand = strings.TrimPrefix(value, "regex:")
+		pattern, err = regexp.Compile(operand)
+		return MatchRegex, operand, pattern, err
+	default:
+		return MatchExact, value, nil, nil
+	}
+}
+
+// Check verifies a single expectation against the response headers.
+func (e HeaderExpectation) Check(headers http.Header) error {
+	values, ok := headers[http.CanonicalHeaderKey(e.Name)]
+	if !ok || len(values) == 0 {
+		return fmt.Errorf("header %s missing", e.Name)
+	}
+	actual := strings.Join(values, ", ")
+
+	switch e.Mode {
+	case MatchPresent:
+		return nil
+	case MatchExact:
+		if actual != e.Value {
+			return fmt.Errorf("header %s: expected %q, got %q", e.Name, e.Value, actual)
+		}
+	case MatchPrefix:
+		if !strings.HasPrefix(actual, e.Value) {
+			return fmt.Errorf("header %s: expected prefix %q, got %q", e.Name, e.Value, actual)
+		}
+	case MatchRegex:
+		if !e.pattern.MatchString(actual) {
+			return fmt.Errorf("header %s: %q does not match /%s/", e.Name, actual, e.Value)
+		}
+	}
+
+	return nil
+}
+
+// CheckHeaders verifies all expectations and returns the first failure.
+func CheckHeaders(headers http.Header, expectations []HeaderExpectation) error {
+	for _, exp := range expectations {
+		if err := exp.Check(headers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
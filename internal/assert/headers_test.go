@@ -0,0 +1,77 @@
+package assert
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseHeaderExpectations(t *testing.T) {
+	expectations, err := ParseHeaderExpectations(map[string]string{
+		"Content-Type":  "application/json",
+		"Cache-Control": "prefix:max-age",
+		"X-Request-Id":  "regex:^[a-f0-9-]+$",
+		"ETag":          "",
+	})
+	if err != nil {
+		t.Fatalf("ParseHeaderExpectations() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Cache-Control": MatchPrefix,
+		"Content-Type":  MatchExact,
+		"ETag":          MatchPresent,
+		"X-Request-Id":  MatchRegex,
+	}
+
+	if len(expectations) != len(want) {
+		t.Fatalf("got %d expectations, want %d", len(expectations), len(want))
+	}
+	for _, exp := range expectations {
+		if exp.Mode != want[exp.Name] {
+			t.Errorf("%s: Mode = %s, want %s", exp.Name, exp.Mode, want[exp.Name])
+		}
+	}
+}
+
+func TestParseHeaderExpectations_InvalidRegex(t *testing.T) {
+	_, err := ParseHeaderExpectations(map[string]string{"X-Bad": "regex:(["})
+	if err == nil {
+		t.Error("ParseHeaderExpectations() expected error for invalid regex")
+	}
+}
+
+func TestCheckHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json; charset=utf-8")
+	headers.Set("X-Version", "1.4.2")
+
+	tests := []struct {
+		name     string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"exact match", map[string]string{"X-Version": "1.4.2"}, false},
+		{"exact mismatch", map[string]string{"X-Version": "1.4.3"}, true},
+		{"prefix match", map[string]string{"Content-Type": "prefix:application/json"}, false},
+		{"prefix mismatch", map[string]string{"Content-Type": "prefix:text/"}, true},
+		{"regex match", map[string]string{"X-Version": `regex:^1\.\d+\.\d+$`}, false},
+		{"regex mismatch", map[string]string{"X-Version": `regex:^2\.`}, true},
+		{"case-insensitive name", map[string]string{"x-version": "1.4.2"}, false},
+		{"present", map[string]string{"Content-Type": ""}, false},
+		{"missing", map[string]string{"X-Missing": ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectations, err := ParseHeaderExpectations(tt.expected)
+			if err != nil {
+				t.Fatalf("ParseHeaderExpectations() error = %v", err)
+			}
+
+			err = CheckHeaders(headers, expectations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
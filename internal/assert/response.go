@@ -0,0 +1,55 @@
+package assert
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CheckSize verifies that a response body of size bytes is within
+// [minSize, maxSize]. A zero limit is not checked; a negative size means
+// the size is unknown.
+func CheckSize(size, minSize, maxSize int64) error {
+	if minSize == 0 && maxSize == 0 {
+		return nil
+	}
+	if size < 0 {
+		return fmt.Errorf("response size unknown")
+	}
+	if minSize > 0 && size < minSize {
+		return fmt.Errorf("response size %d bytes below min %d", size, minSize)
+	}
+	if maxSize > 0 && size > maxSize {
+		return fmt.Errorf("response size %d bytes exceeds max %d", size, maxSize)
+	}
+	return nil
+}
+
+// CheckRedirect verifies that a request to requested ended up at expected
+// after following redirects. An expected value starting with "/" is
+// compared with the path (and query, if it has one) of the final URL only.
+func CheckRedirect(requested, final, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	if final == "" || final == requested {
+		return fmt.Errorf("expected redirect to %s, got no redirect", expected)
+	}
+
+	actual := final
+	if strings.HasPrefix(expected, "/") {
+		parsed, err := url.Parse(final)
+		if err != nil {
+			return fmt.Errorf("invalid redirect URL %s: %w", final, err)
+		}
+		actual = parsed.Path
+		if strings.Contains(expected, "?") {
+			actual = parsed.RequestURI()
+		}
+	}
+
+	if actual != expected {
+		return fmt.Errorf("expected redirect to %s, got %s", expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package assert
+
+import "testing"
+
+func TestCheckSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int64
+		min, max int64
+		wantErr  bool
+	}{
+		{"no limits", 10, 0, 0, false},
+		{"no limits, unknown size", -1, 0, 0, false},
+		{"within range", 500, 100, 1000, false},
+		{"at limits", 100, 100, 100, false},
+		{"below min", 99, 100, 0, true},
+		{"above max", 1001, 0, 1000, true},
+		{"unknown size", -1, 100, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSize(tt.size, tt.min, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckSize(%d, %d, %d) error = %v, wantErr %v", tt.size, tt.min, tt.max, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		final     string
+		expected  string
+		wantErr   bool
+	}{
+		{"no expectation", "http://a.io/", "http://a.io/", "", false},
+		{"full URL", "http://a.io/", "https://a.io/login", "https://a.io/login", false},
+		{"path only", "http://a.io/", "https://a.io/login?next=%2F", "/login", false},
+		{"path and query", "http://a.io/", "https://a.io/login?next=%2F", "/login?next=%2F", false},
+		{"wrong target", "http://a.io/", "https://a.io/home", "/login", true},
+		{"no redirect", "http://a.io/", "http://a.io/", "/login", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRedirect(tt.requested, tt.final, tt.expected)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package assert
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/xpath"
+)
+
+// XPathExpectation describes a value expected in an XML response body.
+type XPathExpectation struct {
+	Path  string // XPath expression (see package xpath)
+	Mode  string // One of the Match* modes
+	Value string // Expected value, prefix or pattern
+
+	expr    xpath.Expr
+	pattern *regexp.Regexp
+}
+
+// ParseXPathExpectations converts a map of XPath expression to expected
+// value into expectations. Values use the same syntax as
+// ParseHeaderExpectations; an empty value means the path must match
+// something. Expectations are returned sorted by path.
+func ParseXPathExpectations(expected map[string]string) ([]XPathExpectation, error) {
+	expectations := make([]XPathExpectation, 0, len(expected))
+
+	for path, value := range expected {
+		expr, err := xpath.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+		mode, operand, pattern, err := parseMatch(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for xpath '%s': %w", path, err)
+		}
+		expectations = append(expectations, XPathExpectation{Path: path, Mode: mode, Value: operand, expr: expr, pattern: pattern})
+	}
+
+	sort.Slice(expectations, func(i, j int) bool {
+		return expectations[i].Path < expectations[j].Path
+	})
+
+	return expectations, nil
+}
+
+// Check verifies a single expectation against a parsed XML document.
+func (e XPathExpectation) Check(doc *xpath.Node) error {
+	actual, err := e.expr.Evaluate(doc)
+	if err != nil {
+		return fmt.Errorf("xpath %w", err)
+	}
+
+	switch e.Mode {
+	case MatchExact:
+		if actual != e.Value {
+			return fmt.Errorf("xpath %s: expected %q, got %q", e.Path, e.Value, actual)
+		}
+	case MatchPrefix:
+		if !strings.HasPrefix(actual, e.Value) {
+			return fmt.Errorf("xpath %s: expected prefix %q, got %q", e.Path, e.Value, actual)
+		}
+	case MatchRegex:
+		if !e.pattern.MatchString(actual) {
+			return fmt.Errorf("xpath %s: %q does not match /%s/", e.Path, actual, e.Value)
+		}
+	}
+
+	return nil
+}
+
+// CheckXPath parses an XML response body and verifies all expectations,
+// returning the first failure.
+func CheckXPath(body []byte, expectations []XPathExpectation) error {
+	if len(expectations) == 0 {
+		return nil
+	}
+
+	doc, err := xpath.ParseDocument(body)
+	if err != nil {
+		return fmt.Errorf("response body: %w", err)
+	}
+	for _, exp := range expectations {
+		if err := exp.Check(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
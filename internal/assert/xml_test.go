@@ -0,0 +1,67 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckXPath(t *testing.T) {
+	body := []byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetStatusResponse><Status>OK</Status><Version>2.4.1</Version><Queue depth="3"/></GetStatusResponse>
+  </soap:Body>
+</soap:Envelope>`)
+
+	tests := []struct {
+		name     string
+		expected map[string]string
+		wantErr  string
+	}{
+		{"exact match", map[string]string{"//Status": "OK"}, ""},
+		{"exact mismatch", map[string]string{"//Status": "DEGRADED"}, `xpath //Status: expected "DEGRADED", got "OK"`},
+		{"prefix match", map[string]string{"//Version": "prefix:2."}, ""},
+		{"regex match", map[string]string{"//Queue/@depth": `regex:^\d$`}, ""},
+		{"regex mismatch", map[string]string{"//Version": `regex:^3\.`}, `does not match /^3\./`},
+		{"present", map[string]string{"//soap:Body/GetStatusResponse": ""}, ""},
+		{"missing", map[string]string{"//Fault": ""}, "xpath //Fault matched nothing"},
+		{"count", map[string]string{"count(//Fault)": "0"}, ""},
+		{"first failure by path", map[string]string{"//Version": "1", "//Status": "DOWN"}, "xpath //Status:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectations, err := ParseXPathExpectations(tt.expected)
+			if err != nil {
+				t.Fatalf("ParseXPathExpectations() error = %v", err)
+			}
+
+			err = CheckXPath(body, expectations)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckXPath() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckXPath() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	expectations, _ := ParseXPathExpectations(map[string]string{"//Status": "OK"})
+	if err := CheckXPath([]byte(`{"status": "OK"}`), expectations); err == nil || !strings.Contains(err.Error(), "invalid XML") {
+		t.Errorf("CheckXPath() on JSON error = %v, want invalid XML", err)
+	}
+}
+
+func TestParseXPathExpectations_Invalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"bad path":  {"//Order[": "1"},
+		"bad regex": {"//Status": "regex:(["},
+	}
+	for name, expected := range tests {
+		if _, err := ParseXPathExpectations(expected); err == nil {
+			t.Errorf("%s: ParseXPathExpectations() expected an error", name)
+		}
+	}
+}
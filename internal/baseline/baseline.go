@@ -0,0 +1,189 @@
+// Package baseline stores the latency and success rate of a run in a file
+// that can be committed to a repository, and compares later runs against
+// it so CI can fail when performance regresses.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// Version is the format version written to baseline files.
+const Version = 1
+
+// Metric names, in the order they are reported.
+const (
+	Avg         = "avg"
+	P50         = "p50"
+	P95         = "p95"
+	P99         = "p99"
+	SuccessRate = "success_rate"
+)
+
+// minLatencyChange is the smallest latency increase in milliseconds that
+// counts as a regression, so jitter on very fast endpoints doesn't fail.
+const minLatencyChange = 5.0
+
+// Metrics lists every metric name, in report order.
+var Metrics = []string{Avg, P50, P95, P99, SuccessRate}
+
+// File is a saved baseline.
+type File struct {
+	Version   int                `json:"version"`
+	Command   string             `json:"command"`
+	Target    string             `json:"target"`
+	CreatedAt time.Time          `json:"created_at"`
+	Metrics   map[string]float64 `json:"metrics"` // Latencies in milliseconds, success rate in percent
+}
+
+// FromSummary takes the metrics of a run. Latency metrics are left out if
+// the run has no latency statistics.
+func FromSummary(summary output.RunSummary) File {
+	file := File{
+		Version:   Version,
+		Command:   summary.Command,
+		Target:    summary.Target,
+		CreatedAt: summary.StartedAt,
+		Metrics:   map[string]float64{SuccessRate: summary.SuccessRate},
+	}
+	if latency := summary.Latency; latency != nil {
+		file.Metrics[Avg] = latency.Avg
+		file.Metrics[P50] = latency.P50
+		file.Metrics[P95] = latency.P95
+		file.Metrics[P99] = latency.P99
+	}
+	return file
+}
+
+// Load reads a baseline file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if file.Version != Version {
+		return nil, fmt.Errorf("baseline %s has unsupported version %d", path, file.Version)
+	}
+	return &file, nil
+}
+
+// Save writes a baseline file as indented JSON.
+func Save(path string, file File) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Tolerances is how much each metric may regress: latencies in percent
+// above the baseline, the success rate in percentage points below it.
+// Metrics without a tolerance are reported but never fail.
+type Tolerances map[string]float64
+
+// DefaultTolerances gates on p95 latency only, allowing 10%.
+func DefaultTolerances() Tolerances {
+	return Tolerances{P95: 10}
+}
+
+// ParseTolerances applies specs such as "p95=15%", "p99=30" or
+// "success_rate=1" to the default tolerances. "off" stops gating on a
+// metric.
+func ParseTolerances(specs []string) (Tolerances, error) {
+	tolerances := DefaultTolerances()
+	for _, spec := range specs {
+		metric, value, ok := strings.Cut(spec, "=")
+		metric = strings.TrimSpace(metric)
+		if !ok || !known(metric) {
+			return nil, fmt.Errorf("invalid tolerance %q (want metric=percent, metric one of %s)", spec, strings.Join(Metrics, ", "))
+		}
+		value = strings.TrimSpace(value)
+		if value == "off" {
+			delete(tolerances, metric)
+			continue
+		}
+		allowed, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || allowed < 0 {
+			return nil, fmt.Errorf("invalid tolerance %q: %q is not a positive percentage", spec, value)
+		}
+		tolerances[metric] = allowed
+	}
+	return tolerances, nil
+}
+
+// known reports whether metric is a metric name.
+func known(metric string) bool {
+	for _, m := range Metrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Check is the comparison of one metric with the baseline.
+type Check struct {
+	Metric    string
+	Baseline  float64
+	Current   float64
+	Change    float64 // Percent for latencies, percentage points for the success rate
+	Allowed   float64 // Tolerance, if Gated
+	Gated     bool    // Whether the metric has a tolerance
+	Regressed bool
+}
+
+// Compare checks the metrics of a run against the baseline, in report
+// order. Metrics missing from either side are skipped. Latencies less than
+// 5ms above the baseline never regress.
+func Compare(base *File, current File, tolerances Tolerances) []Check {
+	var checks []Check
+	for _, metric := range Metrics {
+		before, ok := base.Metrics[metric]
+		if !ok {
+			continue
+		}
+		after, ok := current.Metrics[metric]
+		if !ok {
+			continue
+		}
+
+		check := Check{Metric: metric, Baseline: before, Current: after}
+		check.Allowed, check.Gated = tolerances[metric]
+		if metric == SuccessRate {
+			check.Change = after - before
+			check.Regressed = check.Gated && -check.Change > check.Allowed
+		} else {
+			if before <= 0 {
+				continue
+			}
+			check.Change = (after - before) / before * 100
+			check.Regressed = check.Gated && check.Change > check.Allowed && after-before >= minLatencyChange
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// Regressions returns how many checks regressed.
+func Regressions(checks []Check) int {
+	n := 0
+	for _, check := range checks {
+		if check.Regressed {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,85 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	summary := output.RunSummary{Command: "batch", Target: "endpoints.yml"}
+	summary.SuccessRate = 100
+	summary.Latency = &output.JSONLatency{Avg: 80, P50: 70, P95: 150, P99: 200}
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := Save(path, FromSummary(summary)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if file.Command != "batch" || file.Metrics[P95] != 150 || file.Metrics[SuccessRate] != 100 {
+		t.Errorf("Load() = %+v, want the saved batch metrics", file)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file should fail")
+	}
+}
+
+func TestParseTolerances(t *testing.T) {
+	tolerances, err := ParseTolerances([]string{"p99=25%", "success_rate=0.5", "p95=off"})
+	if err != nil {
+		t.Fatalf("ParseTolerances() error = %v", err)
+	}
+	if _, ok := tolerances[P95]; ok || tolerances[P99] != 25 || tolerances[SuccessRate] != 0.5 {
+		t.Errorf("ParseTolerances() = %v, want p99=25 and success_rate=0.5 without p95", tolerances)
+	}
+
+	if tolerances, _ := ParseTolerances(nil); tolerances[P95] != 10 {
+		t.Errorf("default p95 tolerance = %v, want 10", tolerances[P95])
+	}
+
+	for _, spec := range []string{"p90=10", "p95", "p95=fast", "p95=-5"} {
+		if _, err := ParseTolerances([]string{spec}); err == nil {
+			t.Errorf("ParseTolerances(%q) should fail", spec)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := &File{Metrics: map[string]float64{Avg: 100, P50: 90, P95: 200, P99: 300, SuccessRate: 100}}
+	current := File{Metrics: map[string]float64{Avg: 150, P50: 80, P95: 230, P99: 310, SuccessRate: 98}}
+
+	checks := Compare(base, current, Tolerances{P95: 10, P99: 10, SuccessRate: 1})
+	if len(checks) != 5 {
+		t.Fatalf("got %d checks, want 5", len(checks))
+	}
+
+	want := map[string]bool{Avg: false, P50: false, P95: true, P99: false, SuccessRate: true}
+	for _, check := range checks {
+		if check.Regressed != want[check.Metric] {
+			t.Errorf("%s: regressed = %v (change %.1f), want %v", check.Metric, check.Regressed, check.Change, want[check.Metric])
+		}
+	}
+	if checks[0].Gated || checks[0].Change != 50 {
+		t.Errorf("avg = %+v, want ungated with a 50%% change", checks[0])
+	}
+	if Regressions(checks) != 2 {
+		t.Errorf("Regressions() = %d, want 2", Regressions(checks))
+	}
+
+	// Tiny latencies may jitter by more than the tolerance
+	tiny := Compare(&File{Metrics: map[string]float64{P95: 2}}, File{Metrics: map[string]float64{P95: 4}}, DefaultTolerances())
+	if len(tiny) != 1 || tiny[0].Regressed {
+		t.Errorf("p95 2ms → 4ms regressed, want it within the minimum change")
+	}
+
+	// Latency metrics are skipped when the run has none
+	checks = Compare(base, File{Metrics: map[string]float64{SuccessRate: 100}}, DefaultTolerances())
+	if len(checks) != 1 || checks[0].Metric != SuccessRate {
+		t.Errorf("checks = %+v, want only success_rate", checks)
+	}
+}
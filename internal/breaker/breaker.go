@@ -0,0 +1,113 @@
+// Package breaker implements a circuit breaker for scheduled checks: after
+// a run of consecutive failures it stretches the check interval so a
+// service that is clearly down isn't hammered, while the slower checks
+// keep probing for recovery and the outage is still timed.
+package breaker
+
+import "time"
+
+// DefaultMaxInterval caps the backed-off interval when none is given.
+const DefaultMaxInterval = 5 * time.Minute
+
+// Breaker tracks consecutive failures of one check. The zero value is not
+// usable; create breakers with New.
+type Breaker struct {
+	threshold   int           // Consecutive failures that open the circuit
+	maxInterval time.Duration // Upper bound for the backed-off interval
+
+	failures     int           // Current run of consecutive failures
+	firstFailure time.Time     // Start of the current run of failures
+	open         bool          // Whether the circuit is open
+	backoff      int           // Failed probes since the circuit opened
+	opens        int           // Times the circuit has opened
+	downtime     time.Duration // Total length of finished outages
+}
+
+// New creates a breaker that opens after threshold consecutive failures
+// and backs off to at most maxInterval (DefaultMaxInterval if zero).
+func New(threshold int, maxInterval time.Duration) *Breaker {
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxInterval
+	}
+	return &Breaker{threshold: threshold, maxInterval: maxInterval}
+}
+
+// Observe records the outcome of a check made at now. It reports whether
+// the circuit changed state (opened or closed).
+func (b *Breaker) Observe(success bool, now time.Time) bool {
+	if success {
+		b.failures = 0
+		if !b.open {
+			return false
+		}
+		b.open = false
+		b.backoff = 0
+		b.downtime += now.Sub(b.firstFailure)
+		return true
+	}
+
+	if b.failures == 0 {
+		b.firstFailure = now
+	}
+	b.failures++
+
+	if b.open {
+		b.backoff++
+		return false
+	}
+	if b.failures < b.threshold {
+		return false
+	}
+	b.open = true
+	b.opens++
+	return true
+}
+
+// Interval returns the time to wait before the next check: base while the
+// circuit is closed, and base doubled for every failed probe since it
+// opened, up to the maximum, while it is open.
+func (b *Breaker) Interval(base time.Duration) time.Duration {
+	if !b.open {
+		return base
+	}
+	interval := base
+	for i := 0; i <= b.backoff && interval < b.maxInterval; i++ {
+		interval *= 2
+	}
+	if interval > b.maxInterval {
+		interval = b.maxInterval
+	}
+	if interval < base {
+		interval = base
+	}
+	return interval
+}
+
+// Open reports whether the circuit is open.
+func (b *Breaker) Open() bool {
+	return b.open
+}
+
+// Opens returns how many times the circuit has opened.
+func (b *Breaker) Opens() int {
+	return b.opens
+}
+
+// Downtime returns the total time the check has been down during outages
+// that opened the circuit, counted from the first failure of each outage
+// and including an outage still in progress at now.
+func (b *Breaker) Downtime(now time.Time) time.Duration {
+	if b.open {
+		return b.downtime + now.Sub(b.firstFailure)
+	}
+	return b.downtime
+}
+
+// DownSince returns when the current outage started, or the zero time if
+// the circuit is closed.
+func (b *Breaker) DownSince() time.Time {
+	if !b.open {
+		return time.Time{}
+	}
+	return b.firstFailure
+}
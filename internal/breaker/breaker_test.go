@@ -0,0 +1,77 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	base := 2 * time.Second
+	b := New(3, 10*time.Second)
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	steps := []struct {
+		success      bool
+		wantChanged  bool
+		wantOpen     bool
+		wantInterval time.Duration
+	}{
+		{false, false, false, base}, // t=0: first failure
+		{false, false, false, base},
+		{false, true, true, 4 * time.Second}, // threshold reached: circuit opens
+		{false, false, true, 8 * time.Second},
+		{false, false, true, 10 * time.Second}, // capped at the maximum
+		{false, false, true, 10 * time.Second},
+		{true, true, false, base}, // probe succeeds: circuit closes
+		{false, false, false, base},
+	}
+
+	for i, step := range steps {
+		changed := b.Observe(step.success, at(i*10))
+		if changed != step.wantChanged || b.Open() != step.wantOpen {
+			t.Errorf("step %d: changed=%v open=%v, want %v %v", i, changed, b.Open(), step.wantChanged, step.wantOpen)
+		}
+		if got := b.Interval(base); got != step.wantInterval {
+			t.Errorf("step %d: Interval() = %v, want %v", i, got, step.wantInterval)
+		}
+	}
+
+	// The outage ran from the first failure (t=0) to the recovery (t=60)
+	if got := b.Downtime(at(100)); got != time.Minute {
+		t.Errorf("Downtime() = %v, want 1m", got)
+	}
+	if b.Opens() != 1 {
+		t.Errorf("Opens() = %d, want 1", b.Opens())
+	}
+}
+
+func TestBreaker_DowntimeInProgress(t *testing.T) {
+	b := New(1, 0)
+	start := time.Now()
+	b.Observe(false, start)
+
+	if got := b.Downtime(start.Add(30 * time.Second)); got != 30*time.Second {
+		t.Errorf("Downtime() = %v, want 30s", got)
+	}
+	if !b.DownSince().Equal(start) {
+		t.Errorf("DownSince() = %v, want %v", b.DownSince(), start)
+	}
+	if got := b.Interval(time.Minute); got != 2*time.Minute {
+		t.Errorf("Interval() = %v, want 2m", got)
+	}
+}
+
+func TestBreaker_FailuresBelowThreshold(t *testing.T) {
+	b := New(3, 0)
+	now := time.Now()
+	b.Observe(false, now)
+	b.Observe(false, now)
+	b.Observe(true, now)
+	b.Observe(false, now)
+	b.Observe(false, now)
+
+	if b.Open() || b.Downtime(now) != 0 {
+		t.Errorf("breaker opened without %d consecutive failures", 3)
+	}
+}
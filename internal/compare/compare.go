@@ -0,0 +1,179 @@
+// Package compare diffs two runs endpoint by endpoint, flagging latency and
+// success-rate regressions. Runs come from saved summary files
+// (--summary-file) or from the runs recorded in the history store.
+package compare
+
+import (
+	"time"
+
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// Verdicts of a Change.
+const (
+	Regressed = "regressed" // Slower beyond the threshold, or fewer successes
+	Improved  = "improved"  // Faster beyond the threshold, or more successes
+	Unchanged = "unchanged"
+	Added     = "added"   // Only in the second run
+	Removed   = "removed" // Only in the first run
+)
+
+// DefaultThreshold is the relative latency change that counts as a
+// regression or an improvement.
+const DefaultThreshold = 0.2
+
+// minLatencyChange is the smallest absolute latency change that counts,
+// so a 2ms endpoint taking 3ms isn't a 50% regression.
+const minLatencyChange = 5 * time.Millisecond
+
+// Endpoint is the result of one endpoint in a run.
+type Endpoint struct {
+	Name       string
+	Checks     int
+	Successful int
+	Latency    time.Duration // Average over the checks
+}
+
+// SuccessRate returns the share of successful checks in percent.
+func (e Endpoint) SuccessRate() float64 {
+	if e.Checks == 0 {
+		return 0
+	}
+	return float64(e.Successful) / float64(e.Checks) * 100
+}
+
+// Run is a set of endpoint results, in the order they were first seen.
+type Run struct {
+	Label     string // Run ID or file name
+	Endpoints []Endpoint
+}
+
+// Change is the difference of one endpoint between two runs.
+type Change struct {
+	Name          string
+	Before        *Endpoint // nil when added
+	After         *Endpoint // nil when removed
+	LatencyChange float64   // Relative, e.g. 0.25 for 25% slower
+	Verdict       string
+}
+
+// Result is the comparison of two runs.
+type Result struct {
+	Changes      []Change // In the order of the first run, then added endpoints
+	Regressions  int
+	Improvements int
+}
+
+// FromSummary converts a saved run summary: one endpoint per batch result,
+// per target of a multi-URL watch, or the single target of ping and watch.
+func FromSummary(label string, summary output.RunSummary) Run {
+	run := Run{Label: label}
+	switch {
+	case len(summary.Results) > 0:
+		index := make(map[string]int)
+		for _, result := range summary.Results {
+			name := result.Name
+			if name == "" {
+				name = result.URL
+			}
+			i, ok := index[name]
+			if !ok {
+				i = len(run.Endpoints)
+				index[name] = i
+				run.Endpoints = append(run.Endpoints, Endpoint{Name: name})
+			}
+			add(&run.Endpoints[i], result.Success, time.Duration(result.Latency)*time.Millisecond)
+		}
+	case len(summary.Targets) > 0:
+		for _, target := range summary.Targets {
+			run.Endpoints = append(run.Endpoints, fromStats(target.Target, target.SummaryStats))
+		}
+	default:
+		run.Endpoints = append(run.Endpoints, fromStats(summary.Target, summary.SummaryStats))
+	}
+	return run
+}
+
+// FromRecords converts the history records of a run, one endpoint per name.
+func FromRecords(label string, records []history.Record) Run {
+	run := Run{Label: label}
+	index := make(map[string]int)
+	for _, record := range records {
+		i, ok := index[record.Name]
+		if !ok {
+			i = len(run.Endpoints)
+			index[record.Name] = i
+			run.Endpoints = append(run.Endpoints, Endpoint{Name: record.Name})
+		}
+		add(&run.Endpoints[i], record.Success, record.Latency())
+	}
+	return run
+}
+
+// fromStats converts the statistics of a ping or watch target.
+func fromStats(name string, stats output.SummaryStats) Endpoint {
+	endpoint := Endpoint{Name: name, Checks: stats.Total, Successful: stats.Successful}
+	if stats.Latency != nil {
+		endpoint.Latency = time.Duration(stats.Latency.Avg * float64(time.Millisecond))
+	}
+	return endpoint
+}
+
+// add counts one check, keeping the latency a running average.
+func add(endpoint *Endpoint, success bool, latency time.Duration) {
+	endpoint.Checks++
+	if success {
+		endpoint.Successful++
+	}
+	n := float64(endpoint.Checks)
+	endpoint.Latency = time.Duration((float64(endpoint.Latency)*(n-1) + float64(latency)) / n)
+}
+
+// Compare diffs two runs. threshold is the relative latency change that
+// counts as a regression or improvement (DefaultThreshold if 0). A lower
+// success rate is always a regression.
+func Compare(before, after Run, threshold float64) Result {
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+
+	afterByName := make(map[string]*Endpoint, len(after.Endpoints))
+	for i := range after.Endpoints {
+		afterByName[after.Endpoints[i].Name] = &after.Endpoints[i]
+	}
+
+	var result Result
+	seen := make(map[string]bool, len(before.Endpoints))
+	for i := range before.Endpoints {
+		b := &before.Endpoints[i]
+		seen[b.Name] = true
+		a := afterByName[b.Name]
+		if a == nil {
+			result.Changes = append(result.Changes, Change{Name: b.Name, Before: b, Verdict: Removed})
+			continue
+		}
+		change := Change{Name: b.Name, Before: b, After: a, Verdict: Unchanged}
+		if b.Latency > 0 {
+			change.LatencyChange = float64(a.Latency-b.Latency) / float64(b.Latency)
+		}
+		slower := change.LatencyChange >= threshold && a.Latency-b.Latency >= minLatencyChange
+		faster := change.LatencyChange <= -threshold && b.Latency-a.Latency >= minLatencyChange
+		switch {
+		case a.SuccessRate() < b.SuccessRate() || slower:
+			change.Verdict = Regressed
+			result.Regressions++
+		case a.SuccessRate() > b.SuccessRate() || faster:
+			change.Verdict = Improved
+			result.Improvements++
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	for i := range after.Endpoints {
+		if a := &after.Endpoints[i]; !seen[a.Name] {
+			result.Changes = append(result.Changes, Change{Name: a.Name, After: a, Verdict: Added})
+		}
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package compare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+func TestCompare(t *testing.T) {
+	before := Run{Endpoints: []Endpoint{
+		{Name: "slower", Checks: 1, Successful: 1, Latency: 100 * time.Millisecond},
+		{Name: "faster", Checks: 1, Successful: 1, Latency: 100 * time.Millisecond},
+		{Name: "same", Checks: 1, Successful: 1, Latency: 100 * time.Millisecond},
+		{Name: "tiny", Checks: 1, Successful: 1, Latency: 2 * time.Millisecond},
+		{Name: "failing", Checks: 1, Successful: 1, Latency: 100 * time.Millisecond},
+		{Name: "gone", Checks: 1, Successful: 1, Latency: 100 * time.Millisecond},
+	}}
+	after := Run{Endpoints: []Endpoint{
+		{Name: "new", Checks: 1, Successful: 1, Latency: 100 * time.Millisecond},
+		{Name: "slower", Checks: 1, Successful: 1, Latency: 150 * time.Millisecond},
+		{Name: "faster", Checks: 1, Successful: 1, Latency: 50 * time.Millisecond},
+		{Name: "same", Checks: 1, Successful: 1, Latency: 110 * time.Millisecond},
+		{Name: "tiny", Checks: 1, Successful: 1, Latency: 4 * time.Millisecond}, // +100%, but only 2ms
+		{Name: "failing", Checks: 1, Successful: 0, Latency: 90 * time.Millisecond},
+	}}
+
+	result := Compare(before, after, 0)
+
+	want := map[string]string{
+		"slower":  Regressed,
+		"faster":  Improved,
+		"same":    Unchanged,
+		"tiny":    Unchanged,
+		"failing": Regressed,
+		"gone":    Removed,
+		"new":     Added,
+	}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(result.Changes), len(want))
+	}
+	for _, change := range result.Changes {
+		if change.Verdict != want[change.Name] {
+			t.Errorf("%s: verdict = %s, want %s", change.Name, change.Verdict, want[change.Name])
+		}
+	}
+	if result.Changes[len(result.Changes)-1].Name != "new" {
+		t.Error("added endpoints should come last")
+	}
+	if result.Regressions != 2 || result.Improvements != 1 {
+		t.Errorf("Regressions = %d, Improvements = %d; want 2, 1", result.Regressions, result.Improvements)
+	}
+
+	// A looser threshold lets the 50% slowdown pass
+	if result := Compare(before, after, 0.6); result.Regressions != 1 {
+		t.Errorf("Regressions = %d with a 60%% threshold, want 1", result.Regressions)
+	}
+}
+
+func TestFromSummary(t *testing.T) {
+	batch := output.RunSummary{Results: []output.JSONEndpoint{
+		{Name: "api", Latency: 100, Success: true},
+		{Name: "api", Latency: 200, Success: false},
+		{URL: "https://example.com", Latency: 50, Success: true},
+	}}
+	run := FromSummary("batch.json", batch)
+	if len(run.Endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2", len(run.Endpoints))
+	}
+	if api := run.Endpoints[0]; api.Checks != 2 || api.Successful != 1 || api.Latency != 150*time.Millisecond {
+		t.Errorf("api = %+v, want 2 checks, 1 successful, 150ms", api)
+	}
+	if run.Endpoints[1].Name != "https://example.com" {
+		t.Errorf("unnamed endpoint = %q, want its URL", run.Endpoints[1].Name)
+	}
+
+	watch := output.RunSummary{Target: "https://example.com"}
+	watch.Total, watch.Successful = 10, 9
+	watch.Latency = &output.JSONLatency{Avg: 42.5}
+	run = FromSummary("watch.json", watch)
+	if len(run.Endpoints) != 1 || run.Endpoints[0].Latency != 42500*time.Microsecond || run.Endpoints[0].SuccessRate() != 90 {
+		t.Errorf("watch endpoints = %+v, want one at 42.5ms and 90%%", run.Endpoints)
+	}
+}
+
+func TestFromRecords(t *testing.T) {
+	run := FromRecords("20240101-120000", []history.Record{
+		{Name: "api", LatencyMs: 100, Success: true},
+		{Name: "auth", LatencyMs: 30, Success: false},
+		{Name: "api", LatencyMs: 300, Success: true},
+	})
+	if len(run.Endpoints) != 2 || run.Endpoints[0].Latency != 200*time.Millisecond || run.Endpoints[1].Successful != 0 {
+		t.Errorf("endpoints = %+v, want api at 200ms and a failed auth", run.Endpoints)
+	}
+}
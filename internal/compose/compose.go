@@ -0,0 +1,478 @@
+// Package compose discovers health check targets from a Docker Compose
+// file: the published ports of every service, with HTTP paths taken from
+// the services' healthchecks.
+package compose
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFiles are the file names docker compose looks for, in order.
+var DefaultFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+// Check types, chosen per port or set with the tapr.check label.
+const (
+	CheckHTTP  = "http"
+	CheckHTTPS = "https"
+	CheckTCP   = "tcp"
+	CheckSkip  = "skip"
+)
+
+// Labels that override discovery for a service.
+const (
+	LabelCheck  = "tapr.check"           // http, https, tcp or skip
+	LabelPath   = "tapr.path"            // Path to request ("/healthz")
+	LabelStatus = "tapr.expected_status" // Status code to expect
+)
+
+// tcpPorts are container ports of well-known services that don't speak
+// HTTP, which get a TCP connect check instead.
+var tcpPorts = map[int]string{
+	1433:  "SQL Server",
+	2181:  "ZooKeeper",
+	3306:  "MySQL",
+	4222:  "NATS",
+	5432:  "PostgreSQL",
+	5672:  "RabbitMQ",
+	6379:  "Redis",
+	9042:  "Cassandra",
+	9092:  "Kafka",
+	11211: "Memcached",
+	27017: "MongoDB",
+}
+
+// File is the part of a compose file tapr uses.
+type File struct {
+	Services map[string]Service `yaml:"services"`
+}
+
+// Service is one service of a compose file.
+type Service struct {
+	Ports       []Port      `yaml:"ports"`
+	Labels      Labels      `yaml:"labels"`
+	Healthcheck Healthcheck `yaml:"healthcheck"`
+}
+
+// Port is a port mapping, from either the short ("8080:80") or the long
+// syntax.
+type Port struct {
+	HostIP    string
+	Published string // Host port or range ("8080", "8080-8081", "" = random)
+	Target    string // Container port or range
+	Protocol  string // "tcp" or "udp"
+}
+
+// UnmarshalYAML parses both port syntaxes.
+func (p *Port) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return p.parseShort(node.Value)
+	}
+
+	var long struct {
+		Target    string `yaml:"target"`
+		Published string `yaml:"published"`
+		HostIP    string `yaml:"host_ip"`
+		Protocol  string `yaml:"protocol"`
+	}
+	if err := node.Decode(&long); err != nil {
+		return err
+	}
+	*p = Port{HostIP: long.HostIP, Published: long.Published, Target: long.Target, Protocol: long.Protocol}
+	if p.Protocol == "" {
+		p.Protocol = "tcp"
+	}
+	return nil
+}
+
+// parseShort parses "[[host_ip:]published:]target[/protocol]".
+func (p *Port) parseShort(spec string) error {
+	p.Protocol = "tcp"
+	if i := strings.LastIndex(spec, "/"); i >= 0 {
+		spec, p.Protocol = spec[:i], spec[i+1:]
+	}
+
+	// The host IP may be an IPv6 address in brackets
+	if strings.HasPrefix(spec, "[") {
+		end := strings.Index(spec, "]")
+		if end < 0 {
+			return fmt.Errorf("invalid port %q", spec)
+		}
+		p.HostIP, spec = spec[1:end], strings.TrimPrefix(spec[end+1:], ":")
+	}
+
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		p.Target = parts[0]
+	case 2:
+		p.Published, p.Target = parts[0], parts[1]
+	case 3:
+		p.HostIP, p.Published, p.Target = parts[0], parts[1], parts[2]
+	default:
+		return fmt.Errorf("invalid port %q", spec)
+	}
+	return nil
+}
+
+// Labels are service labels, written as a map or a list of "key=value".
+type Labels map[string]string
+
+// UnmarshalYAML parses both label syntaxes.
+func (l *Labels) UnmarshalYAML(node *yaml.Node) error {
+	*l = make(Labels)
+	if node.Kind == yaml.SequenceNode {
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		for _, entry := range list {
+			key, value, _ := strings.Cut(entry, "=")
+			(*l)[key] = value
+		}
+		return nil
+	}
+	var m map[string]string
+	if err := node.Decode(&m); err != nil {
+		return err
+	}
+	for key, value := range m {
+		(*l)[key] = value
+	}
+	return nil
+}
+
+// Healthcheck is a service's healthcheck; only its command is used.
+type Healthcheck struct {
+	Test string // The command, joined into one string
+}
+
+// UnmarshalYAML accepts the test as a string or a list.
+func (h *Healthcheck) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Test yaml.Node `yaml:"test"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	switch raw.Test.Kind {
+	case yaml.ScalarNode:
+		h.Test = raw.Test.Value
+	case yaml.SequenceNode:
+		var parts []string
+		if err := raw.Test.Decode(&parts); err != nil {
+			return err
+		}
+		h.Test = strings.Join(parts, " ")
+	}
+	return nil
+}
+
+// Find returns the compose file in dir, trying DefaultFiles in order.
+func Find(dir string) (string, error) {
+	for _, name := range DefaultFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no compose file found (looked for %s)", strings.Join(DefaultFiles, ", "))
+}
+
+// Load reads a compose file, substituting ${VAR} references from the
+// environment and the .env file next to it like docker compose does.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	env, err := readEnvFile(filepath.Join(filepath.Dir(path), ".env"))
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(interpolate(string(data), func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := env[name]
+		return value, ok
+	}))
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(file.Services) == 0 {
+		return nil, fmt.Errorf("%s defines no services", path)
+	}
+	return &file, nil
+}
+
+// readEnvFile reads KEY=value lines from an .env file, if it exists.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env, scanner.Err()
+}
+
+// variablePattern matches $$, ${VAR}, ${VAR:-default}, ${VAR-default} and $VAR.
+var variablePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?-)([^}]*))?[^}]*\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// interpolate substitutes variable references using lookup. Unset
+// variables without a default become empty, and $$ is a literal $.
+func interpolate(s string, lookup func(string) (string, bool)) string {
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		groups := variablePattern.FindStringSubmatch(match)
+		name, operator, fallback := groups[1], groups[2], groups[3]
+		if name == "" {
+			name = groups[4]
+		}
+
+		value, ok := lookup(name)
+		switch {
+		case operator == ":-" && value == "":
+			return fallback
+		case operator == "-" && !ok:
+			return fallback
+		}
+		return value
+	})
+}
+
+// Options control how targets are built.
+type Options struct {
+	Host string // Host to connect to ("localhost" if empty); a port's host_ip wins
+}
+
+// Target is one published port of a service to check.
+type Target struct {
+	Service        string
+	ContainerPort  int
+	HostPort       int
+	Check          string // CheckHTTP, CheckHTTPS, CheckTCP or CheckSkip
+	Address        string // host:port to connect to
+	URL            string // URL to request, for HTTP checks
+	ExpectedStatus int    // Status to expect, 0 for the default
+	Skip           string // Why the port isn't checked
+	Description    string // What the port is, if known ("PostgreSQL")
+}
+
+// Label returns a short name for the target, like "api:8080".
+func (t Target) Label() string {
+	if t.HostPort == 0 {
+		return t.Service
+	}
+	return t.Service + ":" + strconv.Itoa(t.HostPort)
+}
+
+// Targets returns a target per published TCP port of every service, sorted
+// by service and port. Services without published ports are reported as
+// skipped.
+func Targets(file *File, opts Options) ([]Target, error) {
+	names := make([]string, 0, len(file.Services))
+	for name := range file.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var targets []Target
+	for _, name := range names {
+		service := file.Services[name]
+		serviceTargets, err := portTargets(name, service, opts)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		if len(serviceTargets) == 0 {
+			serviceTargets = []Target{{Service: name, Check: CheckSkip, Skip: "no published ports"}}
+		}
+		targets = append(targets, serviceTargets...)
+	}
+	return targets, nil
+}
+
+// portTargets returns the targets for the published ports of one service.
+func portTargets(name string, service Service, opts Options) ([]Target, error) {
+	check := strings.ToLower(service.Labels[LabelCheck])
+	switch check {
+	case "", CheckHTTP, CheckHTTPS, CheckTCP, CheckSkip:
+	default:
+		return nil, fmt.Errorf("invalid %s label %q (use http, https, tcp or skip)", LabelCheck, check)
+	}
+	expectedStatus := 0
+	if value := service.Labels[LabelStatus]; value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid %s label %q", LabelStatus, value)
+		}
+		expectedStatus = status
+	}
+	healthURL := healthcheckURL(service.Healthcheck.Test)
+
+	var targets []Target
+	for _, port := range service.Ports {
+		if strings.ToLower(port.Protocol) != "tcp" {
+			continue
+		}
+		pairs, err := expandPorts(port)
+		if err != nil {
+			return nil, err
+		}
+		for _, pair := range pairs {
+			target := Target{Service: name, ContainerPort: pair[0], HostPort: pair[1], Check: check, ExpectedStatus: expectedStatus}
+			if target.HostPort == 0 {
+				target.Check, target.Skip = CheckSkip, "published on a random port"
+				targets = append(targets, target)
+				continue
+			}
+
+			host := opts.Host
+			if port.HostIP != "" && port.HostIP != "0.0.0.0" && port.HostIP != "::" {
+				host = port.HostIP
+			}
+			if host == "" {
+				host = "localhost"
+			}
+			target.Address = net.JoinHostPort(host, strconv.Itoa(target.HostPort))
+			target.Description = tcpPorts[target.ContainerPort]
+
+			// Without a label, healthchecks and well-known ports decide
+			path := "/"
+			if healthURL != nil && healthcheckPort(healthURL) == target.ContainerPort {
+				path = healthURL.RequestURI()
+				if target.Check == "" {
+					target.Check = healthURL.Scheme
+				}
+			}
+			if target.Check == "" {
+				target.Check = CheckHTTP
+				if target.Description != "" {
+					target.Check = CheckTCP
+				}
+			}
+			if label := service.Labels[LabelPath]; label != "" {
+				path = "/" + strings.TrimPrefix(label, "/")
+			}
+
+			switch target.Check {
+			case CheckHTTP, CheckHTTPS:
+				target.URL = target.Check + "://" + target.Address + path
+			case CheckSkip:
+				target.Skip = "skipped by " + LabelCheck + " label"
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// expandPorts returns the [container, host] port pairs of a mapping, with
+// a host port of 0 when it isn't published to a fixed port.
+func expandPorts(port Port) ([][2]int, error) {
+	targetFirst, targetLast, err := portRange(port.Target)
+	if err != nil {
+		return nil, err
+	}
+	if port.Published == "" {
+		pairs := make([][2]int, 0, targetLast-targetFirst+1)
+		for p := targetFirst; p <= targetLast; p++ {
+			pairs = append(pairs, [2]int{p, 0})
+		}
+		return pairs, nil
+	}
+
+	hostFirst, hostLast, err := portRange(port.Published)
+	if err != nil {
+		return nil, err
+	}
+	if hostLast-hostFirst != targetLast-targetFirst {
+		if targetFirst != targetLast {
+			return nil, fmt.Errorf("port range %s:%s sizes differ", port.Published, port.Target)
+		}
+		// A host range for one container port: docker picks one of them
+		return [][2]int{{targetFirst, 0}}, nil
+	}
+	pairs := make([][2]int, 0, targetLast-targetFirst+1)
+	for i := 0; i <= targetLast-targetFirst; i++ {
+		pairs = append(pairs, [2]int{targetFirst + i, hostFirst + i})
+	}
+	return pairs, nil
+}
+
+// portRange parses "8080" or "8080-8090".
+func portRange(spec string) (first, last int, err error) {
+	from, to, isRange := strings.Cut(spec, "-")
+	first, err = strconv.Atoi(strings.TrimSpace(from))
+	if err != nil || first < 1 || first > 65535 {
+		return 0, 0, fmt.Errorf("invalid port %q", spec)
+	}
+	if !isRange {
+		return first, first, nil
+	}
+	last, err = strconv.Atoi(strings.TrimSpace(to))
+	if err != nil || last < first || last > 65535 {
+		return 0, 0, fmt.Errorf("invalid port range %q", spec)
+	}
+	return first, last, nil
+}
+
+// urlPattern finds an HTTP URL in a healthcheck command.
+var urlPattern = regexp.MustCompile(`https?://[^\s'"|;&]+`)
+
+// healthcheckURL returns the URL a healthcheck command requests (curl,
+// wget and similar), or nil if it doesn't request one.
+func healthcheckURL(test string) *url.URL {
+	match := urlPattern.FindString(test)
+	if match == "" {
+		return nil
+	}
+	u, err := url.Parse(match)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// healthcheckPort returns the port a healthcheck URL connects to.
+func healthcheckPort(u *url.URL) int {
+	if port, err := strconv.Atoi(u.Port()); err == nil {
+		return port
+	}
+	if u.Scheme == "https" {
+		return 443
+	}
+	return 80
+}
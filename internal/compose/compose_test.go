@@ -0,0 +1,195 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testFile = `
+services:
+  api:
+    image: example/api
+    ports:
+      - "${API_PORT:-8080}:3000"
+    healthcheck:
+      test: ["CMD", "curl", "-f", "http://localhost:3000/healthz"]
+  web:
+    ports:
+      - target: 443
+        published: "8443"
+        host_ip: 127.0.0.1
+    healthcheck:
+      test: wget -qO- https://localhost/ready || exit 1
+  db:
+    image: postgres
+    ports:
+      - "5432:5432"
+  admin:
+    ports:
+      - "9000:80"
+    labels:
+      tapr.path: status
+      tapr.expected_status: "204"
+  metrics:
+    ports:
+      - "9100-9101:9100-9101"
+      - "53:53/udp"
+    labels:
+      - tapr.check=tcp
+  worker:
+    image: example/worker
+  cache:
+    ports:
+      - "11211"
+    labels:
+      tapr.check: skip
+`
+
+func TestTargets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "compose.yaml")
+	if err := os.WriteFile(path, []byte(testFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("# ports\nAPI_PORT=18080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	targets, err := Targets(file, Options{})
+	if err != nil {
+		t.Fatalf("Targets() error = %v", err)
+	}
+
+	type row struct {
+		label, check, address, url, skip string
+		status                           int
+	}
+	var got []row
+	for _, target := range targets {
+		got = append(got, row{target.Label(), target.Check, target.Address, target.URL, target.Skip, target.ExpectedStatus})
+	}
+	want := []row{
+		{"admin:9000", "http", "localhost:9000", "http://localhost:9000/status", "", 204},
+		{"api:18080", "http", "localhost:18080", "http://localhost:18080/healthz", "", 0},
+		{"cache", "skip", "", "", "published on a random port", 0},
+		{"db:5432", "tcp", "localhost:5432", "", "", 0},
+		{"metrics:9100", "tcp", "localhost:9100", "", "", 0},
+		{"metrics:9101", "tcp", "localhost:9101", "", "", 0},
+		{"web:8443", "https", "127.0.0.1:8443", "https://127.0.0.1:8443/ready", "", 0},
+		{"worker", "skip", "", "", "no published ports", 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets() =\n%v\nwant\n%v", got, want)
+	}
+	if targets[3].Description != "PostgreSQL" {
+		t.Errorf("db description = %q, want PostgreSQL", targets[3].Description)
+	}
+}
+
+func TestPort_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Port
+		wantErr bool
+	}{
+		{"80", Port{Target: "80", Protocol: "tcp"}, false},
+		{"8080:80", Port{Published: "8080", Target: "80", Protocol: "tcp"}, false},
+		{"127.0.0.1:8080:80/tcp", Port{HostIP: "127.0.0.1", Published: "8080", Target: "80", Protocol: "tcp"}, false},
+		{"[::1]:8080:80", Port{HostIP: "::1", Published: "8080", Target: "80", Protocol: "tcp"}, false},
+		{"53:53/udp", Port{Published: "53", Target: "53", Protocol: "udp"}, false},
+		{"1:2:3:4", Port{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			var got Port
+			err := yaml.Unmarshal([]byte(`"`+tt.spec+`"`), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{"HOST": "db", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"${HOST}:5432", "db:5432"},
+		{"$HOST", "db"},
+		{"${MISSING:-8080}", "8080"},
+		{"${EMPTY:-8080}", "8080"},
+		{"${EMPTY-8080}", ""},
+		{"${MISSING-8080}", "8080"},
+		{"${MISSING}", ""},
+		{"$$HOST", "$HOST"},
+		{"${HOST:?required}", "db"},
+	}
+
+	for _, tt := range tests {
+		if got := interpolate(tt.in, lookup); got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTargets_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		wantErr string
+	}{
+		{"bad check", "ports: [\"80:80\"]\nlabels: {tapr.check: grpc}", "invalid tapr.check label"},
+		{"bad status", "ports: [\"80:80\"]\nlabels: {tapr.expected_status: ok}", "invalid tapr.expected_status label"},
+		{"bad port", "ports: [\"80:http\"]", "invalid port"},
+		{"range mismatch", "ports: [\"80-82:80-81\"]", "sizes differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var service Service
+			if err := yaml.Unmarshal([]byte(tt.service), &service); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Targets(&File{Services: map[string]Service{"app": service}}, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Targets() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Find(dir); err == nil {
+		t.Error("Find() in an empty directory should fail")
+	}
+
+	for _, name := range []string{"docker-compose.yml", "compose.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("services: {}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := Find(dir)
+	if err != nil || filepath.Base(got) != "compose.yaml" {
+		t.Errorf("Find() = %q, %v, want compose.yaml", got, err)
+	}
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+	"gopkg.in/yaml.v3"
+)
+
+// AlertConfig configures where and when alerts are sent.
+type AlertConfig struct {
+	FailureThreshold  int           `yaml:"failure_threshold"`  // Consecutive failures before alerting (default 2)
+	RecoveryThreshold int           `yaml:"recovery_threshold"` // Consecutive successes before recovering (default 1)
+	FlapWindow        int           `yaml:"flap_window"`        // Recent checks examined for flapping (default 20)
+	FlapThreshold     int           `yaml:"flap_threshold"`     // State changes in the window that count as flapping (default 6, -1 = off)
+	LatencyThreshold  time.Duration `yaml:"latency_threshold"`  // Alert when responses are slower (0 = off)
+	Cooldown          time.Duration `yaml:"cooldown"`           // Minimum time between repeat alerts (default 30m)
+
+	Slack    *SlackAlert    `yaml:"slack"`    // Slack incoming webhook
+	Webhooks []WebhookAlert `yaml:"webhooks"` // Generic JSON webhooks
+	Email    *EmailAlert    `yaml:"email"`    // Email via SMTP
+
+	PagerDuty *PagerDutyAlert `yaml:"pagerduty"` // PagerDuty Events API v2
+	Opsgenie  *OpsgenieAlert  `yaml:"opsgenie"`  // Opsgenie Alert API
+}
+
+// SlackAlert posts alerts to a Slack incoming webhook.
+type SlackAlert struct {
+	WebhookURL string `yaml:"webhook_url"`
+	Channel    string `yaml:"channel"` // Optional channel override
+}
+
+// WebhookAlert posts alert events as JSON to a URL.
+type WebhookAlert struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// EmailAlert sends alerts by email through an SMTP server.
+type EmailAlert struct {
+	Host     string   `yaml:"smtp_host"`
+	Port     int      `yaml:"smtp_port"` // Default 587
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// PagerDutyAlert triggers (and resolves) PagerDuty incidents.
+type PagerDutyAlert struct {
+	RoutingKey  string            `yaml:"routing_key"`  // Integration key of the service
+	Severity    map[string]string `yaml:"severity"`     // Severity per alert kind (down, slow, flapping)
+	AutoResolve *bool             `yaml:"auto_resolve"` // Resolve the incident on recovery (default true)
+}
+
+// OpsgenieAlert creates (and closes) Opsgenie alerts.
+type OpsgenieAlert struct {
+	APIKey    string            `yaml:"api_key"`
+	Region    string            `yaml:"region"`     // "us" (default) or "eu"
+	Priority  map[string]string `yaml:"priority"`   // Priority per alert kind (down, slow, flapping)
+	AutoClose *bool             `yaml:"auto_close"` // Close the alert on recovery (default true)
+}
+
+// Default severities for alert kinds, used when no mapping is configured.
+var (
+	DefaultPagerDutySeverity = map[string]string{"down": "critical", "slow": "warning", "flapping": "warning"}
+	DefaultOpsgeniePriority  = map[string]string{"down": "P1", "slow": "P3", "flapping": "P3"}
+)
+
+// LoadAlertConfig reads the alerts section of a YAML file. Daemon config
+// files can be passed directly, since only the top-level "alerts" key is read.
+func LoadAlertConfig(filepath string) (*AlertConfig, error) {
+	// Check if file exists
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("alert config file not found: %s", filepath)
+	}
+
+	// Read file contents
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert config: %w", err)
+	}
+
+	// Parse YAML
+	var file struct {
+		Alerts *AlertConfig `yaml:"alerts"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse alert config YAML: %w", err)
+	}
+	if file.Alerts == nil {
+		return nil, fmt.Errorf("no alerts section in %s", filepath)
+	}
+
+	if err := file.Alerts.prepare(); err != nil {
+		return nil, err
+	}
+	return file.Alerts, nil
+}
+
+// prepare fills in defaults, expands {{env.NAME}} templates in secrets and
+// validates the configured channels.
+func (a *AlertConfig) prepare() error {
+	if a.FailureThreshold == 0 {
+		a.FailureThreshold = 2
+	}
+	if a.FailureThreshold < 0 {
+		return fmt.Errorf("alerts: failure_threshold must be positive")
+	}
+	if err := validateFlapping(a.RecoveryThreshold, a.FlapWindow, a.FlapThreshold); err != nil {
+		return fmt.Errorf("alerts: %w", err)
+	}
+	if a.Cooldown == 0 {
+		a.Cooldown = 30 * time.Minute
+	}
+
+	if a.Slack != nil {
+		if err := expandEnv(&a.Slack.WebhookURL); err != nil {
+			return fmt.Errorf("alerts: slack webhook_url: %w", err)
+		}
+		if a.Slack.WebhookURL == "" {
+			return fmt.Errorf("alerts: slack requires webhook_url")
+		}
+	}
+
+	for i := range a.Webhooks {
+		webhook := &a.Webhooks[i]
+		if err := expandEnv(&webhook.URL); err != nil {
+			return fmt.Errorf("alerts: webhook %d url: %w", i+1, err)
+		}
+		if webhook.URL == "" {
+			return fmt.Errorf("alerts: webhook %d has no url", i+1)
+		}
+		for key := range webhook.Headers {
+			value := webhook.Headers[key]
+			if err := expandEnv(&value); err != nil {
+				return fmt.Errorf("alerts: webhook %d header %s: %w", i+1, key, err)
+			}
+			webhook.Headers[key] = value
+		}
+	}
+
+	if a.PagerDuty != nil {
+		if err := expandEnv(&a.PagerDuty.RoutingKey); err != nil {
+			return fmt.Errorf("alerts: pagerduty routing_key: %w", err)
+		}
+		if a.PagerDuty.RoutingKey == "" {
+			return fmt.Errorf("alerts: pagerduty requires routing_key")
+		}
+		for kind, severity := range a.PagerDuty.Severity {
+			switch severity {
+			case "critical", "error", "warning", "info":
+			default:
+				return fmt.Errorf("alerts: pagerduty severity for %s must be critical, error, warning or info", kind)
+			}
+		}
+		a.PagerDuty.Severity = withDefaults(a.PagerDuty.Severity, DefaultPagerDutySeverity)
+	}
+
+	if a.Opsgenie != nil {
+		if err := expandEnv(&a.Opsgenie.APIKey); err != nil {
+			return fmt.Errorf("alerts: opsgenie api_key: %w", err)
+		}
+		if a.Opsgenie.APIKey == "" {
+			return fmt.Errorf("alerts: opsgenie requires api_key")
+		}
+		if a.Opsgenie.Region != "" && a.Opsgenie.Region != "us" && a.Opsgenie.Region != "eu" {
+			return fmt.Errorf("alerts: opsgenie region must be us or eu")
+		}
+		for kind, priority := range a.Opsgenie.Priority {
+			switch priority {
+			case "P1", "P2", "P3", "P4", "P5":
+			default:
+				return fmt.Errorf("alerts: opsgenie priority for %s must be P1 to P5", kind)
+			}
+		}
+		a.Opsgenie.Priority = withDefaults(a.Opsgenie.Priority, DefaultOpsgeniePriority)
+	}
+
+	if a.Email != nil {
+		if err := expandEnv(&a.Email.Password); err != nil {
+			return fmt.Errorf("alerts: email password: %w", err)
+		}
+		if a.Email.Host == "" || a.Email.From == "" || len(a.Email.To) == 0 {
+			return fmt.Errorf("alerts: email requires smtp_host, from and to")
+		}
+		if a.Email.Port == 0 {
+			a.Email.Port = 587
+		}
+	}
+
+	return nil
+}
+
+// validateFlapping checks the recovery and flap detection settings shared by
+// alerts and the daemon's state.
+func validateFlapping(recoveryThreshold, flapWindow, flapThreshold int) error {
+	if recoveryThreshold < 0 {
+		return fmt.Errorf("recovery_threshold must be positive")
+	}
+	if flapWindow < 0 || flapWindow == 1 {
+		return fmt.Errorf("flap_window must be at least 2")
+	}
+	window := flapWindow
+	if window == 0 {
+		window = stats.DefaultFlapWindow
+	}
+	if flapThreshold < -1 || flapThreshold >= window {
+		return fmt.Errorf("flap_threshold must be below flap_window (or -1 to turn flap detection off)")
+	}
+	return nil
+}
+
+// withDefaults returns values with any missing keys filled from defaults.
+func withDefaults(values, defaults map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range values {
+		merged[key] = value
+	}
+	return merged
+}
+
+// expandEnv renders {{env.NAME}} references in *s so secrets can be kept
+// out of config files.
+func expandEnv(s *string) error {
+	rendered, err := RenderTemplate(*s, nil)
+	if err != nil {
+		return err
+	}
+	*s = rendered
+	return nil
+}
@@ -2,74 +2,184 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/symtalha14/tapr/internal/assert"
+	"github.com/symtalha14/tapr/internal/jsonpath"
+	"github.com/symtalha14/tapr/internal/jsonschema"
 	"gopkg.in/yaml.v3"
 )
 
 // Endpoint represents a single API endpoint to test in batch mode.
 type Endpoint struct {
-	Name           string            `yaml:"name"`            // Friendly name for the endpoint
-	URL            string            `yaml:"url"`             // Full URL to test
-	Method         string            `yaml:"method"`          // HTTP method (GET, POST, etc.)
-	Headers        map[string]string `yaml:"headers"`         // Optional headers for this endpoint
-	Body           string            `yaml:"body"`            // Optional request body
-	ExpectedStatus int               `yaml:"expected_status"` // Expected HTTP status code
-	Timeout        time.Duration     `yaml:"timeout"`         // Optional timeout override
+	Name             string            `yaml:"name,omitempty"`               // Friendly name for the endpoint
+	URL              string            `yaml:"url,omitempty"`                // Full URL to test
+	Method           string            `yaml:"method,omitempty"`             // HTTP method (GET, POST, etc.)
+	Headers          map[string]string `yaml:"headers,omitempty"`            // Optional headers for this endpoint
+	UserAgent        string            `yaml:"user_agent,omitempty"`         // User-Agent to send, overriding headers and --user-agent
+	Params           map[string]string `yaml:"params,omitempty"`             // Optional query parameters (URL-encoded for you)
+	Body             string            `yaml:"body,omitempty"`               // Optional request body ("@path" reads a file)
+	SOAPAction       string            `yaml:"soap_action,omitempty"`        // SOAP action, sent as SOAP 1.1 or 1.2 expects
+	ExpectedStatus   int               `yaml:"expected_status,omitempty"`    // Expected HTTP status code
+	Timeout          time.Duration     `yaml:"timeout,omitempty"`            // Optional timeout override
+	FirstByteTimeout time.Duration     `yaml:"first_byte_timeout,omitempty"` // Maximum wait for the first response byte
+	IdleTimeout      time.Duration     `yaml:"idle_timeout,omitempty"`       // Maximum pause while reading the body
+	ConnectTimeout   time.Duration     `yaml:"connect_timeout,omitempty"`    // Maximum time to connect: DNS, TCP and TLS together
+	ResponseTimeout  time.Duration     `yaml:"response_timeout,omitempty"`   // Maximum wait for response headers once sent
+	MaxLatency       time.Duration     `yaml:"max_latency,omitempty"`        // Optional latency SLA (0 = no limit)
+	MinSize          int64             `yaml:"min_size,omitempty"`           // Minimum response body size in bytes (0 = no limit)
+	MaxSize          int64             `yaml:"max_size,omitempty"`           // Maximum response body size in bytes (0 = no limit)
+	ExpectRedirect   string            `yaml:"expect_redirect_to,omitempty"` // URL (or "/path") the request must redirect to
+	ExpectHeaders    map[string]string `yaml:"expect_headers,omitempty"`     // Optional response header assertions
+	ExpectSchema     string            `yaml:"expect_schema,omitempty"`      // JSON Schema file the response body must match
+	ExpectXPath      map[string]string `yaml:"expect_xpath,omitempty"`       // Values expected in an XML response body (XPath: value)
+	Capture          map[string]string `yaml:"capture,omitempty"`            // Variables to capture from the JSON response (name: path)
+	DependsOn        []string          `yaml:"depends_on,omitempty"`         // Endpoints that must pass before this one runs
+	SaveBody         string            `yaml:"save_body,omitempty"`          // Optional file to save the response body to
+	Tags             []string          `yaml:"tags,omitempty"`               // Labels for selecting endpoints (--tags, --skip-tags)
+	Retries          int               `yaml:"retries,omitempty"`            // Times to retry a failed check (0 = use --retries)
+	SnapshotIgnore   []string          `yaml:"snapshot_ignore,omitempty"`    // JSON paths left out of snapshots (ids, timestamps)
+
+	Source     string `yaml:"-"` // Where the endpoint is defined ("file:line"), for errors
+	BodyFile   string `yaml:"-"` // File the body was read from, for content type detection
+	Stage      string `yaml:"-"` // Name of the stage the endpoint belongs to, if any
+	StageIndex int    `yaml:"-"` // Position of that stage (0 without stages)
+}
+
+// HasTag reports whether the endpoint has any of the given tags.
+func (e Endpoint) HasTag(tags ...string) bool {
+	for _, tag := range tags {
+		for _, own := range e.Tags {
+			if own == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Stage is a named group of endpoints in a batch config. Stages run in
+// order; the endpoints within a stage run concurrently.
+type Stage struct {
+	Name      string      `yaml:"name,omitempty"`      // Stage name shown in output
+	Endpoints []Endpoint  `yaml:"endpoints,omitempty"` // Endpoints in this stage
+	Discover  []Discovery `yaml:"discover,omitempty"`  // Endpoints discovered at run time
 }
 
 // BatchConfig represents the entire batch configuration file.
 type BatchConfig struct {
-	Endpoints   []Endpoint    `yaml:"endpoints"`   // List of endpoints to test
-	Concurrency int           `yaml:"concurrency"` // Number of concurrent requests
-	Timeout     time.Duration `yaml:"timeout"`     // Global timeout
+	Include []string `yaml:"include,omitempty"` // Other batch configs to merge in (see LoadBatchConfig)
+
+	Endpoints   []Endpoint    `yaml:"endpoints,omitempty"`   // List of endpoints to test
+	Stages      []Stage       `yaml:"stages,omitempty"`      // Ordered stages (instead of endpoints)
+	Discover    []Discovery   `yaml:"discover,omitempty"`    // Endpoints discovered at run time (see ExpandDiscovery)
+	Concurrency int           `yaml:"concurrency,omitempty"` // Number of concurrent requests
+	Timeout     time.Duration `yaml:"timeout,omitempty"`     // Global timeout
+	RateLimit   float64       `yaml:"rate_limit,omitempty"`  // Maximum requests per second (0 = unlimited)
+
+	Variables map[string]string `yaml:"variables,omitempty"` // Variables available to {{name}} templates
+	Headers   map[string]string `yaml:"headers,omitempty"`   // Headers sent with every endpoint (endpoint headers win)
+
+	SnapshotIgnore []string `yaml:"snapshot_ignore,omitempty"` // JSON paths left out of every endpoint's snapshot
+
+	Remotes []Remote `yaml:"remotes,omitempty"` // Other machines to run the batch on (--remotes)
+
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty"` // Windows in which failures don't fail the run
 }
 
 // LoadBatchConfig reads and parses a batch configuration YAML file.
-func LoadBatchConfig(filepath string) (*BatchConfig, error) {
-	// Check if file exists
-	if _, err := os.Stat(filepath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("batch config file not found: %s", filepath)
+//
+// Files listed under include (relative to the including file) are merged
+// in order before the file itself, so later files take precedence:
+//   - an endpoint replaces an earlier endpoint with the same name, in place
+//   - a stage adds its endpoints to an earlier stage with the same name
+//   - variables, headers, concurrency, timeout and rate_limit override
+//     earlier values
+//   - snapshot_ignore paths are added to earlier ones
+//   - a remote replaces an earlier remote with the same name
+//   - discover entries and maintenance windows are added to earlier ones
+//
+// A path of "-" reads the config from standard input, resolving relative
+// paths against the working directory.
+func LoadBatchConfig(path string) (*BatchConfig, error) {
+	config, err := readBatchConfig(path, nil)
+	if err != nil {
+		return nil, err
 	}
+	return finishBatchConfig(config)
+}
 
-	// Read file contents
-	data, err := os.ReadFile(filepath)
+// ParseBatchConfig parses a batch config that isn't read from a file, such
+// as one received over the network (see BatchConfig.Standalone). name is
+// used in error messages; includes and body files are resolved against the
+// working directory.
+func ParseBatchConfig(data []byte, name string) (*BatchConfig, error) {
+	config, err := parseBatchConfig(data, name, ".", name, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read batch config: %w", err)
+		return nil, err
 	}
+	return finishBatchConfig(config)
+}
 
-	// Parse YAML
-	var config BatchConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse batch config YAML: %w", err)
+// finishBatchConfig flattens stages, fills in defaults and validates a
+// parsed config.
+func finishBatchConfig(config *BatchConfig) (*BatchConfig, error) {
+	// Flatten stages into the endpoint list
+	if err := flattenStages(config); err != nil {
+		return nil, err
 	}
 
 	// Validate
-	if len(config.Endpoints) == 0 {
+	if len(config.Endpoints) == 0 && len(config.Discover) == 0 {
 		return nil, fmt.Errorf("no endpoints defined in batch config")
 	}
 
 	// Set defaults
 	for i := range config.Endpoints {
-		endpoint := &config.Endpoints[i]
-
-		// Default method to GET
-		if endpoint.Method == "" {
-			endpoint.Method = "GET"
+		if len(config.SnapshotIgnore) > 0 {
+			endpoint := &config.Endpoints[i]
+			endpoint.SnapshotIgnore = append(append([]string{}, config.SnapshotIgnore...), endpoint.SnapshotIgnore...)
 		}
-
-		// Default expected status to 200
-		if endpoint.ExpectedStatus == 0 {
-			endpoint.ExpectedStatus = 200
+		if err := prepareEndpoint(&config.Endpoints[i]); err != nil {
+			if source := config.Endpoints[i].Source; source != "" {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+			return nil, err
 		}
-
-		// Validate URL
-		if endpoint.URL == "" {
-			return nil, fmt.Errorf("endpoint '%s' has no URL", endpoint.Name)
+	}
+	for i := range config.Discover {
+		if len(config.SnapshotIgnore) > 0 {
+			template := &config.Discover[i].Endpoint
+			template.SnapshotIgnore = append(append([]string{}, config.SnapshotIgnore...), template.SnapshotIgnore...)
 		}
 	}
+	if err := validateDiscovery(config.Discover); err != nil {
+		return nil, err
+	}
+
+	// Validate names and dependencies between endpoints
+	if err := validateNames(config.Endpoints); err != nil {
+		return nil, err
+	}
+	if err := validateDependencies(config.Endpoints); err != nil {
+		return nil, err
+	}
+
+	if config.RateLimit < 0 {
+		return nil, fmt.Errorf("rate_limit must not be negative")
+	}
+	if err := validateRemotes(config.Remotes); err != nil {
+		return nil, err
+	}
+	if err := prepareMaintenance(config.Maintenance); err != nil {
+		return nil, err
+	}
 
 	// Default concurrency
 	if config.Concurrency == 0 {
@@ -81,5 +191,420 @@ func LoadBatchConfig(filepath string) (*BatchConfig, error) {
 		config.Timeout = 10 * time.Second
 	}
 
-	return &config, nil
+	return config, nil
+}
+
+// readBatchConfig parses a batch config and merges its includes. including
+// holds the files that (transitively) include this one, to detect cycles.
+func readBatchConfig(path string, including []string) (*BatchConfig, error) {
+	data, absPath, err := readBatchFile(path, including)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	if path == "-" {
+		dir = "."
+	}
+	return parseBatchConfig(data, path, dir, absPath, including)
+}
+
+// parseBatchConfig parses the contents of a batch config and merges its
+// includes, resolving relative paths against dir. path names the config in
+// errors and absPath identifies it for cycle detection.
+func parseBatchConfig(data []byte, path, dir, absPath string, including []string) (*BatchConfig, error) {
+	// Parse YAML strictly, so misspelled keys aren't silently ignored
+	var own BatchConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&own); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse %s:\n  %w", path, describeYAMLError(err))
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err == nil {
+		setSources(&own, &root, path)
+	}
+	if err := resolveFiles(&own, dir); err != nil {
+		return nil, err
+	}
+	if len(own.Include) == 0 {
+		return &own, nil
+	}
+
+	// Merge includes first, then the file itself
+	merged := &BatchConfig{}
+	for _, include := range own.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(dir, include)
+		}
+		included, err := readBatchConfig(include, append(including, absPath))
+		if err != nil {
+			return nil, err
+		}
+		merged.merge(included)
+	}
+	merged.merge(&own)
+	return merged, nil
+}
+
+// readBatchFile reads a batch config file ("-" for standard input) and
+// returns its contents and absolute path, checking for include cycles.
+func readBatchFile(path string, including []string) ([]byte, string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read batch config from stdin: %w", err)
+		}
+		return data, path, nil
+	}
+
+	// Check if file exists
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("batch config file not found: %s", path)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read batch config: %w", err)
+	}
+	for _, parent := range including {
+		if parent == absPath {
+			return nil, "", fmt.Errorf("include cycle involving %s", path)
+		}
+	}
+
+	// Read file contents
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read batch config: %w", err)
+	}
+	return data, absPath, nil
+}
+
+// resolveFiles replaces "@path" bodies with the contents of the file and
+// makes expect_schema paths absolute, resolving relative paths against dir
+// (the directory of the config file that declares the endpoint).
+func resolveFiles(config *BatchConfig, dir string) error {
+	endpoints := make([]*Endpoint, 0, len(config.Endpoints))
+	for i := range config.Endpoints {
+		endpoints = append(endpoints, &config.Endpoints[i])
+	}
+	for i := range config.Discover {
+		endpoints = append(endpoints, &config.Discover[i].Endpoint)
+	}
+	for i := range config.Stages {
+		for j := range config.Stages[i].Endpoints {
+			endpoints = append(endpoints, &config.Stages[i].Endpoints[j])
+		}
+		for j := range config.Stages[i].Discover {
+			endpoints = append(endpoints, &config.Stages[i].Discover[j].Endpoint)
+		}
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.ExpectSchema != "" && !filepath.IsAbs(endpoint.ExpectSchema) {
+			endpoint.ExpectSchema = filepath.Join(dir, endpoint.ExpectSchema)
+		}
+
+		if !strings.HasPrefix(endpoint.Body, "@") {
+			continue
+		}
+		bodyFile := strings.TrimPrefix(endpoint.Body, "@")
+		if !filepath.IsAbs(bodyFile) {
+			bodyFile = filepath.Join(dir, bodyFile)
+		}
+		data, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return fmt.Errorf("endpoint '%s': failed to read body file: %w", endpoint.Name, err)
+		}
+		endpoint.Body = string(data)
+		endpoint.BodyFile = bodyFile
+	}
+	return nil
+}
+
+// merge adds other to c, with other taking precedence (see LoadBatchConfig).
+func (c *BatchConfig) merge(other *BatchConfig) {
+	c.Endpoints = mergeEndpoints(c.Endpoints, other.Endpoints)
+
+	for _, stage := range other.Stages {
+		found := false
+		for i := range c.Stages {
+			if stage.Name != "" && c.Stages[i].Name == stage.Name {
+				c.Stages[i].Endpoints = mergeEndpoints(c.Stages[i].Endpoints, stage.Endpoints)
+				c.Stages[i].Discover = append(c.Stages[i].Discover, stage.Discover...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Stages = append(c.Stages, stage)
+		}
+	}
+
+	if other.Concurrency != 0 {
+		c.Concurrency = other.Concurrency
+	}
+	if other.Timeout != 0 {
+		c.Timeout = other.Timeout
+	}
+	if other.RateLimit != 0 {
+		c.RateLimit = other.RateLimit
+	}
+
+	if len(other.Variables) > 0 && c.Variables == nil {
+		c.Variables = make(map[string]string, len(other.Variables))
+	}
+	for name, value := range other.Variables {
+		c.Variables[name] = value
+	}
+
+	if len(other.Headers) > 0 {
+		c.Headers = MergeHeaders(c.Headers, other.Headers)
+	}
+
+	c.SnapshotIgnore = append(c.SnapshotIgnore, other.SnapshotIgnore...)
+	c.Remotes = mergeRemotes(c.Remotes, other.Remotes)
+	c.Discover = append(c.Discover, other.Discover...)
+	c.Maintenance = append(c.Maintenance, other.Maintenance...)
+}
+
+// mergeEndpoints appends endpoints to base, replacing endpoints of the same
+// name in place.
+func mergeEndpoints(base, endpoints []Endpoint) []Endpoint {
+	for _, endpoint := range endpoints {
+		replaced := false
+		if endpoint.Name != "" {
+			for i := range base {
+				if base[i].Name == endpoint.Name {
+					base[i] = endpoint
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			base = append(base, endpoint)
+		}
+	}
+	return base
+}
+
+// SelectTags keeps only the endpoints that have one of tags (all endpoints
+// if tags is empty) and none of skipTags. Endpoints that a kept endpoint
+// depends on are kept too, so chained requests still work.
+func (c *BatchConfig) SelectTags(tags, skipTags []string) error {
+	if len(tags) == 0 && len(skipTags) == 0 {
+		return nil
+	}
+
+	byName := make(map[string]int, len(c.Endpoints))
+	for i, endpoint := range c.Endpoints {
+		if _, exists := byName[endpoint.Name]; !exists {
+			byName[endpoint.Name] = i
+		}
+	}
+
+	keep := make([]bool, len(c.Endpoints))
+	var include func(i int)
+	include = func(i int) {
+		if keep[i] {
+			return
+		}
+		keep[i] = true
+		for _, dep := range c.Endpoints[i].DependsOn {
+			include(byName[dep])
+		}
+	}
+	for i, endpoint := range c.Endpoints {
+		if (len(tags) == 0 || endpoint.HasTag(tags...)) && !endpoint.HasTag(skipTags...) {
+			include(i)
+		}
+	}
+
+	selected := make([]Endpoint, 0, len(c.Endpoints))
+	for i, endpoint := range c.Endpoints {
+		if keep[i] {
+			selected = append(selected, endpoint)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no endpoints match the selected tags")
+	}
+
+	c.Endpoints = selected
+	return nil
+}
+
+// flattenStages moves the endpoints of each stage into config.Endpoints,
+// recording the stage on every endpoint.
+func flattenStages(config *BatchConfig) error {
+	if len(config.Stages) == 0 {
+		return nil
+	}
+	if len(config.Endpoints) > 0 {
+		return fmt.Errorf("batch config defines both endpoints and stages (put every endpoint in a stage)")
+	}
+	if len(config.Discover) > 0 {
+		return fmt.Errorf("batch config defines both discover and stages (put discover entries in a stage)")
+	}
+
+	for i, stage := range config.Stages {
+		if stage.Name == "" {
+			stage.Name = fmt.Sprintf("stage %d", i+1)
+		}
+		if len(stage.Endpoints) == 0 && len(stage.Discover) == 0 {
+			return fmt.Errorf("stage '%s' has no endpoints", stage.Name)
+		}
+		for _, endpoint := range stage.Endpoints {
+			endpoint.Stage = stage.Name
+			endpoint.StageIndex = i
+			config.Endpoints = append(config.Endpoints, endpoint)
+		}
+		for _, d := range stage.Discover {
+			d.Stage = stage.Name
+			d.StageIndex = i
+			config.Discover = append(config.Discover, d)
+		}
+	}
+	return nil
+}
+
+// prepareEndpoint fills in endpoint defaults and validates its fields.
+func prepareEndpoint(endpoint *Endpoint) error {
+	// Default method to GET, or POST when there is a body
+	if endpoint.Method == "" {
+		endpoint.Method = "GET"
+		if endpoint.Body != "" {
+			endpoint.Method = "POST"
+		}
+	}
+
+	// Default expected status to 200
+	if endpoint.ExpectedStatus == 0 {
+		endpoint.ExpectedStatus = 200
+	}
+
+	// Validate URL
+	if endpoint.URL == "" {
+		return fmt.Errorf("endpoint '%s' has no URL", endpoint.Name)
+	}
+	if err := validateURL(endpoint.URL); err != nil {
+		return fmt.Errorf("endpoint '%s' has invalid URL '%s': %w", endpoint.Name, endpoint.URL, err)
+	}
+
+	if endpoint.Retries < 0 {
+		return fmt.Errorf("endpoint '%s' has negative retries", endpoint.Name)
+	}
+	if endpoint.Timeout < 0 || endpoint.FirstByteTimeout < 0 || endpoint.IdleTimeout < 0 ||
+		endpoint.ConnectTimeout < 0 || endpoint.ResponseTimeout < 0 {
+		return fmt.Errorf("endpoint '%s' has a negative timeout", endpoint.Name)
+	}
+
+	// Validate size limits
+	if endpoint.MinSize < 0 || endpoint.MaxSize < 0 {
+		return fmt.Errorf("endpoint '%s' has a negative size limit", endpoint.Name)
+	}
+	if endpoint.MaxSize > 0 && endpoint.MinSize > endpoint.MaxSize {
+		return fmt.Errorf("endpoint '%s' has min_size greater than max_size", endpoint.Name)
+	}
+
+	// Validate header assertions (e.g., regex syntax)
+	if _, err := assert.ParseHeaderExpectations(endpoint.ExpectHeaders); err != nil {
+		return fmt.Errorf("endpoint '%s': %w", endpoint.Name, err)
+	}
+
+	// Validate capture paths
+	for name, path := range endpoint.Capture {
+		if _, err := jsonpath.Parse(path); err != nil {
+			return fmt.Errorf("endpoint '%s': capture '%s': %w", endpoint.Name, name, err)
+		}
+	}
+
+	// Validate XPath assertions
+	if _, err := assert.ParseXPathExpectations(endpoint.ExpectXPath); err != nil {
+		return fmt.Errorf("endpoint '%s': expect_xpath: %w", endpoint.Name, err)
+	}
+
+	if endpoint.SOAPAction != "" && endpoint.Body == "" {
+		return fmt.Errorf("endpoint '%s': soap_action needs a body with the SOAP envelope", endpoint.Name)
+	}
+
+	// Compile the schema to report problems before any request is made
+	if endpoint.ExpectSchema != "" {
+		if _, err := jsonschema.NewCache().Load(endpoint.ExpectSchema); err != nil {
+			return fmt.Errorf("endpoint '%s': expect_schema: %w", endpoint.Name, err)
+		}
+	}
+
+	// Validate snapshot ignore paths
+	for _, path := range endpoint.SnapshotIgnore {
+		if _, err := jsonpath.Parse(path); err != nil {
+			return fmt.Errorf("endpoint '%s': snapshot_ignore: %w", endpoint.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateDependencies checks that every depends_on entry refers to a
+// uniquely named endpoint and that there are no dependency cycles.
+func validateDependencies(endpoints []Endpoint) error {
+	byName := make(map[string]int, len(endpoints))
+	for i, endpoint := range endpoints {
+		if _, exists := byName[endpoint.Name]; exists && endpoint.Name != "" {
+			byName[endpoint.Name] = -1 // Duplicate name
+			continue
+		}
+		byName[endpoint.Name] = i
+	}
+
+	for _, endpoint := range endpoints {
+		for _, dep := range endpoint.DependsOn {
+			index, ok := byName[dep]
+			if !ok || dep == "" {
+				return fmt.Errorf("endpoint '%s' depends on unknown endpoint '%s'", endpoint.Name, dep)
+			}
+			if index == -1 {
+				return fmt.Errorf("endpoint '%s' depends on '%s', but that name is used by multiple endpoints", endpoint.Name, dep)
+			}
+			if endpoints[index].StageIndex > endpoint.StageIndex {
+				return fmt.Errorf("endpoint '%s' depends on '%s', which runs in a later stage", endpoint.Name, dep)
+			}
+		}
+	}
+
+	// Depth-first search for cycles
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(endpoints))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("dependency cycle involving endpoint '%s'", endpoints[i].Name)
+		case visited:
+			return nil
+		}
+
+		state[i] = visiting
+		for _, dep := range endpoints[i].DependsOn {
+			if err := visit(byName[dep]); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+
+	for i := range endpoints {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadBatchConfig_Stages(t *testing.T) {
+	content := `stages:
+  - name: infra
+    endpoints:
+      - name: db
+        url: https://db.example.com
+      - name: cache
+        url: https://cache.example.com
+  - endpoints:
+      - name: api
+        url: https://api.example.com
+        depends_on: [db]
+`
+	path := filepath.Join(t.TempDir(), "batch.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadBatchConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+
+	var got []string
+	for _, endpoint := range cfg.Endpoints {
+		got = append(got, endpoint.Name+"@"+endpoint.Stage)
+	}
+	want := []string{"db@infra", "cache@infra", "api@stage 2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+	if cfg.Endpoints[2].StageIndex != 1 {
+		t.Errorf("api StageIndex = %d, want 1", cfg.Endpoints[2].StageIndex)
+	}
+}
+
+func TestLoadBatchConfig_StageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			"endpoints and stages",
+			"endpoints:\n  - url: https://a.example.com\nstages:\n  - endpoints:\n      - url: https://b.example.com\n",
+			"both endpoints and stages",
+		},
+		{
+			"empty stage",
+			"stages:\n  - name: infra\n",
+			"stage 'infra' has no endpoints",
+		},
+		{
+			"dependency in later stage",
+			"stages:\n  - endpoints:\n      - name: a\n        url: https://a.example.com\n        depends_on: [b]\n  - endpoints:\n      - name: b\n        url: https://b.example.com\n",
+			"later stage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "batch.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadBatchConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBatchConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBatchConfig_Include(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"common.yml": `concurrency: 2
+timeout: 3s
+variables:
+  base: https://common.example.com
+  token: common
+headers:
+  Accept: application/json
+  X-Team: common
+endpoints:
+  - name: health
+    url: "{{base}}/health"
+  - name: status
+    url: "{{base}}/status"
+`,
+		"shared/payments.yml": `variables:
+  token: payments
+endpoints:
+  - name: pay
+    url: https://pay.example.com
+`,
+		"main.yml": `include: [common.yml, shared/payments.yml]
+concurrency: 8
+variables:
+  base: https://main.example.com
+headers:
+  X-Team: main
+endpoints:
+  - name: status
+    url: https://main.example.com/status
+    expected_status: 204
+  - name: users
+    url: https://main.example.com/users
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := LoadBatchConfig(filepath.Join(dir, "main.yml"))
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+
+	var got []string
+	for _, endpoint := range cfg.Endpoints {
+		got = append(got, endpoint.Name)
+	}
+	if want := []string{"health", "status", "pay", "users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+	if cfg.Endpoints[1].ExpectedStatus != 204 {
+		t.Errorf("status expected_status = %d, want 204 from main.yml", cfg.Endpoints[1].ExpectedStatus)
+	}
+	if cfg.Concurrency != 8 || cfg.Timeout != 3*time.Second {
+		t.Errorf("concurrency, timeout = %d, %v; want 8, 3s", cfg.Concurrency, cfg.Timeout)
+	}
+	wantVars := map[string]string{"base": "https://main.example.com", "token": "payments"}
+	if !reflect.DeepEqual(cfg.Variables, wantVars) {
+		t.Errorf("variables = %v, want %v", cfg.Variables, wantVars)
+	}
+	wantHeaders := map[string]string{"Accept": "application/json", "X-Team": "main"}
+	if !reflect.DeepEqual(cfg.Headers, wantHeaders) {
+		t.Errorf("headers = %v, want %v", cfg.Headers, wantHeaders)
+	}
+}
+
+func TestLoadBatchConfig_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			"missing include",
+			map[string]string{"main.yml": "include: [missing.yml]\n"},
+			"not found",
+		},
+		{
+			"cycle",
+			map[string]string{
+				"main.yml":  "include: [other.yml]\nendpoints:\n  - url: https://a.example.com\n",
+				"other.yml": "include: [main.yml]\n",
+			},
+			"include cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := LoadBatchConfig(filepath.Join(dir, "main.yml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBatchConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBatchConfig_Body(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "payloads"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "payloads", "user.json"), []byte(`{"name": "{{name}}"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content := `endpoints:
+  - name: file
+    url: https://api.example.com/users
+    body: "@payloads/user.json"
+  - name: inline
+    url: https://api.example.com/users
+    method: PUT
+    body: hello
+  - name: missing
+    url: https://api.example.com/users
+`
+	path := filepath.Join(dir, "batch.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadBatchConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+
+	file := cfg.Endpoints[0]
+	if file.Body != `{"name": "{{name}}"}` || file.BodyFile != filepath.Join(dir, "payloads", "user.json") {
+		t.Errorf("file body = %q from %q, want the contents of payloads/user.json", file.Body, file.BodyFile)
+	}
+	if file.Method != "POST" {
+		t.Errorf("file method = %s, want POST for an endpoint with a body", file.Method)
+	}
+	if cfg.Endpoints[1].Method != "PUT" || cfg.Endpoints[1].BodyFile != "" {
+		t.Errorf("inline method, body file = %s, %q; want PUT, none", cfg.Endpoints[1].Method, cfg.Endpoints[1].BodyFile)
+	}
+	if cfg.Endpoints[2].Method != "GET" {
+		t.Errorf("missing method = %s, want GET", cfg.Endpoints[2].Method)
+	}
+
+	// A missing body file is an error
+	if err := os.WriteFile(path, []byte("endpoints:\n  - name: a\n    url: https://a.example.com\n    body: \"@nope.json\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBatchConfig(path); err == nil || !strings.Contains(err.Error(), "failed to read body file") {
+		t.Errorf("LoadBatchConfig() error = %v, want body file error", err)
+	}
+}
+
+func TestLoadBatchConfig_ExpectSchema(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	schemaPath := filepath.Join(dir, "schemas", "user.json")
+	if err := os.WriteFile(schemaPath, []byte(`{"type": "object", "required": ["id"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "batch.yml")
+	content := "endpoints:\n  - name: user\n    url: https://api.example.com/users/1\n    expect_schema: schemas/user.json\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadBatchConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+	if cfg.Endpoints[0].ExpectSchema != schemaPath {
+		t.Errorf("ExpectSchema = %s, want %s (relative to the config)", cfg.Endpoints[0].ExpectSchema, schemaPath)
+	}
+
+	// Invalid schemas are reported when the config is loaded
+	if err := os.WriteFile(schemaPath, []byte(`{"type": "int"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBatchConfig(path); err == nil || !strings.Contains(err.Error(), "expect_schema: schema user.json#/type: unknown type 'int'") {
+		t.Errorf("LoadBatchConfig() error = %v, want the schema error", err)
+	}
+}
+
+func TestBatchConfig_SelectTags(t *testing.T) {
+	endpoints := []Endpoint{
+		{Name: "login", Tags: []string{"auth"}},
+		{Name: "pay", Tags: []string{"critical", "payments"}, DependsOn: []string{"login"}},
+		{Name: "refund", Tags: []string{"payments", "flaky"}},
+		{Name: "home"},
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		skipTags []string
+		want     []string
+		wantErr  bool
+	}{
+		{"no filter", nil, nil, []string{"login", "pay", "refund", "home"}, false},
+		{"tag", []string{"payments"}, nil, []string{"login", "pay", "refund"}, false}, // login is a dependency
+		{"any of several tags", []string{"auth", "critical"}, nil, []string{"login", "pay"}, false},
+		{"skip tag", nil, []string{"flaky"}, []string{"login", "pay", "home"}, false},
+		{"tag and skip tag", []string{"payments"}, []string{"flaky"}, []string{"login", "pay"}, false},
+		{"no match", []string{"missing"}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &BatchConfig{Endpoints: append([]Endpoint(nil), endpoints...)}
+			err := cfg.SelectTags(tt.tags, tt.skipTags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, endpoint := range cfg.Endpoints {
+				got = append(got, endpoint.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectTags() kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBatchConfig(t *testing.T) {
+	cfg, err := ParseBatchConfig([]byte("stages:\n  - name: smoke\n    endpoints:\n      - url: https://api.example.com\n"), "controller config")
+	if err != nil {
+		t.Fatalf("ParseBatchConfig() error = %v", err)
+	}
+	if len(cfg.Endpoints) != 1 || cfg.Endpoints[0].Stage != "smoke" || cfg.Concurrency != 5 {
+		t.Errorf("ParseBatchConfig() = %+v", cfg)
+	}
+
+	if _, err := ParseBatchConfig([]byte("endpoints:\n  - url: https://a.example.com\n    retry: 2\n"), "controller config"); err == nil || !strings.Contains(err.Error(), "controller config") {
+		t.Errorf("ParseBatchConfig() error = %v, want one naming the config", err)
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDaemonListen is the address the daemon status API binds to when
+// none is configured. It only accepts local connections.
+const DefaultDaemonListen = "127.0.0.1:9876"
+
+// Monitor is an endpoint checked on a fixed schedule by the daemon.
+type Monitor struct {
+	Endpoint `yaml:",inline"`
+
+	Interval time.Duration `yaml:"interval"` // Time between checks (defaults to the global interval)
+}
+
+// DaemonConfig represents a daemon configuration file.
+type DaemonConfig struct {
+	Listen   string        `yaml:"listen"`    // Address for the status API and page
+	Title    string        `yaml:"title"`     // Heading of the status page
+	APIToken string        `yaml:"api_token"` // Bearer token for the control API (default: local requests only)
+	History  string        `yaml:"history"`   // Path of the history store
+	Interval time.Duration `yaml:"interval"`  // Default time between checks
+	Timeout  time.Duration `yaml:"timeout"`   // Default request timeout
+	SLO      float64       `yaml:"slo"`       // Availability target in percent (0 = none)
+	Monitors []Monitor     `yaml:"monitors"`  // Endpoints to monitor
+
+	State       StateConfig         `yaml:"state"`           // When monitors count as down, up or flapping
+	Alerts      *AlertConfig        `yaml:"alerts"`          // Optional alerting
+	Breaker     *BreakerConfig      `yaml:"circuit_breaker"` // Optional back-off for monitors that are down
+	Maintenance []MaintenanceWindow `yaml:"maintenance"`     // Windows in which failures don't alert
+	Silences    string              `yaml:"silences"`        // Path of the silences file (see tapr silence)
+}
+
+// StateConfig sets how many checks it takes to change a monitor's state on
+// the status API and page, when its changes count as flapping, and when its
+// latency counts as an anomaly.
+type StateConfig struct {
+	FailureThreshold  int `yaml:"failure_threshold"`  // Consecutive failures before down (default 1)
+	RecoveryThreshold int `yaml:"recovery_threshold"` // Consecutive successes before up again (default 1)
+	FlapWindow        int `yaml:"flap_window"`        // Recent checks examined for flapping (default 20)
+	FlapThreshold     int `yaml:"flap_threshold"`     // State changes in the window that count as flapping (default 6, -1 = off)
+
+	AnomalyFactor float64 `yaml:"anomaly_factor"` // Deviations above the learned baseline latency that count as an anomaly (default 3, -1 = off)
+}
+
+// BreakerConfig configures the circuit breaker of every monitor.
+type BreakerConfig struct {
+	Failures    int           `yaml:"failures"`     // Consecutive failures that open the circuit
+	MaxInterval time.Duration `yaml:"max_interval"` // Longest interval between probes while open
+}
+
+// LoadDaemonConfig reads and parses a daemon configuration YAML file.
+//
+// Example file:
+//
+//	interval: 30s
+//	monitors:
+//	  - name: api
+//	    url: https://api.example.com/health
+//	    depends_on: [auth]   # Degraded rather than down while auth is down
+//	  - name: auth
+//	    url: https://auth.example.com/health
+//	    interval: 10s
+//	state:
+//	  failure_threshold: 3
+//	  recovery_threshold: 2
+//	circuit_breaker:
+//	  failures: 3
+//	  max_interval: 5m
+func LoadDaemonConfig(filepath string) (*DaemonConfig, error) {
+	// Check if file exists
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("daemon config file not found: %s", filepath)
+	}
+
+	// Read file contents
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daemon config: %w", err)
+	}
+
+	// Parse YAML
+	var config DaemonConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon config YAML: %w", err)
+	}
+
+	if len(config.Monitors) == 0 {
+		return nil, fmt.Errorf("no monitors defined in daemon config")
+	}
+
+	// Set global defaults
+	if config.Listen == "" {
+		config.Listen = DefaultDaemonListen
+	}
+	if config.Interval == 0 {
+		config.Interval = time.Minute
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	if config.Alerts != nil {
+		if err := config.Alerts.prepare(); err != nil {
+			return nil, err
+		}
+	}
+	if config.State.FailureThreshold < 0 {
+		return nil, fmt.Errorf("state: failure_threshold must be positive")
+	}
+	if err := validateFlapping(config.State.RecoveryThreshold, config.State.FlapWindow, config.State.FlapThreshold); err != nil {
+		return nil, fmt.Errorf("state: %w", err)
+	}
+	if config.SLO < 0 || config.SLO >= 100 {
+		return nil, fmt.Errorf("slo must be between 0 and 100 (e.g. 99.9)")
+	}
+	if config.Breaker != nil && config.Breaker.Failures < 1 {
+		return nil, fmt.Errorf("circuit_breaker: failures must be at least 1")
+	}
+	if err := prepareMaintenance(config.Maintenance); err != nil {
+		return nil, err
+	}
+
+	if err := expandEnv(&config.APIToken); err != nil {
+		return nil, fmt.Errorf("api_token: %w", err)
+	}
+
+	// Monitors are identified by name in the history store and status API
+	names := make(map[string]bool, len(config.Monitors))
+	for i := range config.Monitors {
+		monitor := &config.Monitors[i]
+
+		if monitor.Name == "" {
+			return nil, fmt.Errorf("monitor %d has no name", i+1)
+		}
+		if names[monitor.Name] {
+			return nil, fmt.Errorf("duplicate monitor name '%s'", monitor.Name)
+		}
+		names[monitor.Name] = true
+
+		if err := config.PrepareMonitor(monitor); err != nil {
+			return nil, err
+		}
+	}
+
+	// depends_on names other monitors, without cycles
+	endpoints := make([]Endpoint, len(config.Monitors))
+	for i, monitor := range config.Monitors {
+		endpoints[i] = monitor.Endpoint
+	}
+	if err := validateDependencies(endpoints); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// PrepareMonitor validates a monitor and fills in its defaults from the
+// config. It is used for monitors in the file and for those added at
+// runtime through the control API.
+func (c *DaemonConfig) PrepareMonitor(monitor *Monitor) error {
+	if monitor.Name == "" {
+		return fmt.Errorf("monitor has no name")
+	}
+	if err := prepareEndpoint(&monitor.Endpoint); err != nil {
+		return err
+	}
+
+	if monitor.Interval == 0 {
+		monitor.Interval = c.Interval
+	}
+	if monitor.Interval < time.Second {
+		return fmt.Errorf("monitor '%s': interval must be at least 1s", monitor.Name)
+	}
+	return nil
+}
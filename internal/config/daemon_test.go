@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadDaemonConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.yml")
+	content := `interval: 30s
+monitors:
+  - name: api
+    url: https://api.example.com/health
+  - name: auth
+    url: https://auth.example.com/health
+    interval: 10s
+    expected_status: 204
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadDaemonConfig(path)
+	if err != nil {
+		t.Fatalf("LoadDaemonConfig() error = %v", err)
+	}
+
+	if cfg.Listen != DefaultDaemonListen {
+		t.Errorf("Listen = %s, want %s", cfg.Listen, DefaultDaemonListen)
+	}
+	if cfg.Monitors[0].Interval != 30*time.Second {
+		t.Errorf("Monitors[0].Interval = %v, want 30s", cfg.Monitors[0].Interval)
+	}
+	if cfg.Monitors[1].Interval != 10*time.Second {
+		t.Errorf("Monitors[1].Interval = %v, want 10s", cfg.Monitors[1].Interval)
+	}
+	if cfg.Monitors[0].Method != "GET" || cfg.Monitors[0].ExpectedStatus != 200 {
+		t.Errorf("Monitors[0] defaults = %s %d, want GET 200", cfg.Monitors[0].Method, cfg.Monitors[0].ExpectedStatus)
+	}
+	if cfg.Monitors[1].ExpectedStatus != 204 {
+		t.Errorf("Monitors[1].ExpectedStatus = %d, want 204", cfg.Monitors[1].ExpectedStatus)
+	}
+}
+
+func TestLoadDaemonConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no monitors", "interval: 30s\n", "no monitors"},
+		{"missing name", "monitors:\n  - url: https://a.example.com\n", "has no name"},
+		{"duplicate name", "monitors:\n  - name: a\n    url: https://a.example.com\n  - name: a\n    url: https://b.example.com\n", "duplicate"},
+		{"interval too short", "monitors:\n  - name: a\n    url: https://a.example.com\n    interval: 100ms\n", "at least 1s"},
+		{"breaker without failures", "circuit_breaker:\n  max_interval: 5m\nmonitors:\n  - name: a\n    url: https://a.example.com\n", "failures must be at least 1"},
+		{"unknown dependency", "monitors:\n  - name: a\n    url: https://a.example.com\n    depends_on: [b]\n", "depends on unknown endpoint 'b'"},
+		{"flap threshold above window", "state:\n  flap_window: 10\n  flap_threshold: 10\nmonitors:\n  - name: a\n    url: https://a.example.com\n", "state: flap_threshold must be below flap_window"},
+		{"negative recovery threshold", "alerts:\n  recovery_threshold: -2\nmonitors:\n  - name: a\n    url: https://a.example.com\n", "alerts: recovery_threshold must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "daemon.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadDaemonConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadDaemonConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadAlertConfig(t *testing.T) {
+	t.Setenv("TAPR_TEST_SLACK", "https://hooks.slack.com/services/T/B/X")
+
+	path := filepath.Join(t.TempDir(), "alerts.yml")
+	content := `alerts:
+  latency_threshold: 2s
+  slack:
+    webhook_url: "{{env.TAPR_TEST_SLACK}}"
+  email:
+    smtp_host: smtp.example.com
+    from: tapr@example.com
+    to: [oncall@example.com]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadAlertConfig(path)
+	if err != nil {
+		t.Fatalf("LoadAlertConfig() error = %v", err)
+	}
+
+	if cfg.Slack.WebhookURL != "https://hooks.slack.com/services/T/B/X" {
+		t.Errorf("Slack.WebhookURL = %s, want value from environment", cfg.Slack.WebhookURL)
+	}
+	if cfg.FailureThreshold != 2 || cfg.Cooldown != 30*time.Minute {
+		t.Errorf("defaults = %d, %v; want 2, 30m", cfg.FailureThreshold, cfg.Cooldown)
+	}
+	if cfg.Email.Port != 587 {
+		t.Errorf("Email.Port = %d, want 587", cfg.Email.Port)
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Discovery adds endpoints at run time for every instance of a service
+// registered in a service catalog, so checks follow the instances that
+// are actually running. The endpoint fields are a template applied to
+// each instance; name becomes the prefix of their names ("api@10.0.0.5:8080").
+type Discovery struct {
+	Consul *ConsulDiscovery `yaml:"consul,omitempty"` // Consul service catalog
+	Etcd   *EtcdDiscovery   `yaml:"etcd,omitempty"`   // etcd keys under a prefix
+
+	Scheme string `yaml:"scheme,omitempty"` // Scheme of the instance URLs (default "http")
+	Path   string `yaml:"path,omitempty"`   // Path to request on every instance (default "/")
+
+	Endpoint `yaml:",inline"` // Template for the discovered endpoints
+}
+
+// ConsulDiscovery selects the instances of a service registered in Consul.
+type ConsulDiscovery struct {
+	Address    string `yaml:"address,omitempty"`    // Consul HTTP API (default $CONSUL_HTTP_ADDR or 127.0.0.1:8500)
+	Service    string `yaml:"service,omitempty"`    // Service name
+	Tag        string `yaml:"tag,omitempty"`        // Only instances with this tag
+	Datacenter string `yaml:"datacenter,omitempty"` // Datacenter (default: the agent's)
+	Token      string `yaml:"token,omitempty"`      // ACL token (default $CONSUL_HTTP_TOKEN)
+	Passing    bool   `yaml:"passing,omitempty"`    // Only instances whose Consul health checks pass
+}
+
+// EtcdDiscovery selects instances stored as etcd keys under a prefix. Each
+// value is an address ("10.0.0.5:8080"), a URL or a JSON object with an
+// "addr", "address" or "url" field.
+type EtcdDiscovery struct {
+	Address  string `yaml:"address,omitempty"`  // etcd endpoint (default $ETCDCTL_ENDPOINTS or 127.0.0.1:2379)
+	Prefix   string `yaml:"prefix,omitempty"`   // Key prefix ("/services/api/")
+	Username string `yaml:"username,omitempty"` // Optional etcd user
+	Password string `yaml:"password,omitempty"` // Password for username
+}
+
+// Provider returns a description of where the discovery looks, like
+// "consul service api".
+func (d Discovery) Provider() string {
+	if d.Consul != nil {
+		return "consul service " + d.Consul.Service
+	}
+	if d.Etcd != nil {
+		return "etcd prefix " + d.Etcd.Prefix
+	}
+	return "discovery"
+}
+
+// validateDiscovery fills in discovery defaults and checks that every entry
+// has exactly one provider and a valid endpoint template.
+func validateDiscovery(discover []Discovery) error {
+	for i := range discover {
+		d := &discover[i]
+		where := fmt.Sprintf("discover '%s'", d.Name)
+		if d.Source != "" {
+			where = d.Source + ": " + where
+		}
+
+		switch {
+		case d.Name == "":
+			return fmt.Errorf("discover entry for %s has no name", d.Provider())
+		case (d.Consul == nil) == (d.Etcd == nil):
+			return fmt.Errorf("%s needs exactly one of consul or etcd", where)
+		case d.Consul != nil && d.Consul.Service == "":
+			return fmt.Errorf("%s has no consul service", where)
+		case d.Etcd != nil && d.Etcd.Prefix == "":
+			return fmt.Errorf("%s has no etcd prefix", where)
+		case d.URL != "":
+			return fmt.Errorf("%s sets url (use scheme and path; the address comes from discovery)", where)
+		case len(d.DependsOn) > 0:
+			return fmt.Errorf("%s can't use depends_on", where)
+		}
+
+		if d.Scheme == "" {
+			d.Scheme = "http"
+		}
+		if d.Scheme != "http" && d.Scheme != "https" {
+			return fmt.Errorf("%s has unsupported scheme '%s' (expected http or https)", where, d.Scheme)
+		}
+		if !strings.HasPrefix(d.Path, "/") {
+			d.Path = "/" + d.Path
+		}
+
+		// Check the template like a real endpoint, without keeping the URL
+		template := d.Endpoint
+		template.URL = d.Scheme + "://discovered.invalid" + d.Path
+		if err := prepareEndpoint(&template); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		template.URL = ""
+		d.Endpoint = template
+	}
+	return nil
+}
+
+// ExpandDiscovery replaces the config's discover entries with an endpoint
+// for every instance they find. lookup returns the addresses ("host:port")
+// or base URLs ("https://host:port") of the instances of one entry.
+// Discovered endpoints join the stage of their entry, after its fixed
+// endpoints. An entry that finds no instances is an error.
+func (c *BatchConfig) ExpandDiscovery(lookup func(Discovery) ([]string, error)) error {
+	for _, d := range c.Discover {
+		d, err := d.render(c.Variables)
+		if err != nil {
+			return fmt.Errorf("discover '%s': %w", d.Name, err)
+		}
+		addresses, err := lookup(d)
+		if err != nil {
+			return fmt.Errorf("discover '%s': %w", d.Name, err)
+		}
+		if len(addresses) == 0 {
+			return fmt.Errorf("discover '%s': %s has no instances", d.Name, d.Provider())
+		}
+
+		seen := make(map[string]bool, len(addresses))
+		var discovered []Endpoint
+		for _, address := range addresses {
+			base := address
+			if !strings.Contains(base, "://") {
+				base = d.Scheme + "://" + address
+			}
+			base = strings.TrimSuffix(base, "/")
+			host := base[strings.Index(base, "://")+3:]
+			if i := strings.Index(host, "/"); i >= 0 {
+				host = host[:i]
+			}
+			if seen[host] {
+				continue
+			}
+			seen[host] = true
+
+			endpoint := d.Endpoint
+			endpoint.Name = d.Name + "@" + host
+			endpoint.URL = base + d.Path
+			if err := validateURL(endpoint.URL); err != nil {
+				return fmt.Errorf("discover '%s': invalid instance address '%s': %w", d.Name, address, err)
+			}
+			if _, _, err := net.SplitHostPort(host); err != nil {
+				return fmt.Errorf("discover '%s': instance address '%s' has no port", d.Name, address)
+			}
+			discovered = append(discovered, endpoint)
+		}
+		c.Endpoints = insertEndpoints(c.Endpoints, discovered, d.StageIndex)
+	}
+	c.Discover = nil
+
+	return validateNames(c.Endpoints)
+}
+
+// render returns the entry with {{name}} and {{env.NAME}} placeholders in
+// its provider settings replaced, so addresses and credentials can come from
+// variables or the environment.
+func (d Discovery) render(vars map[string]string) (Discovery, error) {
+	var fields []*string
+	if d.Consul != nil {
+		consul := *d.Consul
+		d.Consul = &consul
+		fields = append(fields, &consul.Address, &consul.Service, &consul.Tag, &consul.Datacenter, &consul.Token)
+	}
+	if d.Etcd != nil {
+		etcd := *d.Etcd
+		d.Etcd = &etcd
+		fields = append(fields, &etcd.Address, &etcd.Prefix, &etcd.Username, &etcd.Password)
+	}
+	for _, field := range fields {
+		rendered, err := RenderTemplate(*field, vars)
+		if err != nil {
+			return d, err
+		}
+		*field = rendered
+	}
+	return d, nil
+}
+
+// insertEndpoints adds endpoints after the last endpoint of the given stage
+// (or of an earlier one), keeping the list ordered by stage.
+func insertEndpoints(endpoints, added []Endpoint, stageIndex int) []Endpoint {
+	at := 0
+	for i, endpoint := range endpoints {
+		if endpoint.StageIndex <= stageIndex {
+			at = i + 1
+		}
+	}
+	result := make([]Endpoint, 0, len(endpoints)+len(added))
+	result = append(result, endpoints[:at]...)
+	result = append(result, added...)
+	return append(result, endpoints[at:]...)
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBatchConfig_ExpandDiscovery(t *testing.T) {
+	content := `stages:
+  - name: setup
+    endpoints:
+      - name: login
+        url: https://auth.example.com/login
+  - name: services
+    discover:
+      - name: api
+        consul: {service: api, passing: true}
+        path: healthz
+        expected_status: 204
+        tags: [api]
+    endpoints:
+      - name: web
+        url: https://www.example.com
+  - name: workers
+    discover:
+      - name: worker
+        etcd: {prefix: /services/worker/}
+        scheme: https
+`
+	path := filepath.Join(t.TempDir(), "batch.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadBatchConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+	if len(cfg.Discover) != 2 || cfg.Discover[0].Source != path+":8" {
+		t.Fatalf("Discover = %+v, want 2 entries with sources", cfg.Discover)
+	}
+
+	instances := map[string][]string{
+		"api":    {"10.0.0.6:8080", "10.0.0.5:8080", "10.0.0.5:8080"},
+		"worker": {"https://10.0.1.7:9443/"},
+	}
+	err = cfg.ExpandDiscovery(func(d Discovery) ([]string, error) {
+		return instances[d.Name], nil
+	})
+	if err != nil {
+		t.Fatalf("ExpandDiscovery() error = %v", err)
+	}
+
+	type row struct {
+		name, url, stage string
+		status           int
+	}
+	var got []row
+	for _, endpoint := range cfg.Endpoints {
+		got = append(got, row{endpoint.Name, endpoint.URL, endpoint.Stage, endpoint.ExpectedStatus})
+	}
+	want := []row{
+		{"login", "https://auth.example.com/login", "setup", 200},
+		{"web", "https://www.example.com", "services", 200},
+		{"api@10.0.0.6:8080", "http://10.0.0.6:8080/healthz", "services", 204},
+		{"api@10.0.0.5:8080", "http://10.0.0.5:8080/healthz", "services", 204},
+		{"worker@10.0.1.7:9443", "https://10.0.1.7:9443/", "workers", 200},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Endpoints =\n%v\nwant\n%v", got, want)
+	}
+	if !cfg.Endpoints[2].HasTag("api") || cfg.Endpoints[2].Method != "GET" {
+		t.Errorf("discovered endpoint = %+v, want the template's tags and method", cfg.Endpoints[2])
+	}
+	if cfg.Discover != nil {
+		t.Errorf("Discover = %v, want nil after expanding", cfg.Discover)
+	}
+}
+
+func TestBatchConfig_ExpandDiscoveryErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []string
+		err       error
+		wantErr   string
+	}{
+		{"lookup fails", nil, errors.New("consul: connection refused"), "discover 'api': consul: connection refused"},
+		{"no instances", nil, nil, "consul service api has no instances"},
+		{"no port", []string{"10.0.0.5"}, nil, "instance address '10.0.0.5' has no port"},
+		{"duplicate name", []string{"10.0.0.5:80", "https://10.0.0.5:80"}, nil, ""},
+		{"clashes with endpoint", []string{"web:80"}, nil, "duplicate endpoint name 'api@web:80'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseBatchConfig([]byte(`endpoints:
+  - name: api@web:80
+    url: http://web
+discover:
+  - name: api
+    consul: {service: api}
+`), "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = cfg.ExpandDiscovery(func(Discovery) ([]string, error) { return tt.instances, tt.err })
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ExpandDiscovery() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExpandDiscovery() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBatchConfig_DiscoveryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no name", "discover:\n  - consul: {service: api}\n", "discover entry for consul service api has no name"},
+		{"no provider", "discover:\n  - name: api\n", "needs exactly one of consul or etcd"},
+		{"two providers", "discover:\n  - name: api\n    consul: {service: api}\n    etcd: {prefix: /api}\n", "needs exactly one of consul or etcd"},
+		{"no service", "discover:\n  - name: api\n    consul: {tag: v2}\n", "has no consul service"},
+		{"no prefix", "discover:\n  - name: api\n    etcd: {address: etcd:2379}\n", "has no etcd prefix"},
+		{"url", "discover:\n  - name: api\n    consul: {service: api}\n    url: http://api\n", "sets url"},
+		{"scheme", "discover:\n  - name: api\n    consul: {service: api}\n    scheme: ftp\n", "unsupported scheme 'ftp'"},
+		{"template", "discover:\n  - name: api\n    consul: {service: api}\n    retries: -1\n", "negative retries"},
+		{"with stages", "stages:\n  - endpoints: [{url: http://a}]\ndiscover:\n  - name: api\n    consul: {service: api}\n", "both discover and stages"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "batch.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadBatchConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBatchConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBatchConfig_StandaloneDiscovery(t *testing.T) {
+	cfg, err := ParseBatchConfig([]byte(`stages:
+  - name: first
+    endpoints: [{name: web, url: http://web}]
+  - name: second
+    discover:
+      - name: api
+        consul: {service: api}
+        path: /healthz
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := cfg.Standalone()
+	if err != nil {
+		t.Fatalf("Standalone() error = %v", err)
+	}
+	parsed, err := ParseBatchConfig(data, "standalone")
+	if err != nil {
+		t.Fatalf("ParseBatchConfig(Standalone()) error = %v\n%s", err, data)
+	}
+	if len(parsed.Discover) != 1 {
+		t.Fatalf("Discover = %+v, want the api entry", parsed.Discover)
+	}
+	d := parsed.Discover[0]
+	if d.Stage != "second" || d.StageIndex != 1 || d.Consul.Service != "api" || d.Path != "/healthz" {
+		t.Errorf("Discover[0] = %+v, want api in stage second", d)
+	}
+}
+
+func TestBatchConfig_ExpandDiscoveryTemplates(t *testing.T) {
+	t.Setenv("TAPR_TEST_CONSUL_TOKEN", "s3cret")
+	cfg, err := ParseBatchConfig([]byte(`variables:
+  consul: consul.internal:8500
+discover:
+  - name: api
+    consul: {address: "{{consul}}", service: api, token: "{{env.TAPR_TEST_CONSUL_TOKEN}}"}
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got ConsulDiscovery
+	err = cfg.ExpandDiscovery(func(d Discovery) ([]string, error) {
+		got = *d.Consul
+		return []string{"10.0.0.5:80"}, nil
+	})
+	if err != nil {
+		t.Fatalf("ExpandDiscovery() error = %v", err)
+	}
+	if got.Address != "consul.internal:8500" || got.Token != "s3cret" {
+		t.Errorf("consul = %+v, want rendered address and token", got)
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/cron"
+)
+
+// maxMaintenanceDuration bounds recurring windows, which are matched by
+// looking back over their duration minute by minute.
+const maxMaintenanceDuration = 7 * 24 * time.Hour
+
+// MaintenanceWindow is a planned period during which failures are still
+// recorded but don't alert or fail the run. A window either recurs on a
+// cron schedule for a duration, or covers a fixed start and end time.
+//
+//	maintenance:
+//	  - name: nightly deploy
+//	    schedule: "0 2 * * *"    # Every day at 02:00
+//	    duration: 30m
+//	    timezone: Europe/Berlin
+//	    endpoints: [api, "auth-*"]
+//	  - name: database migration
+//	    start: 2025-03-01T22:00:00Z
+//	    end: 2025-03-02T01:00:00Z
+type MaintenanceWindow struct {
+	Name      string        `yaml:"name"`
+	Schedule  string        `yaml:"schedule,omitempty"`  // Cron expression starting the window
+	Duration  time.Duration `yaml:"duration,omitempty"`  // Length of a scheduled window
+	Start     time.Time     `yaml:"start,omitempty"`     // Start of a one-off window
+	End       time.Time     `yaml:"end,omitempty"`       // End of a one-off window
+	Timezone  string        `yaml:"timezone,omitempty"`  // Location the schedule is read in (default: local time)
+	Endpoints []string      `yaml:"endpoints,omitempty"` // Endpoint or monitor names, globs allowed (default: all)
+
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// Active reports whether the window covers the endpoint or monitor called
+// name at now.
+func (w *MaintenanceWindow) Active(name string, now time.Time) bool {
+	if !w.covers(name) {
+		return false
+	}
+	if w.Schedule == "" {
+		return !now.Before(w.Start) && now.Before(w.End)
+	}
+	if w.location != nil {
+		now = now.In(w.location)
+	}
+	return w.schedule.Within(now, w.Duration)
+}
+
+// covers reports whether the window applies to name.
+func (w *MaintenanceWindow) covers(name string) bool {
+	if len(w.Endpoints) == 0 {
+		return true
+	}
+	for _, pattern := range w.Endpoints {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Label names the window in messages.
+func (w *MaintenanceWindow) Label() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	if w.Schedule != "" {
+		return w.Schedule
+	}
+	return w.Start.Format(time.RFC3339)
+}
+
+// prepareMaintenance validates maintenance windows and parses their
+// schedules.
+func prepareMaintenance(windows []MaintenanceWindow) error {
+	for i := range windows {
+		w := &windows[i]
+		label := fmt.Sprintf("maintenance window %d", i+1)
+		if w.Name != "" {
+			label = fmt.Sprintf("maintenance window '%s'", w.Name)
+		}
+
+		w.location = time.Local
+		if w.Timezone != "" {
+			location, err := time.LoadLocation(w.Timezone)
+			if err != nil {
+				return fmt.Errorf("%s: invalid timezone '%s'", label, w.Timezone)
+			}
+			w.location = location
+		}
+
+		for _, pattern := range w.Endpoints {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid endpoint pattern '%s'", label, pattern)
+			}
+		}
+
+		switch {
+		case w.Schedule != "" && (!w.Start.IsZero() || !w.End.IsZero()):
+			return fmt.Errorf("%s: use either schedule and duration, or start and end", label)
+		case w.Schedule != "":
+			schedule, err := cron.Parse(w.Schedule)
+			if err != nil {
+				return fmt.Errorf("%s: %w", label, err)
+			}
+			w.schedule = schedule
+			if w.Duration <= 0 {
+				return fmt.Errorf("%s: a scheduled window needs a duration", label)
+			}
+			if w.Duration > maxMaintenanceDuration {
+				return fmt.Errorf("%s: duration must be at most 7 days", label)
+			}
+		case w.Start.IsZero() || w.End.IsZero():
+			return fmt.Errorf("%s: needs a schedule and duration, or a start and end", label)
+		case !w.End.After(w.Start):
+			return fmt.Errorf("%s: end must be after start", label)
+		case w.Duration != 0:
+			return fmt.Errorf("%s: duration only applies to scheduled windows", label)
+		}
+	}
+	return nil
+}
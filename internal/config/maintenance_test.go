@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindow_Active(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.yml")
+	content := `endpoints:
+  - name: api
+    url: https://api.example.com
+maintenance:
+  - name: nightly deploy
+    schedule: "0 2 * * *"
+    duration: 30m
+    timezone: Europe/Berlin
+    endpoints: [api, "auth-*"]
+  - start: 2025-03-01T22:00:00Z
+    end: 2025-03-02T01:00:00Z
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadBatchConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+	nightly, migration := &cfg.Maintenance[0], &cfg.Maintenance[1]
+
+	// 02:10 in Berlin (CET, UTC+1) on a day outside the one-off window
+	berlin := time.Date(2025, time.March, 5, 1, 10, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		window *MaintenanceWindow
+		target string
+		at     time.Time
+		want   bool
+	}{
+		{"scheduled", nightly, "api", berlin, true},
+		{"scheduled glob", nightly, "auth-eu", berlin, true},
+		{"other endpoint", nightly, "search", berlin, false},
+		{"after duration", nightly, "api", berlin.Add(20 * time.Minute), false},
+		{"schedule in UTC would differ", nightly, "api", berlin.Add(time.Hour), false},
+		{"one-off start", migration, "search", time.Date(2025, time.March, 1, 22, 0, 0, 0, time.UTC), true},
+		{"one-off end", migration, "search", time.Date(2025, time.March, 2, 1, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Active(tt.target, tt.at); got != tt.want {
+				t.Errorf("Active(%s, %s) = %v, want %v", tt.target, tt.at.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+
+	if nightly.Label() != "nightly deploy" || migration.Label() != "2025-03-01T22:00:00Z" {
+		t.Errorf("Label() = %q, %q", nightly.Label(), migration.Label())
+	}
+}
+
+func TestPrepareMaintenance_Errors(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		window  MaintenanceWindow
+		wantErr string
+	}{
+		{"nothing", MaintenanceWindow{Name: "x"}, "maintenance window 'x': needs a schedule and duration"},
+		{"no duration", MaintenanceWindow{Schedule: "0 2 * * *"}, "maintenance window 1: a scheduled window needs a duration"},
+		{"long duration", MaintenanceWindow{Schedule: "0 2 * * *", Duration: 8 * 24 * time.Hour}, "at most 7 days"},
+		{"bad schedule", MaintenanceWindow{Schedule: "0 25 * * *", Duration: time.Hour}, "invalid hour '25'"},
+		{"bad timezone", MaintenanceWindow{Schedule: "0 2 * * *", Duration: time.Hour, Timezone: "Mars/Olympus"}, "invalid timezone"},
+		{"bad pattern", MaintenanceWindow{Schedule: "0 2 * * *", Duration: time.Hour, Endpoints: []string{"[api"}}, "invalid endpoint pattern"},
+		{"both kinds", MaintenanceWindow{Schedule: "0 2 * * *", Duration: time.Hour, Start: start}, "use either"},
+		{"end before start", MaintenanceWindow{Start: start, End: start.Add(-time.Hour)}, "end must be after start"},
+		{"one-off duration", MaintenanceWindow{Start: start, End: start.Add(time.Hour), Duration: time.Hour}, "duration only applies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := prepareMaintenance([]MaintenanceWindow{tt.window})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("prepareMaintenance() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseQueryParams converts a slice of "key=value" strings into query values.
+// Keys may repeat; each occurrence adds another value.
+//
+// Example:
+//
+//	params, err := config.ParseQueryParams([]string{"q=hello world", "page=2"})
+func ParseQueryParams(paramStrings []string) (url.Values, error) {
+	params := make(url.Values)
+
+	for _, paramStr := range paramStrings {
+		parts := strings.SplitN(paramStr, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid param format: '%s' (expected 'key=value')", paramStr)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		if key == "" {
+			return nil, fmt.Errorf("empty param key in: '%s'", paramStr)
+		}
+
+		params.Add(key, parts[1])
+	}
+
+	return params, nil
+}
+
+// AppendQueryParams URL-encodes params and appends them to rawURL, keeping
+// any query string that is already present.
+//
+// Example:
+//
+//	u, err := config.AppendQueryParams("https://api.example.com/search?v=1",
+//	    url.Values{"q": {"a&b"}})
+//	// u == "https://api.example.com/search?v=1&q=a%26b"
+func AppendQueryParams(rawURL string, params url.Values) (string, error) {
+	if len(params) == 0 {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	encoded := params.Encode()
+	if u.RawQuery == "" {
+		u.RawQuery = encoded
+	} else {
+		u.RawQuery += "&" + encoded
+	}
+
+	return u.String(), nil
+}
+
+// RequestURL returns the endpoint URL with its params appended.
+func (e Endpoint) RequestURL() (string, error) {
+	params := make(url.Values, len(e.Params))
+	for key, value := range e.Params {
+		params.Set(key, value)
+	}
+	return AppendQueryParams(e.URL, params)
+}
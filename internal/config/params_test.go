@@ -0,0 +1,115 @@
+package config
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseQueryParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    url.Values
+		wantErr bool
+	}{
+		{
+			name:  "single param",
+			input: []string{"page=2"},
+			want:  url.Values{"page": {"2"}},
+		},
+		{
+			name:  "repeated key",
+			input: []string{"tag=a", "tag=b"},
+			want:  url.Values{"tag": {"a", "b"}},
+		},
+		{
+			name:  "equals in value",
+			input: []string{"filter=a=b"},
+			want:  url.Values{"filter": {"a=b"}},
+		},
+		{
+			name:  "empty value",
+			input: []string{"debug="},
+			want:  url.Values{"debug": {""}},
+		},
+		{
+			name:    "missing equals",
+			input:   []string{"page"},
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			input:   []string{"=value"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQueryParams(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQueryParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Encode() != tt.want.Encode() {
+				t.Errorf("ParseQueryParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendQueryParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawURL string
+		params url.Values
+		want   string
+	}{
+		{
+			name:   "no params",
+			rawURL: "https://example.com/search",
+			params: nil,
+			want:   "https://example.com/search",
+		},
+		{
+			name:   "encodes special characters",
+			rawURL: "https://example.com/search",
+			params: url.Values{"q": {"hello world & more"}},
+			want:   "https://example.com/search?q=hello+world+%26+more",
+		},
+		{
+			name:   "keeps existing query",
+			rawURL: "https://example.com/search?v=1",
+			params: url.Values{"page": {"2"}},
+			want:   "https://example.com/search?v=1&page=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AppendQueryParams(tt.rawURL, tt.params)
+			if err != nil {
+				t.Fatalf("AppendQueryParams() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("AppendQueryParams() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointRequestURL(t *testing.T) {
+	endpoint := Endpoint{
+		URL:    "https://example.com/users?active=true",
+		Params: map[string]string{"name": "Jane Doe", "sort": "-created"},
+	}
+
+	got, err := endpoint.RequestURL()
+	if err != nil {
+		t.Fatalf("RequestURL() error = %v", err)
+	}
+
+	want := "https://example.com/users?active=true&name=Jane+Doe&sort=-created"
+	if got != want {
+		t.Errorf("RequestURL() = %s, want %s", got, want)
+	}
+}
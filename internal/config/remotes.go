@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalRemote is the name of the machine running tapr when results from
+// several vantage points are compared.
+const LocalRemote = "local"
+
+// Remote is another machine a batch runs on with `tapr batch --remotes`, so
+// endpoints are checked from several regions or networks.
+type Remote struct {
+	Name     string   `yaml:"name,omitempty"`     // Region or vantage point shown in reports
+	SSH      string   `yaml:"ssh,omitempty"`      // SSH destination ([user@]host or an ~/.ssh/config alias)
+	Port     int      `yaml:"port,omitempty"`     // SSH port (0 = ssh's default)
+	Identity string   `yaml:"identity,omitempty"` // Private key file (ssh -i)
+	Tapr     string   `yaml:"tapr,omitempty"`     // Path of tapr on the remote host (default "tapr")
+	Options  []string `yaml:"options,omitempty"`  // Extra ssh -o options ("StrictHostKeyChecking=no")
+}
+
+// validateRemotes fills in remote defaults and checks that every remote has
+// a unique name and a destination.
+func validateRemotes(remotes []Remote) error {
+	seen := make(map[string]bool, len(remotes))
+	for i := range remotes {
+		remote := &remotes[i]
+		if remote.Name == "" {
+			remote.Name = remote.SSH
+		}
+		switch {
+		case remote.SSH == "":
+			return fmt.Errorf("remote '%s' has no ssh destination", remote.Name)
+		case remote.Name == LocalRemote:
+			return fmt.Errorf("remote name '%s' is reserved for this machine", LocalRemote)
+		case seen[remote.Name]:
+			return fmt.Errorf("duplicate remote name '%s'", remote.Name)
+		case remote.Port < 0 || remote.Port > 65535:
+			return fmt.Errorf("remote '%s' has an invalid port %d", remote.Name, remote.Port)
+		}
+		seen[remote.Name] = true
+		if remote.Tapr == "" {
+			remote.Tapr = "tapr"
+		}
+	}
+	return nil
+}
+
+// mergeRemotes appends remotes to base, replacing remotes of the same name
+// in place.
+func mergeRemotes(base, remotes []Remote) []Remote {
+	for _, remote := range remotes {
+		replaced := false
+		for i := range base {
+			if remote.Name != "" && base[i].Name == remote.Name {
+				base[i] = remote
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, remote)
+		}
+	}
+	return base
+}
+
+// Standalone returns the loaded config as a single YAML document that a
+// remote tapr can run without access to this machine's files: includes are
+// merged, bodies are inlined and endpoints are grouped back into their
+// stages. Remotes are left out. Discover entries that haven't been expanded
+// are kept, so the remote discovers instances itself. Schema files
+// (expect_schema) are referenced by absolute path and must exist on the
+// remote host too.
+func (c *BatchConfig) Standalone() ([]byte, error) {
+	standalone := *c
+	standalone.Include = nil
+	standalone.Remotes = nil
+	standalone.SnapshotIgnore = nil // Already applied to every endpoint
+	standalone.Endpoints = nil
+	standalone.Stages = nil
+	standalone.Discover = nil
+
+	// Stages are rebuilt by index, as a stage may only hold discover entries
+	stages := make(map[int]*Stage)
+	var order []int
+	stageOf := func(name string, index int) *Stage {
+		if stage, ok := stages[index]; ok {
+			return stage
+		}
+		stages[index] = &Stage{Name: name}
+		order = append(order, index)
+		return stages[index]
+	}
+
+	for _, endpoint := range c.Endpoints {
+		if endpoint.Stage == "" {
+			standalone.Endpoints = append(standalone.Endpoints, endpoint)
+			continue
+		}
+		stage := stageOf(endpoint.Stage, endpoint.StageIndex)
+		stage.Endpoints = append(stage.Endpoints, endpoint)
+	}
+	for _, d := range c.Discover {
+		if d.Stage == "" {
+			standalone.Discover = append(standalone.Discover, d)
+			continue
+		}
+		stage := stageOf(d.Stage, d.StageIndex)
+		stage.Discover = append(stage.Discover, d)
+	}
+
+	sort.Ints(order)
+	for _, index := range order {
+		standalone.Stages = append(standalone.Stages, *stages[index])
+	}
+
+	return yaml.Marshal(&standalone)
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadBatchConfig_Remotes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"regions.yml": `remotes:
+  - name: eu-west
+    ssh: probe@eu.example.com
+  - name: us-east
+    ssh: probe@us.example.com
+`,
+		"main.yml": `include: [regions.yml]
+endpoints:
+  - url: https://api.example.com
+remotes:
+  - name: us-east
+    ssh: probe@us2.example.com
+    port: 2222
+  - ssh: ap-probe
+    tapr: /opt/tapr/bin/tapr
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := LoadBatchConfig(filepath.Join(dir, "main.yml"))
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+	want := []Remote{
+		{Name: "eu-west", SSH: "probe@eu.example.com", Tapr: "tapr"},
+		{Name: "us-east", SSH: "probe@us2.example.com", Port: 2222, Tapr: "tapr"},
+		{Name: "ap-probe", SSH: "ap-probe", Tapr: "/opt/tapr/bin/tapr"},
+	}
+	if !reflect.DeepEqual(cfg.Remotes, want) {
+		t.Errorf("Remotes = %+v, want %+v", cfg.Remotes, want)
+	}
+}
+
+func TestLoadBatchConfig_RemoteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		remotes string
+		wantErr string
+	}{
+		{"no destination", "  - name: eu\n", "has no ssh destination"},
+		{"duplicate", "  - name: eu\n    ssh: a\n  - name: eu\n    ssh: b\n", "duplicate remote name 'eu'"},
+		{"reserved", "  - name: local\n    ssh: a\n", "reserved"},
+		{"port", "  - ssh: a\n    port: 70000\n", "invalid port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "endpoints:\n  - url: https://api.example.com\nremotes:\n" + tt.remotes
+			path := filepath.Join(t.TempDir(), "batch.yml")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadBatchConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBatchConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBatchConfig_Standalone(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"body.json": `{"name": "ada"}`,
+		"main.yml": `timeout: 3s
+snapshot_ignore: [$.id]
+stages:
+  - name: setup
+    endpoints:
+      - name: create
+        url: https://api.example.com/users
+        body: "@body.json"
+  - name: check
+    endpoints:
+      - name: list
+        url: https://api.example.com/users
+        depends_on: [create]
+remotes:
+  - ssh: probe@eu.example.com
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := LoadBatchConfig(filepath.Join(dir, "main.yml"))
+	if err != nil {
+		t.Fatalf("LoadBatchConfig() error = %v", err)
+	}
+
+	data, err := cfg.Standalone()
+	if err != nil {
+		t.Fatalf("Standalone() error = %v", err)
+	}
+	if strings.Contains(string(data), "remotes") || strings.Contains(string(data), "@body.json") {
+		t.Errorf("Standalone() kept remotes or a body file reference:\n%s", data)
+	}
+
+	// The standalone config loads to the same endpoints from anywhere
+	path := filepath.Join(t.TempDir(), "standalone.yml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBatchConfig(path)
+	if err != nil {
+		t.Fatalf("LoadBatchConfig(standalone) error = %v\n%s", err, data)
+	}
+	if loaded.Timeout != cfg.Timeout || len(loaded.Endpoints) != len(cfg.Endpoints) {
+		t.Fatalf("standalone config = %+v, want %+v", loaded, cfg)
+	}
+	for i, endpoint := range loaded.Endpoints {
+		original := cfg.Endpoints[i]
+		if endpoint.Name != original.Name || endpoint.Stage != original.Stage || endpoint.Body != original.Body ||
+			!reflect.DeepEqual(endpoint.SnapshotIgnore, original.SnapshotIgnore) || !reflect.DeepEqual(endpoint.DependsOn, original.DependsOn) {
+			t.Errorf("endpoint %d = %+v, want %+v", i, endpoint, original)
+		}
+	}
+}
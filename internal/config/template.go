@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// templateVar matches {{name}} placeholders (whitespace inside braces is allowed).
+var templateVar = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.]*)\s*\}\}`)
+
+// RenderTemplate replaces {{name}} placeholders in s with values from vars.
+// Placeholders of the form {{env.NAME}} are read from the environment.
+// Returns an error naming the first undefined variable.
+//
+// Example:
+//
+//	url, err := config.RenderTemplate("https://{{host}}/users", map[string]string{
+//	    "host": "api.example.com",
+//	})
+func RenderTemplate(s string, vars map[string]string) (string, error) {
+	var missing string
+
+	rendered := templateVar.ReplaceAllStringFunc(s, func(match string) string {
+		name := templateVar.FindStringSubmatch(match)[1]
+
+		if strings.HasPrefix(name, "env.") {
+			if value, ok := os.LookupEnv(strings.TrimPrefix(name, "env.")); ok {
+				return value
+			}
+		} else if value, ok := vars[name]; ok {
+			return value
+		}
+
+		if missing == "" {
+			missing = name
+		}
+		return match
+	})
+
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable '%s'", missing)
+	}
+	return rendered, nil
+}
+
+// Render returns a copy of the endpoint with all templates in its URL,
+// headers, query params, body, save_body path and expected redirect
+// expanded using vars.
+func (e Endpoint) Render(vars map[string]string) (Endpoint, error) {
+	rendered := e
+
+	var err error
+	if rendered.URL, err = RenderTemplate(e.URL, vars); err != nil {
+		return e, fmt.Errorf("template error in url: %w", err)
+	}
+	if rendered.Body, err = RenderTemplate(e.Body, vars); err != nil {
+		return e, fmt.Errorf("template error in body: %w", err)
+	}
+	if rendered.SaveBody, err = RenderTemplate(e.SaveBody, vars); err != nil {
+		return e, fmt.Errorf("template error in save_body: %w", err)
+	}
+	if rendered.ExpectRedirect, err = RenderTemplate(e.ExpectRedirect, vars); err != nil {
+		return e, fmt.Errorf("template error in expect_redirect_to: %w", err)
+	}
+
+	if len(e.Headers) > 0 {
+		rendered.Headers = make(map[string]string, len(e.Headers))
+		for key, value := range e.Headers {
+			if rendered.Headers[key], err = RenderTemplate(value, vars); err != nil {
+				return e, fmt.Errorf("template error in header %s: %w", key, err)
+			}
+		}
+	}
+
+	if len(e.Params) > 0 {
+		rendered.Params = make(map[string]string, len(e.Params))
+		for key, value := range e.Params {
+			if rendered.Params[key], err = RenderTemplate(value, vars); err != nil {
+				return e, fmt.Errorf("template error in param %s: %w", key, err)
+			}
+		}
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import "testing"
+
+func TestRenderTemplate(t *testing.T) {
+	t.Setenv("TAPR_TEST_HOST", "env.example.com")
+
+	vars := map[string]string{
+		"host":  "api.example.com",
+		"token": "abc123",
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"no placeholders", "https://example.com", "https://example.com", false},
+		{"single variable", "https://{{host}}/health", "https://api.example.com/health", false},
+		{"whitespace in braces", "Bearer {{ token }}", "Bearer abc123", false},
+		{"multiple variables", "{{host}}/{{token}}", "api.example.com/abc123", false},
+		{"environment variable", "https://{{env.TAPR_TEST_HOST}}", "https://env.example.com", false},
+		{"undefined variable", "https://{{missing}}/", "", true},
+		{"undefined environment variable", "{{env.TAPR_TEST_MISSING}}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.input, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpoint_Render(t *testing.T) {
+	endpoint := Endpoint{
+		Name:     "profile",
+		URL:      "https://{{host}}/me",
+		Headers:  map[string]string{"Authorization": "Bearer {{token}}"},
+		Body:     `{"token": "{{token}}"}`,
+		Params:   map[string]string{"host": "{{host}}"},
+		SaveBody: "out/{{host}}.json",
+	}
+
+	rendered, err := endpoint.Render(map[string]string{
+		"host":  "api.example.com",
+		"token": "abc123",
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if rendered.URL != "https://api.example.com/me" {
+		t.Errorf("URL = %s, want https://api.example.com/me", rendered.URL)
+	}
+	if rendered.Headers["Authorization"] != "Bearer abc123" {
+		t.Errorf("Authorization = %s, want 'Bearer abc123'", rendered.Headers["Authorization"])
+	}
+	if rendered.Body != `{"token": "abc123"}` {
+		t.Errorf("Body = %s", rendered.Body)
+	}
+	if rendered.Params["host"] != "api.example.com" {
+		t.Errorf("Params[host] = %s, want api.example.com", rendered.Params["host"])
+	}
+	if rendered.SaveBody != "out/api.example.com.json" {
+		t.Errorf("SaveBody = %s, want out/api.example.com.json", rendered.SaveBody)
+	}
+
+	// Original endpoint must be untouched
+	if endpoint.Headers["Authorization"] != "Bearer {{token}}" {
+		t.Error("Render() modified the original headers")
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []Endpoint
+		wantErr   bool
+	}{
+		{
+			name: "valid chain",
+			endpoints: []Endpoint{
+				{Name: "login"},
+				{Name: "profile", DependsOn: []string{"login"}},
+				{Name: "orders", DependsOn: []string{"login", "profile"}},
+			},
+		},
+		{
+			name: "unknown dependency",
+			endpoints: []Endpoint{
+				{Name: "profile", DependsOn: []string{"login"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ambiguous dependency",
+			endpoints: []Endpoint{
+				{Name: "login"},
+				{Name: "login"},
+				{Name: "profile", DependsOn: []string{"login"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cycle",
+			endpoints: []Endpoint{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "self dependency",
+			endpoints: []Endpoint{
+				{Name: "a", DependsOn: []string{"a"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependencies(tt.endpoints)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDependencies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadURLList reads a list of watch targets, one URL (or @alias) per line.
+// Blank lines and lines starting with '#' are skipped.
+//
+// Example file:
+//
+//	# Public endpoints
+//	https://api.example.com/health
+//	https://auth.example.com/health
+//	@prod-health
+func LoadURLList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("URL file not found: %s", path)
+		}
+		return nil, fmt.Errorf("failed to read URL file: %w", err)
+	}
+	defer file.Close()
+
+	var urls []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL file: %w", err)
+	}
+
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs in %s", path)
+	}
+	return urls, nil
+}
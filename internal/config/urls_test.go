@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadURLList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "urls and aliases",
+			content: "https://a.example.com\n@prod\n",
+			want:    []string{"https://a.example.com", "@prod"},
+		},
+		{
+			name:    "comments and blank lines",
+			content: "# services\n\n  https://a.example.com  \n# https://b.example.com\n",
+			want:    []string{"https://a.example.com"},
+		},
+		{
+			name:    "empty",
+			content: "# nothing here\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "urls.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadURLList(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadURLList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadURLList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadURLList_Missing(t *testing.T) {
+	if _, err := LoadURLList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadURLList() on a missing file: want error")
+	}
+}
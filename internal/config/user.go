@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Color modes accepted by UserConfig.Color.
+const (
+	ColorAuto   = "auto"   // Color only when writing to a terminal
+	ColorAlways = "always" // Always color
+	ColorNever  = "never"  // Never color
+)
+
+// UserConfig holds personal defaults read from the user config file.
+// Command-line flags always take precedence.
+//
+// Example file (~/.config/tapr/config.yml):
+//
+//	timeout: 5s
+//	output: pretty
+//	color: auto
+//	fast_threshold: 100ms
+//	slow_threshold: 300ms
+//	latency_colors:
+//	  fast: cyan
+//	headers:
+//	  User-Agent: tapr (team-payments)
+//	aliases:
+//	  prod-health:
+//	    url: https://api.example.com/health
+//	    headers:
+//	      Authorization: Bearer {{env.PROD_TOKEN}}
+type UserConfig struct {
+	Timeout time.Duration    `yaml:"timeout"` // Default request timeout
+	Output  string           `yaml:"output"`  // Default output format
+	Color   string           `yaml:"color"`   // auto, always or never
+	Headers Headers          `yaml:"headers"` // Headers sent with every request
+	Aliases map[string]Alias `yaml:"aliases"` // Named endpoints
+
+	FastThreshold time.Duration     `yaml:"fast_threshold"` // Latencies below this are fast
+	SlowThreshold time.Duration     `yaml:"slow_threshold"` // Latencies above this are slow
+	LatencyColors map[string]string `yaml:"latency_colors"` // Color names for fast, medium and slow latencies
+}
+
+// Alias is a named endpoint defined in the user config.
+type Alias struct {
+	URL     string  `yaml:"url"`
+	Method  string  `yaml:"method"`
+	Headers Headers `yaml:"headers"`
+}
+
+// UserConfigPath returns the user config file location: $TAPR_CONFIG if
+// set, otherwise $XDG_CONFIG_HOME/tapr/config.yml (~/.config/tapr/config.yml),
+// falling back to ~/.taprrc when only that file exists.
+func UserConfigPath() string {
+	if path := os.Getenv("TAPR_CONFIG"); path != "" {
+		return path
+	}
+
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	home, err := os.UserHomeDir()
+	if configDir == "" && err == nil {
+		configDir = filepath.Join(home, ".config")
+	}
+
+	path := filepath.Join(configDir, "tapr", "config.yml")
+	if _, err := os.Stat(path); err == nil || home == "" {
+		return path
+	}
+
+	legacy := filepath.Join(home, ".taprrc")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return path
+}
+
+// LoadUserConfig reads the user config file at path. A missing file is not
+// an error and yields an empty config.
+func LoadUserConfig(path string) (*UserConfig, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &UserConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user config: %w", err)
+	}
+
+	var config UserConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse user config %s: %w", path, err)
+	}
+
+	switch config.Color {
+	case "", ColorAuto, ColorAlways, ColorNever:
+	default:
+		return nil, fmt.Errorf("user config %s: color must be auto, always or never", path)
+	}
+
+	if config.FastThreshold < 0 || config.SlowThreshold < 0 {
+		return nil, fmt.Errorf("user config %s: latency thresholds must not be negative", path)
+	}
+	if config.FastThreshold > 0 && config.SlowThreshold > 0 && config.FastThreshold > config.SlowThreshold {
+		return nil, fmt.Errorf("user config %s: fast_threshold must not exceed slow_threshold", path)
+	}
+	for level := range config.LatencyColors {
+		switch level {
+		case "fast", "medium", "slow":
+		default:
+			return nil, fmt.Errorf("user config %s: unknown latency level '%s' in latency_colors (want fast, medium or slow)", path, level)
+		}
+	}
+
+	for name, alias := range config.Aliases {
+		if alias.URL == "" {
+			return nil, fmt.Errorf("user config %s: alias '%s' has no url", path, name)
+		}
+	}
+
+	return &config, nil
+}
+
+// IsAlias reports whether arg refers to an alias (@name) rather than a URL.
+func IsAlias(arg string) bool {
+	return strings.HasPrefix(arg, "@")
+}
+
+// ResolveAlias looks up an @name argument in the configured aliases and
+// returns the alias with {{env.NAME}} templates in its URL and headers
+// expanded.
+func (c *UserConfig) ResolveAlias(arg string) (Alias, error) {
+	name := strings.TrimPrefix(arg, "@")
+
+	alias, ok := c.Aliases[name]
+	if !ok {
+		return Alias{}, fmt.Errorf("unknown alias '%s' (define it under aliases: in %s)", name, UserConfigPath())
+	}
+
+	resolved := alias
+	var err error
+	if resolved.URL, err = RenderTemplate(alias.URL, nil); err != nil {
+		return Alias{}, fmt.Errorf("alias '%s': template error in url: %w", name, err)
+	}
+
+	if len(alias.Headers) > 0 {
+		resolved.Headers = make(Headers, len(alias.Headers))
+		for key, value := range alias.Headers {
+			if resolved.Headers[key], err = RenderTemplate(value, nil); err != nil {
+				return Alias{}, fmt.Errorf("alias '%s': template error in header %s: %w", name, key, err)
+			}
+		}
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadUserConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	content := `timeout: 5s
+output: json
+color: never
+fast_threshold: 100ms
+slow_threshold: 1s
+latency_colors:
+  slow: magenta
+headers:
+  User-Agent: tapr-test
+aliases:
+  prod:
+    url: https://api.example.com/health
+    method: HEAD
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadUserConfig(path)
+	if err != nil {
+		t.Fatalf("LoadUserConfig() error = %v", err)
+	}
+
+	if cfg.Timeout != 5*time.Second || cfg.Output != "json" || cfg.Color != ColorNever {
+		t.Errorf("LoadUserConfig() = %+v, want timeout 5s, output json, color never", cfg)
+	}
+	if cfg.FastThreshold != 100*time.Millisecond || cfg.SlowThreshold != time.Second || cfg.LatencyColors["slow"] != "magenta" {
+		t.Errorf("LoadUserConfig() = %+v, want thresholds 100ms and 1s, slow latencies magenta", cfg)
+	}
+	if cfg.Headers["User-Agent"] != "tapr-test" {
+		t.Errorf("Headers[User-Agent] = %s, want tapr-test", cfg.Headers["User-Agent"])
+	}
+	if cfg.Aliases["prod"].Method != "HEAD" {
+		t.Errorf("Aliases[prod].Method = %s, want HEAD", cfg.Aliases["prod"].Method)
+	}
+}
+
+func TestLoadUserConfigMissingFile(t *testing.T) {
+	cfg, err := LoadUserConfig(filepath.Join(t.TempDir(), "missing.yml"))
+	if err != nil || cfg == nil {
+		t.Errorf("LoadUserConfig() = %v, %v; want empty config and no error", cfg, err)
+	}
+}
+
+func TestLoadUserConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid color", "color: rainbow\n", "color must be"},
+		{"alias without url", "aliases:\n  prod:\n    method: GET\n", "has no url"},
+		{"fast above slow", "fast_threshold: 1s\nslow_threshold: 500ms\n", "must not exceed"},
+		{"unknown latency level", "latency_colors:\n  blazing: cyan\n", "unknown latency level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadUserConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadUserConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("TAPR_CONFIG", "")
+
+	want := filepath.Join(home, ".config", "tapr", "config.yml")
+	if got := UserConfigPath(); got != want {
+		t.Errorf("UserConfigPath() = %s, want %s", got, want)
+	}
+
+	// Legacy ~/.taprrc is used when it is the only file present
+	legacy := filepath.Join(home, ".taprrc")
+	if err := os.WriteFile(legacy, []byte("timeout: 1s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := UserConfigPath(); got != legacy {
+		t.Errorf("UserConfigPath() = %s, want %s", got, legacy)
+	}
+
+	t.Setenv("TAPR_CONFIG", "/etc/tapr.yml")
+	if got := UserConfigPath(); got != "/etc/tapr.yml" {
+		t.Errorf("UserConfigPath() = %s, want $TAPR_CONFIG", got)
+	}
+}
+
+func TestResolveAlias(t *testing.T) {
+	t.Setenv("TAPR_TEST_TOKEN", "secret")
+
+	cfg := &UserConfig{
+		Aliases: map[string]Alias{
+			"prod": {
+				URL:     "https://api.example.com/health",
+				Method:  "HEAD",
+				Headers: Headers{"Authorization": "Bearer {{env.TAPR_TEST_TOKEN}}"},
+			},
+			"broken": {URL: "https://{{env.TAPR_TEST_MISSING}}/"},
+		},
+	}
+
+	alias, err := cfg.ResolveAlias("@prod")
+	if err != nil {
+		t.Fatalf("ResolveAlias() error = %v", err)
+	}
+	if alias.URL != "https://api.example.com/health" || alias.Method != "HEAD" {
+		t.Errorf("ResolveAlias() = %+v", alias)
+	}
+	if alias.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("Headers[Authorization] = %s, want 'Bearer secret'", alias.Headers["Authorization"])
+	}
+	if cfg.Aliases["prod"].Headers["Authorization"] != "Bearer {{env.TAPR_TEST_TOKEN}}" {
+		t.Error("ResolveAlias() modified the configured headers")
+	}
+
+	if _, err := cfg.ResolveAlias("@missing"); err == nil {
+		t.Error("ResolveAlias() should fail for an unknown alias")
+	}
+	if _, err := cfg.ResolveAlias("@broken"); err == nil {
+		t.Error("ResolveAlias() should fail for an undefined environment variable")
+	}
+}
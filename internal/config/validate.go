@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unknownField matches yaml.v3's error for a key with no matching field.
+var unknownField = regexp.MustCompile(`^line (\d+): field (\S+) not found in type config\.(\w+)$`)
+
+// configTypes describes the config types a batch file decodes into, for
+// unknown key errors.
+var configTypes = map[string]struct {
+	description string
+	typ         reflect.Type
+}{
+	"BatchConfig": {"batch config", reflect.TypeOf(BatchConfig{})},
+	"Stage":       {"stage", reflect.TypeOf(Stage{})},
+	"Endpoint":    {"endpoint", reflect.TypeOf(Endpoint{})},
+}
+
+// describeYAMLError rewrites yaml.v3 decoding errors to be easier to act
+// on: unknown keys name the section they appear in and suggest the closest
+// known key. Each problem is listed on its own line.
+func describeYAMLError(err error) error {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+
+	problems := make([]string, 0, len(typeErr.Errors))
+	for _, problem := range typeErr.Errors {
+		match := unknownField.FindStringSubmatch(problem)
+		configType, known := configTypes[safeIndex(match, 3)]
+		if !known {
+			problems = append(problems, problem)
+			continue
+		}
+
+		message := fmt.Sprintf("line %s: unknown field '%s' in %s", match[1], match[2], configType.description)
+		if suggestion := closestField(match[2], configType.typ); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+		problems = append(problems, message)
+	}
+	return errors.New(strings.Join(problems, "\n  "))
+}
+
+// safeIndex returns match[i], or "" if there is no such submatch.
+func safeIndex(match []string, i int) string {
+	if i < len(match) {
+		return match[i]
+	}
+	return ""
+}
+
+// closestField returns the YAML key of typ closest to key, if any is
+// within a couple of edits.
+func closestField(key string, typ reflect.Type) string {
+	best, bestDistance := "", 3
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if distance := editDistance(strings.ToLower(key), name); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// setSources records where each endpoint and discover entry of config is
+// defined in path, using the parsed document root.
+func setSources(config *BatchConfig, root *yaml.Node, path string) {
+	record := func(endpoints []Endpoint, seq *yaml.Node) {
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			return
+		}
+		for i := range endpoints {
+			if i < len(seq.Content) {
+				endpoints[i].Source = fmt.Sprintf("%s:%d", path, seq.Content[i].Line)
+			}
+		}
+	}
+
+	recordDiscover := func(discover []Discovery, seq *yaml.Node) {
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			return
+		}
+		for i := range discover {
+			if i < len(seq.Content) {
+				discover[i].Source = fmt.Sprintf("%s:%d", path, seq.Content[i].Line)
+			}
+		}
+	}
+
+	record(config.Endpoints, mappingValue(root, "endpoints"))
+	recordDiscover(config.Discover, mappingValue(root, "discover"))
+	if stages := mappingValue(root, "stages"); stages != nil && stages.Kind == yaml.SequenceNode {
+		for i := range config.Stages {
+			if i < len(stages.Content) {
+				record(config.Stages[i].Endpoints, mappingValue(stages.Content[i], "endpoints"))
+				recordDiscover(config.Stages[i].Discover, mappingValue(stages.Content[i], "discover"))
+			}
+		}
+	}
+}
+
+// mappingValue returns the value for key in a YAML mapping (or a document
+// holding one), or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// validateURL checks that an endpoint URL is an absolute http or https URL.
+// URLs with {{templates}} are only known at run time and are not checked.
+func validateURL(raw string) error {
+	if strings.Contains(raw, "{{") {
+		return nil
+	}
+
+	if !strings.Contains(raw, "://") {
+		return fmt.Errorf("missing scheme (did you mean 'https://%s'?)", raw)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return errors.Unwrap(err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme '%s' (expected http or https)", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
+
+// validateNames checks that no two endpoints share a name.
+func validateNames(endpoints []Endpoint) error {
+	seen := make(map[string]Endpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint.Name == "" {
+			continue
+		}
+		if first, exists := seen[endpoint.Name]; exists {
+			return fmt.Errorf("duplicate endpoint name '%s' (defined at %s and %s)", endpoint.Name, first.Source, endpoint.Source)
+		}
+		seen[endpoint.Name] = endpoint
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadBatchConfig_Strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr []string
+	}{
+		{
+			"unknown keys with suggestions",
+			"concurency: 5\nendpoints:\n  - name: a\n    urll: https://a.example.com\n",
+			[]string{
+				"line 1: unknown field 'concurency' in batch config (did you mean 'concurrency'?)",
+				"line 4: unknown field 'urll' in endpoint (did you mean 'url'?)",
+			},
+		},
+		{
+			"unknown key without suggestion",
+			"endpoints:\n  - name: a\n    url: https://a.example.com\n    flavor: vanilla\n",
+			[]string{"line 4: unknown field 'flavor' in endpoint\n"},
+		},
+		{
+			"unknown stage key",
+			"stages:\n  - nam: infra\n    endpoints:\n      - url: https://a.example.com\n",
+			[]string{"line 2: unknown field 'nam' in stage (did you mean 'name'?)"},
+		},
+		{
+			"type error",
+			"endpoints:\n  - url: https://a.example.com\n    expected_status: ok\n",
+			[]string{"line 3: cannot unmarshal"},
+		},
+		{
+			"malformed URL with line",
+			"endpoints:\n  - name: a\n    url: https://a.example.com\n  - name: b\n    url: b.example.com\n",
+			[]string{"batch.yml:4: endpoint 'b' has invalid URL 'b.example.com': missing scheme (did you mean 'https://b.example.com'?)"},
+		},
+		{
+			"duplicate names",
+			"endpoints:\n  - name: a\n    url: https://a.example.com\n  - name: a\n    url: https://b.example.com\n",
+			[]string{"duplicate endpoint name 'a'", "batch.yml:2 and", "batch.yml:4)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "batch.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadBatchConfig(path)
+			if err == nil {
+				t.Fatal("LoadBatchConfig() error = nil, want an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error()+"\n", want) {
+					t.Errorf("LoadBatchConfig() error = %q, want containing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"https://api.example.com/health", ""},
+		{"http://localhost:8080", ""},
+		{"{{base}}/health", ""},
+		{"api.example.com", "missing scheme"},
+		{"localhost:8080/health", "missing scheme"},
+		{"ftp://files.example.com", "unsupported scheme 'ftp'"},
+		{"https:///health", "missing host"},
+		{"https://api.example.com/%zz", "invalid URL escape"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateURL(tt.url)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateURL() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateURL() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"url", "url", 0},
+		{"urll", "url", 1},
+		{"timout", "timeout", 1},
+		{"concurency", "concurrency", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
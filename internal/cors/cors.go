@@ -0,0 +1,230 @@
+// Package cors evaluates CORS preflight responses the way browsers do, to
+// tell whether a page on one origin may make a given request to another.
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Results of a Check.
+const (
+	Pass = "pass"
+	Fail = "fail" // The browser blocks the request
+	Warn = "warn" // Allowed, but likely to cause trouble
+)
+
+// safelistedMethods never need to be listed in Access-Control-Allow-Methods.
+var safelistedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// safelistedHeaders can be set without a preflight. Content-Type is left
+// out: it is only safelisted for form and plain text bodies, and most API
+// calls send JSON.
+var safelistedHeaders = []string{"accept", "accept-language", "content-language"}
+
+// defaultMaxAge is how long browsers cache a preflight without
+// Access-Control-Max-Age.
+const defaultMaxAge = 5 * time.Second
+
+// Request describes the request a page would make from another origin.
+type Request struct {
+	Origin      string   // Origin of the page, e.g. "https://app.example.com"
+	Method      string   // Method of the request
+	Headers     []string // Names of the headers the page sets
+	Credentials bool     // Whether cookies or HTTP auth are sent along
+}
+
+// customHeaders returns the headers of r that aren't safelisted, lower-cased,
+// sorted and without duplicates, as browsers list them in the preflight.
+func (r Request) customHeaders() []string {
+	var names []string
+	for _, name := range r.Headers {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(safelistedHeaders, name) && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NeedsPreflight reports whether browsers send a preflight before r: it
+// uses a method other than GET, HEAD or POST, or sets a header that isn't
+// safelisted.
+func (r Request) NeedsPreflight() bool {
+	return !slices.Contains(safelistedMethods, r.Method) || len(r.customHeaders()) > 0
+}
+
+// PreflightHeaders returns the headers of the OPTIONS request browsers
+// send to ask whether r is allowed.
+func (r Request) PreflightHeaders() map[string]string {
+	headers := map[string]string{
+		"Origin":                        r.Origin,
+		"Access-Control-Request-Method": r.Method,
+	}
+	if custom := r.customHeaders(); len(custom) > 0 {
+		headers["Access-Control-Request-Headers"] = strings.Join(custom, ",")
+	}
+	return headers
+}
+
+// Check is the outcome of one rule a preflight response is held to.
+type Check struct {
+	Name   string // What was checked, e.g. "Allow-Origin"
+	Result string // Pass, Fail or Warn
+	Detail string
+}
+
+// Allowed reports whether no check failed.
+func Allowed(checks []Check) bool {
+	for _, check := range checks {
+		if check.Result == Fail {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate checks a preflight response with the given status and headers
+// against r, in the order browsers do.
+func Evaluate(r Request, status int, header http.Header) []Check {
+	checks := []Check{checkStatus(status), checkOrigin(r, header)}
+	if r.Credentials {
+		checks = append(checks, checkCredentials(header))
+	}
+	checks = append(checks, checkMethod(r, header))
+	if len(r.customHeaders()) > 0 {
+		checks = append(checks, checkHeaders(r, header))
+	}
+	if vary := checkVary(r, header); vary != nil {
+		checks = append(checks, *vary)
+	}
+	return append(checks, checkMaxAge(header))
+}
+
+func checkStatus(status int) Check {
+	check := Check{Name: "Status", Result: Pass, Detail: fmt.Sprintf("%d %s", status, http.StatusText(status))}
+	if status < 200 || status > 299 {
+		check.Result = Fail
+		check.Detail += " (a preflight must get a 2xx response)"
+	}
+	return check
+}
+
+func checkOrigin(r Request, header http.Header) Check {
+	check := Check{Name: "Allow-Origin", Result: Fail}
+	values := header.Values("Access-Control-Allow-Origin")
+	switch {
+	case len(values) == 0:
+		check.Detail = "missing"
+	case len(values) > 1 || strings.Contains(values[0], ","):
+		check.Detail = fmt.Sprintf("%q lists several origins, only one is allowed", strings.Join(values, ", "))
+	case values[0] == "*" && r.Credentials:
+		check.Detail = "* is not allowed with credentials, the origin must be echoed"
+	case values[0] == "*":
+		check.Result, check.Detail = Pass, "* (any origin)"
+	case values[0] == r.Origin:
+		check.Result, check.Detail = Pass, values[0]
+	default:
+		check.Detail = fmt.Sprintf("%s doesn't match %s", values[0], r.Origin)
+	}
+	return check
+}
+
+func checkCredentials(header http.Header) Check {
+	value := header.Get("Access-Control-Allow-Credentials")
+	if value == "true" {
+		return Check{Name: "Allow-Credentials", Result: Pass, Detail: value}
+	}
+	if value == "" {
+		value = "missing"
+	}
+	return Check{Name: "Allow-Credentials", Result: Fail, Detail: value + " (must be true to send credentials)"}
+}
+
+func checkMethod(r Request, header http.Header) Check {
+	allowed := headerList(header, "Access-Control-Allow-Methods")
+	listed := describeList(allowed)
+	switch {
+	case slices.Contains(allowed, r.Method):
+		return Check{Name: "Allow-Methods", Result: Pass, Detail: r.Method + " allowed"}
+	case slices.Contains(allowed, "*") && !r.Credentials:
+		return Check{Name: "Allow-Methods", Result: Pass, Detail: r.Method + " allowed by *"}
+	case slices.Contains(safelistedMethods, r.Method):
+		return Check{Name: "Allow-Methods", Result: Pass, Detail: r.Method + " is always allowed"}
+	}
+	return Check{Name: "Allow-Methods", Result: Fail, Detail: fmt.Sprintf("%s not allowed (%s)", r.Method, listed)}
+}
+
+func checkHeaders(r Request, header http.Header) Check {
+	allowed := headerList(header, "Access-Control-Allow-Headers")
+	wildcard := slices.Contains(allowed, "*") && !r.Credentials
+
+	var missing []string
+	for _, name := range r.customHeaders() {
+		// A wildcard never covers Authorization
+		covered := wildcard && name != "authorization"
+		if !covered && !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, name) }) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return Check{Name: "Allow-Headers", Result: Fail,
+			Detail: fmt.Sprintf("%s not allowed (%s)", strings.Join(missing, ", "), describeList(allowed))}
+	}
+	return Check{Name: "Allow-Headers", Result: Pass, Detail: strings.Join(r.customHeaders(), ", ") + " allowed"}
+}
+
+// checkVary warns when the allowed origin depends on the request but the
+// response doesn't say so, letting caches hand it to other origins.
+func checkVary(r Request, header http.Header) *Check {
+	if header.Get("Access-Control-Allow-Origin") != r.Origin {
+		return nil
+	}
+	for _, name := range headerList(header, "Vary") {
+		if name == "*" || strings.EqualFold(name, "Origin") {
+			return nil
+		}
+	}
+	return &Check{Name: "Vary", Result: Warn,
+		Detail: "the origin is echoed without Vary: Origin, so caches may serve it to other origins"}
+}
+
+func checkMaxAge(header http.Header) Check {
+	value := header.Get("Access-Control-Max-Age")
+	if value == "" {
+		return Check{Name: "Max-Age", Result: Pass, Detail: fmt.Sprintf("not set (cached for %s)", defaultMaxAge)}
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return Check{Name: "Max-Age", Result: Warn, Detail: fmt.Sprintf("%q is not a number of seconds, ignored", value)}
+	}
+	return Check{Name: "Max-Age", Result: Pass, Detail: (time.Duration(seconds) * time.Second).String()}
+}
+
+// headerList splits the comma-separated values of a header, which may be
+// sent more than once.
+func headerList(header http.Header, name string) []string {
+	var values []string
+	for _, line := range header.Values(name) {
+		for _, value := range strings.Split(line, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// describeList formats allowed values for a failure message.
+func describeList(values []string) string {
+	if len(values) == 0 {
+		return "none listed"
+	}
+	return "allowed: " + strings.Join(values, ", ")
+}
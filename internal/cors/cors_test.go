@@ -0,0 +1,139 @@
+package cors
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPreflightHeaders(t *testing.T) {
+	r := Request{Origin: "https://app.example.com", Method: "PUT", Headers: []string{"X-Custom", "Accept", "content-type", "x-custom"}}
+	headers := r.PreflightHeaders()
+	if headers["Origin"] != "https://app.example.com" || headers["Access-Control-Request-Method"] != "PUT" {
+		t.Errorf("PreflightHeaders() = %v", headers)
+	}
+	if got := headers["Access-Control-Request-Headers"]; got != "content-type,x-custom" {
+		t.Errorf("Access-Control-Request-Headers = %q, want %q", got, "content-type,x-custom")
+	}
+}
+
+func TestNeedsPreflight(t *testing.T) {
+	tests := []struct {
+		r    Request
+		want bool
+	}{
+		{Request{Method: "GET"}, false},
+		{Request{Method: "POST", Headers: []string{"Accept-Language"}}, false},
+		{Request{Method: "POST", Headers: []string{"Content-Type"}}, true},
+		{Request{Method: "DELETE"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.r.NeedsPreflight(); got != tt.want {
+			t.Errorf("NeedsPreflight(%+v) = %v, want %v", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	origin := "https://app.example.com"
+	tests := []struct {
+		name   string
+		r      Request
+		status int
+		header http.Header
+		failed string // Name of the failing check, "" if allowed
+	}{
+		{
+			name:   "allowed",
+			r:      Request{Origin: origin, Method: "PUT", Headers: []string{"X-Custom"}},
+			status: 204,
+			header: http.Header{
+				"Access-Control-Allow-Origin":  {origin},
+				"Access-Control-Allow-Methods": {"GET, PUT"},
+				"Access-Control-Allow-Headers": {"X-Custom"},
+				"Vary":                         {"Origin"},
+			},
+		},
+		{
+			name:   "wildcards",
+			r:      Request{Origin: origin, Method: "PATCH", Headers: []string{"X-Custom"}},
+			status: 200,
+			header: http.Header{
+				"Access-Control-Allow-Origin":  {"*"},
+				"Access-Control-Allow-Methods": {"*"},
+				"Access-Control-Allow-Headers": {"*"},
+			},
+		},
+		{
+			name:   "error status",
+			r:      Request{Origin: origin, Method: "GET"},
+			status: 405,
+			header: http.Header{"Access-Control-Allow-Origin": {"*"}},
+			failed: "Status",
+		},
+		{
+			name:   "other origin",
+			r:      Request{Origin: origin, Method: "GET"},
+			status: 204,
+			header: http.Header{"Access-Control-Allow-Origin": {"https://admin.example.com"}},
+			failed: "Allow-Origin",
+		},
+		{
+			name:   "wildcard origin with credentials",
+			r:      Request{Origin: origin, Method: "GET", Credentials: true},
+			status: 204,
+			header: http.Header{"Access-Control-Allow-Origin": {"*"}, "Access-Control-Allow-Credentials": {"true"}},
+			failed: "Allow-Origin",
+		},
+		{
+			name:   "credentials not allowed",
+			r:      Request{Origin: origin, Method: "GET", Credentials: true},
+			status: 204,
+			header: http.Header{"Access-Control-Allow-Origin": {origin}, "Vary": {"Origin"}},
+			failed: "Allow-Credentials",
+		},
+		{
+			name:   "method not allowed",
+			r:      Request{Origin: origin, Method: "DELETE"},
+			status: 204,
+			header: http.Header{"Access-Control-Allow-Origin": {"*"}, "Access-Control-Allow-Methods": {"GET, POST"}},
+			failed: "Allow-Methods",
+		},
+		{
+			name:   "wildcard doesn't cover Authorization",
+			r:      Request{Origin: origin, Method: "GET", Headers: []string{"Authorization"}},
+			status: 204,
+			header: http.Header{"Access-Control-Allow-Origin": {"*"}, "Access-Control-Allow-Headers": {"*"}},
+			failed: "Allow-Headers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := Evaluate(tt.r, tt.status, tt.header)
+			var failed []string
+			for _, check := range checks {
+				if check.Result == Fail {
+					failed = append(failed, check.Name)
+				}
+			}
+			if got := strings.Join(failed, ","); got != tt.failed {
+				t.Errorf("failed checks = %q, want %q (%+v)", got, tt.failed, checks)
+			}
+			if Allowed(checks) != (tt.failed == "") {
+				t.Errorf("Allowed() = %v, want %v", Allowed(checks), tt.failed == "")
+			}
+		})
+	}
+}
+
+func TestEvaluateVary(t *testing.T) {
+	r := Request{Origin: "https://app.example.com", Method: "GET"}
+	checks := Evaluate(r, 204, http.Header{"Access-Control-Allow-Origin": {r.Origin}})
+	for _, check := range checks {
+		if check.Name == "Vary" && check.Result == Warn {
+			return
+		}
+	}
+	t.Errorf("Evaluate() = %+v, want a Vary warning", checks)
+}
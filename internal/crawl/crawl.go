@@ -0,0 +1,164 @@
+// Package crawl discovers the pages of a site for link checking: it
+// follows the links of HTML pages from a start URL, level by level, and
+// reads robots.txt rules and sitemaps.
+package crawl
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxPages limits how many URLs a crawl discovers unless
+// Crawler.MaxPages is set.
+const DefaultMaxPages = 100
+
+// Page is a fetched page.
+type Page struct {
+	URL         string // Final URL, after redirects
+	ContentType string
+	Body        []byte
+}
+
+// Fetcher fetches a page to look for links in.
+type Fetcher func(ctx context.Context, url string) (Page, error)
+
+// Link is a URL found by a crawl.
+type Link struct {
+	URL      string
+	Depth    int    // Links followed from the start page or a sitemap to get here
+	Referrer string // Page the link was found on ("" for the start page and seeds)
+	External bool   // On another host; reported but never followed
+}
+
+// Crawler discovers the pages and assets of a site.
+type Crawler struct {
+	Fetch       Fetcher
+	Depth       int     // Levels of links to follow (0 = only the start page and seeds)
+	MaxPages    int     // Most URLs to discover (0 = DefaultMaxPages)
+	Concurrency int     // Pages fetched at once (0 = 1)
+	External    bool    // Also report links to other hosts
+	Robots      *Robots // Skip paths it disallows (nil = allow all)
+}
+
+// Crawl discovers URLs starting from start and seeds (such as the pages of
+// a sitemap), both at depth 0. Only pages on the start host are fetched
+// and followed. Pages that can't be fetched are skipped, since checking
+// them is up to the caller. The start URL comes first; the others follow
+// in the order they were found. Robots rules apply to every page but the
+// start page.
+func (c *Crawler) Crawl(ctx context.Context, start string, seeds []string) ([]Link, error) {
+	startURL, err := url.Parse(start)
+	if err != nil {
+		return nil, err
+	}
+	maxPages := c.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var links []Link
+	seen := make(map[string]bool)
+	add := func(raw string, depth int, referrer string) {
+		link, err := url.Parse(raw)
+		if err != nil || len(links) >= maxPages {
+			return
+		}
+		link.Fragment, link.RawFragment = "", ""
+		key := link.String()
+		external := !strings.EqualFold(link.Host, startURL.Host)
+		if seen[key] || (external && !c.External) || (!external && !c.Robots.Allowed(link.RequestURI())) {
+			return
+		}
+		seen[key] = true
+		links = append(links, Link{URL: key, Depth: depth, Referrer: referrer, External: external})
+	}
+
+	// The start page is checked even if robots.txt disallows it
+	startURL.Fragment, startURL.RawFragment = "", ""
+	seen[startURL.String()] = true
+	links = append(links, Link{URL: startURL.String()})
+	for _, seed := range seeds {
+		add(seed, 0, "")
+	}
+
+	for depth := 0; depth < c.Depth; depth++ {
+		var pages []string
+		for _, link := range links {
+			if link.Depth == depth && !link.External && !isAsset(link.URL) {
+				pages = append(pages, link.URL)
+			}
+		}
+		if len(pages) == 0 {
+			break
+		}
+
+		// Fetch the level concurrently, then add links in page order so
+		// the result doesn't depend on timing
+		found := c.fetchLinks(ctx, startURL.Host, pages)
+		if err := ctx.Err(); err != nil {
+			return links, err
+		}
+		for i, page := range pages {
+			for _, link := range found[i] {
+				add(link, depth+1, page)
+			}
+		}
+	}
+	return links, nil
+}
+
+// fetchLinks fetches pages and returns the links of each HTML page on host.
+func (c *Crawler) fetchLinks(ctx context.Context, host string, pages []string) [][]string {
+	found := make([][]string, len(pages))
+	semaphore := make(chan struct{}, max(c.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, page := range pages {
+		wg.Add(1)
+		go func(i int, page string) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+
+			fetched, err := c.Fetch(ctx, page)
+			if err != nil || !isHTML(fetched) {
+				return
+			}
+			final, err := url.Parse(fetched.URL)
+			if err != nil || !strings.EqualFold(final.Host, host) {
+				return // Redirected off the site
+			}
+			found[i] = ExtractLinks(final, fetched.Body)
+		}(i, page)
+	}
+	wg.Wait()
+	return found
+}
+
+// assetExtensions mark URLs that are checked but not fetched for links.
+var assetExtensions = []string{
+	".css", ".js", ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".ico",
+	".woff", ".woff2", ".ttf", ".pdf", ".zip", ".mp4", ".mp3",
+}
+
+// isAsset reports whether a URL points to an image, script or other file
+// that can't link to pages.
+func isAsset(raw string) bool {
+	link, err := url.Parse(raw)
+	return err == nil && slices.Contains(assetExtensions, strings.ToLower(path.Ext(link.Path)))
+}
+
+// isHTML reports whether a page is an HTML document.
+func isHTML(page Page) bool {
+	if page.ContentType != "" {
+		return strings.Contains(strings.ToLower(page.ContentType), "html")
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(page.Body[:min(len(page.Body), 512)])), "<")
+}
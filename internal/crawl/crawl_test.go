@@ -0,0 +1,133 @@
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	page, _ := url.Parse("https://example.com/docs/intro")
+	body := []byte(`<html><head>
+		<link rel="stylesheet" href="/style.css">
+		<script src='app.js'></script>
+	</head><body>
+		<a href="guide">Guide</a>
+		<A HREF=/about#team>About</A>
+		<a href="/search?q=a&amp;page=2">Search</a>
+		<a href="#top">Top</a>
+		<a href="mailto:team@example.com">Mail</a>
+		<a href="https://other.example.org/">Other</a>
+		<a href="guide">Guide again</a>
+		<img alt="logo" src="/logo.png">
+	</body></html>`)
+
+	want := []string{
+		"https://example.com/style.css",
+		"https://example.com/docs/app.js",
+		"https://example.com/docs/guide",
+		"https://example.com/about",
+		"https://example.com/search?q=a&page=2",
+		"https://other.example.org/",
+		"https://example.com/logo.png",
+	}
+	if got := ExtractLinks(page, body); !slices.Equal(got, want) {
+		t.Errorf("ExtractLinks() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestExtractLinksBase(t *testing.T) {
+	page, _ := url.Parse("https://example.com/a/b")
+	body := []byte(`<base href="https://cdn.example.com/v2/"><a href="page">Page</a>`)
+	want := []string{"https://cdn.example.com/v2/page"}
+	if got := ExtractLinks(page, body); !slices.Equal(got, want) {
+		t.Errorf("ExtractLinks() = %v, want %v", got, want)
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	pages := map[string]string{
+		"/":         `<a href="/a">A</a> <a href="/b">B</a> <a href="https://elsewhere.example/">Out</a>`,
+		"/a":        `<a href="/a/deep">Deep</a> <a href="/">Home</a> <img src="/logo.png">`,
+		"/b":        `<a href="/private/x">Private</a>`,
+		"/a/deep":   `<a href="/too-deep">Too deep</a>`,
+		"/listed":   `<a href="/from-sitemap">More</a>`,
+		"/logo.png": ``,
+	}
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	fetch := func(ctx context.Context, target string) (Page, error) {
+		fetches.Add(1)
+		resp, err := http.Get(target)
+		if err != nil {
+			return Page{}, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return Page{URL: resp.Request.URL.String(), ContentType: resp.Header.Get("Content-Type"), Body: body}, err
+	}
+
+	crawler := &Crawler{
+		Fetch:       fetch,
+		Depth:       2,
+		Concurrency: 2,
+		External:    true,
+		Robots:      ParseRobots([]byte("User-agent: *\nDisallow: /private/"), "tapr"),
+	}
+	links, err := crawler.Crawl(context.Background(), server.URL+"/", []string{server.URL + "/listed"})
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	var got []string
+	for _, link := range links {
+		got = append(got, fmt.Sprintf("%s@%d<%s", trim(link.URL, server.URL), link.Depth, trim(link.Referrer, server.URL)))
+	}
+	want := []string{
+		"/@0<",
+		"/listed@0<",
+		"/a@1</",
+		"/b@1</",
+		"https://elsewhere.example/@1</",
+		"/from-sitemap@1</listed",
+		"/a/deep@2</a",
+		"/logo.png@2</a",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Crawl() =\n%v\nwant\n%v", got, want)
+	}
+
+	// Level 2 pages, assets and external links are never fetched
+	if fetches.Load() != 5 {
+		t.Errorf("fetched %d pages, want 5", fetches.Load())
+	}
+
+	crawler.MaxPages = 3
+	if links, _ := crawler.Crawl(context.Background(), server.URL+"/", nil); len(links) != 3 {
+		t.Errorf("Crawl() with MaxPages 3 found %d links", len(links))
+	}
+}
+
+// trim shortens URLs on the test server to their path.
+func trim(link, server string) string {
+	if len(link) >= len(server) && link[:len(server)] == server {
+		return link[len(server):]
+	}
+	return link
+}
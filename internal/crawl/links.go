@@ -0,0 +1,63 @@
+package crawl
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// linkPattern matches the href or src attribute of the tags that link to
+// pages and assets.
+var linkPattern = regexp.MustCompile(`(?is)<(a|area|link|img|script|iframe|source)\b[^>]*?\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// basePattern matches the href of a <base> tag.
+var basePattern = regexp.MustCompile(`(?is)<base\b[^>]*?\shref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// ExtractLinks returns the absolute http(s) URLs an HTML page links to,
+// resolved against page (or its <base href>), without fragments and
+// duplicates, in the order they appear.
+func ExtractLinks(page *url.URL, body []byte) []string {
+	base := page
+	if match := basePattern.FindSubmatch(body); match != nil {
+		if href, err := page.Parse(attribute(match[1:])); err == nil {
+			base = href
+		}
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	for _, match := range linkPattern.FindAllSubmatch(body, -1) {
+		link, ok := resolve(base, attribute(match[2:]))
+		if ok && !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// attribute returns the value of whichever quoting alternative matched.
+func attribute(groups [][]byte) string {
+	for _, group := range groups {
+		if len(group) > 0 {
+			return html.UnescapeString(strings.TrimSpace(string(group)))
+		}
+	}
+	return ""
+}
+
+// resolve makes href absolute. It reports false for links that aren't
+// http(s) pages, such as "mailto:" or "#top".
+func resolve(base *url.URL, href string) (string, bool) {
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+	link, err := base.Parse(href)
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+		return "", false
+	}
+	link.Fragment = ""
+	link.RawFragment = ""
+	return link.String(), true
+}
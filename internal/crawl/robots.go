@@ -0,0 +1,101 @@
+package crawl
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// Robots holds the rules of a robots.txt that apply to one user agent, and
+// the sitemaps it lists.
+type Robots struct {
+	Sitemaps []string
+	rules    []robotsRule
+}
+
+// robotsRule is an Allow or Disallow line.
+type robotsRule struct {
+	pattern string
+	match   *regexp.Regexp
+	allow   bool
+}
+
+// ParseRobots parses a robots.txt, keeping the rules of the group for
+// agent, or of the "*" group if no group names it.
+func ParseRobots(body []byte, agent string) *Robots {
+	robots := &Robots{}
+	groups := make(map[string][]robotsRule)
+	var agents []string // User agents of the group being read
+	inRules := false    // Whether the group being read has rules yet
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // "Disallow:" allows everything
+			}
+			rule := robotsRule{pattern: value, match: robotsPattern(value), allow: key == "allow"}
+			for _, name := range agents {
+				groups[name] = append(groups[name], rule)
+			}
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+	}
+
+	// The most specific group naming agent wins
+	agent = strings.ToLower(agent)
+	best := "*"
+	for name := range groups {
+		if name != "*" && strings.Contains(agent, name) && (best == "*" || len(name) > len(best)) {
+			best = name
+		}
+	}
+	robots.rules = groups[best]
+	return robots
+}
+
+// robotsPattern compiles a rule path, where "*" matches any characters and
+// a trailing "$" anchors the end.
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(path), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// Allowed reports whether path (with its query) may be fetched. The
+// longest matching rule decides; Allow wins a tie.
+func (r *Robots) Allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+	allowed, longest := true, -1
+	for _, rule := range r.rules {
+		if !rule.match.MatchString(path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed, longest = rule.allow, len(rule.pattern)
+		}
+	}
+	return allowed
+}
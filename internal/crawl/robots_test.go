@@ -0,0 +1,54 @@
+package crawl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseRobots(t *testing.T) {
+	body := []byte(`# Example
+User-agent: *
+Disallow: /admin/
+Disallow: /*.json$
+Allow: /admin/public
+
+User-agent: tapr
+User-agent: other-bot
+Disallow: /slow
+
+Sitemap: https://example.com/sitemap.xml
+`)
+
+	everyone := ParseRobots(body, "SomeBot/1.0")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/admin/users", false},
+		{"/admin/public/logo", true},
+		{"/data.json", false},
+		{"/data.json?x=1", true},
+		{"/slow", true},
+	}
+	for _, tt := range tests {
+		if got := everyone.Allowed(tt.path); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// A group naming the agent replaces the "*" group
+	tapr := ParseRobots(body, "tapr/1.2.0")
+	if tapr.Allowed("/slow") || !tapr.Allowed("/admin/users") {
+		t.Errorf("tapr rules: /slow allowed = %v, /admin/users allowed = %v", tapr.Allowed("/slow"), tapr.Allowed("/admin/users"))
+	}
+
+	if want := []string{"https://example.com/sitemap.xml"}; !slices.Equal(tapr.Sitemaps, want) {
+		t.Errorf("Sitemaps = %v, want %v", tapr.Sitemaps, want)
+	}
+
+	var none *Robots
+	if !none.Allowed("/admin/") {
+		t.Error("nil Robots should allow everything")
+	}
+}
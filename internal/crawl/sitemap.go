@@ -0,0 +1,37 @@
+package crawl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// sitemapXML is a sitemap (<urlset>) or a sitemap index (<sitemapindex>).
+type sitemapXML struct {
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// ParseSitemap returns the page URLs listed in a sitemap, and the nested
+// sitemaps listed in a sitemap index.
+func ParseSitemap(body []byte) (urls, sitemaps []string, err error) {
+	var parsed sitemapXML
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		return nil, nil, fmt.Errorf("invalid sitemap: %w", err)
+	}
+	for _, entry := range parsed.URLs {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			urls = append(urls, loc)
+		}
+	}
+	for _, entry := range parsed.Sitemaps {
+		if loc := strings.TrimSpace(entry.Loc); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+	return urls, sitemaps, nil
+}
@@ -0,0 +1,31 @@
+package crawl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSitemap(t *testing.T) {
+	urls, sitemaps, err := ParseSitemap([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc><lastmod>2024-01-01</lastmod></url>
+  <url><loc> https://example.com/about </loc></url>
+</urlset>`))
+	if err != nil {
+		t.Fatalf("ParseSitemap() error = %v", err)
+	}
+	if want := []string{"https://example.com/", "https://example.com/about"}; !slices.Equal(urls, want) || sitemaps != nil {
+		t.Errorf("ParseSitemap() = %v, %v, want %v, nil", urls, sitemaps, want)
+	}
+
+	urls, sitemaps, err = ParseSitemap([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-blog.xml</loc></sitemap>
+</sitemapindex>`))
+	if err != nil || urls != nil || !slices.Equal(sitemaps, []string{"https://example.com/sitemap-blog.xml"}) {
+		t.Errorf("ParseSitemap(index) = %v, %v, %v", urls, sitemaps, err)
+	}
+
+	if _, _, err := ParseSitemap([]byte("not xml <")); err == nil {
+		t.Error("ParseSitemap() of invalid XML should fail")
+	}
+}
@@ -0,0 +1,98 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "invalid minute '60'"},
+		{"* 24 * * *", "invalid hour '24'"},
+		{"* * 0 * *", "invalid day of month '0'"},
+		{"* * * foo *", "invalid month 'foo'"},
+		{"* * * * 8", "invalid day of week '8'"},
+		{"*/0 * * * *", "invalid step '0'"},
+		{"30-10 * * * *", "invalid range '30-10'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedule_Matches(t *testing.T) {
+	// Sunday, 2 March 2025
+	sunday := time.Date(2025, time.March, 2, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		at   time.Time
+		want bool
+	}{
+		{"* * * * *", sunday, true},
+		{"0 2 * * sun", sunday, true},
+		{"0 2 * * 7", sunday, true},
+		{"0 2 * * mon-fri", sunday, false},
+		{"0 2 * * 1-5", sunday.AddDate(0, 0, 1), true},
+		{"*/15 * * * *", sunday.Add(45 * time.Minute), true},
+		{"*/15 * * * *", sunday.Add(50 * time.Minute), false},
+		{"5/20 * * * *", sunday.Add(25 * time.Minute), true},
+		{"0,30 1-3 * * *", sunday.Add(30 * time.Minute), true},
+		{"0 2 1 * *", sunday, false},
+		{"0 2 * mar *", sunday, true},
+		{"0 2 * jan-feb *", sunday, false},
+		{"@daily", sunday.Add(-2 * time.Hour), true},
+		{"@weekly", sunday.Add(-2 * time.Hour), true},
+		{"@hourly", sunday.Add(time.Minute), false},
+		// Both day fields restricted: either matches
+		{"0 2 15 * sun", sunday, true},
+		{"0 2 2 * mon", sunday, true},
+		{"0 2 15 * mon", sunday, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := s.Matches(tt.at); got != tt.want {
+				t.Errorf("Parse(%q).Matches(%s) = %v, want %v", tt.expr, tt.at.Format(time.RFC1123), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Within(t *testing.T) {
+	s, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, time.March, 2, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{start.Add(-time.Second), false},
+		{start, true},
+		{start.Add(29*time.Minute + 59*time.Second), true},
+		{start.Add(30 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		if got := s.Within(tt.at, 30*time.Minute); got != tt.want {
+			t.Errorf("Within(%s, 30m) = %v, want %v", tt.at.Format("15:04:05"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// maxMonitorBody limits the size of a monitor sent to the control API.
+const maxMonitorBody = 1 << 20
+
+// AddMonitor validates a monitor and starts checking it. Monitors added at
+// runtime are not written back to the config file.
+func (d *Daemon) AddMonitor(monitor config.Monitor) error {
+	if err := d.cfg.PrepareMonitor(&monitor); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.monitors[monitor.Name]; exists {
+		return fmt.Errorf("monitor '%s' already exists", monitor.Name)
+	}
+	for _, dep := range monitor.DependsOn {
+		if _, exists := d.monitors[dep]; !exists {
+			return fmt.Errorf("monitor '%s' depends on unknown monitor '%s'", monitor.Name, dep)
+		}
+	}
+	d.add(monitor)
+	return nil
+}
+
+// RemoveMonitor stops checking a monitor and forgets its state. Its stored
+// history is kept. It reports whether the monitor existed.
+func (d *Daemon) RemoveMonitor(name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, exists := d.monitors[name]
+	if !exists {
+		return false
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+	delete(d.monitors, name)
+	delete(d.statuses, name)
+	delete(d.breakers, name)
+	delete(d.uptimes, name)
+	delete(d.health, name)
+	delete(d.latency, name)
+	return true
+}
+
+// SetPaused pauses or resumes the scheduled checks of a monitor. It reports
+// whether the monitor exists.
+func (d *Daemon) SetPaused(name string, paused bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status, exists := d.statuses[name]
+	if exists {
+		status.Paused = paused
+	}
+	return exists
+}
+
+// CheckNow checks a monitor right away, even while it is paused, and
+// returns its updated state.
+func (d *Daemon) CheckNow(name string) (MonitorStatus, bool) {
+	d.mu.RLock()
+	s, exists := d.monitors[name]
+	d.mu.RUnlock()
+	if !exists {
+		return MonitorStatus{}, false
+	}
+
+	d.runCheck(s.monitor)
+	return d.Status(name)
+}
+
+// Status returns the current state of one monitor.
+func (d *Daemon) Status(name string) (MonitorStatus, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	status, exists := d.statuses[name]
+	if !exists {
+		return MonitorStatus{}, false
+	}
+	snapshot := *status
+	d.addAvailability(&snapshot, d.uptimes[name], time.Now())
+	return snapshot, true
+}
+
+// handleMonitors lists the monitors (GET) or adds one (POST).
+func (d *Daemon) handleMonitors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"monitors": d.Statuses()})
+
+	case http.MethodPost:
+		if !d.authorize(w, r) {
+			return
+		}
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMonitorBody))
+		if err != nil {
+			writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+			return
+		}
+
+		// JSON is valid YAML, so both work and fields match the config file
+		var monitor config.Monitor
+		if err := yaml.Unmarshal(data, &monitor); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid monitor: " + err.Error()})
+			return
+		}
+		if err := d.AddMonitor(monitor); err != nil {
+			code := http.StatusBadRequest
+			if strings.Contains(err.Error(), "already exists") {
+				code = http.StatusConflict
+			}
+			writeJSON(w, code, map[string]string{"error": err.Error()})
+			return
+		}
+
+		status, _ := d.Status(monitor.Name)
+		writeJSON(w, http.StatusCreated, status)
+
+	default:
+		methodNotAllowed(w, "GET, POST")
+	}
+}
+
+// handleMonitor serves one monitor:
+//
+//	GET    /monitors/{name}          current state
+//	DELETE /monitors/{name}          remove it
+//	POST   /monitors/{name}/pause    pause scheduled checks
+//	POST   /monitors/{name}/resume   resume scheduled checks
+//	POST   /monitors/{name}/check    check it right away
+func (d *Daemon) handleMonitor(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/monitors/")
+	action := ""
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name, action = name[:i], name[i+1:]
+	}
+
+	if _, exists := d.Status(name); !exists {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("no monitor named '%s'", name)})
+		return
+	}
+
+	switch action {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			status, _ := d.Status(name)
+			writeJSON(w, http.StatusOK, status)
+		case http.MethodDelete:
+			if d.authorize(w, r) {
+				d.RemoveMonitor(name)
+				w.WriteHeader(http.StatusNoContent)
+			}
+		default:
+			methodNotAllowed(w, "GET, DELETE")
+		}
+
+	case "pause", "resume", "check":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, "POST")
+			return
+		}
+		if !d.authorize(w, r) {
+			return
+		}
+		if action == "check" {
+			status, _ := d.CheckNow(name)
+			writeJSON(w, http.StatusOK, status)
+			return
+		}
+		d.SetPaused(name, action == "pause")
+		status, _ := d.Status(name)
+		writeJSON(w, http.StatusOK, status)
+
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("unknown action '%s'", action)})
+	}
+}
+
+// authorize checks that a request may change the daemon. With an api_token
+// it must carry the token as a bearer token. Without one, only requests from
+// the local machine are allowed, and not from browsers (which send an
+// Origin header), so web pages can't drive the API.
+func (d *Daemon) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if d.cfg.APIToken != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(d.cfg.APIToken)) == 1 {
+			return true
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid API token"})
+		return false
+	}
+
+	if err := localRequest(r); err != nil {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error() + " (set api_token to allow it)"})
+		return false
+	}
+	return true
+}
+
+// localRequest returns an error unless r comes from a loopback address
+// without an Origin header.
+func localRequest(r *http.Request) error {
+	if r.Header.Get("Origin") != "" {
+		return errors.New("browser requests are not allowed")
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return errors.New("only local requests may change monitors")
+	}
+	return nil
+}
+
+// methodNotAllowed responds with 405 and the allowed methods.
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+}
@@ -0,0 +1,449 @@
+// Package daemon runs scheduled endpoint checks in the background, records
+// their results in the history store and serves the current state over a
+// local HTTP status API and status page. Monitors can be added, removed,
+// paused and checked at runtime through the control API.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/breaker"
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/maintenance"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// CheckFunc runs a single check of an endpoint.
+type CheckFunc func(endpoint config.Endpoint, timeout time.Duration) stats.BatchResult
+
+// ResultFunc is called after every check, e.g. to log it. A failure
+// excused by maintenance has result.Silenced set, and one while a dependency
+// is down has result.Upstream set.
+type ResultFunc func(monitor config.Monitor, result stats.BatchResult)
+
+// MonitorStatus is the current state of one monitor, as served by the API.
+type MonitorStatus struct {
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Interval    string    `json:"interval"`
+	Up          bool      `json:"up"`                 // After the failure and recovery thresholds of the state config
+	Flapping    bool      `json:"flapping,omitempty"` // Keeps alternating between up and down
+	LastCheck   time.Time `json:"last_check,omitempty"`
+	Status      int       `json:"status"`
+	LatencyMs   float64   `json:"latency_ms"`
+	BaselineMs  float64   `json:"baseline_latency_ms,omitempty"` // Learned usual latency
+	Anomalous   bool      `json:"latency_anomaly,omitempty"`     // Last check was much slower than the baseline
+	Anomalies   int       `json:"latency_anomalies,omitempty"`   // Checks much slower than the baseline
+	Message     string    `json:"message,omitempty"`
+	Checks      int       `json:"checks"`
+	Failures    int       `json:"failures"`
+	Uptime      float64   `json:"uptime_percent"`
+	Paused      bool      `json:"paused,omitempty"`           // Paused through the control API
+	Maintenance string    `json:"maintenance,omitempty"`      // Active maintenance window or silence
+	DependsOn   []string  `json:"depends_on,omitempty"`       // Monitors this one depends on
+	Upstream    string    `json:"upstream,omitempty"`         // Down dependency this monitor is degraded by
+	Circuit     string    `json:"circuit,omitempty"`          // "open" or "closed" with a circuit breaker
+	Downtime    float64   `json:"downtime_seconds,omitempty"` // Time down during outages that opened the circuit
+
+	// Availability over time since the daemon started
+	Availability  float64  `json:"availability_percent"`
+	Incidents     int      `json:"incidents"`
+	MTTR          float64  `json:"mttr_seconds"`
+	LongestOutage float64  `json:"longest_outage_seconds"`
+	ErrorBudget   *float64 `json:"error_budget_seconds,omitempty"` // Downtime left under the SLO; negative once breached
+}
+
+// Daemon schedules the monitors from a DaemonConfig.
+type Daemon struct {
+	cfg      *config.DaemonConfig
+	store    *history.Store
+	check    CheckFunc
+	onResult ResultFunc
+	silences *maintenance.Checker
+	started  time.Time
+
+	mu       sync.RWMutex
+	ctx      context.Context // Set by Run, to start monitors added later
+	wg       sync.WaitGroup  // Running monitors
+	monitors map[string]*scheduled
+	statuses map[string]*MonitorStatus
+	breakers map[string]*breaker.Breaker // Per monitor, with circuit_breaker
+	uptimes  map[string]*stats.Uptime
+	health   map[string]*stats.Hysteresis // Debounced up/down state per monitor
+	latency  map[string]*stats.AnomalyDetector
+}
+
+// scheduled is a monitor known to the daemon.
+type scheduled struct {
+	monitor config.Monitor
+	cancel  context.CancelFunc // Stops its checks once the daemon runs
+}
+
+// New creates a daemon that runs check for each monitor in cfg and appends
+// the results to store. onResult may be nil.
+func New(cfg *config.DaemonConfig, store *history.Store, check CheckFunc, onResult ResultFunc) *Daemon {
+	d := &Daemon{
+		cfg:      cfg,
+		store:    store,
+		check:    check,
+		onResult: onResult,
+		silences: maintenance.NewChecker(cfg.Maintenance, cfg.Silences),
+		monitors: make(map[string]*scheduled, len(cfg.Monitors)),
+		statuses: make(map[string]*MonitorStatus, len(cfg.Monitors)),
+		breakers: make(map[string]*breaker.Breaker),
+		uptimes:  make(map[string]*stats.Uptime, len(cfg.Monitors)),
+		health:   make(map[string]*stats.Hysteresis, len(cfg.Monitors)),
+		latency:  make(map[string]*stats.AnomalyDetector, len(cfg.Monitors)),
+	}
+	for _, monitor := range cfg.Monitors {
+		d.add(monitor)
+	}
+	return d
+}
+
+// add registers a monitor, starting it if the daemon is running. The caller
+// must hold d.mu (or be New).
+func (d *Daemon) add(monitor config.Monitor) {
+	d.monitors[monitor.Name] = &scheduled{monitor: monitor}
+	d.uptimes[monitor.Name] = &stats.Uptime{}
+	d.health[monitor.Name] = &stats.Hysteresis{
+		FailureThreshold:  d.cfg.State.FailureThreshold,
+		RecoveryThreshold: d.cfg.State.RecoveryThreshold,
+		FlapWindow:        d.cfg.State.FlapWindow,
+		FlapThreshold:     d.cfg.State.FlapThreshold,
+	}
+	d.latency[monitor.Name] = &stats.AnomalyDetector{Factor: d.cfg.State.AnomalyFactor}
+	d.statuses[monitor.Name] = &MonitorStatus{
+		Name:      monitor.Name,
+		URL:       monitor.URL,
+		Interval:  monitor.Interval.String(),
+		DependsOn: monitor.DependsOn,
+	}
+	if d.cfg.Breaker != nil {
+		d.breakers[monitor.Name] = breaker.New(d.cfg.Breaker.Failures, d.cfg.Breaker.MaxInterval)
+		d.statuses[monitor.Name].Circuit = "closed"
+	}
+	if d.ctx != nil {
+		d.start(d.monitors[monitor.Name])
+	}
+}
+
+// start runs a monitor's checks until the daemon stops or the monitor is
+// removed. The caller must hold d.mu.
+func (d *Daemon) start(s *scheduled) {
+	ctx, cancel := context.WithCancel(d.ctx)
+	s.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.runMonitor(ctx, s.monitor)
+	}()
+}
+
+// Run starts every monitor and the status API, and blocks until ctx is
+// cancelled or the API server fails.
+func (d *Daemon) Run(ctx context.Context) error {
+	d.started = time.Now()
+
+	listener, err := net.Listen("tcp", d.cfg.Listen)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: d.Handler(), ReadHeaderTimeout: 5 * time.Second}
+
+	d.mu.Lock()
+	d.ctx = ctx
+	for _, s := range d.monitors {
+		d.start(s)
+	}
+	d.mu.Unlock()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = nil
+	case err = <-serveErr:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx) // Waits for API requests, so no monitor starts after this
+	d.wg.Wait()
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// runMonitor checks one monitor immediately and then on every interval,
+// backing off while its circuit is open and skipping checks while paused.
+func (d *Daemon) runMonitor(ctx context.Context, monitor config.Monitor) {
+	ticker := time.NewTicker(monitor.Interval)
+	defer ticker.Stop()
+
+	for {
+		if !d.paused(monitor.Name) {
+			d.runCheck(monitor)
+		}
+		ticker.Reset(d.interval(monitor))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCheck performs a single check and records its result.
+func (d *Daemon) runCheck(monitor config.Monitor) {
+	result := d.check(monitor.Endpoint, d.cfg.Timeout)
+	now := time.Now()
+	latencyMs := float64(result.Result.Latency) / float64(time.Millisecond)
+	silenced := d.silences.Reason(monitor.Name, now)
+	if !result.Success {
+		result.Silenced = silenced
+	}
+
+	d.mu.Lock()
+	status := d.statuses[monitor.Name]
+	if status == nil {
+		// Removed while the check ran
+		d.mu.Unlock()
+		return
+	}
+	health := d.health[monitor.Name]
+	health.Observe(result.Success)
+	status.Up = !health.Down()
+	status.Flapping = health.Flapping()
+	status.LastCheck = now
+	status.Status = result.Result.StatusCode
+	status.LatencyMs = latencyMs
+	status.Anomalous = false
+	if result.Success {
+		detector := d.latency[monitor.Name]
+		anomaly, anomalous := detector.Observe(result.Result.Latency)
+		baseline := detector.Baseline()
+		if anomalous {
+			baseline = anomaly.Baseline // As it was before the slow check
+		}
+		status.Anomalous = anomalous
+		status.BaselineMs = float64(baseline) / float64(time.Millisecond)
+		status.Anomalies = detector.Anomalies()
+	}
+	status.Message = result.Message
+	status.Maintenance = silenced
+	status.Upstream = ""
+	if !status.Up {
+		status.Upstream = d.upstream(monitor)
+	}
+	if !result.Success {
+		result.Upstream = d.upstream(monitor)
+	}
+	status.Checks++
+	if !result.Success {
+		status.Failures++
+	}
+	status.Uptime = float64(status.Checks-status.Failures) / float64(status.Checks) * 100
+	if b := d.breakers[monitor.Name]; b != nil {
+		b.Observe(result.Success, now)
+		status.Circuit = "closed"
+		if b.Open() {
+			status.Circuit = "open"
+		}
+		status.Downtime = b.Downtime(now).Seconds()
+	}
+	uptime := d.uptimes[monitor.Name]
+	uptime.Observe(result.Success, now)
+	d.mu.Unlock()
+
+	if d.store != nil {
+		_ = d.store.Append(history.Record{
+			Time:      now,
+			Name:      monitor.Name,
+			URL:       monitor.URL,
+			Status:    result.Result.StatusCode,
+			LatencyMs: latencyMs,
+			Success:   result.Success,
+			Message:   result.Message,
+			Silenced:  result.Silenced != "",
+			Upstream:  result.Upstream,
+		})
+	}
+
+	if d.onResult != nil {
+		d.onResult(monitor, result)
+	}
+}
+
+// upstream returns the down dependency at the root of a monitor's failure,
+// or "" if all its dependencies are up. The caller must hold d.mu.
+func (d *Daemon) upstream(monitor config.Monitor) string {
+	for _, dep := range monitor.DependsOn {
+		status := d.statuses[dep]
+		if status == nil || status.Checks == 0 || status.Up {
+			continue
+		}
+		if status.Upstream != "" {
+			return status.Upstream
+		}
+		return dep
+	}
+	return ""
+}
+
+// paused reports whether a monitor's scheduled checks are paused.
+func (d *Daemon) paused(name string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	status := d.statuses[name]
+	return status != nil && status.Paused
+}
+
+// interval returns the time until the next check of monitor.
+func (d *Daemon) interval(monitor config.Monitor) time.Duration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if b := d.breakers[monitor.Name]; b != nil {
+		return b.Interval(monitor.Interval)
+	}
+	return monitor.Interval
+}
+
+// Statuses returns a snapshot of every monitor's state, sorted by name.
+func (d *Daemon) Statuses() []MonitorStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	now := time.Now()
+	statuses := make([]MonitorStatus, 0, len(d.statuses))
+	for name, status := range d.statuses {
+		snapshot := *status
+		d.addAvailability(&snapshot, d.uptimes[name], now)
+		statuses = append(statuses, snapshot)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// addAvailability fills in the time-based availability of a monitor.
+func (d *Daemon) addAvailability(status *MonitorStatus, uptime *stats.Uptime, now time.Time) {
+	status.Availability = uptime.Percent(now)
+	status.Incidents = len(uptime.Outages())
+	status.MTTR = uptime.MTTR().Seconds()
+	status.LongestOutage = uptime.LongestOutage(now).Seconds()
+	if d.cfg.SLO > 0 {
+		_, remaining := uptime.ErrorBudget(d.cfg.SLO, now)
+		seconds := remaining.Seconds()
+		status.ErrorBudget = &seconds
+	}
+}
+
+// Handler returns the status page and API:
+//
+//	GET /                                 HTML status page
+//	GET /healthz                          daemon liveness
+//	GET /status                           state of every monitor
+//	GET /history?name=api&since=1h&limit=100  stored results
+//
+// and the control API, see handleMonitors and handleMonitor.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", d.handlePage)
+	mux.HandleFunc("/healthz", d.handleHealth)
+	mux.HandleFunc("/status", d.handleStatus)
+	mux.HandleFunc("/history", d.handleHistory)
+	mux.HandleFunc("/monitors", d.handleMonitors)
+	mux.HandleFunc("/monitors/", d.handleMonitor)
+	return mux
+}
+
+// handleHealth reports that the daemon is running.
+func (d *Daemon) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":         "ok",
+		"uptime_seconds": int64(time.Since(d.started).Seconds()),
+	})
+}
+
+// handleStatus serves the state of every monitor. It responds with 503 if
+// any monitor is down (and not paused, in maintenance or degraded by a down
+// dependency) so it can be used directly as a health check.
+func (d *Daemon) handleStatus(w http.ResponseWriter, r *http.Request) {
+	statuses := d.Statuses()
+
+	code := http.StatusOK
+	for _, status := range statuses {
+		if status.Checks > 0 && !status.Up && !status.Paused && status.Maintenance == "" && status.Upstream == "" {
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	writeJSON(w, code, map[string]interface{}{"monitors": statuses})
+}
+
+// handleHistory serves stored results filtered by name, age and count.
+func (d *Daemon) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if d.store == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "history is disabled"})
+		return
+	}
+
+	query := history.Query{Name: r.URL.Query().Get("name")}
+
+	if since := r.URL.Query().Get("since"); since != "" {
+		age, err := time.ParseDuration(since)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid since: " + err.Error()})
+			return
+		}
+		query.Since = time.Now().Add(-age)
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+			return
+		}
+		query.Limit = n
+	}
+
+	records, err := d.store.Query(query)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if records == nil {
+		records = []history.Record{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"records": records})
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
@@ -0,0 +1,532 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/history"
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func newTestDaemon(t *testing.T, up map[string]bool) *Daemon {
+	t.Helper()
+
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.ndjson"))
+	if err != nil {
+		t.Fatalf("history.Open() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	cfg := &config.DaemonConfig{
+		Timeout: time.Second,
+		Monitors: []config.Monitor{
+			{Endpoint: config.Endpoint{Name: "api", URL: "https://api.example.com"}, Interval: time.Minute},
+			{Endpoint: config.Endpoint{Name: "auth", URL: "https://auth.example.com"}, Interval: time.Minute},
+		},
+	}
+
+	check := func(endpoint config.Endpoint, timeout time.Duration) stats.BatchResult {
+		status := 200
+		if !up[endpoint.Name] {
+			status = 500
+		}
+		return stats.BatchResult{
+			Name:    endpoint.Name,
+			Result:  request.Result{StatusCode: status, Latency: 50 * time.Millisecond},
+			Success: up[endpoint.Name],
+		}
+	}
+
+	return New(cfg, store, check, nil)
+}
+
+func TestDaemonStatus(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true, "auth": false})
+	for _, monitor := range d.cfg.Monitors {
+		d.runCheck(monitor)
+		d.runCheck(monitor)
+	}
+
+	statuses := d.Statuses()
+	if len(statuses) != 2 {
+		t.Fatalf("Statuses() returned %d monitors, want 2", len(statuses))
+	}
+	if !statuses[0].Up || statuses[0].Uptime != 100 || statuses[0].Checks != 2 {
+		t.Errorf("api status = %+v, want up with 100%% uptime after 2 checks", statuses[0])
+	}
+	if statuses[1].Up || statuses[1].Failures != 2 {
+		t.Errorf("auth status = %+v, want down with 2 failures", statuses[1])
+	}
+
+	recorder := httptest.NewRecorder()
+	d.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /status code = %d, want 503 when a monitor is down", recorder.Code)
+	}
+}
+
+func TestDaemonHistory(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true, "auth": true})
+	for _, monitor := range d.cfg.Monitors {
+		d.runCheck(monitor)
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		wantCode int
+		wantLen  int
+	}{
+		{"all", "/history", http.StatusOK, 2},
+		{"by name", "/history?name=api", http.StatusOK, 1},
+		{"since", "/history?since=1h", http.StatusOK, 2},
+		{"limit", "/history?limit=1", http.StatusOK, 1},
+		{"bad since", "/history?since=yesterday", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			d.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("GET %s code = %d, want %d", tt.target, recorder.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Records []history.Record `json:"records"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if len(body.Records) != tt.wantLen {
+				t.Errorf("GET %s returned %d records, want %d", tt.target, len(body.Records), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestDaemonCircuitBreaker(t *testing.T) {
+	base := newTestDaemon(t, map[string]bool{"api": true, "auth": false})
+	base.cfg.Breaker = &config.BreakerConfig{Failures: 2, MaxInterval: 3 * time.Minute}
+	d := New(base.cfg, base.store, base.check, nil)
+
+	api, auth := d.cfg.Monitors[0], d.cfg.Monitors[1]
+	wantIntervals := []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute}
+	for i, want := range wantIntervals {
+		d.runCheck(api)
+		d.runCheck(auth)
+		if got := d.interval(auth); got != want {
+			t.Errorf("check %d: auth interval = %v, want %v", i+1, got, want)
+		}
+	}
+
+	statuses := d.Statuses()
+	if statuses[0].Circuit != "closed" || d.interval(api) != time.Minute {
+		t.Errorf("api status = %+v, want closed circuit", statuses[0])
+	}
+	if statuses[1].Circuit != "open" {
+		t.Errorf("auth status = %+v, want open circuit", statuses[1])
+	}
+}
+
+func TestDaemonAvailability(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true, "auth": false})
+	d.cfg.SLO = 99.9
+	for _, monitor := range d.cfg.Monitors {
+		d.runCheck(monitor)
+	}
+
+	statuses := d.Statuses()
+	if statuses[0].Availability != 100 || statuses[0].Incidents != 0 {
+		t.Errorf("api status = %+v, want 100%% availability and no incidents", statuses[0])
+	}
+	if statuses[1].Incidents != 1 || statuses[1].Availability != 0 {
+		t.Errorf("auth status = %+v, want one ongoing incident", statuses[1])
+	}
+	if statuses[1].ErrorBudget == nil || *statuses[1].ErrorBudget > 0 {
+		t.Errorf("auth error budget = %v, want exhausted", statuses[1].ErrorBudget)
+	}
+}
+
+func TestDaemonStatusPage(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true, "auth": false})
+	d.cfg.Title = "Acme <status>"
+
+	recorder := httptest.NewRecorder()
+	d.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(recorder.Body.String(), "Waiting for the first checks") {
+		t.Errorf("page before any check should be pending:\n%s", recorder.Body.String())
+	}
+
+	for _, monitor := range d.cfg.Monitors {
+		d.runCheck(monitor)
+		d.runCheck(monitor)
+	}
+
+	recorder = httptest.NewRecorder()
+	d.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET / code = %d, want 200", recorder.Code)
+	}
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+	page := recorder.Body.String()
+	for _, want := range []string{
+		"<title>Acme &lt;status&gt;</title>",
+		"1 of 2 monitors down",
+		"https://auth.example.com",
+		"100.00%",
+		"0.00%",
+		`fill="#c62828"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	d.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("GET /missing code = %d, want 404", recorder.Code)
+	}
+}
+
+func TestChartBars(t *testing.T) {
+	now := time.Now()
+	var records []history.Record
+	for i := 0; i < pageChartPoints+10; i++ {
+		records = append(records, history.Record{Time: now, Status: 200, LatencyMs: float64(i + 1), Success: true})
+	}
+	records[len(records)-1].Success = false
+
+	bars := chartBars(records)
+	if len(bars) != pageChartPoints {
+		t.Fatalf("chartBars() returned %d bars, want %d", len(bars), pageChartPoints)
+	}
+	if bars[0].X != 1 {
+		t.Errorf("first bar X = %d, want 1", bars[0].X)
+	}
+	slowest := bars[len(bars)-2]
+	if slowest.Height != pageChartHeight || slowest.Y != 0 {
+		t.Errorf("slowest successful bar = %+v, want full height", slowest)
+	}
+	if failed := bars[len(bars)-1]; failed.Fill != "#c62828" || failed.Height != pageChartHeight {
+		t.Errorf("failed bar = %+v, want full height in red", failed)
+	}
+
+	// A few checks are right-aligned, newest last
+	if bars := chartBars(records[:3]); bars[2].X != (pageChartPoints-1)*(pageChartWidth/pageChartPoints)+1 {
+		t.Errorf("last of 3 bars X = %d, want at the right edge", bars[2].X)
+	}
+}
+
+// controlRequest sends a request to the daemon's handler from localhost.
+func controlRequest(d *Daemon, method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.RemoteAddr = "127.0.0.1:40000"
+	d.Handler().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestDaemonControlAPI(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true, "auth": false, "search": true})
+	d.cfg.Interval = time.Minute
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{"list", http.MethodGet, "/monitors", "", http.StatusOK, `"name": "auth"`},
+		{"add json", http.MethodPost, "/monitors", `{"name": "search", "url": "https://search.example.com", "interval": "30s"}`, http.StatusCreated, `"interval": "30s"`},
+		{"add duplicate", http.MethodPost, "/monitors", `{"name": "search", "url": "https://search.example.com"}`, http.StatusConflict, "already exists"},
+		{"add yaml", http.MethodPost, "/monitors", "name: docs\nurl: https://docs.example.com\n", http.StatusCreated, `"interval": "1m0s"`},
+		{"add invalid", http.MethodPost, "/monitors", `{"name": "broken"}`, http.StatusBadRequest, "has no URL"},
+		{"get", http.MethodGet, "/monitors/search", "", http.StatusOK, `"url": "https://search.example.com"`},
+		{"get missing", http.MethodGet, "/monitors/nope", "", http.StatusNotFound, "no monitor named 'nope'"},
+		{"check", http.MethodPost, "/monitors/search/check", "", http.StatusOK, `"checks": 1`},
+		{"check with GET", http.MethodGet, "/monitors/search/check", "", http.StatusMethodNotAllowed, "method not allowed"},
+		{"pause", http.MethodPost, "/monitors/auth/pause", "", http.StatusOK, `"paused": true`},
+		{"unknown action", http.MethodPost, "/monitors/auth/restart", "", http.StatusNotFound, "unknown action"},
+		{"delete", http.MethodDelete, "/monitors/docs", "", http.StatusNoContent, ""},
+		{"delete again", http.MethodDelete, "/monitors/docs", "", http.StatusNotFound, "no monitor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := controlRequest(d, tt.method, tt.target, tt.body)
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("%s %s code = %d, want %d: %s", tt.method, tt.target, recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+			if !strings.Contains(recorder.Body.String(), tt.wantBody) {
+				t.Errorf("%s %s body is missing %q:\n%s", tt.method, tt.target, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+
+	names := []string{}
+	for _, status := range d.Statuses() {
+		names = append(names, status.Name)
+	}
+	if strings.Join(names, ",") != "api,auth,search" {
+		t.Errorf("monitors after API calls = %v, want api,auth,search", names)
+	}
+
+	// A paused monitor is skipped by scheduled checks and doesn't fail /status
+	d.runCheck(d.monitors["api"].monitor)
+	if !d.paused("auth") {
+		t.Error("auth should be paused")
+	}
+	if recorder := controlRequest(d, http.MethodGet, "/status", ""); recorder.Code != http.StatusOK {
+		t.Errorf("GET /status code = %d, want 200 with the failing monitor paused", recorder.Code)
+	}
+	controlRequest(d, http.MethodPost, "/monitors/auth/resume", "")
+	if d.paused("auth") {
+		t.Error("auth should be resumed")
+	}
+}
+
+func TestDaemonControlAuthorization(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true})
+
+	tests := []struct {
+		name       string
+		token      string
+		remoteAddr string
+		header     http.Header
+		wantCode   int
+	}{
+		{"local", "", "127.0.0.1:40000", nil, http.StatusOK},
+		{"local IPv6", "", "[::1]:40000", nil, http.StatusOK},
+		{"remote", "", "10.0.0.7:40000", nil, http.StatusForbidden},
+		{"browser", "", "127.0.0.1:40000", http.Header{"Origin": {"https://evil.example"}}, http.StatusForbidden},
+		{"token", "s3cret", "10.0.0.7:40000", http.Header{"Authorization": {"Bearer s3cret"}}, http.StatusOK},
+		{"wrong token", "s3cret", "127.0.0.1:40000", http.Header{"Authorization": {"Bearer nope"}}, http.StatusUnauthorized},
+		{"missing token", "s3cret", "127.0.0.1:40000", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d.cfg.APIToken = tt.token
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/monitors/api/pause", nil)
+			request.RemoteAddr = tt.remoteAddr
+			for key, values := range tt.header {
+				request.Header[key] = values
+			}
+			d.Handler().ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+		})
+	}
+
+	// Reading state needs no authorization
+	recorder := httptest.NewRecorder()
+	d.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/monitors/api", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("GET /monitors/api from remote code = %d, want 200", recorder.Code)
+	}
+}
+
+func TestDaemonRunAddRemove(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true, "auth": true, "late": true})
+	d.cfg.Listen = "127.0.0.1:0"
+	d.cfg.Interval = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+
+	waitFor := func(what string, ok func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !ok() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor("initial checks", func() bool {
+		status, _ := d.Status("api")
+		return status.Checks == 1
+	})
+
+	// Monitors added while running are checked right away
+	if err := d.AddMonitor(config.Monitor{Endpoint: config.Endpoint{Name: "late", URL: "https://late.example.com"}}); err != nil {
+		t.Fatalf("AddMonitor() error = %v", err)
+	}
+	waitFor("added monitor", func() bool {
+		status, _ := d.Status("late")
+		return status.Checks == 1
+	})
+
+	if !d.RemoveMonitor("late") || d.RemoveMonitor("late") {
+		t.Error("RemoveMonitor() should remove the monitor once")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not stop")
+	}
+}
+
+func TestDaemonMaintenance(t *testing.T) {
+	base := newTestDaemon(t, map[string]bool{"api": true, "auth": false})
+	base.cfg.Maintenance = []config.MaintenanceWindow{{
+		Name:      "auth migration",
+		Start:     time.Now().Add(-time.Hour),
+		End:       time.Now().Add(time.Hour),
+		Endpoints: []string{"auth"},
+	}}
+
+	var silenced []string
+	d := New(base.cfg, base.store, base.check, func(monitor config.Monitor, result stats.BatchResult) {
+		silenced = append(silenced, result.Silenced)
+	})
+	for _, monitor := range d.cfg.Monitors {
+		d.runCheck(monitor)
+	}
+
+	if silenced[0] != "" || silenced[1] != "maintenance window 'auth migration'" {
+		t.Errorf("silenced results = %q, want only auth", silenced)
+	}
+	if recorder := controlRequest(d, http.MethodGet, "/status", ""); recorder.Code != http.StatusOK {
+		t.Errorf("GET /status code = %d, want 200 while the failing monitor is in maintenance", recorder.Code)
+	}
+	if page := controlRequest(d, http.MethodGet, "/", "").Body.String(); !strings.Contains(page, "Maintenance") {
+		t.Error("status page should show auth in maintenance")
+	}
+
+	records, err := d.store.Query(history.Query{Name: "auth"})
+	if err != nil || len(records) != 1 || !records[0].Silenced || records[0].Success {
+		t.Errorf("auth history = %+v, %v, want one failed record marked silenced", records, err)
+	}
+}
+
+func TestDaemonStateHysteresis(t *testing.T) {
+	up := map[string]bool{"api": true}
+	d := newTestDaemon(t, up)
+	d.cfg.State = config.StateConfig{FailureThreshold: 2, RecoveryThreshold: 2, FlapWindow: 10, FlapThreshold: 4}
+	d = New(d.cfg, d.store, d.check, nil)
+	api := d.cfg.Monitors[0]
+
+	steps := []struct {
+		up       bool
+		wantUp   bool
+		flapping bool
+	}{
+		{true, true, false},
+		{false, true, false}, // One failure isn't down yet
+		{false, false, false},
+		{true, false, false}, // One success doesn't recover
+		{true, true, false},
+		{false, true, false},
+		{true, true, true}, // Fourth change in the window
+	}
+	for i, step := range steps {
+		up["api"] = step.up
+		d.runCheck(api)
+
+		status, _ := d.Status("api")
+		if status.Up != step.wantUp || status.Flapping != step.flapping {
+			t.Errorf("check %d: up = %v, flapping = %v; want %v, %v", i+1, status.Up, status.Flapping, step.wantUp, step.flapping)
+		}
+	}
+
+	if page := controlRequest(d, http.MethodGet, "/", "").Body.String(); !strings.Contains(page, "1 of 2 monitors flapping") {
+		t.Error("status page should report the flapping monitor")
+	}
+}
+
+func TestDaemonDependencies(t *testing.T) {
+	up := map[string]bool{"api": false, "auth": false}
+	d := newTestDaemon(t, up)
+	d.cfg.Monitors[0].DependsOn = []string{"auth"}
+	d = New(d.cfg, d.store, d.check, nil)
+	d.cfg.Interval = time.Minute
+	api, auth := d.cfg.Monitors[0], d.cfg.Monitors[1]
+
+	// api failing on its own is down
+	d.runCheck(api)
+	if status, _ := d.Status("api"); status.Up || status.Upstream != "" {
+		t.Errorf("api = up %v, upstream %q; want down on its own", status.Up, status.Upstream)
+	}
+
+	// Once auth is down, api is degraded by it
+	d.runCheck(auth)
+	d.runCheck(api)
+	if status, _ := d.Status("api"); status.Upstream != "auth" {
+		t.Errorf("api upstream = %q, want auth", status.Upstream)
+	}
+	if page := controlRequest(d, http.MethodGet, "/", "").Body.String(); !strings.Contains(page, "1 of 2 monitors down, 1 degraded") {
+		t.Error("status page should count api as degraded, not down")
+	}
+
+	// A monitor added at runtime can only depend on known monitors
+	err := d.AddMonitor(config.Monitor{Endpoint: config.Endpoint{Name: "web", URL: "https://web.example.com", DependsOn: []string{"cdn"}}})
+	if err == nil || !strings.Contains(err.Error(), "unknown monitor 'cdn'") {
+		t.Errorf("AddMonitor() error = %v, want unknown monitor", err)
+	}
+
+	records, err := d.store.Query(history.Query{Name: "api"})
+	if err != nil || len(records) != 2 || records[0].Upstream != "" || records[1].Upstream != "auth" {
+		t.Errorf("api history = %+v, %v, want the second failure recorded under auth", records, err)
+	}
+}
+
+func TestDaemonLatencyAnomaly(t *testing.T) {
+	d := newTestDaemon(t, map[string]bool{"api": true})
+	latency := 50 * time.Millisecond
+	d.check = func(endpoint config.Endpoint, timeout time.Duration) stats.BatchResult {
+		return stats.BatchResult{Result: request.Result{StatusCode: 200, Latency: latency}, Success: true}
+	}
+	api := d.cfg.Monitors[0]
+
+	for i := 0; i < 15; i++ {
+		d.runCheck(api)
+	}
+	if status, _ := d.Status("api"); status.Anomalous || status.BaselineMs != 50 {
+		t.Fatalf("steady checks: anomalous = %v, baseline = %vms; want false, 50ms", status.Anomalous, status.BaselineMs)
+	}
+
+	latency = time.Second
+	d.runCheck(api)
+	status, _ := d.Status("api")
+	if !status.Anomalous || status.Anomalies != 1 {
+		t.Errorf("slow check: anomalous = %v, anomalies = %d; want true, 1", status.Anomalous, status.Anomalies)
+	}
+	if page := controlRequest(d, http.MethodGet, "/", "").Body.String(); !strings.Contains(page, "(usually 50ms)") {
+		t.Error("status page should show the usual latency of an anomalous check")
+	}
+
+	latency = 50 * time.Millisecond
+	d.runCheck(api)
+	if status, _ := d.Status("api"); status.Anomalous {
+		t.Error("anomaly should clear on the next normal check")
+	}
+}
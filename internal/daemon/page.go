@@ -0,0 +1,297 @@
+package daemon
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/history"
+)
+
+// Status page layout: the window of history it covers, the number of recent
+// checks in each latency chart and the chart size in SVG user units.
+const (
+	pageWindow      = 24 * time.Hour
+	pageChartPoints = 60
+	pageChartWidth  = 360
+	pageChartHeight = 40
+)
+
+// pageData is the data passed to pageTemplate.
+type pageData struct {
+	Title       string
+	Generated   string
+	Summary     string
+	Banner      string // "up", "down", "degraded", "flapping" or "pending", like the monitor states
+	Monitors    []pageMonitor
+	Window      string
+	Refresh     int // Seconds between automatic reloads
+	ChartWidth  int
+	ChartHeight int
+}
+
+// pageMonitor is one row of the status page.
+type pageMonitor struct {
+	Name      string
+	URL       string
+	State     string // "up", "down", "degraded", "flapping", "pending", "paused" or "maintenance"
+	DependsOn string // Monitors it depends on, comma-separated
+	LastCheck string
+	Latency   string
+	Message   string
+	Uptime    string
+	Bars      []pageBar
+}
+
+// pageBar is one check in a monitor's latency chart.
+type pageBar struct {
+	X, Y          int
+	Width, Height int
+	Fill          string
+	Title         string // Tooltip with the time, status and latency
+}
+
+// handlePage serves the HTML status page: the current state of every
+// monitor with its uptime and latency over the last day of history.
+func (d *Daemon) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var records []history.Record
+	if d.store != nil {
+		var err error
+		records, err = d.store.Query(history.Query{Since: time.Now().Add(-pageWindow)})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var b strings.Builder
+	if err := pageTemplate.Execute(&b, d.page(records, time.Now())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// page builds the status page from the current state and the stored records
+// of the page window. Without records the uptime falls back to the checks
+// run since the daemon started.
+func (d *Daemon) page(records []history.Record, now time.Time) pageData {
+	byName := make(map[string][]history.Record)
+	for _, record := range records {
+		byName[record.Name] = append(byName[record.Name], record)
+	}
+
+	data := pageData{
+		Title:       d.cfg.Title,
+		Generated:   now.Format("2006-01-02 15:04:05 MST"),
+		Window:      "24h",
+		Refresh:     30,
+		ChartWidth:  pageChartWidth,
+		ChartHeight: pageChartHeight,
+	}
+	if data.Title == "" {
+		data.Title = "tapr status"
+	}
+
+	down, degraded, flapping, pending := 0, 0, 0, 0
+	for _, status := range d.Statuses() {
+		monitor := pageMonitor{
+			Name:      status.Name,
+			URL:       status.URL,
+			State:     "pending",
+			Message:   status.Message,
+			Uptime:    "–",
+			DependsOn: strings.Join(status.DependsOn, ", "),
+		}
+		switch {
+		case status.Paused:
+			monitor.State = "paused"
+		case status.Maintenance != "" && status.Checks > 0 && !status.Up:
+			monitor.State = "maintenance"
+			monitor.Message = status.Message + " · " + status.Maintenance
+		case status.Checks == 0:
+			pending++
+		case status.Flapping:
+			monitor.State = "flapping"
+			flapping++
+		case status.Up:
+			monitor.State = "up"
+		case status.Upstream != "":
+			monitor.State = "degraded"
+			monitor.Message = fmt.Sprintf("upstream %s is down", status.Upstream)
+			degraded++
+		default:
+			monitor.State = "down"
+			down++
+		}
+		if status.Checks > 0 {
+			monitor.LastCheck = formatAgo(now.Sub(status.LastCheck))
+			monitor.Latency = formatMs(status.LatencyMs)
+			if status.Anomalous {
+				monitor.Latency += " (usually " + formatMs(status.BaselineMs) + ")"
+			}
+			monitor.Uptime = fmt.Sprintf("%.2f%%", status.Uptime)
+		}
+
+		if recent := byName[status.Name]; len(recent) > 0 {
+			successful := 0
+			for _, record := range recent {
+				if record.Success {
+					successful++
+				}
+			}
+			monitor.Uptime = fmt.Sprintf("%.2f%%", float64(successful)/float64(len(recent))*100)
+			monitor.Bars = chartBars(recent)
+		}
+		data.Monitors = append(data.Monitors, monitor)
+	}
+
+	switch {
+	case down > 0:
+		data.Summary = fmt.Sprintf("%d of %d monitors down", down, len(data.Monitors))
+		if degraded > 0 {
+			data.Summary += fmt.Sprintf(", %d degraded", degraded)
+		}
+		data.Banner = "down"
+	case degraded > 0:
+		data.Summary = fmt.Sprintf("%d of %d monitors degraded", degraded, len(data.Monitors))
+		data.Banner = "degraded"
+	case flapping > 0:
+		data.Summary = fmt.Sprintf("%d of %d monitors flapping", flapping, len(data.Monitors))
+		data.Banner = "flapping"
+	case pending == len(data.Monitors):
+		data.Summary = "Waiting for the first checks"
+		data.Banner = "pending"
+	default:
+		data.Summary = "All systems operational"
+		data.Banner = "up"
+	}
+	return data
+}
+
+// chartBars draws the most recent checks as bars scaled to the slowest one.
+// Failed checks are drawn full height in red.
+func chartBars(records []history.Record) []pageBar {
+	if len(records) > pageChartPoints {
+		records = records[len(records)-pageChartPoints:]
+	}
+
+	slowest := 0.0
+	for _, record := range records {
+		if record.Success {
+			slowest = max(slowest, record.LatencyMs)
+		}
+	}
+
+	slot := pageChartWidth / pageChartPoints
+	bars := make([]pageBar, 0, len(records))
+	for i, record := range records {
+		height := pageChartHeight
+		fill := "#c62828"
+		if record.Success {
+			fill = "#2e7d32"
+			height = 2
+			if slowest > 0 {
+				height = max(int(record.LatencyMs/slowest*pageChartHeight), 2)
+			}
+		}
+		bars = append(bars, pageBar{
+			X:      (pageChartPoints-len(records)+i)*slot + 1,
+			Y:      pageChartHeight - height,
+			Height: height,
+			Width:  slot - 1,
+			Fill:   fill,
+			Title:  fmt.Sprintf("%s · %d · %s", record.Time.Format("15:04:05"), record.Status, formatMs(record.LatencyMs)),
+		})
+	}
+	return bars
+}
+
+// formatMs formats a latency in milliseconds for display.
+func formatMs(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.0fms", ms)
+}
+
+// formatAgo formats the time since a check, like "12s ago".
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 960px; padding: 0 1rem; color: #222; }
+  h1 { margin-bottom: 0.25rem; }
+  .meta { color: #666; margin-top: 0; }
+  .banner { border-radius: 6px; padding: 0.75rem 1rem; margin: 1.5rem 0; font-weight: 600; color: #fff; }
+  .banner.up { background: #2e7d32; }
+  .banner.down { background: #c62828; }
+  .banner.flapping, .banner.degraded { background: #e65100; }
+  .banner.pending { background: #888; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: 0.5rem 0.6rem; border-bottom: 1px solid #eee; vertical-align: middle; }
+  th { background: #fafafa; }
+  td.num { text-align: right; font-variant-numeric: tabular-nums; }
+  .url { color: #666; font-size: 0.85rem; }
+  .message { color: #c62828; font-size: 0.85rem; }
+  .state { font-weight: 600; }
+  .state.up { color: #2e7d32; }
+  .state.down { color: #c62828; }
+  .state.pending, .state.paused { color: #888; }
+  .state.flapping, .state.degraded { color: #e65100; }
+  .state.maintenance { color: #b26a00; }
+  svg { display: block; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Updated {{.Generated}} · refreshes every {{.Refresh}}s</p>
+
+<div class="banner {{.Banner}}">{{.Summary}}</div>
+
+<table>
+  <tr><th>Monitor</th><th>State</th><th>Last check</th><th>Latency</th><th>Uptime ({{.Window}})</th><th>Recent checks</th></tr>
+  {{- range .Monitors}}
+  <tr>
+    <td><strong>{{.Name}}</strong><div class="url">{{.URL}}</div>{{if .DependsOn}}<div class="url">depends on {{.DependsOn}}</div>{{end}}{{if and (or (eq .State "down") (eq .State "degraded") (eq .State "flapping") (eq .State "maintenance")) .Message}}<div class="message">{{.Message}}</div>{{end}}</td>
+    <td class="state {{.State}}">{{if eq .State "up"}}● Up{{else if eq .State "down"}}● Down{{else if eq .State "degraded"}}◌ Degraded{{else if eq .State "flapping"}}↯ Flapping{{else if eq .State "paused"}}‖ Paused{{else if eq .State "maintenance"}}⚒ Maintenance{{else}}○ Pending{{end}}</td>
+    <td>{{.LastCheck}}</td>
+    <td class="num">{{.Latency}}</td>
+    <td class="num">{{.Uptime}}</td>
+    <td>
+      <svg xmlns="http://www.w3.org/2000/svg" width="{{$.ChartWidth}}" height="{{$.ChartHeight}}" role="img" aria-label="Latency of recent checks of {{.Name}}">
+        <rect x="0" y="0" width="{{$.ChartWidth}}" height="{{$.ChartHeight}}" fill="#fafafa"></rect>
+        {{- range .Bars}}
+        <rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" fill="{{.Fill}}"><title>{{.Title}}</title></rect>
+        {{- end}}
+      </svg>
+    </td>
+  </tr>
+  {{- end}}
+</table>
+</body>
+</html>
+`))
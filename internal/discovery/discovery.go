@@ -0,0 +1,286 @@
+// Package discovery looks up the instances of a service in a service
+// catalog (Consul or etcd), so batch endpoints can follow the instances
+// that are registered when the batch runs.
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// Default API addresses, used when neither the config nor the environment
+// sets one.
+const (
+	DefaultConsulAddress = "127.0.0.1:8500"
+	DefaultEtcdAddress   = "127.0.0.1:2379"
+)
+
+// maxResponseSize limits how much of a catalog response is read.
+const maxResponseSize = 32 << 20
+
+// client is used for every catalog request.
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Lookup returns the addresses ("host:port") or base URLs of the instances
+// found by a discover entry, sorted.
+func Lookup(ctx context.Context, d config.Discovery) ([]string, error) {
+	var (
+		addresses []string
+		err       error
+	)
+	switch {
+	case d.Consul != nil:
+		addresses, err = consul(ctx, *d.Consul)
+	case d.Etcd != nil:
+		addresses, err = etcd(ctx, *d.Etcd)
+	default:
+		return nil, fmt.Errorf("no discovery provider configured")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// baseURL turns an API address into a base URL, taking the default from
+// env (the first of a comma-separated list) or fallback.
+func baseURL(address, env, fallback string) string {
+	if address == "" {
+		address = strings.TrimSpace(strings.Split(os.Getenv(env), ",")[0])
+	}
+	if address == "" {
+		address = fallback
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return strings.TrimSuffix(address, "/")
+}
+
+// consulService is one instance in Consul's catalog API response.
+type consulService struct {
+	Address        string // Node address
+	ServiceAddress string
+	ServicePort    int
+}
+
+// consulHealthEntry is one instance in Consul's health API response.
+type consulHealthEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+// consul returns the addresses of a service's instances from Consul: from
+// the catalog, or from the health API when only passing instances are
+// wanted. The service address wins over the node address, as in Consul.
+func consul(ctx context.Context, c config.ConsulDiscovery) ([]string, error) {
+	query := url.Values{}
+	if c.Tag != "" {
+		query.Set("tag", c.Tag)
+	}
+	if c.Datacenter != "" {
+		query.Set("dc", c.Datacenter)
+	}
+	path := "/v1/catalog/service/"
+	if c.Passing {
+		path = "/v1/health/service/"
+		query.Set("passing", "true")
+	}
+	endpoint := baseURL(c.Address, "CONSUL_HTTP_ADDR", DefaultConsulAddress) + path + url.PathEscape(c.Service)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	token := c.Token
+	if token == "" {
+		token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+	if token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+
+	var services []consulService
+	if c.Passing {
+		var entries []consulHealthEntry
+		if err := doJSON(req, "consul", &entries); err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			services = append(services, consulService{
+				Address:        entry.Node.Address,
+				ServiceAddress: entry.Service.Address,
+				ServicePort:    entry.Service.Port,
+			})
+		}
+	} else if err := doJSON(req, "consul", &services); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(services))
+	for _, service := range services {
+		host := service.ServiceAddress
+		if host == "" {
+			host = service.Address
+		}
+		if host == "" || service.ServicePort == 0 {
+			continue
+		}
+		addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(service.ServicePort)))
+	}
+	return addresses, nil
+}
+
+// etcdKV is a key-value pair in an etcd v3 JSON API response, base64
+// encoded.
+type etcdKV struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// etcd returns the instances stored under a key prefix, read through
+// etcd's v3 JSON gateway.
+func etcd(ctx context.Context, e config.EtcdDiscovery) ([]string, error) {
+	base := baseURL(e.Address, "ETCDCTL_ENDPOINTS", DefaultEtcdAddress)
+
+	token := ""
+	if e.Username != "" {
+		var auth struct {
+			Token string `json:"token"`
+		}
+		body, _ := json.Marshal(map[string]string{"name": e.Username, "password": e.Password})
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/v3/auth/authenticate", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if err := doJSON(req, "etcd", &auth); err != nil {
+			return nil, err
+		}
+		token = auth.Token
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(e.Prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(prefixEnd(e.Prefix)),
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	var result struct {
+		KVs []etcdKV `json:"kvs"`
+	}
+	if err := doJSON(req, "etcd", &result); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(result.KVs))
+	for _, kv := range result.KVs {
+		key, _ := base64.StdEncoding.DecodeString(kv.Key)
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("etcd: invalid value for key %s", key)
+		}
+		address, err := parseEtcdValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("etcd key %s: %w", key, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// prefixEnd returns the end of the key range covering every key that
+// starts with prefix, as etcdctl get --prefix does.
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0} // Every key
+}
+
+// parseEtcdValue reads an instance address from an etcd value: a plain
+// address, a URL, or a JSON object with an address or URL field.
+func parseEtcdValue(value []byte) (string, error) {
+	text := strings.TrimSpace(string(value))
+	if !strings.HasPrefix(text, "{") {
+		if text == "" {
+			return "", fmt.Errorf("empty value")
+		}
+		return text, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &fields); err != nil {
+		return "", fmt.Errorf("invalid JSON value: %w", err)
+	}
+	for _, name := range []string{"url", "URL", "addr", "Addr", "address", "Address"} {
+		if address, ok := fields[name].(string); ok && address != "" {
+			return address, nil
+		}
+	}
+	host, _ := fields["host"].(string)
+	if port, ok := fields["port"].(float64); ok && host != "" {
+		return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+	}
+	return "", fmt.Errorf("value has no url, addr, address or host and port field")
+}
+
+// doJSON sends req and decodes a successful JSON response into v.
+func doJSON(req *http.Request, api string, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", api, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("%s: %w", api, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(data))
+		if len(message) > 200 {
+			message = message[:200]
+		}
+		if message != "" {
+			return fmt.Errorf("%s returned %s: %s", api, resp.Status, message)
+		}
+		return fmt.Errorf("%s returned %s", api, resp.Status)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", api, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package discovery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+func TestLookup_Consul(t *testing.T) {
+	var gotPath, gotQuery, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotToken = r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Consul-Token")
+		switch r.URL.Path {
+		case "/v1/catalog/service/api":
+			_, _ = w.Write([]byte(`[
+				{"Node": "node-b", "Address": "10.0.0.2", "ServiceAddress": "", "ServicePort": 8080},
+				{"Node": "node-a", "Address": "10.0.0.1", "ServiceAddress": "172.17.0.5", "ServicePort": 9090}
+			]`))
+		case "/v1/health/service/api":
+			_, _ = w.Write([]byte(`[
+				{"Node": {"Node": "node-a", "Address": "10.0.0.1"}, "Service": {"Address": "", "Port": 8080}}
+			]`))
+		default:
+			http.Error(w, "no such service", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		consul    config.ConsulDiscovery
+		want      []string
+		wantPath  string
+		wantQuery string
+		wantToken string
+	}{
+		{
+			name:      "catalog",
+			consul:    config.ConsulDiscovery{Service: "api", Tag: "v2", Datacenter: "eu", Token: "s3cret"},
+			want:      []string{"10.0.0.2:8080", "172.17.0.5:9090"},
+			wantPath:  "/v1/catalog/service/api",
+			wantQuery: "dc=eu&tag=v2",
+			wantToken: "s3cret",
+		},
+		{
+			name:      "passing",
+			consul:    config.ConsulDiscovery{Service: "api", Passing: true},
+			want:      []string{"10.0.0.1:8080"},
+			wantPath:  "/v1/health/service/api",
+			wantQuery: "passing=true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.consul.Address = server.URL
+			got, err := Lookup(context.Background(), config.Discovery{Consul: &tt.consul})
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() = %v, want %v", got, tt.want)
+			}
+			if gotPath != tt.wantPath || gotQuery != tt.wantQuery || gotToken != tt.wantToken {
+				t.Errorf("request = %s?%s (token %q), want %s?%s (token %q)", gotPath, gotQuery, gotToken, tt.wantPath, tt.wantQuery, tt.wantToken)
+			}
+		})
+	}
+
+	_, err := Lookup(context.Background(), config.Discovery{Consul: &config.ConsulDiscovery{Address: server.URL, Service: "missing"}})
+	if err == nil || !strings.Contains(err.Error(), "consul returned 404 Not Found: no such service") {
+		t.Errorf("Lookup(missing) error = %v", err)
+	}
+}
+
+func TestLookup_Etcd(t *testing.T) {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	var gotRange map[string]string
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/auth/authenticate":
+			var creds map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			if creds["name"] != "tapr" || creds["password"] != "pw" {
+				http.Error(w, `{"error":"authentication failed"}`, http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"tok123"}`))
+		case "/v3/kv/range":
+			gotAuth = r.Header.Get("Authorization")
+			_ = json.NewDecoder(r.Body).Decode(&gotRange)
+			kvs := []map[string]string{
+				{"key": encode("/services/api/a"), "value": encode("10.0.0.2:8080\n")},
+				{"key": encode("/services/api/b"), "value": encode(`{"Addr": "10.0.0.1:8080", "Metadata": null}`)},
+				{"key": encode("/services/api/c"), "value": encode(`{"host": "10.0.0.3", "port": 8443}`)},
+				{"key": encode("/services/api/d"), "value": encode("https://api-d.internal:8443")},
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"kvs": kvs})
+		}
+	}))
+	defer server.Close()
+
+	etcd := &config.EtcdDiscovery{Address: server.URL, Prefix: "/services/api/", Username: "tapr", Password: "pw"}
+	got, err := Lookup(context.Background(), config.Discovery{Etcd: etcd})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	want := []string{"10.0.0.1:8080", "10.0.0.2:8080", "10.0.0.3:8443", "https://api-d.internal:8443"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup() = %v, want %v", got, want)
+	}
+	if gotRange["key"] != encode("/services/api/") || gotRange["range_end"] != encode("/services/api0") {
+		t.Errorf("range = %v, want the /services/api/ prefix", gotRange)
+	}
+	if gotAuth != "tok123" {
+		t.Errorf("Authorization = %q, want the token from authenticate", gotAuth)
+	}
+
+	etcd.Password = "wrong"
+	if _, err := Lookup(context.Background(), config.Discovery{Etcd: etcd}); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("Lookup(wrong password) error = %v", err)
+	}
+}
+
+func TestParseEtcdValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"10.0.0.1:80", "10.0.0.1:80", false},
+		{`{"url": "http://api:80"}`, "http://api:80", false},
+		{`{"address": "api:80"}`, "api:80", false},
+		{`{"host": "api"}`, "", true},
+		{`{"addr": `, "", true},
+		{"  ", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseEtcdValue([]byte(tt.value))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseEtcdValue(%q) = %q, %v, want %q (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   []byte
+	}{
+		{"/services/", []byte("/services0")},
+		{"a\xff", []byte("b")},
+		{"\xff", []byte{0}},
+	}
+	for _, tt := range tests {
+		if got := prefixEnd(tt.prefix); string(got) != string(tt.want) {
+			t.Errorf("prefixEnd(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package history persists check results as newline-delimited JSON so they
+// can be queried after the process that recorded them has exited.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Record is a single stored check result.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Status    int       `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	Success   bool      `json:"success"`
+	Message   string    `json:"message,omitempty"`
+	Silenced  bool      `json:"silenced,omitempty"` // Failed during maintenance
+	Upstream  string    `json:"upstream,omitempty"` // Down dependency the check failed under
+	Run       string    `json:"run,omitempty"`      // ID of the batch run that recorded it (batch --history)
+}
+
+// Latency returns the record's latency as a duration.
+func (r Record) Latency() time.Duration {
+	return time.Duration(r.LatencyMs * float64(time.Millisecond))
+}
+
+// Query filters records returned by Load and Store.Query.
+type Query struct {
+	Name  string    // Only records for this name ("" = all)
+	Since time.Time // Only records at or after this time (zero = all)
+	Limit int       // Keep only the most recent N records (0 = all)
+	Run   string    // Only records of this batch run ("" = all)
+}
+
+// matches reports whether r passes the name, run and time filters.
+func (q Query) matches(r Record) bool {
+	if q.Name != "" && r.Name != q.Name {
+		return false
+	}
+	if q.Run != "" && r.Run != q.Run {
+		return false
+	}
+	return q.Since.IsZero() || !r.Time.Before(q.Since)
+}
+
+// Store appends records to a history file. It is safe for concurrent use.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// DefaultPath returns the default history file location,
+// ~/.local/share/tapr/history.ndjson (or $XDG_DATA_HOME/tapr/...).
+func DefaultPath() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "tapr", "history.ndjson")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "tapr-history.ndjson"
+	}
+	return filepath.Join(home, ".local", "share", "tapr", "history.ndjson")
+}
+
+// Open opens (creating if needed) the history file at path for appending.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+
+	return &Store{path: path, file: file}, nil
+}
+
+// Path returns the file the store writes to.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append writes a record to the end of the history file.
+func (s *Store) Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Query returns the stored records matching q, oldest first.
+func (s *Store) Query(q Query) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Load(s.path, q)
+}
+
+// Close closes the history file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// Load reads the records matching q from the history file at path, oldest
+// first. A missing file yields no records. Malformed lines (e.g., from an
+// interrupted write) are skipped.
+func Load(path string, q Query) ([]Record, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if q.matches(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[len(records)-q.Limit:]
+	}
+	return records, nil
+}
+
+// Runs returns the IDs of the batch runs recorded in records, oldest first.
+func Runs(records []Record) []string {
+	var runs []string
+	seen := make(map[string]bool)
+	for _, record := range records {
+		if record.Run != "" && !seen[record.Run] {
+			seen[record.Run] = true
+			runs = append(runs, record.Run)
+		}
+	}
+	return runs
+}
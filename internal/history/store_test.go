@@ -0,0 +1,101 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreAppendAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.ndjson")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer store.Close()
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: base, Name: "api", Status: 200, LatencyMs: 120, Success: true},
+		{Time: base.Add(time.Minute), Name: "auth", Status: 500, LatencyMs: 80, Message: "Expected 200, got 500"},
+		{Time: base.Add(2 * time.Minute), Name: "api", Status: 200, LatencyMs: 95.5, Success: true},
+	}
+	for _, r := range records {
+		if err := store.Append(r); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  int
+	}{
+		{"all", Query{}, 3},
+		{"by name", Query{Name: "api"}, 2},
+		{"since", Query{Since: base.Add(time.Minute)}, 2},
+		{"limit keeps newest", Query{Limit: 1}, 1},
+		{"unknown name", Query{Name: "missing"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(tt.query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Query() returned %d records, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	latest, _ := store.Query(Query{Limit: 1})
+	if latest[0].Latency() != 95500*time.Microsecond {
+		t.Errorf("Latency() = %v, want 95.5ms", latest[0].Latency())
+	}
+}
+
+func TestLoadSkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	content := `{"time":"2024-01-01T12:00:00Z","name":"api","status":200,"latency_ms":10,"success":true}
+{"time":"2024-01-01T12:01:00Z","name":"ap
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Load(path, Query{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("Load() returned %d records, want 1", len(records))
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	records, err := Load(filepath.Join(t.TempDir(), "missing.ndjson"), Query{})
+	if err != nil || len(records) != 0 {
+		t.Errorf("Load() = %v, %v; want no records and no error", records, err)
+	}
+}
+
+func TestRuns(t *testing.T) {
+	records := []Record{
+		{Name: "api", Run: "20240101-120000"},
+		{Name: "auth", Run: "20240101-120000"},
+		{Name: "api"}, // From the daemon
+		{Name: "api", Run: "20240102-120000"},
+	}
+
+	runs := Runs(records)
+	if len(runs) != 2 || runs[0] != "20240101-120000" || runs[1] != "20240102-120000" {
+		t.Errorf("Runs() = %v, want both runs oldest first", runs)
+	}
+	if got := (Query{Run: "20240102-120000"}); got.matches(records[0]) || !got.matches(records[3]) {
+		t.Error("Query.Run should only match records of that run")
+	}
+}
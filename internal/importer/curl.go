@@ -0,0 +1,311 @@
+package importer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// curlIgnored are curl options that have no tapr equivalent (or are tapr's
+// default behavior) and take no argument.
+var curlIgnored = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true,
+	"-v": true, "--verbose": true, "-i": true, "--include": true,
+	"-L": true, "--location": true, "-k": true, "--insecure": true,
+	"--compressed": true, "-f": true, "--fail": true, "-#": true,
+	"--progress-bar": true, "--digest": true, "--http1.1": true, "--http2": true,
+}
+
+// curlSwitches are translated curl options that take no argument.
+var curlSwitches = map[string]bool{
+	"-I": true, "--head": true, "-G": true, "--get": true,
+}
+
+// curlIgnoredWithArg are ignored curl options that take an argument.
+var curlIgnoredWithArg = map[string]bool{
+	"-o": true, "--output": true, "-w": true, "--write-out": true,
+	"--connect-timeout": true, "--retry": true, "--cacert": true,
+	"-E": true, "--cert": true, "--key": true, "--resolve": true,
+	"-x": true, "--proxy": true,
+}
+
+// Curl converts the words of a curl command line into a batch endpoint.
+// The leading "curl" is optional. Headers, method, body (-d, --data-*,
+// --json), basic auth (-u), cookies (-b), -G and --max-time are
+// translated; options with no tapr equivalent (-k, -L, -o, ...) are
+// returned as ignored. Unknown options are an error, so nothing is
+// silently misread.
+func Curl(args []string) (config.Endpoint, []string, error) {
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+	args = expandShortOptions(args)
+
+	var (
+		endpoint config.Endpoint
+		ignored  []string
+		data     []string
+		getData  bool
+		head     bool
+	)
+	headers := make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// --opt=value is accepted for long options
+		name, inline, hasInline := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			if eq := strings.IndexByte(arg, '='); eq > 0 {
+				name, inline, hasInline = arg[:eq], arg[eq+1:], true
+			}
+		}
+
+		value := func() (string, error) {
+			if hasInline {
+				return inline, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl option %s requires a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if endpoint.URL != "" {
+				return endpoint, nil, fmt.Errorf("multiple URLs in curl command ('%s' and '%s')", endpoint.URL, arg)
+			}
+			endpoint.URL = arg
+			continue
+		}
+
+		switch {
+		case curlIgnored[name]:
+			ignored = append(ignored, name)
+			continue
+		case curlIgnoredWithArg[name]:
+			if _, err := value(); err != nil {
+				return endpoint, nil, err
+			}
+			ignored = append(ignored, name)
+			continue
+		}
+
+		var v string
+		if !curlSwitches[name] {
+			var err error
+			if v, err = value(); err != nil {
+				return endpoint, nil, err
+			}
+		}
+
+		switch name {
+		case "--url":
+			endpoint.URL = v
+		case "-X", "--request":
+			endpoint.Method = strings.ToUpper(v)
+		case "-H", "--header":
+			key, val, ok := strings.Cut(v, ":")
+			if !ok {
+				return endpoint, nil, fmt.Errorf("invalid header '%s' (expected 'Key: Value')", v)
+			}
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw":
+			if strings.HasPrefix(v, "@") && name != "--data-raw" {
+				content, err := os.ReadFile(v[1:])
+				if err != nil {
+					return endpoint, nil, fmt.Errorf("curl %s: %w", name, err)
+				}
+				v = string(content)
+				if name != "--data-binary" {
+					v = strings.ReplaceAll(strings.ReplaceAll(v, "\r", ""), "\n", "")
+				}
+			}
+			data = append(data, v)
+		case "--data-urlencode":
+			key, val, ok := strings.Cut(v, "=")
+			if ok {
+				data = append(data, key+"="+url.QueryEscape(val))
+			} else {
+				data = append(data, url.QueryEscape(v))
+			}
+		case "--json":
+			data = append(data, v)
+			setDefault(headers, "Content-Type", "application/json")
+			setDefault(headers, "Accept", "application/json")
+		case "-u", "--user":
+			headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(v))
+		case "-b", "--cookie":
+			if !strings.Contains(v, "=") {
+				return endpoint, nil, fmt.Errorf("curl %s: cookie files are not supported", name)
+			}
+			headers["Cookie"] = v
+		case "-A", "--user-agent":
+			headers["User-Agent"] = v
+		case "-e", "--referer":
+			headers["Referer"] = v
+		case "-m", "--max-time":
+			seconds, err := strconv.ParseFloat(v, 64)
+			if err != nil || seconds <= 0 {
+				return endpoint, nil, fmt.Errorf("invalid --max-time '%s'", v)
+			}
+			endpoint.Timeout = time.Duration(seconds * float64(time.Second))
+		case "-I", "--head":
+			head = true
+		case "-G", "--get":
+			getData = true
+		default:
+			return endpoint, nil, fmt.Errorf("unsupported curl option '%s'", name)
+		}
+	}
+
+	if endpoint.URL == "" {
+		return endpoint, nil, fmt.Errorf("no URL in curl command")
+	}
+	if !strings.Contains(endpoint.URL, "://") {
+		endpoint.URL = "http://" + endpoint.URL // curl's default scheme
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case getData && body != "":
+		separator := "?"
+		if strings.Contains(endpoint.URL, "?") {
+			separator = "&"
+		}
+		endpoint.URL += separator + body
+	case body != "":
+		endpoint.Body = body
+		setDefault(headers, "Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if endpoint.Method == "" {
+		switch {
+		case head:
+			endpoint.Method = "HEAD"
+		case endpoint.Body != "":
+			endpoint.Method = "POST"
+		default:
+			endpoint.Method = "GET"
+		}
+	}
+
+	if len(headers) > 0 {
+		endpoint.Headers = headers
+	}
+
+	endpoint.Name = endpoint.Method + " " + endpoint.URL
+	if u, err := url.Parse(endpoint.URL); err == nil && u.Path != "" {
+		endpoint.Name = endpoint.Method + " " + u.Path
+	}
+
+	return endpoint, ignored, nil
+}
+
+// expandShortOptions splits combined short options the way curl reads
+// them: "-sSL" becomes "-s -S -L" and "-XPOST" becomes "-X POST".
+func expandShortOptions(args []string) []string {
+	expanded := make([]string, 0, len(args))
+	for _, arg := range args {
+		if len(arg) <= 2 || arg[0] != '-' || arg[1] == '-' {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		for i := 1; i < len(arg); i++ {
+			flag := "-" + string(arg[i])
+			if curlIgnored[flag] || curlSwitches[flag] {
+				expanded = append(expanded, flag)
+				continue
+			}
+			// The first option taking a value consumes the rest
+			expanded = append(expanded, flag)
+			if i+1 < len(arg) {
+				expanded = append(expanded, arg[i+1:])
+			}
+			break
+		}
+	}
+	return expanded
+}
+
+// setDefault sets a header unless one with the same name (in any case) is
+// already present.
+func setDefault(headers map[string]string, key, value string) {
+	if !hasHeader(headers, key) {
+		headers[key] = value
+	}
+}
+
+// SplitCommand splits a shell command line into words the way a POSIX
+// shell would for a pasted curl snippet: single quotes are literal, double
+// quotes honor backslash escapes, and backslash-newline continues a line.
+func SplitCommand(command string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+	)
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]):
+				i++
+				if runes[i] != '\n' {
+					current.WriteRune(runes[i])
+				}
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' {
+					current.WriteRune(runes[i])
+					inWord = true
+				}
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in command", quote)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
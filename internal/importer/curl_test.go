@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"plain words", "curl -s https://x.io", []string{"curl", "-s", "https://x.io"}, false},
+		{"single quotes", `curl -H 'Authorization: Bearer a b'`, []string{"curl", "-H", "Authorization: Bearer a b"}, false},
+		{"double quotes with escapes", `curl -d "{\"a\": 1}"`, []string{"curl", "-d", `{"a": 1}`}, false},
+		{"line continuation", "curl \\\n  -X POST \\\n  https://x.io", []string{"curl", "-X", "POST", "https://x.io"}, false},
+		{"adjacent quotes", `curl -H 'A: '"b"`, []string{"curl", "-H", "A: b"}, false},
+		{"empty quoted word", `curl -d ''`, []string{"curl", "-d", ""}, false},
+		{"unterminated quote", `curl -d 'oops`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitCommand(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurl(t *testing.T) {
+	args, err := SplitCommand(`curl -sSL -XPOST 'https://api.example.com/users?x=1' \
+  -H 'Authorization: Bearer abc' -H "Content-Type: application/json" \
+  --data-raw '{"name": "ada"}' -m 2.5 -k`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint, ignored, err := Curl(args)
+	if err != nil {
+		t.Fatalf("Curl() error = %v", err)
+	}
+
+	if endpoint.Method != "POST" || endpoint.URL != "https://api.example.com/users?x=1" {
+		t.Errorf("Curl() = %s %s", endpoint.Method, endpoint.URL)
+	}
+	if endpoint.Name != "POST /users" {
+		t.Errorf("Name = %s, want 'POST /users'", endpoint.Name)
+	}
+	if endpoint.Headers["Authorization"] != "Bearer abc" || endpoint.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Headers = %v", endpoint.Headers)
+	}
+	if endpoint.Body != `{"name": "ada"}` {
+		t.Errorf("Body = %s", endpoint.Body)
+	}
+	if endpoint.Timeout != 2500*time.Millisecond {
+		t.Errorf("Timeout = %v, want 2.5s", endpoint.Timeout)
+	}
+	if strings.Join(ignored, " ") != "-s -S -L -k" {
+		t.Errorf("ignored = %v, want [-s -S -L -k]", ignored)
+	}
+}
+
+func TestCurlOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantMethod string
+		wantURL    string
+		wantBody   string
+		wantHeader [2]string
+	}{
+		{"default GET", []string{"example.com/health"}, "GET", "http://example.com/health", "", [2]string{}},
+		{"data implies POST", []string{"-d", "a=1", "-d", "b=2", "https://x.io"}, "POST", "https://x.io", "a=1&b=2", [2]string{"Content-Type", "application/x-www-form-urlencoded"}},
+		{"get moves data to query", []string{"-G", "--data-urlencode", "q=a b", "https://x.io/s"}, "GET", "https://x.io/s?q=a+b", "", [2]string{}},
+		{"json", []string{"--json", `{"a":1}`, "https://x.io"}, "POST", "https://x.io", `{"a":1}`, [2]string{"Content-Type", "application/json"}},
+		{"head", []string{"-I", "https://x.io"}, "HEAD", "https://x.io", "", [2]string{}},
+		{"basic auth", []string{"-u", "alice:secret", "https://x.io"}, "GET", "https://x.io", "", [2]string{"Authorization", "Basic YWxpY2U6c2VjcmV0"}},
+		{"long option with equals", []string{"--request=PUT", "--url=https://x.io", "--cookie=sid=1"}, "PUT", "https://x.io", "", [2]string{"Cookie", "sid=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, _, err := Curl(append([]string{"curl"}, tt.args...))
+			if err != nil {
+				t.Fatalf("Curl() error = %v", err)
+			}
+			if endpoint.Method != tt.wantMethod || endpoint.URL != tt.wantURL || endpoint.Body != tt.wantBody {
+				t.Errorf("Curl() = %s %s %q, want %s %s %q",
+					endpoint.Method, endpoint.URL, endpoint.Body, tt.wantMethod, tt.wantURL, tt.wantBody)
+			}
+			if tt.wantHeader[0] != "" && endpoint.Headers[tt.wantHeader[0]] != tt.wantHeader[1] {
+				t.Errorf("Headers[%s] = %s, want %s", tt.wantHeader[0], endpoint.Headers[tt.wantHeader[0]], tt.wantHeader[1])
+			}
+		})
+	}
+}
+
+func TestCurlErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no url", []string{"curl", "-s"}, "no URL"},
+		{"missing value", []string{"curl", "https://x.io", "-H"}, "requires a value"},
+		{"unknown option", []string{"curl", "--tlsv1.3", "https://x.io"}, "unsupported curl option"},
+		{"two urls", []string{"curl", "https://a.io", "https://b.io"}, "multiple URLs"},
+		{"bad header", []string{"curl", "-H", "nocolon", "https://x.io"}, "invalid header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Curl(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Curl() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// HAROptions controls which HAR entries are imported and how.
+type HAROptions struct {
+	Hosts    []string // Only import requests to these hosts (default: all)
+	Static   bool     // Also import images, scripts, stylesheets, fonts and media
+	Sequence bool     // Chain the endpoints with depends_on to replay them in order
+}
+
+// staticResources are the browser resource types skipped unless
+// HAROptions.Static is set.
+var staticResources = map[string]bool{
+	"image":      true,
+	"stylesheet": true,
+	"script":     true,
+	"font":       true,
+	"media":      true,
+}
+
+// skippedHARHeaders are managed by the HTTP client (or are HTTP/2
+// pseudo-headers) and are not copied from the recording.
+var skippedHARHeaders = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"accept-encoding":   true,
+	"transfer-encoding": true,
+}
+
+// harLog is an HTTP Archive (HAR 1.2) file.
+type harLog struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	ResourceType string `json:"_resourceType"` // Chrome extension field
+	Request      struct {
+		Method   string         `json:"method"`
+		URL      string         `json:"url"`
+		Headers  []harNameValue `json:"headers"`
+		PostData *struct {
+			MimeType string         `json:"mimeType"`
+			Text     string         `json:"text"`
+			Params   []harNameValue `json:"params"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HAR builds a batch config from a browser-recorded HAR file. Each request
+// keeps its method, URL, headers and body, and the recorded response status
+// becomes the expected status (redirects are followed on replay, so 3xx
+// responses keep the default of 200). Static assets are skipped unless
+// opts.Static is set. With opts.Sequence each endpoint depends on the one
+// before it, so 'tapr batch' replays the flow in recorded order.
+func HAR(data []byte, opts HAROptions) (*config.BatchConfig, error) {
+	var har harLog
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file: %w", err)
+	}
+
+	cfg := &config.BatchConfig{}
+	names := make(map[string]int)
+
+	for _, entry := range har.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if !opts.Static && isStatic(entry) {
+			continue
+		}
+		if len(opts.Hosts) > 0 && !containsFold(opts.Hosts, u.Hostname()) {
+			continue
+		}
+
+		endpoint := harEndpoint(entry)
+		endpoint.Name = uniqueName(names, endpoint.Method+" "+u.Path)
+
+		if opts.Sequence && len(cfg.Endpoints) > 0 {
+			endpoint.DependsOn = []string{cfg.Endpoints[len(cfg.Endpoints)-1].Name}
+		}
+		cfg.Endpoints = append(cfg.Endpoints, endpoint)
+	}
+
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("no matching requests found in HAR file")
+	}
+	return cfg, nil
+}
+
+// harEndpoint converts a recorded request into a batch endpoint.
+func harEndpoint(entry harEntry) config.Endpoint {
+	req := entry.Request
+	endpoint := config.Endpoint{
+		URL:    req.URL,
+		Method: strings.ToUpper(req.Method),
+	}
+
+	for _, header := range req.Headers {
+		name := strings.ToLower(header.Name)
+		if strings.HasPrefix(name, ":") || skippedHARHeaders[name] {
+			continue
+		}
+		if endpoint.Headers == nil {
+			endpoint.Headers = make(map[string]string)
+		}
+		endpoint.Headers[header.Name] = header.Value
+	}
+
+	if req.PostData != nil {
+		endpoint.Body = req.PostData.Text
+		if endpoint.Body == "" && len(req.PostData.Params) > 0 {
+			form := url.Values{}
+			for _, param := range req.PostData.Params {
+				form.Add(param.Name, param.Value)
+			}
+			endpoint.Body = form.Encode()
+		}
+		if endpoint.Body != "" && req.PostData.MimeType != "" && !hasHeader(endpoint.Headers, "Content-Type") {
+			if endpoint.Headers == nil {
+				endpoint.Headers = make(map[string]string)
+			}
+			endpoint.Headers["Content-Type"] = req.PostData.MimeType
+		}
+	}
+
+	if status := entry.Response.Status; status > 0 && (status < 300 || status > 399) {
+		endpoint.ExpectedStatus = status
+	}
+
+	return endpoint
+}
+
+// isStatic reports whether an entry loaded a static asset, using Chrome's
+// resource type when recorded and the response MIME type otherwise.
+func isStatic(entry harEntry) bool {
+	if entry.ResourceType != "" {
+		return staticResources[entry.ResourceType]
+	}
+
+	mime := entry.Response.Content.MimeType
+	for _, prefix := range []string{"image/", "font/", "audio/", "video/", "text/css", "text/javascript", "application/javascript"} {
+		if strings.HasPrefix(mime, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueName returns name, numbered when it was already used.
+func uniqueName(used map[string]int, name string) string {
+	used[name]++
+	if n := used[name]; n > 1 {
+		return fmt.Sprintf("%s (%d)", name, n)
+	}
+	return name
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const recording = `{"log": {"version": "1.2", "entries": [
+  {
+    "_resourceType": "document",
+    "request": {"method": "GET", "url": "https://app.example.com/dashboard",
+      "headers": [{"name": ":authority", "value": "app.example.com"}, {"name": "Cookie", "value": "sid=1"}, {"name": "Accept-Encoding", "value": "gzip"}]},
+    "response": {"status": 200, "content": {"mimeType": "text/html"}}
+  },
+  {
+    "_resourceType": "script",
+    "request": {"method": "GET", "url": "https://cdn.example.com/app.js", "headers": []},
+    "response": {"status": 200, "content": {"mimeType": "application/javascript"}}
+  },
+  {
+    "request": {"method": "POST", "url": "https://api.example.com/v1/orders", "headers": [],
+      "postData": {"mimeType": "application/json", "text": "{\"sku\":\"A1\"}"}},
+    "response": {"status": 201, "content": {"mimeType": "application/json"}}
+  },
+  {
+    "request": {"method": "POST", "url": "https://app.example.com/login", "headers": [],
+      "postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "alice"}]}},
+    "response": {"status": 302, "content": {"mimeType": "text/html"}}
+  },
+  {
+    "request": {"method": "GET", "url": "https://app.example.com/dashboard", "headers": []},
+    "response": {"status": 200, "content": {"mimeType": "text/html"}}
+  },
+  {
+    "request": {"method": "GET", "url": "data:image/png;base64,AAAA", "headers": []},
+    "response": {"status": 200, "content": {"mimeType": "image/png"}}
+  }
+]}}`
+
+func TestHAR(t *testing.T) {
+	cfg, err := HAR([]byte(recording), HAROptions{})
+	if err != nil {
+		t.Fatalf("HAR() error = %v", err)
+	}
+
+	var names []string
+	for _, endpoint := range cfg.Endpoints {
+		names = append(names, endpoint.Name)
+	}
+	want := "GET /dashboard,POST /v1/orders,POST /login,GET /dashboard (2)"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("endpoints = %v, want %s", names, want)
+	}
+
+	dashboard := cfg.Endpoints[0]
+	if len(dashboard.Headers) != 1 || dashboard.Headers["Cookie"] != "sid=1" {
+		t.Errorf("dashboard headers = %v, want only Cookie", dashboard.Headers)
+	}
+
+	orders := cfg.Endpoints[1]
+	if orders.Body != `{"sku":"A1"}` || orders.Headers["Content-Type"] != "application/json" || orders.ExpectedStatus != 201 {
+		t.Errorf("orders = %+v", orders)
+	}
+
+	login := cfg.Endpoints[2]
+	if login.Body != "user=alice" || login.ExpectedStatus != 0 {
+		t.Errorf("login = %+v, want form body and no expected status for a redirect", login)
+	}
+
+	if len(cfg.Endpoints[1].DependsOn) != 0 {
+		t.Error("endpoints should not depend on each other without Sequence")
+	}
+}
+
+func TestHAROptions(t *testing.T) {
+	cfg, err := HAR([]byte(recording), HAROptions{Hosts: []string{"APP.example.com"}, Sequence: true})
+	if err != nil {
+		t.Fatalf("HAR() error = %v", err)
+	}
+
+	if len(cfg.Endpoints) != 3 {
+		t.Fatalf("got %d endpoints, want the 3 app.example.com requests", len(cfg.Endpoints))
+	}
+	if len(cfg.Endpoints[0].DependsOn) != 0 {
+		t.Errorf("first endpoint depends on %v", cfg.Endpoints[0].DependsOn)
+	}
+	if deps := cfg.Endpoints[2].DependsOn; len(deps) != 1 || deps[0] != "POST /login" {
+		t.Errorf("third endpoint depends on %v, want [POST /login]", deps)
+	}
+
+	cfg, err = HAR([]byte(recording), HAROptions{Static: true})
+	if err != nil {
+		t.Fatalf("HAR() error = %v", err)
+	}
+	if len(cfg.Endpoints) != 5 {
+		t.Errorf("got %d endpoints, want 5 including the script", len(cfg.Endpoints))
+	}
+}
+
+func TestHARErrors(t *testing.T) {
+	if _, err := HAR([]byte("{"), HAROptions{}); err == nil {
+		t.Error("HAR() should fail on invalid JSON")
+	}
+	if _, err := HAR([]byte(recording), HAROptions{Hosts: []string{"other.example.com"}}); err == nil {
+		t.Error("HAR() should fail when no request matches")
+	}
+}
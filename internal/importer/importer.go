@@ -0,0 +1,48 @@
+// Package importer converts API descriptions from other tools (OpenAPI
+// specs, Postman collections, HAR recordings) into tapr batch configurations.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// BaseVariable is the batch variable holding the server URL that imported
+// endpoint URLs are relative to, so the config can be pointed elsewhere.
+const BaseVariable = "base"
+
+// Marshal encodes an imported batch config as YAML, with variables listed
+// before the endpoints that use them and unset fields left out.
+func Marshal(cfg *config.BatchConfig) ([]byte, error) {
+	ordered := struct {
+		Variables   map[string]string `yaml:"variables,omitempty"`
+		Concurrency int               `yaml:"concurrency,omitempty"`
+		Timeout     time.Duration     `yaml:"timeout,omitempty"`
+		Endpoints   []config.Endpoint `yaml:"endpoints"`
+	}{cfg.Variables, cfg.Concurrency, cfg.Timeout, cfg.Endpoints}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(ordered); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toJSON encodes a decoded YAML/JSON value as compact JSON.
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,362 @@
+package importer
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods lists the operations of a path item in output order.
+var openAPIMethods = []string{"get", "head", "options", "post", "put", "patch", "delete"}
+
+// pathParam matches {name} segments in OpenAPI paths and server URLs.
+var pathParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// OpenAPIOptions controls which operations are imported.
+type OpenAPIOptions struct {
+	Methods []string // HTTP methods to import (default: GET)
+	Server  string   // Base URL overriding the spec's servers
+}
+
+// openAPISpec holds the parts of an OpenAPI 3 or Swagger 2 document that
+// are needed to build requests.
+type openAPISpec struct {
+	OpenAPI string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+
+	// OpenAPI 3
+	Servers    []openAPIServer `yaml:"servers"`
+	Components struct {
+		Parameters map[string]openAPIParameter `yaml:"parameters"`
+	} `yaml:"components"`
+
+	// Swagger 2
+	Host       string                      `yaml:"host"`
+	BasePath   string                      `yaml:"basePath"`
+	Schemes    []string                    `yaml:"schemes"`
+	Parameters map[string]openAPIParameter `yaml:"parameters"`
+
+	Paths map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+type openAPIServer struct {
+	URL       string `yaml:"url"`
+	Variables map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+type openAPIOperation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Parameters  []openAPIParameter   `yaml:"parameters"`
+	Responses   map[string]yaml.Node `yaml:"responses"`
+	RequestBody struct {
+		Content map[string]struct {
+			Example interface{} `yaml:"example"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+}
+
+type openAPIParameter struct {
+	Ref      string      `yaml:"$ref"`
+	Name     string      `yaml:"name"`
+	In       string      `yaml:"in"`
+	Example  interface{} `yaml:"example"`
+	XExample interface{} `yaml:"x-example"` // Swagger 2 vendor extension
+	Default  interface{} `yaml:"default"`
+	Schema   struct {
+		Example interface{} `yaml:"example"`
+		Default interface{} `yaml:"default"`
+	} `yaml:"schema"`
+}
+
+// OpenAPI builds a batch config from an OpenAPI 3 or Swagger 2 document
+// (YAML or JSON). Each selected operation becomes an endpoint whose URL is
+// relative to the {{base}} variable, with parameters filled from their
+// example or default values and the lowest documented 2xx response as the
+// expected status. Path parameters without an example are left as
+// {{name}} placeholders for the user to define.
+func OpenAPI(data []byte, opts OpenAPIOptions) (*config.BatchConfig, error) {
+	var spec openAPISpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if spec.OpenAPI == "" && spec.Swagger == "" {
+		return nil, fmt.Errorf("not an OpenAPI document (missing 'openapi' or 'swagger' version)")
+	}
+
+	base := opts.Server
+	if base == "" {
+		var err error
+		if base, err = spec.serverURL(); err != nil {
+			return nil, err
+		}
+	}
+
+	methods := opts.Methods
+	if len(methods) == 0 {
+		methods = []string{"GET"}
+	}
+	selected := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		selected[strings.ToLower(method)] = true
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	cfg := &config.BatchConfig{
+		Variables: map[string]string{BaseVariable: strings.TrimSuffix(base, "/")},
+	}
+
+	for _, path := range paths {
+		item := spec.Paths[path]
+
+		var shared []openAPIParameter
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&shared); err != nil {
+				return nil, fmt.Errorf("path %s: invalid parameters: %w", path, err)
+			}
+		}
+
+		for _, method := range openAPIMethods {
+			node, ok := item[method]
+			if !ok || !selected[method] {
+				continue
+			}
+
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+
+			endpoint, err := spec.endpoint(path, method, op, shared)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Endpoints = append(cfg.Endpoints, endpoint)
+		}
+	}
+
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("no %s operations found in OpenAPI document", strings.ToUpper(strings.Join(methods, "/")))
+	}
+	return cfg, nil
+}
+
+// serverURL returns the absolute base URL declared by the spec.
+func (s *openAPISpec) serverURL() (string, error) {
+	var base string
+	switch {
+	case len(s.Servers) > 0:
+		server := s.Servers[0]
+		base = pathParam.ReplaceAllStringFunc(server.URL, func(match string) string {
+			if variable, ok := server.Variables[strings.Trim(match, "{}")]; ok {
+				return variable.Default
+			}
+			return match
+		})
+	case s.Host != "":
+		scheme := "https"
+		if len(s.Schemes) > 0 {
+			scheme = s.Schemes[0]
+		}
+		base = scheme + "://" + s.Host + s.BasePath
+	}
+
+	if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+		if base == "" {
+			return "", fmt.Errorf("no server defined in OpenAPI document; pass a base URL with --server")
+		}
+		return "", fmt.Errorf("server '%s' is not an absolute URL; pass a base URL with --server", base)
+	}
+	return base, nil
+}
+
+// endpoint converts one operation into a batch endpoint.
+func (s *openAPISpec) endpoint(path, method string, op openAPIOperation, shared []openAPIParameter) (config.Endpoint, error) {
+	name := op.OperationID
+	if name == "" {
+		name = op.Summary
+	}
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+
+	endpoint := config.Endpoint{
+		Name:           name,
+		Method:         strings.ToUpper(method),
+		ExpectedStatus: expectedStatus(op.Responses),
+	}
+
+	params, err := s.parameters(shared, op.Parameters)
+	if err != nil {
+		return endpoint, fmt.Errorf("operation '%s': %w", name, err)
+	}
+
+	pathValues := make(map[string]string)
+	for _, param := range params {
+		value, ok := param.value()
+		switch param.In {
+		case "path":
+			if ok {
+				pathValues[param.Name] = url.PathEscape(value)
+			}
+		case "query":
+			if ok {
+				if endpoint.Params == nil {
+					endpoint.Params = make(map[string]string)
+				}
+				endpoint.Params[param.Name] = value
+			}
+		case "header":
+			if ok {
+				if endpoint.Headers == nil {
+					endpoint.Headers = make(map[string]string)
+				}
+				endpoint.Headers[param.Name] = value
+			}
+		}
+	}
+
+	endpoint.URL = "{{" + BaseVariable + "}}" + pathParam.ReplaceAllStringFunc(path, func(match string) string {
+		name := strings.Trim(match, "{}")
+		if value, ok := pathValues[name]; ok {
+			return value
+		}
+		return "{{" + name + "}}"
+	})
+
+	if body, contentType := requestExample(op); body != "" {
+		endpoint.Body = body
+		if endpoint.Headers == nil {
+			endpoint.Headers = make(map[string]string)
+		}
+		endpoint.Headers["Content-Type"] = contentType
+	}
+
+	return endpoint, nil
+}
+
+// parameters resolves $refs and merges path-level parameters with the
+// operation's own, which override them by name and location.
+func (s *openAPISpec) parameters(shared, own []openAPIParameter) ([]openAPIParameter, error) {
+	var merged []openAPIParameter
+	index := make(map[string]int)
+
+	for _, param := range append(append([]openAPIParameter{}, shared...), own...) {
+		resolved, err := s.resolve(param)
+		if err != nil {
+			return nil, err
+		}
+
+		key := resolved.In + ":" + resolved.Name
+		if i, ok := index[key]; ok {
+			merged[i] = resolved
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, resolved)
+	}
+	return merged, nil
+}
+
+// resolve follows a local parameter $ref.
+func (s *openAPISpec) resolve(param openAPIParameter) (openAPIParameter, error) {
+	if param.Ref == "" {
+		return param, nil
+	}
+
+	var defs map[string]openAPIParameter
+	var name string
+	switch {
+	case strings.HasPrefix(param.Ref, "#/components/parameters/"):
+		defs, name = s.Components.Parameters, strings.TrimPrefix(param.Ref, "#/components/parameters/")
+	case strings.HasPrefix(param.Ref, "#/parameters/"):
+		defs, name = s.Parameters, strings.TrimPrefix(param.Ref, "#/parameters/")
+	default:
+		return param, fmt.Errorf("unsupported parameter reference '%s'", param.Ref)
+	}
+
+	resolved, ok := defs[name]
+	if !ok {
+		return param, fmt.Errorf("parameter reference '%s' not found", param.Ref)
+	}
+	return resolved, nil
+}
+
+// value returns the parameter's example (or default) as a string.
+func (p openAPIParameter) value() (string, bool) {
+	for _, candidate := range []interface{}{p.Example, p.XExample, p.Schema.Example, p.Default, p.Schema.Default} {
+		if value, ok := scalar(candidate); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// expectedStatus returns the lowest documented 2xx status, or 200.
+func expectedStatus(responses map[string]yaml.Node) int {
+	status := 0
+	for code := range responses {
+		n, err := strconv.Atoi(code)
+		if err != nil || n < 200 || n > 299 {
+			continue
+		}
+		if status == 0 || n < status {
+			status = n
+		}
+	}
+	if status == 0 {
+		return 200
+	}
+	return status
+}
+
+// requestExample returns the example request body of an operation,
+// preferring JSON, along with its content type.
+func requestExample(op openAPIOperation) (string, string) {
+	types := make([]string, 0, len(op.RequestBody.Content))
+	for contentType := range op.RequestBody.Content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+
+	for _, contentType := range types {
+		example := op.RequestBody.Content[contentType].Example
+		if example == nil {
+			continue
+		}
+		if value, ok := scalar(example); ok {
+			return value, contentType
+		}
+		if strings.Contains(contentType, "json") {
+			if body, err := toJSON(example); err == nil {
+				return body, contentType
+			}
+		}
+	}
+	return "", ""
+}
+
+// scalar formats a YAML scalar value; maps and lists are rejected.
+func scalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return "", false
+	case string:
+		return v, true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const petstore = `openapi: 3.0.0
+servers:
+  - url: https://{region}.example.com/v1/
+    variables:
+      region:
+        default: eu
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      schema:
+        example: 10
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        "200": {description: ok}
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            example: {name: rex}
+      responses:
+        "201": {description: created}
+        "400": {description: invalid}
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        example: 42
+    get:
+      summary: Show a pet
+      parameters:
+        - name: X-Tenant
+          in: header
+          schema:
+            default: acme
+      responses:
+        "2XX": {description: ok}
+  /owners/{ownerId}:
+    get:
+      responses:
+        "204": {description: ok}
+`
+
+func TestOpenAPI(t *testing.T) {
+	cfg, err := OpenAPI([]byte(petstore), OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+
+	if cfg.Variables[BaseVariable] != "https://eu.example.com/v1" {
+		t.Errorf("base = %s, want https://eu.example.com/v1", cfg.Variables[BaseVariable])
+	}
+
+	byName := make(map[string]config.Endpoint)
+	for _, endpoint := range cfg.Endpoints {
+		byName[endpoint.Name] = endpoint
+	}
+	if len(byName) != 3 {
+		t.Fatalf("got %d endpoints, want the 3 GET operations", len(byName))
+	}
+
+	list := byName["listPets"]
+	if list.URL != "{{base}}/pets" || list.Params["limit"] != "10" || list.ExpectedStatus != 200 {
+		t.Errorf("listPets = %+v", list)
+	}
+
+	pet := byName["Show a pet"]
+	if pet.URL != "{{base}}/pets/42" || pet.Headers["X-Tenant"] != "acme" {
+		t.Errorf("Show a pet = %+v", pet)
+	}
+
+	owner := byName["GET /owners/{ownerId}"]
+	if owner.URL != "{{base}}/owners/{{ownerId}}" || owner.ExpectedStatus != 204 {
+		t.Errorf("GET /owners/{ownerId} = %+v", owner)
+	}
+}
+
+func TestOpenAPIMethods(t *testing.T) {
+	cfg, err := OpenAPI([]byte(petstore), OpenAPIOptions{Methods: []string{"post"}, Server: "http://localhost:8080"})
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+
+	if len(cfg.Endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(cfg.Endpoints))
+	}
+	create := cfg.Endpoints[0]
+	if create.Method != "POST" || create.ExpectedStatus != 201 {
+		t.Errorf("createPet = %+v", create)
+	}
+	if create.Body != `{"name":"rex"}` || create.Headers["Content-Type"] != "application/json" {
+		t.Errorf("createPet body = %s, headers = %v", create.Body, create.Headers)
+	}
+	if cfg.Variables[BaseVariable] != "http://localhost:8080" {
+		t.Errorf("base = %s, want --server value", cfg.Variables[BaseVariable])
+	}
+}
+
+func TestOpenAPISwagger2(t *testing.T) {
+	spec := `{"swagger": "2.0", "host": "api.example.com", "basePath": "/v2", "schemes": ["http"],
+"paths": {"/health": {"get": {"responses": {"200": {"description": "ok"}}}}}}`
+
+	cfg, err := OpenAPI([]byte(spec), OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+	if cfg.Variables[BaseVariable] != "http://api.example.com/v2" {
+		t.Errorf("base = %s, want http://api.example.com/v2", cfg.Variables[BaseVariable])
+	}
+}
+
+func TestOpenAPIErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{"not a spec", "endpoints: []\n", "not an OpenAPI document"},
+		{"no server", "openapi: 3.0.0\npaths: {}\n", "no server defined"},
+		{"relative server", "openapi: 3.0.0\nservers: [{url: /v1}]\npaths: {}\n", "not an absolute URL"},
+		{"no operations", "openapi: 3.0.0\nservers: [{url: 'https://x.io'}]\npaths: {}\n", "no GET operations"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OpenAPI([]byte(tt.spec), OpenAPIOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("OpenAPI() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	cfg, err := OpenAPI([]byte(petstore), OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+
+	data, err := Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "variables:\n") {
+		t.Errorf("Marshal() should list variables first:\n%s", data)
+	}
+	if strings.Contains(string(data), "save_body") {
+		t.Errorf("Marshal() should leave out unset fields:\n%s", data)
+	}
+
+	var decoded config.BatchConfig
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Marshal() output does not parse: %v", err)
+	}
+	if len(decoded.Endpoints) != len(cfg.Endpoints) {
+		t.Errorf("round trip has %d endpoints, want %d", len(decoded.Endpoints), len(cfg.Endpoints))
+	}
+}
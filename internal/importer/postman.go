@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// postmanCollection is a Postman collection (schema v2.0/v2.1).
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+}
+
+// postmanItem is either a folder (Item set) or a request.
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request *postmanRequest `json:"request"`
+	Auth    *postmanAuth    `json:"auth"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanKV     `json:"header"`
+	URL    json.RawMessage `json:"url"` // A string or a structured URL
+	Body   *struct {
+		Mode       string      `json:"mode"`
+		Raw        string      `json:"raw"`
+		URLEncoded []postmanKV `json:"urlencoded"`
+		Options    struct {
+			Raw struct {
+				Language string `json:"language"`
+			} `json:"raw"`
+		} `json:"options"`
+	} `json:"body"`
+	Auth *postmanAuth `json:"auth"`
+}
+
+type postmanURL struct {
+	Raw      string      `json:"raw"`
+	Protocol string      `json:"protocol"`
+	Host     []string    `json:"host"`
+	Port     string      `json:"port"`
+	Path     []string    `json:"path"`
+	Query    []postmanKV `json:"query"`
+}
+
+type postmanKV struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+type postmanVariable struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled *bool  `json:"enabled"` // Environments only; nil means enabled
+}
+
+type postmanAuth struct {
+	Type   string      `json:"type"`
+	Bearer []postmanKV `json:"bearer"`
+	Basic  []postmanKV `json:"basic"`
+}
+
+// postmanEnvironment is an exported Postman environment.
+type postmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []postmanVariable `json:"values"`
+}
+
+// Postman builds a batch config from a Postman collection (v2.0 or v2.1)
+// and, optionally, an exported environment whose values override the
+// collection variables. Postman's {{name}} syntax matches tapr templates,
+// so variables are carried over as batch variables. Requests in folders are
+// named "Folder / Request". Bearer and basic auth become Authorization
+// headers; form-data bodies are not converted.
+func Postman(collection, environment []byte) (*config.BatchConfig, error) {
+	var c postmanCollection
+	if err := json.Unmarshal(collection, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse Postman collection: %w", err)
+	}
+	if !strings.Contains(c.Info.Schema, "getpostman.com") {
+		return nil, fmt.Errorf("not a Postman v2 collection (missing info.schema)")
+	}
+
+	cfg := &config.BatchConfig{}
+	addVariables(cfg, c.Variable)
+
+	if len(environment) > 0 {
+		var env postmanEnvironment
+		if err := json.Unmarshal(environment, &env); err != nil {
+			return nil, fmt.Errorf("failed to parse Postman environment: %w", err)
+		}
+		addVariables(cfg, env.Values)
+	}
+
+	if err := collectItems(cfg, c.Item, "", c.Auth); err != nil {
+		return nil, err
+	}
+	if len(cfg.Endpoints) == 0 {
+		return nil, fmt.Errorf("no requests found in Postman collection")
+	}
+	return cfg, nil
+}
+
+// addVariables copies enabled Postman variables into the batch variables.
+func addVariables(cfg *config.BatchConfig, vars []postmanVariable) {
+	for _, v := range vars {
+		if v.Key == "" || (v.Enabled != nil && !*v.Enabled) {
+			continue
+		}
+		if cfg.Variables == nil {
+			cfg.Variables = make(map[string]string)
+		}
+		cfg.Variables[v.Key] = v.Value
+	}
+}
+
+// collectItems walks folders depth-first, converting every request.
+// Auth is inherited from the closest folder (or the collection) that sets it.
+func collectItems(cfg *config.BatchConfig, items []postmanItem, prefix string, auth *postmanAuth) error {
+	for _, item := range items {
+		name := item.Name
+		if prefix != "" {
+			name = prefix + " / " + item.Name
+		}
+
+		inherited := auth
+		if item.Auth != nil {
+			inherited = item.Auth
+		}
+
+		if item.Request == nil {
+			if err := collectItems(cfg, item.Item, name, inherited); err != nil {
+				return err
+			}
+			continue
+		}
+
+		endpoint, err := postmanEndpoint(name, item.Request, inherited, cfg.Variables)
+		if err != nil {
+			return err
+		}
+		cfg.Endpoints = append(cfg.Endpoints, endpoint)
+	}
+	return nil
+}
+
+// postmanEndpoint converts a single Postman request.
+func postmanEndpoint(name string, req *postmanRequest, auth *postmanAuth, vars map[string]string) (config.Endpoint, error) {
+	endpoint := config.Endpoint{
+		Name:   name,
+		Method: strings.ToUpper(req.Method),
+	}
+	if endpoint.Method == "" {
+		endpoint.Method = "GET"
+	}
+
+	var err error
+	if endpoint.URL, err = postmanRequestURL(req.URL); err != nil {
+		return endpoint, fmt.Errorf("request '%s': %w", name, err)
+	}
+
+	headers := make(map[string]string)
+	for _, header := range req.Header {
+		if !header.Disabled && header.Key != "" {
+			headers[header.Key] = header.Value
+		}
+	}
+
+	if req.Auth != nil {
+		auth = req.Auth
+	}
+	if value := authorization(auth, vars); value != "" && !hasHeader(headers, "Authorization") {
+		headers["Authorization"] = value
+	}
+
+	if req.Body != nil {
+		switch req.Body.Mode {
+		case "raw":
+			endpoint.Body = req.Body.Raw
+			if req.Body.Options.Raw.Language == "json" && !hasHeader(headers, "Content-Type") {
+				headers["Content-Type"] = "application/json"
+			}
+		case "urlencoded":
+			form := url.Values{}
+			for _, field := range req.Body.URLEncoded {
+				if !field.Disabled {
+					form.Add(field.Key, field.Value)
+				}
+			}
+			endpoint.Body = form.Encode()
+			if !hasHeader(headers, "Content-Type") {
+				headers["Content-Type"] = "application/x-www-form-urlencoded"
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		endpoint.Headers = headers
+	}
+	return endpoint, nil
+}
+
+// postmanRequestURL returns the request URL, which Postman stores either as
+// a string or as an object with a raw form and its parsed parts.
+func postmanRequestURL(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return "", fmt.Errorf("empty URL")
+		}
+		return s, nil
+	}
+
+	var u postmanURL
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Raw != "" {
+		return u.Raw, nil
+	}
+	if len(u.Host) == 0 {
+		return "", fmt.Errorf("empty URL")
+	}
+
+	built := strings.Join(u.Host, ".")
+	if u.Protocol != "" {
+		built = u.Protocol + "://" + built
+	}
+	if u.Port != "" {
+		built += ":" + u.Port
+	}
+	if len(u.Path) > 0 {
+		built += "/" + strings.Join(u.Path, "/")
+	}
+
+	var query []string
+	for _, q := range u.Query {
+		if !q.Disabled {
+			query = append(query, q.Key+"="+q.Value)
+		}
+	}
+	if len(query) > 0 {
+		built += "?" + strings.Join(query, "&")
+	}
+	return built, nil
+}
+
+// authorization returns the Authorization header value for bearer or basic
+// auth, or "" for other (or no) auth types. Basic credentials are encoded
+// at import time, so their templates are expanded from vars first.
+func authorization(auth *postmanAuth, vars map[string]string) string {
+	if auth == nil {
+		return ""
+	}
+
+	switch auth.Type {
+	case "bearer":
+		if token := lookupKV(auth.Bearer, "token"); token != "" {
+			return "Bearer " + token
+		}
+	case "basic":
+		user, userErr := config.RenderTemplate(lookupKV(auth.Basic, "username"), vars)
+		pass, passErr := config.RenderTemplate(lookupKV(auth.Basic, "password"), vars)
+		if user != "" && userErr == nil && passErr == nil {
+			return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+		}
+	}
+	return ""
+}
+
+// lookupKV returns the value stored under key.
+func lookupKV(pairs []postmanKV, key string) string {
+	for _, pair := range pairs {
+		if pair.Key == key {
+			return pair.Value
+		}
+	}
+	return ""
+}
+
+// hasHeader reports whether headers contains name, ignoring case.
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const collection = `{
+  "info": {"name": "Shop", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "variable": [{"key": "baseUrl", "value": "https://dev.example.com"}, {"key": "user", "value": "alice"}],
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]},
+  "item": [
+    {
+      "name": "Orders",
+      "item": [
+        {
+          "name": "List orders",
+          "request": {
+            "method": "GET",
+            "header": [{"key": "Accept", "value": "application/json"}, {"key": "X-Debug", "value": "1", "disabled": true}],
+            "url": {"raw": "{{baseUrl}}/orders?page=1", "host": ["{{baseUrl}}"], "path": ["orders"]}
+          }
+        },
+        {
+          "name": "Create order",
+          "request": {
+            "method": "POST",
+            "url": "{{baseUrl}}/orders",
+            "body": {"mode": "raw", "raw": "{\"sku\": \"A1\"}", "options": {"raw": {"language": "json"}}}
+          }
+        }
+      ]
+    },
+    {
+      "name": "Login",
+      "request": {
+        "method": "POST",
+        "auth": {"type": "basic", "basic": [{"key": "username", "value": "{{user}}"}, {"key": "password", "value": "secret"}]},
+        "url": {"protocol": "https", "host": ["auth", "example", "com"], "path": ["login"], "query": [{"key": "v", "value": "2"}]},
+        "body": {"mode": "urlencoded", "urlencoded": [{"key": "scope", "value": "read write"}]}
+      }
+    }
+  ]
+}`
+
+func TestPostman(t *testing.T) {
+	cfg, err := Postman([]byte(collection), nil)
+	if err != nil {
+		t.Fatalf("Postman() error = %v", err)
+	}
+
+	if len(cfg.Endpoints) != 3 {
+		t.Fatalf("got %d endpoints, want 3", len(cfg.Endpoints))
+	}
+	if cfg.Variables["baseUrl"] != "https://dev.example.com" {
+		t.Errorf("Variables[baseUrl] = %s", cfg.Variables["baseUrl"])
+	}
+
+	list := cfg.Endpoints[0]
+	if list.Name != "Orders / List orders" || list.URL != "{{baseUrl}}/orders?page=1" {
+		t.Errorf("List orders = %+v", list)
+	}
+	if list.Headers["Accept"] != "application/json" || list.Headers["X-Debug"] != "" {
+		t.Errorf("List orders headers = %v, want Accept only", list.Headers)
+	}
+	if list.Headers["Authorization"] != "Bearer {{token}}" {
+		t.Errorf("Authorization = %s, want collection bearer auth", list.Headers["Authorization"])
+	}
+
+	create := cfg.Endpoints[1]
+	if create.Method != "POST" || create.Body != `{"sku": "A1"}` || create.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Create order = %+v", create)
+	}
+
+	login := cfg.Endpoints[2]
+	if login.URL != "https://auth.example.com/login?v=2" {
+		t.Errorf("Login URL = %s", login.URL)
+	}
+	if login.Headers["Authorization"] != "Basic YWxpY2U6c2VjcmV0" {
+		t.Errorf("Login Authorization = %s, want basic auth for alice:secret", login.Headers["Authorization"])
+	}
+	if login.Body != "scope=read+write" || login.Headers["Content-Type"] != "application/x-www-form-urlencoded" {
+		t.Errorf("Login body = %s, headers = %v", login.Body, login.Headers)
+	}
+}
+
+func TestPostmanEnvironment(t *testing.T) {
+	env := `{"name": "Prod", "values": [
+  {"key": "baseUrl", "value": "https://api.example.com", "enabled": true},
+  {"key": "token", "value": "t0k3n"},
+  {"key": "unused", "value": "x", "enabled": false}
+]}`
+
+	cfg, err := Postman([]byte(collection), []byte(env))
+	if err != nil {
+		t.Fatalf("Postman() error = %v", err)
+	}
+
+	if cfg.Variables["baseUrl"] != "https://api.example.com" {
+		t.Errorf("Variables[baseUrl] = %s, want environment value", cfg.Variables["baseUrl"])
+	}
+	if cfg.Variables["token"] != "t0k3n" {
+		t.Errorf("Variables[token] = %s, want t0k3n", cfg.Variables["token"])
+	}
+	if _, ok := cfg.Variables["unused"]; ok {
+		t.Error("disabled environment values should be skipped")
+	}
+}
+
+func TestPostmanErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection string
+		wantErr    string
+	}{
+		{"invalid JSON", "{", "failed to parse"},
+		{"not a collection", `{"info": {"name": "x"}}`, "not a Postman v2 collection"},
+		{"no requests", `{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/"}, "item": []}`, "no requests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Postman([]byte(tt.collection), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Postman() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
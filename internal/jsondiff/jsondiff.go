@@ -0,0 +1,158 @@
+// Package jsondiff compares JSON documents structurally, reporting the
+// paths at which two documents differ rather than a line-based diff.
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change kinds reported in a Difference.
+const (
+	Added   = "added"   // Present only in the right document
+	Removed = "removed" // Present only in the left document
+	Changed = "changed" // Present in both with different values
+)
+
+// Difference describes one point at which two JSON documents differ.
+type Difference struct {
+	Path  string // JSONPath-style location (e.g., "$.data.items[2]")
+	Kind  string // Added, Removed or Changed
+	Left  string // Compact JSON of the left value ("" when added)
+	Right string // Compact JSON of the right value ("" when removed)
+}
+
+// Compare decodes two JSON documents and returns their differences,
+// ordered by path. Object key order and whitespace are ignored.
+//
+// Example:
+//
+//	diffs, err := jsondiff.Compare([]byte(`{"v":1}`), []byte(`{"v":2}`))
+//	// diffs[0] == Difference{Path: "$.v", Kind: "changed", Left: "1", Right: "2"}
+func Compare(left, right []byte) ([]Difference, error) {
+	a, err := decode(left)
+	if err != nil {
+		return nil, fmt.Errorf("left body is not valid JSON: %w", err)
+	}
+	b, err := decode(right)
+	if err != nil {
+		return nil, fmt.Errorf("right body is not valid JSON: %w", err)
+	}
+
+	var diffs []Difference
+	compare("$", a, b, &diffs)
+	return diffs, nil
+}
+
+// decode parses data keeping numbers exact.
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// compare walks a and b in parallel, appending differences found under path.
+func compare(path string, a, b interface{}, diffs *[]Difference) {
+	switch left := a.(type) {
+	case map[string]interface{}:
+		if right, ok := b.(map[string]interface{}); ok {
+			compareObjects(path, left, right, diffs)
+			return
+		}
+	case []interface{}:
+		if right, ok := b.([]interface{}); ok {
+			compareArrays(path, left, right, diffs)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, Difference{Path: path, Kind: Changed, Left: encode(a), Right: encode(b)})
+	}
+}
+
+// compareObjects compares two objects key by key, in sorted key order.
+func compareObjects(path string, a, b map[string]interface{}, diffs *[]Difference) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := childPath(path, key)
+		left, inLeft := a[key]
+		right, inRight := b[key]
+
+		switch {
+		case !inRight:
+			*diffs = append(*diffs, Difference{Path: child, Kind: Removed, Left: encode(left)})
+		case !inLeft:
+			*diffs = append(*diffs, Difference{Path: child, Kind: Added, Right: encode(right)})
+		default:
+			compare(child, left, right, diffs)
+		}
+	}
+}
+
+// compareArrays compares two arrays element by element.
+func compareArrays(path string, a, b []interface{}, diffs *[]Difference) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		child := fmt.Sprintf("%s[%d]", path, i)
+
+		switch {
+		case i >= len(b):
+			*diffs = append(*diffs, Difference{Path: child, Kind: Removed, Left: encode(a[i])})
+		case i >= len(a):
+			*diffs = append(*diffs, Difference{Path: child, Kind: Added, Right: encode(b[i])})
+		default:
+			compare(child, a[i], b[i], diffs)
+		}
+	}
+}
+
+// childPath appends key to path, quoting keys that are not plain identifiers.
+func childPath(path, key string) string {
+	if isIdentifier(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// isIdentifier reports whether key can be written in dot notation.
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		digit := r >= '0' && r <= '9'
+		if !letter && !(digit && i > 0) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// encode renders a decoded value as compact JSON.
+func encode(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package jsondiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		want  []Difference
+	}{
+		{
+			name:  "identical with different key order",
+			left:  `{"a": 1, "b": [1, 2]}`,
+			right: `{"b": [1, 2], "a": 1}`,
+			want:  nil,
+		},
+		{
+			name:  "changed value",
+			left:  `{"version": "1.2.3"}`,
+			right: `{"version": "1.3.0"}`,
+			want:  []Difference{{Path: "$.version", Kind: Changed, Left: `"1.2.3"`, Right: `"1.3.0"`}},
+		},
+		{
+			name:  "added and removed keys",
+			left:  `{"old": true, "same": 1}`,
+			right: `{"new": true, "same": 1}`,
+			want: []Difference{
+				{Path: "$.new", Kind: Added, Right: "true"},
+				{Path: "$.old", Kind: Removed, Left: "true"},
+			},
+		},
+		{
+			name:  "array length and nested values",
+			left:  `{"data": {"items": [1, 2, 3]}}`,
+			right: `{"data": {"items": [1, 5]}}`,
+			want: []Difference{
+				{Path: "$.data.items[1]", Kind: Changed, Left: "2", Right: "5"},
+				{Path: "$.data.items[2]", Kind: Removed, Left: "3"},
+			},
+		},
+		{
+			name:  "type change",
+			left:  `{"id": 1}`,
+			right: `{"id": "1"}`,
+			want:  []Difference{{Path: "$.id", Kind: Changed, Left: "1", Right: `"1"`}},
+		},
+		{
+			name:  "quoted keys",
+			left:  `{"a b": 1}`,
+			right: `{"a b": 2}`,
+			want:  []Difference{{Path: `$["a b"]`, Kind: Changed, Left: "1", Right: "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare([]byte(tt.left), []byte(tt.right))
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareInvalidJSON(t *testing.T) {
+	if _, err := Compare([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Error("Compare() error = nil, want error for invalid JSON")
+	}
+}
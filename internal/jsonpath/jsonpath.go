@@ -0,0 +1,292 @@
+// Package jsonpath extracts values from JSON documents using a small,
+// dependency-free subset of JSONPath and jq path syntax.
+//
+// Supported syntax:
+//
+//	$.data.version      JSONPath-style (leading $ is optional)
+//	.data.version       jq-style
+//	.items[0].id        array index (negative indexes count from the end)
+//	.items[*].id        wildcard over array elements or object values ([] also works)
+//	.["key.with.dots"]  bracket notation for keys with special characters
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// segment is a single step in a path.
+type segment struct {
+	key      string // Object key (when index is not set)
+	index    int    // Array index
+	isIndex  bool   // Whether this segment is an array index
+	wildcard bool   // Whether this segment matches all elements
+}
+
+// Path is a compiled path expression.
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+// String returns the original expression.
+func (p Path) String() string {
+	return p.expr
+}
+
+// Parse compiles a path expression.
+func Parse(expr string) (Path, error) {
+	path := Path{expr: expr}
+
+	rest := strings.TrimSpace(expr)
+	rest = strings.TrimPrefix(rest, "$")
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '[' {
+				continue // "." alone or ".[" - bracket follows
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "*" {
+				path.segments = append(path.segments, segment{wildcard: true})
+			} else {
+				path.segments = append(path.segments, segment{key: key})
+			}
+			rest = rest[end:]
+
+		case '[':
+			end := closingBracket(rest)
+			if end == -1 {
+				return Path{}, fmt.Errorf("invalid path %q: missing ']'", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			seg, err := parseBracket(inner)
+			if err != nil {
+				return Path{}, fmt.Errorf("invalid path %q: %w", expr, err)
+			}
+			path.segments = append(path.segments, seg)
+
+		default:
+			return Path{}, fmt.Errorf("invalid path %q: unexpected %q", expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// closingBracket returns the index of the ']' closing the bracket at s[0],
+// skipping over quoted keys.
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote == 0 && (s[i] == '"' || s[i] == '\''):
+			quote = s[i]
+		case quote == 0 && s[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseBracket parses the contents of a [...] segment.
+func parseBracket(inner string) (segment, error) {
+	if inner == "" || inner == "*" {
+		return segment{wildcard: true}, nil
+	}
+
+	// Quoted key
+	if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+		return segment{key: inner[1 : len(inner)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return segment{}, fmt.Errorf("invalid index %q", inner)
+	}
+	return segment{index: index, isIndex: true}, nil
+}
+
+// Eval evaluates the path against a decoded JSON document. Paths with a
+// wildcard return a []interface{} with one entry per match.
+func (p Path) Eval(doc interface{}) (interface{}, error) {
+	return eval(doc, p.segments, "$")
+}
+
+func eval(value interface{}, segments []segment, at string) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	seg := segments[0]
+	rest := segments[1:]
+
+	if seg.wildcard {
+		var children []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			children = v
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				children = append(children, v[key])
+			}
+		default:
+			return nil, fmt.Errorf("cannot iterate over %s at %s", typeName(value), at)
+		}
+
+		results := make([]interface{}, 0, len(children))
+		for i, child := range children {
+			result, err := eval(child, rest, fmt.Sprintf("%s[%d]", at, i))
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	}
+
+	if seg.isIndex {
+		arr, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot index %s at %s", typeName(value), at)
+		}
+		index := seg.index
+		if index < 0 {
+			index += len(arr)
+		}
+		if index < 0 || index >= len(arr) {
+			return nil, fmt.Errorf("index %d out of range at %s (length %d)", seg.index, at, len(arr))
+		}
+		return eval(arr[index], rest, fmt.Sprintf("%s[%d]", at, seg.index))
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot get key '%s' from %s at %s", seg.key, typeName(value), at)
+	}
+	child, ok := obj[seg.key]
+	if !ok {
+		return nil, fmt.Errorf("key '%s' not found at %s", seg.key, at)
+	}
+	return eval(child, rest, at+"."+seg.key)
+}
+
+// Set replaces every value the path matches in a decoded JSON document
+// with value and returns the updated document. Parts of the path that do not
+// exist are left alone rather than created.
+func (p Path) Set(doc, value interface{}) interface{} {
+	return set(doc, p.segments, value)
+}
+
+func set(current interface{}, segments []segment, value interface{}) interface{} {
+	if len(segments) == 0 {
+		return value
+	}
+
+	seg := segments[0]
+	rest := segments[1:]
+
+	switch v := current.(type) {
+	case map[string]interface{}:
+		if seg.wildcard {
+			for key, child := range v {
+				v[key] = set(child, rest, value)
+			}
+		} else if child, ok := v[seg.key]; ok && !seg.isIndex {
+			v[seg.key] = set(child, rest, value)
+		}
+	case []interface{}:
+		if seg.wildcard {
+			for i, child := range v {
+				v[i] = set(child, rest, value)
+			}
+		} else if seg.isIndex {
+			index := seg.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index >= 0 && index < len(v) {
+				v[index] = set(v[index], rest, value)
+			}
+		}
+	}
+	return current
+}
+
+// typeName returns the JSON type name of a decoded value.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// Extract decodes a JSON body and evaluates the path expression against it.
+//
+// Example:
+//
+//	version, err := jsonpath.Extract(body, "$.data.version")
+func Extract(body []byte, expr string) (interface{}, error) {
+	path, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+
+	return path.Eval(doc)
+}
+
+// Format renders an extracted value for display and comparison. Strings are
+// returned without quotes; everything else is rendered as compact JSON.
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,98 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testBody = `{
+  "status": "ok",
+  "data": {
+    "version": "1.2.3",
+    "count": 3,
+    "enabled": true,
+    "items": [{"id": 1}, {"id": 2}, {"id": 3}]
+  },
+  "weird.key": "dotted"
+}`
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{"jq style", ".status", "ok", false},
+		{"jsonpath style", "$.data.version", "1.2.3", false},
+		{"number", ".data.count", "3", false},
+		{"boolean", ".data.enabled", "true", false},
+		{"array index", ".data.items[1].id", "2", false},
+		{"negative index", ".data.items[-1].id", "3", false},
+		{"wildcard", ".data.items[*].id", "[1,2,3]", false},
+		{"jq iterator", ".data.items[].id", "[1,2,3]", false},
+		{"bracket key", `.["weird.key"]`, "dotted", false},
+		{"single quoted key", `$['data']['version']`, "1.2.3", false},
+		{"identity", ".", "", false},
+		{"missing key", ".data.missing", "", true},
+		{"index out of range", ".data.items[5]", "", true},
+		{"index non-array", ".status[0]", "", true},
+		{"invalid syntax", ".data[", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := Extract([]byte(testBody), tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if tt.wantErr || tt.want == "" {
+				return
+			}
+			if got := Format(value); got != tt.want {
+				t.Errorf("Extract(%q) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_InvalidJSON(t *testing.T) {
+	if _, err := Extract([]byte("<html>"), ".status"); err == nil {
+		t.Error("Extract() expected error for non-JSON body")
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$.status", `"status":"x"`},
+		{"$.data.items[*].id", `"items":[{"id":"x"},{"id":"x"},{"id":"x"}]`},
+		{"$.data.items[-1].id", `"items":[{"id":1},{"id":2},{"id":"x"}]`},
+		{`$.["weird.key"]`, `"weird.key":"x"`},
+		{"$.data.missing.id", `"status":"ok"`}, // Missing paths are not created
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var doc interface{}
+			if err := json.Unmarshal([]byte(testBody), &doc); err != nil {
+				t.Fatal(err)
+			}
+			path, err := Parse(tt.path)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got, _ := json.Marshal(path.Set(doc, "x"))
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("Set() = %s, want containing %s", got, tt.want)
+			}
+			if strings.Contains(string(got), "missing") {
+				t.Errorf("Set() = %s, created a missing key", got)
+			}
+		})
+	}
+}
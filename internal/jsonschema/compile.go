@@ -0,0 +1,480 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Cache loads and compiles schema files once, so that endpoints sharing a
+// schema, and repeated runs in watch or daemon mode, do not read and
+// compile it again. It is safe for concurrent use.
+type Cache struct {
+	mu    sync.Mutex
+	files map[string]*document
+}
+
+// document is a parsed schema file and the schemas compiled from it.
+type document struct {
+	path    string             // Absolute path ("" for schemas given as data)
+	root    interface{}        // Decoded JSON
+	schemas map[string]*Schema // Compiled schemas by JSON pointer ("" is the root)
+}
+
+// NewCache creates an empty schema cache.
+func NewCache() *Cache {
+	return &Cache{files: make(map[string]*document)}
+}
+
+// Load returns the compiled schema in the file at path, reading and
+// compiling it (and the files it refers to) on first use.
+func (c *Cache) Load(path string) (*Schema, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	doc, err := c.document(abs)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := c.compile(doc, "")
+	if err != nil {
+		// Drop partially compiled schemas so the error is reported again
+		c.files = make(map[string]*document)
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Compile compiles a schema from JSON data. References to other files are
+// resolved against the working directory.
+func Compile(data []byte) (*Schema, error) {
+	root, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	c := NewCache()
+	return c.compile(&document{root: root, schemas: make(map[string]*Schema)}, "")
+}
+
+// document returns the parsed schema file at the absolute path abs.
+func (c *Cache) document(abs string) (*document, error) {
+	if doc, ok := c.files[abs]; ok {
+		return doc, nil
+	}
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	root, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", abs, err)
+	}
+
+	doc := &document{path: abs, root: root, schemas: make(map[string]*Schema)}
+	c.files[abs] = doc
+	return doc, nil
+}
+
+// compile returns the schema at the JSON pointer in doc. Schemas are
+// registered before their keywords are compiled, so recursive references
+// resolve to the schema being built.
+func (c *Cache) compile(doc *document, pointer string) (*Schema, error) {
+	if schema, ok := doc.schemas[pointer]; ok {
+		return schema, nil
+	}
+
+	node, err := resolvePointer(doc.root, pointer)
+	if err != nil {
+		return nil, c.errorf(doc, pointer, "%v", err)
+	}
+
+	schema := &Schema{}
+	doc.schemas[pointer] = schema
+	if err := c.fill(schema, node, doc, pointer); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// locatedError is a schema error that already says where it is.
+type locatedError struct{ error }
+
+// errorf reports a problem with the schema at pointer in doc.
+func (c *Cache) errorf(doc *document, pointer, format string, args ...interface{}) error {
+	location := "#" + pointer
+	if doc.path != "" {
+		location = filepath.Base(doc.path) + location
+	}
+	return locatedError{fmt.Errorf("schema %s: %s", location, fmt.Sprintf(format, args...))}
+}
+
+// fill compiles the keywords of node into schema.
+func (c *Cache) fill(schema *Schema, node interface{}, doc *document, pointer string) error {
+	if always, ok := node.(bool); ok {
+		schema.always = &always
+		return nil
+	}
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return c.errorf(doc, pointer, "must be an object or a boolean")
+	}
+
+	// Compile keywords in a fixed order so errors are reproducible
+	for _, key := range sortedKeys(obj) {
+		if err := c.keyword(schema, key, obj[key], obj, doc, pointer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyword compiles a single keyword. Unknown keywords are ignored, as the
+// specification requires.
+func (c *Cache) keyword(schema *Schema, key string, value interface{}, obj map[string]interface{}, doc *document, pointer string) error {
+	at := pointer + "/" + escapePointer(key)
+	sub := func(path string) (*Schema, error) { return c.compile(doc, path) }
+	var err error
+
+	switch key {
+	case "$schema":
+		draft, _ := value.(string)
+		if !supportedDrafts[strings.TrimSuffix(draft, "#")] {
+			return c.errorf(doc, at, "unsupported $schema %q (draft-07 and 2020-12 are supported)", draft)
+		}
+
+	case "$ref":
+		ref, _ := value.(string)
+		schema.ref, err = c.resolveRef(doc, at, ref)
+
+	case "type":
+		schema.types, err = typeList(value)
+
+	case "enum":
+		values, ok := value.([]interface{})
+		if !ok {
+			return c.errorf(doc, at, "must be an array")
+		}
+		schema.enum = values
+
+	case "const":
+		schema.constant, schema.hasConst = value, true
+
+	case "properties", "patternProperties", "dependentSchemas":
+		props, ok := value.(map[string]interface{})
+		if !ok {
+			return c.errorf(doc, at, "must be an object")
+		}
+		for _, name := range sortedKeys(props) {
+			compiled, err := sub(at + "/" + escapePointer(name))
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "properties":
+				if schema.properties == nil {
+					schema.properties = make(map[string]*Schema)
+				}
+				schema.properties[name] = compiled
+			case "patternProperties":
+				pattern, err := regexp.Compile(name)
+				if err != nil {
+					return c.errorf(doc, at, "invalid pattern %q: %v", name, err)
+				}
+				schema.patternProperties = append(schema.patternProperties, patternSchema{pattern, compiled})
+			default:
+				if schema.dependentSchemas == nil {
+					schema.dependentSchemas = make(map[string]*Schema)
+				}
+				schema.dependentSchemas[name] = compiled
+			}
+		}
+
+	case "dependentRequired", "dependencies":
+		// draft-07 dependencies hold either property lists or schemas
+		deps, ok := value.(map[string]interface{})
+		if !ok {
+			return c.errorf(doc, at, "must be an object")
+		}
+		for _, name := range sortedKeys(deps) {
+			if list, isList := deps[name].([]interface{}); isList {
+				names, err := stringList(list)
+				if err != nil {
+					return c.errorf(doc, at, "%v", err)
+				}
+				if schema.dependentRequired == nil {
+					schema.dependentRequired = make(map[string][]string)
+				}
+				schema.dependentRequired[name] = names
+				continue
+			}
+			if key == "dependentRequired" {
+				return c.errorf(doc, at, "'%s' must be an array of property names", name)
+			}
+			compiled, err := sub(at + "/" + escapePointer(name))
+			if err != nil {
+				return err
+			}
+			if schema.dependentSchemas == nil {
+				schema.dependentSchemas = make(map[string]*Schema)
+			}
+			schema.dependentSchemas[name] = compiled
+		}
+
+	case "required":
+		list, ok := value.([]interface{})
+		if !ok {
+			return c.errorf(doc, at, "must be an array")
+		}
+		if schema.required, err = stringList(list); err != nil {
+			return c.errorf(doc, at, "%v", err)
+		}
+
+	case "additionalProperties":
+		schema.additionalProperties, err = sub(at)
+	case "propertyNames":
+		schema.propertyNames, err = sub(at)
+	case "contains":
+		schema.contains, err = sub(at)
+	case "not":
+		schema.not, err = sub(at)
+	case "if":
+		schema.ifS, err = sub(at)
+	case "then":
+		schema.thenS, err = sub(at)
+	case "else":
+		schema.elseS, err = sub(at)
+
+	case "items":
+		if _, isTuple := value.([]interface{}); isTuple {
+			// draft-07 tuple: items after these use additionalItems
+			schema.prefixItems, err = c.compileList(doc, at, value)
+			if err == nil {
+				if _, ok := obj["additionalItems"]; ok {
+					schema.items, err = sub(pointer + "/additionalItems")
+				}
+			}
+		} else {
+			schema.items, err = sub(at)
+		}
+	case "prefixItems":
+		schema.prefixItems, err = c.compileList(doc, at, value)
+
+	case "allOf":
+		schema.allOf, err = c.compileList(doc, at, value)
+	case "anyOf":
+		schema.anyOf, err = c.compileList(doc, at, value)
+	case "oneOf":
+		schema.oneOf, err = c.compileList(doc, at, value)
+
+	case "minProperties":
+		schema.minProperties, err = count(value)
+	case "maxProperties":
+		schema.maxProperties, err = count(value)
+	case "minItems":
+		schema.minItems, err = count(value)
+	case "maxItems":
+		schema.maxItems, err = count(value)
+	case "minLength":
+		schema.minLength, err = count(value)
+	case "maxLength":
+		schema.maxLength, err = count(value)
+
+	case "uniqueItems":
+		schema.uniqueItems, _ = value.(bool)
+
+	case "pattern":
+		text, _ := value.(string)
+		schema.pattern, err = regexp.Compile(text)
+
+	case "minimum":
+		schema.minimum, err = toNumber(value)
+	case "maximum":
+		schema.maximum, err = toNumber(value)
+	case "exclusiveMinimum":
+		schema.exclusiveMinimum, err = toNumber(value)
+	case "exclusiveMaximum":
+		schema.exclusiveMaximum, err = toNumber(value)
+	case "multipleOf":
+		schema.multipleOf, err = toNumber(value)
+		if err == nil && schema.multipleOf.value.Sign() <= 0 {
+			err = fmt.Errorf("must be greater than 0")
+		}
+	}
+
+	if err != nil {
+		if _, located := err.(locatedError); located {
+			return err
+		}
+		return c.errorf(doc, at, "%v", err)
+	}
+	return nil
+}
+
+// compileList compiles an array of schemas.
+func (c *Cache) compileList(doc *document, pointer string, value interface{}) ([]*Schema, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be an array of schemas")
+	}
+	schemas := make([]*Schema, len(list))
+	for i := range list {
+		schema, err := c.compile(doc, pointer+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = schema
+	}
+	return schemas, nil
+}
+
+// resolveRef compiles the schema a $ref points to. References are a file
+// (relative to the referring one), a JSON pointer fragment, or both.
+func (c *Cache) resolveRef(doc *document, pointer, ref string) (*Schema, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return nil, c.errorf(doc, pointer, "$ref %q: only JSON pointer fragments are supported", ref)
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, c.errorf(doc, pointer, "$ref %q: %v", ref, err)
+	}
+
+	target := doc
+	if file != "" {
+		if strings.Contains(file, "://") {
+			return nil, c.errorf(doc, pointer, "$ref %q: only local files can be referenced", ref)
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(doc.path), file)
+		}
+		if file, err = filepath.Abs(file); err != nil {
+			return nil, err
+		}
+		if target, err = c.document(file); err != nil {
+			return nil, c.errorf(doc, pointer, "$ref %q: %v", ref, err)
+		}
+	}
+	return c.compile(target, fragment)
+}
+
+// resolvePointer returns the value at a JSON pointer ("" is the root).
+func resolvePointer(root interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return root, nil
+	}
+
+	node := root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("'%s' not found", token)
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("invalid index '%s'", token)
+			}
+			node = v[index]
+		default:
+			return nil, fmt.Errorf("'%s' not found", token)
+		}
+	}
+	return node, nil
+}
+
+// escapePointer escapes a key for use in a JSON pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// typeList parses the type keyword.
+func typeList(value interface{}) ([]string, error) {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		var err error
+		if types, err = stringList(v); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("must be a string or an array of strings")
+	}
+
+	for _, name := range types {
+		switch name {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, fmt.Errorf("unknown type '%s'", name)
+		}
+	}
+	return types, nil
+}
+
+// stringList converts a decoded array of strings.
+func stringList(list []interface{}) ([]string, error) {
+	strs := make([]string, len(list))
+	for i, item := range list {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+		strs[i] = str
+	}
+	return strs, nil
+}
+
+// count parses a non-negative integer keyword.
+func count(value interface{}) (*int, error) {
+	num, ok := value.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("must be a non-negative integer")
+	}
+	n, err := strconv.Atoi(num.String())
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("must be a non-negative integer")
+	}
+	return &n, nil
+}
+
+// toNumber parses a numeric keyword.
+func toNumber(value interface{}) (*number, error) {
+	num, ok := value.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("must be a number")
+	}
+	rat, ok := new(big.Rat).SetString(num.String())
+	if !ok {
+		return nil, fmt.Errorf("must be a number")
+	}
+	return &number{value: rat, text: num.String()}, nil
+}
+
+// sortedKeys returns the keys of obj in sorted order.
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,137 @@
+// Package jsonschema validates JSON documents against JSON Schema, without
+// external dependencies.
+//
+// The commonly used keywords of draft-07 and 2020-12 are supported:
+//
+//	type, enum, const
+//	properties, required, additionalProperties, patternProperties,
+//	propertyNames, minProperties, maxProperties, dependentRequired
+//	items (schema, or array of schemas in draft-07), prefixItems,
+//	additionalItems, contains, minItems, maxItems, uniqueItems
+//	minLength, maxLength, pattern
+//	minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//	allOf, anyOf, oneOf, not, if/then/else
+//	$ref to "#", "#/definitions/...", "#/$defs/..." and to other files
+//	("common.json#/$defs/user"), resolved relative to the referring file
+//
+// format is treated as an annotation and not checked. Patterns use Go's
+// regexp syntax, which covers the ECMA-262 features schemas usually need.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+)
+
+// Supported $schema values (without the trailing "#"); schemas without
+// $schema are accepted too.
+var supportedDrafts = map[string]bool{
+	"http://json-schema.org/draft-07/schema":       true,
+	"https://json-schema.org/draft-07/schema":      true,
+	"http://json-schema.org/draft/2020-12/schema":  true,
+	"https://json-schema.org/draft/2020-12/schema": true,
+}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	always *bool // Set for the boolean schemas true and false
+	ref    *Schema
+
+	types    []string
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties           map[string]*Schema
+	patternProperties    []patternSchema
+	additionalProperties *Schema
+	propertyNames        *Schema
+	required             []string
+	dependentRequired    map[string][]string
+	dependentSchemas     map[string]*Schema
+	minProperties        *int
+	maxProperties        *int
+
+	prefixItems []*Schema
+	items       *Schema // Items after prefixItems
+	contains    *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	minimum          *number
+	maximum          *number
+	exclusiveMinimum *number
+	exclusiveMaximum *number
+	multipleOf       *number
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+	ifS   *Schema
+	thenS *Schema
+	elseS *Schema
+}
+
+// number is a numeric keyword value, kept exact.
+type number struct {
+	value *big.Rat
+	text  string // As written in the schema, for messages
+}
+
+// patternSchema is a patternProperties entry.
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *Schema
+}
+
+// Violation is a place where a document does not match its schema.
+type Violation struct {
+	Path    string // JSONPath-style location in the document (e.g., "$.users[0].id")
+	Message string // What is wrong (e.g., "expected integer, got string")
+}
+
+// String formats the violation as "path: message".
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Validate checks a decoded JSON document, as produced by Decode, and
+// returns its violations (none if it is valid).
+func (s *Schema) Validate(doc interface{}) []Violation {
+	var violations []Violation
+	s.validate(doc, "$", &violations)
+	return violations
+}
+
+// ValidateJSON decodes a JSON body and validates it.
+func (s *Schema) ValidateJSON(body []byte) ([]Violation, error) {
+	doc, err := Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(doc), nil
+}
+
+// Decode parses a JSON document, keeping numbers exact.
+func Decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the document")
+	}
+	return doc, nil
+}
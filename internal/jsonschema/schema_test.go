@@ -0,0 +1,205 @@
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		doc    string
+		want   []string // Violations as "path: message"; none if valid
+	}{
+		{
+			"valid object",
+			`{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}`,
+			`{"id": 7, "extra": true}`,
+			nil,
+		},
+		{
+			"type and required",
+			`{"type": "object", "required": ["id", "name"], "properties": {"id": {"type": "integer"}}}`,
+			`{"id": "7"}`,
+			[]string{"$: missing required property 'name'", "$.id: expected integer, got string"},
+		},
+		{
+			"integer accepts 1.0",
+			`{"type": "integer"}`,
+			`1.0`,
+			nil,
+		},
+		{
+			"nullable",
+			`{"type": ["string", "null"]}`,
+			`3`,
+			[]string{"$: expected string or null, got integer"},
+		},
+		{
+			"additional properties",
+			`{"properties": {"a": {}}, "patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`,
+			`{"a": 1, "x-trace": 2, "b": 3}`,
+			[]string{`$.b: unexpected property`, `$["x-trace"]: expected string, got integer`},
+		},
+		{
+			"arrays",
+			`{"type": "array", "items": {"type": "object", "properties": {"id": {"minimum": 1}}}, "minItems": 1, "uniqueItems": true}`,
+			`[{"id": 1}, {"id": 0}, {"id": 1}]`,
+			[]string{"$[1].id: must be >= 1, got 0", "$: items 0 and 2 are equal, expected unique items"},
+		},
+		{
+			"prefixItems (2020-12)",
+			`{"prefixItems": [{"type": "string"}, {"type": "number"}], "items": false}`,
+			`["a", 1, true]`,
+			[]string{"$[2]: not allowed by the schema"},
+		},
+		{
+			"tuple items (draft-07)",
+			`{"$schema": "http://json-schema.org/draft-07/schema#", "items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`,
+			`[1, 2, "three"]`,
+			[]string{"$[0]: expected string, got integer", "$[2]: expected integer, got string"},
+		},
+		{
+			"strings",
+			`{"properties": {"code": {"pattern": "^[A-Z]{3}$", "maxLength": 3}, "name": {"minLength": 2}}}`,
+			`{"code": "usd", "name": "é"}`,
+			[]string{"$.code: does not match pattern '^[A-Z]{3}$'", "$.name: expected at least 2 characters, got 1"},
+		},
+		{
+			"numbers",
+			`{"items": {"exclusiveMinimum": 0, "maximum": 1, "multipleOf": 0.25}}`,
+			`[0.5, 0, 1.1, 0.3]`,
+			[]string{"$[1]: must be > 0, got 0", "$[2]: must be <= 1, got 1.1", "$[2]: must be a multiple of 0.25, got 1.1", "$[3]: must be a multiple of 0.25, got 0.3"},
+		},
+		{
+			"enum and const",
+			`{"properties": {"status": {"enum": ["ok", "degraded"]}, "v": {"const": 2}}}`,
+			`{"status": "down", "v": 2.0}`,
+			[]string{`$.status: must be one of ["ok","degraded"]`},
+		},
+		{
+			"combinators",
+			`{"properties": {"a": {"anyOf": [{"type": "string"}, {"type": "null"}]}, "b": {"oneOf": [{"type": "number"}, {"type": "integer"}]}, "c": {"not": {"type": "string"}}}}`,
+			`{"a": 1, "b": 2, "c": "x"}`,
+			[]string{"$.a: does not match any of the anyOf schemas", "$.b: matches 2 of the oneOf schemas, expected exactly one", "$.c: must not match the 'not' schema"},
+		},
+		{
+			"if then else",
+			`{"if": {"properties": {"kind": {"const": "card"}}}, "then": {"required": ["last4"]}, "else": {"required": ["iban"]}}`,
+			`{"kind": "card"}`,
+			[]string{"$: missing required property 'last4'"},
+		},
+		{
+			"dependencies",
+			`{"dependentRequired": {"card": ["expiry"]}}`,
+			`{"card": "4242"}`,
+			[]string{"$: property 'card' requires property 'expiry'"},
+		},
+		{
+			"local refs and recursion",
+			`{"$defs": {"node": {"type": "object", "required": ["name"], "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}}, "$ref": "#/$defs/node"}`,
+			`{"name": "root", "children": [{"name": "a"}, {"children": [{"name": "c"}]}]}`,
+			[]string{"$.children[1]: missing required property 'name'"},
+		},
+		{
+			"draft-07 definitions",
+			`{"definitions": {"id": {"type": "string", "format": "uuid"}}, "properties": {"id": {"$ref": "#/definitions/id"}}}`,
+			`{"id": 1}`,
+			[]string{"$.id: expected string, got integer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Compile([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			violations, err := schema.ValidateJSON([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("ValidateJSON() error = %v", err)
+			}
+
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("violations =\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(tt.want, "\n  "))
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"not JSON", `{"type": `, "invalid JSON"},
+		{"unknown type", `{"properties": {"a": {"type": "int"}}}`, "schema #/properties/a/type: unknown type 'int'"},
+		{"bad pattern", `{"pattern": "("}`, "schema #/pattern:"},
+		{"negative count", `{"minItems": -1}`, "must be a non-negative integer"},
+		{"missing ref", `{"$ref": "#/$defs/missing"}`, "schema #/$defs/missing: '$defs' not found"},
+		{"remote ref", `{"$ref": "https://example.com/schema.json"}`, "only local files can be referenced"},
+		{"anchor ref", `{"$ref": "#node"}`, "only JSON pointer fragments are supported"},
+		{"old draft", `{"$schema": "http://json-schema.org/draft-04/schema#"}`, "unsupported $schema"},
+		{"not a schema", `{"items": 3}`, "schema #/items: must be an object or a boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile([]byte(tt.schema))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCacheLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	write("common/defs.json", `{"$defs": {"user": {"type": "object", "required": ["id"]}}}`)
+	usersPath := write("users.json", `{"type": "array", "items": {"$ref": "common/defs.json#/$defs/user"}}`)
+
+	cache := NewCache()
+	schema, err := cache.Load(usersPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	violations, _ := schema.ValidateJSON([]byte(`[{"id": 1}, {}]`))
+	if len(violations) != 1 || violations[0].String() != "$[1]: missing required property 'id'" {
+		t.Errorf("violations = %v, want a missing id at $[1]", violations)
+	}
+
+	// Later loads come from the cache, even if the file changes
+	write("users.json", `{"type": "string"}`)
+	cached, err := cache.Load(usersPath)
+	if err != nil || cached != schema {
+		t.Errorf("second Load() = %p, %v; want the cached schema %p", cached, err, schema)
+	}
+
+	// Errors name the file and location
+	badPath := write("bad.json", `{"$ref": "common/defs.json#/$defs/admin"}`)
+	if _, err := cache.Load(badPath); err == nil || !strings.Contains(err.Error(), "schema defs.json#/$defs/admin: 'admin' not found") {
+		t.Errorf("Load() error = %v, want a located missing ref", err)
+	}
+	if _, err := cache.Load(filepath.Join(dir, "missing.json")); err == nil || !strings.Contains(err.Error(), "failed to read schema") {
+		t.Errorf("Load() error = %v, want a read error", err)
+	}
+}
@@ -0,0 +1,346 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// validate checks value against s, appending violations found at path.
+func (s *Schema) validate(value interface{}, path string, out *[]Violation) {
+	report := func(format string, args ...interface{}) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.always != nil {
+		if !*s.always {
+			report("not allowed by the schema")
+		}
+		return
+	}
+
+	if s.ref != nil {
+		s.ref.validate(value, path, out)
+	}
+
+	// Other keywords are meaningless for the wrong type, so stop here
+	if len(s.types) > 0 && !matchesType(value, s.types) {
+		report("expected %s, got %s", strings.Join(s.types, " or "), typeName(value))
+		return
+	}
+
+	if s.enum != nil && !containsValue(s.enum, value) {
+		report("must be one of %s", compact(s.enum))
+	}
+	if s.hasConst && !equal(s.constant, value) {
+		report("must be %s", compact(s.constant))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(v, path, out)
+	case []interface{}:
+		s.validateArray(v, path, out)
+	case string:
+		s.validateString(v, report)
+	case json.Number:
+		s.validateNumber(v, report)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(value, path, out)
+	}
+	if len(s.anyOf) > 0 && countValid(s.anyOf, value, path) == 0 {
+		report("does not match any of the anyOf schemas")
+	}
+	if len(s.oneOf) > 0 {
+		if matched := countValid(s.oneOf, value, path); matched != 1 {
+			report("matches %d of the oneOf schemas, expected exactly one", matched)
+		}
+	}
+	if s.not != nil && s.not.valid(value, path) {
+		report("must not match the 'not' schema")
+	}
+	if s.ifS != nil {
+		if s.ifS.valid(value, path) {
+			if s.thenS != nil {
+				s.thenS.validate(value, path, out)
+			}
+		} else if s.elseS != nil {
+			s.elseS.validate(value, path, out)
+		}
+	}
+}
+
+// valid reports whether value matches s.
+func (s *Schema) valid(value interface{}, path string) bool {
+	var violations []Violation
+	s.validate(value, path, &violations)
+	return len(violations) == 0
+}
+
+// countValid returns how many of schemas value matches.
+func countValid(schemas []*Schema, value interface{}, path string) int {
+	matched := 0
+	for _, schema := range schemas {
+		if schema.valid(value, path) {
+			matched++
+		}
+	}
+	return matched
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, out *[]Violation) {
+	report := func(format string, args ...interface{}) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			report("missing required property '%s'", name)
+		}
+	}
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		report("expected at least %d properties, got %d", *s.minProperties, len(obj))
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		report("expected at most %d properties, got %d", *s.maxProperties, len(obj))
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := obj[key]
+		at := childPath(path, key)
+
+		if names, ok := s.dependentRequired[key]; ok {
+			for _, name := range names {
+				if _, present := obj[name]; !present {
+					report("property '%s' requires property '%s'", key, name)
+				}
+			}
+		}
+		if schema, ok := s.dependentSchemas[key]; ok {
+			schema.validate(obj, path, out)
+		}
+		if s.propertyNames != nil && !s.propertyNames.valid(key, at) {
+			*out = append(*out, Violation{Path: at, Message: "property name does not match the propertyNames schema"})
+		}
+
+		matched := false
+		if schema, ok := s.properties[key]; ok {
+			schema.validate(value, at, out)
+			matched = true
+		}
+		for _, pattern := range s.patternProperties {
+			if pattern.pattern.MatchString(key) {
+				pattern.schema.validate(value, at, out)
+				matched = true
+			}
+		}
+		if !matched && s.additionalProperties != nil {
+			if always := s.additionalProperties.always; always != nil && !*always {
+				*out = append(*out, Violation{Path: at, Message: "unexpected property"})
+			} else {
+				s.additionalProperties.validate(value, at, out)
+			}
+		}
+	}
+}
+
+func (s *Schema) validateArray(items []interface{}, path string, out *[]Violation) {
+	report := func(format string, args ...interface{}) {
+		*out = append(*out, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.minItems != nil && len(items) < *s.minItems {
+		report("expected at least %d items, got %d", *s.minItems, len(items))
+	}
+	if s.maxItems != nil && len(items) > *s.maxItems {
+		report("expected at most %d items, got %d", *s.maxItems, len(items))
+	}
+
+	for i, item := range items {
+		at := fmt.Sprintf("%s[%d]", path, i)
+		if i < len(s.prefixItems) {
+			s.prefixItems[i].validate(item, at, out)
+		} else if s.items != nil {
+			s.items.validate(item, at, out)
+		}
+	}
+
+	if s.contains != nil {
+		found := false
+		for i, item := range items {
+			if s.contains.valid(item, fmt.Sprintf("%s[%d]", path, i)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("no item matches the 'contains' schema")
+		}
+	}
+
+	if s.uniqueItems {
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if equal(items[i], items[j]) {
+					report("items %d and %d are equal, expected unique items", i, j)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, report func(string, ...interface{})) {
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		report("expected at least %d characters, got %d", *s.minLength, length)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		report("expected at most %d characters, got %d", *s.maxLength, length)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		report("does not match pattern '%s'", s.pattern)
+	}
+}
+
+func (s *Schema) validateNumber(num json.Number, report func(string, ...interface{})) {
+	value, ok := new(big.Rat).SetString(num.String())
+	if !ok {
+		return
+	}
+
+	if s.minimum != nil && value.Cmp(s.minimum.value) < 0 {
+		report("must be >= %s, got %s", s.minimum.text, num)
+	}
+	if s.maximum != nil && value.Cmp(s.maximum.value) > 0 {
+		report("must be <= %s, got %s", s.maximum.text, num)
+	}
+	if s.exclusiveMinimum != nil && value.Cmp(s.exclusiveMinimum.value) <= 0 {
+		report("must be > %s, got %s", s.exclusiveMinimum.text, num)
+	}
+	if s.exclusiveMaximum != nil && value.Cmp(s.exclusiveMaximum.value) >= 0 {
+		report("must be < %s, got %s", s.exclusiveMaximum.text, num)
+	}
+	if s.multipleOf != nil && !new(big.Rat).Quo(value, s.multipleOf.value).IsInt() {
+		report("must be a multiple of %s, got %s", s.multipleOf.text, num)
+	}
+}
+
+// matchesType reports whether value has one of the JSON types.
+func matchesType(value interface{}, types []string) bool {
+	actual := typeName(value)
+	for _, name := range types {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName returns the JSON type of a decoded value. Numbers without a
+// fractional part (including 1.0) are integers.
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if rat, ok := new(big.Rat).SetString(v.String()); ok && rat.IsInt() {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equal compares two decoded values; numbers are equal if their values are
+// (1 equals 1.0).
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		bn, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ar, aok := new(big.Rat).SetString(a.String())
+		br, bok := new(big.Rat).SetString(bn.String())
+		return aok && bok && ar.Cmp(br) == 0
+	case []interface{}:
+		bl, ok := b.([]interface{})
+		if !ok || len(a) != len(bl) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], bl[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bm, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(bm) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := bm[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// containsValue reports whether values contains value.
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if equal(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compact renders a decoded value as compact JSON.
+func compact(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// childPath appends key to path, using bracket notation for keys that are
+// not plain identifiers.
+func childPath(path, key string) string {
+	for i, r := range key {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return fmt.Sprintf("%s[%q]", path, key)
+		}
+	}
+	if key == "" {
+		return path + `[""]`
+	}
+	return path + "." + key
+}
@@ -0,0 +1,359 @@
+// Package k8s discovers health check targets from Kubernetes Services and
+// Ingresses, as listed by `kubectl get -o json`, and maps them back to the
+// pods that serve them.
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// kubectlCommand is the kubectl binary to run (replaced in tests).
+var kubectlCommand = "kubectl"
+
+// Query selects the resources to discover.
+type Query struct {
+	Context       string // kubeconfig context ("" = current context)
+	Namespace     string // Namespace ("" = the context's namespace)
+	AllNamespaces bool   // Look in every namespace
+	Selector      string // Label selector ("app=api,tier!=db")
+}
+
+// Options control how targets are built from the discovered resources.
+type Options struct {
+	Path       string // Path to check on services ("/" if empty); ingress paths are kept
+	ClusterDNS bool   // Reach ClusterIP services by cluster DNS name (tapr runs in the cluster)
+	Domain     string // Cluster domain for ClusterDNS ("cluster.local" if empty)
+}
+
+// Pod is a pod backing a service, from the service's Endpoints.
+type Pod struct {
+	Name  string
+	Ready bool
+}
+
+// Target is a URL to check and the resource it belongs to.
+type Target struct {
+	Kind      string // "svc" or "ing"
+	Namespace string
+	Name      string // Resource name
+	Port      string // Service port name or number ("" for ingresses)
+	URL       string // URL to check ("" if the resource can't be reached)
+	Skip      string // Why the resource isn't checked, if URL is empty
+	Service   string // Backing service (the service itself, or an ingress backend)
+	Pods      []Pod  // Pods behind Service, from its Endpoints
+}
+
+// Label returns a short name for the target, like "svc/api:http".
+func (t Target) Label(withNamespace bool) string {
+	label := t.Kind + "/" + t.Name
+	if withNamespace {
+		label = t.Kind + "/" + t.Namespace + "/" + t.Name
+	}
+	if t.Port != "" {
+		label += ":" + t.Port
+	}
+	return label
+}
+
+// ReadyPods returns the number of ready pods behind the target.
+func (t Target) ReadyPods() int {
+	ready := 0
+	for _, pod := range t.Pods {
+		if pod.Ready {
+			ready++
+		}
+	}
+	return ready
+}
+
+// Get runs kubectl to list the Services, Ingresses and Endpoints matching q.
+func Get(ctx context.Context, q Query) ([]byte, error) {
+	args := []string{"get", "services,ingresses,endpoints", "-o", "json"}
+	if q.Context != "" {
+		args = append(args, "--context", q.Context)
+	}
+	switch {
+	case q.AllNamespaces:
+		args = append(args, "--all-namespaces")
+	case q.Namespace != "":
+		args = append(args, "--namespace", q.Namespace)
+	}
+	if q.Selector != "" {
+		args = append(args, "--selector", q.Selector)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, kubectlCommand, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("kubectl: %s", message)
+		}
+		return nil, fmt.Errorf("kubectl: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// resource is the part of a Service, Ingress or Endpoints object tapr uses.
+type resource struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		// Service
+		Type      string        `json:"type"`
+		ClusterIP string        `json:"clusterIP"`
+		Ports     []servicePort `json:"ports"`
+
+		// Ingress
+		TLS []struct {
+			Hosts []string `json:"hosts"`
+		} `json:"tls"`
+		Rules []struct {
+			Host string `json:"host"`
+			HTTP struct {
+				Paths []struct {
+					Path    string `json:"path"`
+					Backend struct {
+						Service struct {
+							Name string `json:"name"`
+						} `json:"service"`
+					} `json:"backend"`
+				} `json:"paths"`
+			} `json:"http"`
+		} `json:"rules"`
+	} `json:"spec"`
+	Status struct {
+		LoadBalancer struct {
+			Ingress []struct {
+				IP       string `json:"ip"`
+				Hostname string `json:"hostname"`
+			} `json:"ingress"`
+		} `json:"loadBalancer"`
+	} `json:"status"`
+
+	// Endpoints
+	Subsets []struct {
+		Addresses         []endpointAddress `json:"addresses"`
+		NotReadyAddresses []endpointAddress `json:"notReadyAddresses"`
+	} `json:"subsets"`
+}
+
+// servicePort is one port of a Service.
+type servicePort struct {
+	Name        string `json:"name"`
+	Port        int    `json:"port"`
+	AppProtocol string `json:"appProtocol"`
+}
+
+// endpointAddress is a pod address in an Endpoints object.
+type endpointAddress struct {
+	IP        string `json:"ip"`
+	TargetRef struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	} `json:"targetRef"`
+}
+
+// Targets builds the targets to check from kubectl's JSON list output.
+// Ingresses are checked at each of their hosts and paths; services through
+// their load balancer, or by cluster DNS name with opts.ClusterDNS.
+func Targets(list []byte, opts Options) ([]Target, error) {
+	var parsed struct {
+		Items []resource `json:"items"`
+	}
+	if err := json.Unmarshal(list, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid kubectl output: %w", err)
+	}
+
+	// Pods per service, from the Endpoints objects
+	pods := make(map[string][]Pod)
+	for _, item := range parsed.Items {
+		if item.Kind != "Endpoints" {
+			continue
+		}
+		key := item.Metadata.Namespace + "/" + item.Metadata.Name
+		for _, subset := range item.Subsets {
+			pods[key] = appendPods(pods[key], subset.Addresses, true)
+			pods[key] = appendPods(pods[key], subset.NotReadyAddresses, false)
+		}
+	}
+
+	var targets []Target
+	for _, item := range parsed.Items {
+		switch item.Kind {
+		case "Service":
+			targets = append(targets, serviceTargets(item, opts, pods)...)
+		case "Ingress":
+			targets = append(targets, ingressTargets(item, pods)...)
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		a, b := targets[i], targets[j]
+		if a.Kind != b.Kind {
+			return a.Kind == "ing" // Ingresses first: that's what users hit
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return targets, nil
+}
+
+// appendPods adds the pods of endpoint addresses, deduplicated by name (a
+// pod appears once per port subset).
+func appendPods(pods []Pod, addresses []endpointAddress, ready bool) []Pod {
+	for _, address := range addresses {
+		name := address.TargetRef.Name
+		if name == "" {
+			name = address.IP
+		}
+		found := false
+		for _, pod := range pods {
+			if pod.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			pods = append(pods, Pod{Name: name, Ready: ready})
+		}
+	}
+	return pods
+}
+
+// serviceTargets returns one target per port of a service.
+func serviceTargets(svc resource, opts Options, pods map[string][]Pod) []Target {
+	namespace, name := svc.Metadata.Namespace, svc.Metadata.Name
+	var targets []Target
+	for _, port := range svc.Spec.Ports {
+		target := Target{
+			Kind:      "svc",
+			Namespace: namespace,
+			Name:      name,
+			Port:      port.Name,
+			Service:   name,
+			Pods:      pods[namespace+"/"+name],
+		}
+		if target.Port == "" {
+			target.Port = strconv.Itoa(port.Port)
+		}
+
+		host := ""
+		switch {
+		case svc.Spec.Type == "ExternalName":
+			target.Skip = "ExternalName service"
+		case svc.Spec.Type == "LoadBalancer" && len(svc.Status.LoadBalancer.Ingress) > 0:
+			lb := svc.Status.LoadBalancer.Ingress[0]
+			host = lb.IP
+			if host == "" {
+				host = lb.Hostname
+			}
+		case !opts.ClusterDNS:
+			target.Skip = "no external address (use --cluster-dns from inside the cluster)"
+		case svc.Spec.ClusterIP == "None":
+			target.Skip = "headless service"
+		default:
+			domain := opts.Domain
+			if domain == "" {
+				domain = "cluster.local"
+			}
+			host = fmt.Sprintf("%s.%s.svc.%s", name, namespace, domain)
+		}
+		if host != "" {
+			target.URL = servicePortScheme(port) + "://" + net.JoinHostPort(host, strconv.Itoa(port.Port)) + checkPath(opts.Path)
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// servicePortScheme guesses whether a service port speaks HTTPS.
+func servicePortScheme(port servicePort) string {
+	name := strings.ToLower(port.Name + " " + port.AppProtocol)
+	if port.Port == 443 || port.Port == 8443 || strings.Contains(name, "https") || strings.Contains(name, "tls") {
+		return "https"
+	}
+	return "http"
+}
+
+// checkPath returns the path to check on services.
+func checkPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "/" + path
+	}
+	return path
+}
+
+// ingressTargets returns one target per host and path of an ingress. Rules
+// without a host use the ingress's load balancer address.
+func ingressTargets(ing resource, pods map[string][]Pod) []Target {
+	namespace, name := ing.Metadata.Namespace, ing.Metadata.Name
+	tls := make(map[string]bool)
+	for _, entry := range ing.Spec.TLS {
+		for _, host := range entry.Hosts {
+			tls[host] = true
+		}
+	}
+
+	var targets []Target
+	for _, rule := range ing.Spec.Rules {
+		host := rule.Host
+		if host == "" && len(ing.Status.LoadBalancer.Ingress) > 0 {
+			host = ing.Status.LoadBalancer.Ingress[0].IP
+			if host == "" {
+				host = ing.Status.LoadBalancer.Ingress[0].Hostname
+			}
+		}
+		scheme := "http"
+		if tls[rule.Host] {
+			scheme = "https"
+		}
+
+		for _, path := range rule.HTTP.Paths {
+			service := path.Backend.Service.Name
+			target := Target{
+				Kind:      "ing",
+				Namespace: namespace,
+				Name:      name,
+				Service:   service,
+				Pods:      pods[namespace+"/"+service],
+			}
+			// Wildcard hosts and regex paths can't be requested as they are
+			switch {
+			case host == "":
+				target.Skip = "no host or load balancer address"
+			case strings.HasPrefix(host, "*"):
+				target.Skip = "wildcard host " + host
+			default:
+				target.URL = scheme + "://" + host + ingressPath(path.Path)
+			}
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// ingressPath turns an ingress path into one that can be requested, cutting
+// off regular expressions used by some ingress controllers.
+func ingressPath(path string) string {
+	if i := strings.IndexAny(path, "(*[$"); i >= 0 {
+		path = path[:i]
+	}
+	return checkPath(path)
+}
@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTargets(t *testing.T) {
+	list, err := os.ReadFile("testdata/list.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := Targets(list, Options{Path: "healthz"})
+	if err != nil {
+		t.Fatalf("Targets() error = %v", err)
+	}
+
+	type row struct{ label, url, skip, service string }
+	var got []row
+	for _, target := range targets {
+		got = append(got, row{target.Label(false), target.URL, target.Skip, target.Service})
+	}
+	want := []row{
+		{"ing/storefront", "https://shop.example.com/", "", "web"},
+		{"ing/storefront", "https://shop.example.com/api", "", "api"},
+		{"ing/storefront", "", "wildcard host *.example.com", "web"},
+		{"ing/storefront", "http://lb.example.com/status", "", "api"},
+		{"svc/api:http", "http://127.0.0.1:8766/healthz", "", "api"},
+		{"svc/db:5432", "", "no external address (use --cluster-dns from inside the cluster)", "db"},
+		{"svc/peers:7000", "", "no external address (use --cluster-dns from inside the cluster)", "peers"},
+		{"svc/web:https", "", "no external address (use --cluster-dns from inside the cluster)", "web"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets() =\n%v\nwant\n%v", got, want)
+	}
+
+	api := targets[4]
+	wantPods := []Pod{{"api-7d9f-abcde", true}, {"api-7d9f-fghij", true}, {"api-7d9f-klmno", false}}
+	if !reflect.DeepEqual(api.Pods, wantPods) || api.ReadyPods() != 2 {
+		t.Errorf("api pods = %v, want %v", api.Pods, wantPods)
+	}
+	if targets[5].Pods != nil {
+		t.Errorf("db pods = %v, want none known", targets[5].Pods)
+	}
+}
+
+func TestTargets_ClusterDNS(t *testing.T) {
+	list, err := os.ReadFile("testdata/list.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := Targets(list, Options{ClusterDNS: true, Domain: "corp.internal"})
+	if err != nil {
+		t.Fatalf("Targets() error = %v", err)
+	}
+	urls := make(map[string]string)
+	for _, target := range targets {
+		if target.Kind == "svc" {
+			urls[target.Name] = target.URL + target.Skip
+		}
+	}
+	want := map[string]string{
+		"api":   "http://127.0.0.1:8766/", // The load balancer is preferred
+		"db":    "http://db.shop.svc.corp.internal:5432/",
+		"web":   "https://web.shop.svc.corp.internal:8443/",
+		"peers": "headless service",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("service URLs = %v, want %v", urls, want)
+	}
+}
+
+func TestTargets_Invalid(t *testing.T) {
+	if _, err := Targets([]byte("No resources found"), Options{}); err == nil {
+		t.Error("Targets() of invalid output succeeded")
+	}
+}
+
+func TestGet(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "kubectl")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := kubectlCommand
+	kubectlCommand = script
+	defer func() { kubectlCommand = old }()
+
+	out, err := Get(context.Background(), Query{Context: "prod", Namespace: "shop", Selector: "app=api"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := "get services,ingresses,endpoints -o json --context prod --namespace shop --selector app=api"
+	if strings.TrimSpace(string(out)) != want {
+		t.Errorf("kubectl args = %s, want %s", out, want)
+	}
+
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'error: context \"nope\" does not exist' >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(context.Background(), Query{Context: "nope"}); err == nil || !strings.Contains(err.Error(), `kubectl: error: context "nope" does not exist`) {
+		t.Errorf("Get() error = %v, want kubectl's message", err)
+	}
+}
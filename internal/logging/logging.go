@@ -0,0 +1,143 @@
+// Package logging sets up tapr's diagnostic log: warnings, and at lower
+// levels retry decisions, transport events and request dumps. It is built on
+// log/slog, so packages log through slog's default logger without depending
+// on this one. Results and summaries are not log output; commands print
+// those themselves.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// Formats accepted by New.
+const (
+	FormatText = "text" // Human-readable lines, like the rest of tapr's output
+	FormatJSON = "json" // One JSON object per line
+)
+
+// ParseLevel parses a level name: debug, info, warn or error.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q (want debug, info, warn or error)", name)
+}
+
+// New creates a logger writing records at or above level to w.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	switch format {
+	case FormatText, "":
+		return slog.New(&textHandler{w: w, level: level, mu: &sync.Mutex{}}), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q (want text or json)", format)
+}
+
+// Discard returns a logger that drops everything (--silent).
+func Discard() *slog.Logger {
+	return slog.New(&textHandler{w: io.Discard, level: slog.LevelError + 1, mu: &sync.Mutex{}})
+}
+
+// textHandler writes records as "Warning: message key=value", colored by
+// level, to match the warnings and errors tapr prints itself.
+type textHandler struct {
+	w      io.Writer
+	level  slog.Level
+	attrs  []slog.Attr
+	prefix string      // Group names joined with "."
+	mu     *sync.Mutex // Shared by handlers derived with WithAttrs
+}
+
+// Enabled reports whether records at level are written.
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle writes one record.
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	b.WriteString(record.Message)
+	for _, attr := range h.attrs {
+		writeAttr(&b, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&b, h.prefix, attr)
+		return true
+	})
+
+	var line string
+	switch {
+	case record.Level >= slog.LevelError:
+		line = output.Red("Error: " + b.String())
+	case record.Level >= slog.LevelWarn:
+		line = output.Yellow("Warning: " + b.String())
+	case record.Level >= slog.LevelInfo:
+		line = b.String()
+	default:
+		line = output.Blue("debug: ") + b.String()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, line)
+	return err
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, attr := range attrs {
+		attr.Key = h.prefix + attr.Key
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies later keys with name.
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// writeAttr appends " key=value", flattening groups into dotted keys.
+func writeAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, inner := range attr.Value.Group() {
+			writeAttr(b, prefix, inner)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, attr.Key, value)
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warn":    slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+	}
+	for name, want := range tests {
+		if got, err := ParseLevel(name); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("trace"); err == nil {
+		t.Error("ParseLevel(trace) should fail")
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	output.SetColorEnabled(false)
+	defer output.SetColorEnabled(true)
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, FormatText)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger.Debug("hidden")
+	logger.Info("retrying request", "attempt", 2, "error", "connection refused")
+	logger.With("url", "https://example.com").WithGroup("tls").Warn("handshake slow", "version", "1.3")
+
+	want := "retrying request attempt=2 error=\"connection refused\"\n" +
+		"Warning: handshake slow url=https://example.com tls.version=1.3\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelDebug, FormatJSON)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger.Debug("connected", "remote", "93.184.216.34:443", "reused", false)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output %q is not JSON: %v", buf.String(), err)
+	}
+	if record["level"] != "DEBUG" || record["msg"] != "connected" || record["remote"] != "93.184.216.34:443" {
+		t.Errorf("record = %v", record)
+	}
+
+	if _, err := New(&buf, slog.LevelDebug, "xml"); err == nil || !strings.Contains(err.Error(), "log format") {
+		t.Errorf("New(xml) error = %v, want an invalid format", err)
+	}
+}
@@ -0,0 +1,211 @@
+// Package maintenance decides whether a failing endpoint is excused by a
+// maintenance window from the config or by an ad-hoc silence added with
+// `tapr silence`. Silences are kept in a small JSON file shared by every
+// tapr process, so a running daemon picks them up without restarting.
+package maintenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+// Silence mutes the failures of matching endpoints until it expires.
+type Silence struct {
+	Name    string    `json:"name"` // Endpoint or monitor name, globs allowed ("*" for all)
+	Until   time.Time `json:"until"`
+	Reason  string    `json:"reason,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Active reports whether the silence covers name at now.
+func (s Silence) Active(name string, now time.Time) bool {
+	if !now.Before(s.Until) {
+		return false
+	}
+	matched, _ := path.Match(s.Name, name)
+	return matched
+}
+
+// DefaultSilencePath returns the default silences file location,
+// ~/.local/share/tapr/silences.json (or $XDG_DATA_HOME/tapr/...), next to
+// the history store.
+func DefaultSilencePath() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "tapr", "silences.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "tapr-silences.json"
+	}
+	return filepath.Join(home, ".local", "share", "tapr", "silences.json")
+}
+
+// LoadSilences reads the silences that haven't expired at now. A missing
+// file yields no silences.
+func LoadSilences(path string, now time.Time) ([]Silence, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silences: %w", err)
+	}
+
+	var silences []Silence
+	if err := json.Unmarshal(data, &silences); err != nil {
+		return nil, fmt.Errorf("failed to parse silences %s: %w", path, err)
+	}
+
+	active := silences[:0]
+	for _, s := range silences {
+		if now.Before(s.Until) {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+// SaveSilences replaces the silences file. It writes a temporary file and
+// renames it, so readers never see a partial file.
+func SaveSilences(path string, silences []Silence) error {
+	if silences == nil {
+		silences = []Silence{}
+	}
+	data, err := json.MarshalIndent(silences, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(append(data, '\n')); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	return nil
+}
+
+// AddSilence adds a silence to the file, replacing an active silence with
+// the same name.
+func AddSilence(path string, silence Silence) error {
+	silences, err := LoadSilences(path, time.Now())
+	if err != nil {
+		return err
+	}
+
+	kept := silences[:0]
+	for _, s := range silences {
+		if s.Name != silence.Name {
+			kept = append(kept, s)
+		}
+	}
+	return SaveSilences(path, append(kept, silence))
+}
+
+// RemoveSilence removes the active silence with exactly this name and
+// reports whether there was one.
+func RemoveSilence(path, name string) (bool, error) {
+	silences, err := LoadSilences(path, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	kept := silences[:0]
+	for _, s := range silences {
+		if s.Name != name {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(silences) {
+		return false, nil
+	}
+	return true, SaveSilences(path, kept)
+}
+
+// Checker answers whether an endpoint is in maintenance, from the windows
+// of a config and the silences file. It is safe for concurrent use.
+type Checker struct {
+	windows     []config.MaintenanceWindow
+	silencePath string
+
+	mu       sync.Mutex
+	modTime  time.Time // Of the silences file when last read
+	silences []Silence
+}
+
+// NewChecker creates a checker for prepared windows and the silences file at
+// silencePath ("" to ignore silences).
+func NewChecker(windows []config.MaintenanceWindow, silencePath string) *Checker {
+	return &Checker{windows: windows, silencePath: silencePath}
+}
+
+// Reason returns why failures of name are excused at now, like
+// "maintenance window 'nightly deploy'" or "silenced until 15:04 (deploy)",
+// or "" if they aren't. A nil checker excuses nothing.
+func (c *Checker) Reason(name string, now time.Time) string {
+	if c == nil {
+		return ""
+	}
+
+	for i := range c.windows {
+		if c.windows[i].Active(name, now) {
+			return fmt.Sprintf("maintenance window '%s'", c.windows[i].Label())
+		}
+	}
+
+	for _, s := range c.loadSilences() {
+		if s.Active(name, now) {
+			reason := "silenced until " + s.Until.Local().Format("15:04")
+			if s.Until.Sub(now) >= 24*time.Hour {
+				reason = "silenced until " + s.Until.Local().Format("Jan 2 15:04")
+			}
+			if s.Reason != "" {
+				reason += " (" + s.Reason + ")"
+			}
+			return reason
+		}
+	}
+	return ""
+}
+
+// loadSilences returns the silences, rereading the file when it changed.
+// An unreadable file counts as no silences, so checks keep running.
+func (c *Checker) loadSilences() []Silence {
+	if c.silencePath == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.silencePath)
+	if err != nil {
+		c.silences, c.modTime = nil, time.Time{}
+		return nil
+	}
+	if !info.ModTime().Equal(c.modTime) {
+		c.silences, _ = LoadSilences(c.silencePath, time.Time{})
+		c.modTime = info.ModTime()
+	}
+	return c.silences
+}
@@ -0,0 +1,88 @@
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+func TestSilences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tapr", "silences.json")
+	now := time.Now()
+
+	if silences, err := LoadSilences(path, now); err != nil || len(silences) != 0 {
+		t.Fatalf("LoadSilences() of a missing file = %v, %v", silences, err)
+	}
+
+	for _, s := range []Silence{
+		{Name: "api", Until: now.Add(time.Hour), Reason: "deploy"},
+		{Name: "old", Until: now.Add(-time.Minute)},
+		{Name: "api", Until: now.Add(2 * time.Hour)}, // Replaces the first
+		{Name: "auth-*", Until: now.Add(time.Hour)},
+	} {
+		if err := AddSilence(path, s); err != nil {
+			t.Fatalf("AddSilence(%s) error = %v", s.Name, err)
+		}
+	}
+
+	silences, err := LoadSilences(path, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(silences) != 2 || silences[0].Name != "api" || silences[0].Reason != "" || silences[1].Name != "auth-*" {
+		t.Errorf("LoadSilences() = %+v, want api (replaced) and auth-*", silences)
+	}
+
+	if removed, err := RemoveSilence(path, "api"); err != nil || !removed {
+		t.Errorf("RemoveSilence(api) = %v, %v, want removed", removed, err)
+	}
+	if removed, _ := RemoveSilence(path, "api"); removed {
+		t.Error("RemoveSilence(api) twice should report nothing removed")
+	}
+}
+
+func TestChecker_Reason(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silences.json")
+	now := time.Date(2025, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	windows := []config.MaintenanceWindow{{
+		Name:      "migration",
+		Start:     now.Add(-time.Hour),
+		End:       now.Add(time.Hour),
+		Endpoints: []string{"db-*"},
+	}}
+	checker := NewChecker(windows, path)
+
+	if got := checker.Reason("db-primary", now); got != "maintenance window 'migration'" {
+		t.Errorf("Reason(db-primary) = %q, want the maintenance window", got)
+	}
+	if got := checker.Reason("api", now); got != "" {
+		t.Errorf("Reason(api) = %q, want none", got)
+	}
+
+	// Silences added later are picked up by the same checker
+	if err := SaveSilences(path, []Silence{{Name: "api", Until: now.Add(30 * time.Minute), Reason: "deploy"}}); err != nil {
+		t.Fatal(err)
+	}
+	got := checker.Reason("api", now)
+	if !strings.HasPrefix(got, "silenced until ") || !strings.HasSuffix(got, " (deploy)") {
+		t.Errorf("Reason(api) = %q, want silenced (deploy)", got)
+	}
+	if got := checker.Reason("api", now.Add(time.Hour)); got != "" {
+		t.Errorf("Reason(api) after the silence expired = %q, want none", got)
+	}
+
+	os.Remove(path)
+	if got := checker.Reason("api", now); got != "" {
+		t.Errorf("Reason(api) after the file was removed = %q, want none", got)
+	}
+
+	var nilChecker *Checker
+	if got := nilChecker.Reason("api", now); got != "" {
+		t.Errorf("nil Checker Reason() = %q, want none", got)
+	}
+}
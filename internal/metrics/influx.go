@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Measurement is the InfluxDB measurement tapr samples are written to.
+const Measurement = "tapr_request"
+
+// Influx flushing thresholds: a batch is written once it holds this many
+// lines or this much time has passed since the last write.
+const (
+	influxBatchSize     = 100
+	influxFlushInterval = 5 * time.Second
+)
+
+// LineProtocol formats sample as an InfluxDB line protocol line, tagged
+// with endpoint, method, status and mode.
+//
+// Example output:
+//
+//	tapr_request,endpoint=api,method=GET,mode=batch,status=200 latency_ms=120.5,success=true,url="https://..." 1700000000000000000
+func LineProtocol(sample Sample) string {
+	var b strings.Builder
+	b.WriteString(Measurement)
+
+	// Tags must be sorted by key for best write performance
+	tags := [][2]string{
+		{"endpoint", sample.Name},
+		{"method", sample.Method},
+		{"mode", sample.Mode},
+		{"status", strconv.Itoa(sample.Status)},
+	}
+	for _, tag := range tags {
+		if tag[1] == "" {
+			continue
+		}
+		b.WriteString("," + escapeKey(tag[0]) + "=" + escapeKey(tag[1]))
+	}
+
+	latencyMs := float64(sample.Latency) / float64(time.Millisecond)
+	fmt.Fprintf(&b, " latency_ms=%s,success=%t,url=%s",
+		strconv.FormatFloat(latencyMs, 'f', -1, 64),
+		sample.Success,
+		quoteField(sample.URL),
+	)
+	if sample.Bytes > 0 {
+		fmt.Fprintf(&b, ",bytes=%di,throughput_bytes_per_sec=%s",
+			sample.Bytes,
+			strconv.FormatFloat(sample.Rate, 'f', 0, 64),
+		)
+	}
+
+	timestamp := sample.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	fmt.Fprintf(&b, " %d", timestamp.UnixNano())
+
+	return b.String()
+}
+
+// escapeKey escapes commas, equals signs and spaces in tag keys and values.
+func escapeKey(s string) string {
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`).Replace(s)
+}
+
+// quoteField quotes a string field value.
+func quoteField(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Influx writes samples to an InfluxDB (or Telegraf) line protocol endpoint,
+// such as http://localhost:8086/api/v2/write?org=acme&bucket=api.
+type Influx struct {
+	url    string
+	token  string
+	client *http.Client
+
+	mu        sync.Mutex
+	lines     []string
+	lastFlush time.Time
+	err       error // First write error, reported by Close
+}
+
+// NewInflux creates a sink writing to url. token, if set, is sent as an
+// InfluxDB v2 API token.
+func NewInflux(url, token string) (*Influx, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("influx URL must start with http:// or https://")
+	}
+
+	return &Influx{
+		url:       url,
+		token:     token,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		lastFlush: time.Now(),
+	}, nil
+}
+
+// Record implements Sink. Samples are buffered and written in batches.
+func (i *Influx) Record(sample Sample) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.lines = append(i.lines, LineProtocol(sample))
+	if len(i.lines) >= influxBatchSize || time.Since(i.lastFlush) >= influxFlushInterval {
+		i.flush()
+	}
+}
+
+// Close implements Sink, writing any buffered samples.
+func (i *Influx) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.flush()
+	return i.err
+}
+
+// flush writes buffered lines. The caller must hold i.mu.
+func (i *Influx) flush() {
+	i.lastFlush = time.Now()
+	if len(i.lines) == 0 {
+		return
+	}
+
+	body := strings.Join(i.lines, "\n") + "\n"
+	i.lines = i.lines[:0]
+
+	if err := i.write([]byte(body)); err != nil && i.err == nil {
+		i.err = err
+	}
+}
+
+// write POSTs a batch of lines.
+func (i *Influx) write(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, i.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Token "+i.token)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if detail := strings.TrimSpace(string(message)); detail != "" {
+			return fmt.Errorf("influx write failed: %s: %s", resp.Status, detail)
+		}
+		return fmt.Errorf("influx write failed: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineProtocol(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample Sample
+		want   string
+	}{
+		{
+			name: "tags and fields",
+			sample: Sample{
+				Mode: "batch", Name: "api", URL: "https://example.com/health", Method: "GET",
+				Status: 200, Latency: 120500 * time.Microsecond, Success: true,
+				Time: time.Unix(1700000000, 0),
+			},
+			want: `tapr_request,endpoint=api,method=GET,mode=batch,status=200 latency_ms=120.5,success=true,url="https://example.com/health" 1700000000000000000`,
+		},
+		{
+			name: "escaping",
+			sample: Sample{
+				Name: "User API, v2", URL: `https://example.com/q?a="b"`, Method: "GET",
+				Latency: time.Millisecond, Time: time.Unix(1, 0),
+			},
+			want: `tapr_request,endpoint=User\ API\,\ v2,method=GET,status=0 latency_ms=1,success=false,url="https://example.com/q?a=\"b\"" 1000000000`,
+		},
+		{
+			name: "download",
+			sample: Sample{
+				Name: "file", URL: "https://example.com/f", Status: 200, Latency: 2 * time.Millisecond,
+				Success: true, Bytes: 4096, Rate: 2048000, Time: time.Unix(1, 0),
+			},
+			want: `tapr_request,endpoint=file,status=200 latency_ms=2,success=true,url="https://example.com/f",bytes=4096i,throughput_bytes_per_sec=2048000 1000000000`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LineProtocol(tt.sample); got != tt.want {
+				t.Errorf("LineProtocol() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfluxWritesOnClose(t *testing.T) {
+	var body, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body += string(data)
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewInflux(server.URL+"/api/v2/write?org=acme&bucket=api", "secret")
+	if err != nil {
+		t.Fatalf("NewInflux() error = %v", err)
+	}
+
+	sink.Record(Sample{Name: "a", Method: "GET", Status: 200, Success: true})
+	sink.Record(Sample{Name: "b", Method: "GET", Status: 500})
+	if body != "" {
+		t.Errorf("samples written before Close(), want buffering")
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if lines := strings.Count(body, "\n"); lines != 2 {
+		t.Errorf("wrote %d lines, want 2", lines)
+	}
+	if auth != "Token secret" {
+		t.Errorf("Authorization = %q, want %q", auth, "Token secret")
+	}
+}
+
+func TestInfluxReportsWriteErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bucket not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	sink, _ := NewInflux(server.URL, "")
+	sink.Record(Sample{Name: "a"})
+
+	if err := sink.Close(); err == nil || !strings.Contains(err.Error(), "bucket not found") {
+		t.Errorf("Close() error = %v, want influx error message", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Package metrics ships request results to external monitoring systems
+// such as StatsD/DogStatsD, so tapr can feed existing dashboards.
+package metrics
+
+import "time"
+
+// Sample is the outcome of one request, as reported to a Sink.
+type Sample struct {
+	Mode    string // Command that made the request ("watch", "batch")
+	Name    string // Endpoint name (or URL when unnamed)
+	URL     string
+	Method  string
+	Status  int // HTTP status code (0 on request errors)
+	Latency time.Duration
+	Success bool
+	Bytes   int64   // Downloaded body size (download mode only, 0 otherwise)
+	Rate    float64 // Body transfer rate in bytes per second (download mode only)
+	Time    time.Time
+}
+
+// Sink receives samples. Implementations must be safe for concurrent use.
+type Sink interface {
+	Record(sample Sample)
+	Close() error
+}
+
+// Multi fans samples out to several sinks.
+type Multi []Sink
+
+// Record implements Sink.
+func (m Multi) Record(sample Sample) {
+	for _, sink := range m {
+		sink.Record(sample)
+	}
+}
+
+// Close implements Sink, returning the first error.
+func (m Multi) Close() error {
+	var first error
+	for _, sink := range m {
+		if err := sink.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatsD sends samples to a StatsD server over UDP. Each sample produces:
+//
+//	<prefix>.latency   timing in milliseconds
+//	<prefix>.requests  counter
+//	<prefix>.success   counter (or <prefix>.failure)
+//
+// With DogStatsD enabled, samples are tagged with endpoint, method, status
+// and mode plus any global tags.
+type StatsD struct {
+	prefix    string
+	dogStatsD bool
+	tags      []string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewStatsD connects to the StatsD server at addr (host:port). tags are
+// "key:value" strings added to every metric when dogStatsD is set.
+func NewStatsD(addr, prefix string, dogStatsD bool, tags []string) (*StatsD, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid statsd address %q: %w", addr, err)
+	}
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd: %w", err)
+	}
+
+	return &StatsD{
+		prefix:    strings.TrimSuffix(prefix, "."),
+		dogStatsD: dogStatsD,
+		tags:      tags,
+		conn:      conn,
+	}, nil
+}
+
+// Record implements Sink. Delivery is best effort, as usual for StatsD.
+func (s *StatsD) Record(sample Sample) {
+	result := "success"
+	if !sample.Success {
+		result = "failure"
+	}
+
+	latencyMs := strconv.FormatFloat(float64(sample.Latency)/float64(time.Millisecond), 'f', 3, 64)
+	suffix := s.tagSuffix(sample)
+
+	lines := []string{
+		s.metric("latency") + ":" + latencyMs + "|ms" + suffix,
+		s.metric("requests") + ":1|c" + suffix,
+		s.metric(result) + ":1|c" + suffix,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.conn.Write([]byte(strings.Join(lines, "\n")))
+}
+
+// Close implements Sink.
+func (s *StatsD) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.conn.Close()
+}
+
+// metric returns the full name of a metric.
+func (s *StatsD) metric(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "." + name
+}
+
+// tagSuffix returns the DogStatsD tag section for sample ("" if disabled).
+func (s *StatsD) tagSuffix(sample Sample) string {
+	if !s.dogStatsD {
+		return ""
+	}
+
+	tags := append([]string{}, s.tags...)
+	tags = append(tags,
+		"endpoint:"+sanitizeTag(sample.Name),
+		"method:"+sanitizeTag(sample.Method),
+		"status:"+strconv.Itoa(sample.Status),
+	)
+	if sample.Mode != "" {
+		tags = append(tags, "mode:"+sample.Mode)
+	}
+	return "|#" + strings.Join(tags, ",")
+}
+
+// sanitizeTag replaces characters that have a meaning in the DogStatsD format.
+func sanitizeTag(value string) string {
+	return strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_", " ", "_").Replace(value)
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUDP returns a local UDP listener and its address.
+func listenUDP(t *testing.T) (net.PacketConn, string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, conn.LocalAddr().String()
+}
+
+// readPacket reads one UDP packet as a string.
+func readPacket(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestStatsDRecord(t *testing.T) {
+	tests := []struct {
+		name      string
+		dogStatsD bool
+		want      []string
+	}{
+		{
+			name: "plain statsd",
+			want: []string{
+				"tapr.latency:150.000|ms",
+				"tapr.requests:1|c",
+				"tapr.failure:1|c",
+			},
+		},
+		{
+			name:      "dogstatsd tags",
+			dogStatsD: true,
+			want: []string{
+				"tapr.latency:150.000|ms|#env:prod,endpoint:User_API,method:GET,status:500,mode:batch",
+				"tapr.requests:1|c|#env:prod,endpoint:User_API,method:GET,status:500,mode:batch",
+				"tapr.failure:1|c|#env:prod,endpoint:User_API,method:GET,status:500,mode:batch",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, addr := listenUDP(t)
+
+			client, err := NewStatsD(addr, "tapr.", tt.dogStatsD, []string{"env:prod"})
+			if err != nil {
+				t.Fatalf("NewStatsD() error = %v", err)
+			}
+			defer client.Close()
+
+			client.Record(Sample{
+				Mode:    "batch",
+				Name:    "User API",
+				Method:  "GET",
+				Status:  500,
+				Latency: 150 * time.Millisecond,
+			})
+
+			got := strings.Split(readPacket(t, server), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Record() sent\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestNewStatsDInvalidAddress(t *testing.T) {
+	if _, err := NewStatsD("localhost", "tapr", false, nil); err == nil {
+		t.Error("NewStatsD() error = nil, want error for address without port")
+	}
+}
@@ -0,0 +1,116 @@
+// Package netcheck provides lower-level network checks (DNS, TCP, ICMP)
+// that complement the HTTP checks in the request package.
+package netcheck
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DNSResult contains the records returned for a hostname by a single resolver.
+type DNSResult struct {
+	Host     string        // Hostname that was resolved
+	Resolver string        // Resolver address ("" = system resolver)
+	A        []string      // IPv4 addresses
+	AAAA     []string      // IPv6 addresses
+	CNAME    string        // Canonical name (empty if same as host)
+	Duration time.Duration // Time taken to resolve
+	Error    error         // Any error that occurred
+}
+
+// Addresses returns all A and AAAA records, sorted.
+func (r DNSResult) Addresses() []string {
+	addrs := make([]string, 0, len(r.A)+len(r.AAAA))
+	addrs = append(addrs, r.A...)
+	addrs = append(addrs, r.AAAA...)
+	sort.Strings(addrs)
+	return addrs
+}
+
+// LookupDNS resolves host and returns its A, AAAA and CNAME records.
+// If resolver is empty the system resolver is used, otherwise queries are
+// sent to the given DNS server (e.g., "1.1.1.1" or "8.8.8.8:53").
+//
+// Example:
+//
+//	result := netcheck.LookupDNS("api.example.com", "1.1.1.1", 5*time.Second)
+func LookupDNS(host, resolver string, timeout time.Duration) DNSResult {
+	result := DNSResult{
+		Host:     host,
+		Resolver: resolver,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	r := NewResolver(resolver)
+
+	start := time.Now()
+	ips, err := r.LookupIPAddr(ctx, host)
+	result.Duration = time.Since(start)
+
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	for _, ip := range ips {
+		if ip.IP.To4() != nil {
+			result.A = append(result.A, ip.IP.String())
+		} else {
+			result.AAAA = append(result.AAAA, ip.IP.String())
+		}
+	}
+	sort.Strings(result.A)
+	sort.Strings(result.AAAA)
+
+	// CNAME lookup failures are not fatal - many hosts have no CNAME
+	if cname, err := r.LookupCNAME(ctx, host); err == nil {
+		cname = strings.TrimSuffix(cname, ".")
+		if !strings.EqualFold(cname, strings.TrimSuffix(host, ".")) {
+			result.CNAME = cname
+		}
+	}
+
+	return result
+}
+
+// NewResolver returns a resolver that sends queries to the given DNS server.
+// An empty server returns the system default resolver.
+func NewResolver(server string) *net.Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+
+	// Default to the standard DNS port
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{}
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// SameAddresses reports whether two lookups returned the same set of addresses.
+func SameAddresses(a, b DNSResult) bool {
+	addrsA := a.Addresses()
+	addrsB := b.Addresses()
+
+	if len(addrsA) != len(addrsB) {
+		return false
+	}
+	for i := range addrsA {
+		if addrsA[i] != addrsB[i] {
+			return false
+		}
+	}
+	return true
+}
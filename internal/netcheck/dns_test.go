@@ -0,0 +1,64 @@
+package netcheck
+
+import "testing"
+
+func TestDNSResult_Addresses(t *testing.T) {
+	result := DNSResult{
+		A:    []string{"10.0.0.2", "10.0.0.1"},
+		AAAA: []string{"::1"},
+	}
+
+	got := result.Addresses()
+	want := []string{"10.0.0.1", "10.0.0.2", "::1"}
+
+	if len(got) != len(want) {
+		t.Fatalf("Addresses() returned %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Addresses()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSameAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		a    DNSResult
+		b    DNSResult
+		want bool
+	}{
+		{
+			name: "identical",
+			a:    DNSResult{A: []string{"10.0.0.1"}},
+			b:    DNSResult{A: []string{"10.0.0.1"}},
+			want: true,
+		},
+		{
+			name: "different order",
+			a:    DNSResult{A: []string{"10.0.0.1", "10.0.0.2"}},
+			b:    DNSResult{A: []string{"10.0.0.2", "10.0.0.1"}},
+			want: true,
+		},
+		{
+			name: "different address",
+			a:    DNSResult{A: []string{"10.0.0.1"}},
+			b:    DNSResult{A: []string{"10.0.0.3"}},
+			want: false,
+		},
+		{
+			name: "missing IPv6",
+			a:    DNSResult{A: []string{"10.0.0.1"}, AAAA: []string{"::1"}},
+			b:    DNSResult{A: []string{"10.0.0.1"}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameAddresses(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameAddresses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
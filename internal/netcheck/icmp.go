@@ -0,0 +1,173 @@
+package netcheck
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ICMP message types for echo request/reply
+const (
+	icmpv4EchoRequest = 8
+	icmpv4EchoReply   = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+)
+
+// ICMPResult represents the outcome of a single ICMP echo request.
+type ICMPResult struct {
+	Seq     int           // Sequence number of the echo request
+	Addr    string        // Address that replied
+	RTT     time.Duration // Round-trip time
+	Error   error         // Any error (including timeouts)
+	Success bool          // Whether a matching reply was received
+}
+
+// Pinger sends ICMP echo requests to a single host.
+// Raw ICMP sockets usually require root or CAP_NET_RAW.
+type Pinger struct {
+	host    string
+	addr    *net.IPAddr
+	conn    net.PacketConn
+	id      int
+	seq     int
+	timeout time.Duration
+	isIPv6  bool
+}
+
+// NewPinger resolves host and opens a raw ICMP socket for it.
+func NewPinger(host string, timeout time.Duration) (*Pinger, error) {
+	addr, err := net.ResolveIPAddr("ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+
+	isIPv6 := addr.IP.To4() == nil
+
+	network, listen := "ip4:icmp", "0.0.0.0"
+	if isIPv6 {
+		network, listen = "ip6:ipv6-icmp", "::"
+	}
+
+	conn, err := net.ListenPacket(network, listen)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return nil, fmt.Errorf("raw ICMP sockets need root or CAP_NET_RAW: %w", err)
+		}
+		return nil, fmt.Errorf("failed to open ICMP socket: %w", err)
+	}
+
+	return &Pinger{
+		host:    host,
+		addr:    addr,
+		conn:    conn,
+		id:      os.Getpid() & 0xffff,
+		timeout: timeout,
+		isIPv6:  isIPv6,
+	}, nil
+}
+
+// Addr returns the resolved address being pinged.
+func (p *Pinger) Addr() string {
+	return p.addr.String()
+}
+
+// Close releases the underlying socket.
+func (p *Pinger) Close() error {
+	return p.conn.Close()
+}
+
+// Ping sends a single echo request and waits for the matching reply.
+func (p *Pinger) Ping() ICMPResult {
+	p.seq++
+	result := ICMPResult{Seq: p.seq}
+
+	requestType := byte(icmpv4EchoRequest)
+	if p.isIPv6 {
+		requestType = icmpv6EchoRequest
+	}
+
+	packet := buildEchoRequest(requestType, p.id, p.seq, []byte("tapr-icmp-probe"))
+
+	start := time.Now()
+	if _, err := p.conn.WriteTo(packet, p.addr); err != nil {
+		result.Error = err
+		return result
+	}
+
+	deadline := start.Add(p.timeout)
+	_ = p.conn.SetReadDeadline(deadline)
+
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := p.conn.ReadFrom(buf)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+
+		if !p.isReply(buf[:n]) {
+			continue // Not our packet (other pings, unrelated ICMP)
+		}
+
+		result.RTT = time.Since(start)
+		result.Addr = from.String()
+		result.Success = true
+		return result
+	}
+}
+
+// isReply reports whether msg is the echo reply for the current sequence.
+func (p *Pinger) isReply(msg []byte) bool {
+	if len(msg) < 8 {
+		return false
+	}
+
+	replyType := byte(icmpv4EchoReply)
+	if p.isIPv6 {
+		replyType = icmpv6EchoReply
+	}
+
+	if msg[0] != replyType {
+		return false
+	}
+
+	id := int(binary.BigEndian.Uint16(msg[4:6]))
+	seq := int(binary.BigEndian.Uint16(msg[6:8]))
+	return id == p.id && seq == p.seq&0xffff
+}
+
+// buildEchoRequest encodes an ICMP echo request message.
+// For ICMPv6 the kernel fills in the checksum.
+func buildEchoRequest(msgType byte, id, seq int, payload []byte) []byte {
+	msg := make([]byte, 8+len(payload))
+	msg[0] = msgType
+	msg[1] = 0 // Code
+	binary.BigEndian.PutUint16(msg[4:6], uint16(id))
+	binary.BigEndian.PutUint16(msg[6:8], uint16(seq))
+	copy(msg[8:], payload)
+
+	if msgType == icmpv4EchoRequest {
+		binary.BigEndian.PutUint16(msg[2:4], checksum(msg))
+	}
+
+	return msg
+}
+
+// checksum computes the Internet checksum (RFC 1071) of b.
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
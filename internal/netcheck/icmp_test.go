@@ -0,0 +1,32 @@
+package netcheck
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestBuildEchoRequest(t *testing.T) {
+	msg := buildEchoRequest(icmpv4EchoRequest, 0x1234, 7, []byte("hi"))
+
+	if msg[0] != icmpv4EchoRequest {
+		t.Errorf("type = %d, want %d", msg[0], icmpv4EchoRequest)
+	}
+	if id := binary.BigEndian.Uint16(msg[4:6]); id != 0x1234 {
+		t.Errorf("id = %#x, want 0x1234", id)
+	}
+	if seq := binary.BigEndian.Uint16(msg[6:8]); seq != 7 {
+		t.Errorf("seq = %d, want 7", seq)
+	}
+
+	// A message with a valid checksum sums to zero
+	if got := checksum(msg); got != 0 {
+		t.Errorf("checksum over message = %#x, want 0", got)
+	}
+}
+
+func TestChecksum_OddLength(t *testing.T) {
+	// Odd-length input is padded with a zero byte
+	if checksum([]byte{0x01}) != checksum([]byte{0x01, 0x00}) {
+		t.Error("checksum of odd-length input should match zero-padded input")
+	}
+}
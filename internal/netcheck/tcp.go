@@ -0,0 +1,82 @@
+package netcheck
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// TCPOptions contains configuration options for a raw TCP check.
+type TCPOptions struct {
+	Timeout      time.Duration // Maximum time to connect (and to read the banner)
+	Send         string        // Optional payload to send after connecting (e.g., "PING\r\n")
+	ExpectBanner string        // Optional substring the server's banner must contain
+}
+
+// TCPResult represents the outcome of a raw TCP connectivity check.
+type TCPResult struct {
+	Address     string        // The host:port that was checked
+	RemoteAddr  string        // Resolved remote address
+	ConnectTime time.Duration // Time to establish the TCP connection
+	Banner      string        // First bytes sent by the server (if read)
+	Error       error         // Any error that occurred
+}
+
+// CheckTCP opens a TCP connection to address and measures the connect time.
+// If Send or ExpectBanner is set, it also exchanges data with the server,
+// which allows checking services like Redis, SMTP or SSH.
+//
+// Example:
+//
+//	result := netcheck.CheckTCP("localhost:6379", netcheck.TCPOptions{
+//	    Timeout:      5 * time.Second,
+//	    Send:         "PING\r\n",
+//	    ExpectBanner: "PONG",
+//	})
+func CheckTCP(address string, opts TCPOptions) TCPResult {
+	result := TCPResult{
+		Address: address,
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, opts.Timeout)
+	result.ConnectTime = time.Since(start)
+
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	defer conn.Close()
+
+	result.RemoteAddr = conn.RemoteAddr().String()
+
+	// Plain connect check - nothing else to do
+	if opts.Send == "" && opts.ExpectBanner == "" {
+		return result
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(opts.Timeout))
+
+	if opts.Send != "" {
+		if _, err := conn.Write([]byte(opts.Send)); err != nil {
+			result.Error = fmt.Errorf("failed to send payload: %w", err)
+			return result
+		}
+	}
+
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	result.Banner = strings.TrimSpace(string(buf[:n]))
+
+	if n == 0 && err != nil {
+		result.Error = fmt.Errorf("failed to read banner: %w", err)
+		return result
+	}
+
+	if opts.ExpectBanner != "" && !strings.Contains(result.Banner, opts.ExpectBanner) {
+		result.Error = fmt.Errorf("banner %q does not contain %q", result.Banner, opts.ExpectBanner)
+	}
+
+	return result
+}
@@ -0,0 +1,126 @@
+package netcheck
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenTCP starts a local TCP server that handles each connection with
+// handle, and returns its address.
+func listenTCP(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestCheckTCP_Connect(t *testing.T) {
+	address := listenTCP(t, func(net.Conn) {})
+
+	result := CheckTCP(address, TCPOptions{Timeout: time.Second})
+	if result.Error != nil {
+		t.Fatalf("CheckTCP() error = %v", result.Error)
+	}
+	if result.Address != address || result.RemoteAddr != address {
+		t.Errorf("Address = %s, RemoteAddr = %s, want %s", result.Address, result.RemoteAddr, address)
+	}
+	if result.ConnectTime <= 0 {
+		t.Errorf("ConnectTime = %v, want > 0", result.ConnectTime)
+	}
+	if result.Banner != "" {
+		t.Errorf("Banner = %q, want none read on a plain connect", result.Banner)
+	}
+}
+
+func TestCheckTCP_Banner(t *testing.T) {
+	address := listenTCP(t, func(conn net.Conn) {
+		conn.Write([]byte("SSH-2.0-OpenSSH_9.6\r\n"))
+	})
+
+	result := CheckTCP(address, TCPOptions{Timeout: time.Second, ExpectBanner: "SSH-2.0"})
+	if result.Error != nil {
+		t.Fatalf("CheckTCP() error = %v", result.Error)
+	}
+	if result.Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("Banner = %q, want the trimmed server greeting", result.Banner)
+	}
+}
+
+func TestCheckTCP_SendAndExpect(t *testing.T) {
+	address := listenTCP(t, func(conn net.Conn) {
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		if strings.TrimSpace(line) == "PING" {
+			conn.Write([]byte("+PONG\r\n"))
+		}
+	})
+
+	result := CheckTCP(address, TCPOptions{Timeout: time.Second, Send: "PING\r\n", ExpectBanner: "PONG"})
+	if result.Error != nil {
+		t.Fatalf("CheckTCP() error = %v", result.Error)
+	}
+	if result.Banner != "+PONG" {
+		t.Errorf("Banner = %q, want +PONG", result.Banner)
+	}
+}
+
+func TestCheckTCP_ExpectMismatch(t *testing.T) {
+	address := listenTCP(t, func(conn net.Conn) {
+		conn.Write([]byte("220 mail.example.com ESMTP\r\n"))
+	})
+
+	result := CheckTCP(address, TCPOptions{Timeout: time.Second, ExpectBanner: "SSH"})
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "does not contain") {
+		t.Fatalf("CheckTCP() error = %v, want a banner mismatch", result.Error)
+	}
+	if result.Banner != "220 mail.example.com ESMTP" {
+		t.Errorf("Banner = %q, want the banner that was read", result.Banner)
+	}
+}
+
+func TestCheckTCP_NoBanner(t *testing.T) {
+	address := listenTCP(t, func(conn net.Conn) {
+		time.Sleep(time.Second) // Accept but stay silent
+	})
+
+	result := CheckTCP(address, TCPOptions{Timeout: 100 * time.Millisecond, ExpectBanner: "SSH"})
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "failed to read banner") {
+		t.Fatalf("CheckTCP() error = %v, want a banner read timeout", result.Error)
+	}
+}
+
+func TestCheckTCP_Refused(t *testing.T) {
+	// Grab a free port, then close it so nothing listens there
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	result := CheckTCP(address, TCPOptions{Timeout: time.Second})
+	if result.Error == nil {
+		t.Fatal("CheckTCP() succeeded against a closed port")
+	}
+	if result.RemoteAddr != "" {
+		t.Errorf("RemoteAddr = %q, want none after a failed connect", result.RemoteAddr)
+	}
+}
@@ -2,19 +2,105 @@
 // including colored text and styled messages.
 package output
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 // ANSI color codes for terminal text styling.
 // These codes work on most modern terminals (Linux, macOS, Windows 10+).
 const (
-	ColorReset  = "\033[0m"  // Reset to default color
-	ColorRed    = "\033[31m" // Red text (errors, failures)
-	ColorGreen  = "\033[32m" // Green text (success, fast responses)
-	ColorYellow = "\033[33m" // Yellow text (warnings, slow responses)
-	ColorBlue   = "\033[34m" // Blue text (informational)
-	ColorCyan   = "\033[36m" // Cyan text (exceptional performance)
+	ColorReset   = "\033[0m"  // Reset to default color
+	ColorRed     = "\033[31m" // Red text (errors, failures)
+	ColorGreen   = "\033[32m" // Green text (success, fast responses)
+	ColorYellow  = "\033[33m" // Yellow text (warnings, slow responses)
+	ColorBlue    = "\033[34m" // Blue text (informational)
+	ColorCyan    = "\033[36m" // Cyan text (exceptional performance)
+	ColorMagenta = "\033[35m" // Magenta text
+	ColorWhite   = "\033[37m" // White text
 )
 
+// colorNames maps the color names accepted by SetLatencyColor to codes.
+var colorNames = map[string]string{
+	"red":     ColorRed,
+	"green":   ColorGreen,
+	"yellow":  ColorYellow,
+	"blue":    ColorBlue,
+	"magenta": ColorMagenta,
+	"cyan":    ColorCyan,
+	"white":   ColorWhite,
+}
+
+// Latency levels colored by Latency.
+const (
+	LatencyFast   = iota // Below the fast threshold
+	LatencyMedium        // Between the fast and slow thresholds
+	LatencySlow          // Above the slow threshold
+)
+
+// latencyColors holds the color of each latency level.
+var latencyColors = [...]string{ColorGreen, ColorYellow, ColorRed}
+
+// Color modes accepted by ConfigureColor.
+const (
+	ColorAuto   = "auto"   // Color only on a terminal, unless NO_COLOR is set
+	ColorAlways = "always" // Always color
+	ColorNever  = "never"  // Never color
+)
+
+// colorEnabled controls whether the helpers below emit ANSI codes.
+var colorEnabled = true
+
+// SetColorEnabled turns colored output on or off.
+func SetColorEnabled(enabled bool) {
+	colorEnabled = enabled
+}
+
+// ConfigureColor turns colored output on or off for a color mode ("" means
+// auto). In auto mode, color is used only when stdout is a terminal that
+// understands ANSI escapes (see SetupTerminal), the NO_COLOR environment
+// variable is unset or empty (https://no-color.org) and TERM is not "dumb".
+func ConfigureColor(mode string) error {
+	switch mode {
+	case ColorAlways:
+		SetColorEnabled(true)
+	case ColorNever:
+		SetColorEnabled(false)
+	case ColorAuto, "":
+		SetColorEnabled(!legacyConsole && autoColor(IsTerminal(os.Stdout)))
+	default:
+		SetColorEnabled(autoColor(IsTerminal(os.Stdout))) // For the error message
+		return fmt.Errorf("invalid color mode %q (want auto, always or never)", mode)
+	}
+	return nil
+}
+
+// autoColor reports whether auto mode colors output written to a terminal
+// (or not).
+func autoColor(terminal bool) bool {
+	return terminal && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+}
+
+// ClearScreen clears the terminal for a redraw of live output. Nothing is
+// written when stdout is a pipe or file, so logs don't fill with escape
+// codes, or on a legacy console.
+func ClearScreen() {
+	if IsTerminal(os.Stdout) && !legacyConsole {
+		fmt.Print("\033[H\033[2J")
+	}
+}
+
+// IsTerminal reports whether f is an interactive terminal rather than a
+// pipe or file.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // Green wraps the given text in ANSI green color codes.
 func Green(text string) string {
 	return colorize(text, ColorGreen)
@@ -41,8 +127,28 @@ func Cyan(text string) string {
 	return colorize(text, ColorCyan)
 }
 
+// SetLatencyColor sets the color of a latency level by name: red, green,
+// yellow, blue, magenta, cyan or white.
+func SetLatencyColor(level int, name string) error {
+	code, ok := colorNames[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown color %q (want red, green, yellow, blue, magenta, cyan or white)", name)
+	}
+	latencyColors[level] = code
+	return nil
+}
+
+// Latency wraps text in the color of a latency level (green, yellow and
+// red unless changed with SetLatencyColor).
+func Latency(text string, level int) string {
+	return colorize(text, latencyColors[level])
+}
+
 // colorize is a helper function that wraps text with the specified
 // color code and automatically resets the color at the end.
 func colorize(text, color string) string {
+	if !colorEnabled {
+		return text
+	}
 	return fmt.Sprintf("%s%s%s", color, text, ColorReset)
 }
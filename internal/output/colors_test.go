@@ -0,0 +1,61 @@
+package output
+
+import "testing"
+
+func TestConfigureColor(t *testing.T) {
+	defer SetColorEnabled(true)
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	if err := ConfigureColor(ColorAlways); err != nil || !colorEnabled {
+		t.Errorf("always: enabled = %v, err = %v; want colors", colorEnabled, err)
+	}
+	if err := ConfigureColor(ColorNever); err != nil || colorEnabled {
+		t.Errorf("never: enabled = %v, err = %v; want no colors", colorEnabled, err)
+	}
+	if Red("x") != "x" {
+		t.Errorf("Red() = %q with colors off, want plain text", Red("x"))
+	}
+
+	if err := ConfigureColor("rainbow"); err == nil {
+		t.Error("ConfigureColor(rainbow) should fail")
+	}
+}
+
+func TestAutoColor(t *testing.T) {
+	tests := []struct {
+		noColor, term string
+		terminal      bool
+		want          bool
+	}{
+		{"", "xterm", true, true},
+		{"", "xterm", false, false},
+		{"1", "xterm", true, false},
+		{"", "dumb", true, false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		t.Setenv("TERM", tt.term)
+		if got := autoColor(tt.terminal); got != tt.want {
+			t.Errorf("autoColor(%v) with NO_COLOR=%q TERM=%q = %v, want %v", tt.terminal, tt.noColor, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestSetLatencyColor(t *testing.T) {
+	defer SetLatencyColor(LatencySlow, "red")
+
+	if got := Latency("812ms", LatencySlow); got != ColorRed+"812ms"+ColorReset {
+		t.Errorf("Latency(slow) = %q, want red by default", got)
+	}
+	if err := SetLatencyColor(LatencySlow, "Magenta"); err != nil {
+		t.Fatalf("SetLatencyColor() error = %v", err)
+	}
+	if got := Latency("812ms", LatencySlow); got != ColorMagenta+"812ms"+ColorReset {
+		t.Errorf("Latency(slow) = %q, want magenta", got)
+	}
+	if err := SetLatencyColor(LatencyFast, "chartreuse"); err == nil {
+		t.Error("SetLatencyColor(chartreuse) should fail")
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// Mask replaces secret values in repro commands.
+const Mask = request.Mask
+
+// FormatCurl returns a curl command that repeats the request of a batch
+// result, for reproducing failures. Values of credential-like headers and
+// query parameters (Authorization, Cookie, X-API-Key, ?token=, ...) are
+// masked; the auth scheme of an Authorization header is kept.
+//
+// Example output:
+//
+//	curl -X POST 'https://api.example.com/orders' \
+//	  -H 'Authorization: Bearer ****' \
+//	  --data-raw '{"sku": "A1"}'
+func FormatCurl(result stats.BatchResult) string {
+	target := result.Result.URL
+	if target == "" {
+		target = result.URL
+	}
+
+	parts := []string{"curl"}
+	if method := strings.ToUpper(result.Method); method != "" && method != "GET" {
+		if method == "HEAD" {
+			parts = append(parts, "-I")
+		} else {
+			parts = append(parts, "-X "+method)
+		}
+	}
+	parts = append(parts, shellQuote(request.MaskURL(target)))
+
+	names := make([]string, 0, len(result.RequestHeaders))
+	for name := range result.RequestHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{strings.Join(parts, " ")}
+	for _, name := range names {
+		lines = append(lines, "-H "+shellQuote(name+": "+request.MaskHeader(name, result.RequestHeaders[name])))
+	}
+	if result.RequestBody != "" {
+		lines = append(lines, "--data-raw "+shellQuote(result.RequestBody))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
+
+// shellQuote wraps s in single quotes for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// FormatRequest renders a request in HTTP/1.1 message form, for dry runs:
+// the request line, headers sorted by name, and the body. Secrets are
+// masked as in FormatCurl, and bodies that aren't text are summarized.
+//
+// Example output:
+//
+//	POST https://api.example.com/orders
+//	Authorization: Bearer ****
+//	Content-Type: application/json
+//
+//	{"sku": "A1"}
+func FormatRequest(req *http.Request, body []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", req.Method, request.MaskURL(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s: %s\n", name, request.MaskHeader(name, strings.Join(req.Header[name], ", ")))
+	}
+
+	switch {
+	case len(body) == 0:
+	case utf8.Valid(body) && !bytes.ContainsRune(body, 0):
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimRight(string(body), "\n"))
+	default:
+		fmt.Fprintf(&b, "\n[%d bytes of binary data]\n", len(body))
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -14,63 +14,113 @@ type JSONBatchResult struct {
 	Successful  int            `json:"successful"`
 	Failed      int            `json:"failed"`
 	Slow        int            `json:"slow"`
+	Flaky       int            `json:"flaky,omitempty"`
+	Silenced    int            `json:"silenced,omitempty"`
+	Degraded    int            `json:"degraded,omitempty"`
 	SuccessRate float64        `json:"success_rate"`
 	AvgLatency  int64          `json:"avg_latency_ms"`
 	TotalTime   int64          `json:"total_time_ms"`
+	Requests    int            `json:"requests"`
+	RequestRate float64        `json:"requests_per_sec"`
+	Statuses    map[string]int `json:"statuses"`
+	Errors      map[string]int `json:"errors,omitempty"`
 	Results     []JSONEndpoint `json:"results"`
 }
 
 // JSONEndpoint represents a single endpoint result in JSON format.
 type JSONEndpoint struct {
-	Name           string `json:"name"`
-	URL            string `json:"url"`
-	Method         string `json:"method"`
-	Status         int    `json:"status"`
-	ExpectedStatus int    `json:"expected_status"`
-	Latency        int64  `json:"latency_ms"`
-	Size           int64  `json:"size_bytes"`
-	Success        bool   `json:"success"`
-	Error          string `json:"error,omitempty"`
+	Name           string   `json:"name"`
+	URL            string   `json:"url"`
+	Method         string   `json:"method"`
+	Status         int      `json:"status"`
+	ExpectedStatus int      `json:"expected_status"`
+	Latency        int64    `json:"latency_ms"`
+	TTFB           int64    `json:"ttfb_ms"`
+	Size           int64    `json:"size_bytes"`
+	Download       int64    `json:"download_ms,omitempty"`
+	Throughput     float64  `json:"throughput_bytes_per_sec,omitempty"`
+	Encoding       string   `json:"encoding,omitempty"`
+	CompressedSize int64    `json:"compressed_size_bytes,omitempty"`
+	Compression    float64  `json:"compression_ratio,omitempty"`
+	Success        bool     `json:"success"`
+	Skipped        bool     `json:"skipped,omitempty"`
+	Attempts       int      `json:"attempts,omitempty"`
+	Flaky          bool     `json:"flaky,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Stage          string   `json:"stage,omitempty"`
+	Error          string   `json:"error,omitempty"`
+	Silenced       string   `json:"silenced,omitempty"`
+	DependsOn      []string `json:"depends_on,omitempty"`
+	Upstream       string   `json:"upstream,omitempty"` // Failed dependency that degraded this endpoint
 }
 
 // FormatBatchResultJSON converts a batch summary to JSON format.
 func FormatBatchResultJSON(summary *stats.BatchSummary) (string, error) {
+	data, err := json.MarshalIndent(NewJSONBatchResult(summary), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// NewJSONBatchResult converts a batch summary to its JSON representation.
+func NewJSONBatchResult(summary *stats.BatchSummary) JSONBatchResult {
 	jsonResult := JSONBatchResult{
 		Total:       summary.Total,
 		Successful:  summary.Successful,
 		Failed:      summary.Failed,
 		Slow:        summary.Slow,
+		Flaky:       summary.Flaky,
+		Silenced:    summary.Silenced,
+		Degraded:    summary.Degraded,
 		SuccessRate: summary.SuccessRate(),
 		AvgLatency:  summary.AvgLatency.Milliseconds(),
 		TotalTime:   summary.TotalTime.Milliseconds(),
+		Requests:    summary.Requests,
+		RequestRate: summary.RequestRate(),
+		Statuses:    summary.Statuses,
+		Errors:      summary.Errors,
 		Results:     make([]JSONEndpoint, len(summary.Results)),
 	}
 
 	for i, result := range summary.Results {
-		endpoint := JSONEndpoint{
-			Name:           result.Name,
-			URL:            result.URL,
-			Method:         result.Method,
-			Status:         result.Result.StatusCode,
-			ExpectedStatus: result.ExpectedStatus,
-			Latency:        result.Result.Latency.Milliseconds(),
-			Size:           result.Result.Size,
-			Success:        result.Success,
-		}
-
-		if result.Result.Error != nil {
-			endpoint.Error = result.Result.Error.Error()
-		} else if !result.Success {
-			endpoint.Error = result.Message
-		}
-
-		jsonResult.Results[i] = endpoint
+		jsonResult.Results[i] = toJSONEndpoint(result)
 	}
+	return jsonResult
+}
 
-	data, err := json.MarshalIndent(jsonResult, "", "  ")
-	if err != nil {
-		return "", err
+// toJSONEndpoint converts a single batch result.
+func toJSONEndpoint(result stats.BatchResult) JSONEndpoint {
+	endpoint := JSONEndpoint{
+		Name:           result.Name,
+		URL:            result.URL,
+		Method:         result.Method,
+		Status:         result.Result.StatusCode,
+		ExpectedStatus: result.ExpectedStatus,
+		Latency:        result.Result.Latency.Milliseconds(),
+		TTFB:           result.Result.TTFB.Milliseconds(),
+		Size:           result.Result.Size,
+		Download:       result.Result.Download.Milliseconds(),
+		Throughput:     result.Result.Throughput(),
+		Encoding:       result.Result.Encoding,
+		CompressedSize: result.Result.CompressedSize,
+		Compression:    result.Result.CompressionRatio(),
+		Success:        result.Success,
+		Skipped:        result.Skipped,
+		Attempts:       result.Attempts,
+		Flaky:          result.Flaky(),
+		Tags:           result.Tags,
+		Stage:          result.Stage,
+		Silenced:       result.Silenced,
+		DependsOn:      result.DependsOn,
+		Upstream:       result.Upstream,
 	}
 
-	return string(data), nil
+	if result.Result.Error != nil {
+		endpoint.Error = result.Result.Error.Error()
+	} else if !result.Success {
+		endpoint.Error = result.Message
+	}
+	return endpoint
 }
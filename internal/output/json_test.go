@@ -19,9 +19,13 @@ func TestFormatBatchResultJSON(t *testing.T) {
 		Method:         "GET",
 		ExpectedStatus: 200,
 		Success:        true,
+		Attempts:       2,
+		Tags:           []string{"critical"},
 		Result: request.Result{
+			URL:        "https://example.com",
 			StatusCode: 200,
 			Latency:    150 * time.Millisecond,
+			TTFB:       120 * time.Millisecond,
 			Size:       1024,
 		},
 	})
@@ -35,6 +39,7 @@ func TestFormatBatchResultJSON(t *testing.T) {
 		Success:        false,
 		Message:        "Expected 200, got 500",
 		Result: request.Result{
+			URL:        "https://broken.com",
 			StatusCode: 500,
 			Latency:    250 * time.Millisecond,
 			Size:       512,
@@ -70,6 +75,9 @@ func TestFormatBatchResultJSON(t *testing.T) {
 	if result.TotalTime != 500 {
 		t.Errorf("TotalTime = %d, want 500", result.TotalTime)
 	}
+	if result.Statuses["200"] != 1 || result.Statuses["500"] != 1 {
+		t.Errorf("Statuses = %v, want one 200 and one 500", result.Statuses)
+	}
 
 	// Verify results array
 	if len(result.Results) != 2 {
@@ -80,17 +88,30 @@ func TestFormatBatchResultJSON(t *testing.T) {
 	if result.Results[0].Name != "Test API" {
 		t.Errorf("Results[0].Name = %s, want 'Test API'", result.Results[0].Name)
 	}
+	if result.Results[0].TTFB != 120 {
+		t.Errorf("Results[0].TTFB = %d, want 120", result.Results[0].TTFB)
+	}
 	if result.Results[0].Success != true {
 		t.Errorf("Results[0].Success = %v, want true", result.Results[0].Success)
 	}
+	if result.Results[0].Attempts != 2 || !result.Results[0].Flaky || result.Flaky != 1 {
+		t.Errorf("Results[0] attempts, flaky = %d, %v (total flaky %d); want 2, true (1)",
+			result.Results[0].Attempts, result.Results[0].Flaky, result.Flaky)
+	}
 	if result.Results[0].Status != 200 {
 		t.Errorf("Results[0].Status = %d, want 200", result.Results[0].Status)
 	}
+	if len(result.Results[0].Tags) != 1 || result.Results[0].Tags[0] != "critical" {
+		t.Errorf("Results[0].Tags = %v, want [critical]", result.Results[0].Tags)
+	}
 
 	// Check second result (failed)
 	if result.Results[1].Name != "Broken API" {
 		t.Errorf("Results[1].Name = %s, want 'Broken API'", result.Results[1].Name)
 	}
+	if result.Results[1].Tags != nil {
+		t.Errorf("Results[1].Tags = %v, want none", result.Results[1].Tags)
+	}
 	if result.Results[1].Success != false {
 		t.Errorf("Results[1].Success = %v, want false", result.Results[1].Success)
 	}
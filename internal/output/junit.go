@@ -0,0 +1,132 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the endpoints of one batch run.
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a single endpoint check.
+type JUnitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	Failure    *JUnitProblem    `xml:"failure,omitempty"`
+	Error      *JUnitProblem    `xml:"error,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+// JUnitProperties holds the properties of a test case, such as the
+// endpoint's tags.
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+// JUnitProperty is a single name/value property.
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitProblem describes why a test case failed or errored.
+type JUnitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// FormatBatchResultJUnit converts a batch summary to JUnit XML. Endpoints
+// whose request failed (network error, timeout) are reported as errors and
+// endpoints that failed a check (status, latency, headers) as failures.
+func FormatBatchResultJUnit(summary *stats.BatchSummary, suiteName string) (string, error) {
+	suite := JUnitTestSuite{
+		Name:  suiteName,
+		Tests: summary.Total,
+		Time:  seconds(summary.TotalTime.Seconds()),
+		Cases: make([]JUnitTestCase, len(summary.Results)),
+	}
+
+	for i, result := range summary.Results {
+		name := result.Name
+		if name == "" {
+			name = result.URL
+		}
+
+		testCase := JUnitTestCase{
+			Name:      name,
+			ClassName: suiteName,
+			Time:      seconds(result.Result.Latency.Seconds()),
+			SystemOut: fmt.Sprintf("%s %s -> %d", result.Method, result.URL, result.Result.StatusCode),
+		}
+		if len(result.Tags) > 0 {
+			testCase.Properties = &JUnitProperties{}
+			for _, tag := range result.Tags {
+				testCase.Properties.Properties = append(testCase.Properties.Properties, JUnitProperty{Name: "tag", Value: tag})
+			}
+		}
+		if result.Result.Download > 0 {
+			testCase.SystemOut += fmt.Sprintf(", %d bytes in %s", result.Result.Size, result.Result.Download.Round(time.Millisecond))
+		}
+
+		if result.Result.Error != nil {
+			testCase.Error = &JUnitProblem{
+				Message: result.Result.Error.Error(),
+				Type:    "RequestError",
+				Text:    result.Message,
+			}
+			suite.Errors++
+		} else if !result.Success {
+			testCase.Failure = &JUnitProblem{
+				Message: result.Message,
+				Type:    "AssertionFailure",
+				Text:    fmt.Sprintf("status %d (expected %d), latency %s", result.Result.StatusCode, result.ExpectedStatus, result.Result.Latency.Round(time.Millisecond)),
+			}
+			suite.Failures++
+		}
+
+		suite.Cases[i] = testCase
+	}
+
+	report := JUnitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []JUnitTestSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(data), nil
+}
+
+// seconds formats a duration in seconds the way JUnit readers expect.
+func seconds(s float64) string {
+	return fmt.Sprintf("%.3f", s)
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func TestFormatBatchResultJUnit(t *testing.T) {
+	summary := stats.NewBatchSummary()
+
+	summary.AddResult(stats.BatchResult{
+		Name:           "Health",
+		URL:            "https://example.com/health",
+		Method:         "GET",
+		ExpectedStatus: 200,
+		Success:        true,
+		Tags:           []string{"critical", "smoke"},
+		Result:         request.Result{StatusCode: 200, Latency: 150 * time.Millisecond},
+	})
+	summary.AddResult(stats.BatchResult{
+		Name:           "Users",
+		URL:            "https://example.com/users",
+		Method:         "GET",
+		ExpectedStatus: 200,
+		Message:        "Expected 200, got 500",
+		Result:         request.Result{StatusCode: 500, Latency: 250 * time.Millisecond},
+	})
+	summary.AddResult(stats.BatchResult{
+		Name:           "Down",
+		URL:            "https://down.example.com",
+		Method:         "GET",
+		ExpectedStatus: 200,
+		Message:        "Error: connection refused",
+		Result:         request.Result{Error: errors.New("connection refused")},
+	})
+
+	xmlOutput, err := FormatBatchResultJUnit(summary, "endpoints.yml")
+	if err != nil {
+		t.Fatalf("FormatBatchResultJUnit() error = %v", err)
+	}
+
+	var report JUnitTestSuites
+	if err := xml.Unmarshal([]byte(xmlOutput), &report); err != nil {
+		t.Fatalf("Invalid XML output: %v", err)
+	}
+
+	if report.Tests != 3 || report.Failures != 1 || report.Errors != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d errors; want 3, 1, 1", report.Tests, report.Failures, report.Errors)
+	}
+
+	cases := report.Suites[0].Cases
+	if cases[0].Failure != nil || cases[0].Error != nil {
+		t.Errorf("Health has a failure or error, want pass")
+	}
+	if cases[0].Properties == nil || len(cases[0].Properties.Properties) != 2 ||
+		cases[0].Properties.Properties[0] != (JUnitProperty{Name: "tag", Value: "critical"}) {
+		t.Errorf("Health properties = %+v, want tag properties for critical and smoke", cases[0].Properties)
+	}
+	if cases[1].Properties != nil {
+		t.Errorf("Users properties = %+v, want none", cases[1].Properties)
+	}
+	if cases[0].Time != "0.150" {
+		t.Errorf("Health time = %s, want 0.150", cases[0].Time)
+	}
+	if cases[1].Failure == nil || cases[1].Failure.Message != "Expected 200, got 500" {
+		t.Errorf("Users failure = %+v, want status mismatch", cases[1].Failure)
+	}
+	if cases[2].Error == nil || cases[2].Error.Message != "connection refused" {
+		t.Errorf("Down error = %+v, want connection refused", cases[2].Error)
+	}
+	if report.Suites[0].Name != "endpoints.yml" {
+		t.Errorf("suite name = %s, want endpoints.yml", report.Suites[0].Name)
+	}
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// ResultStream receives results as soon as they complete, and the summary
+// of the run at its end.
+type ResultStream interface {
+	Result(mode string, result stats.BatchResult, at time.Time) error
+	Summary(summary RunSummary) error
+}
+
+// NDJSONResult is a result line of an NDJSON stream: one endpoint check,
+// written as soon as it completes.
+type NDJSONResult struct {
+	Type string    `json:"type"` // Always "result"
+	Mode string    `json:"mode"` // batch or watch
+	Time time.Time `json:"time"`
+	JSONEndpoint
+}
+
+// NDJSONSummary is the last line of an NDJSON stream, with the statistics
+// of the whole run (without the results, which were already streamed).
+type NDJSONSummary struct {
+	Type string `json:"type"` // Always "summary"
+	RunSummary
+}
+
+// NDJSONStream writes results as newline-delimited JSON, one object per
+// line, so they can be piped into jq, Vector or Fluent Bit while the run
+// is still going. It is safe for concurrent use.
+type NDJSONStream struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewNDJSONStream creates a stream writing to w.
+func NewNDJSONStream(w io.Writer) *NDJSONStream {
+	return &NDJSONStream{w: w}
+}
+
+// Result writes the line of a completed check.
+func (s *NDJSONStream) Result(mode string, result stats.BatchResult, at time.Time) error {
+	return s.write(NDJSONResult{Type: "result", Mode: mode, Time: at, JSONEndpoint: toJSONEndpoint(result)})
+}
+
+// Summary writes the closing line of the run.
+func (s *NDJSONStream) Summary(summary RunSummary) error {
+	summary.Results = nil
+	summary.Targets = nil
+	return s.write(NDJSONSummary{Type: "summary", RunSummary: summary})
+}
+
+// write writes v as one line, in a single write so lines never interleave.
+func (s *NDJSONStream) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
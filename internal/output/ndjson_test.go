@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func TestNDJSONStream(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewNDJSONStream(&buf)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ok := stats.BatchResult{
+		Name:    "users",
+		URL:     "https://api.example.com/users",
+		Method:  "GET",
+		Success: true,
+		Result:  request.Result{StatusCode: 200, Latency: 142 * time.Millisecond},
+	}
+	failed := stats.BatchResult{
+		Name:   "orders",
+		URL:    "https://api.example.com/orders",
+		Method: "GET",
+		Result: request.Result{Error: errors.New("connection refused")},
+	}
+	if err := stream.Result("batch", ok, at); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Result("batch", failed, at); err != nil {
+		t.Fatal(err)
+	}
+	summary := RunSummary{Command: "batch", Results: []JSONEndpoint{{Name: "users"}}}
+	summary.Total = 2
+	if err := stream.Summary(summary); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line 1 is not JSON: %v", err)
+	}
+	if first["type"] != "result" || first["mode"] != "batch" || first["name"] != "users" ||
+		first["status"] != float64(200) || first["latency_ms"] != float64(142) || first["time"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("line 1 = %s", lines[0])
+	}
+
+	var second map[string]any
+	json.Unmarshal([]byte(lines[1]), &second)
+	if second["success"] != false || second["error"] != "connection refused" {
+		t.Errorf("line 2 = %s", lines[1])
+	}
+
+	var last map[string]any
+	json.Unmarshal([]byte(lines[2]), &last)
+	if last["type"] != "summary" || last["total"] != float64(2) {
+		t.Errorf("line 3 = %s", lines[2])
+	}
+	if _, ok := last["results"]; ok {
+		t.Errorf("summary line repeats the results: %s", lines[2])
+	}
+}
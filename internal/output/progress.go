@@ -0,0 +1,103 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressWidth is the number of cells in the progress bar.
+const progressWidth = 30
+
+// Progress draws a live progress bar on one terminal line, e.g.
+//
+//	[██████████░░░░░░░░░░░░░░░░░░░░] 42/120  3 failed  ETA 12s
+//
+// It redraws whenever a task finishes and once a second in between, so the
+// elapsed time keeps moving while slow tasks run. Call Stop before printing
+// anything else to w.
+type Progress struct {
+	w       io.Writer
+	total   int
+	started time.Time
+
+	mu     sync.Mutex
+	done   int
+	failed int
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewProgress starts drawing a progress bar for total tasks on w, which
+// should be a terminal.
+func NewProgress(w io.Writer, total int) *Progress {
+	p := &Progress{w: w, total: total, started: time.Now(), stop: make(chan struct{})}
+	p.draw()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.draw()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// Add records a finished task and redraws the bar.
+func (p *Progress) Add(success bool) {
+	p.mu.Lock()
+	p.done++
+	if !success {
+		p.failed++
+	}
+	p.mu.Unlock()
+	p.draw()
+}
+
+// Stop stops redrawing and erases the bar.
+func (p *Progress) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+// draw redraws the bar in place.
+func (p *Progress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprint(p.w, "\r"+FormatProgress(p.done, p.total, p.failed, time.Since(p.started))+"\033[K")
+}
+
+// FormatProgress renders one line of a progress bar: the bar, done/total,
+// the failures so far and the estimated time left, extrapolated from the
+// average time per task.
+func FormatProgress(done, total, failed int, elapsed time.Duration) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done*progressWidth/total, progressWidth)
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+
+	line := fmt.Sprintf("[%s] %d/%d", bar, done, total)
+	if failed > 0 {
+		line += "  " + Red(fmt.Sprintf("%d failed", failed))
+	}
+	switch {
+	case done == 0 || done >= total:
+		line += fmt.Sprintf("  %s elapsed", elapsed.Round(time.Second))
+	default:
+		eta := elapsed / time.Duration(done) * time.Duration(total-done)
+		line += fmt.Sprintf("  ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatProgress(t *testing.T) {
+	SetColorEnabled(false)
+	defer SetColorEnabled(true)
+
+	tests := []struct {
+		done, total, failed int
+		elapsed             time.Duration
+		want                string
+	}{
+		{0, 10, 0, 0, "[░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░] 0/10  0s elapsed"},
+		{5, 10, 2, 4 * time.Second, "[███████████████░░░░░░░░░░░░░░░] 5/10  2 failed  ETA 4s"},
+		{1, 3, 0, 10 * time.Second, "[██████████░░░░░░░░░░░░░░░░░░░░] 1/3  ETA 20s"},
+		{10, 10, 0, 12 * time.Second, "[██████████████████████████████] 10/10  12s elapsed"},
+	}
+	for _, tt := range tests {
+		if got := FormatProgress(tt.done, tt.total, tt.failed, tt.elapsed); got != tt.want {
+			t.Errorf("FormatProgress(%d, %d, %d, %v) = %q, want %q", tt.done, tt.total, tt.failed, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestProgressStopErasesLine(t *testing.T) {
+	var buf bytes.Buffer
+	progress := NewProgress(&buf, 2)
+	progress.Add(true)
+	progress.Add(false)
+	progress.Stop()
+
+	got := buf.String()
+	if !strings.Contains(got, "2/2") || !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("output = %q, want the finished bar, then an erased line", got)
+	}
+}
@@ -0,0 +1,37 @@
+package output
+
+import "strings"
+
+// sparkBars are the block characters of a sparkline, lowest first.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a row of block characters scaled between their
+// minimum and maximum. Negative values are drawn as a space (no data).
+func Sparkline(values []float64) string {
+	low, high := -1.0, -1.0
+	for _, v := range values {
+		if v < 0 {
+			continue
+		}
+		if low < 0 || v < low {
+			low = v
+		}
+		if v > high {
+			high = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case v < 0:
+			b.WriteRune(' ')
+		case high == low:
+			b.WriteRune(sparkBars[len(sparkBars)/2])
+		default:
+			level := int((v - low) / (high - low) * float64(len(sparkBars)-1))
+			b.WriteRune(sparkBars[level])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,228 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// RunSummary is the machine-readable document written by --summary-file.
+// It has the same shape for every command so CI jobs can consume it
+// without knowing how tapr was invoked.
+type RunSummary struct {
+	Command   string    `json:"command"`
+	Target    string    `json:"target"` // URL, config file for batch, or URLs joined by ", " for multi-URL watch
+	StartedAt time.Time `json:"started_at"`
+	Duration  int64     `json:"duration_ms"`
+	ExitCode  int       `json:"exit_code"`
+	SummaryStats
+	Results []JSONEndpoint  `json:"results,omitempty"`
+	Targets []TargetSummary `json:"targets,omitempty"` // Per URL, for multi-URL watch
+}
+
+// SummaryStats holds the request statistics of a run or of one target.
+type SummaryStats struct {
+	Total        int            `json:"total"`
+	Successful   int            `json:"successful"`
+	Failed       int            `json:"failed"`
+	SuccessRate  float64        `json:"success_rate"`
+	Latency      *JSONLatency   `json:"latency,omitempty"`
+	Availability *JSONUptime    `json:"availability,omitempty"`
+	Flaps        int            `json:"flaps,omitempty"`             // Times the endpoint started flapping (watch only)
+	Flapping     bool           `json:"flapping,omitempty"`          // Still flapping at the end (watch only)
+	Anomalies    int            `json:"latency_anomalies,omitempty"` // Checks much slower than the learned baseline (watch only)
+	Statuses     map[string]int `json:"statuses"`
+	Errors       map[string]int `json:"errors,omitempty"`
+}
+
+// TargetSummary holds the statistics of one URL of a multi-URL watch.
+type TargetSummary struct {
+	Target string `json:"target"`
+	SummaryStats
+}
+
+// JSONLatency holds latency statistics in milliseconds.
+type JSONLatency struct {
+	Min float64 `json:"min_ms"`
+	Avg float64 `json:"avg_ms"`
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// JSONUptime holds time-based availability (watch only).
+type JSONUptime struct {
+	Percent       float64 `json:"uptime_percent"`
+	Incidents     int     `json:"incidents"`
+	Downtime      int64   `json:"downtime_ms"`
+	MTTR          int64   `json:"mttr_ms"`
+	LongestOutage int64   `json:"longest_outage_ms"`
+	SLO           float64 `json:"slo_percent,omitempty"`
+	ErrorBudget   *int64  `json:"error_budget_ms,omitempty"` // Downtime left under the SLO; negative once breached
+}
+
+// UptimeSummary converts availability up to now. slo is the target in
+// percent, or 0 for none.
+func UptimeSummary(uptime *stats.Uptime, slo float64, now time.Time) *JSONUptime {
+	summary := &JSONUptime{
+		Percent:       uptime.Percent(now),
+		Incidents:     len(uptime.Outages()),
+		Downtime:      uptime.Downtime(now).Milliseconds(),
+		MTTR:          uptime.MTTR().Milliseconds(),
+		LongestOutage: uptime.LongestOutage(now).Milliseconds(),
+	}
+	if slo > 0 {
+		_, remaining := uptime.ErrorBudget(slo, now)
+		budget := remaining.Milliseconds()
+		summary.SLO = slo
+		summary.ErrorBudget = &budget
+	}
+	return summary
+}
+
+// TrackerSummary builds a run summary from tracker statistics (ping and
+// watch).
+func TrackerSummary(tracker *stats.Tracker) RunSummary {
+	summary := RunSummary{SummaryStats: SummaryStats{
+		Total:       tracker.Total,
+		Successful:  tracker.Successful,
+		Failed:      tracker.Failed,
+		SuccessRate: tracker.SuccessRate(),
+		Statuses:    tracker.Statuses,
+		Errors:      tracker.Errors,
+	}}
+	if tracker.Total > 0 {
+		summary.Latency = &JSONLatency{
+			Min: milliseconds(tracker.MinLatency),
+			Avg: milliseconds(tracker.AvgLatency()),
+			P50: milliseconds(tracker.Percentile(0.50)),
+			P95: milliseconds(tracker.Percentile(0.95)),
+			P99: milliseconds(tracker.Percentile(0.99)),
+			Max: milliseconds(tracker.MaxLatency),
+		}
+	}
+	return summary
+}
+
+// BatchSummary builds a run summary from batch results, including every
+// endpoint result.
+func BatchSummary(batch *stats.BatchSummary) RunSummary {
+	tracker := stats.NewTracker()
+	results := make([]JSONEndpoint, len(batch.Results))
+	for i, result := range batch.Results {
+		if !result.Skipped {
+			tracker.Record(result.Result.Latency, result.Success)
+		}
+		results[i] = toJSONEndpoint(result)
+	}
+
+	summary := TrackerSummary(tracker)
+	summary.Total = batch.Total
+	summary.Successful = batch.Successful
+	summary.Failed = batch.Failed
+	summary.SuccessRate = batch.SuccessRate()
+	summary.Statuses = batch.Statuses
+	summary.Errors = batch.Errors
+	summary.Results = results
+	return summary
+}
+
+// FormatBatchResultSummary formats a batch summary as the counts on one
+// line followed by the names of the failed endpoints, one per line, for
+// cron mails and chat notifications.
+func FormatBatchResultSummary(summary *stats.BatchSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d endpoints: %d passed, %d failed", summary.Total, summary.Successful, summary.Failed)
+	var notes []string
+	if summary.Slow > 0 {
+		notes = append(notes, fmt.Sprintf("%d slow", summary.Slow))
+	}
+	if summary.Flaky > 0 {
+		notes = append(notes, fmt.Sprintf("%d flaky", summary.Flaky))
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(notes, ", "))
+	}
+	fmt.Fprintf(&b, " in %s\n", summary.TotalTime.Round(10*time.Millisecond))
+
+	for _, result := range summary.Results {
+		switch {
+		case result.Success:
+		case result.Silenced != "":
+			fmt.Fprintf(&b, "%s (silenced: %s)\n", result.Name, result.Silenced)
+		case result.Upstream != "":
+			fmt.Fprintf(&b, "%s (upstream: %s)\n", result.Name, result.Upstream)
+		default:
+			fmt.Fprintln(&b, result.Name)
+		}
+	}
+	return b.String()
+}
+
+// CombineTargets builds a run summary from several targets, adding up
+// their counts. Latency percentiles can't be combined, so per-target
+// latency is only in Targets.
+func CombineTargets(targets []TargetSummary) RunSummary {
+	summary := RunSummary{Targets: targets}
+	summary.Statuses = make(map[string]int)
+	for _, target := range targets {
+		summary.Total += target.Total
+		summary.Successful += target.Successful
+		summary.Failed += target.Failed
+		summary.Flaps += target.Flaps
+		summary.Anomalies += target.Anomalies
+		summary.Flapping = summary.Flapping || target.Flapping
+		for label, count := range target.Statuses {
+			summary.Statuses[label] += count
+		}
+		for category, count := range target.Errors {
+			if summary.Errors == nil {
+				summary.Errors = make(map[string]int)
+			}
+			summary.Errors[category] += count
+		}
+	}
+	if summary.Total > 0 {
+		summary.SuccessRate = float64(summary.Successful) / float64(summary.Total) * 100
+	}
+	return summary
+}
+
+// WriteSummaryFile writes summary as indented JSON to path.
+func WriteSummaryFile(path string, summary RunSummary) error {
+	if summary.Statuses == nil {
+		summary.Statuses = map[string]int{}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	return nil
+}
+
+// ReadSummaryFile reads a summary written by WriteSummaryFile.
+func ReadSummaryFile(path string) (RunSummary, error) {
+	var summary RunSummary
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return summary, fmt.Errorf("failed to read summary file: %w", err)
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return summary, fmt.Errorf("failed to parse summary file %s: %w", path, err)
+	}
+	return summary, nil
+}
+
+// milliseconds converts a duration to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func TestBatchSummary(t *testing.T) {
+	batch := stats.NewBatchSummary()
+	batch.AddResult(stats.BatchResult{
+		Name: "ok", Success: true,
+		Result: request.Result{URL: "https://x.io", StatusCode: 200, Latency: 100 * time.Millisecond},
+	})
+	batch.AddResult(stats.BatchResult{
+		Name:   "down",
+		Result: request.Result{URL: "https://x.io/down", StatusCode: 503, Latency: 300 * time.Millisecond},
+	})
+	batch.AddResult(stats.BatchResult{Name: "skipped", Skipped: true, Message: "Skipped: dependency 'down' failed"})
+
+	summary := BatchSummary(batch)
+	summary.Command, summary.ExitCode = "batch", 1
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := WriteSummaryFile(path, summary); err != nil {
+		t.Fatalf("WriteSummaryFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got RunSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if got.Command != "batch" || got.ExitCode != 1 || got.Total != 3 || got.Failed != 2 {
+		t.Errorf("summary = %+v", got)
+	}
+	if len(got.Results) != 3 || got.Results[1].Status != 503 {
+		t.Errorf("results = %+v", got.Results)
+	}
+	// Skipped endpoints were never requested, so they don't affect latency
+	if got.Latency == nil || got.Latency.Min != 100 || got.Latency.Max != 300 {
+		t.Errorf("latency = %+v, want min 100ms and max 300ms", got.Latency)
+	}
+	if got.Errors["5xx"] != 1 || got.Errors[stats.StatusSkipped] != 1 {
+		t.Errorf("errors = %v", got.Errors)
+	}
+}
+
+func TestFormatBatchResultSummary(t *testing.T) {
+	batch := stats.NewBatchSummary()
+	batch.AddResult(stats.BatchResult{Name: "ok", Success: true, Attempts: 1, Result: request.Result{StatusCode: 200, Latency: 100 * time.Millisecond}})
+	batch.AddResult(stats.BatchResult{Name: "retried", Success: true, Attempts: 2, Result: request.Result{StatusCode: 200, Latency: 100 * time.Millisecond}})
+	batch.AddResult(stats.BatchResult{Name: "down", Result: request.Result{StatusCode: 503, Latency: 300 * time.Millisecond}})
+	batch.AddResult(stats.BatchResult{Name: "orders", Silenced: "deploy window", Result: request.Result{StatusCode: 502}})
+	batch.AddResult(stats.BatchResult{Name: "skipped", Skipped: true, Upstream: "down"})
+	batch.TotalTime = 1234 * time.Millisecond
+
+	want := "5 endpoints: 2 passed, 3 failed (1 flaky) in 1.23s\n" +
+		"down\n" +
+		"orders (silenced: deploy window)\n" +
+		"skipped (upstream: down)\n"
+	if got := FormatBatchResultSummary(batch); got != want {
+		t.Errorf("FormatBatchResultSummary() =\n%s\nwant\n%s", got, want)
+	}
+
+	// An all-green run prints the counts alone
+	green := stats.NewBatchSummary()
+	green.AddResult(stats.BatchResult{Name: "ok", Success: true, Attempts: 1, Result: request.Result{StatusCode: 200}})
+	if got := FormatBatchResultSummary(green); got != "1 endpoints: 1 passed, 0 failed in 0s\n" {
+		t.Errorf("FormatBatchResultSummary() = %q", got)
+	}
+}
+
+func TestTrackerSummary(t *testing.T) {
+	tracker := stats.NewTracker()
+	if summary := TrackerSummary(tracker); summary.Latency != nil {
+		t.Errorf("Latency = %+v, want nil without requests", summary.Latency)
+	}
+
+	tracker.Record(1500*time.Microsecond, true)
+	tracker.RecordStatus("200")
+	summary := TrackerSummary(tracker)
+	if summary.Latency == nil || summary.Latency.Avg != 1.5 || summary.Statuses["200"] != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestUptimeSummary(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	var uptime stats.Uptime
+	uptime.Observe(true, start)
+	uptime.Observe(false, start.Add(50*time.Second))
+	uptime.Observe(true, start.Add(60*time.Second))
+
+	// 99% of 100s allows 1s of downtime; 10s were used
+	summary := UptimeSummary(&uptime, 99, start.Add(100*time.Second))
+	if summary.Percent != 90 || summary.Incidents != 1 || summary.MTTR != 10000 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.ErrorBudget == nil || *summary.ErrorBudget != -9000 {
+		t.Errorf("ErrorBudget = %v, want -9000ms", summary.ErrorBudget)
+	}
+	if UptimeSummary(&uptime, 0, start).ErrorBudget != nil {
+		t.Error("ErrorBudget set without an SLO")
+	}
+}
+
+func TestCombineTargets(t *testing.T) {
+	targets := []TargetSummary{
+		{Target: "https://a.io", SummaryStats: SummaryStats{Total: 3, Successful: 3, Statuses: map[string]int{"200": 3}}},
+		{Target: "https://b.io", SummaryStats: SummaryStats{
+			Total: 1, Failed: 1,
+			Statuses: map[string]int{"timeout": 1},
+			Errors:   map[string]int{"timeout": 1},
+		}},
+	}
+
+	summary := CombineTargets(targets)
+	if summary.Total != 4 || summary.Successful != 3 || summary.SuccessRate != 75 {
+		t.Errorf("summary = %+v, want 3 of 4 successful", summary.SummaryStats)
+	}
+	if summary.Statuses["200"] != 3 || summary.Statuses["timeout"] != 1 || summary.Errors["timeout"] != 1 {
+		t.Errorf("statuses = %v, errors = %v", summary.Statuses, summary.Errors)
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded["total"]; !ok {
+		t.Errorf("stats not inlined in JSON: %s", data)
+	}
+	if list, ok := decoded["targets"].([]interface{}); !ok || len(list) != 2 {
+		t.Errorf("targets = %v, want 2 entries", decoded["targets"])
+	}
+}
@@ -0,0 +1,317 @@
+package output
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis marks truncated text.
+const ellipsis = "…"
+
+// DisplayWidth returns how many terminal columns s takes up. ANSI escape
+// codes take none, emoji and East Asian wide characters take two.
+func DisplayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// escapeLength returns the length of the ANSI escape sequence at the start
+// of s, or 0 if there is none.
+func escapeLength(s string) int {
+	if !strings.HasPrefix(s, "\033[") {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// runeWidth returns how many columns r takes up.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0xfe0f:
+		return 1 // Emoji presentation: the symbol before it becomes wide
+	case r == 0x200b || r == 0x200c || r == 0x200d || (r >= 0x0300 && r <= 0x036f) || (r >= 0xfe00 && r <= 0xfe0e):
+		return 0
+	case r >= 0x1100 && r <= 0x115f, r >= 0x2e80 && r <= 0xa4cf, r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff, r >= 0xfe30 && r <= 0xfe4f, r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6, r >= 0x1f300 && r <= 0x1faff, r >= 0x20000 && r <= 0x3fffd,
+		r == 0x231a || r == 0x231b || r == 0x23f3 || r == 0x26a1:
+		return 2
+	}
+	return 1
+}
+
+// Truncate shortens s to at most width columns, ending it with an ellipsis
+// if anything was cut. Escape codes are kept, and a reset is added after a
+// cut so colors don't leak.
+func Truncate(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	used, colored := 0, false
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			colored = true
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if used+runeWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += runeWidth(r)
+		i += size
+	}
+	b.WriteString(ellipsis)
+	if colored {
+		b.WriteString(ColorReset)
+	}
+	return b.String()
+}
+
+// TruncateMiddle shortens plain text such as a URL to at most width
+// columns by cutting out its middle, so both the host and the end of the
+// path stay visible. Colored text is truncated at the end instead.
+func TruncateMiddle(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if width <= 1 || strings.Contains(s, "\033[") {
+		return Truncate(s, width)
+	}
+	runes := []rune(s)
+	head := (width - 1) / 2
+	tail := width - 1 - head
+	return string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
+}
+
+// Pad appends spaces to s until it is width columns wide.
+func Pad(s string, width int) string {
+	if n := width - DisplayWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft prepends spaces to s until it is width columns wide.
+func PadLeft(s string, width int) string {
+	if n := width - DisplayWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Column describes a table column.
+type Column struct {
+	Header string
+	Right  bool // Align right
+	Flex   bool // Shrink to fit the table into its width, truncating cells
+	Middle bool // Truncate Flex cells in the middle (for URLs)
+	Min    int  // Narrowest a Flex column gets (0 = its header width, at least 8)
+}
+
+// Table renders rows in columns sized to their content. Columns marked
+// Flex shrink when the table is wider than Width, so long names and URLs
+// are truncated instead of wrapping. The last column is never padded, so
+// long messages in it don't widen the table.
+type Table struct {
+	Columns []Column
+	Indent  string // Prefix of every line
+	Gap     int    // Spaces between columns (0 = 2)
+	Width   int    // Widest a line may get, including Indent (0 = unlimited)
+	rows    []tableRow
+}
+
+// tableRow is a row of cells, optionally followed by text spanning the
+// remaining columns, or a heading line (no cells, only text).
+type tableRow struct {
+	cells []string
+	span  string
+}
+
+// NewTable creates a table with the given columns.
+func NewTable(columns ...Column) *Table {
+	return &Table{Columns: columns}
+}
+
+// AddRow adds a row. Missing cells are left empty.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, tableRow{cells: cells})
+}
+
+// AddTitle adds a heading line, such as the name of a batch stage.
+func (t *Table) AddTitle(title string) {
+	t.rows = append(t.rows, tableRow{span: title})
+}
+
+// AddSpan adds a row of the given leading cells followed by text running
+// across the remaining columns, such as why an endpoint was skipped or an
+// error below the row it belongs to. The text doesn't widen the columns.
+func (t *Table) AddSpan(text string, cells ...string) {
+	t.rows = append(t.rows, tableRow{cells: append([]string{}, cells...), span: text})
+}
+
+// Render returns the header, a rule and the rows, each line ending in a
+// newline. Text is drawn with the current theme before it is measured.
+func (t *Table) Render() string {
+	gap := t.Gap
+	if gap == 0 {
+		gap = 2
+	}
+	widths := t.columnWidths(gap)
+
+	var b strings.Builder
+	headers := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		headers[i] = column.Header
+	}
+	t.writeLine(&b, tableRow{cells: headers}, widths, gap)
+
+	// The rule spans the columns, the last one up to its widest cell, and
+	// stops at t.Width, but is never shorter than the header line
+	last := len(widths) - 1
+	lastHeader := DisplayWidth(theme.Render(t.Columns[last].Header))
+	header := len(t.Indent) + gap*last + lastHeader
+	for _, width := range widths[:last] {
+		header += width
+	}
+	rule := header + max(widths[last]-lastHeader, 0)
+	if t.Width > 0 {
+		rule = max(min(rule, t.Width), header)
+	}
+	b.WriteString(t.Indent + strings.Repeat("─", rule-len(t.Indent)) + "\n")
+
+	for _, row := range t.rows {
+		t.writeLine(&b, row, widths, gap)
+	}
+	return b.String()
+}
+
+// columnWidths sizes every column to its widest cell, then shrinks Flex
+// columns while the table is wider than t.Width.
+func (t *Table) columnWidths(gap int) []int {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = DisplayWidth(theme.Render(column.Header))
+	}
+	for _, row := range t.rows {
+		for i, cell := range row.cells {
+			if i < len(widths) {
+				widths[i] = max(widths[i], DisplayWidth(theme.Render(cell)))
+			}
+		}
+	}
+	if t.Width <= 0 {
+		return widths
+	}
+
+	// The last column may overflow, so only its header has to fit
+	last := len(widths) - 1
+	total := len(t.Indent) + gap*last
+	for i, width := range widths {
+		if i == last && !t.Columns[i].Flex {
+			width = DisplayWidth(t.Columns[i].Header)
+		}
+		total += width
+	}
+
+	for i, column := range t.Columns {
+		if total <= t.Width {
+			break
+		}
+		if !column.Flex {
+			continue
+		}
+		least := column.Min
+		if least == 0 {
+			least = max(DisplayWidth(column.Header), 8)
+		}
+		cut := min(total-t.Width, widths[i]-least)
+		if cut > 0 {
+			widths[i] -= cut
+			total -= cut
+		}
+	}
+	return widths
+}
+
+// writeLine writes one row.
+func (t *Table) writeLine(b *strings.Builder, row tableRow, widths []int, gap int) {
+	b.WriteString(t.Indent)
+	cells := row.cells
+	for i, column := range t.Columns {
+		if row.span != "" && i == len(cells) {
+			b.WriteString(theme.Render(row.span))
+			break
+		}
+		cell := ""
+		if i < len(cells) {
+			cell = theme.Render(cells[i])
+		}
+		if DisplayWidth(cell) > widths[i] {
+			if column.Middle {
+				cell = TruncateMiddle(cell, widths[i])
+			} else {
+				cell = Truncate(cell, widths[i])
+			}
+		}
+
+		switch {
+		case column.Right:
+			cell = PadLeft(cell, widths[i])
+		case i < len(widths)-1:
+			cell = Pad(cell, widths[i])
+		}
+		b.WriteString(cell)
+		if i < len(widths)-1 {
+			b.WriteString(strings.Repeat(" ", gap))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// boxWidth is the inner width of boxes that fit their content.
+const boxWidth = 69
+
+// Box draws lines in a box 69 columns wide inside, wider if a line needs
+// more room, but no wider than maxWidth columns in total (0 = unlimited).
+// Lines that still don't fit are truncated.
+func Box(maxWidth int, lines ...string) string {
+	inner := boxWidth
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		rendered[i] = theme.Render(line)
+		inner = max(inner, DisplayWidth(rendered[i])+2)
+	}
+	if maxWidth > 0 {
+		inner = min(inner, max(maxWidth-2, 12))
+	}
+
+	var b strings.Builder
+	b.WriteString("┌" + strings.Repeat("─", inner) + "┐\n")
+	for _, line := range rendered {
+		b.WriteString("│ " + Pad(Truncate(line, inner-2), inner-2) + " │\n")
+	}
+	b.WriteString("└" + strings.Repeat("─", inner) + "┘\n")
+	return b.String()
+}
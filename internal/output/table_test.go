@@ -0,0 +1,170 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"tapr":                    4,
+		"\033[32m✓\033[0m ok":     4,
+		"📊 Summary":               10,
+		"⚠️  SLOW":                8,
+		"https://api.example.com": 23,
+	}
+	for in, want := range tests {
+		if got := DisplayWidth(in); got != want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("short", 10); got != "short" {
+		t.Errorf("Truncate() = %q, want the text unchanged", got)
+	}
+	if got := Truncate("users-service-health", 10); got != "users-ser…" {
+		t.Errorf("Truncate() = %q, want %q", got, "users-ser…")
+	}
+	if got := Truncate("\033[31mfailed request\033[0m", 7); got != "\033[31mfailed…\033[0m" {
+		t.Errorf("Truncate() = %q, want the color kept and reset", got)
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	got := TruncateMiddle("api.example.com/v1/users/profile", 15)
+	if got != "api.exa…profile" {
+		t.Errorf("TruncateMiddle() = %q, want %q", got, "api.exa…profile")
+	}
+	if DisplayWidth(got) != 15 {
+		t.Errorf("width = %d, want 15", DisplayWidth(got))
+	}
+}
+
+func TestTableSizesColumns(t *testing.T) {
+	table := NewTable(Column{Header: "NAME"}, Column{Header: "REQS", Right: true}, Column{Header: "STATUS"})
+	table.AddRow("users", "12", "ok")
+	table.AddRow("orders-api", "3", "failing")
+
+	want := "NAME        REQS  STATUS\n" +
+		"─────────────────────────\n" +
+		"users         12  ok\n" +
+		"orders-api     3  failing\n"
+	if got := table.Render(); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTableSpan(t *testing.T) {
+	table := NewTable(Column{Header: "SERVICE"}, Column{Header: "TARGET"}, Column{Header: "STATUS"})
+	table.AddRow("web", "localhost:8080", "200")
+	table.AddSpan("✗ connection refused by the server", "")
+	table.AddSpan("– no ports published", "worker")
+
+	want := "SERVICE  TARGET          STATUS\n" +
+		"───────────────────────────────\n" +
+		"web      localhost:8080  200\n" +
+		"         ✗ connection refused by the server\n" +
+		"worker   – no ports published\n"
+	if got := table.Render(); got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTableFitsWidth(t *testing.T) {
+	table := NewTable(
+		Column{Header: "ENDPOINT", Flex: true, Middle: true},
+		Column{Header: "STATUS"},
+	)
+	table.Indent = "   "
+	table.Width = 30
+	table.AddRow("api.example.com/v1/users/profile/settings", "200")
+	table.AddTitle("stage")
+
+	lines := strings.Split(strings.TrimSuffix(table.Render(), "\n"), "\n")
+	for _, line := range lines {
+		if DisplayWidth(line) > 30 {
+			t.Errorf("line %q is %d columns wide, want at most 30", line, DisplayWidth(line))
+		}
+	}
+	if !strings.Contains(lines[2], "…") || !strings.HasSuffix(lines[2], "200") {
+		t.Errorf("row = %q, want the URL truncated and the status kept", lines[2])
+	}
+	if lines[3] != "   stage" {
+		t.Errorf("title = %q, want %q", lines[3], "   stage")
+	}
+
+	// Without a width nothing is cut
+	table.Width = 0
+	if !strings.Contains(table.Render(), "api.example.com/v1/users/profile/settings") {
+		t.Error("Render() truncated a table without a width")
+	}
+}
+
+func TestTableRuleCoversHeader(t *testing.T) {
+	// The batch columns, narrower than the header, with a stage heading
+	table := NewTable(
+		Column{Header: "ENDPOINT", Flex: true, Min: 12},
+		Column{Header: "METHOD"},
+		Column{Header: "STATUS"},
+		Column{Header: "LATENCY"},
+		Column{Header: "SIZE"},
+		Column{Header: "RESULT"},
+	)
+	table.Gap = 1
+	table.Width = 40
+	table.AddTitle("▸ infra")
+	table.AddRow("db", "GET", "200", "21ms", "-", "✓")
+	table.AddRow("api", "GET", "500", "9ms", "-", "✗ Expected 200, got 500 from the upstream service")
+
+	lines := strings.Split(table.Render(), "\n")
+	if header, rule := DisplayWidth(lines[0]), DisplayWidth(lines[1]); rule != header {
+		t.Errorf("rule is %d columns wide, header %d; want the same", rule, header)
+	}
+
+	// With room to spare, the rule runs on under long results
+	table.Width = 60
+	lines = strings.Split(table.Render(), "\n")
+	if header, rule := DisplayWidth(lines[0]), DisplayWidth(lines[1]); rule != 60 || rule < header {
+		t.Errorf("rule is %d columns wide (header %d), want 60", rule, header)
+	}
+}
+
+func TestBox(t *testing.T) {
+	box := Box(0, "Watching: https://api.example.com")
+	lines := strings.Split(strings.TrimSuffix(box, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Box() has %d lines, want 3", len(lines))
+	}
+	for _, line := range lines {
+		if DisplayWidth(line) != boxWidth+2 {
+			t.Errorf("line %q is %d columns wide, want %d", line, DisplayWidth(line), boxWidth+2)
+		}
+	}
+
+	long := "Trace: https://api.example.com/" + strings.Repeat("x", 100)
+	if got := Box(0, long); !strings.Contains(got, long) {
+		t.Error("Box(0) truncated a line, want the box widened")
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(Box(40, long), "\n"), "\n") {
+		if DisplayWidth(line) != 40 {
+			t.Errorf("line %q is %d columns wide, want 40", line, DisplayWidth(line))
+		}
+	}
+}
+
+func TestBoxASCII(t *testing.T) {
+	defer SetTheme(UnicodeTheme)
+	SetTheme(ASCIITheme)
+
+	// The box is measured as printed, without the emoji
+	box := ASCIITheme.Render(Box(0, "📋 Watch Summary"))
+	lines := strings.Split(strings.TrimSuffix(box, "\n"), "\n")
+	if lines[1] != "| Watch Summary"+strings.Repeat(" ", boxWidth-len(" Watch Summary"))+"|" {
+		t.Errorf("line = %q, want the text padded to the frame", lines[1])
+	}
+	if len(lines[0]) != len(lines[1]) {
+		t.Errorf("frame is %d columns wide, text line %d", len(lines[0]), len(lines[1]))
+	}
+}
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// TemplateResult is the data an --output-template is executed with, once
+// per result.
+type TemplateResult struct {
+	Mode           string        // ping, batch or watch
+	Time           time.Time     // When the check completed
+	Name           string        // Endpoint name (the URL for ping and watch)
+	URL            string        // Requested URL
+	FinalURL       string        // URL of the final response, after redirects
+	Method         string        // HTTP method
+	Status         int           // Status code (0 if the request failed)
+	StatusText     string        // Status line, e.g. "200 OK"
+	ExpectedStatus int           // Expected status code (batch only)
+	Latency        time.Duration // Total time of the request
+	LatencyMs      float64       // Latency in milliseconds
+	TTFBMs         float64       // Time to first byte in milliseconds
+	Size           int64         // Response body size in bytes (-1 if unknown)
+	Protocol       string        // e.g. "HTTP/2.0"
+	RemoteAddr     string        // Server address connected to ("ip:port")
+	Header         http.Header   // Response headers: {{.Header.Get "Content-Type"}}
+	Success        bool          // Whether the check passed
+	Attempts       int           // Requests made, including retries (batch only)
+	Tags           []string      // Endpoint tags (batch only)
+	Stage          string        // Endpoint stage (batch only)
+	Error          string        // Why the check failed ("" on success)
+}
+
+// templateFuncs are the functions available in output templates.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// ParseTemplate parses an output template and tries it on an empty result,
+// so a misspelled field is reported before any request is made.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, TemplateResult{}); err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// NewTemplateResult converts a result for a template.
+func NewTemplateResult(mode string, result stats.BatchResult, at time.Time) TemplateResult {
+	data := TemplateResult{
+		Mode:           mode,
+		Time:           at,
+		Name:           result.Name,
+		URL:            result.URL,
+		FinalURL:       result.Result.FinalURL,
+		Method:         result.Method,
+		Status:         result.Result.StatusCode,
+		StatusText:     result.Result.Status,
+		ExpectedStatus: result.ExpectedStatus,
+		Latency:        result.Result.Latency,
+		LatencyMs:      float64(result.Result.Latency.Microseconds()) / 1000,
+		TTFBMs:         float64(result.Result.TTFB.Microseconds()) / 1000,
+		Size:           result.Result.Size,
+		Protocol:       result.Result.Protocol,
+		RemoteAddr:     result.Result.RemoteAddr,
+		Header:         result.Result.Headers,
+		Success:        result.Success,
+		Attempts:       result.Attempts,
+		Tags:           result.Tags,
+		Stage:          result.Stage,
+	}
+	if result.Result.Error != nil {
+		data.Error = result.Result.Error.Error()
+	} else if !result.Success {
+		data.Error = result.Message
+	}
+	if data.Header == nil {
+		data.Header = http.Header{}
+	}
+	return data
+}
+
+// TemplateStream writes a line per result, rendered with an output
+// template. It is safe for concurrent use.
+type TemplateStream struct {
+	mu   sync.Mutex
+	w    io.Writer
+	tmpl *template.Template
+}
+
+// NewTemplateStream creates a stream rendering results with tmpl to w.
+func NewTemplateStream(w io.Writer, tmpl *template.Template) *TemplateStream {
+	return &TemplateStream{w: w, tmpl: tmpl}
+}
+
+// Result renders a completed check. A newline is added unless the template
+// ends with one.
+func (s *TemplateStream) Result(mode string, result stats.BatchResult, at time.Time) error {
+	var b strings.Builder
+	if err := s.tmpl.Execute(&b, NewTemplateResult(mode, result, at)); err != nil {
+		return err
+	}
+	line := b.String()
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := io.WriteString(s.w, line)
+	return err
+}
+
+// Summary does nothing: templates render results only.
+func (s *TemplateStream) Summary(RunSummary) error {
+	return nil
+}
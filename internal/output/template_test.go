@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func TestParseTemplate(t *testing.T) {
+	if _, err := ParseTemplate(`{{.Status}} {{.LatencyMs}}ms {{.Header.Get "Server"}} {{join .Tags ","}}`); err != nil {
+		t.Errorf("ParseTemplate() error = %v", err)
+	}
+	for _, text := range []string{"{{.Status", "{{.Latency_ms}}", "{{nope .Status}}"} {
+		if _, err := ParseTemplate(text); err == nil {
+			t.Errorf("ParseTemplate(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func TestTemplateStream(t *testing.T) {
+	var buf bytes.Buffer
+	tmpl, err := ParseTemplate(`{{.Mode}} {{.Name}} {{.Status}} {{.LatencyMs}}ms {{upper .Method}} {{.Header.Get "Server"}}{{if not .Success}} {{.Error}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := NewTemplateStream(&buf, tmpl)
+	at := time.Now()
+
+	stream.Result("batch", stats.BatchResult{
+		Name:    "users",
+		Method:  "get",
+		Success: true,
+		Result: request.Result{
+			StatusCode: 200,
+			Latency:    142500 * time.Microsecond,
+			Headers:    http.Header{"Server": {"nginx"}},
+		},
+	}, at)
+	stream.Result("batch", stats.BatchResult{
+		Name:   "orders",
+		Method: "get",
+		Result: request.Result{Error: errors.New("connection refused")},
+	}, at)
+	stream.Result("batch", stats.BatchResult{
+		Name:    "billing",
+		Method:  "get",
+		Message: "Expected status 200, got 503",
+		Result:  request.Result{StatusCode: 503},
+	}, at)
+	if err := stream.Summary(RunSummary{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "batch users 200 142.5ms GET nginx\n" +
+		"batch orders 0 0ms GET  connection refused\n" +
+		"batch billing 503 0ms GET  Expected status 200, got 503\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestTemplateStreamNewline(t *testing.T) {
+	var buf bytes.Buffer
+	tmpl, _ := ParseTemplate("{{.Status}}\n")
+	NewTemplateStream(&buf, tmpl).Result("ping", stats.BatchResult{Result: request.Result{StatusCode: 204}}, time.Now())
+	if buf.String() != "204\n" {
+		t.Errorf("output = %q, want a single newline", buf.String())
+	}
+}
@@ -0,0 +1,21 @@
+package output
+
+// legacyConsole is set by SetupTerminal when stdout or stderr is a console
+// that can't interpret ANSI escape codes.
+var legacyConsole bool
+
+// SetupTerminal prepares the console for tapr's output. On Windows it
+// switches the console to UTF-8 and turns on ANSI escape processing
+// (virtual terminal mode); consoles that don't support it, such as the
+// legacy console of Windows 8 and earlier, are reported by LegacyConsole.
+// Elsewhere it does nothing.
+func SetupTerminal() {
+	legacyConsole = !enableVirtualTerminal()
+}
+
+// LegacyConsole reports whether SetupTerminal found a console without ANSI
+// escape support. Colors and screen redraws are then left out, and the
+// ASCII theme should be used.
+func LegacyConsole() bool {
+	return legacyConsole
+}
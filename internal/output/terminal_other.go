@@ -0,0 +1,9 @@
+//go:build !windows
+
+package output
+
+// enableVirtualTerminal reports true: terminals outside Windows interpret
+// ANSI escape codes natively.
+func enableVirtualTerminal() bool {
+	return true
+}
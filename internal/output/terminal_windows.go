@@ -0,0 +1,63 @@
+//go:build windows
+
+package output
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	enableVirtualTerminalProcessing = 0x0004 // ENABLE_VIRTUAL_TERMINAL_PROCESSING
+	codePageUTF8                    = 65001
+)
+
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode     = kernel32.NewProc("SetConsoleMode")
+	procSetConsoleOutputCP = kernel32.NewProc("SetConsoleOutputCP")
+)
+
+// enableVirtualTerminal switches the console to UTF-8 output and turns on
+// ANSI escape processing for stdout and stderr. It reports false if either
+// is a console that refuses virtual terminal mode.
+func enableVirtualTerminal() bool {
+	_, _, _ = procSetConsoleOutputCP.Call(codePageUTF8)
+	stdout := enableVirtualTerminalFor(os.Stdout)
+	stderr := enableVirtualTerminalFor(os.Stderr)
+	return stdout && stderr
+}
+
+// enableVirtualTerminalFor turns on ANSI escape processing for f if it is
+// a console. Pipes and files need nothing and report true.
+func enableVirtualTerminalFor(f *os.File) bool {
+	handle := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return true // Not a console
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	ok, _, _ := procSetConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	return ok != 0
+}
+
+var procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+
+// terminalWidth returns the width of the console window behind f, or 0 if
+// f is not a console.
+func terminalWidth(f *os.File) int {
+	var info struct {
+		size, cursor      struct{ x, y int16 }
+		attributes        uint16
+		window            struct{ left, top, right, bottom int16 }
+		maximumWindowSize struct{ x, y int16 }
+	}
+	ok, _, _ := procGetConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info)))
+	if ok == 0 {
+		return 0
+	}
+	return int(info.window.right-info.window.left) + 1
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Theme decides how the box drawing, symbols and emoji in tapr's output are
+// drawn. Commands print through Printf, Println, Print and Stderr, which
+// render text with the current theme.
+type Theme struct {
+	Name     string
+	replacer *strings.Replacer // nil draws text as is
+}
+
+// UnicodeTheme draws text as written. It is the default.
+var UnicodeTheme = Theme{Name: "unicode"}
+
+// ASCIITheme replaces every non-ASCII symbol tapr prints, for consoles and
+// fonts without box drawing or emoji (legacy Windows consoles, some CI log
+// viewers). Single-width symbols get a single-character replacement so
+// tables stay aligned; emoji are dropped.
+var ASCIITheme = Theme{Name: "ascii", replacer: strings.NewReplacer(asciiGlyphs()...)}
+
+// asciiSymbols maps single-width symbols to their ASCII replacement.
+var asciiSymbols = []string{
+	"─", "-", "│", "|", "┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+", "┤", "+",
+	"█", "#", "░", ".", "▁", "_", "▂", ".", "▃", "-", "▄", "-", "▅", "=", "▆", "=", "▇", "#",
+	"✓", "+", "✗", "x", "•", "*", "·", ".", "●", "*", "◌", "o", "★", "*",
+	"→", ">", "↳", ">", "▸", ">", "▲", "^", "▼", "v", "↯", "!",
+	"–", "-", "—", "-", "…", "~", "µ", "u",
+	"⚠️", "!", "⚠", "!",
+}
+
+// asciiEmoji lists the emoji dropped by ASCIITheme.
+var asciiEmoji = []string{
+	"📊", "📈", "📋", "📌", "📬", "📶", "📼", "📸", "📅", "🔀", "🔁", "🔇", "🔔", "🔎", "🔍",
+	"🔢", "💡", "👋", "🎯", "🐳", "🌳", "🌍", "🌐", "🛰️", "🛰", "🟢", "🕒", "🕐",
+	"⚡", "⏱️", "⏱", "⏳", "☸️", "☸", "♻️", "♻",
+}
+
+// asciiGlyphs returns the replacement pairs of ASCIITheme. An emoji is
+// dropped together with the space after it, so "📊 Summary" becomes
+// "Summary"; pairs are listed longest first since the replacer prefers
+// earlier pairs.
+func asciiGlyphs() []string {
+	var pairs []string
+	for _, emoji := range asciiEmoji {
+		pairs = append(pairs, emoji+" ", "", emoji, "")
+	}
+	pairs = append(pairs, asciiSymbols...)
+	return append(pairs, "️", "") // Stray emoji variation selectors
+}
+
+// theme is the current theme.
+var theme = UnicodeTheme
+
+// SetTheme sets the theme used by Printf, Println, Print and Stderr.
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// Render draws text with the theme.
+func (t Theme) Render(text string) string {
+	if t.replacer == nil {
+		return text
+	}
+	return t.replacer.Replace(text)
+}
+
+// themedWriter renders everything written to it with the current theme.
+type themedWriter struct {
+	w io.Writer
+}
+
+// Write renders p and writes it. It reports len(p) on success, as the
+// rendered text may be shorter.
+func (t themedWriter) Write(p []byte) (int, error) {
+	if theme.replacer == nil {
+		return t.w.Write(p)
+	}
+	if _, err := io.WriteString(t.w, theme.Render(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Stdout and Stderr render text written to them with the current theme.
+// Machine-readable output (JSON, CSV, response bodies) should be written
+// to os.Stdout directly so it is never altered.
+var (
+	Stdout io.Writer = themedWriter{os.Stdout}
+	Stderr io.Writer = themedWriter{os.Stderr}
+)
+
+// Printf formats according to format and prints to Stdout.
+func Printf(format string, args ...any) {
+	fmt.Fprintf(Stdout, format, args...)
+}
+
+// Println prints its arguments to Stdout, separated by spaces and followed
+// by a newline.
+func Println(args ...any) {
+	fmt.Fprintln(Stdout, args...)
+}
+
+// Print prints its arguments to Stdout.
+func Print(args ...any) {
+	fmt.Fprint(Stdout, args...)
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestASCIITheme(t *testing.T) {
+	tests := map[string]string{
+		"┌──────┐\n│ tapr │\n└──────┘": "+------+\n| tapr |\n+------+",
+		"✓ Success":                     "+ Success",
+		"✗ 2 endpoint(s) failed!":       "x 2 endpoint(s) failed!",
+		"📊 Summary":                     "Summary",
+		"⚠️  Slow: 812ms":               "!  Slow: 812ms",
+		"[████░░░░] 4/8":                "[####....] 4/8",
+		"Latency:  812.4µs → 1.2ms":     "Latency:  812.4us > 1.2ms",
+		"https://api.example.com/users": "https://api.example.com/users",
+	}
+	for in, want := range tests {
+		if got := ASCIITheme.Render(in); got != want {
+			t.Errorf("Render(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if got := UnicodeTheme.Render("✓ 📊"); got != "✓ 📊" {
+		t.Errorf("UnicodeTheme.Render() = %q, want the text unchanged", got)
+	}
+}
+
+func TestThemedWriter(t *testing.T) {
+	defer SetTheme(UnicodeTheme)
+
+	var buf bytes.Buffer
+	w := themedWriter{&buf}
+
+	SetTheme(ASCIITheme)
+	n, err := fmt.Fprint(w, "✓ ok")
+	if err != nil || n != len("✓ ok") {
+		t.Errorf("Fprint() = %d, %v; want %d, nil", n, err, len("✓ ok"))
+	}
+	SetTheme(UnicodeTheme)
+	fmt.Fprint(w, " ✓")
+
+	if buf.String() != "+ ok ✓" {
+		t.Errorf("output = %q, want %q", buf.String(), "+ ok ✓")
+	}
+}
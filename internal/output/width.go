@@ -0,0 +1,20 @@
+package output
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalWidth returns the width of the terminal stdout writes to, in
+// columns: $COLUMNS if set, otherwise what the terminal reports. It returns
+// 0 if stdout is not a terminal, so output to pipes and files is never
+// truncated.
+func TerminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !IsTerminal(os.Stdout) {
+		return 0
+	}
+	return terminalWidth(os.Stdout)
+}
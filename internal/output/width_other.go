@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !windows
+
+package output
+
+import "os"
+
+// terminalWidth returns 0: the width is unknown on this platform.
+func terminalWidth(*os.File) int {
+	return 0
+}
@@ -0,0 +1,67 @@
+// Package ratelimit implements a token-bucket rate limiter, used to spread
+// requests out so large runs don't overwhelm a target or trip its rate
+// limits.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter hands out tokens at a fixed rate, allowing short bursts of up to
+// burst requests. It is safe for concurrent use. A nil *Limiter never
+// blocks, so callers can use one unconditionally.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration // Time to earn one token
+	burst    float64       // Bucket capacity
+	tokens   float64       // Available tokens (negative when reserved ahead)
+	last     time.Time     // When tokens was last updated
+}
+
+// New creates a limiter allowing rps requests per second with bursts of up
+// to burst requests (at least 1). It returns nil if rps is not positive.
+func New(rps float64, burst int) *Limiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		interval: time.Duration(float64(time.Second) / rps),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+	}
+}
+
+// Wait blocks until a request may be made.
+func (l *Limiter) Wait() {
+	if l == nil {
+		return
+	}
+	if delay := l.reserve(time.Now()); delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// reserve takes a token at now and returns how long the caller must wait
+// before using it.
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
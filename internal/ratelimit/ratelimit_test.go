@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_Reserve(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rps    float64
+		burst  int
+		offset []time.Duration // When each request is made, relative to start
+		want   []time.Duration // How long each request must wait
+	}{
+		{
+			name:   "spaced evenly without burst",
+			rps:    10,
+			burst:  1,
+			offset: []time.Duration{0, 0, 0, 0},
+			want:   []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond},
+		},
+		{
+			name:   "burst then throttled",
+			rps:    10,
+			burst:  3,
+			offset: []time.Duration{0, 0, 0, 0},
+			want:   []time.Duration{0, 0, 0, 100 * time.Millisecond},
+		},
+		{
+			name:   "tokens refill over time",
+			rps:    10,
+			burst:  1,
+			offset: []time.Duration{0, 50 * time.Millisecond, 300 * time.Millisecond},
+			want:   []time.Duration{0, 50 * time.Millisecond, 0},
+		},
+		{
+			name:   "refill is capped at burst",
+			rps:    10,
+			burst:  2,
+			offset: []time.Duration{0, time.Minute, time.Minute, time.Minute},
+			want:   []time.Duration{0, 0, 0, 100 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := New(tt.rps, tt.burst)
+			for i, offset := range tt.offset {
+				if got := limiter.reserve(start.Add(offset)); got != tt.want[i] {
+					t.Errorf("request %d: wait = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNew_Disabled(t *testing.T) {
+	limiter := New(0, 1)
+	if limiter != nil {
+		t.Fatalf("New(0, 1) = %v, want nil", limiter)
+	}
+	limiter.Wait() // Must not block or panic
+}
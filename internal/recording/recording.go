@@ -0,0 +1,151 @@
+// Package recording stores full request/response exchanges in an archive
+// (newline-delimited JSON, one exchange per line) so that they can be
+// replayed later, for example against another deployment.
+package recording
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Exchange is a recorded request and the response it received.
+type Exchange struct {
+	Time     time.Time `json:"time"`
+	Name     string    `json:"name,omitempty"`
+	Request  Request   `json:"request"`
+	Response Response  `json:"response"`
+}
+
+// Request is the request half of an exchange.
+type Request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Response is the response half of an exchange. Bodies that are not valid
+// UTF-8 are stored base64-encoded.
+type Response struct {
+	Status     int         `json:"status,omitempty"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 bool        `json:"body_base64,omitempty"`
+	LatencyMs  float64     `json:"latency_ms"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// SetBody stores data as the response body.
+func (r *Response) SetBody(data []byte) {
+	if utf8.Valid(data) {
+		r.Body, r.BodyBase64 = string(data), false
+		return
+	}
+	r.Body, r.BodyBase64 = base64.StdEncoding.EncodeToString(data), true
+}
+
+// BodyBytes returns the response body as stored by SetBody.
+func (r Response) BodyBytes() ([]byte, error) {
+	if !r.BodyBase64 {
+		return []byte(r.Body), nil
+	}
+	return base64.StdEncoding.DecodeString(r.Body)
+}
+
+// Latency returns the recorded latency as a duration.
+func (r Response) Latency() time.Duration {
+	return time.Duration(r.LatencyMs * float64(time.Millisecond))
+}
+
+// Write saves exchanges to the archive at path, replacing any existing file.
+func Write(path string, exchanges []Exchange) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	for _, exchange := range exchanges {
+		if err := encoder.Encode(exchange); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return file.Close()
+}
+
+// Load reads the exchanges of the archive at path, in recorded order.
+func Load(path string) ([]Exchange, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	var exchanges []Exchange
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return nil, fmt.Errorf("archive line %d: %w", line, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	if len(exchanges) == 0 {
+		return nil, fmt.Errorf("archive %s has no exchanges", path)
+	}
+	return exchanges, nil
+}
+
+// Rebase moves rawURL onto base: the scheme and host are replaced with
+// those of base, and the path of base (if any) is prepended to the path.
+// The query string is kept.
+//
+// Example:
+//
+//	Rebase("https://api.example.com/users?page=2", "https://canary.example.com/v2")
+//	// https://canary.example.com/v2/users?page=2
+func Rebase(rawURL, base string) (string, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return "", fmt.Errorf("base URL must include a scheme and host: %s", base)
+	}
+
+	target.Scheme = baseURL.Scheme
+	target.Host = baseURL.Host
+	target.User = baseURL.User
+	if prefix := strings.TrimSuffix(baseURL.Path, "/"); prefix != "" {
+		target.Path = prefix + target.Path
+		target.RawPath = ""
+	}
+	return target.String(), nil
+}
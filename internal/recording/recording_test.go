@@ -0,0 +1,112 @@
+package recording
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.ndjson")
+
+	text := Response{Status: 200, Headers: http.Header{"Content-Type": {"application/json"}}, LatencyMs: 12.5}
+	text.SetBody([]byte(`{"id": 1, "html": "<b>"}`))
+	binary := Response{Status: 200}
+	binary.SetBody([]byte{0xff, 0xd8, 0xff})
+
+	exchanges := []Exchange{
+		{
+			Time:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			Name:     "user",
+			Request:  Request{Method: "POST", URL: "https://api.example.com/users", Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"name": "ada"}`},
+			Response: text,
+		},
+		{
+			Time:     time.Date(2024, 5, 1, 12, 0, 1, 0, time.UTC),
+			Request:  Request{Method: "GET", URL: "https://api.example.com/logo.jpg"},
+			Response: binary,
+		},
+	}
+
+	if err := Write(path, exchanges); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("archive has %d lines, want 2", lines)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, exchanges) {
+		t.Errorf("Load() = %+v, want %+v", loaded, exchanges)
+	}
+
+	body, err := loaded[1].Response.BodyBytes()
+	if err != nil || !bytes.Equal(body, []byte{0xff, 0xd8, 0xff}) {
+		t.Errorf("binary BodyBytes() = %v, %v; want the original bytes", body, err)
+	}
+	if !loaded[1].Response.BodyBase64 || loaded[0].Response.BodyBase64 {
+		t.Error("only the binary body should be base64-encoded")
+	}
+	if loaded[0].Response.Latency() != 12500*time.Microsecond {
+		t.Errorf("Latency() = %v, want 12.5ms", loaded[0].Response.Latency())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", "\n", "no exchanges"},
+		{"malformed", `{"name": "a"}` + "\n{not json\n", "archive line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "run.ndjson")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRebase(t *testing.T) {
+	tests := []struct {
+		url     string
+		base    string
+		want    string
+		wantErr bool
+	}{
+		{"https://api.example.com/users?page=2", "https://canary.example.com", "https://canary.example.com/users?page=2", false},
+		{"https://api.example.com/users", "http://localhost:8080/", "http://localhost:8080/users", false},
+		{"https://api.example.com/users", "https://canary.example.com/v2", "https://canary.example.com/v2/users", false},
+		{"https://api.example.com/users", "canary.example.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.base, func(t *testing.T) {
+			got, err := Rebase(tt.url, tt.base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Rebase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Rebase() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package remote runs batch checks on other machines over SSH, so endpoints
+// can be checked from several vantage points in one run.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/config"
+	"github.com/symtalha14/tapr/internal/output"
+)
+
+// sshCommand is the ssh client to run (replaced in tests).
+var sshCommand = "ssh"
+
+// Region is the outcome of running a batch from one vantage point.
+type Region struct {
+	Name     string                  // Remote name, or config.LocalRemote
+	Result   *output.JSONBatchResult // Batch results (nil if the run failed)
+	Duration time.Duration           // Time the run took, including the SSH connection
+	Error    error                   // Why the batch couldn't run there
+}
+
+// Command returns the ssh arguments that run a batch config read from
+// standard input on r and print the results as JSON, passing extra batch
+// flags along. Host key prompts and password authentication are disabled,
+// since nobody can answer them.
+func Command(r config.Remote, extra ...string) []string {
+	args := []string{"-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if r.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(r.Port))
+	}
+	if r.Identity != "" {
+		args = append(args, "-i", r.Identity)
+	}
+	for _, option := range r.Options {
+		args = append(args, "-o", option)
+	}
+
+	tapr := r.Tapr
+	if tapr == "" {
+		tapr = "tapr"
+	}
+	remoteArgs := []string{shellQuote(tapr), "batch", "-", "--output", "json"}
+	for _, arg := range extra {
+		remoteArgs = append(remoteArgs, shellQuote(arg))
+	}
+	return append(args, "--", r.SSH, strings.Join(remoteArgs, " "))
+}
+
+// Run runs the batch config on r (see Command) and returns its results.
+// Failing checks are part of the result; an error means the batch couldn't
+// run at all (SSH failure, tapr missing, invalid config).
+func Run(ctx context.Context, r config.Remote, batch []byte, extra ...string) Region {
+	region := Region{Name: r.Name}
+	start := time.Now()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, sshCommand, Command(r, extra...)...)
+	cmd.Stdin = bytes.NewReader(batch)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	region.Duration = time.Since(start)
+
+	// tapr exits non-zero when checks fail, so the output decides
+	var result output.JSONBatchResult
+	if jsonErr := json.Unmarshal(stdout.Bytes(), &result); jsonErr == nil && stdout.Len() > 0 {
+		region.Result = &result
+		return region
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		region.Error = fmt.Errorf("no results within %s", region.Duration.Round(time.Second))
+	case lastLine(stderr.String()) != "":
+		region.Error = fmt.Errorf("%s", lastLine(stderr.String()))
+	case err != nil:
+		region.Error = err
+	default:
+		region.Error = fmt.Errorf("unexpected output from tapr on %s", r.SSH)
+	}
+	return region
+}
+
+// lastLine returns the last non-empty line of s, where tapr and ssh put
+// their error messages.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// shellQuote quotes s for the remote shell unless it only contains
+// characters that need no quoting.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./~=,:@%+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
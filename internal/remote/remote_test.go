@@ -0,0 +1,110 @@
+package remote
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/symtalha14/tapr/internal/config"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote config.Remote
+		extra  []string
+		want   []string
+	}{
+		{
+			"defaults",
+			config.Remote{SSH: "probe@eu.example.com"},
+			nil,
+			[]string{"-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "--", "probe@eu.example.com", "tapr batch - --output json"},
+		},
+		{
+			"options",
+			config.Remote{SSH: "eu", Port: 2222, Identity: "~/.ssh/probe", Tapr: "/opt/my tapr/tapr", Options: []string{"StrictHostKeyChecking=no"}},
+			[]string{"--retries=2", "--fail-on=5xx,timeout"},
+			[]string{"-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "-p", "2222", "-i", "~/.ssh/probe", "-o", "StrictHostKeyChecking=no",
+				"--", "eu", "'/opt/my tapr/tapr' batch - --output json --retries=2 --fail-on=5xx,timeout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Command(tt.remote, tt.extra...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"tapr":            "tapr",
+		"/usr/local/tapr": "/usr/local/tapr",
+		"":                "''",
+		"a b":             "'a b'",
+		"it's":            `'it'\''s'`,
+		"$(rm -rf ~)":     "'$(rm -rf ~)'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+// fakeSSH replaces the ssh client with a shell script for the test.
+func fakeSSH(t *testing.T, script string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ssh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := sshCommand
+	sshCommand = path
+	t.Cleanup(func() { sshCommand = old })
+}
+
+func TestRun(t *testing.T) {
+	remote := config.Remote{Name: "eu-west", SSH: "probe@eu.example.com"}
+
+	// Failing checks exit non-zero but still report results
+	fakeSSH(t, `cat > /dev/null
+echo '{"total": 2, "successful": 1, "failed": 1, "results": [{"name": "api", "status": 503}]}'
+exit 1
+`)
+	region := Run(context.Background(), remote, []byte("endpoints: []"))
+	if region.Error != nil {
+		t.Fatalf("Run() error = %v", region.Error)
+	}
+	if region.Name != "eu-west" || region.Result.Failed != 1 || region.Result.Results[0].Status != 503 {
+		t.Errorf("Run() = %+v, want the remote's results", region.Result)
+	}
+
+	// The config arrives on standard input
+	fakeSSH(t, `grep -q '^endpoints:' || exit 3
+echo '{"total": 0, "results": []}'
+`)
+	if region := Run(context.Background(), remote, []byte("endpoints: []\n")); region.Error != nil {
+		t.Errorf("Run() error = %v, want the config on stdin", region.Error)
+	}
+
+	fakeSSH(t, `echo "Warning: Permanently added 'eu' to the list of known hosts." >&2
+echo "probe@eu.example.com: Permission denied (publickey)." >&2
+exit 255
+`)
+	region = Run(context.Background(), remote, nil)
+	if region.Error == nil || !strings.Contains(region.Error.Error(), "Permission denied") {
+		t.Errorf("Run() error = %v, want the ssh error", region.Error)
+	}
+
+	fakeSSH(t, "exit 127\n")
+	if region := Run(context.Background(), remote, nil); region.Error == nil {
+		t.Error("Run() without output succeeded")
+	}
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// Layout of the HTML latency chart, in SVG user units. Bars start after a
+// 180-unit label column (see htmlTemplate).
+const (
+	chartBarWidth  = 480
+	chartRowHeight = 24
+)
+
+// htmlData is the data passed to htmlTemplate.
+type htmlData struct {
+	Meta        Meta
+	Generated   string
+	Summary     *stats.BatchSummary
+	SuccessRate string
+	AvgLatency  string
+	TotalTime   string
+	Rows        []row
+	Failures    []row
+	Chart       []chartBar
+	ChartHeight int
+}
+
+// chartBar is one bar of the latency chart.
+type chartBar struct {
+	Label string
+	Value string
+	Y     int
+	Width int
+	Fill  string
+}
+
+// HTML renders summary as a self-contained HTML page with inline styles and
+// an SVG latency chart, so it can be opened without network access.
+func HTML(summary *stats.BatchSummary, meta Meta) (string, error) {
+	results := rows(summary)
+
+	data := htmlData{
+		Meta:        meta,
+		Generated:   meta.Generated.Format(time.RFC1123),
+		Summary:     summary,
+		SuccessRate: fmt.Sprintf("%.1f%%", summary.SuccessRate()),
+		AvgLatency:  summary.AvgLatency.Round(time.Millisecond).String(),
+		TotalTime:   summary.TotalTime.Round(time.Millisecond).String(),
+		Rows:        results,
+		ChartHeight: len(results)*chartRowHeight + 8,
+	}
+
+	max := maxLatencyMs(results)
+	for i, r := range results {
+		if !r.Success {
+			data.Failures = append(data.Failures, r)
+		}
+
+		width := 0
+		if max > 0 {
+			width = int(r.LatencyMs / max * chartBarWidth)
+		}
+		fill := "#2e7d32"
+		if !r.Success {
+			fill = "#c62828"
+		}
+		data.Chart = append(data.Chart, chartBar{
+			Label: r.Name,
+			Value: r.Latency,
+			Y:     i*chartRowHeight + 4,
+			Width: width,
+			Fill:  fill,
+		})
+	}
+
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return b.String(), nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Meta.Title}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem auto; max-width: 960px; color: #222; }
+  h1 { margin-bottom: 0.25rem; }
+  .meta { color: #666; margin-top: 0; }
+  .cards { display: flex; gap: 1rem; flex-wrap: wrap; margin: 1.5rem 0; }
+  .card { border: 1px solid #ddd; border-radius: 6px; padding: 0.75rem 1rem; min-width: 110px; }
+  .card .value { font-size: 1.5rem; font-weight: 600; }
+  .card .label { color: #666; font-size: 0.85rem; }
+  .pass { color: #2e7d32; }
+  .fail { color: #c62828; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: 0.4rem 0.6rem; border-bottom: 1px solid #eee; }
+  th { background: #fafafa; }
+  td.num { text-align: right; font-variant-numeric: tabular-nums; }
+  code { background: #f4f4f4; padding: 0.1rem 0.3rem; border-radius: 3px; }
+  .failure { border-left: 4px solid #c62828; padding: 0.25rem 1rem; margin: 1rem 0; background: #fff8f8; }
+</style>
+</head>
+<body>
+<h1>{{.Meta.Title}}</h1>
+<p class="meta">{{if .Meta.Source}}{{.Meta.Source}} · {{end}}{{.Generated}}</p>
+
+<div class="cards">
+  <div class="card"><div class="value">{{.Summary.Total}}</div><div class="label">Endpoints</div></div>
+  <div class="card"><div class="value pass">{{.Summary.Successful}}</div><div class="label">Passed</div></div>
+  <div class="card"><div class="value{{if .Summary.Failed}} fail{{end}}">{{.Summary.Failed}}</div><div class="label">Failed</div></div>
+  <div class="card"><div class="value">{{.SuccessRate}}</div><div class="label">Success rate</div></div>
+  <div class="card"><div class="value">{{.AvgLatency}}</div><div class="label">Avg latency</div></div>
+  <div class="card"><div class="value">{{.TotalTime}}</div><div class="label">Total time</div></div>
+</div>
+
+<h2>Endpoints</h2>
+<table>
+  <tr><th>Result</th><th>Endpoint</th><th>Method</th><th>Status</th><th>Latency</th><th>Size</th></tr>
+  {{- range .Rows}}
+  <tr>
+    <td>{{if .Success}}<span class="pass">✔ pass</span>{{else}}<span class="fail">✘ fail</span>{{end}}</td>
+    <td title="{{.URL}}">{{.Name}}</td>
+    <td>{{.Method}}</td>
+    <td class="num">{{.Status}}</td>
+    <td class="num">{{.Latency}}</td>
+    <td class="num">{{.Size}}</td>
+  </tr>
+  {{- end}}
+</table>
+
+<h2>Latency</h2>
+<svg xmlns="http://www.w3.org/2000/svg" width="760" height="{{.ChartHeight}}" role="img" aria-label="Latency per endpoint">
+  {{- range .Chart}}
+  <text x="0" y="{{add .Y 15}}" font-size="12">{{.Label}}</text>
+  <rect x="180" y="{{.Y}}" width="{{.Width}}" height="18" fill="{{.Fill}}" rx="2"></rect>
+  <text x="{{add .Width 186}}" y="{{add .Y 15}}" font-size="12" fill="#555">{{.Value}}</text>
+  {{- end}}
+</svg>
+
+{{- if .Failures}}
+<h2>Failures</h2>
+{{- range .Failures}}
+<div class="failure">
+  <h3>{{.Name}}</h3>
+  <p><code>{{.Method}} {{.URL}}</code> → status {{.Status}}</p>
+  <p>{{.Message}}</p>
+</div>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// chartWidth is the width in characters of Markdown latency bars.
+const chartWidth = 30
+
+// Markdown renders summary as a Markdown document.
+func Markdown(summary *stats.BatchSummary, meta Meta) string {
+	var b strings.Builder
+	results := rows(summary)
+
+	fmt.Fprintf(&b, "# %s\n\n", meta.Title)
+	if meta.Source != "" {
+		fmt.Fprintf(&b, "- **Source:** `%s`\n", meta.Source)
+	}
+	fmt.Fprintf(&b, "- **Generated:** %s\n\n", meta.Generated.Format(time.RFC1123))
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Total | Passed | Failed | Slow | Success Rate | Avg Latency | Total Time |\n")
+	b.WriteString("|------:|-------:|-------:|-----:|-------------:|------------:|-----------:|\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %.1f%% | %s | %s |\n\n",
+		summary.Total, summary.Successful, summary.Failed, summary.Slow, summary.SuccessRate(),
+		summary.AvgLatency.Round(time.Millisecond), summary.TotalTime.Round(time.Millisecond))
+
+	b.WriteString("## Endpoints\n\n")
+	b.WriteString("| Result | Endpoint | Method | Status | Latency | Size |\n")
+	b.WriteString("|:------:|----------|--------|-------:|--------:|-----:|\n")
+	for _, r := range results {
+		result := "✅"
+		if !r.Success {
+			result = "❌"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			result, escapeCell(r.Name), r.Method, r.Status, r.Latency, r.Size)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Latency\n\n```\n")
+	max := maxLatencyMs(results)
+	nameWidth := 0
+	for _, r := range results {
+		if len(r.Name) > nameWidth {
+			nameWidth = len(r.Name)
+		}
+	}
+	for _, r := range results {
+		width := 0
+		if max > 0 {
+			width = int(math.Round(r.LatencyMs / max * chartWidth))
+		}
+		fmt.Fprintf(&b, "%-*s %s %s\n", nameWidth, r.Name, strings.Repeat("█", width)+strings.Repeat("░", chartWidth-width), r.Latency)
+	}
+	b.WriteString("```\n")
+
+	failures := 0
+	for _, r := range results {
+		if r.Success {
+			continue
+		}
+		if failures == 0 {
+			b.WriteString("\n## Failures\n\n")
+		}
+		failures++
+		fmt.Fprintf(&b, "### %s\n\n", r.Name)
+		fmt.Fprintf(&b, "- **Request:** `%s %s`\n", r.Method, r.URL)
+		fmt.Fprintf(&b, "- **Status:** %s\n", r.Status)
+		fmt.Fprintf(&b, "- **Reason:** %s\n\n", r.Message)
+	}
+
+	return b.String()
+}
+
+// escapeCell makes s safe to place inside a Markdown table cell.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,121 @@
+// Package report renders batch results as self-contained Markdown or HTML
+// documents that can be attached to tickets or published as CI artifacts.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+// Meta describes the run a report was generated for.
+type Meta struct {
+	Title     string    // Report heading
+	Source    string    // Config file or command that produced the results
+	Generated time.Time // When the run finished
+}
+
+// Write renders summary to path, choosing the format from the file
+// extension: .html/.htm for HTML, .md/.markdown for Markdown.
+func Write(path string, summary *stats.BatchSummary, meta Meta) error {
+	var content string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		rendered, err := HTML(summary, meta)
+		if err != nil {
+			return err
+		}
+		content = rendered
+	case ".md", ".markdown":
+		content = Markdown(summary, meta)
+	default:
+		return fmt.Errorf("unsupported report format %q (use .html or .md)", filepath.Ext(path))
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// row is a display-ready result shared by both formats.
+type row struct {
+	Name      string
+	Method    string
+	URL       string
+	Status    string
+	Latency   string
+	LatencyMs float64
+	Size      string
+	Success   bool
+	Message   string
+}
+
+// rows converts the results of summary for display.
+func rows(summary *stats.BatchSummary) []row {
+	result := make([]row, 0, len(summary.Results))
+	for _, r := range summary.Results {
+		status := "-"
+		if r.Result.StatusCode > 0 {
+			status = fmt.Sprintf("%d", r.Result.StatusCode)
+		}
+
+		size := "-"
+		if r.Result.Size > 0 {
+			size = formatBytes(r.Result.Size)
+		}
+		if rate := r.Result.Throughput(); rate > 0 {
+			size += " @ " + formatBytes(int64(rate)) + "/s"
+		}
+
+		message := r.Message
+		if message == "" && r.Result.Error != nil {
+			message = r.Result.Error.Error()
+		}
+
+		result = append(result, row{
+			Name:      r.Name,
+			Method:    r.Method,
+			URL:       r.URL,
+			Status:    status,
+			Latency:   r.Result.Latency.Round(time.Millisecond).String(),
+			LatencyMs: float64(r.Result.Latency) / float64(time.Millisecond),
+			Size:      size,
+			Success:   r.Success,
+			Message:   message,
+		})
+	}
+	return result
+}
+
+// maxLatencyMs returns the largest latency in rows, for scaling charts.
+func maxLatencyMs(rows []row) float64 {
+	max := 0.0
+	for _, r := range rows {
+		if r.LatencyMs > max {
+			max = r.LatencyMs
+		}
+	}
+	return max
+}
+
+// formatBytes converts bytes to a human-readable format.
+func formatBytes(bytes int64) string {
+	const (
+		KB = 1024
+		MB = 1024 * KB
+	)
+
+	switch {
+	case bytes >= MB:
+		return fmt.Sprintf("%.2f MB", float64(bytes)/float64(MB))
+	case bytes >= KB:
+		return fmt.Sprintf("%.2f KB", float64(bytes)/float64(KB))
+	default:
+		return fmt.Sprintf("%d bytes", bytes)
+	}
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+	"github.com/symtalha14/tapr/internal/stats"
+)
+
+func testSummary() *stats.BatchSummary {
+	summary := stats.NewBatchSummary()
+	summary.AddResult(stats.BatchResult{
+		Name:    "Health",
+		URL:     "https://example.com/health",
+		Method:  "GET",
+		Success: true,
+		Result:  request.Result{StatusCode: 200, Latency: 100 * time.Millisecond, Size: 2048},
+	})
+	summary.AddResult(stats.BatchResult{
+		Name:    "Users <v2>",
+		URL:     "https://example.com/users?a=1&b=2",
+		Method:  "GET",
+		Message: "Expected 200, got 500",
+		Result:  request.Result{StatusCode: 500, Latency: 400 * time.Millisecond},
+	})
+	summary.TotalTime = 450 * time.Millisecond
+	return summary
+}
+
+func TestMarkdown(t *testing.T) {
+	md := Markdown(testSummary(), Meta{Title: "Batch Report", Source: "endpoints.yml"})
+
+	for _, want := range []string{
+		"# Batch Report",
+		"`endpoints.yml`",
+		"| 2 | 1 | 1 | 0 | 50.0% |",
+		"| ✅ | Health | GET | 200 | 100ms | 2.00 KB |",
+		"## Failures",
+		"Expected 200, got 500",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q", want)
+		}
+	}
+
+	// The slowest endpoint gets the full-width bar
+	if !strings.Contains(md, strings.Repeat("█", chartWidth)+" 400ms") {
+		t.Errorf("Markdown() latency chart not scaled to the slowest endpoint")
+	}
+}
+
+func TestHTMLEscapesContent(t *testing.T) {
+	html, err := HTML(testSummary(), Meta{Title: "Batch Report"})
+	if err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+
+	if strings.Contains(html, "Users <v2>") {
+		t.Error("HTML() did not escape endpoint names")
+	}
+	if !strings.Contains(html, "Users &lt;v2&gt;") {
+		t.Error("HTML() missing escaped endpoint name")
+	}
+	if !strings.Contains(html, "<svg") || !strings.Contains(html, "Failures") {
+		t.Error("HTML() missing chart or failure details")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"report.html", "<!DOCTYPE html>", false},
+		{"report.md", "# Report", false},
+		{"report.txt", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			err := Write(path, testSummary(), Meta{Title: "Report"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			data, _ := os.ReadFile(path)
+			if !strings.HasPrefix(string(data), tt.want) {
+				t.Errorf("Write(%s) content starts with %q, want %q", tt.file, string(data[:20]), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package request
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// Credentials holds HTTP authentication details.
+type Credentials struct {
+	Username string // Username for Basic or Digest auth
+	Password string // Password for Basic or Digest auth
+	Digest   bool   // Use Digest instead of Basic authentication
+}
+
+// ParseCredentials parses a curl-style "user:password" string.
+// The password may contain colons; only the first colon separates the parts.
+func ParseCredentials(userpass string, digest bool) (*Credentials, error) {
+	parts := strings.SplitN(userpass, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid credentials format (expected 'user:password')")
+	}
+
+	return &Credentials{
+		Username: parts[0],
+		Password: parts[1],
+		Digest:   digest,
+	}, nil
+}
+
+// digestChallenge holds the parameters of a WWW-Authenticate: Digest header.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// parseDigestChallenge parses a WWW-Authenticate header value.
+// Returns nil if the header is not a Digest challenge.
+func parseDigestChallenge(header string) *digestChallenge {
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Digest ") {
+		return nil
+	}
+
+	params := parseAuthParams(header[7:])
+	challenge := &digestChallenge{
+		realm:     params["realm"],
+		nonce:     params["nonce"],
+		opaque:    params["opaque"],
+		algorithm: params["algorithm"],
+	}
+
+	// Prefer qop=auth when the server offers several options
+	for _, qop := range strings.Split(params["qop"], ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			challenge.qop = "auth"
+		}
+	}
+
+	if challenge.nonce == "" {
+		return nil
+	}
+	return challenge
+}
+
+// parseAuthParams parses comma-separated key=value pairs, where values
+// may be quoted and contain commas.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+
+	for s != "" {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.Index(s, "=")
+		if eq == -1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimSpace(s[eq+1:])
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end == -1 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else {
+			end := strings.Index(s, ",")
+			if end == -1 {
+				value, s = s, ""
+			} else {
+				value, s = s[:end], s[end:]
+			}
+		}
+
+		params[key] = strings.TrimSpace(value)
+	}
+
+	return params
+}
+
+// authorization computes the Authorization header answering this challenge.
+func (c *digestChallenge) authorization(creds *Credentials, method, uri string) (string, error) {
+	var newHash func() hash.Hash
+	switch strings.ToUpper(c.algorithm) {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm: %s", c.algorithm)
+	}
+
+	digest := func(s string) string {
+		h := newHash()
+		h.Write([]byte(s))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	ha1 := digest(creds.Username + ":" + c.realm + ":" + creds.Password)
+	ha2 := digest(method + ":" + uri)
+
+	var response, cnonce string
+	const nc = "00000001"
+	if c.qop == "auth" {
+		buf := make([]byte, 8)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		cnonce = hex.EncodeToString(buf)
+		response = digest(strings.Join([]string{ha1, c.nonce, nc, cnonce, c.qop, ha2}, ":"))
+	} else {
+		response = digest(ha1 + ":" + c.nonce + ":" + ha2)
+	}
+
+	header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		creds.Username, c.realm, c.nonce, uri, response)
+	if c.algorithm != "" {
+		header += fmt.Sprintf(", algorithm=%s", c.algorithm)
+	}
+	if c.opaque != "" {
+		header += fmt.Sprintf(`, opaque="%s"`, c.opaque)
+	}
+	if c.qop != "" {
+		header += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, c.qop, nc, cnonce)
+	}
+
+	return header, nil
+}
+
+// digestRetry returns a copy of req carrying Digest credentials that answer
+// the challenge in resp, or nil if resp is not a Digest challenge.
+func digestRetry(req *http.Request, resp *http.Response, creds *Credentials) (*http.Request, error) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return nil, nil
+	}
+
+	for _, header := range resp.Header.Values("WWW-Authenticate") {
+		challenge := parseDigestChallenge(header)
+		if challenge == nil {
+			continue
+		}
+
+		authorization, err := challenge.authorization(creds, req.Method, req.URL.RequestURI())
+		if err != nil {
+			return nil, err
+		}
+
+		retry := req.Clone(req.Context())
+		retry.Header.Set("Authorization", authorization)
+		if err := rewindBody(retry); err != nil {
+			return nil, err
+		}
+		return retry, nil
+	}
+
+	return nil, nil
+}
+
+// digestAuthorization sends req once without credentials to obtain the
+// server's Digest challenge and returns the Authorization header answering
+// it. Used by TraceRequest so only the authenticated request is traced.
+func digestAuthorization(client *http.Client, req *http.Request, creds *Credentials) (string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	retry, err := digestRetry(req, resp, creds)
+	if err != nil || retry == nil {
+		return "", err
+	}
+	return retry.Header.Get("Authorization"), nil
+}
@@ -0,0 +1,138 @@
+package request
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantUser string
+		wantPass string
+		wantErr  bool
+	}{
+		{"simple", "alice:secret", "alice", "secret", false},
+		{"colon in password", "alice:se:cret", "alice", "se:cret", false},
+		{"empty password", "alice:", "alice", "", false},
+		{"missing colon", "alice", "", "", true},
+		{"empty user", ":secret", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := ParseCredentials(tt.input, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if creds.Username != tt.wantUser || creds.Password != tt.wantPass {
+				t.Errorf("ParseCredentials() = %s/%s, want %s/%s",
+					creds.Username, creds.Password, tt.wantUser, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestParseDigestChallenge(t *testing.T) {
+	header := `Digest realm="api@example.com", qop="auth,auth-int", nonce="abc123", opaque="xyz"`
+
+	challenge := parseDigestChallenge(header)
+	if challenge == nil {
+		t.Fatal("parseDigestChallenge() returned nil")
+	}
+	if challenge.realm != "api@example.com" {
+		t.Errorf("realm = %s, want api@example.com", challenge.realm)
+	}
+	if challenge.nonce != "abc123" {
+		t.Errorf("nonce = %s, want abc123", challenge.nonce)
+	}
+	if challenge.qop != "auth" {
+		t.Errorf("qop = %s, want auth", challenge.qop)
+	}
+	if challenge.opaque != "xyz" {
+		t.Errorf("opaque = %s, want xyz", challenge.opaque)
+	}
+
+	if parseDigestChallenge(`Basic realm="x"`) != nil {
+		t.Error("parseDigestChallenge() should ignore Basic challenges")
+	}
+}
+
+func TestPing_BasicAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "alice" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := Ping(server.URL, PingOptions{
+		Method:  "GET",
+		Timeout: 5 * time.Second,
+		Auth:    &Credentials{Username: "alice", Password: "secret"},
+	})
+
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", result.StatusCode)
+	}
+}
+
+func TestPing_DigestAuth(t *testing.T) {
+	const (
+		realm = "tapr"
+		nonce = "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+	)
+
+	md5hex := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			w.Header().Set("WWW-Authenticate", `Digest realm="`+realm+`", qop="auth", nonce="`+nonce+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := parseAuthParams(header[len("Digest "):])
+		ha1 := md5hex("alice:" + realm + ":secret")
+		ha2 := md5hex(r.Method + ":" + params["uri"])
+		want := md5hex(ha1 + ":" + nonce + ":" + params["nc"] + ":" + params["cnonce"] + ":auth:" + ha2)
+
+		if params["response"] != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := Ping(server.URL+"/protected?x=1", PingOptions{
+		Method:  "GET",
+		Timeout: 5 * time.Second,
+		Auth:    &Credentials{Username: "alice", Password: "secret", Digest: true},
+	})
+
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", result.StatusCode)
+	}
+}
@@ -0,0 +1,96 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// SOAP envelope namespaces
+const (
+	soap11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// DetectContentType guesses the Content-Type of a request body. SOAP
+// envelopes get the type their SOAP version requires. Otherwise the
+// extension of filename (if the body was read from a file) wins, then JSON
+// and XML are recognized by their content and anything else is sent as
+// plain text.
+func DetectContentType(body []byte, filename string) string {
+	switch soapVersion(body) {
+	case "1.1":
+		return "text/xml; charset=utf-8"
+	case "1.2":
+		return "application/soap+xml; charset=utf-8"
+	}
+
+	if filename != "" {
+		if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+			return contentType
+		}
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	switch {
+	case len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed):
+		return "application/json"
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return "application/xml"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// SetSOAPAction adds a SOAP action to the headers of a request with the
+// given body, the way the envelope's SOAP version expects: SOAP 1.2 puts it
+// in the action parameter of the Content-Type, SOAP 1.1 (and anything that
+// is not a SOAP 1.2 envelope) in a quoted SOAPAction header. Headers that
+// are already set are left alone.
+func SetSOAPAction(headers map[string]string, body []byte, action string) {
+	if soapVersion(body) != "1.2" {
+		if !HasHeader(headers, "SOAPAction") {
+			headers["SOAPAction"] = `"` + action + `"`
+		}
+		return
+	}
+
+	for name, value := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			if !strings.Contains(value, "action=") {
+				headers[name] = value + `; action="` + action + `"`
+			}
+			return
+		}
+	}
+	headers["Content-Type"] = DetectContentType(body, "") + `; action="` + action + `"`
+}
+
+// soapVersion returns "1.1" or "1.2" if body is a SOAP envelope, and ""
+// otherwise.
+func soapVersion(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if !bytes.HasPrefix(trimmed, []byte("<")) || !bytes.Contains(trimmed, []byte("Envelope")) {
+		return ""
+	}
+	switch {
+	case bytes.Contains(trimmed, []byte(soap12Namespace)):
+		return "1.2"
+	case bytes.Contains(trimmed, []byte(soap11Namespace)):
+		return "1.1"
+	default:
+		return ""
+	}
+}
+
+// HasHeader reports whether headers contains name, ignoring case.
+func HasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package request
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		filename string
+		want     string
+	}{
+		{"json object", `{"name": "tapr"}`, "", "application/json"},
+		{"json array with whitespace", "\n  [1, 2]\n", "", "application/json"},
+		{"invalid json", `{name: tapr}`, "", "text/plain; charset=utf-8"},
+		{"xml", `<?xml version="1.0"?><a/>`, "", "application/xml"},
+		{"plain text", "hello", "", "text/plain; charset=utf-8"},
+		{"extension wins", `{"a": 1}`, "payload.xml", "text/xml; charset=utf-8"},
+		{"unknown extension", `{"a": 1}`, "payload.unknownext", "application/json"},
+		{"soap 1.1", `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"/>`, "", "text/xml; charset=utf-8"},
+		{"soap 1.2 beats extension", `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"/>`, "status.xml", "application/soap+xml; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType([]byte(tt.body), tt.filename); got != tt.want {
+				t.Errorf("DetectContentType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSOAPAction(t *testing.T) {
+	soap11 := []byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"/>`)
+	soap12 := []byte(`<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"/>`)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    []byte
+		want    map[string]string
+	}{
+		{
+			"soap 1.1 header",
+			map[string]string{"Content-Type": "text/xml; charset=utf-8"},
+			soap11,
+			map[string]string{"Content-Type": "text/xml; charset=utf-8", "SOAPAction": `"urn:GetStatus"`},
+		},
+		{
+			"soap 1.1 header already set",
+			map[string]string{"soapaction": "custom"},
+			soap11,
+			map[string]string{"soapaction": "custom"},
+		},
+		{
+			"soap 1.2 content type parameter",
+			map[string]string{"content-type": "application/soap+xml; charset=utf-8"},
+			soap12,
+			map[string]string{"content-type": `application/soap+xml; charset=utf-8; action="urn:GetStatus"`},
+		},
+		{
+			"soap 1.2 without content type",
+			map[string]string{},
+			soap12,
+			map[string]string{"Content-Type": `application/soap+xml; charset=utf-8; action="urn:GetStatus"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetSOAPAction(tt.headers, tt.body, "urn:GetStatus")
+			if !reflect.DeepEqual(tt.headers, tt.want) {
+				t.Errorf("headers = %v, want %v", tt.headers, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasHeader(t *testing.T) {
+	headers := map[string]string{"content-type": "application/json"}
+	if !HasHeader(headers, "Content-Type") {
+		t.Error("HasHeader(content-type) = false, want true")
+	}
+	if HasHeader(headers, "Accept") {
+		t.Error("HasHeader(Accept) = true, want false")
+	}
+}
@@ -3,7 +3,14 @@
 package request
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptrace"
 	"time"
 )
 
@@ -11,12 +18,23 @@ import (
 // information, response status, and any errors encountered.
 type Result struct {
 	URL        string        // The URL that was requested
+	FinalURL   string        // The URL of the final response, after redirects
 	StatusCode int           // HTTP status code (e.g., 200, 404, 500)
 	Status     string        // HTTP status text (e.g., "200 OK")
 	Latency    time.Duration // Total time taken for the request
+	TTFB       time.Duration // Time from request start to the first response byte
+	Download   time.Duration // Time to read the full body (PingOptions.Download only)
 	Size       int64         // Response body size in bytes (-1 if unknown)
 	Protocol   string        // HTTP protocol version (e.g., "HTTP/2.0")
+	Headers    http.Header   // Response headers
+	Body       []byte        // Response body (only when PingOptions.ReadBody is set)
+	Truncated  bool          // Body holds only the first MaxBodySize bytes of a longer body
+	ConnReused bool          // Whether an existing keep-alive connection was reused
+	RemoteAddr string        // Address of the server connected to ("ip:port")
 	Error      error         // Any error that occurred during the request
+
+	Encoding       string // Content-Encoding of the response (PingOptions.Compressed only)
+	CompressedSize int64  // Bytes received before decoding (PingOptions.Compressed only)
 }
 
 // PingOptions contains configuration options for making HTTP requests.
@@ -25,8 +43,50 @@ type PingOptions struct {
 	Timeout time.Duration     // Maximum time to wait for response
 	Retries int               // Number of retry attempts on failure
 	Headers map[string]string // HTTP headers to include in the request
+	Body    []byte            // Optional request body (resent on retries)
+
+	UserAgent string // User-Agent to send, even if Headers set one ("" = Headers' or DefaultUserAgent)
+
+	RetryOnStatus []int         // Also retry responses with these status codes (e.g. 429, 503)
+	RetryMaxDelay time.Duration // Longest wait between retries, even if Retry-After asks for more (0 = DefaultRetryMaxDelay)
+
+	FirstByteTimeout time.Duration // Maximum wait for the first response byte (0 = only Timeout)
+	IdleTimeout      time.Duration // Maximum pause while reading the body (0 = only Timeout)
+	ConnectTimeout   time.Duration // Maximum time to connect: DNS, TCP and TLS together (0 = 30s, within Timeout)
+	ResponseTimeout  time.Duration // Maximum wait for response headers once the request is sent (0 = only Timeout)
+
+	Resolve   map[string]string // Connect to these addresses instead of resolving "host:port" (see ParseResolve)
+	DNSServer string            // DNS server to resolve hosts with ("" = system resolver)
+	LocalAddr string            // Source IP address to send from ("" = chosen by the OS)
+	IPVersion string            // Force an IP version ("", "4", "6")
+
+	HTTPVersion      string // Force a protocol version ("", "1.1", "2")
+	DisableKeepAlive bool   // Open a new connection for every request, to measure cold connections
+	ReadBody         bool   // Read the response body into Result.Body
+	Download         bool   // Read the full body to measure its exact size and transfer rate
+	Compressed       bool   // Send Accept-Encoding and measure compressed vs decoded size (implies Download)
+
+	Auth *Credentials   // Optional Basic or Digest credentials
+	Jar  http.CookieJar // Optional cookie jar shared between requests
+	Pool *Pool          // Share connections with other requests (nil = a new transport per client)
+
+	Dump     io.Writer // Write a curl -v style dump of every request and response (nil = off)
+	DumpBody int       // Bytes of each response body to include in Dump
 }
 
+// DefaultUserAgent identifies tapr to servers when neither
+// PingOptions.UserAgent nor a User-Agent header is set. Some APIs block
+// requests with Go's default agent.
+var DefaultUserAgent = "tapr"
+
+// MaxBodySize limits how much of a response body is kept in memory.
+const MaxBodySize = 10 * 1024 * 1024 // 10 MB
+
+// maxDrainSize limits how much of an unread response body is read and
+// discarded to keep the connection alive. Past it, dropping the connection
+// is cheaper than downloading the rest.
+const maxDrainSize = 64 << 10 // 64 KB
+
 // Ping makes an HTTP request to the specified URL and returns detailed
 // timing and response information. It will retry the request if it fails,
 // up to the number of times specified in options.Retries.
@@ -44,43 +104,101 @@ type PingOptions struct {
 //	}
 //	result := request.Ping("https://api.example.com/health", opts)
 func Ping(url string, opts PingOptions) Result {
-	// Create HTTP client with custom timeout
+	return PingContext(context.Background(), url, opts)
+}
+
+// PingContext behaves like Ping, but stops as soon as ctx is done: a
+// request in flight is canceled and a wait between retries cut short.
+func PingContext(ctx context.Context, url string, opts PingOptions) Result {
+	client, err := NewClient(opts)
+	if err != nil {
+		return Result{URL: url, Error: err}
+	}
+	if opts.Pool == nil {
+		defer client.CloseIdleConnections() // Nothing else will reuse them
+	}
+
+	return PingWithClientContext(ctx, client, url, opts)
+}
+
+// NewClient creates an HTTP client configured from opts. The client keeps
+// connections alive and can be shared across PingWithClient calls to
+// measure warm-connection latency; with opts.DisableKeepAlive set, every
+// request made with it opens a new connection. With opts.Pool set, the client uses
+// the pool's shared transport instead. With opts.Dump set, every exchange
+// is dumped.
+func NewClient(opts PingOptions) (*http.Client, error) {
+	var transport http.RoundTripper
+	var err error
+	if opts.Pool != nil {
+		transport, err = opts.Pool.roundTripper(opts)
+	} else {
+		transport, err = newRoundTripper(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	client := &http.Client{
-		Timeout: opts.Timeout,
+		Timeout:   opts.Timeout,
+		Transport: transport,
+		Jar:       opts.Jar,
 	}
+	if opts.Dump != nil {
+		client.Transport = newDumpTransport(transport, opts.Dump, opts.DumpBody)
+	}
+	return client, nil
+}
+
+// PingWithClient behaves like Ping but uses the given client, allowing
+// connections to be reused between calls.
+func PingWithClient(client *http.Client, url string, opts PingOptions) Result {
+	return PingWithClientContext(context.Background(), client, url, opts)
+}
 
+// PingWithClientContext behaves like PingWithClient, but stops as soon as
+// ctx is done.
+func PingWithClientContext(ctx context.Context, client *http.Client, url string, opts PingOptions) Result {
 	var lastResult Result
 	maxAttempts := opts.Retries + 1 // Initial attempt + retries
 
 	// Attempt the request, with retries if needed
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		lastResult = makeRequest(client, url, opts.Method, opts.Headers)
+		lastResult = makeRequest(ctx, client, url, opts)
 
-		// If successful, return immediately
-		if lastResult.Error == nil {
+		// If successful (or failed for good), return immediately
+		if !shouldRetry(lastResult, opts.RetryOnStatus) {
 			return lastResult
 		}
 
-		// If this wasn't the last attempt, wait before retrying
+		// If this wasn't the last attempt, wait before retrying, as long
+		// as the server asks with Retry-After
 		if attempt < maxAttempts-1 {
-			// Exponential backoff: 1s, 2s, 4s, 8s...
-			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			time.Sleep(backoff)
+			backoff := RetryDelay(attempt+1, lastResult, opts.RetryMaxDelay)
+			reason := lastResult.Status
+			if lastResult.Error != nil {
+				reason = lastResult.Error.Error()
+			}
+			slog.Debug("retrying request", "url", url, "attempt", attempt+2, "of", maxAttempts,
+				"backoff", backoff, "reason", reason)
+			if err := Sleep(ctx, backoff); err != nil {
+				break
+			}
 		}
 	}
 
-	// Return the last result (which contains the error)
+	// Return the last result (which contains the error or retried status)
 	return lastResult
 }
 
 // makeRequest performs a single HTTP request and measures its timing.
 // This is an internal helper function used by Ping.
-func makeRequest(client *http.Client, url, method string, headers map[string]string) Result {
+func makeRequest(ctx context.Context, client *http.Client, url string, opts PingOptions) Result {
 	// Record the start time for latency measurement
 	start := time.Now()
 
 	// Create the HTTP request
-	req, err := http.NewRequest(method, url, nil)
+	req, err := newRequest(url, opts)
 	if err != nil {
 		return Result{
 			URL:     url,
@@ -89,38 +207,276 @@ func makeRequest(client *http.Client, url, method string, headers map[string]str
 		}
 	}
 
-	// Add headers to the request
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	// Cancel the request if the first byte or body data is late
+	ctx, watchdog := newWatchdog(ctx)
+	defer watchdog.stop()
+	watchdog.arm(opts.FirstByteTimeout, firstByteTimeoutError(opts.FirstByteTimeout))
+
+	// Cancel it if getting a connection is slow too: DNS, TCP and the TLS
+	// handshake share one deadline, lifted once GotConn fires
+	ctx, connectWatchdog := newWatchdog(ctx)
+	defer connectWatchdog.stop()
+	connectWatchdog.arm(opts.ConnectTimeout, connectTimeoutError(opts.ConnectTimeout))
+
+	// Record whether the connection was reused and when the first
+	// response byte arrived (the last one, after a Digest challenge)
+	var (
+		connReused bool
+		remoteAddr string
+		ttfb       time.Duration
+	)
+	trace := &httptrace.ClientTrace{
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err != nil {
+				slog.Debug("DNS lookup failed", "url", url, "error", info.Err)
+				return
+			}
+			slog.Debug("resolved host", "url", url, "addrs", len(info.Addrs))
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err != nil {
+				slog.Debug("TLS handshake failed", "url", url, "error", err)
+				return
+			}
+			slog.Debug("TLS handshake done", "url", url, "version", tls.VersionName(state.Version), "resumed", state.DidResume)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			connectWatchdog.disarm()
+			connReused = info.Reused
+			remoteAddr = info.Conn.RemoteAddr().String()
+			slog.Debug("got connection", "url", url, "remote", remoteAddr, "reused", info.Reused)
+		},
+		GotFirstResponseByte: func() {
+			ttfb = time.Since(start)
+			watchdog.disarm()
+		},
 	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
 
 	// Execute the request
+	slog.Debug("sending request", "method", req.Method, "url", url, "headers", len(req.Header))
 	resp, err := client.Do(req)
+	if expired := connectWatchdog.expired(); err != nil && expired != nil {
+		err = expired
+	} else if expired := watchdog.expired(); err != nil && expired != nil {
+		err = expired
+	} else if err != nil {
+		err = phaseTimeout(err, opts)
+	}
+
+	// Answer a Digest challenge with a second, authenticated request.
+	// Latency covers the full challenge-response exchange.
+	if err == nil && opts.Auth != nil && opts.Auth.Digest {
+		retry, retryErr := digestRetry(req, resp, opts.Auth)
+		if retryErr != nil {
+			resp.Body.Close()
+			resp, err = nil, retryErr
+		} else if retry != nil {
+			_, _ = io.CopyN(io.Discard, resp.Body, maxDrainSize)
+			resp.Body.Close()
+			resp, err = client.Do(retry)
+		}
+	}
 
 	// Calculate total latency
 	latency := time.Since(start)
+	if err == nil {
+		slog.Debug("received response", "url", url, "status", resp.StatusCode, "protocol", resp.Proto, "latency", latency)
+	}
 
 	// Handle request errors (network issues, timeout, etc.)
 	if err != nil {
 		return Result{
-			URL:     url,
-			Latency: latency,
-			Error:   err,
+			URL:        url,
+			Latency:    latency,
+			TTFB:       ttfb,
+			ConnReused: connReused,
+			RemoteAddr: remoteAddr,
+			Error:      err,
 		}
 	}
 
 	// Always close the response body to prevent connection leaks
 	// defer ensures this runs even if we return early
-	defer resp.Body.Close()
+	keepAlive := !opts.DisableKeepAlive
+	if opts.Pool != nil {
+		keepAlive = opts.Pool.KeepAlive()
+	}
+	defer func() {
+		// Drain the rest of a short body so keep-alive connections can be reused
+		if keepAlive {
+			_, _ = io.CopyN(io.Discard, resp.Body, maxDrainSize)
+		}
+		resp.Body.Close()
+	}()
+
+	// Make sure a forced protocol version was actually used
+	if err := checkProtocol(resp, opts.HTTPVersion); err != nil {
+		return Result{
+			URL:      url,
+			Latency:  latency,
+			Protocol: resp.Proto,
+			Error:    err,
+		}
+	}
 
 	// Return successful result with all response metadata
-	return Result{
+	result := Result{
 		URL:        url,
+		FinalURL:   resp.Request.URL.String(),
 		StatusCode: resp.StatusCode,
 		Status:     resp.Status,
 		Latency:    latency,
+		TTFB:       ttfb,
 		Size:       resp.ContentLength,
 		Protocol:   resp.Proto,
+		Headers:    resp.Header,
+		ConnReused: connReused,
+		RemoteAddr: remoteAddr,
 		Error:      nil,
 	}
+
+	// Fail reads once the server pauses for longer than IdleTimeout
+	var raw io.Reader = resp.Body
+	if opts.IdleTimeout > 0 {
+		raw = newIdleReader(resp.Body, watchdog, opts.IdleTimeout)
+	}
+
+	// With Compressed, count the bytes on the wire and read decoded bytes
+	downloadStart := time.Now()
+	body := raw
+	var wire *countingReader
+	if opts.Compressed {
+		result.Encoding = resp.Header.Get("Content-Encoding")
+		wire = &countingReader{r: raw}
+		decoded, ok, err := decoder(wire, result.Encoding)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to decode %s response body: %w", result.Encoding, err)
+			return result
+		}
+		if !ok {
+			// Unknown encoding: measure the transfer only
+			if _, err := io.Copy(io.Discard, wire); err != nil {
+				result.Error = fmt.Errorf("failed to download response body: %w", err)
+				return result
+			}
+			result.Size = -1
+			result.CompressedSize = wire.n
+			result.Download = time.Since(downloadStart)
+			return result
+		}
+		body = decoded
+	}
+
+	// Read the body if requested (latency above excludes body transfer)
+	var read int64
+	if opts.ReadBody {
+		// One byte past the limit tells a cut-off body from one that fits
+		data, err := io.ReadAll(io.LimitReader(body, MaxBodySize+1))
+		if err != nil {
+			result.Error = fmt.Errorf("failed to read response body: %w", err)
+			return result
+		}
+		read = int64(len(data))
+		if read > MaxBodySize {
+			data, result.Truncated = data[:MaxBodySize], true
+		}
+		result.Body = data
+	}
+
+	// Download the rest of the body, counting the bytes actually received
+	// (ContentLength is -1 for chunked responses)
+	if opts.Download || opts.Compressed {
+		n, err := io.Copy(io.Discard, body)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to download response body: %w", err)
+			return result
+		}
+		result.Size = read + n
+		result.Download = time.Since(downloadStart)
+		if wire != nil {
+			result.CompressedSize = wire.n
+		}
+	}
+
+	return result
+}
+
+// Throughput returns the body transfer rate in bytes per second, or 0 if
+// the body was not downloaded.
+func (r Result) Throughput() float64 {
+	size := r.Size
+	if r.CompressedSize > 0 {
+		size = r.CompressedSize // Bytes actually transferred
+	}
+	if r.Download <= 0 || size <= 0 {
+		return 0
+	}
+	return float64(size) / r.Download.Seconds()
+}
+
+// newRequest builds an HTTP request with body, headers and credentials applied.
+func newRequest(url string, opts PingOptions) (*http.Request, error) {
+	var body io.Reader
+	if opts.Body != nil {
+		body = bytes.NewReader(opts.Body)
+	}
+
+	req, err := http.NewRequest(opts.Method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ask for a compressed response; set explicitly, Go leaves the body
+	// encoded so both sizes can be measured
+	if opts.Compressed {
+		req.Header.Set("Accept-Encoding", AcceptEncoding)
+	}
+
+	// Add headers to the request
+	for key, value := range opts.Headers {
+		req.Header.Set(key, value)
+	}
+	if opts.UserAgent != "" {
+		req.Header.Set("User-Agent", opts.UserAgent)
+	} else if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", DefaultUserAgent)
+	}
+
+	// Basic auth is sent up front; Digest waits for the server's challenge
+	if opts.Auth != nil && !opts.Auth.Digest {
+		req.SetBasicAuth(opts.Auth.Username, opts.Auth.Password)
+	}
+
+	return req, nil
+}
+
+// Preview builds the request that Ping would send to url, without sending
+// it. Cookies from opts.Jar are included; a Digest Authorization header is
+// not, since it depends on the server's challenge.
+func Preview(url string, opts PingOptions) (*http.Request, error) {
+	req, err := newRequest(url, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Jar != nil {
+		for _, cookie := range opts.Jar.Cookies(req.URL) {
+			req.AddCookie(cookie)
+		}
+	}
+	return req, nil
+}
+
+// rewindBody resets req's body so the request can be sent again.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
 }
@@ -0,0 +1,64 @@
+package request
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PingOptions
+		want string
+	}{
+		{"default", PingOptions{}, DefaultUserAgent},
+		{"header", PingOptions{Headers: map[string]string{"user-agent": "curl/8.0"}}, "curl/8.0"},
+		{"option", PingOptions{UserAgent: "Mozilla/5.0"}, "Mozilla/5.0"},
+		{"option beats header", PingOptions{UserAgent: "Mozilla/5.0", Headers: map[string]string{"User-Agent": "curl/8.0"}}, "Mozilla/5.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Method = "GET"
+			req, err := Preview("https://api.example.com/", tt.opts)
+			if err != nil {
+				t.Fatalf("Preview() error = %v", err)
+			}
+			if got := req.Header.Get("User-Agent"); got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPingReadBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		w.Write(bytes.Repeat([]byte("x"), size))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		size          int
+		wantLen       int
+		wantTruncated bool
+	}{
+		{size: 10, wantLen: 10},
+		{size: MaxBodySize, wantLen: MaxBodySize},
+		{size: MaxBodySize + 1, wantLen: MaxBodySize, wantTruncated: true},
+		{size: MaxBodySize + 4096, wantLen: MaxBodySize, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		result := Ping(server.URL+"?size="+strconv.Itoa(tt.size), PingOptions{Method: "GET", ReadBody: true})
+		if result.Error != nil {
+			t.Fatalf("Ping() error = %v", result.Error)
+		}
+		if len(result.Body) != tt.wantLen || result.Truncated != tt.wantTruncated {
+			t.Errorf("body of %d bytes: read %d, truncated %t; want %d, %t",
+				tt.size, len(result.Body), result.Truncated, tt.wantLen, tt.wantTruncated)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package request
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+)
+
+// AcceptEncoding is sent with PingOptions.Compressed. Brotli is offered so
+// servers that prefer it are detected, but tapr cannot decode it without a
+// third-party package, so only its compressed size is measured.
+const AcceptEncoding = "gzip, deflate, br"
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// decoder wraps r to decode the given Content-Encoding. ok is false for
+// encodings tapr cannot decode (br, zstd, ...).
+func decoder(r io.Reader, encoding string) (decoded io.Reader, ok bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return r, true, nil
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(r)
+		return zr, true, err
+	case "deflate":
+		zr, err := zlib.NewReader(r)
+		return zr, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// CompressionRatio returns how much smaller the transferred body was than
+// the decoded one, as a fraction (0.75 = 75% smaller), or 0 if unknown.
+func (r Result) CompressionRatio() float64 {
+	if r.Encoding == "" || r.CompressedSize <= 0 || r.Size <= 0 {
+		return 0
+	}
+	return 1 - float64(r.CompressedSize)/float64(r.Size)
+}
@@ -0,0 +1,74 @@
+package request
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPingCompressed(t *testing.T) {
+	payload := strings.Repeat(`{"status":"ok"}`, 200)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/br" && strings.Contains(r.Header.Get("Accept-Encoding"), "br"):
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte("not really brotli"))
+		case strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"):
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write([]byte(payload))
+			zw.Close()
+		default:
+			w.Write([]byte(payload))
+		}
+	}))
+	defer server.Close()
+
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, Compressed: true, ReadBody: true}
+
+	t.Run("gzip", func(t *testing.T) {
+		result := Ping(server.URL, opts)
+		if result.Error != nil {
+			t.Fatalf("Ping() error = %v", result.Error)
+		}
+		if result.Encoding != "gzip" {
+			t.Errorf("Encoding = %q, want gzip", result.Encoding)
+		}
+		if result.Size != int64(len(payload)) || string(result.Body) != payload {
+			t.Errorf("Size = %d, want decoded size %d", result.Size, len(payload))
+		}
+		if result.CompressedSize <= 0 || result.CompressedSize >= result.Size {
+			t.Errorf("CompressedSize = %d, want less than %d", result.CompressedSize, result.Size)
+		}
+		if ratio := result.CompressionRatio(); ratio < 0.9 {
+			t.Errorf("CompressionRatio() = %.2f, want > 0.9 for a repetitive payload", ratio)
+		}
+	})
+
+	t.Run("undecodable encoding", func(t *testing.T) {
+		result := Ping(server.URL+"/br", opts)
+		if result.Error != nil {
+			t.Fatalf("Ping() error = %v", result.Error)
+		}
+		if result.Encoding != "br" || result.CompressedSize != int64(len("not really brotli")) {
+			t.Errorf("Encoding = %q, CompressedSize = %d", result.Encoding, result.CompressedSize)
+		}
+		if result.Size != -1 || result.CompressionRatio() != 0 {
+			t.Errorf("Size = %d, CompressionRatio() = %v, want unknown", result.Size, result.CompressionRatio())
+		}
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		result := Ping(server.URL, PingOptions{Method: "GET", Timeout: 5 * time.Second, ReadBody: true})
+		if result.Encoding != "" || result.CompressedSize != 0 {
+			t.Errorf("Encoding = %q, CompressedSize = %d, want unset", result.Encoding, result.CompressedSize)
+		}
+		if string(result.Body) != payload {
+			t.Error("Body should be transparently decoded without Compressed")
+		}
+	})
+}
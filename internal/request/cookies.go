@@ -0,0 +1,256 @@
+package request
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieJar is an http.CookieJar that keeps cookies across requests and can
+// be saved to and loaded from a Netscape/curl-format cookie file.
+//
+// Cookies given with AddStatic are sent to every host, like curl's --cookie.
+type CookieJar struct {
+	jar *cookiejar.Jar
+
+	mu      sync.Mutex
+	static  []*http.Cookie         // Cookies sent with every request
+	entries map[string]savedCookie // Cookies set by servers, for saving
+}
+
+// savedCookie remembers where a cookie was set so it can be persisted.
+type savedCookie struct {
+	domain     string
+	subdomains bool
+	cookie     *http.Cookie
+}
+
+// NewCookieJar creates an empty cookie jar.
+func NewCookieJar() *CookieJar {
+	jar, _ := cookiejar.New(nil) // Only fails with invalid options
+	return &CookieJar{
+		jar:     jar,
+		entries: make(map[string]savedCookie),
+	}
+}
+
+// LoadCookieJar reads a Netscape-format cookie file. A missing file is not
+// an error and returns an empty jar, so the same path can be used to save.
+func LoadCookieJar(path string) (*CookieJar, error) {
+	jar := NewCookieJar()
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return jar, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookie jar: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// curl marks HttpOnly cookies with a #HttpOnly_ prefix
+		httpOnly := strings.HasPrefix(line, "#HttpOnly_")
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookie jar line %d: expected 7 tab-separated fields", lineNum)
+		}
+
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie jar line %d: bad expiry: %w", lineNum, err)
+		}
+
+		domain := fields[0]
+		subdomains := fields[1] == "TRUE"
+		secure := fields[3] == "TRUE"
+
+		cookie := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   secure,
+			HttpOnly: httpOnly,
+		}
+		if expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+		}
+		if subdomains {
+			cookie.Domain = domain
+		}
+
+		scheme := "http"
+		if secure {
+			scheme = "https"
+		}
+		u := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(domain, "."), Path: cookie.Path}
+		jar.SetCookies(u, []*http.Cookie{cookie})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookie jar: %w", err)
+	}
+
+	return jar, nil
+}
+
+// AddStatic adds cookies that are sent with every request.
+func (j *CookieJar) AddStatic(cookies ...*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.static = append(j.static, cookies...)
+}
+
+// SetCookies implements http.CookieJar.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, cookie := range cookies {
+		domain := u.Hostname()
+		subdomains := false
+		if cookie.Domain != "" {
+			domain = "." + strings.TrimPrefix(cookie.Domain, ".")
+			subdomains = true
+		}
+
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+
+		saved := *cookie
+		saved.Path = path
+		key := domain + "\t" + path + "\t" + cookie.Name
+
+		// MaxAge < 0 or an expiry in the past deletes the cookie
+		if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(time.Now())) {
+			delete(j.entries, key)
+			continue
+		}
+		if cookie.MaxAge > 0 {
+			saved.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+
+		j.entries[key] = savedCookie{domain: domain, subdomains: subdomains, cookie: &saved}
+	}
+}
+
+// Cookies implements http.CookieJar. Static cookies are added unless the
+// server has set a cookie with the same name.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	cookies := j.jar.Cookies(u)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, static := range j.static {
+		overridden := false
+		for _, cookie := range cookies {
+			if cookie.Name == static.Name {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			cookies = append(cookies, static)
+		}
+	}
+
+	return cookies
+}
+
+// Save writes all server-set cookies to path in Netscape/curl format.
+func (j *CookieJar) Save(path string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# Netscape HTTP Cookie File\n")
+	b.WriteString("# Generated by tapr\n\n")
+
+	for _, entry := range j.entries {
+		cookie := entry.cookie
+
+		var expiry int64
+		if !cookie.Expires.IsZero() {
+			expiry = cookie.Expires.Unix()
+		}
+
+		prefix := ""
+		if cookie.HttpOnly {
+			prefix = "#HttpOnly_"
+		}
+
+		fmt.Fprintf(&b, "%s%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			prefix,
+			entry.domain,
+			netscapeBool(entry.subdomains),
+			cookie.Path,
+			netscapeBool(cookie.Secure),
+			expiry,
+			cookie.Name,
+			cookie.Value,
+		)
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to save cookie jar: %w", err)
+	}
+	return nil
+}
+
+// netscapeBool formats a boolean the way cookie files expect.
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// ParseCookies parses curl-style cookie strings such as "session=abc" or
+// "a=1; b=2" into cookies.
+func ParseCookies(values []string) ([]*http.Cookie, error) {
+	cookies := make([]*http.Cookie, 0, len(values))
+
+	for _, value := range values {
+		for _, pair := range strings.Split(value, ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+				return nil, fmt.Errorf("invalid cookie format: '%s' (expected 'name=value')", pair)
+			}
+
+			cookies = append(cookies, &http.Cookie{
+				Name:  strings.TrimSpace(parts[0]),
+				Value: strings.TrimSpace(parts[1]),
+			})
+		}
+	}
+
+	return cookies, nil
+}
@@ -0,0 +1,111 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseCookies(t *testing.T) {
+	cookies, err := ParseCookies([]string{"session=abc", "a=1; b=2"})
+	if err != nil {
+		t.Fatalf("ParseCookies() error = %v", err)
+	}
+
+	want := map[string]string{"session": "abc", "a": "1", "b": "2"}
+	if len(cookies) != len(want) {
+		t.Fatalf("ParseCookies() returned %d cookies, want %d", len(cookies), len(want))
+	}
+	for _, cookie := range cookies {
+		if want[cookie.Name] != cookie.Value {
+			t.Errorf("cookie %s = %s, want %s", cookie.Name, cookie.Value, want[cookie.Name])
+		}
+	}
+
+	if _, err := ParseCookies([]string{"novalue"}); err == nil {
+		t.Error("ParseCookies() expected error for missing '='")
+	}
+}
+
+func TestCookieJar_Session(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t", Path: "/"})
+			return
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if cookie, err := r.Cookie("static"); err != nil || cookie.Value != "yes" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}))
+	defer server.Close()
+
+	jar := NewCookieJar()
+	jar.AddStatic(&http.Cookie{Name: "static", Value: "yes"})
+
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, Jar: jar}
+
+	if result := Ping(server.URL+"/login", opts); result.Error != nil {
+		t.Fatalf("login error = %v", result.Error)
+	}
+
+	result := Ping(server.URL+"/profile", opts)
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("profile StatusCode = %d, want 200 (session cookie not sent?)", result.StatusCode)
+	}
+}
+
+func TestCookieJar_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	u, _ := url.Parse("https://api.example.com/")
+
+	jar := NewCookieJar()
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "abc", Path: "/", Secure: true, HttpOnly: true},
+		{Name: "shared", Value: "xyz", Domain: "example.com", Expires: time.Now().Add(time.Hour)},
+	})
+
+	if err := jar.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadCookieJar(path)
+	if err != nil {
+		t.Fatalf("LoadCookieJar() error = %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, cookie := range loaded.Cookies(u) {
+		got[cookie.Name] = cookie.Value
+	}
+
+	if got["session"] != "abc" {
+		t.Errorf("session = %q, want abc", got["session"])
+	}
+	if got["shared"] != "xyz" {
+		t.Errorf("shared = %q, want xyz", got["shared"])
+	}
+
+	// Domain cookies apply to subdomains too
+	other, _ := url.Parse("https://www.example.com/")
+	if cookies := loaded.Cookies(other); len(cookies) != 1 || cookies[0].Name != "shared" {
+		t.Errorf("Cookies(www.example.com) = %v, want only 'shared'", cookies)
+	}
+}
+
+func TestLoadCookieJar_Missing(t *testing.T) {
+	jar, err := LoadCookieJar(filepath.Join(t.TempDir(), "missing.txt"))
+	if err != nil {
+		t.Fatalf("LoadCookieJar() error = %v, want nil for missing file", err)
+	}
+	if jar == nil {
+		t.Fatal("LoadCookieJar() returned nil jar")
+	}
+}
@@ -0,0 +1,44 @@
+package request
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// AllowedMethods returns the methods a response lists in its Allow header,
+// as sent by servers answering OPTIONS (or 405), upper-cased and without
+// duplicates.
+func AllowedMethods(header http.Header) []string {
+	return headerList(header, "Allow", strings.ToUpper)
+}
+
+// CORSHeaders returns the names of the Access-Control-* headers of a
+// response, sorted.
+func CORSHeaders(header http.Header) []string {
+	var names []string
+	for name := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "Access-Control-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// headerList splits the comma-separated values of a header, which may be
+// sent more than once, normalizing each with normalize.
+func headerList(header http.Header, name string, normalize func(string) string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, line := range header.Values(name) {
+		for _, value := range strings.Split(line, ",") {
+			value = normalize(strings.TrimSpace(value))
+			if value != "" && !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
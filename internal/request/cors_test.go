@@ -0,0 +1,33 @@
+package request
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestAllowedMethods(t *testing.T) {
+	header := http.Header{}
+	header.Add("Allow", "GET, head,POST")
+	header.Add("Allow", "OPTIONS, GET")
+	want := []string{"GET", "HEAD", "POST", "OPTIONS"}
+	if got := AllowedMethods(header); !slices.Equal(got, want) {
+		t.Errorf("AllowedMethods() = %v, want %v", got, want)
+	}
+	if got := AllowedMethods(http.Header{}); got != nil {
+		t.Errorf("AllowedMethods() = %v, want nil", got)
+	}
+}
+
+func TestCORSHeaders(t *testing.T) {
+	header := http.Header{
+		"Access-Control-Max-Age":       {"600"},
+		"Access-Control-Allow-Origin":  {"*"},
+		"Content-Type":                 {"text/plain"},
+		"Access-Control-Allow-Methods": {"GET, POST"},
+	}
+	want := []string{"Access-Control-Allow-Methods", "Access-Control-Allow-Origin", "Access-Control-Max-Age"}
+	if got := CORSHeaders(header); !slices.Equal(got, want) {
+		t.Errorf("CORSHeaders() = %v, want %v", got, want)
+	}
+}
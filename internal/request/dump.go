@@ -0,0 +1,154 @@
+package request
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// dumpTransport writes every request it sends and the response it gets, in
+// the style of curl -v: "*" lines for connection events, ">" for the request
+// and "<" for the response. Secrets are masked.
+type dumpTransport struct {
+	next http.RoundTripper
+	w    io.Writer
+	body int        // Bytes of the response body to preview
+	mu   sync.Mutex // Serializes dumps of concurrent requests
+}
+
+// newDumpTransport wraps next so requests are dumped to w, with up to body
+// bytes of each response body.
+func newDumpTransport(next http.RoundTripper, w io.Writer, body int) *dumpTransport {
+	return &dumpTransport{next: next, w: w, body: body}
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport.
+func (t *dumpTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// RoundTrip sends req through the wrapped transport and dumps the exchange.
+// The dump of one exchange is written at once so concurrent requests don't
+// interleave.
+func (t *dumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var b strings.Builder
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				fmt.Fprintf(&b, "* Reusing connection to %s\n", info.Conn.RemoteAddr())
+			} else {
+				fmt.Fprintf(&b, "* Connected to %s (%s)\n", req.URL.Host, info.Conn.RemoteAddr())
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err != nil {
+				fmt.Fprintf(&b, "* TLS handshake failed: %v\n", err)
+				return
+			}
+			fmt.Fprintf(&b, "* %s, %s", tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+			if state.NegotiatedProtocol != "" {
+				fmt.Fprintf(&b, ", ALPN %s", state.NegotiatedProtocol)
+			}
+			b.WriteString("\n")
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := t.next.RoundTrip(req)
+
+	// Connection events come first, though they are recorded while sending
+	dumpRequest(&b, req)
+	if err != nil {
+		fmt.Fprintf(&b, "* Request failed: %v\n", err)
+	} else {
+		resp.Body = dumpResponse(&b, resp, t.body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.w, b.String()+"\n")
+	return resp, err
+}
+
+// dumpRequest writes the request line and headers of req, marked with ">".
+func dumpRequest(b *strings.Builder, req *http.Request) {
+	fmt.Fprintf(b, "> %s %s\n", req.Method, MaskURL(req.URL.String()))
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	fmt.Fprintf(b, "> Host: %s\n", host)
+	writeHeaders(b, ">", req.Header)
+	if req.ContentLength > 0 {
+		fmt.Fprintf(b, "> Content-Length: %d\n", req.ContentLength)
+	}
+	b.WriteString(">\n")
+}
+
+// dumpResponse writes the status line and headers of resp, marked with "<",
+// followed by up to limit bytes of its body. It returns a body that still
+// yields every byte, so the caller can read the response as usual.
+func dumpResponse(b *strings.Builder, resp *http.Response, limit int) io.ReadCloser {
+	fmt.Fprintf(b, "< %s %s\n", resp.Proto, resp.Status)
+	writeHeaders(b, "<", resp.Header)
+	b.WriteString("<\n")
+
+	if limit <= 0 || resp.Body == nil {
+		return resp.Body
+	}
+	// Read one extra byte to tell whether the body is longer than the preview
+	preview, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	shown := preview[:min(len(preview), limit)]
+	switch {
+	case len(shown) == 0:
+	case utf8.Valid(shown) && !bytes.ContainsRune(shown, 0):
+		b.WriteString(strings.TrimRight(string(shown), "\n") + "\n")
+		if len(preview) > limit {
+			fmt.Fprintf(b, "[body truncated after %d bytes]\n", limit)
+		}
+	default:
+		b.WriteString("[binary body]\n")
+	}
+
+	// Replay the preview, then the rest of the body (or the read error)
+	rest := resp.Body
+	if err != nil {
+		rest = struct {
+			io.Reader
+			io.Closer
+		}{&errReader{err: err}, resp.Body}
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(preview), rest), resp.Body}
+}
+
+// writeHeaders writes headers sorted by name, with secrets masked.
+func writeHeaders(b *strings.Builder, marker string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(b, "%s %s: %s\n", marker, name, MaskHeader(name, value))
+		}
+	}
+}
+
+// errReader fails every read with err.
+type errReader struct{ err error }
+
+// Read returns the error.
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,68 @@
+package request
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc123")
+		w.Write([]byte(`{"status": "ok", "version": "1.4.2"}`))
+	}))
+	defer server.Close()
+
+	var dump bytes.Buffer
+	result := Ping(server.URL+"/health?api_key=s3cret&page=2", PingOptions{
+		Method:   "GET",
+		Timeout:  5 * time.Second,
+		Headers:  map[string]string{"Authorization": "Bearer s3cret", "Accept": "application/json"},
+		ReadBody: true,
+		Dump:     &dump,
+		DumpBody: 16,
+	})
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+
+	got := dump.String()
+	for _, want := range []string{
+		"* Connected to ",
+		"> GET " + server.URL + "/health?api_key=****&page=2\n",
+		"> Accept: application/json\n",
+		"> Authorization: Bearer ****\n",
+		"< HTTP/1.1 200 OK\n",
+		"< Set-Cookie: ****\n",
+		`{"status": "ok",` + "\n[body truncated after 16 bytes]\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("dump is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "s3cret") || strings.Contains(got, "abc123") {
+		t.Errorf("dump leaks a secret:\n%s", got)
+	}
+
+	// The preview must not be taken from the body
+	if string(result.Body) != `{"status": "ok", "version": "1.4.2"}` {
+		t.Errorf("Body = %q, want the full body", result.Body)
+	}
+}
+
+func TestDumpFailedRequest(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var dump bytes.Buffer
+	result := Ping(server.URL, PingOptions{Method: "GET", Timeout: time.Second, Dump: &dump})
+	if result.Error == nil {
+		t.Fatal("Ping() of a closed server should fail")
+	}
+	if got := dump.String(); !strings.Contains(got, "> GET ") || !strings.Contains(got, "* Request failed: ") {
+		t.Errorf("dump = %q, want the request and the failure", got)
+	}
+}
@@ -0,0 +1,75 @@
+package request
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// Failure categories reported by Classify.
+const (
+	CategoryDNS       = "dns"       // Host name could not be resolved
+	CategoryConnect   = "connect"   // TCP connection refused, reset or unreachable
+	CategoryTLS       = "tls"       // TLS handshake or certificate failure
+	CategoryTimeout   = "timeout"   // No response within the timeout
+	CategoryServer    = "5xx"       // Server error response
+	CategoryClient    = "4xx"       // Client error response
+	CategorySlow      = "slow"      // Response exceeded a latency limit (set by callers)
+	CategoryAssertion = "assertion" // Response failed an expectation (set by callers)
+	CategoryOther     = "other"     // Any other request error
+)
+
+// Classify returns the failure category of a result: a transport category
+// when the request failed, CategoryServer or CategoryClient for error
+// status codes, or "" for a 1xx-3xx response.
+func Classify(result Result) string {
+	if result.Error != nil {
+		return ClassifyError(result.Error)
+	}
+	switch {
+	case result.StatusCode >= 500:
+		return CategoryServer
+	case result.StatusCode >= 400:
+		return CategoryClient
+	}
+	return ""
+}
+
+// ClassifyError returns the category of a request error. DNS failures are
+// checked first since resolver timeouts are also net.Error timeouts.
+func ClassifyError(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return CategoryDNS
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return CategoryTimeout
+	}
+
+	var (
+		recordErr    tls.RecordHeaderError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	if errors.As(err, &recordErr) || errors.As(err, &verifyErr) || errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) || strings.Contains(err.Error(), "tls: ") {
+		return CategoryTLS
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return CategoryConnect
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EHOSTUNREACH) {
+		return CategoryConnect
+	}
+
+	return CategoryOther
+}
@@ -0,0 +1,87 @@
+package request
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"dns", &net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}, CategoryDNS},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "slow.example", IsTimeout: true}, CategoryDNS},
+		{"deadline", fmt.Errorf("request failed: %w", os.ErrDeadlineExceeded), CategoryTimeout},
+		{"context deadline", context.DeadlineExceeded, CategoryTimeout},
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, CategoryConnect},
+		{"reset on read", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, CategoryConnect},
+		{"unknown authority", fmt.Errorf("request failed: %w", x509.UnknownAuthorityError{}), CategoryTLS},
+		{"handshake", errors.New("remote error: tls: handshake failure"), CategoryTLS},
+		{"other", errors.New("failed to read response body: unexpected EOF"), CategoryOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		want   string
+	}{
+		{"ok", Result{StatusCode: 200}, ""},
+		{"redirect", Result{StatusCode: 301}, ""},
+		{"not found", Result{StatusCode: 404}, CategoryClient},
+		{"unavailable", Result{StatusCode: 503}, CategoryServer},
+		{"error wins", Result{StatusCode: 0, Error: context.DeadlineExceeded}, CategoryTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.result); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyLiveErrors(t *testing.T) {
+	// A closed listener gives a real connection refused
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	result := Ping("http://"+addr, PingOptions{Method: "GET", Timeout: time.Second})
+	if got := Classify(result); got != CategoryConnect {
+		t.Errorf("refused connection classified as %q (%v)", got, result.Error)
+	}
+
+	// A self-signed test server fails certificate verification
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result = Ping(server.URL, PingOptions{Method: "GET", Timeout: time.Second})
+	if got := Classify(result); got != CategoryTLS {
+		t.Errorf("untrusted certificate classified as %q (%v)", got, result.Error)
+	}
+}
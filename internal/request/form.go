@@ -0,0 +1,109 @@
+package request
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormField is a single multipart/form-data field. When File is set the
+// field is uploaded as a file read from that path; otherwise Value is sent.
+type FormField struct {
+	Name  string
+	Value string
+	File  string
+}
+
+// ParseFormFields parses "name=value" and "name=@path" strings, as accepted
+// by --form.
+func ParseFormFields(fieldStrings []string) ([]FormField, error) {
+	fields := make([]FormField, 0, len(fieldStrings))
+
+	for _, fieldStr := range fieldStrings {
+		parts := strings.SplitN(fieldStr, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid form field: '%s' (expected 'name=value' or 'name=@file')", fieldStr)
+		}
+
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			return nil, fmt.Errorf("empty form field name in: '%s'", fieldStr)
+		}
+
+		field := FormField{Name: name}
+		if path, ok := strings.CutPrefix(parts[1], "@"); ok {
+			if path == "" {
+				return nil, fmt.Errorf("missing file path for form field '%s'", name)
+			}
+			field.File = path
+		} else {
+			field.Value = parts[1]
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// EncodeMultipart builds a multipart/form-data body from fields, reading any
+// files into memory. It returns the body and the Content-Type header
+// (including the boundary) to send with it.
+//
+// Example:
+//
+//	fields, _ := request.ParseFormFields([]string{"name=avatar", "file=@logo.png"})
+//	body, contentType, err := request.EncodeMultipart(fields)
+func EncodeMultipart(fields []FormField) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, field := range fields {
+		if field.File == "" {
+			if err := writer.WriteField(field.Name, field.Value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(field.File)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read form file: %w", err)
+		}
+
+		part, err := writer.CreatePart(filePartHeader(field.Name, field.File))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(data); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// filePartHeader returns the MIME header for a file part, guessing the
+// content type from the file extension.
+func filePartHeader(name, path string) textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     name,
+		"filename": filepath.Base(path),
+	}))
+	header.Set("Content-Type", contentType)
+	return header
+}
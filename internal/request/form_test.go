@@ -0,0 +1,110 @@
+package request
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseFormFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    FormField
+		wantErr bool
+	}{
+		{"value", "name=avatar", FormField{Name: "name", Value: "avatar"}, false},
+		{"file", "file=@logo.png", FormField{Name: "file", File: "logo.png"}, false},
+		{"equals in value", "q=a=b", FormField{Name: "q", Value: "a=b"}, false},
+		{"missing equals", "name", FormField{}, true},
+		{"empty name", "=value", FormField{}, true},
+		{"empty file path", "file=@", FormField{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormFields([]string{tt.input})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got[0] != tt.want {
+				t.Errorf("ParseFormFields() = %+v, want %+v", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeMultipart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("file contents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	body, contentType, err := EncodeMultipart([]FormField{
+		{Name: "title", Value: "weekly"},
+		{Name: "upload", File: path},
+	})
+	if err != nil {
+		t.Fatalf("EncodeMultipart() error = %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("EncodeMultipart() content type = %s, want multipart/form-data", contentType)
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("ReadForm() error = %v", err)
+	}
+
+	if got := form.Value["title"]; len(got) != 1 || got[0] != "weekly" {
+		t.Errorf("title = %v, want [weekly]", got)
+	}
+
+	files := form.File["upload"]
+	if len(files) != 1 {
+		t.Fatalf("upload files = %d, want 1", len(files))
+	}
+	if files[0].Filename != "report.txt" {
+		t.Errorf("filename = %s, want report.txt", files[0].Filename)
+	}
+	if got := files[0].Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("file content type = %s, want text/plain; charset=utf-8", got)
+	}
+}
+
+func TestEncodeMultipartMissingFile(t *testing.T) {
+	_, _, err := EncodeMultipart([]FormField{{Name: "upload", File: "/does/not/exist"}})
+	if err == nil {
+		t.Error("EncodeMultipart() error = nil, want error for missing file")
+	}
+}
+
+func TestPingSendsBody(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+	}))
+	defer server.Close()
+
+	result := Ping(server.URL, PingOptions{
+		Method:  http.MethodPost,
+		Timeout: 5 * time.Second,
+		Body:    []byte("payload"),
+	})
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if received != "payload" {
+		t.Errorf("server received %q, want %q", received, "payload")
+	}
+}
@@ -0,0 +1,257 @@
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"github.com/symtalha14/tapr/internal/netcheck"
+)
+
+// http3Transport sends requests over HTTP/3 (QUIC). Unlike the quic-go
+// RoundTripper it wraps, it reports DNS, TLS, connection and first-byte
+// events to httptrace as http.Transport does, so timings, traces and
+// dumps work the same over QUIC.
+type http3Transport struct {
+	opts      PingOptions
+	tlsConfig *tls.Config         // Shared by every connection (and its session cache)
+	shared    *http3.RoundTripper // nil when keep-alives are disabled
+
+	mu    sync.Mutex
+	conns map[string]quic.EarlyConnection // Open connections by "host:port"
+}
+
+// newHTTP3Transport returns an HTTP/3 transport configured for the given
+// options. With opts.DisableKeepAlive set, every request opens a new QUIC
+// connection, closed along with the response body.
+func newHTTP3Transport(opts PingOptions) (*http3Transport, error) {
+	switch opts.IPVersion {
+	case IPVersionAny, IPVersion4, IPVersion6:
+	default:
+		return nil, fmt.Errorf("unknown IP version: %s", opts.IPVersion)
+	}
+	if opts.LocalAddr != "" && net.ParseIP(opts.LocalAddr) == nil {
+		return nil, fmt.Errorf("invalid local address %q: not an IP address", opts.LocalAddr)
+	}
+
+	t := &http3Transport{
+		opts:      opts,
+		tlsConfig: &tls.Config{},
+		conns:     make(map[string]quic.EarlyConnection),
+	}
+	if !opts.DisableKeepAlive {
+		t.shared = t.newRoundTripper()
+	}
+	return t, nil
+}
+
+// newRoundTripper returns a quic-go RoundTripper that dials through t.
+func (t *http3Transport) newRoundTripper() *http3.RoundTripper {
+	return &http3.RoundTripper{TLSClientConfig: t.tlsConfig, Dial: t.dial}
+}
+
+// RoundTrip sends req over an open QUIC connection to its host, or a new one.
+func (t *http3Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Wait at most ResponseTimeout for the headers once connected
+	ctx, watchdog := newWatchdog(req.Context())
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			watchdog.arm(t.opts.ResponseTimeout, errors.New("http3: timeout awaiting response headers"))
+		},
+	})
+	req = req.WithContext(ctx)
+	trace := httptrace.ContextClientTrace(ctx)
+
+	rt := t.shared
+	if rt == nil {
+		rt = t.newRoundTripper()
+	} else if conn := t.conn(authority(req.URL)); conn != nil {
+		trace.GotConn(httptrace.GotConnInfo{Conn: quicConn{conn: conn}, Reused: true})
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if expired := watchdog.expired(); err != nil && expired != nil {
+		err = expired
+	}
+	watchdog.disarm()
+	if err != nil {
+		watchdog.stop()
+		if rt != t.shared {
+			rt.Close()
+		}
+		return nil, err
+	}
+	if trace.GotFirstResponseByte != nil {
+		trace.GotFirstResponseByte()
+	}
+
+	// Release the request context, and a connection of its own, once
+	// the body is closed
+	resp.Body = &closeHook{ReadCloser: resp.Body, close: func() {
+		watchdog.stop()
+		if rt != t.shared {
+			rt.Close()
+		}
+	}}
+	return resp, nil
+}
+
+// CloseIdleConnections closes the connections not carrying a request.
+func (t *http3Transport) CloseIdleConnections() {
+	if t.shared != nil {
+		t.shared.CloseIdleConnections()
+	}
+}
+
+// conn returns the open connection to addr, or nil.
+func (t *http3Transport) conn(addr string) quic.EarlyConnection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conn := t.conns[addr]
+	if conn == nil || conn.Context().Err() != nil {
+		return nil
+	}
+	return conn
+}
+
+// dial opens a QUIC connection to addr ("host:port") from a UDP socket of
+// its own, honoring the resolve, DNS server, IP version and local address
+// options, and reports each step to the httptrace of ctx.
+func (t *http3Transport) dial(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace == nil {
+		trace = &httptrace.ClientTrace{}
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	address, ok := t.opts.Resolve[net.JoinHostPort(strings.ToLower(host), port)]
+	if !ok {
+		address, err = t.lookup(ctx, host, trace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	remote, err := net.ResolveUDPAddr("udp"+t.opts.IPVersion, net.JoinHostPort(address, port))
+	if err != nil {
+		return nil, err
+	}
+
+	var local *net.UDPAddr
+	if t.opts.LocalAddr != "" {
+		local = &net.UDPAddr{IP: net.ParseIP(t.opts.LocalAddr)}
+	}
+	udpConn, err := net.ListenUDP("udp"+t.opts.IPVersion, local)
+	if err != nil {
+		return nil, err
+	}
+
+	// The QUIC handshake sets up the connection and TLS at once
+	if trace.TLSHandshakeStart != nil {
+		trace.TLSHandshakeStart()
+	}
+	conn, err := quic.DialEarly(ctx, udpConn, remote, tlsCfg, cfg)
+	if err == nil {
+		select {
+		case <-conn.HandshakeComplete():
+		case <-ctx.Done():
+			conn.CloseWithError(0, "")
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		udpConn.Close()
+		if trace.TLSHandshakeDone != nil {
+			trace.TLSHandshakeDone(tls.ConnectionState{}, err)
+		}
+		return nil, err
+	}
+	if trace.TLSHandshakeDone != nil {
+		trace.TLSHandshakeDone(conn.ConnectionState().TLS, nil)
+	}
+
+	t.mu.Lock()
+	t.conns[addr] = conn
+	t.mu.Unlock()
+	go func() {
+		<-conn.Context().Done()
+		udpConn.Close()
+		t.mu.Lock()
+		if t.conns[addr] == conn {
+			delete(t.conns, addr)
+		}
+		t.mu.Unlock()
+	}()
+
+	if trace.GotConn != nil {
+		trace.GotConn(httptrace.GotConnInfo{Conn: quicConn{conn: conn}})
+	}
+	return conn, nil
+}
+
+// lookup resolves host to a single address of the requested IP version.
+func (t *http3Transport) lookup(ctx context.Context, host string, trace *httptrace.ClientTrace) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+
+	if trace.DNSStart != nil {
+		trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+	}
+	ips, err := netcheck.NewResolver(t.opts.DNSServer).LookupIP(ctx, "ip"+t.opts.IPVersion, host)
+	if trace.DNSDone != nil {
+		addrs := make([]net.IPAddr, 0, len(ips))
+		for _, ip := range ips {
+			addrs = append(addrs, net.IPAddr{IP: ip})
+		}
+		trace.DNSDone(httptrace.DNSDoneInfo{Addrs: addrs, Err: err})
+	}
+	if err != nil {
+		return "", err
+	}
+	return ips[0].String(), nil
+}
+
+// authority returns the "host:port" that HTTP/3 connections to u are kept under.
+func authority(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+// quicConn presents a QUIC connection as the net.Conn that httptrace
+// reports. Only its addresses are available: data travels on QUIC
+// streams, not on the connection itself.
+type quicConn struct {
+	net.Conn
+	conn quic.Connection
+}
+
+func (c quicConn) LocalAddr() net.Addr  { return c.conn.LocalAddr() }
+func (c quicConn) RemoteAddr() net.Addr { return c.conn.RemoteAddr() }
+
+// closeHook calls close after closing the wrapped body.
+type closeHook struct {
+	io.ReadCloser
+	close func()
+}
+
+func (b *closeHook) Close() error {
+	err := b.ReadCloser.Close()
+	b.close()
+	return err
+}
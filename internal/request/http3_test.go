@@ -0,0 +1,117 @@
+package request
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// startHTTP3Server serves handler over HTTP/3 on a local UDP port, with
+// the certificate of httptest (valid for example.com). It returns the URL
+// to request, the options that resolve it to the server, and a transport
+// that trusts the certificate.
+func startHTTP3Server(t *testing.T, handler http.Handler, opts PingOptions) (string, PingOptions, *http3Transport) {
+	t.Helper()
+	tlsServer := httptest.NewTLSServer(nil)
+	cert := tlsServer.TLS.Certificates[0]
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsServer.Certificate())
+	tlsServer.Close()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http3.Server{TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}}, Handler: handler}
+	go server.Serve(conn)
+	t.Cleanup(func() {
+		server.Close()
+		conn.Close()
+	})
+
+	port := strconv.Itoa(conn.LocalAddr().(*net.UDPAddr).Port)
+	opts.HTTPVersion = HTTPVersion3
+	opts.Resolve = map[string]string{"example.com:" + port: "127.0.0.1"}
+	transport, err := newHTTP3Transport(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.tlsConfig.RootCAs = roots
+	t.Cleanup(transport.CloseIdleConnections)
+	return "https://example.com:" + port + "/", opts, transport
+}
+
+func TestHTTP3Request(t *testing.T) {
+	url, opts, transport := startHTTP3Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	}), PingOptions{ReadBody: true})
+	client := &http.Client{Transport: transport}
+
+	first := makeRequest(context.Background(), client, url, opts)
+	if first.Error != nil {
+		t.Fatalf("makeRequest() error = %v", first.Error)
+	}
+	if first.Protocol != "HTTP/3.0" || string(first.Body) != "HTTP/3.0" {
+		t.Errorf("Protocol = %s, server saw %s; want HTTP/3.0", first.Protocol, first.Body)
+	}
+	if first.ConnReused || !strings.HasPrefix(first.RemoteAddr, "127.0.0.1:") || first.TTFB <= 0 {
+		t.Errorf("first request: reused %t, remote %q, TTFB %v; want a new connection to 127.0.0.1",
+			first.ConnReused, first.RemoteAddr, first.TTFB)
+	}
+
+	second := makeRequest(context.Background(), client, url, opts)
+	if second.Error != nil || !second.ConnReused {
+		t.Errorf("second request: reused %t, error %v; want the connection reused", second.ConnReused, second.Error)
+	}
+}
+
+func TestHTTP3DisableKeepAlive(t *testing.T) {
+	url, opts, transport := startHTTP3Server(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+		PingOptions{DisableKeepAlive: true})
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		result := makeRequest(context.Background(), client, url, opts)
+		if result.Error != nil || result.ConnReused {
+			t.Errorf("request %d: reused %t, error %v; want a new connection", i+1, result.ConnReused, result.Error)
+		}
+	}
+}
+
+func TestHTTP3Trace(t *testing.T) {
+	url, opts, transport := startHTTP3Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}), PingOptions{DisableKeepAlive: true})
+
+	result := traceWithClient(&http.Client{Transport: transport}, url, opts, true)
+	if result.Error != nil {
+		t.Fatalf("traceWithClient() error = %v", result.Error)
+	}
+	if result.Protocol != "HTTP/3.0" || result.ALPN != "h3" {
+		t.Errorf("Protocol = %s, ALPN = %s; want HTTP/3.0 over h3", result.Protocol, result.ALPN)
+	}
+	if result.TLSHandshake <= 0 || result.ServerProcessing <= 0 {
+		t.Errorf("TLSHandshake = %v, ServerProcessing = %v; want both measured", result.TLSHandshake, result.ServerProcessing)
+	}
+}
+
+func TestHTTP3ResponseTimeout(t *testing.T) {
+	url, opts, transport := startHTTP3Server(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		time.Sleep(time.Second)
+	}), PingOptions{ResponseTimeout: 50 * time.Millisecond})
+
+	result := makeRequest(context.Background(), &http.Client{Transport: transport}, url, opts)
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "response timeout") {
+		t.Errorf("makeRequest() error = %v, want a response timeout", result.Error)
+	}
+}
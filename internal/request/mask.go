@@ -0,0 +1,67 @@
+package request
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Mask replaces secret values in dumps and repro commands.
+const Mask = "****"
+
+// sensitiveWords mark header and query parameter names whose values are
+// masked.
+var sensitiveWords = []string{"auth", "token", "secret", "password", "passwd", "key", "cookie", "session", "signature"}
+
+// IsSensitive reports whether a header or parameter name suggests a secret.
+func IsSensitive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskHeader masks a sensitive header value, keeping an auth scheme such
+// as "Bearer" so it still shows which kind of credential was used.
+func MaskHeader(name, value string) string {
+	if !IsSensitive(name) {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && strings.EqualFold(name, "Authorization") {
+		return scheme + " " + Mask
+	}
+	return Mask
+}
+
+// MaskURL masks sensitive query parameter values and URL passwords.
+func MaskURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	// url.Userinfo would percent-encode the mask, so it is added back below
+	var userinfo string
+	if _, hasPassword := u.User.Password(); hasPassword {
+		userinfo = url.User(u.User.Username()).String() + ":" + Mask + "@"
+		u.User = nil
+	}
+
+	// Edit the raw query so parameter order and encoding are preserved
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		name, _, ok := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); ok && err == nil && IsSensitive(decoded) {
+			params[i] = name + "=" + Mask
+		}
+	}
+	u.RawQuery = strings.Join(params, "&")
+
+	masked := u.String()
+	if userinfo != "" {
+		masked = strings.Replace(masked, "://", "://"+userinfo, 1)
+	}
+	return masked
+}
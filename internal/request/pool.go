@@ -0,0 +1,153 @@
+package request
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PoolOptions tunes the connections of a Pool.
+type PoolOptions struct {
+	MaxIdleConns    int  // Idle connections kept for reuse, across all hosts (0 = 100)
+	MaxConnsPerHost int  // Connections per host, active or idle (0 = unlimited)
+	NoKeepAlive     bool // Open a new connection for every request
+	TLSSessions     int  // TLS sessions cached for resumption (0 = 64, negative = no cache)
+}
+
+// Default pool settings.
+const (
+	DefaultMaxIdleConns = 100
+	DefaultTLSSessions  = 64
+)
+
+// Pool shares transports between requests, so concurrent and successive
+// requests reuse connections and resume TLS sessions instead of paying for
+// a new handshake each time. Requests with different connection settings
+// (--resolve, --http1, ...) get separate transports. A Pool is safe for
+// concurrent use.
+type Pool struct {
+	opts       PoolOptions
+	sessions   tls.ClientSessionCache // nil when disabled
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+	quic       map[string]*http3Transport // HTTP/3 transports
+}
+
+// NewPool creates a pool with the given settings.
+func NewPool(opts PoolOptions) *Pool {
+	if opts.MaxIdleConns == 0 {
+		opts.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if opts.TLSSessions == 0 {
+		opts.TLSSessions = DefaultTLSSessions
+	}
+
+	pool := &Pool{
+		opts:       opts,
+		transports: make(map[string]*http.Transport),
+		quic:       make(map[string]*http3Transport),
+	}
+	if opts.TLSSessions > 0 {
+		pool.sessions = tls.NewLRUClientSessionCache(opts.TLSSessions)
+	}
+	return pool
+}
+
+// KeepAlive reports whether connections of the pool are reused.
+func (p *Pool) KeepAlive() bool {
+	return !p.opts.NoKeepAlive
+}
+
+// roundTripper returns the shared transport for the connection settings
+// of opts: over QUIC for HTTP/3, or else over TCP.
+func (p *Pool) roundTripper(opts PingOptions) (http.RoundTripper, error) {
+	if opts.HTTPVersion == HTTPVersion3 {
+		return p.http3Transport(opts)
+	}
+	transport, err := p.transport(opts)
+	if err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// transport returns the shared TCP transport for the connection settings
+// of opts, creating it on first use.
+func (p *Pool) transport(opts PingOptions) (*http.Transport, error) {
+	key := transportKey(opts)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if transport, ok := p.transports[key]; ok {
+		return transport, nil
+	}
+
+	opts.DisableKeepAlive = !p.KeepAlive()
+	transport, err := newTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	transport.MaxIdleConns = p.opts.MaxIdleConns
+	transport.MaxIdleConnsPerHost = p.opts.MaxIdleConns // Most runs talk to only a few hosts
+	transport.MaxConnsPerHost = p.opts.MaxConnsPerHost
+	if p.sessions != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.ClientSessionCache = p.sessions
+	}
+
+	p.transports[key] = transport
+	return transport, nil
+}
+
+// http3Transport returns the shared HTTP/3 transport for the connection
+// settings of opts, creating it on first use.
+func (p *Pool) http3Transport(opts PingOptions) (*http3Transport, error) {
+	key := transportKey(opts)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if transport, ok := p.quic[key]; ok {
+		return transport, nil
+	}
+
+	opts.DisableKeepAlive = !p.KeepAlive()
+	transport, err := newHTTP3Transport(opts)
+	if err != nil {
+		return nil, err
+	}
+	if p.sessions != nil {
+		transport.tlsConfig.ClientSessionCache = p.sessions
+	}
+
+	p.quic[key] = transport
+	return transport, nil
+}
+
+// CloseIdleConnections closes the idle connections of every transport.
+func (p *Pool) CloseIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, transport := range p.transports {
+		transport.CloseIdleConnections()
+	}
+	for _, transport := range p.quic {
+		transport.CloseIdleConnections()
+	}
+}
+
+// transportKey identifies the options that shape a transport.
+func transportKey(opts PingOptions) string {
+	resolve := make([]string, 0, len(opts.Resolve))
+	for host, address := range opts.Resolve {
+		resolve = append(resolve, host+"="+address)
+	}
+	sort.Strings(resolve)
+	return strings.Join([]string{
+		strings.Join(resolve, ","), opts.DNSServer, opts.LocalAddr, opts.IPVersion, opts.HTTPVersion,
+		opts.ConnectTimeout.String(), opts.ResponseTimeout.String(),
+	}, "|")
+}
@@ -0,0 +1,115 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPoolReusesConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	pool := NewPool(PoolOptions{})
+	defer pool.CloseIdleConnections()
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, Pool: pool}
+
+	first := Ping(server.URL, opts)
+	second := Ping(server.URL, opts)
+	if first.Error != nil || second.Error != nil {
+		t.Fatalf("Ping() errors = %v, %v", first.Error, second.Error)
+	}
+	if first.ConnReused {
+		t.Error("first request reused a connection")
+	}
+	if !second.ConnReused {
+		t.Error("second request opened a new connection, want the pooled one")
+	}
+}
+
+func TestPoolNoKeepAlive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, Pool: NewPool(PoolOptions{NoKeepAlive: true})}
+	Ping(server.URL, opts)
+	if result := Ping(server.URL, opts); result.Error != nil || result.ConnReused {
+		t.Errorf("Ping() = reused %t, error %v; want a new connection", result.ConnReused, result.Error)
+	}
+}
+
+func TestPingDrainsBoundedBody(t *testing.T) {
+	// An endless body: draining it all would never return
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := make([]byte, 32<<10)
+		for {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	done := make(chan Result, 1)
+	go func() { done <- Ping(server.URL, PingOptions{Method: "GET", Timeout: 5 * time.Second}) }()
+	select {
+	case result := <-done:
+		if result.Error != nil {
+			t.Fatalf("Ping() error = %v", result.Error)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Ping() kept draining an endless body")
+	}
+}
+
+func TestPoolTransports(t *testing.T) {
+	pool := NewPool(PoolOptions{MaxIdleConns: 10, MaxConnsPerHost: 4})
+
+	plain, err := pool.transport(PingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := pool.transport(PingOptions{Method: "POST", Timeout: time.Second})
+	if plain != again {
+		t.Error("requests with the same connection settings got different transports")
+	}
+	http1, _ := pool.transport(PingOptions{HTTPVersion: HTTPVersion1})
+	resolved, _ := pool.transport(PingOptions{Resolve: map[string]string{"example.com:443": "127.0.0.1"}})
+	if http1 == plain || resolved == plain || http1 == resolved {
+		t.Error("requests with different connection settings share a transport")
+	}
+
+	if plain.DisableKeepAlives || plain.MaxIdleConns != 10 || plain.MaxConnsPerHost != 4 {
+		t.Errorf("transport = keep-alives disabled %t, max idle %d, max per host %d; want false, 10, 4",
+			plain.DisableKeepAlives, plain.MaxIdleConns, plain.MaxConnsPerHost)
+	}
+	if plain.TLSClientConfig == nil || plain.TLSClientConfig.ClientSessionCache == nil {
+		t.Error("transport has no TLS session cache")
+	}
+	if http1.TLSClientConfig.ClientSessionCache != plain.TLSClientConfig.ClientSessionCache {
+		t.Error("transports don't share the TLS session cache")
+	}
+
+	if _, err := pool.transport(PingOptions{HTTPVersion: "4"}); err == nil {
+		t.Error("transport() accepted an unknown HTTP version")
+	}
+
+	quic, err := pool.roundTripper(PingOptions{HTTPVersion: HTTPVersion3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := pool.roundTripper(PingOptions{HTTPVersion: HTTPVersion3}); again != quic {
+		t.Error("HTTP/3 requests with the same connection settings got different transports")
+	}
+	if h3, ok := quic.(*http3Transport); !ok || h3.tlsConfig.ClientSessionCache != plain.TLSClientConfig.ClientSessionCache {
+		t.Error("HTTP/3 transport doesn't share the TLS session cache")
+	}
+
+	noCache, _ := NewPool(PoolOptions{TLSSessions: -1}).transport(PingOptions{})
+	if noCache.TLSClientConfig != nil && noCache.TLSClientConfig.ClientSessionCache != nil {
+		t.Error("TLSSessions < 0 still caches sessions")
+	}
+}
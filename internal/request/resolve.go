@@ -0,0 +1,118 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/netcheck"
+)
+
+// ParseResolve parses curl-style --resolve overrides ("host:port:address")
+// into a map from "host:port" to the address to connect to instead. The
+// address may be an IPv6 address, with or without brackets.
+func ParseResolve(values []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid resolve %q: expected host:port:address", value)
+		}
+		host, port, address := strings.ToLower(parts[0]), parts[1], strings.Trim(parts[2], "[]")
+
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("invalid resolve %q: port must be a number between 1 and 65535", value)
+		}
+		if net.ParseIP(address) == nil {
+			return nil, fmt.Errorf("invalid resolve %q: %q is not an IP address", value, address)
+		}
+		overrides[net.JoinHostPort(host, port)] = address
+	}
+	return overrides, nil
+}
+
+// InterfaceAddress returns the address to bind to for sending from the
+// named network interface: its first IPv4 address, or else its first
+// global IPv6 address.
+func InterfaceAddress(name string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", fmt.Errorf("interface %s: %w", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", fmt.Errorf("interface %s: %w", name, err)
+	}
+
+	var ipv6 net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			return ip4.String(), nil
+		}
+		if ipv6 == nil && ipNet.IP.IsGlobalUnicast() {
+			ipv6 = ipNet.IP
+		}
+	}
+	if ipv6 != nil {
+		return ipv6.String(), nil
+	}
+	return "", fmt.Errorf("interface %s has no usable address", name)
+}
+
+// dialContext returns a dial function that connects to the addresses in
+// opts.Resolve instead of resolving their hosts, resolves other hosts with
+// opts.DNSServer, binds to opts.LocalAddr and only dials addresses of
+// opts.IPVersion. It returns nil when none is set, keeping the transport's
+// default dialer. TLS still uses the requested host name for SNI and
+// certificate checks, so origins behind a CDN can be tested.
+func dialContext(opts PingOptions) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	if len(opts.Resolve) == 0 && opts.DNSServer == "" && opts.LocalAddr == "" && opts.IPVersion == IPVersionAny &&
+		opts.ConnectTimeout == 0 {
+		return nil, nil
+	}
+
+	var family string // Suffix restricting the network ("tcp" to "tcp4")
+	switch opts.IPVersion {
+	case IPVersionAny:
+	case IPVersion4, IPVersion6:
+		family = opts.IPVersion
+	default:
+		return nil, fmt.Errorf("unknown IP version: %s", opts.IPVersion)
+	}
+
+	// Same settings as http.DefaultTransport's dialer
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Resolver:  netcheck.NewResolver(opts.DNSServer),
+	}
+	if opts.ConnectTimeout > 0 {
+		dialer.Timeout = opts.ConnectTimeout
+	}
+	if opts.LocalAddr != "" {
+		ip := net.ParseIP(opts.LocalAddr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid local address %q: not an IP address", opts.LocalAddr)
+		}
+		// Only addresses of the same family are dialed
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if network == "tcp" {
+			network += family
+		}
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if address, ok := opts.Resolve[net.JoinHostPort(strings.ToLower(host), port)]; ok {
+				addr = net.JoinHostPort(address, port)
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}, nil
+}
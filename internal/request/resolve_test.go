@@ -0,0 +1,144 @@
+package request
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseResolve(t *testing.T) {
+	got, err := ParseResolve([]string{"api.example.com:443:203.0.113.7", "API.example.com:80:[2001:db8::1]", "v6.example.com:8443:2001:db8::2"})
+	if err != nil {
+		t.Fatalf("ParseResolve() error = %v", err)
+	}
+	want := map[string]string{
+		"api.example.com:443": "203.0.113.7",
+		"api.example.com:80":  "2001:db8::1",
+		"v6.example.com:8443": "2001:db8::2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseResolve() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{"api.example.com:203.0.113.7", "expected host:port:address"},
+		{"api.example.com:https:203.0.113.7", "port must be a number"},
+		{"api.example.com", "expected host:port:address"},
+		{":443:203.0.113.7", "expected host:port:address"},
+		{"api.example.com:0:203.0.113.7", "port must be a number"},
+		{"api.example.com:443:origin.example.com", "is not an IP address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := ParseResolve([]string{tt.value})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseResolve() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPingResolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	resolve, err := ParseResolve([]string{"origin.tapr.invalid:" + port + ":127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, ReadBody: true, Resolve: resolve}
+	result := Ping("http://origin.tapr.invalid:"+port+"/", opts)
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if want := "origin.tapr.invalid:" + port; string(result.Body) != want {
+		t.Errorf("Host header = %q, want %q", result.Body, want)
+	}
+
+	// Other ports of the host are not overridden
+	opts.Timeout = time.Second
+	if result := Ping("http://origin.tapr.invalid:1/", opts); result.Error == nil {
+		t.Error("Ping() to a port without an override succeeded, want a DNS error")
+	}
+}
+
+func TestPingLocalAddr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		w.Write([]byte(host))
+	}))
+	defer server.Close()
+
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, ReadBody: true, LocalAddr: "127.0.0.1"}
+	result := Ping(server.URL, opts)
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if string(result.Body) != "127.0.0.1" {
+		t.Errorf("source address = %s, want 127.0.0.1", result.Body)
+	}
+
+	opts.LocalAddr = "eth0"
+	if result := Ping(server.URL, opts); result.Error == nil || !strings.Contains(result.Error.Error(), "not an IP address") {
+		t.Errorf("Ping() error = %v, want an invalid local address", result.Error)
+	}
+}
+
+func TestInterfaceAddress(t *testing.T) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		addr, err := InterfaceAddress(iface.Name)
+		if err != nil {
+			t.Fatalf("InterfaceAddress(%s) error = %v", iface.Name, err)
+		}
+		if addr != "127.0.0.1" {
+			t.Errorf("InterfaceAddress(%s) = %s, want 127.0.0.1", iface.Name, addr)
+		}
+		break
+	}
+
+	if _, err := InterfaceAddress("tapr-missing0"); err == nil {
+		t.Error("InterfaceAddress() of a missing interface succeeded")
+	}
+}
+
+func TestPingIPVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	url := "http://localhost:" + port + "/"
+
+	// The test server only listens on 127.0.0.1
+	result := Ping(url, PingOptions{Method: "GET", Timeout: 5 * time.Second, IPVersion: IPVersion4})
+	if result.Error != nil {
+		t.Fatalf("Ping() over IPv4 error = %v", result.Error)
+	}
+	if result.RemoteAddr != "127.0.0.1:"+port {
+		t.Errorf("RemoteAddr = %s, want 127.0.0.1:%s", result.RemoteAddr, port)
+	}
+
+	if result := Ping(url, PingOptions{Method: "GET", Timeout: 5 * time.Second, IPVersion: IPVersion6}); result.Error == nil {
+		t.Errorf("Ping() over IPv6 connected to %s, want an error", result.RemoteAddr)
+	}
+
+	if result := Ping(url, PingOptions{Method: "GET", IPVersion: "5"}); result.Error == nil || !strings.Contains(result.Error.Error(), "unknown IP version") {
+		t.Errorf("Ping() error = %v, want unknown IP version", result.Error)
+	}
+}
@@ -0,0 +1,70 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryMaxDelay caps the wait between retries unless
+// PingOptions.RetryMaxDelay is set.
+const DefaultRetryMaxDelay = 30 * time.Second
+
+// Backoff returns how long to wait before the given retry (1 for the first
+// retry): 1s, 2s, 4s, 8s...
+func Backoff(retry int) time.Duration {
+	return time.Duration(1<<uint(min(retry-1, 30))) * time.Second
+}
+
+// RetryDelay returns how long to wait before the given retry of a request
+// that got result: the server's Retry-After if it sent one (429 and 503
+// responses usually do), otherwise the exponential Backoff. The delay never
+// exceeds limit (0 = DefaultRetryMaxDelay).
+func RetryDelay(retry int, result Result, limit time.Duration) time.Duration {
+	if limit <= 0 {
+		limit = DefaultRetryMaxDelay
+	}
+	delay := Backoff(retry)
+	if after, ok := RetryAfter(result.Headers, time.Now()); ok {
+		delay = after
+	}
+	return min(delay, limit)
+}
+
+// RetryAfter parses the Retry-After header, given either in seconds or as
+// an HTTP date, into how long to wait from now.
+func RetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// shouldRetry reports whether a request that got result is worth another
+// attempt: it failed without a response, or its status is one of statuses.
+func shouldRetry(result Result, statuses []int) bool {
+	return result.Error != nil || slices.Contains(statuses, result.StatusCode)
+}
+
+// Sleep waits for d, or until ctx is done, in which case it returns the
+// context's error. Retries wait with it so Ctrl+C or a deadline doesn't
+// have to sit out a long backoff.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"0", 0, true},
+		{"-5", 0, true},
+		{"Wed, 01 May 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 May 2024 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, ok := RetryAfter(header, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("RetryAfter(%q) = %v, %t; want %v, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if got := RetryDelay(3, Result{}, 0); got != 4*time.Second {
+		t.Errorf("RetryDelay(3) = %v, want the 4s backoff", got)
+	}
+	if got := RetryDelay(10, Result{}, 0); got != DefaultRetryMaxDelay {
+		t.Errorf("RetryDelay(10) = %v, want it capped at %v", got, DefaultRetryMaxDelay)
+	}
+
+	throttled := Result{StatusCode: 429, Headers: http.Header{"Retry-After": {"7"}}}
+	if got := RetryDelay(1, throttled, 0); got != 7*time.Second {
+		t.Errorf("RetryDelay() = %v, want Retry-After's 7s", got)
+	}
+	if got := RetryDelay(1, throttled, 5*time.Second); got != 5*time.Second {
+		t.Errorf("RetryDelay() = %v, want Retry-After capped at 5s", got)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	statuses := []int{429, 503}
+	if !shouldRetry(Result{Error: errors.New("refused")}, nil) {
+		t.Error("request errors should be retried")
+	}
+	if !shouldRetry(Result{StatusCode: 503}, statuses) {
+		t.Error("503 should be retried when listed")
+	}
+	if shouldRetry(Result{StatusCode: 503}, nil) || shouldRetry(Result{StatusCode: 500}, statuses) {
+		t.Error("unlisted statuses should not be retried")
+	}
+}
+
+func TestPingRetriesOnStatus(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, Retries: 3, RetryOnStatus: []int{429}}
+	start := time.Now()
+	result := Ping(server.URL, opts)
+	if result.StatusCode != http.StatusOK || requests.Load() != 3 {
+		t.Errorf("Ping() = %d after %d requests, want 200 after 3", result.StatusCode, requests.Load())
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Ping() took %v, want Retry-After: 0 honored", time.Since(start))
+	}
+
+	// Without RetryOnStatus a 429 is a response like any other
+	requests.Store(0)
+	opts.RetryOnStatus = nil
+	if result := Ping(server.URL, opts); result.StatusCode != http.StatusTooManyRequests || requests.Load() != 1 {
+		t.Errorf("Ping() = %d after %d requests, want 429 after 1", result.StatusCode, requests.Load())
+	}
+}
+
+func TestSleep(t *testing.T) {
+	if err := Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Sleep() = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := Sleep(ctx, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Sleep() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Sleep() took %v, want it cut short", time.Since(start))
+	}
+}
+
+func TestPingContextInterruptsBackoff(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	opts := PingOptions{Method: "GET", Timeout: 5 * time.Second, Retries: 3, RetryOnStatus: []int{503}}
+	start := time.Now()
+	result := PingContext(ctx, server.URL, opts)
+	if time.Since(start) > time.Second {
+		t.Errorf("PingContext() took %v, want the wait canceled", time.Since(start))
+	}
+	if result.StatusCode != http.StatusServiceUnavailable || requests.Load() != 1 {
+		t.Errorf("PingContext() = %d after %d requests, want 503 after 1", result.StatusCode, requests.Load())
+	}
+}
@@ -0,0 +1,186 @@
+package request
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// SSEEvent is one event received from a Server-Sent Events stream.
+type SSEEvent struct {
+	ID    string        // Last event ID ("id:" field), if any
+	Type  string        // Event type ("event:" field), "message" by default
+	Data  string        // Data lines joined with newlines
+	After time.Duration // Time from request start until the event arrived
+}
+
+// SSEResult is the outcome of a Server-Sent Events check. The embedded
+// Result describes the response; its Latency is the time until the
+// response headers arrived.
+type SSEResult struct {
+	Result
+	FirstEvent time.Duration // Time from request start until the first event (0 if none)
+	Events     []SSEEvent    // Events received, up to the number requested
+}
+
+// CheckSSE connects to an event-stream endpoint and waits until it has
+// received the given number of events. opts.Timeout bounds the whole check,
+// so a stream that accepts the connection but never sends an event fails
+// with a timeout. Comments (keep-alive heartbeats) don't count as events,
+// but do keep the stream from exceeding opts.IdleTimeout.
+func CheckSSE(url string, opts PingOptions, events int) SSEResult {
+	client, err := NewClient(opts)
+	if err != nil {
+		return SSEResult{Result: Result{URL: url, Error: err}}
+	}
+	client.Timeout = 0 // The context deadline covers reading the stream
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+	ctx, watchdog := newWatchdog(ctx)
+	defer watchdog.stop()
+	watchdog.arm(opts.FirstByteTimeout, firstByteTimeoutError(opts.FirstByteTimeout))
+
+	start := time.Now()
+	req, err := newRequest(url, opts)
+	if err != nil {
+		return SSEResult{Result: Result{URL: url, Error: err}}
+	}
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	if req.Header.Get("Cache-Control") == "" {
+		req.Header.Set("Cache-Control", "no-cache")
+	}
+
+	var ttfb time.Duration
+	trace := &httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			ttfb = time.Since(start)
+			watchdog.disarm()
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if expired := watchdog.expired(); expired != nil {
+			err = expired
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			err = timeoutError(fmt.Sprintf("no response within %s", opts.Timeout))
+		}
+		return SSEResult{Result: Result{URL: url, Latency: time.Since(start), Error: err}}
+	}
+	defer resp.Body.Close()
+
+	result := SSEResult{Result: Result{
+		URL:        url,
+		FinalURL:   resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Latency:    time.Since(start),
+		TTFB:       ttfb,
+		Size:       -1,
+		Protocol:   resp.Proto,
+		Headers:    resp.Header,
+	}}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.Error = fmt.Errorf("server returned %s", resp.Status)
+		return result
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		result.Error = fmt.Errorf("expected Content-Type text/event-stream, got %q", resp.Header.Get("Content-Type"))
+		return result
+	}
+
+	var body io.Reader = resp.Body
+	if opts.IdleTimeout > 0 {
+		body = newIdleReader(resp.Body, watchdog, opts.IdleTimeout)
+	}
+	err = readEvents(body, func(event SSEEvent) bool {
+		event.After = time.Since(start)
+		if len(result.Events) == 0 {
+			result.FirstEvent = event.After
+		}
+		result.Events = append(result.Events, event)
+		return len(result.Events) < events
+	})
+
+	switch {
+	case len(result.Events) >= events:
+		return result
+	case watchdog.expired() != nil:
+		result.Error = watchdog.expired()
+	case ctx.Err() != nil:
+		if len(result.Events) == 0 {
+			result.Error = timeoutError(fmt.Sprintf("no event within %s", opts.Timeout))
+		} else {
+			result.Error = timeoutError(fmt.Sprintf("received %d of %d events within %s", len(result.Events), events, opts.Timeout))
+		}
+	case err != nil:
+		result.Error = fmt.Errorf("failed to read event stream: %w", err)
+	default:
+		result.Error = fmt.Errorf("stream closed after %d of %d events", len(result.Events), events)
+	}
+	return result
+}
+
+// readEvents parses an event stream, calling handle for every event until
+// handle returns false or the stream ends. It returns nil at the end of the
+// stream.
+func readEvents(r io.Reader, handle func(SSEEvent) bool) error {
+	reader := bufio.NewReader(r)
+	var (
+		event   SSEEvent
+		data    []string
+		hasData bool
+	)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			if err == io.EOF {
+				return nil // An event without a final blank line is discarded
+			}
+			return err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		// A blank line dispatches the event, if it has data
+		if line == "" {
+			if hasData {
+				event.Data = strings.Join(data, "\n")
+				if event.Type == "" {
+					event.Type = "message"
+				}
+				if !handle(event) {
+					return nil
+				}
+			}
+			event, data, hasData = SSEEvent{ID: event.ID}, nil, false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment, often sent as a heartbeat
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "event":
+			event.Type = value
+		case "id":
+			event.ID = value
+		}
+	}
+}
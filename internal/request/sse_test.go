@@ -0,0 +1,125 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadEvents(t *testing.T) {
+	stream := ": connected\n\n" +
+		"data: first\n\n" +
+		"event: price\r\nid: 7\r\ndata: {\"a\": 1}\r\ndata: {\"b\": 2}\r\n\r\n" +
+		"retry: 1000\n\n" +
+		"data\n\n" +
+		"data: no blank line"
+
+	var got []SSEEvent
+	err := readEvents(strings.NewReader(stream), func(event SSEEvent) bool {
+		got = append(got, event)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("readEvents() error = %v", err)
+	}
+
+	want := []SSEEvent{
+		{Type: "message", Data: "first"},
+		{ID: "7", Type: "price", Data: "{\"a\": 1}\n{\"b\": 2}"},
+		{ID: "7", Type: "message", Data: ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readEvents() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckSSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Write([]byte(`{}`))
+			return
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		switch r.URL.Path {
+		case "/events":
+			for i := 1; i <= 3; i++ {
+				fmt.Fprintf(w, "id: %d\ndata: tick %d\n\n", i, i)
+				w.(http.Flusher).Flush()
+			}
+			<-r.Context().Done()
+		case "/heartbeat":
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(20 * time.Millisecond):
+					fmt.Fprint(w, ": ping\n\n")
+					w.(http.Flusher).Flush()
+				}
+			}
+		case "/short":
+			fmt.Fprint(w, "data: only one\n\n")
+		}
+	}))
+	defer server.Close()
+
+	opts := PingOptions{Method: "GET", Timeout: 300 * time.Millisecond}
+
+	result := CheckSSE(server.URL+"/events", opts, 2)
+	if result.Error != nil {
+		t.Fatalf("CheckSSE() error = %v", result.Error)
+	}
+	if len(result.Events) != 2 || result.Events[1].Data != "tick 2" {
+		t.Errorf("Events = %+v, want the first two ticks", result.Events)
+	}
+	if result.FirstEvent <= 0 || result.FirstEvent != result.Events[0].After {
+		t.Errorf("FirstEvent = %v, want the arrival of the first event", result.FirstEvent)
+	}
+
+	// Heartbeats keep a stream within its idle timeout, silence doesn't
+	idle := opts
+	idle.IdleTimeout = 100 * time.Millisecond
+	if result := CheckSSE(server.URL+"/events", idle, 5); result.Error == nil || !strings.Contains(result.Error.Error(), "idle timeout") {
+		t.Errorf("CheckSSE() error = %v, want an idle timeout", result.Error)
+	}
+	if result := CheckSSE(server.URL+"/heartbeat", idle, 1); result.Error == nil || !strings.Contains(result.Error.Error(), "no event within") {
+		t.Errorf("CheckSSE() error = %v, want no event within the timeout", result.Error)
+	}
+
+	tests := []struct {
+		path        string
+		events      int
+		wantErr     string
+		wantTimeout bool
+	}{
+		{"/heartbeat", 1, "no event within 300ms", true},
+		{"/events", 5, "received 3 of 5 events within 300ms", true},
+		{"/short", 2, "stream closed after 1 of 2 events", false},
+		{"/json", 1, `expected Content-Type text/event-stream, got "text/plain; charset=utf-8"`, false},
+		{"/missing", 1, "server returned 404 Not Found", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			result := CheckSSE(server.URL+tt.path, opts, tt.events)
+			if result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantErr) {
+				t.Fatalf("CheckSSE() error = %v, want containing %q", result.Error, tt.wantErr)
+			}
+			if timedOut := ClassifyError(result.Error) == CategoryTimeout; timedOut != tt.wantTimeout {
+				t.Errorf("classified as timeout = %v, want %v", timedOut, tt.wantTimeout)
+			}
+		})
+	}
+}
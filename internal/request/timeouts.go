@@ -0,0 +1,147 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeoutError reports a request or stream that didn't deliver in time. It
+// is a net.Error timeout, so Classify reports CategoryTimeout.
+type timeoutError string
+
+func (e timeoutError) Error() string   { return string(e) }
+func (e timeoutError) Timeout() bool   { return true }
+func (e timeoutError) Temporary() bool { return false }
+
+// watchdog cancels a request when one phase of it takes too long: the wait
+// for the first response byte (PingOptions.FirstByteTimeout) or a pause
+// while reading the body (PingOptions.IdleTimeout). The overall
+// PingOptions.Timeout is enforced separately.
+type watchdog struct {
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	timer  *time.Timer
+	reason error // Error to report if the armed timer fires
+	err    error // Set once the timer fired
+}
+
+// newWatchdog returns a watchdog and the context to send the request with.
+func newWatchdog(parent context.Context) (context.Context, *watchdog) {
+	ctx, cancel := context.WithCancel(parent)
+	return ctx, &watchdog{cancel: cancel}
+}
+
+// arm (re)starts the timer: unless disarmed or re-armed within d, the
+// request is cancelled and reason becomes its error. d <= 0 disarms.
+func (w *watchdog) arm(d time.Duration, reason error) {
+	if d <= 0 {
+		w.disarm()
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reason = reason
+	if w.timer == nil {
+		w.timer = time.AfterFunc(d, w.fire)
+	} else {
+		w.timer.Reset(d)
+	}
+}
+
+// disarm stops the timer.
+func (w *watchdog) disarm() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+func (w *watchdog) fire() {
+	w.mu.Lock()
+	w.err = w.reason
+	w.mu.Unlock()
+	w.cancel()
+}
+
+// expired returns the reason the request was cancelled, or nil.
+func (w *watchdog) expired() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// stop disarms the watchdog and releases its context.
+func (w *watchdog) stop() {
+	w.disarm()
+	w.cancel()
+}
+
+// idleReader re-arms a watchdog whenever body data arrives, so reading
+// fails once the server pauses for longer than idle.
+type idleReader struct {
+	r        io.Reader
+	watchdog *watchdog
+	idle     time.Duration
+	reason   error
+}
+
+// newIdleReader arms the watchdog and returns a reader of r that keeps it
+// armed.
+func newIdleReader(r io.Reader, watchdog *watchdog, idle time.Duration) *idleReader {
+	reason := idleTimeoutError(idle)
+	watchdog.arm(idle, reason)
+	return &idleReader{r: r, watchdog: watchdog, idle: idle, reason: reason}
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil {
+		if expired := r.watchdog.expired(); expired != nil {
+			return n, expired
+		}
+		r.watchdog.disarm()
+		return n, err
+	}
+	r.watchdog.arm(r.idle, r.reason)
+	return n, nil
+}
+
+// phaseTimeout replaces the error of a request that ran out of its
+// connect or response timeout with one naming the timeout, so a slow
+// connect can be told from a slow server. Other errors are returned as is.
+func phaseTimeout(err error, opts PingOptions) error {
+	var opErr *net.OpError
+	switch {
+	case opts.ConnectTimeout > 0 && errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return connectTimeoutError(opts.ConnectTimeout)
+	case opts.ConnectTimeout > 0 && strings.Contains(err.Error(), "TLS handshake timeout"):
+		return connectTimeoutError(opts.ConnectTimeout)
+	case opts.ResponseTimeout > 0 && strings.Contains(err.Error(), "timeout awaiting response headers"):
+		return responseTimeoutError(opts.ResponseTimeout)
+	}
+	return err
+}
+
+func connectTimeoutError(d time.Duration) error {
+	return timeoutError("could not connect within " + d.String() + " (connect timeout)")
+}
+
+func responseTimeoutError(d time.Duration) error {
+	return timeoutError("no response headers within " + d.String() + " of sending the request (response timeout)")
+}
+
+func firstByteTimeoutError(d time.Duration) error {
+	return timeoutError("no response within " + d.String() + " (first-byte timeout)")
+}
+
+func idleTimeoutError(d time.Duration) error {
+	return timeoutError("no data for " + d.String() + " while reading the response (idle timeout)")
+}
@@ -0,0 +1,178 @@
+package request
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPingStreamTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow-start":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte("late"))
+		case "/stream":
+			// Sends a chunk every 50ms for 300ms in total
+			for i := 0; i < 6; i++ {
+				w.Write([]byte("chunk\n"))
+				w.(http.Flusher).Flush()
+				time.Sleep(50 * time.Millisecond)
+			}
+		case "/stall":
+			w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    PingOptions
+		wantErr string
+	}{
+		{"first byte late", "/slow-start", PingOptions{FirstByteTimeout: 50 * time.Millisecond}, "no response within 50ms (first-byte timeout)"},
+		{"first byte in time", "/slow-start", PingOptions{FirstByteTimeout: time.Second}, ""},
+		{"streaming within idle timeout", "/stream", PingOptions{IdleTimeout: 150 * time.Millisecond}, ""},
+		{"stalled stream", "/stall", PingOptions{IdleTimeout: 100 * time.Millisecond}, "no data for 100ms while reading the response (idle timeout)"},
+		{"response late", "/slow-start", PingOptions{ResponseTimeout: 50 * time.Millisecond}, "no response headers within 50ms of sending the request (response timeout)"},
+		{"response in time", "/slow-start", PingOptions{ResponseTimeout: time.Second}, ""},
+		{"total timeout still applies", "/stream", PingOptions{Timeout: 100 * time.Millisecond, IdleTimeout: time.Second}, "Client.Timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Method = "GET"
+			opts.ReadBody = true
+
+			result := Ping(server.URL+tt.path, opts)
+			if tt.wantErr == "" {
+				if result.Error != nil {
+					t.Fatalf("Ping() error = %v", result.Error)
+				}
+				return
+			}
+			if result.Error == nil || !strings.Contains(result.Error.Error(), tt.wantErr) {
+				t.Fatalf("Ping() error = %v, want containing %q", result.Error, tt.wantErr)
+			}
+			if category := ClassifyError(result.Error); category != CategoryTimeout {
+				t.Errorf("ClassifyError() = %s, want %s", category, CategoryTimeout)
+			}
+		})
+	}
+}
+
+func TestPhaseTimeout(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError("i/o timeout")}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	tlsErr := errors.New("net/http: TLS handshake timeout")
+	opts := PingOptions{ConnectTimeout: 2 * time.Second}
+
+	tests := []struct {
+		name string
+		err  error
+		opts PingOptions
+		want string
+	}{
+		{"dial timeout", dialErr, opts, "could not connect within 2s (connect timeout)"},
+		{"TLS handshake timeout", tlsErr, opts, "could not connect within 2s (connect timeout)"},
+		{"connection refused", refused, opts, refused.Error()},
+		{"no connect timeout set", dialErr, PingOptions{}, dialErr.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := phaseTimeout(tt.err, tt.opts); got.Error() != tt.want {
+				t.Errorf("phaseTimeout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// slowDNS starts a DNS server that answers A queries with 127.0.0.1 after
+// delay, and AAAA queries with no records, and returns its address.
+func slowDNS(t *testing.T, delay time.Duration) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			query := append([]byte(nil), buf[:n]...)
+			go func() {
+				time.Sleep(delay)
+				// The question: a name of length-prefixed labels, type and class
+				end := 12
+				for end < len(query) && query[end] != 0 {
+					end += int(query[end]) + 1
+				}
+				if end+5 > len(query) {
+					return
+				}
+				question := query[12 : end+5]
+
+				// Header: same ID, response flags, one question
+				reply := append([]byte{query[0], query[1], 0x81, 0x80, 0, 1, 0, 0, 0, 0, 0, 0}, question...)
+				if qtype := query[end+1 : end+3]; qtype[0] == 0 && qtype[1] == 1 {
+					reply[7] = 1 // One answer: a pointer to the question name, A, IN, TTL 60
+					reply = append(reply, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+				}
+				conn.WriteTo(reply, addr)
+			}()
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestPingConnectTimeoutCoversDNSAndTLS(t *testing.T) {
+	// Accepts connections but never answers the TLS handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	// DNS takes 150ms of the 250ms, leaving 100ms for the handshake
+	opts := PingOptions{
+		Method:         "GET",
+		ConnectTimeout: 250 * time.Millisecond,
+		DNSServer:      slowDNS(t, 150*time.Millisecond),
+	}
+	start := time.Now()
+	result := Ping("https://stall.test:"+port+"/", opts)
+	elapsed := time.Since(start)
+
+	if result.Error == nil || result.Error.Error() != "could not connect within 250ms (connect timeout)" {
+		t.Fatalf("Ping() error = %v, want the connect timeout", result.Error)
+	}
+	if elapsed > 350*time.Millisecond {
+		t.Errorf("Ping() took %v, want the connect timeout to cover DNS, TCP and TLS together", elapsed)
+	}
+}
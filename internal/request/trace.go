@@ -27,8 +27,10 @@ type TraceResult struct {
 	StatusCode int    // HTTP status code
 	Status     string // HTTP status text
 	Protocol   string // HTTP protocol version
+	ALPN       string // Protocol negotiated via TLS ALPN (HTTPS only)
 	RemoteAddr string // Server IP address
 	Size       int64  // Response size
+	Reused     bool   // Whether an idle keep-alive connection was reused
 
 	Error error // Any error that occurred
 }
@@ -36,6 +38,65 @@ type TraceResult struct {
 // TraceRequest performs an HTTP request with detailed timing information.
 // It uses Go's httptrace package to capture timing at each phase.
 func TraceRequest(url, method string, opts PingOptions) TraceResult {
+	transport := &http.Transport{
+		// CRITICAL: Disable connection pooling to force fresh connections
+		DisableKeepAlives: true,
+		// Disable compression to get accurate transfer times
+		DisableCompression: false,
+		// Force new connection for each request
+		MaxIdleConns:        0,
+		MaxIdleConnsPerHost: 0,
+		IdleConnTimeout:     0,
+	}
+
+	// Restrict to a single protocol version if requested. HTTP/3 runs
+	// over QUIC, on a fresh connection too.
+	var roundTripper http.RoundTripper = transport
+	if opts.HTTPVersion == HTTPVersion3 {
+		quic, err := newHTTP3Transport(PingOptions{DisableKeepAlive: true})
+		if err != nil {
+			return TraceResult{URL: url, Error: err}
+		}
+		roundTripper = quic
+	} else if err := configureProtocol(transport, opts.HTTPVersion); err != nil {
+		return TraceResult{URL: url, Error: err}
+	}
+
+	// Create HTTP client with tracing and disabled keep-alives
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: roundTripper,
+		Jar:       opts.Jar,
+	}
+
+	opts.Method = method
+	return traceWithClient(client, url, opts, true)
+}
+
+// TraceReuse traces two requests over one keep-alive client: the first
+// opens a fresh connection (cold) and the second reuses it (warm). The
+// difference between them is the cost of DNS, TCP and TLS setup.
+func TraceReuse(url, method string, opts PingOptions) (cold, warm TraceResult) {
+	opts.Method = method
+	opts.DisableKeepAlive = false
+
+	client, err := NewClient(opts)
+	if err != nil {
+		return TraceResult{URL: url, Error: err}, TraceResult{URL: url, Error: err}
+	}
+	defer client.CloseIdleConnections()
+
+	cold = traceWithClient(client, url, opts, true)
+	if cold.Error != nil {
+		return cold, TraceResult{URL: url, Error: cold.Error}
+	}
+	return cold, traceWithClient(client, url, opts, false)
+}
+
+// traceWithClient traces a single request made with client. When fresh is
+// set, idle connections left by a Digest challenge are closed first so the
+// traced request pays for its own connection setup.
+func traceWithClient(client *http.Client, url string, opts PingOptions, fresh bool) TraceResult {
 	result := TraceResult{
 		URL: url,
 	}
@@ -89,8 +150,9 @@ func TraceRequest(url, method string, opts PingOptions) TraceResult {
 		},
 
 		// Connection obtained (reused or new)
-		GotConn: func(_ httptrace.GotConnInfo) {
+		GotConn: func(info httptrace.GotConnInfo) {
 			gotConn = time.Now()
+			result.Reused = info.Reused
 		},
 
 		// First byte of response received
@@ -99,31 +161,33 @@ func TraceRequest(url, method string, opts PingOptions) TraceResult {
 		},
 	}
 
-	// Create HTTP client with tracing and disabled keep-alives
-	client := &http.Client{
-		Timeout: opts.Timeout,
-		Transport: &http.Transport{
-			// CRITICAL: Disable connection pooling to force fresh connections
-			DisableKeepAlives: true,
-			// Disable compression to get accurate transfer times
-			DisableCompression: false,
-			// Force new connection for each request
-			MaxIdleConns:        0,
-			MaxIdleConnsPerHost: 0,
-			IdleConnTimeout:     0,
-		},
-	}
-
 	// Create request with trace context
-	req, err := http.NewRequest(method, url, nil)
+	req, err := newRequest(url, opts)
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
-	// Add headers
-	for key, value := range opts.Headers {
-		req.Header.Set(key, value)
+	// Fetch the Digest challenge up front so only the authenticated request is traced
+	if opts.Auth != nil && opts.Auth.Digest {
+		authorization, err := digestAuthorization(client, req, opts.Auth)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		if err := rewindBody(req); err != nil {
+			result.Error = err
+			return result
+		}
+		if fresh {
+			client.CloseIdleConnections()
+		}
+
+		// The challenge round-trip is not part of the traced request
+		overallStart = time.Now()
 	}
 
 	// Attach trace to request context
@@ -140,6 +204,14 @@ func TraceRequest(url, method string, opts PingOptions) TraceResult {
 	}
 	defer resp.Body.Close()
 
+	// Make sure a forced protocol version was actually used
+	if err := checkProtocol(resp, opts.HTTPVersion); err != nil {
+		result.Error = err
+		result.Protocol = resp.Proto
+		result.TotalTime = overallEnd.Sub(overallStart)
+		return result
+	}
+
 	// Read the entire body to complete content transfer timing
 	_, _ = io.ReadAll(resp.Body)
 	transferEnd := time.Now()
@@ -164,6 +236,9 @@ func TraceRequest(url, method string, opts PingOptions) TraceResult {
 	result.Status = resp.Status
 	result.Protocol = resp.Proto
 	result.Size = resp.ContentLength
+	if resp.TLS != nil {
+		result.ALPN = resp.TLS.NegotiatedProtocol
+	}
 
 	// Get remote address if available
 	if resp.Request != nil && resp.Request.RemoteAddr != "" {
@@ -0,0 +1,96 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTraceReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	cold, warm := TraceReuse(server.URL, "GET", PingOptions{Timeout: 5 * time.Second})
+
+	if cold.Error != nil || warm.Error != nil {
+		t.Fatalf("TraceReuse() errors = %v, %v", cold.Error, warm.Error)
+	}
+	if cold.Reused || cold.TCPConnection == 0 {
+		t.Errorf("cold request should open a new connection (reused=%v, tcp=%v)", cold.Reused, cold.TCPConnection)
+	}
+	if !warm.Reused || warm.TCPConnection != 0 {
+		t.Errorf("warm request should reuse the connection (reused=%v, tcp=%v)", warm.Reused, warm.TCPConnection)
+	}
+	if warm.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", warm.StatusCode)
+	}
+}
+
+func TestTraceRequest_FreshConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		result := TraceRequest(server.URL, "GET", PingOptions{Timeout: 5 * time.Second})
+		if result.Error != nil {
+			t.Fatalf("TraceRequest() error = %v", result.Error)
+		}
+		if result.Reused {
+			t.Error("TraceRequest() should never reuse a connection")
+		}
+	}
+}
+
+func TestPingTTFB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond) // Server processing
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result := Ping(server.URL, PingOptions{Method: "GET", Timeout: 5 * time.Second})
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if result.TTFB < 50*time.Millisecond {
+		t.Errorf("TTFB = %v, want at least the 50ms of server processing", result.TTFB)
+	}
+	if result.TTFB > result.Latency {
+		t.Errorf("TTFB = %v exceeds latency %v", result.TTFB, result.Latency)
+	}
+}
+
+func TestPingDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing forces a chunked response with no Content-Length
+		for i := 0; i < 4; i++ {
+			w.Write(make([]byte, 1000))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	result := Ping(server.URL, PingOptions{Method: "GET", Timeout: 5 * time.Second})
+	if result.Size != -1 {
+		t.Fatalf("Size without Download = %d, want -1 for a chunked response", result.Size)
+	}
+	if result.Throughput() != 0 {
+		t.Errorf("Throughput() without Download = %v, want 0", result.Throughput())
+	}
+
+	result = Ping(server.URL, PingOptions{Method: "GET", Timeout: 5 * time.Second, Download: true, ReadBody: true})
+	if result.Error != nil {
+		t.Fatalf("Ping() error = %v", result.Error)
+	}
+	if result.Size != 4000 || len(result.Body) != 4000 {
+		t.Errorf("Size = %d, len(Body) = %d, want 4000", result.Size, len(result.Body))
+	}
+	if result.Download <= 0 || result.Throughput() <= 0 {
+		t.Errorf("Download = %v, Throughput() = %v, want both measured", result.Download, result.Throughput())
+	}
+}
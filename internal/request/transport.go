@@ -0,0 +1,109 @@
+package request
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTP protocol versions that can be forced via PingOptions.HTTPVersion.
+const (
+	HTTPVersionAuto = ""    // Negotiate normally (HTTP/2 via ALPN when available)
+	HTTPVersion1    = "1.1" // Force HTTP/1.1
+	HTTPVersion2    = "2"   // Force HTTP/2 (fails if the server doesn't negotiate it)
+	HTTPVersion3    = "3"   // Force HTTP/3 over QUIC
+)
+
+// IP versions that can be forced via PingOptions.IPVersion.
+const (
+	IPVersionAny = ""  // Use whatever the host resolves to
+	IPVersion4   = "4" // Connect over IPv4 only
+	IPVersion6   = "6" // Connect over IPv6 only
+)
+
+// newRoundTripper returns a new transport for the given options: over
+// QUIC for HTTP/3, or else over TCP.
+func newRoundTripper(opts PingOptions) (http.RoundTripper, error) {
+	if opts.HTTPVersion == HTTPVersion3 {
+		return newHTTP3Transport(opts)
+	}
+	return newTransport(opts)
+}
+
+// newTransport returns a new transport configured for the given options.
+// Keep-alives are on unless opts.DisableKeepAlive is set, which makes each
+// request measure a cold connection.
+func newTransport(opts PingOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = opts.DisableKeepAlive
+	if opts.ConnectTimeout > 0 {
+		transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	}
+	transport.ResponseHeaderTimeout = opts.ResponseTimeout
+	dial, err := dialContext(opts)
+	if err != nil {
+		return nil, err
+	}
+	if dial != nil {
+		transport.DialContext = dial
+	}
+
+	if err := configureProtocol(transport, opts.HTTPVersion); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// configureProtocol restricts a transport to a single HTTP protocol version.
+// HTTP/3 runs over QUIC and needs an http3Transport instead.
+func configureProtocol(transport *http.Transport, version string) error {
+	switch version {
+	case HTTPVersionAuto:
+		return nil
+	case HTTPVersion1:
+		// A non-nil, empty TLSNextProto map disables HTTP/2
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		transport.TLSClientConfig = tlsConfigWithALPN(transport.TLSClientConfig, "http/1.1")
+		return nil
+	case HTTPVersion2:
+		transport.ForceAttemptHTTP2 = true
+		transport.TLSClientConfig = tlsConfigWithALPN(transport.TLSClientConfig, "h2")
+		return nil
+	case HTTPVersion3:
+		return errors.New("HTTP/3 needs a QUIC transport")
+	default:
+		return fmt.Errorf("unknown HTTP version: %s", version)
+	}
+}
+
+// tlsConfigWithALPN returns a copy of cfg that only offers the given ALPN protocol.
+func tlsConfigWithALPN(cfg *tls.Config, proto string) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	} else {
+		cfg = cfg.Clone()
+	}
+	cfg.NextProtos = []string{proto}
+	return cfg
+}
+
+// checkProtocol verifies that the response used the forced protocol version.
+func checkProtocol(resp *http.Response, version string) error {
+	switch version {
+	case HTTPVersion1:
+		if resp.ProtoMajor != 1 {
+			return fmt.Errorf("expected HTTP/1.1, server used %s", resp.Proto)
+		}
+	case HTTPVersion2:
+		if resp.ProtoMajor != 2 {
+			return fmt.Errorf("server did not negotiate HTTP/2 (got %s)", resp.Proto)
+		}
+	case HTTPVersion3:
+		if resp.ProtoMajor != 3 {
+			return fmt.Errorf("expected HTTP/3, server used %s", resp.Proto)
+		}
+	}
+	return nil
+}
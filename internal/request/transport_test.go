@@ -0,0 +1,92 @@
+package request
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestConfigureProtocol(t *testing.T) {
+	t.Run("HTTP/1.1 disables HTTP/2", func(t *testing.T) {
+		transport := &http.Transport{}
+		if err := configureProtocol(transport, HTTPVersion1); err != nil {
+			t.Fatalf("configureProtocol() error = %v", err)
+		}
+		if transport.TLSNextProto == nil {
+			t.Error("TLSNextProto should be a non-nil empty map")
+		}
+		if got := transport.TLSClientConfig.NextProtos; len(got) != 1 || got[0] != "http/1.1" {
+			t.Errorf("NextProtos = %v, want [http/1.1]", got)
+		}
+	})
+
+	t.Run("HTTP/2 offers only h2", func(t *testing.T) {
+		transport := &http.Transport{}
+		if err := configureProtocol(transport, HTTPVersion2); err != nil {
+			t.Fatalf("configureProtocol() error = %v", err)
+		}
+		if !transport.ForceAttemptHTTP2 {
+			t.Error("ForceAttemptHTTP2 should be true")
+		}
+		if got := transport.TLSClientConfig.NextProtos; len(got) != 1 || got[0] != "h2" {
+			t.Errorf("NextProtos = %v, want [h2]", got)
+		}
+	})
+
+	t.Run("HTTP/3 needs QUIC", func(t *testing.T) {
+		if err := configureProtocol(&http.Transport{}, HTTPVersion3); err == nil {
+			t.Error("configureProtocol() accepted HTTP/3 for a TCP transport")
+		}
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		if err := configureProtocol(&http.Transport{}, "4"); err == nil {
+			t.Error("configureProtocol() expected error for unknown version")
+		}
+	})
+}
+
+func TestCheckProtocol(t *testing.T) {
+	tests := []struct {
+		name       string
+		protoMajor int
+		version    string
+		wantErr    bool
+	}{
+		{"auto accepts anything", 1, HTTPVersionAuto, false},
+		{"forced 1.1 got 1.1", 1, HTTPVersion1, false},
+		{"forced 1.1 got 2", 2, HTTPVersion1, true},
+		{"forced 2 got 2", 2, HTTPVersion2, false},
+		{"forced 2 got 1.1", 1, HTTPVersion2, true},
+		{"forced 3 got 3", 3, HTTPVersion3, false},
+		{"forced 3 got 2", 2, HTTPVersion3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{ProtoMajor: tt.protoMajor, Proto: "HTTP/x"}
+			err := checkProtocol(resp, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkProtocol() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTransportKeepAlive(t *testing.T) {
+	// The zero value keeps connections alive, like http.DefaultTransport
+	transport, err := newTransport(PingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.DisableKeepAlives {
+		t.Error("zero PingOptions disabled keep-alives")
+	}
+
+	transport, err = newTransport(PingOptions{DisableKeepAlive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !transport.DisableKeepAlives {
+		t.Error("DisableKeepAlive did not disable keep-alives")
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// launchctlCommand is the launchctl binary to run (replaced in tests).
+var launchctlCommand = "launchctl"
+
+// launchdLabelPrefix makes launchd labels unique ("com.github.symtalha14.tapr").
+const launchdLabelPrefix = "com.github.symtalha14."
+
+// launchdSystemDir holds system-wide jobs.
+const launchdSystemDir = "/Library/LaunchDaemons"
+
+// launchd manages services as launchd jobs: daemons under
+// /Library/LaunchDaemons, agents under ~/Library/LaunchAgents.
+type launchd struct{}
+
+// Name returns "launchd".
+func (launchd) Name() string { return "launchd" }
+
+// Path returns the plist file of a job.
+func (launchd) Path(name string, system bool) (string, error) {
+	if system {
+		return filepath.Join(launchdSystemDir, launchdLabelPrefix+name+".plist"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabelPrefix+name+".plist"), nil
+}
+
+// logPath returns the file a job's output is written to.
+func (launchd) logPath(name string, system bool) string {
+	if system {
+		return filepath.Join("/Library/Logs/tapr", name+".log")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "Logs", "tapr", name+".log")
+}
+
+// Render returns the job's plist. The job starts at load and is restarted
+// whenever it exits with an error.
+func (l launchd) Render(spec Spec) ([]byte, error) {
+	logs := l.logPath(spec.Name, spec.System)
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Installed by tapr daemon install; remove with tapr daemon uninstall -->
+<plist version="1.0">
+<dict>
+`)
+	writeKey := func(key, value string) {
+		fmt.Fprintf(&b, "\t<key>%s</key>\n\t<string>%s</string>\n", key, xmlEscape(value))
+	}
+	writeKey("Label", launchdLabelPrefix+spec.Name)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{spec.Program}, spec.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if spec.WorkingDir != "" {
+		writeKey("WorkingDirectory", spec.WorkingDir)
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	b.WriteString("\t<key>ThrottleInterval</key>\n\t<integer>5</integer>\n")
+	writeKey("StandardOutPath", logs)
+	writeKey("StandardErrorPath", logs)
+	b.WriteString("</dict>\n</plist>\n")
+	return []byte(b.String()), nil
+}
+
+// xmlEscape escapes s for use in XML text.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// domain returns the launchd domain jobs are loaded into.
+func (launchd) domain(system bool) string {
+	if system {
+		return "system"
+	}
+	return "gui/" + strconv.Itoa(os.Getuid())
+}
+
+// Install writes the plist and loads the job, replacing a loaded job of
+// the same name.
+func (l launchd) Install(spec Spec, force bool) (string, error) {
+	path, err := l.Path(spec.Name, spec.System)
+	if err != nil {
+		return "", err
+	}
+	data, err := l.Render(spec)
+	if err != nil {
+		return "", err
+	}
+	if err := writeDefinition(path, data, force); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.logPath(spec.Name, spec.System)), 0o755); err != nil {
+		return path, err
+	}
+
+	domain := l.domain(spec.System)
+	_, _ = run(launchctlCommand, "bootout", domain+"/"+launchdLabelPrefix+spec.Name) // Not loaded yet is fine
+	_, err = run(launchctlCommand, "bootstrap", domain, path)
+	return path, err
+}
+
+// Uninstall unloads the job and removes its plist.
+func (l launchd) Uninstall(name string, system bool) (string, error) {
+	path, err := l.Path(name, system)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path, fmt.Errorf("not installed (no %s)", path)
+	}
+
+	_, _ = run(launchctlCommand, "bootout", l.domain(system)+"/"+launchdLabelPrefix+name)
+	return path, os.Remove(path)
+}
+
+// Status reports the job's state from launchctl print.
+func (l launchd) Status(name string, system bool) (Status, error) {
+	path, err := l.Path(name, system)
+	if err != nil {
+		return Status{}, err
+	}
+	status := Status{Path: path, Logs: l.logPath(name, system)}
+	if _, err := os.Stat(path); err != nil {
+		status.State = "not installed"
+		return status, nil
+	}
+	status.Exists = true
+
+	out, err := run(launchctlCommand, "print", l.domain(system)+"/"+launchdLabelPrefix+name)
+	if err != nil {
+		status.State = "not loaded"
+		return status, nil
+	}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "state":
+			if status.State == "" {
+				status.State = value
+			}
+		case "pid":
+			status.PID, _ = strconv.Atoi(value)
+		}
+	}
+	status.Running = status.State == "running"
+	return status, nil
+}
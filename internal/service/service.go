@@ -0,0 +1,110 @@
+// Package service installs tapr as a background service managed by the
+// operating system: a systemd unit on Linux or a launchd job on macOS.
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultName is the service name used when none is given.
+const DefaultName = "tapr"
+
+// Spec describes a service to install.
+type Spec struct {
+	Name        string   // Service name ("tapr" → tapr.service)
+	Description string   // Shown by the service manager
+	Program     string   // Absolute path of the tapr binary
+	Args        []string // Arguments passed to Program
+	WorkingDir  string   // Directory the service runs in
+	System      bool     // Install system-wide (needs root) instead of for the current user
+}
+
+// Status is the state of an installed service.
+type Status struct {
+	Path    string // Unit or plist file
+	Exists  bool   // Whether the file exists
+	State   string // Manager's state ("active (running)", "running", "inactive", ...)
+	Running bool
+	PID     int
+	Since   string // When the service entered its state, if known
+	Logs    string // Where the output goes
+}
+
+// Manager installs and controls services with one service manager.
+type Manager interface {
+	// Name is the service manager, like "systemd".
+	Name() string
+	// Path returns the file a service is defined in.
+	Path(name string, system bool) (string, error)
+	// Render returns the service definition for spec.
+	Render(spec Spec) ([]byte, error)
+	// Install writes the definition for spec and starts the service, now
+	// and at boot or login.
+	Install(spec Spec, force bool) (string, error)
+	// Uninstall stops the service and removes its definition.
+	Uninstall(name string, system bool) (string, error)
+	// Status reports the state of the service.
+	Status(name string, system bool) (Status, error)
+}
+
+// ForOS returns the service manager for an operating system (runtime.GOOS).
+func ForOS(goos string) (Manager, error) {
+	switch goos {
+	case "linux":
+		return systemd{}, nil
+	case "darwin":
+		return launchd{}, nil
+	}
+	return nil, fmt.Errorf("installing a service is supported with systemd (Linux) and launchd (macOS), not on %s", goos)
+}
+
+// ValidateName checks that a service name is safe to use in file names
+// and unit names.
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("service name is empty")
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("invalid service name %q (use letters, digits, '-', '_' and '.')", name)
+		}
+	}
+	return nil
+}
+
+// writeDefinition writes a service definition to path, refusing to replace
+// an existing one unless force is set.
+func writeDefinition(path string, data []byte, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("already installed at %s (use --force to replace it)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// run runs a service manager command, returning its output and an error
+// that includes what the command printed.
+func run(name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		if message != "" {
+			return stdout.String(), fmt.Errorf("%s %s: %s", filepath.Base(name), strings.Join(args, " "), message)
+		}
+		return stdout.String(), fmt.Errorf("%s %s: %w", filepath.Base(name), strings.Join(args, " "), err)
+	}
+	return stdout.String(), nil
+}
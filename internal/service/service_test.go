@@ -0,0 +1,242 @@
+package service
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeCommand replaces a service manager command with a script that logs
+// its arguments to a file and prints output, returning the log's path.
+func fakeCommand(t *testing.T, command *string, output string) string {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls.log")
+	script := filepath.Join(dir, "manager")
+	content := "#!/bin/sh\necho \"$@\" >> " + log + "\ncat <<'EOF'\n" + output + "\nEOF\n"
+	if err := os.WriteFile(script, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := *command
+	*command = script
+	t.Cleanup(func() { *command = old })
+	return log
+}
+
+func readCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+var testSpec = Spec{
+	Name:        "tapr",
+	Description: "tapr monitoring daemon (monitors.yml)",
+	Program:     "/usr/local/bin/tapr",
+	Args:        []string{"daemon", "/srv/my configs/monitors.yml", "--listen", ":9876"},
+	WorkingDir:  "/srv/my configs",
+}
+
+func TestSystemd_Render(t *testing.T) {
+	data, err := systemd{}.Render(testSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit := string(data)
+	for _, want := range []string{
+		"Description=tapr monitoring daemon (monitors.yml)\n",
+		`ExecStart=/usr/local/bin/tapr daemon "/srv/my configs/monitors.yml" --listen :9876` + "\n",
+		`WorkingDirectory="/srv/my configs"` + "\n",
+		"Restart=on-failure\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit is missing %q:\n%s", want, unit)
+		}
+	}
+
+	system := testSpec
+	system.System = true
+	data, _ = systemd{}.Render(system)
+	if !strings.Contains(string(data), "WantedBy=multi-user.target\n") {
+		t.Errorf("system unit should be wanted by multi-user.target:\n%s", data)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"/usr/bin/tapr", "/usr/bin/tapr"},
+		{"a b", `"a b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"100%", "100%%"},
+		{"$HOME", "$$HOME"},
+		{"", `""`},
+	}
+	for _, tt := range tests {
+		if got := systemdQuote(tt.in); got != tt.want {
+			t.Errorf("systemdQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSystemd_InstallStatusUninstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	log := fakeCommand(t, &systemctlCommand, "ActiveState=active\nSubState=running\nMainPID=4242\nStateChangeTimestamp=Fri 2026-10-16 09:00:00 UTC")
+	s := systemd{}
+
+	status, err := s.Status("tapr", false)
+	if err != nil || status.Exists || status.Running || status.State != "not installed" {
+		t.Fatalf("Status() before install = %+v, %v, want not installed", status, err)
+	}
+
+	path, err := s.Install(testSpec, false)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if want := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "systemd", "user", "tapr.service"); path != want {
+		t.Errorf("Install() path = %s, want %s", path, want)
+	}
+	if _, err := s.Install(testSpec, false); err == nil || !strings.Contains(err.Error(), "already installed") {
+		t.Errorf("second Install() error = %v, want already installed", err)
+	}
+	if _, err := s.Install(testSpec, true); err != nil {
+		t.Errorf("Install(force) error = %v", err)
+	}
+
+	status, err = s.Status("tapr", false)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Exists || !status.Running || status.PID != 4242 || status.State != "active (running)" || status.Logs != "journalctl --user -u tapr" {
+		t.Errorf("Status() = %+v", status)
+	}
+
+	if _, err := s.Uninstall("tapr", false); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unit file still exists after Uninstall()")
+	}
+	if _, err := s.Uninstall("tapr", false); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("second Uninstall() error = %v, want not installed", err)
+	}
+
+	want := []string{
+		"--user daemon-reload",
+		"--user enable tapr.service",
+		"--user restart tapr.service",
+		"--user daemon-reload",
+		"--user enable tapr.service",
+		"--user restart tapr.service",
+		"--user show tapr.service --property=ActiveState,SubState,MainPID,StateChangeTimestamp",
+		"--user disable --now tapr.service",
+		"--user daemon-reload",
+	}
+	if got := readCalls(t, log); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("systemctl calls =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLaunchd_Render(t *testing.T) {
+	t.Setenv("HOME", "/Users/dev")
+	spec := testSpec
+	spec.Args = append(spec.Args, "--history", "/tmp/a&b.ndjson")
+
+	data, err := launchd{}.Render(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The plist must be well-formed XML with every argument in order
+	var plist struct {
+		Dict struct {
+			Keys    []string `xml:"key"`
+			Strings []string `xml:"string"`
+			Array   struct {
+				Strings []string `xml:"string"`
+			} `xml:"array"`
+		} `xml:"dict"`
+	}
+	if err := xml.Unmarshal(data, &plist); err != nil {
+		t.Fatalf("plist is not valid XML: %v\n%s", err, data)
+	}
+	wantArgs := append([]string{spec.Program}, spec.Args...)
+	if strings.Join(plist.Dict.Array.Strings, "|") != strings.Join(wantArgs, "|") {
+		t.Errorf("ProgramArguments = %q, want %q", plist.Dict.Array.Strings, wantArgs)
+	}
+	wantStrings := []string{
+		"com.github.symtalha14.tapr",
+		"/srv/my configs",
+		"/Users/dev/Library/Logs/tapr/tapr.log",
+		"/Users/dev/Library/Logs/tapr/tapr.log",
+	}
+	if strings.Join(plist.Dict.Strings, "|") != strings.Join(wantStrings, "|") {
+		t.Errorf("plist strings = %q, want %q", plist.Dict.Strings, wantStrings)
+	}
+}
+
+func TestLaunchd_InstallStatusUninstall(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	log := fakeCommand(t, &launchctlCommand, "com.github.symtalha14.tapr = {\n\tstate = running\n\tpid = 777\n\tprogram = /usr/local/bin/tapr\n}")
+	l := launchd{}
+
+	path, err := l.Install(testSpec, false)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if want := filepath.Join(os.Getenv("HOME"), "Library", "LaunchAgents", "com.github.symtalha14.tapr.plist"); path != want {
+		t.Errorf("Install() path = %s, want %s", path, want)
+	}
+
+	status, err := l.Status("tapr", false)
+	if err != nil || !status.Running || status.PID != 777 {
+		t.Errorf("Status() = %+v, %v, want running with pid 777", status, err)
+	}
+
+	if _, err := l.Uninstall("tapr", false); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	domain := l.domain(false)
+	want := []string{
+		"bootout " + domain + "/com.github.symtalha14.tapr",
+		"bootstrap " + domain + " " + path,
+		"print " + domain + "/com.github.symtalha14.tapr",
+		"bootout " + domain + "/com.github.symtalha14.tapr",
+	}
+	if got := readCalls(t, log); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("launchctl calls =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"tapr", "tapr-staging", "tapr_2.eu"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../etc/passwd", "tapr staging", "tapr;rm"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) should fail", name)
+		}
+	}
+}
+
+func TestForOS(t *testing.T) {
+	if m, err := ForOS("linux"); err != nil || m.Name() != "systemd" {
+		t.Errorf("ForOS(linux) = %v, %v", m, err)
+	}
+	if m, err := ForOS("darwin"); err != nil || m.Name() != "launchd" {
+		t.Errorf("ForOS(darwin) = %v, %v", m, err)
+	}
+	if _, err := ForOS("windows"); err == nil {
+		t.Error("ForOS(windows) should fail")
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// systemctlCommand is the systemctl binary to run (replaced in tests).
+var systemctlCommand = "systemctl"
+
+// systemdSystemDir holds system-wide units.
+const systemdSystemDir = "/etc/systemd/system"
+
+// systemd manages services as systemd units: system units under
+// /etc/systemd/system, user units under ~/.config/systemd/user.
+type systemd struct{}
+
+// Name returns "systemd".
+func (systemd) Name() string { return "systemd" }
+
+// Path returns the unit file of a service.
+func (systemd) Path(name string, system bool) (string, error) {
+	if system {
+		return filepath.Join(systemdSystemDir, name+".service"), nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(config, "systemd", "user", name+".service"), nil
+}
+
+// Render returns the unit file. The service starts at boot (system) or
+// login (user) and is restarted whenever it fails.
+func (systemd) Render(spec Spec) ([]byte, error) {
+	wantedBy := "default.target"
+	if spec.System {
+		wantedBy = "multi-user.target"
+	}
+
+	command := make([]string, 0, len(spec.Args)+1)
+	for _, arg := range append([]string{spec.Program}, spec.Args...) {
+		command = append(command, systemdQuote(arg))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Installed by `tapr daemon install`; remove with `tapr daemon uninstall --name %s`\n", spec.Name)
+	fmt.Fprintf(&b, "[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", spec.Description)
+	fmt.Fprintf(&b, "Documentation=https://github.com/symtalha14/tapr\n")
+	fmt.Fprintf(&b, "Wants=network-online.target\n")
+	fmt.Fprintf(&b, "After=network-online.target\n\n")
+	fmt.Fprintf(&b, "[Service]\n")
+	fmt.Fprintf(&b, "Type=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(command, " "))
+	if spec.WorkingDir != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(spec.WorkingDir))
+	}
+	fmt.Fprintf(&b, "Restart=on-failure\n")
+	fmt.Fprintf(&b, "RestartSec=5\n\n")
+	fmt.Fprintf(&b, "[Install]\n")
+	fmt.Fprintf(&b, "WantedBy=%s\n", wantedBy)
+	return []byte(b.String()), nil
+}
+
+// systemdQuote quotes a command line argument for a unit file, escaping
+// the specifiers (%) and variables ($) systemd would otherwise expand.
+func systemdQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	arg = strings.ReplaceAll(arg, "$", "$$")
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	arg = strings.ReplaceAll(arg, `\`, `\\`)
+	arg = strings.ReplaceAll(arg, `"`, `\"`)
+	return `"` + arg + `"`
+}
+
+// systemctl runs systemctl for the user or system manager.
+func (systemd) systemctl(system bool, args ...string) (string, error) {
+	if !system {
+		args = append([]string{"--user"}, args...)
+	}
+	return run(systemctlCommand, args...)
+}
+
+// Install writes the unit, then enables and (re)starts it.
+func (s systemd) Install(spec Spec, force bool) (string, error) {
+	path, err := s.Path(spec.Name, spec.System)
+	if err != nil {
+		return "", err
+	}
+	data, err := s.Render(spec)
+	if err != nil {
+		return "", err
+	}
+	if err := writeDefinition(path, data, force); err != nil {
+		return "", err
+	}
+
+	if _, err := s.systemctl(spec.System, "daemon-reload"); err != nil {
+		return path, err
+	}
+	if _, err := s.systemctl(spec.System, "enable", spec.Name+".service"); err != nil {
+		return path, err
+	}
+	// Restart rather than start, so --force picks up a changed unit
+	_, err = s.systemctl(spec.System, "restart", spec.Name+".service")
+	return path, err
+}
+
+// Uninstall stops and disables the unit and removes its file.
+func (s systemd) Uninstall(name string, system bool) (string, error) {
+	path, err := s.Path(name, system)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path, fmt.Errorf("not installed (no %s)", path)
+	}
+
+	// A unit that failed to load can't be stopped; removing it is enough
+	_, _ = s.systemctl(system, "disable", "--now", name+".service")
+	if err := os.Remove(path); err != nil {
+		return path, err
+	}
+	_, err = s.systemctl(system, "daemon-reload")
+	return path, err
+}
+
+// Status reports the unit's state from systemctl show.
+func (s systemd) Status(name string, system bool) (Status, error) {
+	path, err := s.Path(name, system)
+	if err != nil {
+		return Status{}, err
+	}
+	status := Status{Path: path, Logs: "journalctl -u " + name}
+	if !system {
+		status.Logs = "journalctl --user -u " + name
+	}
+	if _, err := os.Stat(path); err != nil {
+		status.State = "not installed"
+		return status, nil
+	}
+	status.Exists = true
+
+	out, err := s.systemctl(system, "show", name+".service", "--property=ActiveState,SubState,MainPID,StateChangeTimestamp")
+	if err != nil {
+		return status, err
+	}
+	properties := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			properties[key] = value
+		}
+	}
+
+	status.State = properties["ActiveState"]
+	if sub := properties["SubState"]; sub != "" && sub != status.State {
+		status.State += " (" + sub + ")"
+	}
+	status.Running = properties["SubState"] == "running"
+	status.PID, _ = strconv.Atoi(properties["MainPID"])
+	status.Since = properties["StateChangeTimestamp"]
+	return status, nil
+}
@@ -0,0 +1,149 @@
+// Package snapshot stores normalized response bodies on disk and compares
+// later responses against them, so that unexpected changes to a response
+// fail a check.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/symtalha14/tapr/internal/jsondiff"
+	"github.com/symtalha14/tapr/internal/jsonpath"
+)
+
+// Ignored replaces the values of ignored paths in a snapshot. The key must
+// still be present, but its value may change.
+const Ignored = "<ignored>"
+
+// Store is a directory of snapshots, one file per endpoint.
+type Store struct {
+	Dir    string // Directory holding the snapshot files
+	Update bool   // Overwrite snapshots with the current responses instead of comparing
+}
+
+// Check compares body, normalized with Normalize, with the snapshot stored
+// under name. A missing snapshot is created from body (as is every snapshot
+// when Update is set); written reports whether that happened.
+func (s Store) Check(name string, body []byte, ignore []string) (written bool, err error) {
+	normalized, err := Normalize(body, ignore)
+	if err != nil {
+		return false, err
+	}
+
+	path := s.Path(name)
+	stored, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	if s.Update || err != nil {
+		if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+			return false, fmt.Errorf("failed to create snapshot dir: %w", err)
+		}
+		if err := os.WriteFile(path, normalized, 0o644); err != nil {
+			return false, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		return true, nil
+	}
+
+	return false, compare(filepath.Base(path), stored, normalized)
+}
+
+// Path returns the file the snapshot for name is stored in.
+func (s Store) Path(name string) string {
+	return filepath.Join(s.Dir, FileName(name))
+}
+
+// FileName turns an endpoint name into a snapshot file name: letters and
+// digits are kept (lowercased) and everything else becomes a dash.
+//
+// Example:
+//
+//	FileName("GET /users (v2)") // "get-users-v2.snap"
+func FileName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("endpoint")
+	}
+	return b.String() + ".snap"
+}
+
+// Normalize prepares a response body for snapshotting. JSON bodies are
+// re-encoded indented with sorted keys, and the values at the ignore paths
+// are replaced with Ignored. Other bodies are returned unchanged.
+func Normalize(body []byte, ignore []string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil || decoder.More() {
+		return body, nil
+	}
+
+	for _, expr := range ignore {
+		path, err := jsonpath.Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		doc = path.Set(doc, Ignored)
+	}
+
+	var normalized bytes.Buffer
+	encoder := json.NewEncoder(&normalized)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return normalized.Bytes(), nil
+}
+
+// compare reports how a normalized body differs from the stored snapshot.
+func compare(file string, stored, current []byte) error {
+	diffs, err := jsondiff.Compare(stored, current)
+	if err != nil {
+		// Not JSON: compare byte for byte
+		if bytes.Equal(stored, current) {
+			return nil
+		}
+		return fmt.Errorf("response differs from snapshot %s (%d bytes → %d bytes)", file, len(stored), len(current))
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("response differs from snapshot %s: %s", file, describe(diffs[0]))
+	if len(diffs) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(diffs)-1)
+	}
+	return errors.New(message)
+}
+
+// describe formats a single difference for a failure message.
+func describe(d jsondiff.Difference) string {
+	switch d.Kind {
+	case jsondiff.Added:
+		return fmt.Sprintf("%s added (%s)", d.Path, d.Right)
+	case jsondiff.Removed:
+		return fmt.Sprintf("%s removed (was %s)", d.Path, d.Left)
+	default:
+		return fmt.Sprintf("%s changed from %s to %s", d.Path, d.Left, d.Right)
+	}
+}
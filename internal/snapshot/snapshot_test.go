@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		ignore  []string
+		want    string
+		wantErr bool
+	}{
+		{
+			"sorted and indented",
+			`{"b": 1, "a": {"y": true, "x": 1.50}}`,
+			nil,
+			"{\n  \"a\": {\n    \"x\": 1.50,\n    \"y\": true\n  },\n  \"b\": 1\n}\n",
+			false,
+		},
+		{
+			"ignored paths",
+			`{"id": 7, "items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}], "meta": {}}`,
+			[]string{"$.id", "$.items[*].id", "$.meta.generated_at"},
+			`{"id":"<ignored>","items":[{"id":"<ignored>","name":"a"},{"id":"<ignored>","name":"b"}],"meta":{}}`,
+			false,
+		},
+		{"text body", "OK\n", nil, "OK\n", false},
+		{"text body with ignore", "OK\n", []string{"$.id"}, "OK\n", false},
+		{"invalid path", `{"id": 1}`, []string{"$.items["}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize([]byte(tt.body), tt.ignore)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(tt.ignore) > 0 && strings.HasPrefix(tt.body, "{") {
+				// Compare compacted, the layout is covered above
+				got = []byte(strings.Join(strings.Fields(string(got)), ""))
+			}
+			if string(got) != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreCheck(t *testing.T) {
+	store := Store{Dir: filepath.Join(t.TempDir(), "snapshots")}
+	ignore := []string{"$.generated_at"}
+
+	written, err := store.Check("Users API", []byte(`{"users": ["ada"], "generated_at": "10:00"}`), ignore)
+	if err != nil || !written {
+		t.Fatalf("first Check() = %v, %v; want a new snapshot", written, err)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir, "users-api.snap")); err != nil {
+		t.Fatalf("snapshot file not written: %v", err)
+	}
+
+	// Ignored values and key order may change
+	written, err = store.Check("Users API", []byte(`{"generated_at": "10:05", "users": ["ada"]}`), ignore)
+	if err != nil || written {
+		t.Errorf("matching Check() = %v, %v; want a match", written, err)
+	}
+
+	_, err = store.Check("Users API", []byte(`{"users": ["ada", "grace"], "generated_at": "10:10"}`), ignore)
+	if err == nil || !strings.Contains(err.Error(), "users-api.snap: $.users[1] added") {
+		t.Errorf("changed Check() error = %v, want the added element", err)
+	}
+
+	// Update rewrites the snapshot, after which the new body matches
+	store.Update = true
+	if written, err := store.Check("Users API", []byte(`{"users": []}`), nil); err != nil || !written {
+		t.Fatalf("update Check() = %v, %v; want a rewritten snapshot", written, err)
+	}
+	store.Update = false
+	if _, err := store.Check("Users API", []byte(`{"users": []}`), nil); err != nil {
+		t.Errorf("Check() after update error = %v", err)
+	}
+
+	// Text bodies are compared byte for byte
+	store.Check("health", []byte("OK"), nil)
+	if _, err := store.Check("health", []byte("DEGRADED"), nil); err == nil || !strings.Contains(err.Error(), "2 bytes → 8 bytes") {
+		t.Errorf("text Check() error = %v, want a size change", err)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"health":          "health.snap",
+		"GET /users (v2)": "get-users-v2.snap",
+		"--":              "endpoint.snap",
+	}
+	for name, want := range tests {
+		if got := FileName(name); got != want {
+			t.Errorf("FileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultAnomalyFactor is how many deviations above the baseline a latency
+// must be to count as an anomaly.
+const DefaultAnomalyFactor = 3.0
+
+const (
+	anomalyAlpha  = 0.1 // Weight of a new latency in the baseline
+	anomalyWarmup = 10  // Checks learned before any is flagged
+
+	// Floors of the deviation, so the jitter of a very steady endpoint
+	// isn't flagged: a tenth of the baseline, and at least a millisecond
+	anomalyMinSpread    = 0.1
+	anomalyMinDeviation = float64(time.Millisecond)
+)
+
+// Anomaly is a check whose latency deviated from the learned baseline.
+type Anomaly struct {
+	Latency  time.Duration
+	Baseline time.Duration
+	Score    float64 // Deviations above the baseline
+}
+
+// AnomalyDetector learns the usual latency of an endpoint and flags checks
+// that are much slower. The baseline is an exponentially weighted moving
+// average (EWMA) of latencies, and its spread the EWMA of their absolute
+// deviations from it, a running stand-in for the median absolute deviation.
+// A latency more than Factor spreads above the baseline is an anomaly.
+// Anomalies are learned at a quarter of the usual weight, so a spike
+// doesn't move the baseline but a lasting change becomes the new normal.
+// The zero value uses the default factor.
+type AnomalyDetector struct {
+	Factor float64 // Deviations above the baseline that count as an anomaly (default 3, negative = off)
+
+	baseline  float64 // Nanoseconds
+	spread    float64 // Nanoseconds
+	checks    int
+	anomalies int
+	worst     Anomaly
+}
+
+// Observe learns the latency of a successful check and reports whether it
+// is an anomaly. Nothing is flagged during the first checks, while the
+// baseline is learned.
+func (d *AnomalyDetector) Observe(latency time.Duration) (Anomaly, bool) {
+	x := float64(latency)
+	d.checks++
+	if d.checks == 1 {
+		d.baseline = x
+		return Anomaly{}, false
+	}
+
+	factor := d.Factor
+	if factor == 0 {
+		factor = DefaultAnomalyFactor
+	}
+	spread := math.Max(d.spread, math.Max(d.baseline*anomalyMinSpread, anomalyMinDeviation))
+	deviation := x - d.baseline
+	anomaly := Anomaly{Latency: latency, Baseline: time.Duration(d.baseline), Score: deviation / spread}
+	anomalous := factor > 0 && d.checks > anomalyWarmup && anomaly.Score > factor
+
+	alpha := anomalyAlpha
+	if anomalous {
+		alpha /= 4
+	}
+	d.baseline += alpha * deviation
+	d.spread += alpha * (math.Abs(deviation) - d.spread)
+
+	if !anomalous {
+		return Anomaly{}, false
+	}
+	d.anomalies++
+	if anomaly.Score > d.worst.Score {
+		d.worst = anomaly
+	}
+	return anomaly, true
+}
+
+// Baseline returns the learned usual latency, or 0 before any check.
+func (d *AnomalyDetector) Baseline() time.Duration {
+	return time.Duration(d.baseline)
+}
+
+// Anomalies returns the number of anomalies flagged.
+func (d *AnomalyDetector) Anomalies() int {
+	return d.anomalies
+}
+
+// Worst returns the anomaly furthest above its baseline, or the zero
+// Anomaly if there was none.
+func (d *AnomalyDetector) Worst() Anomaly {
+	return d.worst
+}
@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnomalyDetector(t *testing.T) {
+	var d AnomalyDetector
+	steady := []time.Duration{100, 110, 95, 105, 100, 98, 102, 107, 93, 101, 99, 104}
+
+	// Learn the baseline; nothing is flagged during the warm-up, even a spike
+	for i, ms := range steady {
+		latency := ms * time.Millisecond
+		if i == 3 {
+			latency = time.Second
+		}
+		if _, anomalous := d.Observe(latency); anomalous {
+			t.Fatalf("check %d (%v) flagged while learning", i+1, latency)
+		}
+	}
+	baseline := d.Baseline()
+	if baseline < 100*time.Millisecond || baseline > 200*time.Millisecond {
+		t.Fatalf("Baseline() = %v, want near 100ms", baseline)
+	}
+
+	tests := []struct {
+		latency time.Duration
+		want    bool
+	}{
+		{105 * time.Millisecond, false},
+		{120 * time.Millisecond, false}, // Within the spread
+		{50 * time.Millisecond, false},  // Faster is never an anomaly
+		{900 * time.Millisecond, true},
+		{101 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		anomaly, got := d.Observe(tt.latency)
+		if got != tt.want {
+			t.Errorf("Observe(%v) anomalous = %v (score %.1f), want %v", tt.latency, got, anomaly.Score, tt.want)
+		}
+	}
+
+	if d.Anomalies() != 1 || d.Worst().Latency != 900*time.Millisecond {
+		t.Errorf("Anomalies() = %d, Worst() = %+v; want 1 at 900ms", d.Anomalies(), d.Worst())
+	}
+	if d.Baseline() > 2*baseline {
+		t.Errorf("Baseline() = %v after one spike, want it barely moved from %v", d.Baseline(), baseline)
+	}
+}
+
+func TestAnomalyDetector_LearnsNewNormal(t *testing.T) {
+	d := AnomalyDetector{Factor: 3}
+	for i := 0; i < 20; i++ {
+		d.Observe(100 * time.Millisecond)
+	}
+
+	// A lasting slowdown is flagged at first, then becomes the baseline
+	flagged := 0
+	for i := 0; i < 100; i++ {
+		if _, anomalous := d.Observe(300 * time.Millisecond); anomalous {
+			flagged++
+		}
+	}
+	if flagged == 0 || flagged == 100 {
+		t.Errorf("flagged %d of 100 checks after a lasting slowdown, want some", flagged)
+	}
+	if _, anomalous := d.Observe(300 * time.Millisecond); anomalous {
+		t.Errorf("300ms still anomalous with baseline %v", d.Baseline())
+	}
+}
+
+func TestAnomalyDetector_Off(t *testing.T) {
+	d := AnomalyDetector{Factor: -1}
+	for i := 0; i < 20; i++ {
+		d.Observe(100 * time.Millisecond)
+	}
+	if _, anomalous := d.Observe(10 * time.Second); anomalous {
+		t.Error("anomaly flagged with detection off")
+	}
+}
@@ -14,47 +14,130 @@ type BatchResult struct {
 	Result         request.Result // The actual request result
 	ExpectedStatus int            // What status code we expected
 	Success        bool           // Whether the test passed
+	Skipped        bool           // Never requested: a stage or dependency failed, or the endpoint was invalid
 	Message        string         // Optional message (e.g., "Status mismatch")
+	Category       string         // Failure category (see request.Classify), "" on success
+	Tags           []string       // Endpoint tags from the batch config
+	Stage          string         // Batch stage the endpoint ran in, if any
+	Attempts       int            // Requests made, including retries (0 if never sent)
+	Silenced       string         // Maintenance window or silence excusing a failure, "" if none
+	DependsOn      []string       // Endpoints this one depends on
+	Upstream       string         // Failed dependency at the root of a failure, "" if the endpoint failed itself
+
+	RequestHeaders map[string]string // Headers that were sent (for repro commands)
+	RequestBody    string            // Body that was sent (for repro commands)
 }
 
 // BatchSummary aggregates results from multiple endpoint tests.
 type BatchSummary struct {
-	Total      int           // Total endpoints tested
-	Successful int           // Number of successful tests
-	Failed     int           // Number of failed tests
-	Slow       int           // Number of slow responses (> 500ms)
-	TotalTime  time.Duration // Total time for all tests
-	AvgLatency time.Duration // Average latency across all tests
-	Results    []BatchResult // Individual results
+	Total      int            // Total endpoints tested
+	Successful int            // Number of successful tests
+	Failed     int            // Number of failed tests
+	Slow       int            // Number of slow responses (above SlowThreshold)
+	Flaky      int            // Number of tests that passed only on a retry
+	Silenced   int            // Failures excused by maintenance (still counted in Failed)
+	Degraded   int            // Failures caused by a failed dependency (still counted in Failed)
+	Requests   int            // Requests sent, including retries
+	TotalTime  time.Duration  // Total time for all tests
+	AvgLatency time.Duration  // Average latency across all tests
+	Statuses   map[string]int // Results per status label (see StatusLabel)
+	Errors     map[string]int // Failures per category (see request.Classify)
+	Results    []BatchResult  // Individual results
+
+	SlowThreshold time.Duration // Latency above which a response counts as slow (0 = DefaultSlowThreshold)
+
+	timed int // Results with a response, averaged into AvgLatency
 }
 
+// DefaultSlowThreshold is the latency above which a batch response counts
+// as slow, unless BatchSummary.SlowThreshold is set.
+const DefaultSlowThreshold = 500 * time.Millisecond
+
 // NewBatchSummary creates a new batch summary.
 func NewBatchSummary() *BatchSummary {
 	return &BatchSummary{
-		Results: make([]BatchResult, 0),
+		Results:  make([]BatchResult, 0),
+		Statuses: make(map[string]int),
+		Errors:   make(map[string]int),
 	}
 }
 
 // AddResult adds a result to the summary and updates statistics.
 func (bs *BatchSummary) AddResult(result BatchResult) {
+	// Categorize failures; a response that failed no transport or status
+	// check must have failed an expectation
+	if !result.Success && result.Category == "" {
+		switch {
+		case result.Skipped:
+			result.Category = StatusSkipped
+		case request.Classify(result.Result) != "":
+			result.Category = request.Classify(result.Result)
+		default:
+			result.Category = request.CategoryAssertion
+		}
+	}
+
 	bs.Results = append(bs.Results, result)
 	bs.Total++
+	bs.Requests += result.Attempts
 
 	if result.Success {
 		bs.Successful++
+		if result.Flaky() {
+			bs.Flaky++
+		}
 	} else {
 		bs.Failed++
+		if result.Upstream != "" {
+			bs.Degraded++
+		}
+	}
+
+	// Count by status
+	if bs.Statuses == nil {
+		bs.Statuses = make(map[string]int)
+		bs.Errors = make(map[string]int)
+	}
+	if result.Skipped {
+		bs.Statuses[StatusSkipped]++
+	} else {
+		bs.Statuses[StatusLabel(result.Result)]++
+	}
+	if !result.Success {
+		bs.Errors[result.Category]++
+	}
+
+	// Latency only means something for requests that got a response
+	if result.Skipped || result.Result.Error != nil {
+		return
 	}
 
 	// Count slow responses
-	if result.Result.Error == nil && result.Result.Latency > 500*time.Millisecond {
+	slow := bs.SlowThreshold
+	if slow <= 0 {
+		slow = DefaultSlowThreshold
+	}
+	if result.Result.Latency > slow {
 		bs.Slow++
 	}
 
 	// Update average latency
-	if result.Result.Error == nil {
-		bs.AvgLatency = (bs.AvgLatency*time.Duration(bs.Total-1) + result.Result.Latency) / time.Duration(bs.Total)
+	bs.timed++
+	bs.AvgLatency = (bs.AvgLatency*time.Duration(bs.timed-1) + result.Result.Latency) / time.Duration(bs.timed)
+}
+
+// Flaky reports whether the test passed only after failing at least once.
+func (r BatchResult) Flaky() bool {
+	return r.Success && r.Attempts > 1
+}
+
+// RequestRate returns the achieved rate in requests per second over the
+// whole run.
+func (bs *BatchSummary) RequestRate() float64 {
+	if bs.TotalTime <= 0 {
+		return 0
 	}
+	return float64(bs.Requests) / bs.TotalTime.Seconds()
 }
 
 // SuccessRate returns the success rate as a percentage.
@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+func TestBatchSummary_SkippedAndFailedLatency(t *testing.T) {
+	summary := NewBatchSummary()
+	summary.AddResult(BatchResult{Success: true, Result: request.Result{URL: "https://x.io/a", StatusCode: 200, Latency: 2 * time.Millisecond}})
+	summary.AddResult(BatchResult{Success: true, Result: request.Result{URL: "https://x.io/b", StatusCode: 200, Latency: 4 * time.Millisecond}})
+	summary.AddResult(BatchResult{Result: request.Result{URL: "https://x.io/c", Error: errors.New("connection refused")}})
+	summary.AddResult(BatchResult{Skipped: true, Message: "Skipped: dependency 'c' failed"})
+	summary.AddResult(BatchResult{Skipped: true, Upstream: "c", Message: "Skipped: dependency 'c' failed"})
+
+	if summary.AvgLatency != 3*time.Millisecond {
+		t.Errorf("AvgLatency = %v, want 3ms (only responses count)", summary.AvgLatency)
+	}
+	if summary.Total != 5 || summary.Successful != 2 || summary.Failed != 3 || summary.Degraded != 1 {
+		t.Errorf("Total/Successful/Failed/Degraded = %d/%d/%d/%d, want 5/2/3/1",
+			summary.Total, summary.Successful, summary.Failed, summary.Degraded)
+	}
+	if summary.Statuses[StatusSkipped] != 2 || summary.Errors[StatusSkipped] != 2 {
+		t.Errorf("skipped statuses/errors = %d/%d, want 2/2", summary.Statuses[StatusSkipped], summary.Errors[StatusSkipped])
+	}
+	if summary.Slow != 0 {
+		t.Errorf("Slow = %d, want 0", summary.Slow)
+	}
+}
+
+func TestBatchSummary_SkippedOnly(t *testing.T) {
+	summary := NewBatchSummary()
+	summary.AddResult(BatchResult{Skipped: true, Message: "Skipped: stage 'setup' failed"})
+
+	if summary.AvgLatency != 0 || summary.Successful != 0 || summary.Failed != 1 {
+		t.Errorf("AvgLatency/Successful/Failed = %v/%d/%d, want 0/0/1", summary.AvgLatency, summary.Successful, summary.Failed)
+	}
+}
@@ -0,0 +1,107 @@
+package stats
+
+// Defaults for flap detection.
+const (
+	DefaultFlapWindow    = 20 // Recent checks examined for flapping
+	DefaultFlapThreshold = 6  // State changes in the window that start flapping
+)
+
+// Hysteresis debounces the up/down state of an endpoint: it takes
+// FailureThreshold consecutive failures to go down and RecoveryThreshold
+// consecutive successes to come back up. An endpoint whose checks keep
+// alternating is flapping: FlapThreshold or more changes between success and
+// failure in the last FlapWindow checks start flapping, and it ends once the
+// changes drop to half that. The zero value uses the defaults.
+type Hysteresis struct {
+	FailureThreshold  int // Consecutive failures before down (default 1)
+	RecoveryThreshold int // Consecutive successes before up again (default 1)
+	FlapWindow        int // Recent checks examined for flapping (default 20)
+	FlapThreshold     int // State changes in the window that start flapping (default 6, negative = off)
+
+	down      bool
+	flapping  bool
+	failures  int    // Consecutive failures
+	successes int    // Consecutive successes
+	recent    []bool // Results of the last FlapWindow checks, oldest first
+	flaps     int    // Times flapping started
+}
+
+// Observe records the result of a check.
+func (h *Hysteresis) Observe(success bool) {
+	if success {
+		h.successes++
+		h.failures = 0
+		if h.down && h.successes >= max(h.RecoveryThreshold, 1) {
+			h.down = false
+		}
+	} else {
+		h.failures++
+		h.successes = 0
+		if !h.down && h.failures >= max(h.FailureThreshold, 1) {
+			h.down = true
+		}
+	}
+
+	window := h.FlapWindow
+	if window <= 0 {
+		window = DefaultFlapWindow
+	}
+	h.recent = append(h.recent, success)
+	if len(h.recent) > window {
+		h.recent = h.recent[len(h.recent)-window:]
+	}
+
+	threshold := h.FlapThreshold
+	if threshold == 0 {
+		threshold = DefaultFlapThreshold
+	}
+	if threshold < 0 {
+		return
+	}
+	changes := h.Changes()
+	switch {
+	case !h.flapping && changes >= threshold:
+		h.flapping = true
+		h.flaps++
+	case h.flapping && changes <= threshold/2:
+		h.flapping = false
+	}
+}
+
+// Down reports whether the endpoint is down.
+func (h *Hysteresis) Down() bool {
+	return h.down
+}
+
+// Flapping reports whether the endpoint is flapping.
+func (h *Hysteresis) Flapping() bool {
+	return h.flapping
+}
+
+// Failures returns the number of consecutive failures.
+func (h *Hysteresis) Failures() int {
+	return h.failures
+}
+
+// Changes returns the number of changes between success and failure in the
+// last FlapWindow checks.
+func (h *Hysteresis) Changes() int {
+	changes := 0
+	for i := 1; i < len(h.recent); i++ {
+		if h.recent[i] != h.recent[i-1] {
+			changes++
+		}
+	}
+	return changes
+}
+
+// Checks returns the number of checks examined for flapping, at most
+// FlapWindow.
+func (h *Hysteresis) Checks() int {
+	return len(h.recent)
+}
+
+// Flaps returns how many times the endpoint started flapping.
+func (h *Hysteresis) Flaps() int {
+	return h.flaps
+}
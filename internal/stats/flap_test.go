@@ -0,0 +1,64 @@
+package stats
+
+import "testing"
+
+func TestHysteresis(t *testing.T) {
+	h := Hysteresis{FailureThreshold: 3, RecoveryThreshold: 2, FlapThreshold: -1}
+
+	steps := []struct {
+		success bool
+		down    bool
+	}{
+		{true, false},
+		{false, false},
+		{false, false},
+		{true, false}, // Streak broken before the threshold
+		{false, false},
+		{false, false},
+		{false, true}, // Third failure in a row
+		{true, true},  // One success isn't enough to recover
+		{false, true},
+		{true, true},
+		{true, false}, // Second success in a row
+	}
+	for i, step := range steps {
+		h.Observe(step.success)
+		if h.Down() != step.down {
+			t.Errorf("check %d: Down() = %v, want %v", i+1, h.Down(), step.down)
+		}
+	}
+	if h.Flapping() {
+		t.Error("Flapping() = true with flap detection off")
+	}
+}
+
+func TestHysteresis_Flapping(t *testing.T) {
+	h := Hysteresis{FlapWindow: 10, FlapThreshold: 4}
+
+	// Alternating results: the fourth change starts flapping
+	for i, success := range []bool{true, false, true, false} {
+		h.Observe(success)
+		if h.Flapping() {
+			t.Fatalf("check %d: flapping after %d changes", i+1, h.Changes())
+		}
+	}
+	h.Observe(true)
+	if !h.Flapping() || h.Changes() != 4 {
+		t.Fatalf("Flapping() = %v with %d changes, want true with 4", h.Flapping(), h.Changes())
+	}
+
+	// Stable results push the changes out of the window
+	for i := 0; i < 6; i++ {
+		h.Observe(true)
+		if !h.Flapping() {
+			t.Fatalf("stable check %d: stopped flapping at %d changes, want at 2", i+1, h.Changes())
+		}
+	}
+	h.Observe(true)
+	if h.Flapping() || h.Changes() != 2 {
+		t.Errorf("Flapping() = %v with %d changes, want false with 2", h.Flapping(), h.Changes())
+	}
+	if h.Flaps() != 1 || h.Checks() != 10 {
+		t.Errorf("Flaps() = %d, Checks() = %d, want 1, 10", h.Flaps(), h.Checks())
+	}
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math/bits"
+	"time"
+)
+
+// HDR histogram layout: values are counted in microseconds, exactly below
+// hdrSubBuckets and with three significant digits above. Each power of two
+// adds hdrHalfBuckets counters, so a session with hour-long latencies still
+// needs only a few tens of thousands of counters.
+const (
+	hdrUnit        = time.Microsecond
+	hdrSubBuckets  = 2048
+	hdrHalfBuckets = hdrSubBuckets / 2
+	hdrSubBits     = 11 // log2(hdrSubBuckets)
+)
+
+// hdrHistogram counts latencies in log-linear buckets, giving percentiles
+// with bounded error in memory that does not grow with the sample count.
+type hdrHistogram struct {
+	counts []int
+	total  int
+}
+
+// record adds one latency.
+func (h *hdrHistogram) record(latency time.Duration) {
+	index := hdrIndex(latency)
+	if index >= len(h.counts) {
+		grown := make([]int, index+1)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[index]++
+	h.total++
+}
+
+// valueAtRank returns the highest latency that the bucket holding the
+// rank-th smallest recorded latency (1-based) can contain.
+func (h *hdrHistogram) valueAtRank(rank int) time.Duration {
+	seen := 0
+	for index, count := range h.counts {
+		seen += count
+		if seen >= rank {
+			return hdrHighest(index)
+		}
+	}
+	return 0
+}
+
+// hdrIndex returns the counter index for a latency.
+func hdrIndex(latency time.Duration) int {
+	value := uint64(0)
+	if latency > 0 {
+		value = uint64(latency / hdrUnit)
+	}
+
+	// Bucket 0 covers [0, hdrSubBuckets) exactly; bucket b > 0 covers
+	// [hdrHalfBuckets<<b, hdrSubBuckets<<b) in steps of 1<<b.
+	bucket := bits.Len64(value|(hdrSubBuckets-1)) - hdrSubBits
+	sub := int(value >> uint(bucket))
+	return bucket*hdrHalfBuckets + sub
+}
+
+// hdrHighest returns the largest latency that maps to a counter index.
+func hdrHighest(index int) time.Duration {
+	bucket, sub := 0, index
+	if index >= hdrSubBuckets {
+		bucket = index/hdrHalfBuckets - 1
+		sub = index - bucket*hdrHalfBuckets
+	}
+	lowest := uint64(sub) << uint(bucket)
+	return time.Duration(lowest+(1<<uint(bucket))-1) * hdrUnit
+}
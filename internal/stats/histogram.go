@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// Bucket is one bar of a latency histogram, counting latencies in the
+// half-open range [Lower, Upper). The last bucket also includes Upper.
+type Bucket struct {
+	Lower time.Duration
+	Upper time.Duration
+	Count int
+}
+
+// Histogram groups latencies into the given number of buckets spanning the
+// observed min to max. With logScale the bucket edges grow geometrically,
+// which keeps detail in the fast range when a long tail stretches the max.
+func Histogram(latencies []time.Duration, buckets int, logScale bool) []Bucket {
+	if len(latencies) == 0 || buckets < 1 {
+		return nil
+	}
+
+	min, max := latencies[0], latencies[0]
+	for _, latency := range latencies {
+		if latency < min {
+			min = latency
+		}
+		if latency > max {
+			max = latency
+		}
+	}
+
+	// All latencies equal: a single bar
+	if min == max {
+		return []Bucket{{Lower: min, Upper: max, Count: len(latencies)}}
+	}
+
+	// Log scale needs positive edges
+	if logScale && min <= 0 {
+		min = 1
+	}
+
+	edges := make([]time.Duration, buckets+1)
+	for i := range edges {
+		fraction := float64(i) / float64(buckets)
+		if logScale {
+			ratio := float64(max) / float64(min)
+			edges[i] = time.Duration(float64(min) * math.Pow(ratio, fraction))
+		} else {
+			edges[i] = min + time.Duration(float64(max-min)*fraction)
+		}
+	}
+	edges[0], edges[buckets] = min, max
+
+	result := make([]Bucket, buckets)
+	for i := range result {
+		result[i] = Bucket{Lower: edges[i], Upper: edges[i+1]}
+	}
+
+	for _, latency := range latencies {
+		// Buckets are few, so a linear scan is fine
+		index := buckets - 1
+		for i := 0; i < buckets-1; i++ {
+			if latency < edges[i+1] {
+				index = i
+				break
+			}
+		}
+		result[index].Count++
+	}
+
+	return result
+}
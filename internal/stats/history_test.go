@@ -129,3 +129,39 @@ func TestHistory_GetRecent_Order(t *testing.T) {
 		}
 	}
 }
+
+func TestWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := NewWindow(time.Minute)
+
+	// An early failure, then healthy requests every 10s. Stats prunes,
+	// so the cases below must move forward in time.
+	window.Record(start, 900*time.Millisecond, false)
+	for i := 1; i <= 5; i++ {
+		window.Record(start.Add(time.Duration(i)*10*time.Second), 100*time.Millisecond, true)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantTotal int
+		wantRate  float64
+	}{
+		{"failure still in window", start.Add(50 * time.Second), 6, 83.33333333333334},
+		{"failure aged out", start.Add(60 * time.Second), 5, 100},
+		{"only last sample left", start.Add(109 * time.Second), 1, 100},
+		{"everything aged out", start.Add(5 * time.Minute), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := window.Stats(tt.now)
+			if got.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", got.Total, tt.wantTotal)
+			}
+			if got.SuccessRate() != tt.wantRate {
+				t.Errorf("SuccessRate() = %v, want %v", got.SuccessRate(), tt.wantRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package stats
+
+import "time"
+
+// TrackerState is a serializable snapshot of a Tracker, used to resume
+// statistics after a restart.
+type TrackerState struct {
+	Total      int             `json:"total"`
+	Successful int             `json:"successful"`
+	Failed     int             `json:"failed"`
+	Latencies  []time.Duration `json:"latencies_ns"`
+	MinLatency time.Duration   `json:"min_latency_ns"`
+	MaxLatency time.Duration   `json:"max_latency_ns"`
+	Statuses   map[string]int  `json:"statuses,omitempty"`
+	Errors     map[string]int  `json:"errors,omitempty"`
+	Sum        time.Duration   `json:"sum_ns"`
+	Mean       float64         `json:"mean_ns"`
+	M2         float64         `json:"m2"`
+	Histogram  map[int]int     `json:"histogram"` // Non-empty HDR counters by index
+}
+
+// State returns a snapshot of the tracker.
+func (t *Tracker) State() TrackerState {
+	state := TrackerState{
+		Total:      t.Total,
+		Successful: t.Successful,
+		Failed:     t.Failed,
+		Latencies:  append([]time.Duration(nil), t.Latencies...),
+		MinLatency: t.MinLatency,
+		MaxLatency: t.MaxLatency,
+		Statuses:   t.Statuses,
+		Errors:     t.Errors,
+		Sum:        t.sum,
+		Mean:       t.mean,
+		M2:         t.m2,
+		Histogram:  make(map[int]int),
+	}
+	for index, count := range t.hist.counts {
+		if count > 0 {
+			state.Histogram[index] = count
+		}
+	}
+	return state
+}
+
+// RestoreTracker rebuilds a tracker from a snapshot taken with State.
+func RestoreTracker(state TrackerState) *Tracker {
+	t := NewTracker()
+	t.Total = state.Total
+	t.Successful = state.Successful
+	t.Failed = state.Failed
+	t.Latencies = append(t.Latencies, state.Latencies...)
+	t.MinLatency = state.MinLatency
+	t.MaxLatency = state.MaxLatency
+	for label, count := range state.Statuses {
+		t.Statuses[label] = count
+	}
+	for category, count := range state.Errors {
+		t.Errors[category] = count
+	}
+	t.sum = state.Sum
+	t.mean = state.Mean
+	t.m2 = state.M2
+
+	for index, count := range state.Histogram {
+		if index < 0 {
+			continue
+		}
+		if index >= len(t.hist.counts) {
+			grown := make([]int, index+1)
+			copy(grown, t.hist.counts)
+			t.hist.counts = grown
+		}
+		t.hist.counts[index] += count
+		t.hist.total += count
+	}
+	return t
+}
@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+// Status labels for requests that have no HTTP status code.
+const (
+	StatusTimeout = "timeout" // The request timed out
+	StatusError   = "error"   // The request failed (DNS, connection, TLS, ...)
+	StatusSkipped = "skipped" // The request was never sent (batch only)
+)
+
+// StatusLabel returns the label a result is counted under in a status
+// breakdown: the HTTP status code ("200", "404"), or StatusTimeout or
+// StatusError when no response was received.
+func StatusLabel(result request.Result) string {
+	if result.Error != nil {
+		var netErr net.Error
+		if errors.As(result.Error, &netErr) && netErr.Timeout() {
+			return StatusTimeout
+		}
+		return StatusError
+	}
+	return strconv.Itoa(result.StatusCode)
+}
+
+// StatusCount is one row of a status breakdown.
+type StatusCount struct {
+	Label string
+	Count int
+}
+
+// SortStatuses returns the entries of a status breakdown ordered with
+// status codes first (ascending), then timeouts, errors and skips.
+func SortStatuses(statuses map[string]int) []StatusCount {
+	counts := make([]StatusCount, 0, len(statuses))
+	for label, count := range statuses {
+		counts = append(counts, StatusCount{Label: label, Count: count})
+	}
+
+	rank := func(label string) int {
+		if code, err := strconv.Atoi(label); err == nil {
+			return code
+		}
+		switch label {
+		case StatusTimeout:
+			return 1000
+		case StatusError:
+			return 1001
+		}
+		return 1002
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return rank(counts[i].Label) < rank(counts[j].Label)
+	})
+	return counts
+}
@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/symtalha14/tapr/internal/request"
+)
+
+func TestStatusLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		result request.Result
+		want   string
+	}{
+		{"ok", request.Result{StatusCode: 200}, "200"},
+		{"server error", request.Result{StatusCode: 503}, "503"},
+		{"timeout", request.Result{Error: fmt.Errorf("request failed: %w", os.ErrDeadlineExceeded)}, StatusTimeout},
+		{"other error", request.Result{Error: errors.New("connection refused")}, StatusError},
+		{"context deadline", request.Result{Error: context.DeadlineExceeded}, StatusTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusLabel(tt.result); got != tt.want {
+				t.Errorf("StatusLabel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortStatuses(t *testing.T) {
+	got := SortStatuses(map[string]int{"error": 1, "500": 2, StatusSkipped: 1, "200": 5, "timeout": 3, "404": 1})
+
+	want := []string{"200", "404", "500", "timeout", "error", "skipped"}
+	if len(got) != len(want) {
+		t.Fatalf("SortStatuses() returned %d rows, want %d", len(got), len(want))
+	}
+	for i, label := range want {
+		if got[i].Label != label {
+			t.Errorf("row %d = %s, want %s", i, got[i].Label, label)
+		}
+	}
+}
+
+func TestBatchSummary_Statuses(t *testing.T) {
+	summary := NewBatchSummary()
+	summary.AddResult(BatchResult{Success: true, Result: request.Result{URL: "https://x.io", StatusCode: 200}})
+	summary.AddResult(BatchResult{Result: request.Result{URL: "https://x.io", StatusCode: 500}})
+	summary.AddResult(BatchResult{Result: request.Result{URL: "https://x.io", StatusCode: 200}})
+	summary.AddResult(BatchResult{Skipped: true, Message: "Skipped: dependency 'login' failed"})
+
+	want := map[string]int{"200": 2, "500": 1, StatusSkipped: 1}
+	for label, count := range want {
+		if summary.Statuses[label] != count {
+			t.Errorf("Statuses[%s] = %d, want %d", label, summary.Statuses[label], count)
+		}
+	}
+}
+
+func TestBatchSummary_Errors(t *testing.T) {
+	summary := NewBatchSummary()
+	summary.AddResult(BatchResult{Success: true, Result: request.Result{URL: "https://x.io", StatusCode: 200}})
+	summary.AddResult(BatchResult{Result: request.Result{URL: "https://x.io", StatusCode: 503}})
+	summary.AddResult(BatchResult{Result: request.Result{URL: "https://x.io", StatusCode: 200}, Message: "Header X-Id missing"})
+	summary.AddResult(BatchResult{Result: request.Result{URL: "https://x.io", Error: context.DeadlineExceeded}})
+	summary.AddResult(BatchResult{Skipped: true, Message: "Skipped: dependency 'login' failed"})
+
+	want := map[string]int{request.CategoryServer: 1, request.CategoryAssertion: 1, request.CategoryTimeout: 1, StatusSkipped: 1}
+	if len(summary.Errors) != len(want) {
+		t.Errorf("Errors = %v, want %v", summary.Errors, want)
+	}
+	for category, count := range want {
+		if summary.Errors[category] != count {
+			t.Errorf("Errors[%s] = %d, want %d", category, summary.Errors[category], count)
+		}
+	}
+}
+
+func TestBatchSummary_SlowThreshold(t *testing.T) {
+	add := func(summary *BatchSummary) {
+		for _, latency := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 800 * time.Millisecond} {
+			summary.AddResult(BatchResult{Success: true, Result: request.Result{URL: "https://x.io", StatusCode: 200, Latency: latency}})
+		}
+	}
+
+	summary := NewBatchSummary()
+	add(summary)
+	if summary.Slow != 1 {
+		t.Errorf("Slow = %d with the default threshold, want 1", summary.Slow)
+	}
+
+	summary = NewBatchSummary()
+	summary.SlowThreshold = 250 * time.Millisecond
+	add(summary)
+	if summary.Slow != 2 {
+		t.Errorf("Slow = %d with a 250ms threshold, want 2", summary.Slow)
+	}
+}
@@ -3,25 +3,60 @@
 package stats
 
 import (
-	"sort"
+	"math"
 	"time"
 )
 
-// Tracker keeps track of request statistics for watch mode.
+// MaxSamples is the number of most recent latencies kept in
+// Tracker.Latencies. Aggregates (average, percentiles, standard deviation)
+// cover every recorded request regardless of this limit.
+const MaxSamples = 10000
+
+// Tracker keeps track of request statistics for watch mode. Memory stays
+// bounded however long a session runs: percentiles come from an HDR
+// histogram (three significant digits) rather than a sorted copy of every
+// latency.
 type Tracker struct {
 	Total      int             // Total number of requests
 	Successful int             // Number of successful requests
 	Failed     int             // Number of failed requests
-	Latencies  []time.Duration // All latency measurements
+	Latencies  []time.Duration // Most recent latencies, at most MaxSamples
 	MinLatency time.Duration   // Minimum latency observed
 	MaxLatency time.Duration   // Maximum latency observed
+	Statuses   map[string]int  // Requests per status label (see StatusLabel)
+	Errors     map[string]int  // Failures per category (see request.Classify)
+
+	sum  time.Duration // Sum of all latencies, for the average
+	mean float64       // Running mean in nanoseconds (Welford)
+	m2   float64       // Sum of squared differences from the mean (Welford)
+	hist hdrHistogram  // Latency distribution, for percentiles
 }
 
 // NewTracker creates a new statistics tracker.
 func NewTracker() *Tracker {
 	return &Tracker{
 		Latencies: make([]time.Duration, 0),
+		Statuses:  make(map[string]int),
+		Errors:    make(map[string]int),
+	}
+}
+
+// RecordError counts a failure under its category (see request.Classify).
+func (t *Tracker) RecordError(category string) {
+	if t.Errors == nil {
+		t.Errors = make(map[string]int)
 	}
+	t.Errors[category]++
+}
+
+// RecordStatus counts a request under its status label (see StatusLabel).
+// It is separate from Record so that callers without HTTP results can
+// still use the tracker.
+func (t *Tracker) RecordStatus(label string) {
+	if t.Statuses == nil {
+		t.Statuses = make(map[string]int)
+	}
+	t.Statuses[label]++
 }
 
 // Record adds a new request result to the tracker.
@@ -34,8 +69,11 @@ func (t *Tracker) Record(latency time.Duration, success bool) {
 		t.Failed++
 	}
 
-	// Record latency
+	// Keep a bounded tail of raw latencies
 	t.Latencies = append(t.Latencies, latency)
+	if len(t.Latencies) > MaxSamples {
+		t.Latencies = t.Latencies[1:]
+	}
 
 	// Update min/max
 	if t.MinLatency == 0 || latency < t.MinLatency {
@@ -44,48 +82,59 @@ func (t *Tracker) Record(latency time.Duration, success bool) {
 	if latency > t.MaxLatency {
 		t.MaxLatency = latency
 	}
+
+	// Streaming aggregates
+	t.sum += latency
+	delta := float64(latency) - t.mean
+	t.mean += delta / float64(t.Total)
+	t.m2 += delta * (float64(latency) - t.mean)
+	t.hist.record(latency)
 }
 
 // AvgLatency calculates the average latency.
 func (t *Tracker) AvgLatency() time.Duration {
-	if len(t.Latencies) == 0 {
+	if t.Total == 0 {
 		return 0
 	}
+	return t.sum / time.Duration(t.Total)
+}
 
-	var total time.Duration
-	for _, latency := range t.Latencies {
-		total += latency
+// StdDev returns the population standard deviation of latencies.
+func (t *Tracker) StdDev() time.Duration {
+	if t.Total == 0 {
+		return 0
 	}
-
-	return total / time.Duration(len(t.Latencies))
+	return time.Duration(math.Sqrt(t.m2 / float64(t.Total)))
 }
 
 // Percentile calculates the Nth percentile of latencies.
 // For example, P95 means 95% of requests were faster than this value.
+// Results are exact below about 2ms and within 0.1% above; they never
+// fall outside the observed min and max.
 func (t *Tracker) Percentile(p float64) time.Duration {
-	if len(t.Latencies) == 0 {
+	if t.Total == 0 {
 		return 0
 	}
 
-	// Sort latencies
-	sorted := make([]time.Duration, len(t.Latencies))
-	copy(sorted, t.Latencies)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i] < sorted[j]
-	})
-
-	// Calculate index for percentile (0-based indexing)
-	index := int(float64(len(sorted))*p) - 1
+	// Rank of the percentile (1-based)
+	rank := int(float64(t.Total) * p)
 
 	// Handle edge cases
-	if index < 0 {
-		index = 0
+	if rank < 1 {
+		return t.MinLatency
 	}
-	if index >= len(sorted) {
-		index = len(sorted) - 1
+	if rank >= t.Total {
+		return t.MaxLatency
 	}
 
-	return sorted[index]
+	value := t.hist.valueAtRank(rank)
+	if value < t.MinLatency {
+		value = t.MinLatency
+	}
+	if value > t.MaxLatency {
+		value = t.MaxLatency
+	}
+	return value
 }
 
 // SuccessRate returns the success rate as a percentage.
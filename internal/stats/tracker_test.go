@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -121,14 +122,71 @@ func TestTracker_Percentile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Percentiles are accurate to three significant digits
 			got := tracker.Percentile(tt.percentile)
-			if got != tt.want {
-				t.Errorf("Percentile(%v) = %v, want %v", tt.percentile, got, tt.want)
+			if diff := got - tt.want; diff < 0 || diff > tt.want/1000 {
+				t.Errorf("Percentile(%v) = %v, want %v (+0.1%%)", tt.percentile, got, tt.want)
 			}
 		})
 	}
 }
 
+func TestTracker_PercentileExactBelow2ms(t *testing.T) {
+	tracker := NewTracker()
+	for i := 1; i <= 1000; i++ {
+		tracker.Record(time.Duration(i)*time.Microsecond, true)
+	}
+
+	if got := tracker.Percentile(0.95); got != 950*time.Microsecond {
+		t.Errorf("Percentile(0.95) = %v, want 950µs", got)
+	}
+}
+
+func TestTracker_BoundedMemory(t *testing.T) {
+	tracker := NewTracker()
+	for i := 0; i < MaxSamples*3; i++ {
+		tracker.Record(time.Duration(i%500)*time.Millisecond, true)
+	}
+
+	if len(tracker.Latencies) != MaxSamples {
+		t.Errorf("len(Latencies) = %d, want %d", len(tracker.Latencies), MaxSamples)
+	}
+	if tracker.Total != MaxSamples*3 {
+		t.Errorf("Total = %d, want %d", tracker.Total, MaxSamples*3)
+	}
+	if got := tracker.Percentile(0.50); got < 249*time.Millisecond || got > 250*time.Millisecond {
+		t.Errorf("Percentile(0.50) = %v, want ~249ms", got)
+	}
+}
+
+func TestTracker_StdDev(t *testing.T) {
+	tracker := NewTracker()
+	for _, latency := range []time.Duration{2, 4, 4, 4, 5, 5, 7, 9} {
+		tracker.Record(latency*time.Millisecond, true)
+	}
+
+	if got := tracker.StdDev(); got != 2*time.Millisecond {
+		t.Errorf("StdDev() = %v, want 2ms", got)
+	}
+	if got := NewTracker().StdDev(); got != 0 {
+		t.Errorf("StdDev() on empty tracker = %v, want 0", got)
+	}
+}
+
+func TestHDRIndex(t *testing.T) {
+	// Every latency must map to a bucket whose highest value is at least
+	// the latency and within 0.1% of it.
+	for _, latency := range []time.Duration{
+		0, time.Microsecond, 2047 * time.Microsecond, 2048 * time.Microsecond,
+		50 * time.Millisecond, 3 * time.Second, time.Hour,
+	} {
+		highest := hdrHighest(hdrIndex(latency))
+		if highest < latency || highest-latency > latency/1000+time.Microsecond {
+			t.Errorf("hdrHighest(hdrIndex(%v)) = %v", latency, highest)
+		}
+	}
+}
+
 func TestTracker_Percentile_Empty(t *testing.T) {
 	tracker := NewTracker()
 	got := tracker.Percentile(0.95)
@@ -192,3 +250,93 @@ func TestTracker_MinMax(t *testing.T) {
 		t.Errorf("MaxLatency = %v, want 1000ms", tracker.MaxLatency)
 	}
 }
+
+func TestHistogram(t *testing.T) {
+	ms := time.Millisecond
+	latencies := []time.Duration{10 * ms, 12 * ms, 15 * ms, 90 * ms, 100 * ms, 110 * ms}
+
+	tests := []struct {
+		name       string
+		latencies  []time.Duration
+		buckets    int
+		logScale   bool
+		wantCounts []int
+	}{
+		{"bimodal linear", latencies, 4, false, []int{3, 0, 0, 3}},
+		{"bimodal log", latencies, 4, true, []int{3, 0, 0, 3}},
+		{"equal latencies", []time.Duration{5 * ms, 5 * ms}, 4, false, []int{2}},
+		{"empty", nil, 4, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := Histogram(tt.latencies, tt.buckets, tt.logScale)
+			if len(buckets) != len(tt.wantCounts) {
+				t.Fatalf("Histogram() returned %d buckets, want %d", len(buckets), len(tt.wantCounts))
+			}
+			total := 0
+			for i, bucket := range buckets {
+				if bucket.Count != tt.wantCounts[i] {
+					t.Errorf("bucket %d count = %d, want %d", i, bucket.Count, tt.wantCounts[i])
+				}
+				total += bucket.Count
+			}
+			if total != len(tt.latencies) {
+				t.Errorf("bucket counts sum to %d, want %d", total, len(tt.latencies))
+			}
+		})
+	}
+}
+
+func TestHistogram_LogEdges(t *testing.T) {
+	latencies := []time.Duration{time.Millisecond, 1000 * time.Millisecond}
+	buckets := Histogram(latencies, 3, true)
+
+	want := []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+	for i, bucket := range buckets {
+		if diff := bucket.Lower - want[i]; diff > time.Microsecond || diff < -time.Microsecond {
+			t.Errorf("bucket %d lower = %v, want %v", i, bucket.Lower, want[i])
+		}
+	}
+	if buckets[2].Upper != time.Second {
+		t.Errorf("last bucket upper = %v, want 1s", buckets[2].Upper)
+	}
+}
+
+func TestTracker_StateRoundTrip(t *testing.T) {
+	tracker := NewTracker()
+	for i := 1; i <= 200; i++ {
+		tracker.Record(time.Duration(i)*time.Millisecond, i%10 != 0)
+	}
+	tracker.RecordStatus("200")
+	tracker.RecordError("5xx")
+
+	data, err := json.Marshal(tracker.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state TrackerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	restored := RestoreTracker(state)
+
+	// Recording after a restore continues the same statistics
+	tracker.Record(500*time.Millisecond, true)
+	restored.Record(500*time.Millisecond, true)
+
+	if restored.Total != tracker.Total || restored.Failed != tracker.Failed {
+		t.Errorf("counts = %d/%d, want %d/%d", restored.Total, restored.Failed, tracker.Total, tracker.Failed)
+	}
+	for _, p := range []float64{0.5, 0.95, 0.99} {
+		if got, want := restored.Percentile(p), tracker.Percentile(p); got != want {
+			t.Errorf("Percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+	if restored.AvgLatency() != tracker.AvgLatency() || restored.StdDev() != tracker.StdDev() {
+		t.Errorf("avg/stddev = %v/%v, want %v/%v", restored.AvgLatency(), restored.StdDev(), tracker.AvgLatency(), tracker.StdDev())
+	}
+	if restored.Statuses["200"] != 1 || restored.Errors["5xx"] != 1 || len(restored.Latencies) != 201 {
+		t.Errorf("restored = %+v", restored)
+	}
+}
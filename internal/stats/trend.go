@@ -0,0 +1,162 @@
+package stats
+
+import "time"
+
+// Trend directions reported by AnalyzeTrend.
+const (
+	TrendImproving = "improving"
+	TrendDegrading = "degrading"
+	TrendStable    = "stable"
+)
+
+// trendThreshold is the relative change in latency over the analyzed span
+// below which a trend counts as stable.
+const trendThreshold = 0.1
+
+// TrendSample is one stored check, as read from the history.
+type TrendSample struct {
+	Time    time.Time
+	Latency time.Duration
+	Success bool
+}
+
+// DayStats summarizes the checks of one calendar day.
+type DayStats struct {
+	Date         time.Time // Midnight at the start of the day
+	Checks       int
+	Failures     int
+	Availability float64       // Successful checks in percent
+	P95          time.Duration // Of successful checks
+}
+
+// HourStats summarizes the checks made in one hour of the day, over all days.
+type HourStats struct {
+	Hour     int // 0-23
+	Checks   int
+	Failures int
+	P95      time.Duration // Of successful checks, 0 without any
+}
+
+// Trend is the long-term behavior of an endpoint over stored checks.
+type Trend struct {
+	Direction string        // TrendImproving, TrendDegrading or TrendStable
+	Change    float64       // Relative latency change over the span, e.g. 0.25 for 25% slower
+	Start     time.Duration // Fitted latency at the first check
+	End       time.Duration // Fitted latency at the last check
+	Checks    int
+	Failures  int
+	Days      []DayStats    // Oldest first, only days with checks
+	Hours     [24]HourStats // By hour of the day
+}
+
+// AnalyzeTrend fits a straight line through the latencies of successful
+// checks (least squares over time) to tell whether the endpoint is getting
+// slower or faster, and breaks the checks down by day and by hour of the
+// day. Days and hours are in loc. Samples must be in time order.
+func AnalyzeTrend(samples []TrendSample, loc *time.Location) Trend {
+	trend := Trend{Direction: TrendStable, Checks: len(samples)}
+	for i := range trend.Hours {
+		trend.Hours[i].Hour = i
+	}
+	if len(samples) == 0 {
+		return trend
+	}
+
+	var hours [24]trendBucket
+	var day trendBucket
+	var n, sumX, sumY, sumXY, sumXX float64
+	origin := samples[0].Time
+
+	for _, sample := range samples {
+		local := sample.Time.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if len(trend.Days) == 0 || !trend.Days[len(trend.Days)-1].Date.Equal(midnight) {
+			if len(trend.Days) > 0 {
+				day.fill(&trend.Days[len(trend.Days)-1])
+			}
+			trend.Days = append(trend.Days, DayStats{Date: midnight})
+			day = trendBucket{}
+		}
+		day.add(sample)
+		hours[local.Hour()].add(sample)
+
+		if !sample.Success {
+			trend.Failures++
+			continue
+		}
+		x := sample.Time.Sub(origin).Hours()
+		y := float64(sample.Latency)
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	day.fill(&trend.Days[len(trend.Days)-1])
+
+	for i := range hours {
+		trend.Hours[i].Checks = hours[i].checks
+		trend.Hours[i].Failures = hours[i].failures
+		trend.Hours[i].P95 = hours[i].p95()
+	}
+
+	// Fit latency = a + b*hours; a single point in time has no trend
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return trend
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	span := samples[len(samples)-1].Time.Sub(origin).Hours()
+	start := intercept
+	end := intercept + slope*span
+	if start <= 0 {
+		return trend
+	}
+	trend.Start = time.Duration(start)
+	trend.End = time.Duration(end)
+	trend.Change = (end - start) / start
+	switch {
+	case trend.Change >= trendThreshold:
+		trend.Direction = TrendDegrading
+	case trend.Change <= -trendThreshold:
+		trend.Direction = TrendImproving
+	}
+	return trend
+}
+
+// trendBucket collects the checks of one day or hour of the day.
+type trendBucket struct {
+	checks   int
+	failures int
+	latency  *Tracker // Of successful checks
+}
+
+// add counts a check in the bucket.
+func (b *trendBucket) add(sample TrendSample) {
+	b.checks++
+	if !sample.Success {
+		b.failures++
+		return
+	}
+	if b.latency == nil {
+		b.latency = NewTracker()
+	}
+	b.latency.Record(sample.Latency, true)
+}
+
+// p95 returns the p95 latency of the successful checks, or 0 without any.
+func (b *trendBucket) p95() time.Duration {
+	if b.latency == nil {
+		return 0
+	}
+	return b.latency.Percentile(0.95)
+}
+
+// fill copies the bucket's totals into the stats of its day.
+func (b *trendBucket) fill(day *DayStats) {
+	day.Checks = b.checks
+	day.Failures = b.failures
+	day.Availability = float64(b.checks-b.failures) / float64(b.checks) * 100
+	day.P95 = b.p95()
+}
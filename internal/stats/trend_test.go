@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeTrend(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var samples []TrendSample
+	for h := 0; h < 72; h++ {
+		latency := 100*time.Millisecond + time.Duration(h)*time.Millisecond
+		if h%24 == 9 {
+			latency += 200 * time.Millisecond // Slow every morning at 9
+		}
+		samples = append(samples, TrendSample{Time: start.Add(time.Duration(h) * time.Hour), Latency: latency, Success: true})
+	}
+	samples[30].Success = false // Day two, 06:00
+
+	trend := AnalyzeTrend(samples, time.UTC)
+
+	if trend.Direction != TrendDegrading || trend.Change < 0.5 {
+		t.Errorf("Direction = %s, Change = %.2f; want degrading by over 50%%", trend.Direction, trend.Change)
+	}
+	if trend.Checks != 72 || trend.Failures != 1 {
+		t.Errorf("Checks = %d, Failures = %d; want 72, 1", trend.Checks, trend.Failures)
+	}
+
+	if len(trend.Days) != 3 {
+		t.Fatalf("len(Days) = %d, want 3", len(trend.Days))
+	}
+	if day := trend.Days[1]; !day.Date.Equal(start.AddDate(0, 0, 1)) || day.Checks != 24 || day.Failures != 1 {
+		t.Errorf("Days[1] = %+v, want 24 checks with 1 failure on March 2", day)
+	}
+	if trend.Days[0].Availability != 100 {
+		t.Errorf("Days[0].Availability = %.2f, want 100", trend.Days[0].Availability)
+	}
+
+	if hour := trend.Hours[9]; hour.Checks != 3 || hour.P95 < 300*time.Millisecond {
+		t.Errorf("Hours[9] = %+v, want 3 checks with a p95 over 300ms", hour)
+	}
+	if trend.Hours[10].P95 >= trend.Hours[9].P95 {
+		t.Errorf("Hours[10].P95 = %v, want below 09:00's %v", trend.Hours[10].P95, trend.Hours[9].P95)
+	}
+}
+
+func TestAnalyzeTrend_Directions(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		step  time.Duration // Latency change per check
+		wants string
+	}{
+		{"improving", -2 * time.Millisecond, TrendImproving},
+		{"stable", 0, TrendStable},
+		{"degrading", 2 * time.Millisecond, TrendDegrading},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var samples []TrendSample
+			for i := 0; i < 40; i++ {
+				latency := 200*time.Millisecond + time.Duration(i)*tt.step
+				samples = append(samples, TrendSample{Time: start.Add(time.Duration(i) * time.Hour), Latency: latency, Success: true})
+			}
+			if got := AnalyzeTrend(samples, time.UTC).Direction; got != tt.wants {
+				t.Errorf("Direction = %s, want %s", got, tt.wants)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTrend_Empty(t *testing.T) {
+	trend := AnalyzeTrend(nil, time.UTC)
+	if trend.Direction != TrendStable || trend.Checks != 0 || len(trend.Days) != 0 {
+		t.Errorf("AnalyzeTrend(nil) = %+v, want a stable trend without checks", trend)
+	}
+}
@@ -0,0 +1,120 @@
+package stats
+
+import "time"
+
+// Outage is a contiguous period during which checks failed, from the first
+// failed check to the first successful one after it.
+type Outage struct {
+	Start time.Time
+	End   time.Time // Zero while the outage is ongoing
+}
+
+// Duration returns the length of the outage, counting an ongoing outage
+// up to now.
+func (o Outage) Duration(now time.Time) time.Duration {
+	if o.End.IsZero() {
+		return now.Sub(o.Start)
+	}
+	return o.End.Sub(o.Start)
+}
+
+// Uptime tracks up and down periods of an endpoint over time, for
+// availability reporting. Unlike Tracker's success rate it weighs each
+// state by how long it lasted, not by how many checks saw it.
+type Uptime struct {
+	started time.Time
+	outages []Outage
+}
+
+// Observe records the outcome of a check made at the given time. Checks
+// must be observed in time order.
+func (u *Uptime) Observe(up bool, at time.Time) {
+	if u.started.IsZero() {
+		u.started = at
+	}
+
+	down := u.down()
+	switch {
+	case !up && !down:
+		u.outages = append(u.outages, Outage{Start: at})
+	case up && down:
+		u.outages[len(u.outages)-1].End = at
+	}
+}
+
+// down reports whether an outage is ongoing.
+func (u *Uptime) down() bool {
+	return len(u.outages) > 0 && u.outages[len(u.outages)-1].End.IsZero()
+}
+
+// Outages returns every outage so far, oldest first.
+func (u *Uptime) Outages() []Outage {
+	return u.outages
+}
+
+// Elapsed returns the time from the first check to now.
+func (u *Uptime) Elapsed(now time.Time) time.Duration {
+	if u.started.IsZero() {
+		return 0
+	}
+	return now.Sub(u.started)
+}
+
+// Downtime returns the total length of all outages up to now.
+func (u *Uptime) Downtime(now time.Time) time.Duration {
+	var total time.Duration
+	for _, outage := range u.outages {
+		total += outage.Duration(now)
+	}
+	return total
+}
+
+// Percent returns the share of time up since the first check, as a
+// percentage. It is 100 before any time has passed.
+func (u *Uptime) Percent(now time.Time) float64 {
+	elapsed := u.Elapsed(now)
+	if elapsed <= 0 {
+		if u.down() {
+			return 0
+		}
+		return 100
+	}
+	return float64(elapsed-u.Downtime(now)) / float64(elapsed) * 100
+}
+
+// MTTR returns the mean time to recovery of finished outages, or 0 if none
+// has finished.
+func (u *Uptime) MTTR() time.Duration {
+	var total time.Duration
+	recovered := 0
+	for _, outage := range u.outages {
+		if !outage.End.IsZero() {
+			total += outage.Duration(outage.End)
+			recovered++
+		}
+	}
+	if recovered == 0 {
+		return 0
+	}
+	return total / time.Duration(recovered)
+}
+
+// LongestOutage returns the longest outage up to now, including an
+// ongoing one.
+func (u *Uptime) LongestOutage(now time.Time) time.Duration {
+	var longest time.Duration
+	for _, outage := range u.outages {
+		if d := outage.Duration(now); d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// ErrorBudget returns the downtime an SLO target (in percent, e.g. 99.9)
+// allows over the time elapsed so far, and how much of it remains.
+// Remaining is negative once the SLO is breached.
+func (u *Uptime) ErrorBudget(slo float64, now time.Time) (allowed, remaining time.Duration) {
+	allowed = time.Duration(float64(u.Elapsed(now)) * (100 - slo) / 100)
+	return allowed, allowed - u.Downtime(now)
+}
@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUptime(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	var uptime Uptime
+	checks := []struct {
+		minute int
+		up     bool
+	}{
+		{0, true}, {10, false}, {12, false}, {14, true}, // 4m outage
+		{50, false}, {60, true}, // 10m outage
+		{90, false}, // ongoing at 100
+	}
+	for _, check := range checks {
+		uptime.Observe(check.up, at(check.minute))
+	}
+
+	now := at(100)
+	if got := len(uptime.Outages()); got != 3 {
+		t.Fatalf("len(Outages()) = %d, want 3", got)
+	}
+	if got := uptime.Downtime(now); got != 24*time.Minute {
+		t.Errorf("Downtime() = %v, want 24m", got)
+	}
+	if got := uptime.Percent(now); got != 76 {
+		t.Errorf("Percent() = %v, want 76", got)
+	}
+	if got := uptime.MTTR(); got != 7*time.Minute {
+		t.Errorf("MTTR() = %v, want 7m (ongoing outages excluded)", got)
+	}
+	if got := uptime.LongestOutage(now); got != 10*time.Minute {
+		t.Errorf("LongestOutage() = %v, want 10m", got)
+	}
+
+	// 90% over 100 minutes allows 10 minutes of downtime
+	allowed, remaining := uptime.ErrorBudget(90, now)
+	if allowed != 10*time.Minute || remaining != -14*time.Minute {
+		t.Errorf("ErrorBudget(90) = %v, %v, want 10m, -14m", allowed, remaining)
+	}
+}
+
+func TestUptime_Empty(t *testing.T) {
+	var uptime Uptime
+	now := time.Now()
+
+	if uptime.Percent(now) != 100 || uptime.MTTR() != 0 || uptime.LongestOutage(now) != 0 {
+		t.Errorf("empty Uptime = %v%%, MTTR %v, longest %v", uptime.Percent(now), uptime.MTTR(), uptime.LongestOutage(now))
+	}
+
+	uptime.Observe(false, now)
+	if uptime.Percent(now) != 0 {
+		t.Errorf("Percent() right after a failed first check = %v, want 0", uptime.Percent(now))
+	}
+}
@@ -0,0 +1,51 @@
+package stats
+
+import "time"
+
+// windowSample is one request recorded in a Window.
+type windowSample struct {
+	at      time.Time
+	latency time.Duration
+	success bool
+}
+
+// Window keeps the requests of a trailing time span, so recent behavior
+// can be reported separately from all-time statistics.
+type Window struct {
+	Span    time.Duration // How far back the window reaches
+	samples []windowSample
+}
+
+// NewWindow creates a sliding window covering the given span.
+func NewWindow(span time.Duration) *Window {
+	return &Window{Span: span}
+}
+
+// Record adds a request made at the given time. Samples that have fallen
+// out of the window are dropped, so memory stays bounded by the span.
+func (w *Window) Record(at time.Time, latency time.Duration, success bool) {
+	w.samples = append(w.samples, windowSample{at: at, latency: latency, success: success})
+	w.prune(at)
+}
+
+// Stats returns a Tracker holding only the requests made within the span
+// before now.
+func (w *Window) Stats(now time.Time) *Tracker {
+	w.prune(now)
+
+	tracker := NewTracker()
+	for _, sample := range w.samples {
+		tracker.Record(sample.latency, sample.success)
+	}
+	return tracker
+}
+
+// prune drops samples older than the span.
+func (w *Window) prune(now time.Time) {
+	cutoff := now.Add(-w.Span)
+	drop := 0
+	for drop < len(w.samples) && !w.samples[drop].at.After(cutoff) {
+		drop++
+	}
+	w.samples = w.samples[drop:]
+}
@@ -0,0 +1,287 @@
+package xpath
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Node kinds
+const (
+	documentNode = iota
+	elementNode
+	attributeNode
+	textNode
+)
+
+// Node is a node of a parsed XML document.
+type Node struct {
+	kind     int
+	name     string // Local name of elements and attributes
+	data     string // Value of attributes and text
+	attrs    []*Node
+	children []*Node
+	parent   *Node
+}
+
+// ParseDocument parses an XML document. Comments, processing instructions
+// and namespace declarations are dropped.
+func ParseDocument(data []byte) (*Node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charsetReader
+
+	root := &Node{kind: documentNode}
+	current := root
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &Node{kind: elementNode, name: t.Name.Local, parent: current}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				element.attrs = append(element.attrs, &Node{kind: attributeNode, name: attr.Name.Local, data: attr.Value, parent: element})
+			}
+			current.children = append(current.children, element)
+			current = element
+		case xml.EndElement:
+			current = current.parent
+		case xml.CharData:
+			if current == root {
+				continue // Whitespace around the root element
+			}
+			if n := len(current.children); n > 0 && current.children[n-1].kind == textNode {
+				current.children[n-1].data += string(t)
+			} else {
+				current.children = append(current.children, &Node{kind: textNode, data: string(t), parent: current})
+			}
+		}
+	}
+
+	if len(root.children) == 0 {
+		return nil, fmt.Errorf("invalid XML: no root element")
+	}
+	return root, nil
+}
+
+// charsetReader converts Latin-1 documents, still common with SOAP
+// services, to UTF-8.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "us-ascii":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		converted := make([]byte, 0, len(data))
+		for _, b := range data {
+			converted = utf8.AppendRune(converted, rune(b))
+		}
+		return bytes.NewReader(converted), nil
+	default:
+		return nil, fmt.Errorf("unsupported charset %s", charset)
+	}
+}
+
+// Value returns the string value of a node: the text of an element and all
+// its descendants, or the value of an attribute or text node.
+func (n *Node) Value() string {
+	if n.kind == attributeNode || n.kind == textNode {
+		return n.data
+	}
+	var b strings.Builder
+	for _, child := range n.children {
+		b.WriteString(child.Value())
+	}
+	return b.String()
+}
+
+// Select returns the nodes the expression's path selects in doc, in
+// document order. For count() expressions it selects the counted nodes.
+func (e Expr) Select(doc *Node) []*Node {
+	return e.path.eval(doc)
+}
+
+// Evaluate returns the result of the expression as a string: the value of
+// the first selected node with surrounding whitespace removed, or the
+// number of nodes for count(). It fails if a path selects nothing.
+func (e Expr) Evaluate(doc *Node) (string, error) {
+	nodes := e.Select(doc)
+	if e.count {
+		return strconv.Itoa(len(nodes)), nil
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("%s matched nothing", e.expr)
+	}
+	return strings.TrimSpace(nodes[0].Value()), nil
+}
+
+// Extract parses an XML body and evaluates the expression against it.
+func Extract(body []byte, expr string) (string, error) {
+	compiled, err := Parse(expr)
+	if err != nil {
+		return "", err
+	}
+	doc, err := ParseDocument(body)
+	if err != nil {
+		return "", err
+	}
+	return compiled.Evaluate(doc)
+}
+
+// eval selects the nodes of the path, starting at context (or at the
+// document for absolute paths).
+func (p path) eval(context *Node) []*Node {
+	if p.absolute {
+		for context.parent != nil {
+			context = context.parent
+		}
+	}
+
+	nodes := []*Node{context}
+	for _, s := range p.steps {
+		var next []*Node
+		seen := make(map[*Node]bool)
+		for _, node := range nodes {
+			for _, selected := range s.eval(node) {
+				if !seen[selected] {
+					seen[selected] = true
+					next = append(next, selected)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// eval selects the nodes of a step for one context node, applying the
+// predicates to the candidates of each parent separately (so that //Item[1]
+// is the first Item of every parent, as in XPath).
+func (s step) eval(node *Node) []*Node {
+	contexts := []*Node{node}
+	if s.descendant {
+		contexts = descendantsOrSelf(node, nil)
+	}
+
+	var result []*Node
+	for _, context := range contexts {
+		var candidates []*Node
+		switch s.axis {
+		case axisSelf:
+			candidates = []*Node{context}
+		case axisParent:
+			if context.parent != nil {
+				candidates = []*Node{context.parent}
+			}
+		case axisAttribute:
+			for _, attr := range context.attrs {
+				if s.name == "*" || attr.name == s.name {
+					candidates = append(candidates, attr)
+				}
+			}
+		default:
+			for _, child := range context.children {
+				if s.matches(child) {
+					candidates = append(candidates, child)
+				}
+			}
+		}
+
+		for _, pred := range s.predicates {
+			candidates = pred.filter(candidates)
+		}
+		result = append(result, candidates...)
+	}
+	return result
+}
+
+// matches reports whether a child node passes the step's node test.
+func (s step) matches(node *Node) bool {
+	if s.name == "text()" {
+		return node.kind == textNode
+	}
+	return node.kind == elementNode && (s.name == "*" || node.name == s.name)
+}
+
+// filter keeps the candidates that satisfy the predicate.
+func (p predicate) filter(candidates []*Node) []*Node {
+	switch {
+	case p.position > 0:
+		if p.position > len(candidates) {
+			return nil
+		}
+		return candidates[p.position-1 : p.position]
+	case p.last:
+		if len(candidates) == 0 {
+			return nil
+		}
+		return candidates[len(candidates)-1:]
+	}
+
+	var kept []*Node
+	for _, candidate := range candidates {
+		if p.holds(candidate) {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// holds reports whether the predicate is true for a node. Comparisons are
+// true if any selected node compares, as in XPath.
+func (p predicate) holds(node *Node) bool {
+	if p.localName {
+		return compare(node.name, p.op, p.literal)
+	}
+
+	selected := p.path.eval(node)
+	if p.op == "" {
+		return len(selected) > 0
+	}
+	for _, s := range selected {
+		if compare(s.Value(), p.op, p.literal) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare compares a node value with a literal, numerically if both are
+// numbers.
+func compare(value, op, literal string) bool {
+	equal := value == literal
+	if a, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+		if b, err := strconv.ParseFloat(literal, 64); err == nil {
+			equal = a == b
+		}
+	}
+	if op == "!=" {
+		return !equal
+	}
+	return equal
+}
+
+// descendantsOrSelf appends node and all its descendant elements to nodes,
+// in document order.
+func descendantsOrSelf(node *Node, nodes []*Node) []*Node {
+	nodes = append(nodes, node)
+	for _, child := range node.children {
+		if child.kind == elementNode {
+			nodes = descendantsOrSelf(child, nodes)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,282 @@
+// Package xpath evaluates a small, dependency-free subset of XPath 1.0
+// against XML documents, enough to assert on values in SOAP and other XML
+// responses.
+//
+// Supported syntax:
+//
+//	/Envelope/Body/Status       absolute path
+//	//Status                    anywhere in the document
+//	//Item[2]/Name              position (1-based), also [last()]
+//	//Item[@id='7']/Price       attribute comparison (= and !=)
+//	//Item[Name='tea']          child element comparison
+//	//Item[@discount]           existence
+//	//*[local-name()='Fault']   any element by name
+//	//Order/@status             attribute value
+//	//Note/text()               text nodes
+//	count(//Item)               number of matches
+//
+// Element and attribute names are matched by local name: namespace prefixes
+// in the expression (soap:Body) and in the document are ignored, so no
+// namespace declarations are needed.
+package xpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Step axes
+const (
+	axisChild = iota
+	axisAttribute
+	axisSelf
+	axisParent
+)
+
+// Expr is a compiled XPath expression.
+type Expr struct {
+	expr  string
+	count bool // count(path) rather than path
+	path  path
+}
+
+// path is a location path.
+type path struct {
+	absolute bool
+	steps    []step
+}
+
+// step selects nodes relative to each context node.
+type step struct {
+	descendant bool   // Preceded by "//": search all descendants
+	axis       int    // One of the axis* constants
+	name       string // Local name, "*" for any, "text()" for text nodes
+	predicates []predicate
+}
+
+// predicate filters the nodes selected by a step.
+type predicate struct {
+	position  int    // [n], 1-based (0 if not positional)
+	last      bool   // [last()]
+	localName bool   // [local-name()='x'] compares the node's name
+	path      *path  // [path] or [path='x']: relative to the node
+	op        string // "=", "!=" or "" for existence
+	literal   string
+}
+
+// String returns the original expression.
+func (e Expr) String() string {
+	return e.expr
+}
+
+// Parse compiles an XPath expression.
+func Parse(expr string) (Expr, error) {
+	p := &parser{s: strings.TrimSpace(expr)}
+	compiled := Expr{expr: expr}
+
+	if strings.HasPrefix(p.s, "count(") && strings.HasSuffix(p.s, ")") {
+		compiled.count = true
+		p.s = strings.TrimSpace(p.s[len("count(") : len(p.s)-1])
+	}
+	if p.s == "" {
+		return Expr{}, fmt.Errorf("invalid XPath %q: empty expression", expr)
+	}
+
+	parsed, err := p.parsePath()
+	if err == nil && p.pos < len(p.s) {
+		err = fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	if err != nil {
+		return Expr{}, fmt.Errorf("invalid XPath %q: %w", expr, err)
+	}
+	compiled.path = parsed
+	return compiled, nil
+}
+
+// parser reads an expression left to right.
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parsePath() (path, error) {
+	var parsed path
+	descendant := false
+
+	p.skipSpace()
+	switch {
+	case p.consume("//"):
+		parsed.absolute, descendant = true, true
+	case p.consume("/"):
+		parsed.absolute = true
+		if p.pos == len(p.s) {
+			return parsed, nil // "/" selects the document itself
+		}
+	}
+
+	for {
+		s, err := p.parseStep(descendant)
+		if err != nil {
+			return path{}, err
+		}
+		parsed.steps = append(parsed.steps, s)
+
+		switch {
+		case p.consume("//"):
+			descendant = true
+		case p.consume("/"):
+			descendant = false
+		default:
+			return parsed, nil
+		}
+	}
+}
+
+func (p *parser) parseStep(descendant bool) (step, error) {
+	s := step{descendant: descendant, axis: axisChild}
+
+	switch {
+	case p.consume(".."):
+		s.axis = axisParent
+	case p.consume("text()"):
+		s.name = "text()"
+	case p.consume("@"):
+		s.axis = axisAttribute
+		s.name = p.readName()
+	case p.pos < len(p.s) && p.s[p.pos] == '.' && (p.pos+1 == len(p.s) || !isNameChar(p.s[p.pos+1])):
+		p.pos++
+		s.axis = axisSelf
+	default:
+		s.name = p.readName()
+	}
+	if s.name == "" && (s.axis == axisChild || s.axis == axisAttribute) {
+		if p.pos == len(p.s) {
+			return step{}, fmt.Errorf("missing name at end")
+		}
+		return step{}, fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+
+	for p.consume("[") {
+		pred, err := p.parsePredicate()
+		if err != nil {
+			return step{}, err
+		}
+		s.predicates = append(s.predicates, pred)
+	}
+	return s, nil
+}
+
+// readName reads a name test, dropping any namespace prefix.
+func (p *parser) readName() string {
+	if p.consume("*") {
+		return "*"
+	}
+	start := p.pos
+	for p.pos < len(p.s) && (isNameChar(p.s[p.pos]) || p.s[p.pos] == ':') {
+		p.pos++
+	}
+	name := p.s[start:p.pos]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func (p *parser) parsePredicate() (predicate, error) {
+	var pred predicate
+	p.skipSpace()
+
+	switch {
+	case p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9':
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		pred.position, _ = strconv.Atoi(p.s[start:p.pos])
+		if pred.position == 0 {
+			return predicate{}, fmt.Errorf("positions start at 1")
+		}
+	case p.consume("last()"):
+		pred.last = true
+	default:
+		if p.consume("local-name()") || p.consume("name()") {
+			pred.localName = true
+		} else {
+			parsed, err := p.parsePath()
+			if err != nil {
+				return predicate{}, err
+			}
+			pred.path = &parsed
+		}
+
+		p.skipSpace()
+		switch {
+		case p.consume("!="):
+			pred.op = "!="
+		case p.consume("="):
+			pred.op = "="
+		}
+		if pred.op != "" {
+			literal, err := p.parseLiteral()
+			if err != nil {
+				return predicate{}, err
+			}
+			pred.literal = literal
+		} else if pred.localName {
+			return predicate{}, fmt.Errorf("local-name() must be compared with a value")
+		}
+	}
+
+	p.skipSpace()
+	if !p.consume("]") {
+		return predicate{}, fmt.Errorf("missing ']'")
+	}
+	return pred, nil
+}
+
+// parseLiteral reads a quoted string or a number.
+func (p *parser) parseLiteral() (string, error) {
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return "", fmt.Errorf("missing value to compare with")
+	}
+
+	if quote := p.s[p.pos]; quote == '\'' || quote == '"' {
+		end := strings.IndexByte(p.s[p.pos+1:], quote)
+		if end == -1 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		literal := p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return literal, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] == '-' || p.s[p.pos] == '.' || p.s[p.pos] >= '0' && p.s[p.pos] <= '9') {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("expected a quoted string or a number, got %q", p.s[p.pos:])
+	}
+	return p.s[start:p.pos], nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
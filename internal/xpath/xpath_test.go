@@ -0,0 +1,100 @@
+package xpath
+
+import (
+	"strings"
+	"testing"
+)
+
+const testEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:m="urn:shop">
+  <soap:Body>
+    <m:GetOrdersResponse status="ok">
+      <m:Order id="1" priority="high"><m:Name>tea</m:Name><m:Price>3.50</m:Price></m:Order>
+      <m:Order id="2"><m:Name>coffee</m:Name><m:Price>4</m:Price></m:Order>
+      <m:Order id="3"><m:Name>cake</m:Name><m:Price>5.25</m:Price><!-- seasonal --></m:Order>
+      <m:Note>
+        Delivered <b>today</b>
+      </m:Note>
+    </m:GetOrdersResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"/Envelope/Body/GetOrdersResponse/@status", "ok"},
+		{"/soap:Envelope/soap:Body/m:GetOrdersResponse/@status", "ok"},
+		{"//Order/Name", "tea"},
+		{"//Order[2]/Name", "coffee"},
+		{"//Order[last()]/Name", "cake"},
+		{"//Order[@id='3']/Price", "5.25"},
+		{"//Order[@id=2]/Name", "coffee"},
+		{"//Order[Name='coffee']/@id", "2"},
+		{"//Order[Price=3.5]/Name", "tea"},
+		{"//Order[@priority]/Name", "tea"},
+		{"//Order[@id!='1'][1]/Name", "coffee"},
+		{"//*[local-name()='Order'][2]/@id", "2"},
+		{"//Order[Name='cake']/../@status", "ok"},
+		{"//Note", "Delivered today"},
+		{"//Note/text()", "Delivered"},
+		{"//Order/@*", "1"},
+		{"count(//Order)", "3"},
+		{"count(//Missing)", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Extract([]byte(testEnvelope), tt.expr)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	tests := []struct {
+		body    string
+		expr    string
+		wantErr string
+	}{
+		{testEnvelope, "//Missing", "//Missing matched nothing"},
+		{testEnvelope, "//Order[5]/Name", "matched nothing"},
+		{testEnvelope, "//Order[1", "missing ']'"},
+		{testEnvelope, "//Order[Price>1]", "missing ']'"},
+		{testEnvelope, "//Order[0]", "positions start at 1"},
+		{testEnvelope, "//Order[@id='1]", "unterminated string"},
+		{testEnvelope, "//Order/", "missing name at end"},
+		{testEnvelope, "//*[local-name()]", "must be compared"},
+		{testEnvelope, "", "empty expression"},
+		{"not xml", "//a", "invalid XML"},
+		{"<a><b></a>", "//a", "invalid XML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Extract([]byte(tt.body), tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Extract() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDocument_Latin1(t *testing.T) {
+	body := append([]byte(`<?xml version="1.0" encoding="ISO-8859-1"?><city>M`), 0xfc, 'n', 'c', 'h', 'e', 'n')
+	body = append(body, []byte(`</city>`)...)
+
+	got, err := Extract(body, "/city")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if got != "München" {
+		t.Errorf("Extract() = %q, want München", got)
+	}
+}